@@ -0,0 +1,133 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	namespace string
+}
+
+func (flags *flags) Reset() {
+	flags.namespace = oxia.DefaultNamespace
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&Config.namespace, "namespace", "n", oxia.DefaultNamespace, "The namespace of the keys")
+
+	Cmd.AddCommand(setCmd)
+	Cmd.AddCommand(removeCmd)
+	Cmd.AddCommand(listCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Manage the key prefix ACLs",
+	Long:  `Operations on the permissions granted to the principals on the keys of a namespace starting with a prefix`,
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set [flags] PRINCIPAL PREFIX PERMISSION...",
+	Short: "Grant permissions on a key prefix",
+	Long: `Grant permissions to a principal on the keys starting with a prefix, replacing the permissions it had ` +
+		`on the same prefix. The prefix must end with '/', and the permissions are "read", "write" or "admin".`,
+	Args:         cobra.MinimumNArgs(3),
+	RunE:         execSet,
+	SilenceUsage: true,
+}
+
+func execSet(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if err = client.SetKeyPrefixACL(ctx, Config.namespace, oxia.KeyPrefixACL{
+		Principal:   args[0],
+		Prefix:      args[1],
+		Permissions: args[2:],
+	}); err != nil {
+		return err
+	}
+
+	cmd.Printf("Granted %v on %s to %s\n", args[2:], args[1], args[0])
+	return nil
+}
+
+var removeCmd = &cobra.Command{
+	Use:          "remove [flags] PRINCIPAL PREFIX",
+	Short:        "Remove the permissions on a key prefix",
+	Long:         `Remove the permissions of a principal on the keys starting with a prefix`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         execRemove,
+	SilenceUsage: true,
+}
+
+func execRemove(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if err = client.RemoveKeyPrefixACL(ctx, Config.namespace, args[0], args[1]); err != nil {
+		return err
+	}
+
+	cmd.Printf("Removed the permissions on %s of %s\n", args[1], args[0])
+	return nil
+}
+
+var listCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List the key prefix ACLs",
+	Long:         `List the permissions granted to the principals on the key prefixes of a namespace`,
+	Args:         cobra.NoArgs,
+	RunE:         execList,
+	SilenceUsage: true,
+}
+
+func execList(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	acls, err := client.ListKeyPrefixACLs(ctx, Config.namespace)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), acls)
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestACL_Set(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("SetKeyPrefixACL", "default", oxia.KeyPrefixACL{
+		Principal:   "team-a",
+		Prefix:      "/configs/a/",
+		Permissions: []string{"read", "write"},
+	}).Return(nil)
+	out, err := runCmd(Cmd, "set team-a /configs/a/ read write")
+	assert.NoError(t, err)
+	assert.Equal(t, "Granted [read write] on /configs/a/ to team-a", out)
+
+	common.MockedAdminClient.On("SetKeyPrefixACL", "ns-1", oxia.KeyPrefixACL{
+		Principal:   "team-a",
+		Prefix:      "/configs",
+		Permissions: []string{"read"},
+	}).Return(errors.New("the prefix must end with /"))
+	out, err = runCmd(Cmd, "set -n ns-1 team-a /configs read")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: the prefix must end with /", out)
+
+	_, err = runCmd(Cmd, "set team-a /configs/a/")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestACL_Remove(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("RemoveKeyPrefixACL", "default", "team-a", "/configs/a/").Return(nil)
+	out, err := runCmd(Cmd, "remove team-a /configs/a/")
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed the permissions on /configs/a/ of team-a", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestACL_List(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ListKeyPrefixACLs", "ns-1").Return([]oxia.KeyPrefixACL{{
+		Principal:   "team-a",
+		Prefix:      "/configs/a/",
+		Permissions: []string{"write"},
+	}}, nil)
+	out, err := runCmd(Cmd, "list -n ns-1")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"principal":"team-a","prefix":"/configs/a/","permissions":["write"]}`, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
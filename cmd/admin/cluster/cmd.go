@@ -0,0 +1,529 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	dryRun bool
+	reason string
+	limit  uint32
+	follow bool
+	output string
+
+	wait         bool
+	waitInterval time.Duration
+}
+
+func (flags *flags) Reset() {
+	flags.dryRun = false
+	flags.reason = ""
+	flags.limit = 0
+	flags.follow = false
+	flags.output = ""
+	flags.wait = false
+	flags.waitInterval = time.Second
+}
+
+func init() {
+	rebalanceCmd.Flags().BoolVar(&Config.dryRun, "dry-run", false, "Only print the moves, without applying them")
+	freezeCmd.Flags().StringVarP(&Config.reason, "reason", "r", "", "Why the cluster is frozen, eg: a reference to the incident")
+	eventsCmd.Flags().Uint32Var(&Config.limit, "limit", 0, "Only print the latest events")
+	eventsCmd.Flags().BoolVarP(&Config.follow, "follow", "f", false, "Keep printing the new events as they happen")
+	auditCmd.Flags().Uint32Var(&Config.limit, "limit", 0, "Only print the latest records")
+	exportCmd.Flags().StringVarP(&Config.output, "output", "o", "", "The file where the export is written, instead of the standard output")
+	executePlanCmd.Flags().BoolVarP(&Config.wait, "wait", "w", false, "Wait until all the moves of the plan are done")
+	executePlanCmd.Flags().DurationVar(&Config.waitInterval, "wait-interval", time.Second, "How often to check the progress when waiting")
+
+	Cmd.AddCommand(statusCmd)
+	Cmd.AddCommand(rebalanceCmd)
+	Cmd.AddCommand(planCmd)
+	Cmd.AddCommand(executePlanCmd)
+	Cmd.AddCommand(planStatusCmd)
+	Cmd.AddCommand(operationsCmd)
+	Cmd.AddCommand(freezeCmd)
+	Cmd.AddCommand(unfreezeCmd)
+	Cmd.AddCommand(eventsCmd)
+	Cmd.AddCommand(auditCmd)
+	Cmd.AddCommand(exportCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manage the cluster",
+	Long:  `Operations on the whole cluster`,
+}
+
+var rebalanceCmd = &cobra.Command{
+	Use:   "rebalance",
+	Short: "Rebalance the shard replicas",
+	Long: `Move the shard replicas so that all the servers have a similar number of them, eg: after adding new servers. ` +
+		`The moves are applied in the background, one at a time.`,
+	Args:         cobra.NoArgs,
+	RunE:         execRebalance,
+	SilenceUsage: true,
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan a rebalance of the shard replicas",
+	Long: `Compute the moves of a rebalance, along with the size of the shard data to copy, without applying them. ` +
+		`The plan is only applied once approved with the "execute-plan" command.`,
+	Args:         cobra.NoArgs,
+	RunE:         execPlan,
+	SilenceUsage: true,
+}
+
+var executePlanCmd = &cobra.Command{
+	Use:   "execute-plan [flags] PLAN_ID",
+	Short: "Execute a rebalance plan",
+	Long: `Apply the moves of a rebalance plan in the background, one at a time. The plan is rejected ` +
+		`if the cluster has changed since it was computed.`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execExecutePlan,
+	SilenceUsage: true,
+}
+
+var planStatusCmd = &cobra.Command{
+	Use:          "plan-status",
+	Short:        "Show the progress of the rebalance plan",
+	Long:         `Print the latest rebalance plan as json, with the progress of each of its moves`,
+	Args:         cobra.NoArgs,
+	RunE:         execPlanStatus,
+	SilenceUsage: true,
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of the cluster",
+	Long: `Summarize the status of the servers, the namespaces and the shards of the cluster, with the number ` +
+		`of operations in progress, printed as a json object`,
+	Args:         cobra.NoArgs,
+	RunE:         execStatus,
+	SilenceUsage: true,
+}
+
+var operationsCmd = &cobra.Command{
+	Use:   "operations",
+	Short: "List the operations in progress",
+	Long: `List the changes to the cluster that are in progress, like the replica moves, the leader elections ` +
+		`and the server decommissions, printing one json object per operation`,
+	Args:         cobra.NoArgs,
+	RunE:         execOperations,
+	SilenceUsage: true,
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the latest coordinator events",
+	Long: `List the latest decisions taken by the coordinator and the changes it observed, like the leader ` +
+		`elections and the failed servers, from the oldest to the newest, printing one json object per event. ` +
+		`The events are kept in memory by the coordinator, and they are lost when it restarts. ` +
+		`With --follow, the new events are streamed until the command is interrupted.`,
+	Args:         cobra.NoArgs,
+	RunE:         execEvents,
+	SilenceUsage: true,
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List the records of the audit log",
+	Long: `List the changes made to the cluster, either through the admin API or by the coordinator itself, ` +
+		`with who made them and why, from the oldest to the newest, printing one json object per record. ` +
+		`The reason of a change made through this tool is set with --audit-reason.`,
+	Args:         cobra.NoArgs,
+	RunE:         execAudit,
+	SilenceUsage: true,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the cluster status",
+	Long: `Export the full cluster status, with the namespaces, the shard ensembles and their terms, as json. ` +
+		`A new coordinator can be bootstrapped from the export with the --import-cluster-status flag, ` +
+		`eg: after the loss of the metadata store.`,
+	Args:         cobra.NoArgs,
+	RunE:         execExport,
+	SilenceUsage: true,
+}
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Start a maintenance freeze",
+	Long: `Stop the coordinator from moving any shard replica or leader on its own, eg: during an incident. ` +
+		`The leader elections still happen, and the operations already in progress are completed.`,
+	Args:         cobra.NoArgs,
+	RunE:         execFreeze,
+	SilenceUsage: true,
+}
+
+var unfreezeCmd = &cobra.Command{
+	Use:          "unfreeze",
+	Short:        "End a maintenance freeze",
+	Long:         `Let the coordinator apply the changes that were held back during the maintenance freeze`,
+	Args:         cobra.NoArgs,
+	RunE:         execUnfreeze,
+	SilenceUsage: true,
+}
+
+func execRebalance(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	moves, err := client.RebalanceCluster(ctx, Config.dryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(moves) == 0 {
+		cmd.Println("The cluster is already balanced")
+		return nil
+	}
+
+	for _, m := range moves {
+		cmd.Printf("Shard %d: %s -> %s\n", m.Shard, m.From, m.To)
+	}
+	return nil
+}
+
+func execPlan(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	plan, err := client.PlanRebalance(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Moves) == 0 {
+		cmd.Println("The cluster is already balanced")
+		return nil
+	}
+
+	var totalBytes int64
+	for _, m := range plan.Moves {
+		size := "unknown size"
+		if m.EstimatedBytes != nil {
+			size = fmt.Sprintf("%d bytes", *m.EstimatedBytes)
+			totalBytes += *m.EstimatedBytes
+		}
+		cmd.Printf("Shard %d: %s -> %s (%s)\n", m.Shard, m.From, m.To, size)
+	}
+	cmd.Printf("Plan %d: %d moves, %d bytes to copy\n", plan.Id, len(plan.Moves), totalBytes)
+	return nil
+}
+
+func execExecutePlan(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	err = client.ExecuteRebalancePlan(ctx, id)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for {
+		plan, err := getPlan(client)
+		if err != nil {
+			return err
+		}
+		if plan == nil || plan.Id != id {
+			return errors.New("the rebalance plan is not known to the coordinator anymore")
+		}
+
+		done := 0
+		for _, m := range plan.Moves {
+			if m.State != "pending" && m.State != "in-progress" {
+				done++
+			}
+		}
+		cmd.Printf("Plan %d is %s: %d/%d moves done\n", id, plan.State, done, len(plan.Moves))
+		if !Config.wait || plan.State != "executing" {
+			return nil
+		}
+
+		time.Sleep(Config.waitInterval)
+	}
+}
+
+func execPlanStatus(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	plan, err := getPlan(client)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		cmd.Println("No rebalance plan was computed")
+		return nil
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), []oxia.RebalancePlan{*plan})
+}
+
+func getPlan(client oxia.AdminClient) (*oxia.RebalancePlan, error) {
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	return client.GetRebalancePlan(ctx)
+}
+
+type serversStatus struct {
+	Total    int `json:"total"`
+	Running  int `json:"running"`
+	Draining int `json:"draining"`
+}
+
+type shardsStatus struct {
+	Total int `json:"total"`
+
+	// ByStatus is the number of shards in each status
+	ByStatus map[string]int `json:"byStatus"`
+
+	// WithoutLeader lists the shards that have no leader, per namespace
+	WithoutLeader map[string][]int64 `json:"withoutLeader,omitempty"`
+}
+
+type clusterStatus struct {
+	Servers    serversStatus `json:"servers"`
+	Namespaces int           `json:"namespaces"`
+	Shards     shardsStatus  `json:"shards"`
+	Operations int           `json:"operations"`
+}
+
+func execStatus(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	status := clusterStatus{
+		Shards: shardsStatus{ByStatus: map[string]int{}},
+	}
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		status.Servers.Total++
+		if server.Running {
+			status.Servers.Running++
+		}
+		if server.Draining {
+			status.Servers.Draining++
+		}
+	}
+
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		if namespace.DeletedAt != nil {
+			continue
+		}
+		status.Namespaces++
+
+		shards, err := client.ListShards(ctx, namespace.Name)
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			status.Shards.Total++
+			status.Shards.ByStatus[shard.Status]++
+			if shard.Leader == "" {
+				if status.Shards.WithoutLeader == nil {
+					status.Shards.WithoutLeader = map[string][]int64{}
+				}
+				status.Shards.WithoutLeader[namespace.Name] = append(status.Shards.WithoutLeader[namespace.Name], shard.Shard)
+			}
+		}
+	}
+
+	operations, err := client.ListOperations(ctx)
+	if err != nil {
+		return err
+	}
+	status.Operations = len(operations)
+
+	return common.WriteOutput(cmd.OutOrStdout(), []clusterStatus{status})
+}
+
+func execOperations(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	operations, err := client.ListOperations(ctx)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), operations)
+}
+
+func execEvents(cmd *cobra.Command, _ []string) error {
+	if Config.follow && Config.limit > 0 {
+		return errors.New("the limit cannot be used with --follow")
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if Config.follow {
+		var writeErr error
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		err = client.WatchEvents(ctx, true, func(e oxia.EventInfo) {
+			if writeErr = common.WriteOutput(cmd.OutOrStdout(), []oxia.EventInfo{e}); writeErr != nil {
+				cancel()
+			}
+		})
+		if writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	events, err := client.ListEvents(ctx, Config.limit)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), events)
+}
+
+func execAudit(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	records, err := client.ListAuditRecords(ctx, Config.limit)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), records)
+}
+
+func execExport(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	export, err := client.ExportClusterStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	if Config.output != "" {
+		return os.WriteFile(Config.output, export, 0600)
+	}
+	_, err = cmd.OutOrStdout().Write(export)
+	return err
+}
+
+func execFreeze(cmd *cobra.Command, _ []string) error {
+	if err := setFreeze(true, Config.reason); err != nil {
+		return err
+	}
+	cmd.Println("The cluster is frozen")
+	return nil
+}
+
+func execUnfreeze(cmd *cobra.Command, _ []string) error {
+	if err := setFreeze(false, ""); err != nil {
+		return err
+	}
+	cmd.Println("The cluster is not frozen anymore")
+	return nil
+}
+
+func setFreeze(freeze bool, reason string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	return client.SetClusterFreeze(ctx, freeze, reason)
+}
@@ -0,0 +1,292 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestCluster_Rebalance(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("RebalanceCluster", true).Return([]oxia.ReplicaMove{
+		{Shard: 1, From: "s1:6649", To: "s4:6649"},
+		{Shard: 3, From: "s2:6649", To: "s4:6649"},
+	}, nil)
+	out, err := runCmd(Cmd, "rebalance --dry-run")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shard 1: s1:6649 -> s4:6649\nShard 3: s2:6649 -> s4:6649", out)
+
+	common.MockedAdminClient.On("RebalanceCluster", false).Return([]oxia.ReplicaMove{}, nil)
+	out, err = runCmd(Cmd, "rebalance")
+	assert.NoError(t, err)
+	assert.Equal(t, "The cluster is already balanced", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Status(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	deletedAt := time.UnixMilli(1)
+	common.MockedAdminClient.On("ListServers").Return([]oxia.ServerInfo{
+		{InternalAddress: "s1:6649", Running: true},
+		{InternalAddress: "s2:6649", Running: true, Draining: true},
+		{InternalAddress: "s3:6649"},
+	}, nil)
+	common.MockedAdminClient.On("ListNamespaces").Return([]oxia.NamespaceInfo{
+		{Name: "default"},
+		{Name: "ns-1"},
+		{Name: "deleted", DeletedAt: &deletedAt},
+	}, nil)
+	common.MockedAdminClient.On("ListShards", "default").Return([]oxia.ShardInfo{
+		{Shard: 0, Status: "SteadyState", Leader: "s1:6649"},
+		{Shard: 1, Status: "Election"},
+	}, nil)
+	common.MockedAdminClient.On("ListShards", "ns-1").Return([]oxia.ShardInfo{
+		{Shard: 2, Status: "SteadyState", Leader: "s2:6649"},
+	}, nil)
+	common.MockedAdminClient.On("ListOperations").Return([]oxia.OperationInfo{
+		{Type: "server-drain", Server: "s2:6649"},
+	}, nil)
+
+	out, err := runCmd(Cmd, "status")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"servers":{"total":3,"running":2,"draining":1},"namespaces":2,`+
+		`"shards":{"total":3,"byStatus":{"Election":1,"SteadyState":2},"withoutLeader":{"default":[1]}},"operations":1}`, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Operations(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	shard := int64(2)
+	common.MockedAdminClient.On("ListOperations").Return([]oxia.OperationInfo{
+		{Type: "leader-election", Namespace: "default", Shard: &shard, Description: "term 3"},
+		{Type: "server-drain", Server: "s1:6649", Description: "1 shards still led by the server"},
+	}, nil).Once()
+	out, err := runCmd(Cmd, "operations")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"leader-election","namespace":"default","shard":2,"description":"term 3"}`+"\n"+
+		`{"type":"server-drain","server":"s1:6649","description":"1 shards still led by the server"}`, out)
+
+	common.MockedAdminClient.On("ListOperations").Return([]oxia.OperationInfo{}, nil).Once()
+	out, err = runCmd(Cmd, "operations")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Freeze(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("SetClusterFreeze", true, "INC-42").Return(nil)
+	out, err := runCmd(Cmd, "freeze --reason INC-42")
+	assert.NoError(t, err)
+	assert.Equal(t, "The cluster is frozen", out)
+
+	common.MockedAdminClient.On("SetClusterFreeze", false, "").Return(nil)
+	out, err = runCmd(Cmd, "unfreeze")
+	assert.NoError(t, err)
+	assert.Equal(t, "The cluster is not frozen anymore", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Events(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	shard := int64(1)
+	eventTime := time.UnixMilli(1000).UTC()
+	common.MockedAdminClient.On("ListEvents", uint32(0)).Return([]oxia.EventInfo{
+		{Time: eventTime, Type: "node-failed", Server: "s1:6649"},
+		{Time: eventTime, Type: "leader-elected", Namespace: "default", Shard: &shard, Server: "s2:6649", Description: "term 2"},
+	}, nil)
+	out, err := runCmd(Cmd, "events")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"time":"1970-01-01T00:00:01Z","type":"node-failed","server":"s1:6649"}`+"\n"+
+		`{"time":"1970-01-01T00:00:01Z","type":"leader-elected","namespace":"default","shard":1,"server":"s2:6649","description":"term 2"}`, out)
+
+	common.MockedAdminClient.On("ListEvents", uint32(5)).Return([]oxia.EventInfo{}, nil)
+	out, err = runCmd(Cmd, "events --limit 5")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.On("WatchEvents", true).Return([]oxia.EventInfo{
+		{Time: eventTime, Type: "leader-elected", Namespace: "default", Shard: &shard, Server: "s3:6649", Description: "term 3"},
+	}, nil)
+	out, err = runCmd(Cmd, "events --follow")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"time":"1970-01-01T00:00:01Z","type":"leader-elected","namespace":"default","shard":1,"server":"s3:6649","description":"term 3"}`, out)
+
+	_, err = runCmd(Cmd, "events --follow --limit 5")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Audit(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	recordTime := time.UnixMilli(1000).UTC()
+	common.MockedAdminClient.On("ListAuditRecords", uint32(0)).Return([]oxia.AuditRecord{
+		{Time: recordTime, Actor: "admin (10.0.0.1:1234)", Action: "delete-namespace", Namespace: "ns-1", Reason: "INC-42"},
+		{Time: recordTime, Actor: "coordinator", Action: "node-failed", Server: "s1:6649"},
+	}, nil)
+	out, err := runCmd(Cmd, "audit")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"time":"1970-01-01T00:00:01Z","actor":"admin (10.0.0.1:1234)","action":"delete-namespace","namespace":"ns-1","reason":"INC-42"}`+"\n"+
+		`{"time":"1970-01-01T00:00:01Z","actor":"coordinator","action":"node-failed","server":"s1:6649"}`, out)
+
+	common.MockedAdminClient.On("ListAuditRecords", uint32(10)).Return([]oxia.AuditRecord{}, nil)
+	out, err = runCmd(Cmd, "audit --limit 10")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Export(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	export := []byte(`{"namespaces":{},"shardIdGenerator":0,"serverIdx":0}`)
+	common.MockedAdminClient.On("ExportClusterStatus").Return(export, nil)
+	out, err := runCmd(Cmd, "export")
+	assert.NoError(t, err)
+	assert.Equal(t, string(export), out)
+
+	path := filepath.Join(t.TempDir(), "cluster-status.json")
+	out, err = runCmd(Cmd, "export -o "+path)
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, export, content)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_Plan(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	size := int64(1024)
+	common.MockedAdminClient.On("PlanRebalance").Return(oxia.RebalancePlan{
+		Id:    3,
+		State: "pending",
+		Moves: []oxia.ReplicaMove{
+			{Shard: 1, From: "s1:6649", To: "s3:6649", EstimatedBytes: &size, State: "pending"},
+			{Shard: 2, From: "s2:6649", To: "s3:6649", State: "pending"},
+		},
+	}, nil).Once()
+	out, err := runCmd(Cmd, "plan")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shard 1: s1:6649 -> s3:6649 (1024 bytes)\n"+
+		"Shard 2: s2:6649 -> s3:6649 (unknown size)\n"+
+		"Plan 3: 2 moves, 1024 bytes to copy", out)
+
+	common.MockedAdminClient.On("PlanRebalance").Return(oxia.RebalancePlan{Id: 4, State: "pending"}, nil).Once()
+	out, err = runCmd(Cmd, "plan")
+	assert.NoError(t, err)
+	assert.Equal(t, "The cluster is already balanced", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestCluster_ExecutePlan(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	plan := func(state string, moveStates ...string) *oxia.RebalancePlan {
+		p := &oxia.RebalancePlan{Id: 3, State: state, CreatedAt: time.UnixMilli(1000).UTC()}
+		for i, s := range moveStates {
+			p.Moves = append(p.Moves, oxia.ReplicaMove{Shard: int64(i), From: "s1:6649", To: "s3:6649", State: s})
+		}
+		return p
+	}
+
+	common.MockedAdminClient.On("ExecuteRebalancePlan", int64(3)).Return(nil).Twice()
+	common.MockedAdminClient.On("GetRebalancePlan").Return(plan("executing", "in-progress", "pending"), nil).Once()
+	out, err := runCmd(Cmd, "execute-plan 3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Plan 3 is executing: 0/2 moves done", out)
+
+	common.MockedAdminClient.On("GetRebalancePlan").Return(plan("executing", "completed", "in-progress"), nil).Once()
+	common.MockedAdminClient.On("GetRebalancePlan").Return(plan("completed", "completed", "skipped"), nil).Once()
+	out, err = runCmd(Cmd, "execute-plan 3 -w --wait-interval 1ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "Plan 3 is executing: 1/2 moves done\n"+
+		"Plan 3 is completed: 2/2 moves done", out)
+
+	common.MockedAdminClient.On("ExecuteRebalancePlan", int64(2)).
+		Return(errors.New("rebalance plan not found")).Once()
+	out, err = runCmd(Cmd, "execute-plan 2")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: rebalance plan not found", out)
+
+	common.MockedAdminClient.On("GetRebalancePlan").Return(plan("completed", "failed"), nil).Once()
+	out, err = runCmd(Cmd, "plan-status")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":3,"state":"completed","createdAt":"1970-01-01T00:00:01Z","moves":`+
+		`[{"shard":0,"from":"s1:6649","to":"s3:6649","state":"failed"}]}`, out)
+
+	common.MockedAdminClient.On("GetRebalancePlan").Return((*oxia.RebalancePlan)(nil), nil).Once()
+	out, err = runCmd(Cmd, "plan-status")
+	assert.NoError(t, err)
+	assert.Equal(t, "No rebalance plan was computed", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
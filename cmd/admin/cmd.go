@@ -0,0 +1,61 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/acl"
+	"github.com/streamnative/oxia/cmd/admin/cluster"
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/cmd/admin/namespace"
+	"github.com/streamnative/oxia/cmd/admin/server"
+	"github.com/streamnative/oxia/cmd/admin/session"
+	"github.com/streamnative/oxia/cmd/admin/shard"
+	oxiacommon "github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Cmd = &cobra.Command{
+		Use:   "admin",
+		Short: "Manage the cluster",
+		Long:  `Administrative operations on an oxia cluster, through the coordinator admin API`,
+	}
+)
+
+func init() {
+	defaultAdminAddress := fmt.Sprintf("localhost:%d", oxiacommon.DefaultInternalPort)
+	Cmd.PersistentFlags().StringVarP(&common.Config.AdminAddr, "admin-address", "a", defaultAdminAddress, "Coordinator admin service address")
+	Cmd.PersistentFlags().DurationVar(&common.Config.RequestTimeout, "request-timeout", oxia.DefaultRequestTimeout, "Requests timeout")
+	Cmd.PersistentFlags().StringVar(&common.Config.AuditReason, "audit-reason", "", "Why the changes are made, as recorded in the coordinator audit log")
+	Cmd.PersistentFlags().StringVar(&common.Config.AuthToken, "auth-token", "", "Bearer token, when the coordinator admin service requires authentication")
+
+	// TLS section
+	Cmd.PersistentFlags().StringVar(&common.Config.TLS.CertFile, "tls-cert-file", "", "Tls certificate file, to authenticate the client")
+	Cmd.PersistentFlags().StringVar(&common.Config.TLS.KeyFile, "tls-key-file", "", "Tls key file")
+	Cmd.PersistentFlags().StringVar(&common.Config.TLS.TrustedCaFile, "tls-trusted-ca-file", "", "Tls trusted ca file, to verify the coordinator")
+	Cmd.PersistentFlags().StringVar(&common.Config.TLS.ServerName, "tls-server-name", "", "Tls server name")
+	Cmd.PersistentFlags().BoolVar(&common.Config.TLS.InsecureSkipVerify, "tls-insecure-skip-verify", false, "Tls insecure skip verify")
+
+	Cmd.AddCommand(acl.Cmd)
+	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(namespace.Cmd)
+	Cmd.AddCommand(server.Cmd)
+	Cmd.AddCommand(session.Cmd)
+	Cmd.AddCommand(shard.Cmd)
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/streamnative/oxia/common/security"
+	"github.com/streamnative/oxia/oxia"
+	"github.com/streamnative/oxia/oxia/auth"
+)
+
+var (
+	Config            = AdminConfig{}
+	MockedAdminClient *MockAdminClient
+)
+
+type AdminConfig struct {
+	AdminAddr      string
+	RequestTimeout time.Duration
+	AuditReason    string
+	TLS            security.TLSOption
+	// AuthToken is sent as a bearer token, when the admin service requires
+	// the clients to authenticate
+	AuthToken string
+}
+
+func (AdminConfig) NewAdminClient() (oxia.AdminClient, error) {
+	if MockedAdminClient != nil {
+		return MockedAdminClient, nil
+	}
+
+	var options []oxia.ClientOption
+	if Config.TLS.IsConfigured() {
+		tlsConf, err := Config.TLS.MakeClientTLSConf()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, oxia.WithTLS(tlsConf))
+	}
+	if Config.AuthToken != "" {
+		options = append(options, oxia.WithAuthentication(auth.NewTokenAuthenticationWithToken(Config.AuthToken, false)))
+	}
+
+	return oxia.NewAdminClient(Config.AdminAddr, options...)
+}
+
+// NewRequestContext returns the context of a request to the admin API, that
+// carries the reason of the change to record in the audit log.
+func (AdminConfig) NewRequestContext() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if Config.AuditReason != "" {
+		ctx = oxia.WithAuditReason(ctx, Config.AuditReason)
+	}
+	return context.WithTimeout(ctx, Config.RequestTimeout)
+}
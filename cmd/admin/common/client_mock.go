@@ -0,0 +1,169 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/streamnative/oxia/oxia"
+)
+
+type MockAdminClient struct {
+	mock.Mock
+}
+
+func NewMockAdminClient() *MockAdminClient {
+	return &MockAdminClient{}
+}
+
+func (m *MockAdminClient) Close() error {
+	args := m.MethodCalled("Close")
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) CreateNamespace(_ context.Context, namespace string, initialShardCount uint32, replicationFactor uint32) error {
+	args := m.MethodCalled("CreateNamespace", namespace, initialShardCount, replicationFactor)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) CreateTenantNamespace(_ context.Context, tenant string, namespace string, initialShardCount uint32, replicationFactor uint32) error {
+	args := m.MethodCalled("CreateTenantNamespace", tenant, namespace, initialShardCount, replicationFactor)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DeleteNamespace(_ context.Context, namespace string) error {
+	args := m.MethodCalled("DeleteNamespace", namespace)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DecommissionServer(_ context.Context, server string) (oxia.DecommissionStatus, error) {
+	args := m.MethodCalled("DecommissionServer", server)
+	return args.Get(0).(oxia.DecommissionStatus), args.Error(1)
+}
+
+func (m *MockAdminClient) RebalanceCluster(_ context.Context, dryRun bool) ([]oxia.ReplicaMove, error) {
+	args := m.MethodCalled("RebalanceCluster", dryRun)
+	return args.Get(0).([]oxia.ReplicaMove), args.Error(1)
+}
+
+func (m *MockAdminClient) PlanRebalance(context.Context) (oxia.RebalancePlan, error) {
+	args := m.MethodCalled("PlanRebalance")
+	return args.Get(0).(oxia.RebalancePlan), args.Error(1)
+}
+
+func (m *MockAdminClient) ExecuteRebalancePlan(_ context.Context, id int64) error {
+	args := m.MethodCalled("ExecuteRebalancePlan", id)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) GetRebalancePlan(context.Context) (*oxia.RebalancePlan, error) {
+	args := m.MethodCalled("GetRebalancePlan")
+	return args.Get(0).(*oxia.RebalancePlan), args.Error(1)
+}
+
+func (m *MockAdminClient) SetServerDrain(_ context.Context, server string, drain bool) (uint32, error) {
+	args := m.MethodCalled("SetServerDrain", server, drain)
+	return args.Get(0).(uint32), args.Error(1)
+}
+
+func (m *MockAdminClient) ReplaceServer(_ context.Context, oldServer string, newServer string) (uint32, error) {
+	args := m.MethodCalled("ReplaceServer", oldServer, newServer)
+	return args.Get(0).(uint32), args.Error(1)
+}
+
+func (m *MockAdminClient) ListNamespaces(context.Context) ([]oxia.NamespaceInfo, error) {
+	args := m.MethodCalled("ListNamespaces")
+	return args.Get(0).([]oxia.NamespaceInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) ListShards(_ context.Context, namespace string) ([]oxia.ShardInfo, error) {
+	args := m.MethodCalled("ListShards", namespace)
+	return args.Get(0).([]oxia.ShardInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) ListServers(context.Context) ([]oxia.ServerInfo, error) {
+	args := m.MethodCalled("ListServers")
+	return args.Get(0).([]oxia.ServerInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) ListOperations(context.Context) ([]oxia.OperationInfo, error) {
+	args := m.MethodCalled("ListOperations")
+	return args.Get(0).([]oxia.OperationInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) TransferLeader(_ context.Context, namespace string, shard int64, server string) error {
+	args := m.MethodCalled("TransferLeader", namespace, shard, server)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) MergeShard(_ context.Context, namespace string, shard int64, into int64) error {
+	args := m.MethodCalled("MergeShard", namespace, shard, into)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) ListSessions(_ context.Context, namespace string, shard *int64) ([]oxia.SessionInfo, error) {
+	args := m.MethodCalled("ListSessions", namespace, shard)
+	return args.Get(0).([]oxia.SessionInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) ForceCloseSession(_ context.Context, namespace string, shard int64, sessionId int64) error {
+	args := m.MethodCalled("ForceCloseSession", namespace, shard, sessionId)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) SetClusterFreeze(_ context.Context, freeze bool, reason string) error {
+	args := m.MethodCalled("SetClusterFreeze", freeze, reason)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) SetKeyPrefixACL(_ context.Context, namespace string, acl oxia.KeyPrefixACL) error {
+	args := m.MethodCalled("SetKeyPrefixACL", namespace, acl)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) RemoveKeyPrefixACL(_ context.Context, namespace string, principal string, prefix string) error {
+	args := m.MethodCalled("RemoveKeyPrefixACL", namespace, principal, prefix)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) ListKeyPrefixACLs(_ context.Context, namespace string) ([]oxia.KeyPrefixACL, error) {
+	args := m.MethodCalled("ListKeyPrefixACLs", namespace)
+	return args.Get(0).([]oxia.KeyPrefixACL), args.Error(1)
+}
+
+func (m *MockAdminClient) ListEvents(_ context.Context, limit uint32) ([]oxia.EventInfo, error) {
+	args := m.MethodCalled("ListEvents", limit)
+	return args.Get(0).([]oxia.EventInfo), args.Error(1)
+}
+
+func (m *MockAdminClient) WatchEvents(_ context.Context, includeRecent bool, handler func(oxia.EventInfo)) error {
+	args := m.MethodCalled("WatchEvents", includeRecent)
+	for _, e := range args.Get(0).([]oxia.EventInfo) {
+		handler(e)
+	}
+	return args.Error(1)
+}
+
+func (m *MockAdminClient) ExportClusterStatus(context.Context) ([]byte, error) {
+	args := m.MethodCalled("ExportClusterStatus")
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockAdminClient) ListAuditRecords(_ context.Context, limit uint32) ([]oxia.AuditRecord, error) {
+	args := m.MethodCalled("ListAuditRecords", limit)
+	return args.Get(0).([]oxia.AuditRecord), args.Error(1)
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteOutput writes each of the items as a json object on its own line.
+func WriteOutput[T any](out io.Writer, items []T) error {
+	for _, item := range items {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err = out.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
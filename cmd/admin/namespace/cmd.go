@@ -0,0 +1,153 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	initialShardCount uint32
+	replicationFactor uint32
+	tenant            string
+}
+
+func (flags *flags) Reset() {
+	flags.initialShardCount = 1
+	flags.replicationFactor = 1
+	flags.tenant = ""
+}
+
+func init() {
+	createCmd.Flags().Uint32VarP(&Config.initialShardCount, "shards", "s", 1, "Number of shards of the namespace")
+	createCmd.Flags().Uint32VarP(&Config.replicationFactor, "replication-factor", "r", 1, "Number of replicas of each shard")
+	createCmd.Flags().StringVar(&Config.tenant, "tenant", "", "Tenant of the namespace, as defined in the cluster config")
+
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(deleteCmd)
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(shardsCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "namespace",
+	Short: "Manage namespaces",
+	Long:  `Create, delete and inspect namespaces at runtime, without changing the cluster config`,
+}
+
+var createCmd = &cobra.Command{
+	Use:          "create [flags] NAMESPACE",
+	Short:        "Create a namespace",
+	Long:         `Create a new namespace with the given number of shards and replication factor`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execCreate,
+	SilenceUsage: true,
+}
+
+var deleteCmd = &cobra.Command{
+	Use:          "delete NAMESPACE",
+	Short:        "Delete a namespace",
+	Long:         `Delete a namespace and all the records it contains. Only the namespaces that were created with the admin API can be deleted`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execDelete,
+	SilenceUsage: true,
+}
+
+var listCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List the namespaces",
+	Long:         `List all the namespaces of the cluster, printing one json object per namespace`,
+	Args:         cobra.NoArgs,
+	RunE:         execList,
+	SilenceUsage: true,
+}
+
+var shardsCmd = &cobra.Command{
+	Use:          "shards NAMESPACE",
+	Short:        "List the shards of a namespace",
+	Long:         `List the shards of a namespace with their status, leader and ensemble, printing one json object per shard`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execShards,
+	SilenceUsage: true,
+}
+
+func execCreate(_ *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if Config.tenant != "" {
+		return client.CreateTenantNamespace(ctx, Config.tenant, args[0], Config.initialShardCount, Config.replicationFactor)
+	}
+	return client.CreateNamespace(ctx, args[0], Config.initialShardCount, Config.replicationFactor)
+}
+
+func execDelete(_ *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	return client.DeleteNamespace(ctx, args[0])
+}
+
+func execList(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), namespaces)
+}
+
+func execShards(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	shards, err := client.ListShards(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), shards)
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestNamespace_exec(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("CreateNamespace", "ns-1", uint32(1), uint32(1)).Return(nil)
+	out, err := runCmd(Cmd, "create ns-1")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.On("CreateNamespace", "ns-2", uint32(4), uint32(3)).Return(nil)
+	out, err = runCmd(Cmd, "create ns-2 -s 4 -r 3")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.On("CreateNamespace", "ns-3", uint32(1), uint32(1)).Return(errors.New("namespace already exists"))
+	out, err = runCmd(Cmd, "create ns-3")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: namespace already exists", out)
+
+	common.MockedAdminClient.On("CreateTenantNamespace", "team-a", "ns-4", uint32(2), uint32(1)).Return(nil)
+	out, err = runCmd(Cmd, "create ns-4 -s 2 --tenant team-a")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.On("DeleteNamespace", "ns-1").Return(nil)
+	out, err = runCmd(Cmd, "delete ns-1")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	_, err = runCmd(Cmd, "delete")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestNamespace_List(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ListNamespaces").Return([]oxia.NamespaceInfo{
+		{Name: "default", ReplicationFactor: 3, Shards: 2},
+		{Name: "ns-1", ReplicationFactor: 1, Shards: 1, Dynamic: true},
+	}, nil)
+	out, err := runCmd(Cmd, "list")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"default","replicationFactor":3,"shards":2}`+"\n"+
+		`{"name":"ns-1","replicationFactor":1,"shards":1,"dynamic":true}`, out)
+
+	common.MockedAdminClient.On("ListShards", "default").Return([]oxia.ShardInfo{
+		{Shard: 0, Status: "SteadyState", Term: 2, Leader: "s1:6649", Ensemble: []string{"s1:6649", "s2:6649"},
+			Int32HashMin: 0, Int32HashMax: 100},
+	}, nil)
+	out, err = runCmd(Cmd, "shards default")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"shard":0,"status":"SteadyState","term":2,"leader":"s1:6649","ensemble":["s1:6649","s2:6649"],`+
+		`"int32HashMin":0,"int32HashMax":100}`, out)
+
+	common.MockedAdminClient.On("ListShards", "ns-2").Return([]oxia.ShardInfo(nil), errors.New("namespace not found"))
+	out, err = runCmd(Cmd, "shards ns-2")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: namespace not found", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
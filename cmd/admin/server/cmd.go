@@ -0,0 +1,222 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	wait         bool
+	waitInterval time.Duration
+}
+
+func (flags *flags) Reset() {
+	flags.wait = false
+	flags.waitInterval = time.Second
+}
+
+func init() {
+	decommissionCmd.Flags().BoolVarP(&Config.wait, "wait", "w", false, "Wait until the server is decommissioned")
+	decommissionCmd.Flags().DurationVar(&Config.waitInterval, "wait-interval", time.Second, "How often to check the progress when waiting")
+	drainCmd.Flags().BoolVarP(&Config.wait, "wait", "w", false, "Wait until the server doesn't lead any shard")
+	drainCmd.Flags().DurationVar(&Config.waitInterval, "wait-interval", time.Second, "How often to check the progress when waiting")
+
+	Cmd.AddCommand(decommissionCmd)
+	Cmd.AddCommand(drainCmd)
+	Cmd.AddCommand(undrainCmd)
+	Cmd.AddCommand(replaceCmd)
+	Cmd.AddCommand(listCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "server",
+	Short: "Manage servers",
+	Long:  `Operations on the servers of the cluster`,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the servers",
+	Long: `List the servers of the cluster with their health, maintenance state and number of replicas, ` +
+		`printing one json object per server`,
+	Args:         cobra.NoArgs,
+	RunE:         execList,
+	SilenceUsage: true,
+}
+
+var decommissionCmd = &cobra.Command{
+	Use:   "decommission [flags] SERVER",
+	Short: "Decommission a server",
+	Long: `Move all the shard replicas out of a server, identified by its public or internal address. ` +
+		`Once the decommission is completed, the server can be removed from the cluster config.`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execDecommission,
+	SilenceUsage: true,
+}
+
+var drainCmd = &cobra.Command{
+	Use:   "drain [flags] SERVER",
+	Short: "Put a server in maintenance",
+	Long: `Move the leadership of the shards out of a server, identified by its public or internal address, ` +
+		`and stop placing new replicas on it. The server keeps its replicas, so it can be restarted safely.`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execDrain,
+	SilenceUsage: true,
+}
+
+var undrainCmd = &cobra.Command{
+	Use:          "undrain SERVER",
+	Short:        "Bring a server back from maintenance",
+	Long:         `Allow a drained server to lead shards and to receive new replicas again`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execUndrain,
+	SilenceUsage: true,
+}
+
+var replaceCmd = &cobra.Command{
+	Use:   "replace OLD_SERVER NEW_SERVER",
+	Short: "Replace a failed server",
+	Long: `Move all the shard replicas of a failed server to a new server, one shard at a time. ` +
+		`The new server recovers the shards from their leaders, and the progress can be followed ` +
+		`with the "cluster operations" command. The failed server must not be restarted with its old data.`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         execReplace,
+	SilenceUsage: true,
+}
+
+func execDecommission(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for {
+		status, err := decommission(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if status.Completed {
+			cmd.Printf("Server %s is decommissioned and it can be removed from the cluster config\n", args[0])
+			return nil
+		}
+
+		cmd.Printf("Decommissioning server %s: %d shards remaining\n", args[0], status.RemainingShards)
+		if !Config.wait {
+			return nil
+		}
+
+		time.Sleep(Config.waitInterval)
+	}
+}
+
+func decommission(client oxia.AdminClient, server string) (oxia.DecommissionStatus, error) {
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	return client.DecommissionServer(ctx, server)
+}
+
+func execDrain(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for {
+		leaderShards, err := setDrain(client, args[0], true)
+		if err != nil {
+			return err
+		}
+
+		if leaderShards == 0 {
+			cmd.Printf("Server %s is drained\n", args[0])
+			return nil
+		}
+
+		cmd.Printf("Draining server %s: %d shards still led by the server\n", args[0], leaderShards)
+		if !Config.wait {
+			return nil
+		}
+
+		time.Sleep(Config.waitInterval)
+	}
+}
+
+func execUndrain(_ *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, err = setDrain(client, args[0], false)
+	return err
+}
+
+func setDrain(client oxia.AdminClient, server string, drain bool) (uint32, error) {
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	return client.SetServerDrain(ctx, server, drain)
+}
+
+func execReplace(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	shards, err := client.ReplaceServer(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Replacing server %s with %s: %d shards are being moved\n", args[0], args[1], shards)
+	return nil
+}
+
+func execList(cmd *cobra.Command, _ []string) error {
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), servers)
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestServer_Decommission(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("DecommissionServer", "s1:6649").
+		Return(oxia.DecommissionStatus{RemainingShards: 3}, nil).Once()
+	out, err := runCmd(Cmd, "decommission s1:6649")
+	assert.NoError(t, err)
+	assert.Equal(t, "Decommissioning server s1:6649: 3 shards remaining", out)
+
+	common.MockedAdminClient.On("DecommissionServer", "s1:6649").
+		Return(oxia.DecommissionStatus{RemainingShards: 1}, nil).Once()
+	common.MockedAdminClient.On("DecommissionServer", "s1:6649").
+		Return(oxia.DecommissionStatus{Completed: true}, nil).Once()
+	out, err = runCmd(Cmd, "decommission s1:6649 --wait --wait-interval 1ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "Decommissioning server s1:6649: 1 shards remaining\n"+
+		"Server s1:6649 is decommissioned and it can be removed from the cluster config", out)
+
+	common.MockedAdminClient.On("DecommissionServer", "s9:6649").
+		Return(oxia.DecommissionStatus{}, errors.New("server not found"))
+	out, err = runCmd(Cmd, "decommission s9:6649")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: server not found", out)
+
+	_, err = runCmd(Cmd, "decommission")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestServer_Drain(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("SetServerDrain", "s1:6649", true).Return(uint32(2), nil).Once()
+	out, err := runCmd(Cmd, "drain s1:6649")
+	assert.NoError(t, err)
+	assert.Equal(t, "Draining server s1:6649: 2 shards still led by the server", out)
+
+	common.MockedAdminClient.On("SetServerDrain", "s1:6649", true).Return(uint32(1), nil).Once()
+	common.MockedAdminClient.On("SetServerDrain", "s1:6649", true).Return(uint32(0), nil).Once()
+	out, err = runCmd(Cmd, "drain s1:6649 -w --wait-interval 1ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "Draining server s1:6649: 1 shards still led by the server\n"+
+		"Server s1:6649 is drained", out)
+
+	common.MockedAdminClient.On("SetServerDrain", "s1:6649", false).Return(uint32(0), nil).Once()
+	out, err = runCmd(Cmd, "undrain s1:6649")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestServer_Replace(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ReplaceServer", "s1:6649", "s4:6649").Return(uint32(3), nil).Once()
+	out, err := runCmd(Cmd, "replace s1:6649 s4:6649")
+	assert.NoError(t, err)
+	assert.Equal(t, "Replacing server s1:6649 with s4:6649: 3 shards are being moved", out)
+
+	common.MockedAdminClient.On("ReplaceServer", "s2:6649", "s4:6649").
+		Return(uint32(0), errors.New("only a server that is not running can be replaced")).Once()
+	out, err = runCmd(Cmd, "replace s2:6649 s4:6649")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: only a server that is not running can be replaced", out)
+
+	_, err = runCmd(Cmd, "replace s1:6649")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestServer_List(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ListServers").Return([]oxia.ServerInfo{
+		{PublicAddress: "s1:6648", InternalAddress: "s1:6649", Running: true, Replicas: 3, Leaders: 1},
+		{PublicAddress: "s2:6648", InternalAddress: "s2:6649", Draining: true, Replicas: 3,
+			Labels: map[string]string{"zone": "z2"}},
+	}, nil)
+	out, err := runCmd(Cmd, "list")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"publicAddress":"s1:6648","internalAddress":"s1:6649","running":true,"replicas":3,"leaders":1}`+"\n"+
+		`{"publicAddress":"s2:6648","internalAddress":"s2:6649","running":false,"draining":true,"replicas":3,"leaders":0,`+
+		`"labels":{"zone":"z2"}}`, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	namespace string
+	shard     int64
+}
+
+func (flags *flags) Reset() {
+	flags.namespace = oxia.DefaultNamespace
+	flags.shard = -1
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&Config.namespace, "namespace", "n", oxia.DefaultNamespace, "The namespace of the sessions")
+	listCmd.Flags().Int64VarP(&Config.shard, "shard", "s", -1, "Only list the sessions of this shard")
+
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(closeCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "session",
+	Short: "Manage client sessions",
+	Long:  `Operations on the client sessions, which hold the ephemeral records`,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the client sessions",
+	Long: `List the client sessions that are active on the shards of a namespace, with their client identity, ` +
+		`their last heartbeat and the number of their ephemeral records`,
+	Args:         cobra.NoArgs,
+	RunE:         execList,
+	SilenceUsage: true,
+}
+
+func execList(cmd *cobra.Command, _ []string) error {
+	var shard *int64
+	if Config.shard >= 0 {
+		shard = &Config.shard
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	sessions, err := client.ListSessions(ctx, Config.namespace, shard)
+	if err != nil {
+		return err
+	}
+	return common.WriteOutput(cmd.OutOrStdout(), sessions)
+}
+
+var closeCmd = &cobra.Command{
+	Use:   "close [flags] SHARD SESSION_ID",
+	Short: "Close a client session",
+	Long: `Close a client session, as if it had expired, eg: to release the ephemeral records held by a stuck client. ` +
+		`The ephemeral records of the session are deleted.`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         execClose,
+	SilenceUsage: true,
+}
+
+func execClose(cmd *cobra.Command, args []string) error {
+	shard, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	sessionId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if err = client.ForceCloseSession(ctx, Config.namespace, shard, sessionId); err != nil {
+		return err
+	}
+
+	cmd.Printf("Session %d of shard %d was closed\n", sessionId, shard)
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestSession_List(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	created := time.UnixMilli(1700000000000).UTC()
+	common.MockedAdminClient.On("ListSessions", "default", (*int64)(nil)).Return([]oxia.SessionInfo{{
+		Shard:          1,
+		SessionId:      5,
+		Leader:         "s1:6649",
+		ClientIdentity: "client-1",
+		Timeout:        15 * time.Second,
+		CreatedTime:    created,
+		LastHeartbeat:  created.Add(time.Minute),
+		EphemeralKeys:  3,
+	}}, nil)
+	out, err := runCmd(Cmd, "list")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"shard":1,"sessionId":5,"leader":"s1:6649","clientIdentity":"client-1","timeout":15000000000,`+
+		`"createdTime":"2023-11-14T22:13:20Z","lastHeartbeat":"2023-11-14T22:14:20Z","ephemeralKeys":3}`, out)
+
+	shard := int64(2)
+	common.MockedAdminClient.On("ListSessions", "ns-1", &shard).Return([]oxia.SessionInfo{}, nil)
+	out, err = runCmd(Cmd, "list -n ns-1 -s 2")
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestSession_Close(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ForceCloseSession", "default", int64(1), int64(5)).Return(nil)
+	out, err := runCmd(Cmd, "close 1 5")
+	assert.NoError(t, err)
+	assert.Equal(t, "Session 5 of shard 1 was closed", out)
+
+	common.MockedAdminClient.On("ForceCloseSession", "ns-1", int64(0), int64(7)).
+		Return(errors.New("session not found"))
+	out, err = runCmd(Cmd, "close -n ns-1 0 7")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: session not found", out)
+
+	_, err = runCmd(Cmd, "close 1 not-a-session")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	namespace string
+}
+
+func (flags *flags) Reset() {
+	flags.namespace = oxia.DefaultNamespace
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&Config.namespace, "namespace", "n", oxia.DefaultNamespace, "The namespace of the shard")
+
+	Cmd.AddCommand(statusCmd)
+	Cmd.AddCommand(transferLeaderCmd)
+	Cmd.AddCommand(mergeCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "shard",
+	Short: "Manage shards",
+	Long:  `Operations on the shards of a namespace`,
+}
+
+var statusCmd = &cobra.Command{
+	Use:          "status [flags] SHARD",
+	Short:        "Show the status of a shard",
+	Long:         `Print the status, term, leader, ensemble and hash range of a shard as a json object`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         execStatus,
+	SilenceUsage: true,
+}
+
+var transferLeaderCmd = &cobra.Command{
+	Use:   "transfer-leader [flags] SHARD SERVER",
+	Short: "Transfer the leadership of a shard",
+	Long: `Move the leadership of a shard to another member of its ensemble, identified by its public or internal address. ` +
+		`The new leader is only elected once it has caught up with the current one, so the request timeout ` +
+		`might need to be increased for shards with a large backlog.`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         execTransferLeader,
+	SilenceUsage: true,
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [flags] SHARD INTO_SHARD",
+	Short: "Merge a shard into an adjacent shard",
+	Long: `Copy the records of a shard into the adjacent shard, right before or after it, which then takes over its ` +
+		`hash range, and delete the shard. The shard is unavailable while its records are copied, so the request ` +
+		`timeout might need to be increased for shards with many records. The request is rejected if the shard ` +
+		`holds any client session.`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         execMerge,
+	SilenceUsage: true,
+}
+
+func execStatus(cmd *cobra.Command, args []string) error {
+	shard, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	shards, err := client.ListShards(ctx, Config.namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range shards {
+		if s.Shard == shard {
+			return common.WriteOutput(cmd.OutOrStdout(), []oxia.ShardInfo{s})
+		}
+	}
+	return fmt.Errorf("shard %d not found in namespace %s", shard, Config.namespace)
+}
+
+func execTransferLeader(cmd *cobra.Command, args []string) error {
+	shard, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if err = client.TransferLeader(ctx, Config.namespace, shard, args[1]); err != nil {
+		return err
+	}
+
+	cmd.Printf("Shard %d is now led by %s\n", shard, args[1])
+	return nil
+}
+
+func execMerge(cmd *cobra.Command, args []string) error {
+	shard, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	into, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := common.Config.NewRequestContext()
+	defer cancel()
+
+	if err = client.MergeShard(ctx, Config.namespace, shard, into); err != nil {
+		return err
+	}
+
+	cmd.Printf("Shard %d was merged into shard %d\n", shard, into)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/admin/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestShard_TransferLeader(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("TransferLeader", "default", int64(2), "s2:6649").Return(nil)
+	out, err := runCmd(Cmd, "transfer-leader 2 s2:6649")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shard 2 is now led by s2:6649", out)
+
+	common.MockedAdminClient.On("TransferLeader", "ns-1", int64(0), "s3:6649").
+		Return(errors.New("server s3:6649 is not running"))
+	out, err = runCmd(Cmd, "transfer-leader -n ns-1 0 s3:6649")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: server s3:6649 is not running", out)
+
+	_, err = runCmd(Cmd, "transfer-leader not-a-shard s2:6649")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestShard_Merge(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("MergeShard", "default", int64(1), int64(0)).Return(nil)
+	out, err := runCmd(Cmd, "merge 1 0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shard 1 was merged into shard 0", out)
+
+	common.MockedAdminClient.On("MergeShard", "ns-1", int64(2), int64(1)).
+		Return(errors.New("shard has client sessions"))
+	out, err = runCmd(Cmd, "merge -n ns-1 2 1")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: shard has client sessions", out)
+
+	_, err = runCmd(Cmd, "merge 0 not-a-shard")
+	assert.Error(t, err)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
+
+func TestShard_Status(t *testing.T) {
+	common.Config.RequestTimeout = time.Minute
+	common.MockedAdminClient = common.NewMockAdminClient()
+	common.MockedAdminClient.On("Close").Return(nil)
+
+	common.MockedAdminClient.On("ListShards", "ns-1").Return([]oxia.ShardInfo{
+		{Shard: 0, Status: "SteadyState", Term: 2, Leader: "s1:6649", Ensemble: []string{"s1:6649", "s2:6649"}},
+		{Shard: 1, Status: "Election", Term: 3, Ensemble: []string{"s1:6649", "s2:6649"}, Int32HashMin: 10},
+	}, nil)
+	out, err := runCmd(Cmd, "status -n ns-1 1")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"shard":1,"status":"Election","term":3,"ensemble":["s1:6649","s2:6649"],"int32HashMin":10,"int32HashMax":0}`, out)
+
+	out, err = runCmd(Cmd, "status -n ns-1 5")
+	assert.Error(t, err)
+	assert.Equal(t, "Error: shard 5 not found in namespace ns-1", out)
+
+	common.MockedAdminClient.AssertExpectations(t)
+}
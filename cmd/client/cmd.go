@@ -15,22 +15,20 @@
 package client
 
 import (
-	"fmt"
-
 	"github.com/streamnative/oxia/cmd/client/deleterange"
 
 	"github.com/spf13/cobra"
 
-	"github.com/streamnative/oxia/oxia"
-
 	"github.com/streamnative/oxia/cmd/client/common"
 	"github.com/streamnative/oxia/cmd/client/del"
+	"github.com/streamnative/oxia/cmd/client/export"
 	"github.com/streamnative/oxia/cmd/client/get"
+	"github.com/streamnative/oxia/cmd/client/imp"
+	"github.com/streamnative/oxia/cmd/client/increment"
 	"github.com/streamnative/oxia/cmd/client/list"
 	"github.com/streamnative/oxia/cmd/client/notifications"
 	"github.com/streamnative/oxia/cmd/client/put"
 	"github.com/streamnative/oxia/cmd/client/rangescan"
-	oxiacommon "github.com/streamnative/oxia/common"
 )
 
 var (
@@ -42,16 +40,16 @@ var (
 )
 
 func init() {
-	defaultServiceAddress := fmt.Sprintf("localhost:%d", oxiacommon.DefaultPublicPort)
-	Cmd.PersistentFlags().StringVarP(&common.Config.ServiceAddr, "service-address", "a", defaultServiceAddress, "Service address")
-	Cmd.PersistentFlags().StringVarP(&common.Config.Namespace, "namespace", "n", oxia.DefaultNamespace, "The Oxia namespace to use")
-	Cmd.PersistentFlags().DurationVar(&common.Config.RequestTimeout, "request-timeout", oxia.DefaultRequestTimeout, "Requests timeout")
+	common.AddFlags(Cmd.PersistentFlags())
 
 	Cmd.AddCommand(put.Cmd)
+	Cmd.AddCommand(increment.Cmd)
 	Cmd.AddCommand(del.Cmd)
 	Cmd.AddCommand(get.Cmd)
 	Cmd.AddCommand(list.Cmd)
 	Cmd.AddCommand(rangescan.Cmd)
 	Cmd.AddCommand(deleterange.Cmd)
 	Cmd.AddCommand(notifications.Cmd)
+	Cmd.AddCommand(export.Cmd)
+	Cmd.AddCommand(imp.Cmd)
 }
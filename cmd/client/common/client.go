@@ -15,8 +15,13 @@
 package common
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/spf13/pflag"
+
+	oxiacommon "github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/oxia"
 )
 
@@ -29,15 +34,41 @@ type ClientConfig struct {
 	ServiceAddr    string
 	Namespace      string
 	RequestTimeout time.Duration
+	TLS            security.TLSOption
+}
+
+// AddFlags adds the flags to connect to the cluster to the flag set of a
+// command.
+func AddFlags(flags *pflag.FlagSet) {
+	defaultServiceAddress := fmt.Sprintf("localhost:%d", oxiacommon.DefaultPublicPort)
+	flags.StringVarP(&Config.ServiceAddr, "service-address", "a", defaultServiceAddress, "Service address")
+	flags.StringVarP(&Config.Namespace, "namespace", "n", oxia.DefaultNamespace, "The Oxia namespace to use")
+	flags.DurationVar(&Config.RequestTimeout, "request-timeout", oxia.DefaultRequestTimeout, "Requests timeout")
+
+	// TLS section
+	flags.StringVar(&Config.TLS.CertFile, "tls-cert-file", "", "Tls certificate file, to authenticate the client")
+	flags.StringVar(&Config.TLS.KeyFile, "tls-key-file", "", "Tls key file")
+	flags.StringVar(&Config.TLS.TrustedCaFile, "tls-trusted-ca-file", "", "Tls trusted ca file, to verify the servers")
+	flags.StringVar(&Config.TLS.ServerName, "tls-server-name", "", "Tls server name")
+	flags.BoolVar(&Config.TLS.InsecureSkipVerify, "tls-insecure-skip-verify", false, "Tls insecure skip verify")
 }
 
-func (ClientConfig) NewClient() (oxia.SyncClient, error) {
+func (c ClientConfig) NewClient() (oxia.SyncClient, error) {
 	if MockedClient != nil {
 		return MockedClient, nil
 	}
 
-	return oxia.NewSyncClient(Config.ServiceAddr,
-		oxia.WithRequestTimeout(Config.RequestTimeout),
-		oxia.WithNamespace(Config.Namespace),
-	)
+	options := []oxia.ClientOption{
+		oxia.WithRequestTimeout(c.RequestTimeout),
+		oxia.WithNamespace(c.Namespace),
+	}
+	if c.TLS.IsConfigured() {
+		tlsConf, err := c.TLS.MakeClientTLSConf()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, oxia.WithTLS(tlsConf))
+	}
+
+	return oxia.NewSyncClient(c.ServiceAddr, options...)
 }
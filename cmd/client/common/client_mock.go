@@ -16,6 +16,7 @@ package common
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
@@ -41,6 +42,11 @@ func (m *MockClient) Put(_ context.Context, key string, value []byte, options ..
 	return args.String(0), oxia.Version{}, args.Error(2)
 }
 
+func (m *MockClient) Increment(_ context.Context, key string, delta int64, options ...oxia.PutOption) (value int64, version oxia.Version, err error) {
+	args := m.MethodCalled("Increment", key, delta, options)
+	return args.Get(0).(int64), oxia.Version{}, args.Error(2)
+}
+
 func (m *MockClient) Delete(_ context.Context, key string, options ...oxia.DeleteOption) error {
 	args := m.MethodCalled("Delete", key, options)
 	return args.Error(0)
@@ -78,6 +84,10 @@ func (m *MockClient) RangeScan(_ context.Context, minKeyInclusive string, maxKey
 	return arg0
 }
 
-func (*MockClient) GetNotifications() (oxia.Notifications, error) {
+func (*MockClient) GetNotifications(...oxia.NotificationsOption) (oxia.Notifications, error) {
+	return nil, errors.New("not implemented in mock")
+}
+
+func (*MockClient) GrantLease(string, time.Duration) (oxia.Lease, error) {
 	return nil, errors.New("not implemented in mock")
 }
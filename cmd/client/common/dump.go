@@ -0,0 +1,213 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// The formats of the records exported and imported in bulk.
+const (
+	DumpFormatJSONL = "jsonl"
+	DumpFormatCSV   = "csv"
+)
+
+// EncodingBase64 is the encoding of the values that are not valid UTF-8.
+const EncodingBase64 = "base64"
+
+var dumpCSVHeader = []string{"key", "value", "encoding"}
+
+// DumpRecord is a record exported and imported in bulk. The value is kept as
+// is when it's valid UTF-8, and base64 encoded otherwise.
+type DumpRecord struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+func NewDumpRecord(key string, value []byte) DumpRecord {
+	if utf8.Valid(value) {
+		return DumpRecord{Key: key, Value: string(value)}
+	}
+	return DumpRecord{Key: key, Value: base64.StdEncoding.EncodeToString(value), Encoding: EncodingBase64}
+}
+
+func (r DumpRecord) DecodeValue() ([]byte, error) {
+	switch r.Encoding {
+	case "":
+		return []byte(r.Value), nil
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(r.Value)
+	default:
+		return nil, errors.Errorf("unknown encoding %q of the value of key %q", r.Encoding, r.Key)
+	}
+}
+
+func ValidateDumpFormat(format string) error {
+	switch format {
+	case DumpFormatJSONL, DumpFormatCSV:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidFormat, "format %q", format)
+	}
+}
+
+// DumpWriter writes the records in the jsonl (one JSON object per line) or
+// the csv format. The csv format starts with a header line.
+type DumpWriter struct {
+	out     *bufio.Writer
+	csv     *csv.Writer
+	encoder *json.Encoder
+}
+
+func NewDumpWriter(out io.Writer, format string) (*DumpWriter, error) {
+	if err := ValidateDumpFormat(format); err != nil {
+		return nil, err
+	}
+
+	w := &DumpWriter{out: bufio.NewWriter(out)}
+	if format == DumpFormatJSONL {
+		w.encoder = json.NewEncoder(w.out)
+		w.encoder.SetEscapeHTML(false)
+		return w, nil
+	}
+
+	w.csv = csv.NewWriter(w.out)
+	if err := w.csv.Write(dumpCSVHeader); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *DumpWriter) Write(record DumpRecord) error {
+	if w.encoder != nil {
+		return w.encoder.Encode(record)
+	}
+	return w.csv.Write([]string{record.Key, record.Value, record.Encoding})
+}
+
+// Flush writes the buffered records to the output.
+func (w *DumpWriter) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.out.Flush()
+}
+
+// DumpReader reads the records written by a DumpWriter. The csv format must
+// start with a header line, with at least the key and the value columns, in
+// any order.
+type DumpReader struct {
+	in   *bufio.Reader
+	line int
+
+	csv      *csv.Reader
+	columns  map[string]int
+	maxIndex int
+}
+
+func NewDumpReader(in io.Reader, format string) (*DumpReader, error) {
+	if err := ValidateDumpFormat(format); err != nil {
+		return nil, err
+	}
+
+	r := &DumpReader{in: bufio.NewReader(in)}
+	if format == DumpFormatJSONL {
+		return r, nil
+	}
+
+	r.csv = csv.NewReader(r.in)
+	r.csv.FieldsPerRecord = -1
+	header, err := r.csv.Read()
+	if err == io.EOF {
+		// An empty input has no records
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.columns = map[string]int{}
+	for i, name := range header {
+		r.columns[name] = i
+	}
+	for _, name := range dumpCSVHeader[:2] {
+		idx, ok := r.columns[name]
+		if !ok {
+			return nil, errors.Errorf("missing column %q in the csv header", name)
+		}
+		r.maxIndex = max(r.maxIndex, idx)
+	}
+	return r, nil
+}
+
+// Read returns the next record, or io.EOF at the end of the input.
+func (r *DumpReader) Read() (DumpRecord, error) {
+	if r.csv != nil {
+		return r.readCSV()
+	}
+
+	for {
+		line, err := r.in.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return DumpRecord{}, err
+		}
+		r.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		record := DumpRecord{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return DumpRecord{}, errors.Wrapf(err, "invalid record at line %d", r.line)
+		}
+		return record, nil
+	}
+}
+
+func (r *DumpReader) readCSV() (DumpRecord, error) {
+	if r.columns == nil {
+		return DumpRecord{}, io.EOF
+	}
+
+	fields, err := r.csv.Read()
+	if err != nil {
+		return DumpRecord{}, err
+	}
+	if len(fields) <= r.maxIndex {
+		line, _ := r.csv.FieldPos(0)
+		return DumpRecord{}, errors.Errorf("missing fields in the record at line %d", line)
+	}
+
+	record := DumpRecord{
+		Key:   fields[r.columns["key"]],
+		Value: fields[r.columns["value"]],
+	}
+	if idx, ok := r.columns["encoding"]; ok && idx < len(fields) {
+		record.Encoding = fields[idx]
+	}
+	return record, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpRecord(t *testing.T) {
+	r := NewDumpRecord("a", []byte("hello"))
+	assert.Equal(t, DumpRecord{Key: "a", Value: "hello"}, r)
+	value, err := r.DecodeValue()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), value)
+
+	r = NewDumpRecord("b", []byte{0xff, 0x00})
+	assert.Equal(t, DumpRecord{Key: "b", Value: "/wA=", Encoding: EncodingBase64}, r)
+	value, err = r.DecodeValue()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x00}, value)
+
+	_, err = DumpRecord{Key: "c", Value: "x", Encoding: "rot13"}.DecodeValue()
+	assert.Error(t, err)
+}
+
+func TestDump_RoundTrip(t *testing.T) {
+	records := []DumpRecord{
+		NewDumpRecord("/a", []byte("hello")),
+		NewDumpRecord("/b", []byte("comma, \"quote\"\nnew line")),
+		NewDumpRecord("/c", []byte{0xff, 0x00}),
+		NewDumpRecord("/d", []byte{}),
+	}
+
+	for _, format := range []string{DumpFormatJSONL, DumpFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			w, err := NewDumpWriter(out, format)
+			assert.NoError(t, err)
+			for _, r := range records {
+				assert.NoError(t, w.Write(r))
+			}
+			assert.NoError(t, w.Flush())
+
+			r, err := NewDumpReader(out, format)
+			assert.NoError(t, err)
+			var res []DumpRecord
+			for {
+				record, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				res = append(res, record)
+			}
+			assert.Equal(t, records, res)
+		})
+	}
+}
+
+func TestDumpWriter_Format(t *testing.T) {
+	out := new(bytes.Buffer)
+	w, err := NewDumpWriter(out, DumpFormatJSONL)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(NewDumpRecord("/a<b>", []byte("x"))))
+	assert.NoError(t, w.Write(NewDumpRecord("/c", []byte{0xff})))
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, `{"key":"/a<b>","value":"x"}
+{"key":"/c","value":"/w==","encoding":"base64"}
+`, out.String())
+
+	out.Reset()
+	w, err = NewDumpWriter(out, DumpFormatCSV)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write(NewDumpRecord("/a", []byte("x,y"))))
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "key,value,encoding\n/a,\"x,y\",\n", out.String())
+
+	_, err = NewDumpWriter(out, "xml")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestDumpReader(t *testing.T) {
+	readAll := func(input string, format string) ([]DumpRecord, error) {
+		r, err := NewDumpReader(strings.NewReader(input), format)
+		if err != nil {
+			return nil, err
+		}
+		var res []DumpRecord
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return res, nil
+			} else if err != nil {
+				return res, err
+			}
+			res = append(res, record)
+		}
+	}
+
+	// Empty lines are skipped, and the last line may have no new line
+	res, err := readAll("{\"key\":\"a\",\"value\":\"1\"}\n\n{\"key\":\"b\",\"value\":\"2\"}", DumpFormatJSONL)
+	assert.NoError(t, err)
+	assert.Equal(t, []DumpRecord{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, res)
+
+	_, err = readAll("{\"key\":\"a\",\"value\":\"1\"}\nnot json\n", DumpFormatJSONL)
+	assert.ErrorContains(t, err, "line 2")
+
+	// The columns can be in any order, and the encoding is optional
+	res, err = readAll("value,key\n1,a\n2,b\n", DumpFormatCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, []DumpRecord{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, res)
+
+	res, err = readAll("", DumpFormatCSV)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	_, err = readAll("key,data\na,1\n", DumpFormatCSV)
+	assert.ErrorContains(t, err, `missing column "value"`)
+
+	_, err = readAll("key,value\na,1\nb\n", DumpFormatCSV)
+	assert.ErrorContains(t, err, "line 3")
+
+	_, err = readAll("", "xml")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/oxia"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatTSV  = "tsv"
+)
+
+var ErrInvalidFormat = fmt.Errorf("invalid output format, expected %q, %q or %q", FormatText, FormatJSON, FormatTSV)
+
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatTSV:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidFormat, "format %q", format)
+	}
+}
+
+// PrefixKeyRange returns the key range [keyMin, keyMax) of the keys that
+// start with the given prefix.
+//
+// Since the keys are sorted hierarchically, keys that have more `/` after
+// the prefix belong to a deeper level of the hierarchy and are not in the
+// range. For instance, the range of `a/` contains `a/b` but not `a/b/c`.
+func PrefixKeyRange(prefix string) (keyMin string, keyMax string) {
+	// Only the last level of the prefix can be incremented, without changing
+	// the level of the upper bound
+	level := prefix[:strings.LastIndexByte(prefix, '/')+1]
+	segment := []byte(prefix[len(level):])
+	for len(segment) > 0 {
+		if segment[len(segment)-1] < 0xff {
+			segment[len(segment)-1]++
+			return prefix, level + string(segment)
+		}
+		segment = segment[:len(segment)-1]
+	}
+
+	// All the keys of the level sort before the deeper ones
+	return prefix, level + "/"
+}
+
+func NewOutputRecord(result oxia.GetResult, hexValue bool) OutputRecord {
+	value := string(result.Value)
+	if hexValue {
+		value = hex.EncodeToString(result.Value)
+	}
+
+	version := result.Version
+	return OutputRecord{
+		OutputVersion: OutputVersion{
+			Key:                result.Key,
+			VersionId:          version.VersionId,
+			CreatedTimestamp:   time.UnixMilli(int64(version.CreatedTimestamp)),
+			ModifiedTimestamp:  time.UnixMilli(int64(version.ModifiedTimestamp)),
+			ModificationsCount: version.ModificationsCount,
+			Ephemeral:          version.Ephemeral,
+			ClientIdentity:     version.ClientIdentity,
+		},
+		Value: value,
+	}
+}
+
+// WriteKeys writes the keys with the given format: one key per line for
+// text and tsv, a JSON array for json.
+func WriteKeys(out io.Writer, format string, keys []string) {
+	if format == FormatJSON {
+		if keys == nil {
+			keys = []string{}
+		}
+		b, err := json.Marshal(keys)
+		if err != nil {
+			panic(err)
+		}
+		writeLine(out, string(b))
+		return
+	}
+
+	for _, key := range keys {
+		writeLine(out, escapeTSV(key))
+	}
+}
+
+// TSVHeader is the header line of the records written in the tsv format.
+var TSVHeader = strings.Join([]string{"key", "value", "version_id", "created_timestamp", "modified_timestamp",
+	"modifications_count", "ephemeral", "client_identity"}, "\t")
+
+// WriteRecord writes a record in the json (one JSON object per line) or the
+// tsv format. Tabs, newlines and backslashes in the tsv fields are escaped.
+func WriteRecord(out io.Writer, format string, record OutputRecord) {
+	if format == FormatJSON {
+		WriteOutput(out, record)
+		return
+	}
+
+	writeLine(out, strings.Join([]string{
+		escapeTSV(record.Key),
+		escapeTSV(record.Value),
+		strconv.FormatInt(record.VersionId, 10),
+		record.CreatedTimestamp.Format(time.RFC3339Nano),
+		record.ModifiedTimestamp.Format(time.RFC3339Nano),
+		strconv.FormatInt(record.ModificationsCount, 10),
+		strconv.FormatBool(record.Ephemeral),
+		escapeTSV(record.ClientIdentity),
+	}, "\t"))
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func escapeTSV(s string) string {
+	return tsvEscaper.Replace(s)
+}
+
+func writeLine(out io.Writer, line string) {
+	if _, err := io.WriteString(out, line+"\n"); err != nil {
+		panic(err)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common/compare"
 )
 
 func TestWriteOutput(t *testing.T) {
@@ -47,3 +49,41 @@ func TestWriteOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestPrefixKeyRange(t *testing.T) {
+	for _, test := range []struct {
+		prefix string
+		keyMin string
+		keyMax string
+	}{
+		{"a", "a", "b"},
+		{"a/", "a/", "a//"},
+		{"a/b", "a/b", "a/c"},
+		{"a\xff", "a\xff", "b"},
+		{"\xff", "\xff", "/"},
+		{"a/\xff", "a/\xff", "a//"},
+	} {
+		keyMin, keyMax := PrefixKeyRange(test.prefix)
+		assert.Equal(t, test.keyMin, keyMin)
+		assert.Equal(t, test.keyMax, keyMax)
+	}
+
+	inRange := func(prefix string, key string) bool {
+		keyMin, keyMax := PrefixKeyRange(prefix)
+		return compare.CompareWithSlash([]byte(keyMin), []byte(key)) <= 0 &&
+			compare.CompareWithSlash([]byte(key), []byte(keyMax)) < 0
+	}
+
+	assert.True(t, inRange("/users/", "/users/a"))
+	assert.True(t, inRange("/users/", "/users/"))
+	assert.False(t, inRange("/users/", "/users/a/settings"))
+	assert.False(t, inRange("/users/", "/users"))
+	assert.False(t, inRange("/users/", "/usersx/a"))
+	assert.True(t, inRange("/users/a", "/users/a"))
+	assert.True(t, inRange("/users/a", "/users/ab"))
+	assert.False(t, inRange("/users/a", "/users/b"))
+	assert.False(t, inRange("/users/a", "/users/a/settings"))
+	assert.True(t, inRange("/users/\xff", "/users/\xff\xff"))
+	assert.False(t, inRange("/users/\xff", "/users/a"))
+	assert.False(t, inRange("/users/\xff", "/users/\xff/a"))
+}
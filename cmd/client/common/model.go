@@ -26,6 +26,16 @@ type OutputVersion struct {
 	ClientIdentity     string    `json:"client_identity"`
 }
 
+type OutputCounter struct {
+	OutputVersion
+	Value int64 `json:"value"`
+}
+
+type OutputRecord struct {
+	OutputVersion
+	Value string `json:"value"`
+}
+
 type OutputError struct {
 	Err string `json:"error,omitempty"`
 }
@@ -0,0 +1,85 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress counts the records processed by a bulk operation, and reports the
+// count and the rate periodically. The reports are written apart from the
+// records, usually to the standard error.
+type Progress struct {
+	out       io.Writer
+	operation string
+	start     time.Time
+	count     atomic.Int64
+	done      chan any
+	wg        sync.WaitGroup
+}
+
+// NewProgress starts reporting the progress of the operation at the given
+// interval, or only once closed if the interval is 0.
+func NewProgress(out io.Writer, operation string, interval time.Duration) *Progress {
+	p := &Progress{
+		out:       out,
+		operation: operation,
+		start:     time.Now(),
+		done:      make(chan any),
+	}
+	if interval > 0 {
+		p.wg.Add(1)
+		go p.run(interval)
+	}
+	return p
+}
+
+func (p *Progress) run(interval time.Duration) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.report("in progress", "")
+		}
+	}
+}
+
+func (p *Progress) report(state string, details string) {
+	elapsed := time.Since(p.start)
+	count := p.count.Load()
+	_, _ = fmt.Fprintf(p.out, "%s %s: %d records%s in %v (%.1f records/s)\n", p.operation, state, count, details,
+		elapsed.Round(time.Millisecond), float64(count)/max(elapsed.Seconds(), 0.001))
+}
+
+func (p *Progress) Add(n int64) {
+	p.count.Add(n)
+}
+
+// Close stops the periodic reports and reports the final count, with the
+// given details.
+func (p *Progress) Close(details string) {
+	close(p.done)
+	p.wg.Wait()
+	p.report("completed", details)
+}
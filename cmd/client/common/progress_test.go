@@ -0,0 +1,57 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lockedBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.Buffer.String()
+}
+
+func TestProgress(t *testing.T) {
+	out := &lockedBuffer{}
+	p := NewProgress(out, "Import", 10*time.Millisecond)
+	p.Add(3)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "Import in progress: 3 records in ")
+	}, 10*time.Second, 10*time.Millisecond)
+
+	p.Add(2)
+	p.Close(" (1 skipped)")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Contains(t, lines[len(lines)-1], "Import completed: 5 records (1 skipped) in ")
+	assert.Contains(t, lines[len(lines)-1], "records/s)")
+}
@@ -31,27 +31,25 @@ var (
 type flags struct {
 	keyMin       string
 	keyMax       string
+	prefix       string
 	partitionKey string
 }
 
 func (flags *flags) Reset() {
 	flags.keyMin = ""
 	flags.keyMax = ""
+	flags.prefix = ""
 	flags.partitionKey = ""
 }
 
 func init() {
 	Cmd.Flags().StringVarP(&Config.keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
 	Cmd.Flags().StringVarP(&Config.keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	Cmd.Flags().StringVar(&Config.prefix, "prefix", "", "Delete the records whose keys start with the prefix, instead of a key range")
 	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
-	err := Cmd.MarkFlagRequired("key-min")
-	if err != nil {
-		panic(err)
-	}
-	err = Cmd.MarkFlagRequired("key-max")
-	if err != nil {
-		panic(err)
-	}
+	Cmd.MarkFlagsRequiredTogether("key-min", "key-max")
+	Cmd.MarkFlagsOneRequired("key-min", "prefix")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-min")
 }
 
 var Cmd = &cobra.Command{
@@ -73,5 +71,9 @@ func exec(_ *cobra.Command, _ []string) error {
 		options = append(options, oxia.PartitionKey(Config.partitionKey))
 	}
 
+	if Config.prefix != "" {
+		Config.keyMin, Config.keyMax = common.PrefixKeyRange(Config.prefix)
+	}
+
 	return client.DeleteRange(context.Background(), Config.keyMin, Config.keyMax, options...)
 }
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/oxia"
@@ -35,6 +36,9 @@ func runCmd(cmd *cobra.Command, args string, stdin string) (string, error) {
 	cmd.SetArgs(strings.Split(args, " "))
 	err := cmd.Execute()
 	Config.Reset()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
 	return strings.TrimSpace(actual.String()), err
 }
 
@@ -49,6 +53,7 @@ func TestDeleteRange_exec(t *testing.T) {
 		{"range", "--key-min a --key-max c", []any{"a", "c", emptyOptions}},
 		{"short", "-s a -e c", []any{"a", "c", emptyOptions}},
 		{"partition-key", "-s a -e c -p xyz", []any{"a", "c", []oxia.DeleteRangeOption{oxia.PartitionKey("xyz")}}},
+		{"prefix", "--prefix a/", []any{"a/", "a//", emptyOptions}},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			common.MockedClient = common.NewMockClient()
@@ -61,3 +66,20 @@ func TestDeleteRange_exec(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteRange_invalidFlags(t *testing.T) {
+	for _, args := range []string{
+		"-p xyz",
+		"-s a",
+		"--prefix a -s a -e c",
+	} {
+		t.Run(args, func(t *testing.T) {
+			common.MockedClient = common.NewMockClient()
+
+			_, err := runCmd(Cmd, args, "")
+			assert.Error(t, err)
+
+			common.MockedClient.AssertNotCalled(t, "DeleteRange")
+		})
+	}
+}
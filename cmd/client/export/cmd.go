@@ -0,0 +1,132 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	oxiacommon "github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	keyMin           string
+	keyMax           string
+	prefix           string
+	partitionKey     string
+	format           string
+	output           string
+	progressInterval time.Duration
+}
+
+func (flags *flags) Reset() {
+	flags.keyMin = ""
+	flags.keyMax = ""
+	flags.prefix = ""
+	flags.partitionKey = ""
+	flags.format = common.DumpFormatJSONL
+	flags.output = ""
+	flags.progressInterval = 5 * time.Second
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&Config.keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
+	Cmd.Flags().StringVarP(&Config.keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	Cmd.Flags().StringVar(&Config.prefix, "prefix", "", "Export the records whose keys start with the prefix, instead of a key range")
+	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	Cmd.Flags().StringVarP(&Config.format, "format", "f", common.DumpFormatJSONL, "Output format: jsonl or csv")
+	Cmd.Flags().StringVarP(&Config.output, "output", "o", "", "The file where the records are written, instead of the standard output")
+	Cmd.Flags().DurationVar(&Config.progressInterval, "progress-interval", 5*time.Second, "The interval at which the progress is reported on the standard error, 0 to only report it at the end")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-min")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-max")
+}
+
+var Cmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export records",
+	Long: `Export the keys and the values of the records in a key range, in the jsonl or the csv format. The values ` +
+		`that are not valid UTF-8 are base64 encoded, and the internal records are left out. The exported records can be imported with the import command.`,
+	Args:         cobra.NoArgs,
+	RunE:         exec,
+	SilenceUsage: true,
+}
+
+func exec(cmd *cobra.Command, _ []string) (err error) {
+	if err := common.ValidateDumpFormat(Config.format); err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewClient()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = cmd.OutOrStdout()
+	if Config.output != "" {
+		f, err := os.Create(Config.output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = multierr.Append(err, f.Close())
+		}()
+		out = f
+	}
+
+	writer, err := common.NewDumpWriter(out, Config.format)
+	if err != nil {
+		return err
+	}
+
+	var options []oxia.RangeScanOption
+	if Config.partitionKey != "" {
+		options = append(options, oxia.PartitionKey(Config.partitionKey))
+	}
+
+	if Config.prefix != "" {
+		Config.keyMin, Config.keyMax = common.PrefixKeyRange(Config.prefix)
+	}
+
+	progress := common.NewProgress(cmd.ErrOrStderr(), "Export", Config.progressInterval)
+	defer progress.Close("")
+
+	for result := range client.RangeScan(context.Background(), Config.keyMin, Config.keyMax, options...) {
+		if result.Err != nil {
+			return result.Err
+		}
+		// The internal keys do not sort after all the other keys, so they
+		// are skipped as they are read instead of being left out of the range
+		if strings.HasPrefix(result.Key, oxiacommon.InternalKeyPrefix) {
+			continue
+		}
+		if err := writer.Write(common.NewDumpRecord(result.Key, result.Value)); err != nil {
+			return err
+		}
+		progress.Add(1)
+	}
+	return writer.Flush()
+}
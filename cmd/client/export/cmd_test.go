@@ -0,0 +1,126 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, string, error) {
+	actual := new(bytes.Buffer)
+	progress := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(progress)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
+	return strings.TrimSpace(actual.String()), progress.String(), err
+}
+
+func mockRangeScan(expectedParameters []any, results ...oxia.GetResult) {
+	common.MockedClient = common.NewMockClient()
+	ch := make(chan oxia.GetResult, len(results))
+	for _, r := range results {
+		ch <- r
+	}
+	close(ch)
+	common.MockedClient.On("RangeScan", expectedParameters...).Return(ch)
+}
+
+func TestExport_exec(t *testing.T) {
+	var emptyOptions []oxia.RangeScanOption
+	results := []oxia.GetResult{
+		{Key: "a/b", Value: []byte("x")},
+		{Key: "a/c", Value: []byte{0xff}},
+	}
+
+	for _, test := range []struct {
+		name               string
+		args               string
+		expectedParameters []any
+		expected           string
+	}{
+		{"jsonl", "-s a -e b", []any{"a", "b", emptyOptions},
+			`{"key":"a/b","value":"x"}` + "\n" + `{"key":"a/c","value":"/w==","encoding":"base64"}`},
+		{"csv", "-s a -e b -f csv", []any{"a", "b", emptyOptions}, "key,value,encoding\na/b,x,\na/c,/w==,base64"},
+		{"no-max", "-s a", []any{"a", "", emptyOptions},
+			`{"key":"a/b","value":"x"}` + "\n" + `{"key":"a/c","value":"/w==","encoding":"base64"}`},
+		{"prefix", "--prefix a/ -f csv", []any{"a/", "a//", emptyOptions}, "key,value,encoding\na/b,x,\na/c,/w==,base64"},
+		{"partition-key", "-s a -e b -p xyz -f csv", []any{"a", "b", []oxia.RangeScanOption{oxia.PartitionKey("xyz")}},
+			"key,value,encoding\na/b,x,\na/c,/w==,base64"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			mockRangeScan(test.expectedParameters, results...)
+			out, _, err := runCmd(Cmd, test.args)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+			common.MockedClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExport_internalKeys(t *testing.T) {
+	// With the slash-aware ordering, "a/b" and "users/1" sort after "__oxia/x"
+	mockRangeScan([]any{"", "", []oxia.RangeScanOption(nil)},
+		oxia.GetResult{Key: "a", Value: []byte("1")},
+		oxia.GetResult{Key: "__oxia/x", Value: []byte("2")},
+		oxia.GetResult{Key: "a/b", Value: []byte("3")},
+		oxia.GetResult{Key: "users/1", Value: []byte("4")},
+	)
+	out, progress, err := runCmd(Cmd, "-f csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "key,value,encoding\na,1,\na/b,3,\nusers/1,4,", out)
+	assert.Contains(t, progress, "Export completed: 3 records in ")
+	common.MockedClient.AssertExpectations(t)
+}
+
+func TestExport_output(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.csv")
+	mockRangeScan([]any{"", "", []oxia.RangeScanOption(nil)}, oxia.GetResult{Key: "a", Value: []byte("x")})
+	out, progress, err := runCmd(Cmd, "-f csv -o "+path)
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Contains(t, progress, "Export completed: 1 records in ")
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "key,value,encoding\na,x,\n", string(content))
+}
+
+func TestExport_errors(t *testing.T) {
+	_, _, err := runCmd(Cmd, "-f xml")
+	assert.ErrorIs(t, err, common.ErrInvalidFormat)
+
+	_, _, err = runCmd(Cmd, "--prefix a/ -s a")
+	assert.Error(t, err)
+
+	mockRangeScan([]any{"", "", []oxia.RangeScanOption(nil)}, oxia.GetResult{Err: oxia.ErrUnknownStatus})
+	_, _, err = runCmd(Cmd, "-f csv")
+	assert.ErrorIs(t, err, oxia.ErrUnknownStatus)
+}
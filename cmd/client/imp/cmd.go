@@ -0,0 +1,199 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	input            string
+	format           string
+	partitionKey     string
+	concurrency      int
+	rate             float64
+	skipExisting     bool
+	progressInterval time.Duration
+}
+
+func (flags *flags) Reset() {
+	flags.input = ""
+	flags.format = common.DumpFormatJSONL
+	flags.partitionKey = ""
+	flags.concurrency = 16
+	flags.rate = 0
+	flags.skipExisting = false
+	flags.progressInterval = 5 * time.Second
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&Config.input, "input", "i", "", "The file where the records are read, instead of the standard input")
+	Cmd.Flags().StringVarP(&Config.format, "format", "f", common.DumpFormatJSONL, "Input format: jsonl or csv")
+	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	Cmd.Flags().IntVarP(&Config.concurrency, "concurrency", "c", 16, "The maximum number of records written concurrently")
+	Cmd.Flags().Float64Var(&Config.rate, "rate", 0, "The maximum number of records written per second, 0 for no limit")
+	Cmd.Flags().BoolVar(&Config.skipExisting, "skip-existing", false, "Do not overwrite the records that already exist")
+	Cmd.Flags().DurationVar(&Config.progressInterval, "progress-interval", 5*time.Second, "The interval at which the progress is reported on the standard error, 0 to only report it at the end")
+}
+
+var Cmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import records",
+	Long: `Import records in the jsonl or the csv format, as written by the export command. The csv format starts with ` +
+		`a header line with the key, value and, optionally, encoding columns. The import stops at the first record ` +
+		`that fails to be written.`,
+	Args:         cobra.NoArgs,
+	RunE:         exec,
+	SilenceUsage: true,
+}
+
+type record struct {
+	key   string
+	value []byte
+}
+
+func exec(cmd *cobra.Command, _ []string) (err error) {
+	if Config.concurrency < 1 {
+		return errors.New("the concurrency must be at least 1")
+	}
+
+	var in io.Reader = cmd.InOrStdin()
+	if Config.input != "" {
+		f, err := os.Open(Config.input)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = multierr.Append(err, f.Close())
+		}()
+		in = f
+	}
+
+	reader, err := common.NewDumpReader(in, Config.format)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.Config.NewClient()
+	if err != nil {
+		return err
+	}
+
+	var options []oxia.PutOption
+	if Config.partitionKey != "" {
+		options = append(options, oxia.PartitionKey(Config.partitionKey))
+	}
+	if Config.skipExisting {
+		options = append(options, oxia.ExpectedRecordNotExists())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var failure error
+	var failureOnce sync.Once
+	fail := func(err error) {
+		failureOnce.Do(func() {
+			failure = err
+			cancel()
+		})
+	}
+
+	progress := common.NewProgress(cmd.ErrOrStderr(), "Import", Config.progressInterval)
+	skipped := atomic.Int64{}
+	records := make(chan record, Config.concurrency)
+	wg := sync.WaitGroup{}
+	for i := 0; i < Config.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range records {
+				if ctx.Err() != nil {
+					// Drain the records after a failure
+					continue
+				}
+
+				_, _, err := client.Put(ctx, r.key, r.value, options...)
+				switch {
+				case err == nil:
+					progress.Add(1)
+				case Config.skipExisting && errors.Is(err, oxia.ErrUnexpectedVersionId):
+					skipped.Add(1)
+				default:
+					fail(errors.Wrapf(err, "failed to import key %q", r.key))
+				}
+			}
+		}()
+	}
+
+	if err := readRecords(ctx, reader, records); err != nil {
+		fail(err)
+	}
+	close(records)
+	wg.Wait()
+	progress.Close(fmt.Sprintf(" (%d skipped)", skipped.Load()))
+	return failure
+}
+
+func readRecords(ctx context.Context, reader *common.DumpReader, records chan<- record) error {
+	var limiter *rate.Limiter
+	if Config.rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(Config.rate), max(1, int(Config.rate)))
+	}
+
+	for {
+		r, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		value, err := r.DecodeValue()
+		if err != nil {
+			return err
+		}
+
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				// The import was stopped by a failure
+				return nil
+			}
+		}
+
+		select {
+		case records <- record{key: r.Key, value: value}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string, stdin string) (string, string, error) {
+	actual := new(bytes.Buffer)
+	progress := new(bytes.Buffer)
+	cmd.SetIn(bytes.NewBufferString(stdin))
+	cmd.SetOut(actual)
+	cmd.SetErr(progress)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), progress.String(), err
+}
+
+const records = `{"key":"a","value":"x"}
+{"key":"b","value":"/w==","encoding":"base64"}
+{"key":"c","value":""}
+`
+
+func TestImport_exec(t *testing.T) {
+	var emptyOptions []oxia.PutOption
+	for _, test := range []struct {
+		name    string
+		args    string
+		options []oxia.PutOption
+	}{
+		{"default", "--progress-interval 0", emptyOptions},
+		{"concurrency", "-c 1", emptyOptions},
+		{"rate", "--rate 1000", emptyOptions},
+		{"partition-key", "-p xyz", []oxia.PutOption{oxia.PartitionKey("xyz")}},
+		{"skip-existing", "--skip-existing", []oxia.PutOption{oxia.ExpectedRecordNotExists()}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			common.MockedClient = common.NewMockClient()
+			common.MockedClient.On("Put", "a", []byte("x"), test.options).Return("a", oxia.Version{}, nil)
+			common.MockedClient.On("Put", "b", []byte{0xff}, test.options).Return("b", oxia.Version{}, nil)
+			common.MockedClient.On("Put", "c", []byte{}, test.options).Return("c", oxia.Version{}, nil)
+
+			out, _, err := runCmd(Cmd, test.args, records)
+			assert.NoError(t, err)
+			assert.Empty(t, out)
+			common.MockedClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImport_csv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("key,value,encoding\na,\"x,y\",\nb,/w==,base64\n"), 0600))
+
+	common.MockedClient = common.NewMockClient()
+	common.MockedClient.On("Put", "a", []byte("x,y"), []oxia.PutOption(nil)).Return("a", oxia.Version{}, nil)
+	common.MockedClient.On("Put", "b", []byte{0xff}, []oxia.PutOption(nil)).Return("b", oxia.Version{}, nil)
+
+	_, _, err := runCmd(Cmd, "-f csv -i "+path, "")
+	assert.NoError(t, err)
+	common.MockedClient.AssertExpectations(t)
+}
+
+func TestImport_skipExisting(t *testing.T) {
+	options := []oxia.PutOption{oxia.ExpectedRecordNotExists()}
+	common.MockedClient = common.NewMockClient()
+	common.MockedClient.On("Put", "a", []byte("x"), options).Return("", oxia.Version{}, oxia.ErrUnexpectedVersionId)
+	common.MockedClient.On("Put", "b", []byte{0xff}, options).Return("b", oxia.Version{}, nil)
+	common.MockedClient.On("Put", "c", []byte{}, options).Return("", oxia.Version{}, oxia.ErrUnexpectedVersionId)
+
+	_, progress, err := runCmd(Cmd, "--skip-existing", records)
+	assert.NoError(t, err)
+	assert.Contains(t, progress, "Import completed: 1 records (2 skipped) in ")
+	common.MockedClient.AssertExpectations(t)
+}
+
+func TestImport_errors(t *testing.T) {
+	common.MockedClient = common.NewMockClient()
+	common.MockedClient.On("Put", "a", []byte("x"), []oxia.PutOption(nil)).Return("", oxia.Version{}, oxia.ErrUnexpectedVersionId)
+
+	_, _, err := runCmd(Cmd, "-c 1", `{"key":"a","value":"x"}`)
+	assert.ErrorIs(t, err, oxia.ErrUnexpectedVersionId)
+	assert.ErrorContains(t, err, `failed to import key "a"`)
+
+	common.MockedClient = common.NewMockClient()
+	common.MockedClient.On("Put", "a", []byte("x"), []oxia.PutOption(nil)).Return("a", oxia.Version{}, nil)
+	_, _, err = runCmd(Cmd, "-c 2", "{\"key\":\"a\",\"value\":\"x\"}\n{\"key\":")
+	assert.ErrorContains(t, err, "line 2")
+
+	_, _, err = runCmd(Cmd, "-c 2", `{"key":"a","value":"x","encoding":"rot13"}`)
+	assert.ErrorContains(t, err, "unknown encoding")
+
+	_, _, err = runCmd(Cmd, "-f xml", "")
+	assert.ErrorIs(t, err, common.ErrInvalidFormat)
+
+	_, _, err = runCmd(Cmd, "-c 0", "")
+	assert.Error(t, err)
+
+	_, _, err = runCmd(Cmd, "-i "+filepath.Join(t.TempDir(), "missing"), "")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
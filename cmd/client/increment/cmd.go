@@ -0,0 +1,100 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package increment
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	expectedVersion int64
+	partitionKey    string
+}
+
+func (flags *flags) Reset() {
+	flags.expectedVersion = -1
+	flags.partitionKey = ""
+}
+
+func init() {
+	Cmd.Flags().Int64VarP(&Config.expectedVersion, "expected-version", "e", -1, "Version of entry expected to be on the server")
+	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+}
+
+var Cmd = &cobra.Command{
+	Use:          "increment [flags] KEY [DELTA]",
+	Short:        "Increment a counter",
+	Long:         `Atomically add the delta, 1 by default, to the counter stored in the record, creating it from 0 if it doesn't exist, and print the new value`,
+	Args:         cobra.RangeArgs(1, 2),
+	RunE:         exec,
+	SilenceUsage: true,
+}
+
+func exec(cmd *cobra.Command, args []string) error {
+	client, err := common.Config.NewClient()
+	if err != nil {
+		return err
+	}
+
+	key := args[0]
+	delta := int64(1)
+	if len(args) == 2 {
+		if delta, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return errors.Wrapf(err, "invalid delta %q", args[1])
+		}
+	}
+
+	value, version, err := client.Increment(context.Background(), key, delta, getOptions()...)
+	if err != nil {
+		return err
+	}
+
+	common.WriteOutput(cmd.OutOrStdout(), common.OutputCounter{
+		OutputVersion: common.OutputVersion{
+			Key:                key,
+			VersionId:          version.VersionId,
+			CreatedTimestamp:   time.UnixMilli(int64(version.CreatedTimestamp)),
+			ModifiedTimestamp:  time.UnixMilli(int64(version.ModifiedTimestamp)),
+			ModificationsCount: version.ModificationsCount,
+			Ephemeral:          version.Ephemeral,
+			ClientIdentity:     version.ClientIdentity,
+		},
+		Value: value,
+	})
+	return nil
+}
+
+func getOptions() []oxia.PutOption {
+	var options []oxia.PutOption
+	if Config.expectedVersion != -1 {
+		options = append(options, oxia.ExpectedVersionId(Config.expectedVersion))
+	}
+	if Config.partitionKey != "" {
+		options = append(options, oxia.PartitionKey(Config.partitionKey))
+	}
+	return options
+}
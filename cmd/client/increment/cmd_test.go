@@ -0,0 +1,69 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package increment
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestIncrement_exec(t *testing.T) {
+	var emptyOptions []oxia.PutOption
+	for _, test := range []struct {
+		name               string
+		args               string
+		expectedParameters []any
+	}{
+		{"default-delta", "x", []any{"x", int64(1), emptyOptions}},
+		{"negative-delta", "x -- -5", []any{"x", int64(-5), emptyOptions}},
+		{"expected-version", "x 2 -e 5", []any{"x", int64(2), []oxia.PutOption{oxia.ExpectedVersionId(5)}}},
+		{"partition-key", "x 2 -p abc", []any{"x", int64(2), []oxia.PutOption{oxia.PartitionKey("abc")}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			common.MockedClient = common.NewMockClient()
+
+			common.MockedClient.On("Increment", test.expectedParameters...).Return(int64(7), oxia.Version{}, nil)
+			out, err := runCmd(Cmd, test.args)
+			assert.NoError(t, err)
+			assert.Contains(t, out, `"value":7`)
+
+			common.MockedClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIncrement_InvalidDelta(t *testing.T) {
+	common.MockedClient = common.NewMockClient()
+
+	_, err := runCmd(Cmd, "x abc")
+	assert.ErrorContains(t, err, "invalid delta")
+}
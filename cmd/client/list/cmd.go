@@ -31,19 +31,27 @@ var (
 type flags struct {
 	keyMin       string
 	keyMax       string
+	prefix       string
 	partitionKey string
+	format       string
 }
 
 func (flags *flags) Reset() {
 	flags.keyMin = ""
 	flags.keyMax = ""
+	flags.prefix = ""
 	flags.partitionKey = ""
+	flags.format = common.FormatText
 }
 
 func init() {
 	Cmd.Flags().StringVarP(&Config.keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
 	Cmd.Flags().StringVarP(&Config.keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	Cmd.Flags().StringVar(&Config.prefix, "prefix", "", "List the keys starting with the prefix, instead of a key range")
 	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	Cmd.Flags().StringVarP(&Config.format, "format", "f", common.FormatText, "Output format: text, json or tsv")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-min")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-max")
 }
 
 var Cmd = &cobra.Command{
@@ -55,13 +63,19 @@ var Cmd = &cobra.Command{
 }
 
 func exec(cmd *cobra.Command, _ []string) error {
+	if err := common.ValidateFormat(Config.format); err != nil {
+		return err
+	}
+
 	client, err := common.Config.NewClient()
 	if err != nil {
 		return err
 	}
 
 	var options []oxia.ListOption
-	if Config.keyMax == "" {
+	if Config.prefix != "" {
+		Config.keyMin, Config.keyMax = common.PrefixKeyRange(Config.prefix)
+	} else if Config.keyMax == "" {
 		// By default, do not list internal keys
 		Config.keyMax = "__oxia/"
 	}
@@ -75,6 +89,6 @@ func exec(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	common.WriteOutput(cmd.OutOrStdout(), list)
+	common.WriteKeys(cmd.OutOrStdout(), Config.format, list)
 	return nil
 }
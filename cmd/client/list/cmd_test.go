@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/oxia"
@@ -35,6 +36,9 @@ func runCmd(cmd *cobra.Command, args string, stdin string) (string, error) {
 	cmd.SetArgs(strings.Split(args, " "))
 	err := cmd.Execute()
 	Config.Reset()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
 	return strings.TrimSpace(actual.String()), err
 }
 
@@ -51,6 +55,7 @@ func TestList_exec(t *testing.T) {
 		{"range-no-min", "--key-max c", []any{"", "c", emptyOptions}},
 		{"range-no-max", "--key-min a", []any{"a", "__oxia/", emptyOptions}},
 		{"partition-key", "-s a -e c -p xyz", []any{"a", "c", []oxia.ListOption{oxia.PartitionKey("xyz")}}},
+		{"prefix", "--prefix a/", []any{"a/", "a//", emptyOptions}},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			common.MockedClient = common.NewMockClient()
@@ -63,3 +68,32 @@ func TestList_exec(t *testing.T) {
 		})
 	}
 }
+
+func TestList_format(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		args     string
+		keys     []string
+		expected string
+	}{
+		{"text", "-s a -e c", []string{"a", "b"}, "a\nb"},
+		{"tsv", "-s a -e c -f tsv", []string{"a", "b\tc"}, "a\nb\\tc"},
+		{"json", "-s a -e c -f json", []string{"a", "b"}, `["a","b"]`},
+		{"json-empty", "-s a -e c --format json", nil, `[]`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			common.MockedClient = common.NewMockClient()
+
+			common.MockedClient.On("List", "a", "c", []oxia.ListOption(nil)).Return(test.keys, nil)
+			out, err := runCmd(Cmd, test.args, "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+
+	_, err := runCmd(Cmd, "-f yaml", "")
+	assert.ErrorIs(t, err, common.ErrInvalidFormat)
+
+	_, err = runCmd(Cmd, "--prefix a -s b", "")
+	assert.Error(t, err)
+}
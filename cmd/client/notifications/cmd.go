@@ -15,13 +15,52 @@
 package notifications
 
 import (
+	"context"
 	"log/slog"
+	"strconv"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
 )
 
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	keyPrefixes  []string
+	types        []string
+	includeValue bool
+	startOffsets map[string]int64
+	consumerName string
+}
+
+func (flags *flags) Reset() {
+	flags.keyPrefixes = nil
+	flags.types = nil
+	flags.includeValue = false
+	flags.startOffsets = nil
+	flags.consumerName = ""
+}
+
+func init() {
+	Cmd.Flags().StringSliceVarP(&Config.keyPrefixes, "key-prefix", "k", nil, "Only follow the notifications for the keys starting with the prefix. Can be repeated")
+	Cmd.Flags().StringSliceVarP(&Config.types, "type", "t", nil, "Only follow the notifications of a type: created, modified, deleted or range-deleted. Can be repeated")
+	Cmd.Flags().BoolVar(&Config.includeValue, "include-value", false, "Include the value of the records in the created and modified notifications")
+	Cmd.Flags().StringToInt64Var(&Config.startOffsets, "start-offset", nil, "Resume the notifications of a shard from an offset, as shard=offset. Can be repeated")
+	Cmd.Flags().StringVar(&Config.consumerName, "consumer", "", "Subscribe as a durable consumer, acknowledging each notification once printed")
+}
+
+var notificationTypes = map[string]oxia.NotificationType{
+	"created":       oxia.KeyCreated,
+	"modified":      oxia.KeyModified,
+	"deleted":       oxia.KeyDeleted,
+	"range-deleted": oxia.KeyRangeDeleted,
+}
+
 var Cmd = &cobra.Command{
 	Use:   "notifications",
 	Short: "Get notifications stream",
@@ -38,7 +77,41 @@ func exec(_ *cobra.Command, _ []string) error {
 
 	defer client.Close()
 
-	notifications, err := client.GetNotifications()
+	var options []oxia.NotificationsOption
+	if len(Config.keyPrefixes) > 0 {
+		options = append(options, oxia.KeyPrefixes(Config.keyPrefixes...))
+	}
+	if len(Config.types) > 0 {
+		types := make([]oxia.NotificationType, 0, len(Config.types))
+		for _, name := range Config.types {
+			t, ok := notificationTypes[name]
+			if !ok {
+				return errors.Errorf("invalid notification type %q", name)
+			}
+			types = append(types, t)
+		}
+		options = append(options, oxia.EventTypes(types...))
+	}
+	if Config.includeValue {
+		options = append(options, oxia.IncludeValue())
+	}
+	if len(Config.startOffsets) > 0 {
+		offsets := map[int64]int64{}
+		for shard, offset := range Config.startOffsets {
+			shardId, err := strconv.ParseInt(shard, 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid shard %q", shard)
+			}
+			offsets[shardId] = offset
+		}
+		options = append(options, oxia.StartFromOffsets(offsets))
+	}
+
+	if Config.consumerName != "" {
+		options = append(options, oxia.ConsumerName(Config.consumerName))
+	}
+
+	notifications, err := client.GetNotifications(options...)
 	if err != nil {
 		return err
 	}
@@ -46,12 +119,23 @@ func exec(_ *cobra.Command, _ []string) error {
 	defer notifications.Close()
 
 	for notification := range notifications.Ch() {
-		slog.Info(
-			"",
+		attrs := []any{
 			slog.Any("type", notification.Type),
 			slog.String("key", notification.Key),
 			slog.Int64("version-id", notification.VersionId),
-		)
+			slog.Int64("shard", notification.Shard),
+			slog.Int64("offset", notification.Offset),
+		}
+		if notification.Value != nil {
+			attrs = append(attrs, slog.String("value", string(notification.Value)))
+		}
+		slog.Info("", attrs...)
+
+		if Config.consumerName != "" {
+			if err := notifications.Ack(context.Background(), notification); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
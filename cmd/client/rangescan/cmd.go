@@ -32,25 +32,34 @@ var (
 type flags struct {
 	keyMin         string
 	keyMax         string
+	prefix         string
 	hexDump        bool
 	includeVersion bool
 	partitionKey   string
+	format         string
 }
 
 func (flags *flags) Reset() {
 	flags.keyMin = ""
 	flags.keyMax = ""
+	flags.prefix = ""
 	flags.hexDump = false
 	flags.includeVersion = false
 	flags.partitionKey = ""
+	flags.format = common.FormatText
 }
 
 func init() {
 	Cmd.Flags().StringVarP(&Config.keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
 	Cmd.Flags().StringVarP(&Config.keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	Cmd.Flags().StringVar(&Config.prefix, "prefix", "", "Scan the records whose keys start with the prefix, instead of a key range")
 	Cmd.Flags().BoolVarP(&Config.includeVersion, "include-version", "v", false, "Include the record version object")
 	Cmd.Flags().BoolVar(&Config.hexDump, "hex", false, "Print the value in HexDump format")
 	Cmd.Flags().StringVarP(&Config.partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	Cmd.Flags().StringVarP(&Config.format, "format", "f", common.FormatText,
+		"Output format: text, json (one record per line) or tsv. The json and tsv formats always include the version")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-min")
+	Cmd.MarkFlagsMutuallyExclusive("prefix", "key-max")
 }
 
 var Cmd = &cobra.Command{
@@ -64,6 +73,10 @@ var Cmd = &cobra.Command{
 const lineSeparator = "-------------------------------------------------------------------------------\n"
 
 func exec(cmd *cobra.Command, _ []string) error {
+	if err := common.ValidateFormat(Config.format); err != nil {
+		return err
+	}
+
 	client, err := common.Config.NewClient()
 	if err != nil {
 		return err
@@ -74,12 +87,17 @@ func exec(cmd *cobra.Command, _ []string) error {
 		options = append(options, oxia.PartitionKey(Config.partitionKey))
 	}
 
-	if Config.keyMax == "" {
+	if Config.prefix != "" {
+		Config.keyMin, Config.keyMax = common.PrefixKeyRange(Config.prefix)
+	} else if Config.keyMax == "" {
 		// By default, do not list internal keys
 		Config.keyMax = "__oxia/"
 	}
 
 	ch := client.RangeScan(context.Background(), Config.keyMin, Config.keyMax, options...)
+	if Config.format != common.FormatText {
+		return writeRecords(cmd, ch)
+	}
 
 	isFirst := true
 	for result := range ch {
@@ -116,3 +134,18 @@ func exec(cmd *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+func writeRecords(cmd *cobra.Command, ch <-chan oxia.GetResult) error {
+	if Config.format == common.FormatTSV {
+		_, _ = cmd.OutOrStdout().Write([]byte(common.TSVHeader + "\n"))
+	}
+
+	for result := range ch {
+		if result.Err != nil {
+			return result.Err
+		}
+
+		common.WriteRecord(cmd.OutOrStdout(), Config.format, common.NewOutputRecord(result, Config.hexDump))
+	}
+	return nil
+}
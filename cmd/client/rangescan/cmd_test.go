@@ -18,8 +18,10 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/oxia"
@@ -35,6 +37,9 @@ func runCmd(cmd *cobra.Command, args string, stdin string) (string, error) {
 	cmd.SetArgs(strings.Split(args, " "))
 	err := cmd.Execute()
 	Config.Reset()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
 	return strings.TrimSpace(actual.String()), err
 }
 
@@ -51,6 +56,7 @@ func TestRangeScan_exec(t *testing.T) {
 		{"range-no-min", "--key-max c", []any{"", "c", emptyOptions}, []string{"a", "b"}},
 		{"range-no-max", "--key-min a", []any{"a", "__oxia/", emptyOptions}, []string{"a", "b", "c"}},
 		{"partition-key", "-s a -e c -p xyz", []any{"a", "c", []oxia.RangeScanOption{oxia.PartitionKey("xyz")}}, []string{"a", "b"}},
+		{"prefix", "--prefix a/", []any{"a/", "a//", emptyOptions}, []string{"a/b", "a/c"}},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			common.MockedClient = common.NewMockClient()
@@ -77,3 +83,45 @@ func TestRangeScan_exec(t *testing.T) {
 		})
 	}
 }
+
+func TestRangeScan_format(t *testing.T) {
+	created := time.UnixMilli(1)
+	modified := time.UnixMilli(2)
+	timestamps := created.Format(time.RFC3339Nano) + "\t" + modified.Format(time.RFC3339Nano)
+
+	for _, test := range []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{"json", "-f json", `{"key":"a","version_id":1,"created_timestamp":"` + created.Format(time.RFC3339Nano) +
+			`","modified_timestamp":"` + modified.Format(time.RFC3339Nano) +
+			`","modifications_count":0,"ephemeral":false,"client_identity":"me","value":"x\ty"}`},
+		{"json-hex", "-f json --hex", `{"key":"a","version_id":1,"created_timestamp":"` + created.Format(time.RFC3339Nano) +
+			`","modified_timestamp":"` + modified.Format(time.RFC3339Nano) +
+			`","modifications_count":0,"ephemeral":false,"client_identity":"me","value":"780979"}`},
+		{"tsv", "-f tsv", common.TSVHeader + "\na\tx\\ty\t1\t" + timestamps + "\t0\tfalse\tme"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			common.MockedClient = common.NewMockClient()
+
+			ch := make(chan oxia.GetResult, 1)
+			ch <- oxia.GetResult{
+				Key:   "a",
+				Value: []byte("x\ty"),
+				Version: oxia.Version{
+					VersionId:         1,
+					CreatedTimestamp:  uint64(created.UnixMilli()),
+					ModifiedTimestamp: uint64(modified.UnixMilli()),
+					ClientIdentity:    "me",
+				},
+			}
+			close(ch)
+
+			common.MockedClient.On("RangeScan", "", "__oxia/", []oxia.RangeScanOption(nil)).Return(ch)
+			out, err := runCmd(Cmd, test.args, "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
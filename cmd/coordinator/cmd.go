@@ -15,9 +15,11 @@
 package coordinator
 
 import (
+	"context"
 	"errors"
 	"io"
 	"log/slog"
+	"os"
 	"strings"
 
 	"github.com/fsnotify/fsnotify"
@@ -28,6 +30,7 @@ import (
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/coordinator"
+	"github.com/streamnative/oxia/coordinator/impl"
 	"github.com/streamnative/oxia/coordinator/model"
 )
 
@@ -36,6 +39,18 @@ var (
 	configFile string
 	peerTLS    security.TLSOption
 	serverTLS  security.TLSOption
+	adminTLS   security.TLSOption
+	etcdTLS    security.TLSOption
+	oxiaTLS    security.TLSOption
+
+	etcdPasswordFile string
+	oxiaTokenFile    string
+
+	serverDiscovery impl.K8SServerDiscoveryOptions
+
+	internalIPFilter security.IPFilterOption
+	metricsIPFilter  security.IPFilterOption
+	adminIPFilter    security.IPFilterOption
 
 	Cmd = &cobra.Command{
 		Use:     "coordinator",
@@ -49,11 +64,32 @@ var (
 func init() {
 	flag.InternalAddr(Cmd, &conf.InternalServiceAddr)
 	flag.MetricsAddr(Cmd, &conf.MetricsServiceAddr)
-	Cmd.Flags().Var(&conf.MetadataProviderImpl, "metadata", "Metadata provider implementation: file, configmap or memory")
+	flag.IPFilter(Cmd, "internal", &internalIPFilter)
+	flag.IPFilter(Cmd, "metrics", &metricsIPFilter)
+	flag.IPFilter(Cmd, "admin", &adminIPFilter)
+	Cmd.Flags().StringVar(&conf.AdminServiceAddr, "admin-addr", "", "Admin service bind address. When empty, the admin service is served on the internal address")
+	Cmd.Flags().StringVar(&conf.AdminAuthOptions.ProviderName, "admin-auth-provider-name", "", "Admin service authentication provider name. supported: oidc, token-file, mtls")
+	Cmd.Flags().StringVar(&conf.AdminAuthOptions.ProviderParams, "admin-auth-provider-params", "", "Admin service authentication provider params, as for the servers --auth-provider-params")
+	Cmd.Flags().Var(&conf.MetadataProviderImpl, "metadata", "Metadata provider implementation: file, configmap, etcd, oxia or memory")
 	Cmd.Flags().StringVar(&conf.K8SMetadataNamespace, "k8s-namespace", conf.K8SMetadataNamespace, "Kubernetes namespace for oxia config maps")
 	Cmd.Flags().StringVar(&conf.K8SMetadataConfigMapName, "k8s-configmap-name", conf.K8SMetadataConfigMapName, "ConfigMap name for cluster status configmap")
 	Cmd.Flags().StringVar(&conf.FileMetadataPath, "file-clusters-status-path", "data/cluster-status.json", "The path where the cluster status is stored when using 'file' provider")
+	Cmd.Flags().StringSliceVar(&conf.EtcdEndpoints, "etcd-endpoints", nil, "The etcd endpoints when using 'etcd' provider")
+	Cmd.Flags().StringVar(&conf.EtcdUsername, "etcd-username", "", "The etcd user, when the etcd authentication is enabled")
+	Cmd.Flags().StringVar(&etcdPasswordFile, "etcd-password-file", "", "The file containing the password of the etcd user")
+	Cmd.Flags().StringVar(&conf.OxiaMetadataServiceAddress, "oxia-metadata-address", "", "The service address of the Oxia cluster where the cluster status is stored when using 'oxia' provider")
+	Cmd.Flags().StringVar(&conf.OxiaMetadataNamespace, "oxia-metadata-namespace", common.DefaultNamespace, "The namespace where the cluster status is stored when using 'oxia' provider")
+	Cmd.Flags().StringVar(&oxiaTokenFile, "oxia-metadata-token-file", "", "The file containing the token to authenticate to the Oxia cluster where the cluster status is stored")
+	Cmd.Flags().StringVar(&conf.MetadataKey, "metadata-key", "/oxia/cluster-status", "The key where the cluster status is stored when using 'etcd' or 'oxia' provider")
 	Cmd.Flags().StringVarP(&configFile, "conf", "f", "", "Cluster config file")
+	Cmd.Flags().BoolVar(&conf.LeaderElection, "leader-election", false, "Elect a leader among multiple coordinator replicas, using the metadata provider (configmap, file, etcd or oxia)")
+	Cmd.Flags().StringSliceVar(&conf.Peers, "peers", nil, "The internal addresses of the other coordinator replicas, whose state is mirrored while on standby")
+	Cmd.Flags().StringVar(&conf.ImportClusterStatusPath, "import-cluster-status", "", "A cluster status export to bootstrap from, only used when the metadata provider holds no cluster status")
+	Cmd.Flags().StringVar(&serverDiscovery.LabelSelector, "k8s-server-selector", "", "Label selector of the server pods in the k8s-namespace, added to the servers of the cluster config once ready, eg: when scaled by an autoscaler")
+	Cmd.Flags().StringVar(&serverDiscovery.Service, "k8s-server-service", "", "Headless service through which the discovered server pods are reached")
+	Cmd.Flags().IntVar(&serverDiscovery.PublicPort, "k8s-server-public-port", 6648, "Public port of the discovered server pods")
+	Cmd.Flags().IntVar(&serverDiscovery.InternalPort, "k8s-server-internal-port", 6649, "Internal port of the discovered server pods")
+	Cmd.Flags().StringVar(&conf.AuditLogPath, "audit-log-path", "", "The file where the mutations of the cluster are appended, only the latest ones are kept in memory when not set")
 
 	// server TLS section
 	Cmd.Flags().StringVar(&serverTLS.CertFile, "tls-cert-file", "", "Tls certificate file")
@@ -64,6 +100,15 @@ func init() {
 	Cmd.Flags().BoolVar(&serverTLS.InsecureSkipVerify, "tls-insecure-skip-verify", false, "Tls insecure skip verify")
 	Cmd.Flags().BoolVar(&serverTLS.ClientAuth, "tls-client-auth", false, "Tls client auth")
 
+	// admin server TLS section
+	Cmd.Flags().StringVar(&adminTLS.CertFile, "admin-tls-cert-file", "", "Admin tls certificate file")
+	Cmd.Flags().StringVar(&adminTLS.KeyFile, "admin-tls-key-file", "", "Admin tls key file")
+	Cmd.Flags().Uint16Var(&adminTLS.MinVersion, "admin-tls-min-version", 0, "Admin tls minimum version")
+	Cmd.Flags().Uint16Var(&adminTLS.MaxVersion, "admin-tls-max-version", 0, "Admin tls maximum version")
+	Cmd.Flags().StringVar(&adminTLS.TrustedCaFile, "admin-tls-trusted-ca-file", "", "Admin tls trusted ca file")
+	Cmd.Flags().BoolVar(&adminTLS.InsecureSkipVerify, "admin-tls-insecure-skip-verify", false, "Admin tls insecure skip verify")
+	Cmd.Flags().BoolVar(&adminTLS.ClientAuth, "admin-tls-client-auth", false, "Admin tls client auth")
+
 	// peer client TLS section
 	Cmd.Flags().StringVar(&peerTLS.CertFile, "peer-tls-cert-file", "", "Peer tls certificate file")
 	Cmd.Flags().StringVar(&peerTLS.KeyFile, "peer-tls-key-file", "", "Peer tls key file")
@@ -72,9 +117,29 @@ func init() {
 	Cmd.Flags().StringVar(&peerTLS.TrustedCaFile, "peer-tls-trusted-ca-file", "", "Peer tls trusted ca file")
 	Cmd.Flags().BoolVar(&peerTLS.InsecureSkipVerify, "peer-tls-insecure-skip-verify", false, "Peer tls insecure skip verify")
 	Cmd.Flags().StringVar(&peerTLS.ServerName, "peer-tls-server-name", "", "Peer tls server name")
+
+	// etcd client TLS section
+	Cmd.Flags().StringVar(&etcdTLS.CertFile, "etcd-tls-cert-file", "", "Etcd tls certificate file")
+	Cmd.Flags().StringVar(&etcdTLS.KeyFile, "etcd-tls-key-file", "", "Etcd tls key file")
+	Cmd.Flags().StringVar(&etcdTLS.TrustedCaFile, "etcd-tls-trusted-ca-file", "", "Etcd tls trusted ca file")
+	Cmd.Flags().BoolVar(&etcdTLS.InsecureSkipVerify, "etcd-tls-insecure-skip-verify", false, "Etcd tls insecure skip verify")
+	Cmd.Flags().StringVar(&etcdTLS.ServerName, "etcd-tls-server-name", "", "Etcd tls server name")
+
+	// oxia metadata client TLS section
+	Cmd.Flags().StringVar(&oxiaTLS.CertFile, "oxia-metadata-tls-cert-file", "", "Oxia metadata tls certificate file")
+	Cmd.Flags().StringVar(&oxiaTLS.KeyFile, "oxia-metadata-tls-key-file", "", "Oxia metadata tls key file")
+	Cmd.Flags().StringVar(&oxiaTLS.TrustedCaFile, "oxia-metadata-tls-trusted-ca-file", "", "Oxia metadata tls trusted ca file")
+	Cmd.Flags().BoolVar(&oxiaTLS.InsecureSkipVerify, "oxia-metadata-tls-insecure-skip-verify", false, "Oxia metadata tls insecure skip verify")
+	Cmd.Flags().StringVar(&oxiaTLS.ServerName, "oxia-metadata-tls-server-name", "", "Oxia metadata tls server name")
 }
 
 func validate(*cobra.Command, []string) error {
+	if conf.LeaderElection && conf.MetadataProviderImpl == coordinator.Memory {
+		return errors.New("leader-election is not supported with metadata=memory")
+	}
+	if len(conf.Peers) > 0 && !conf.LeaderElection {
+		return errors.New("peers can only be set with leader-election")
+	}
 	if conf.MetadataProviderImpl == coordinator.Configmap {
 		if conf.K8SMetadataNamespace == "" {
 			return errors.New("k8s-namespace must be set with metadata=configmap")
@@ -83,6 +148,27 @@ func validate(*cobra.Command, []string) error {
 			return errors.New("k8s-configmap-name must be set with metadata=configmap")
 		}
 	}
+	if conf.MetadataProviderImpl == coordinator.Etcd && len(conf.EtcdEndpoints) == 0 {
+		return errors.New("etcd-endpoints must be set with metadata=etcd")
+	}
+	if (conf.EtcdUsername == "") != (etcdPasswordFile == "") {
+		return errors.New("etcd-username and etcd-password-file must be set together")
+	}
+	if conf.MetadataProviderImpl == coordinator.Oxia && conf.OxiaMetadataServiceAddress == "" {
+		return errors.New("oxia-metadata-address must be set with metadata=oxia")
+	}
+	if serverDiscovery.LabelSelector != "" {
+		if conf.K8SMetadataNamespace == "" {
+			return errors.New("k8s-namespace must be set with k8s-server-selector")
+		}
+		if serverDiscovery.Service == "" {
+			return errors.New("k8s-server-service must be set with k8s-server-selector")
+		}
+	}
+	if conf.AdminServiceAddr == "" && (adminTLS.IsConfigured() || conf.AdminAuthOptions.IsEnabled() ||
+		len(adminIPFilter.Allowed) > 0 || len(adminIPFilter.Denied) > 0) {
+		return errors.New("admin-addr must be set with the admin tls, auth or cidrs options")
+	}
 	return nil
 }
 
@@ -132,9 +218,51 @@ func loadClusterConfig(v *viper.Viper) (model.ClusterConfig, error) {
 		return cc, err
 	}
 
+	if err := loadServerLabels(v, &cc); err != nil {
+		return cc, err
+	}
+
 	return cc, nil
 }
 
+// The entries of the servers list can carry the labels and the pool of each
+// server, next to its addresses. They are merged into the server metadata,
+// where the values set explicitly take precedence.
+func loadServerLabels(v *viper.Viper, cc *model.ClusterConfig) error {
+	var servers []struct {
+		Internal string
+		Labels   map[string]string
+		Pool     string
+	}
+	if err := v.UnmarshalKey("servers", &servers); err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		if len(server.Labels) == 0 && server.Pool == "" {
+			continue
+		}
+		if cc.ServerMetadata == nil {
+			cc.ServerMetadata = map[string]model.ServerMetadata{}
+		}
+
+		sm := cc.ServerMetadata[server.Internal]
+		labels := map[string]string{}
+		for k, val := range server.Labels {
+			labels[k] = val
+		}
+		for k, val := range sm.Labels {
+			labels[k] = val
+		}
+		sm.Labels = labels
+		if sm.Pool == "" {
+			sm.Pool = server.Pool
+		}
+		cc.ServerMetadata[server.Internal] = sm
+	}
+	return nil
+}
+
 func exec(*cobra.Command, []string) error {
 	v := viper.New()
 
@@ -143,6 +271,26 @@ func exec(*cobra.Command, []string) error {
 		return loadClusterConfig(v)
 	}
 
+	if serverDiscovery.LabelSelector != "" {
+		serverDiscovery.Namespace = conf.K8SMetadataNamespace
+		discovery := impl.NewK8SServerDiscovery(impl.NewK8SClientset(impl.NewK8SClientConfig()), serverDiscovery)
+		conf.ClusterConfigProvider = func() (model.ClusterConfig, error) {
+			cc, err := loadClusterConfig(v)
+			if err != nil {
+				return cc, err
+			}
+			return cc, discovery.Merge(&cc)
+		}
+
+		go common.DoWithLabels(context.Background(), map[string]string{
+			"component": "k8s-server-discovery",
+		}, func() {
+			discovery.Watch(context.Background(), func() {
+				conf.ClusterConfigChangeNotifications <- nil
+			})
+		})
+	}
+
 	v.OnConfigChange(func(e fsnotify.Event) {
 		conf.ClusterConfigChangeNotifications <- nil
 	})
@@ -167,6 +315,44 @@ func exec(*cobra.Command, []string) error {
 				return nil, err
 			}
 		}
+		if conf.InternalIPFilter, err = internalIPFilter.MakeIPFilter(); err != nil {
+			return nil, err
+		}
+		if conf.MetricsIPFilter, err = metricsIPFilter.MakeIPFilter(); err != nil {
+			return nil, err
+		}
+		if adminTLS.IsConfigured() {
+			if conf.AdminTLS, err = adminTLS.MakeServerTLSConf(); err != nil {
+				return nil, err
+			}
+		}
+		if etcdTLS.IsConfigured() {
+			if conf.EtcdTLS, err = etcdTLS.MakeClientTLSConf(); err != nil {
+				return nil, err
+			}
+		}
+		if etcdPasswordFile != "" {
+			password, err := os.ReadFile(etcdPasswordFile)
+			if err != nil {
+				return nil, err
+			}
+			conf.EtcdPassword = strings.TrimSpace(string(password))
+		}
+		if oxiaTLS.IsConfigured() {
+			if conf.OxiaMetadataTLS, err = oxiaTLS.MakeClientTLSConf(); err != nil {
+				return nil, err
+			}
+		}
+		if oxiaTokenFile != "" {
+			token, err := os.ReadFile(oxiaTokenFile)
+			if err != nil {
+				return nil, err
+			}
+			conf.OxiaMetadataToken = strings.TrimSpace(string(token))
+		}
+		if conf.AdminIPFilter, err = adminIPFilter.MakeIPFilter(); err != nil {
+			return nil, err
+		}
 		return coordinator.New(conf)
 	})
 	return nil
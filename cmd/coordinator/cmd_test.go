@@ -27,6 +27,7 @@ import (
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/coordinator"
+	"github.com/streamnative/oxia/coordinator/impl"
 	"github.com/streamnative/oxia/coordinator/model"
 )
 
@@ -181,10 +182,28 @@ func TestCmd(t *testing.T) {
 		{[]string{"--metadata=configmap", "--k8s-namespace=foo}"}, true},
 		{[]string{"--metadata=configmap", "--k8s-configmap-name=bar"}, true},
 		{[]string{"--metadata=invalid"}, true},
+		{[]string{"--leader-election"}, false},
+		{[]string{"--metadata=memory", "--leader-election"}, true},
+		{[]string{"--leader-election", "--peers=coordinator-1:6649"}, false},
+		{[]string{"--peers=coordinator-1:6649"}, true},
+		{[]string{"--metadata=etcd"}, true},
+		{[]string{"--metadata=etcd", "--etcd-endpoints=localhost:2379"}, false},
+		{[]string{"--metadata=etcd", "--etcd-endpoints=localhost:2379", "--leader-election"}, false},
+		{[]string{"--metadata=etcd", "--etcd-endpoints=localhost:2379", "--etcd-username=oxia"}, true},
+		{[]string{"--metadata=etcd", "--etcd-endpoints=localhost:2379", "--etcd-username=oxia", "--etcd-password-file=password"}, false},
+		{[]string{"--metadata=oxia"}, true},
+		{[]string{"--metadata=oxia", "--oxia-metadata-address=localhost:6648"}, false},
+		{[]string{"--metadata=oxia", "--oxia-metadata-address=localhost:6648", "--leader-election"}, false},
+		{[]string{"--metadata=oxia", "--oxia-metadata-address=localhost:6648", "--oxia-metadata-tls-trusted-ca-file=ca.crt"}, false},
+		{[]string{"--k8s-server-selector=app.kubernetes.io/component=server", "--k8s-namespace=foo"}, true},
+		{[]string{"--k8s-server-selector=app.kubernetes.io/component=server", "--k8s-server-service=oxia-svc"}, true},
+		{[]string{"--k8s-server-selector=app.kubernetes.io/component=server", "--k8s-server-service=oxia-svc", "--k8s-namespace=foo"}, false},
 	} {
 		t.Run(strings.Join(test.args, "_"), func(t *testing.T) {
 			conf = coordinator.NewConfig()
 			configFile = ""
+			etcdPasswordFile = ""
+			serverDiscovery = impl.K8SServerDiscoveryOptions{}
 			viper.Reset()
 			Cmd.SetArgs(test.args)
 			Cmd.RunE = func(cmd *cobra.Command, args []string) error { return nil }
@@ -193,3 +212,45 @@ func TestCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadServerLabels(t *testing.T) {
+	name := t.TempDir() + "/config.yaml"
+	assert.NoError(t, os.WriteFile(name, []byte(`
+namespaces:
+  - name: default
+    initialShardCount: 1
+    replicationFactor: 1
+    placementConstraints:
+      - label: disk
+        values: ["ssd"]
+servers:
+  - public: public-1:1234
+    internal: internal-1:5678
+    labels:
+      zone: us-east-1a
+      disk: ssd
+  - public: public-2:1234
+    internal: internal-2:5678
+    pool: eu
+serverMetadata:
+  internal-1:5678:
+    labels:
+      zone: us-east-1b
+`), os.ModePerm))
+
+	v := viper.New()
+	v.SetConfigFile(name)
+	clusterConf, err := loadClusterConfig(v)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []model.ServerAddress{
+		{Public: "public-1:1234", Internal: "internal-1:5678"},
+		{Public: "public-2:1234", Internal: "internal-2:5678"},
+	}, clusterConf.Servers)
+	assert.Equal(t, map[string]model.ServerMetadata{
+		"internal-1:5678": {Labels: map[string]string{"zone": "us-east-1b", "disk": "ssd"}},
+		"internal-2:5678": {Labels: map[string]string{}, Pool: "eu"},
+	}, clusterConf.ServerMetadata)
+	assert.Equal(t, []model.PlacementConstraint{{Label: "disk", Values: []string{"ssd"}}},
+		clusterConf.Namespaces[0].PlacementConstraints)
+}
@@ -0,0 +1,327 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	oxiacommon "github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/oxia"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server"
+	"github.com/streamnative/oxia/server/encryption"
+	"github.com/streamnative/oxia/server/kv"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	dir               string
+	namespace         string
+	shard             int64
+	keyMin            string
+	keyMax            string
+	prefix            string
+	includeInternal   bool
+	keys              bool
+	values            bool
+	hexDump           bool
+	stats             bool
+	statsDepth        int
+	encryptionOptions encryption.Options
+}
+
+func (flags *flags) Reset() {
+	flags.dir = "./data/db"
+	flags.namespace = oxia.DefaultNamespace
+	flags.shard = 0
+	flags.keyMin = ""
+	flags.keyMax = ""
+	flags.prefix = ""
+	flags.includeInternal = false
+	flags.keys = false
+	flags.values = false
+	flags.hexDump = false
+	flags.stats = false
+	flags.statsDepth = 1
+	flags.encryptionOptions = encryption.Options{}
+}
+
+func init() {
+	inspectCmd.Flags().StringVarP(&Config.dir, "dir", "d", "./data/db", "The data directory of the server")
+	inspectCmd.Flags().StringVarP(&Config.namespace, "namespace", "n", oxia.DefaultNamespace, "The namespace of the shard")
+	inspectCmd.Flags().Int64Var(&Config.shard, "shard", 0, "The shard to inspect")
+	inspectCmd.Flags().StringVarP(&Config.keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
+	inspectCmd.Flags().StringVarP(&Config.keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	inspectCmd.Flags().StringVar(&Config.prefix, "prefix", "", "Only inspect the keys starting with the prefix, instead of a key range")
+	inspectCmd.Flags().BoolVar(&Config.includeInternal, "include-internal", false, "Include the internal records of Oxia in the range, when no key-max is set")
+	inspectCmd.Flags().BoolVar(&Config.keys, "keys", false, "Print the keys in the range, with their version")
+	inspectCmd.Flags().BoolVar(&Config.values, "values", false, "Print the values of the keys in the range, implies --keys")
+	inspectCmd.Flags().BoolVar(&Config.hexDump, "hex", false, "Print the values in hex")
+	inspectCmd.Flags().BoolVar(&Config.stats, "stats", false, "Print the number of records and their size, per prefix, in the range")
+	inspectCmd.Flags().IntVar(&Config.statsDepth, "stats-depth", 1, "The number of levels of the key hierarchy in the prefixes of the stats")
+	inspectCmd.Flags().StringVar(&Config.encryptionOptions.ProviderName, "encryption-provider-name", "",
+		"The key provider of the server, when the database is encrypted. supported: keyfile, vault, aws-kms, gcp-kms")
+	inspectCmd.Flags().StringVar(&Config.encryptionOptions.ProviderParams, "encryption-provider-params", "",
+		"The params of the key provider, as given to the server")
+	if err := inspectCmd.MarkFlagRequired("shard"); err != nil {
+		panic(err)
+	}
+	inspectCmd.MarkFlagsMutuallyExclusive("prefix", "key-min")
+	inspectCmd.MarkFlagsMutuallyExclusive("prefix", "key-max")
+
+	Cmd.AddCommand(inspectCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database utils",
+	Long:  `Tools to inspect the database of a server offline`,
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Inspect the database of a shard",
+	Long: `Print the internal state of the database of a shard: its commit offset, its term and its sessions, with ` +
+		`their number of ephemeral records. Optionally, print the records in a key range, or the number of records ` +
+		`and their size per prefix. The output is one json object per line. The database is opened in read-only mode, ` +
+		`so the server must be stopped.`,
+	Args:         cobra.NoArgs,
+	RunE:         execInspect,
+	SilenceUsage: true,
+}
+
+type sessionOutput struct {
+	Id               int64  `json:"id"`
+	Identity         string `json:"identity"`
+	Principal        string `json:"principal,omitempty"`
+	TimeoutMs        uint32 `json:"timeoutMs"`
+	EphemeralRecords int64  `json:"ephemeralRecords"`
+}
+
+type prefixOutput struct {
+	Prefix  string `json:"prefix"`
+	Records int64  `json:"records"`
+	Bytes   int64  `json:"bytes"`
+}
+
+type versionOutput struct {
+	VersionId          int64     `json:"versionId"`
+	ModificationsCount int64     `json:"modificationsCount"`
+	CreatedTimestamp   time.Time `json:"createdTimestamp"`
+	ModifiedTimestamp  time.Time `json:"modifiedTimestamp"`
+	SessionId          *int64    `json:"sessionId,omitempty"`
+	ClientIdentity     *string   `json:"clientIdentity,omitempty"`
+	PartitionKey       *string   `json:"partitionKey,omitempty"`
+	Principal          *string   `json:"principal,omitempty"`
+}
+
+// recordOutput has no version for the internal records that are not
+// stored with the format of the records of the users.
+type recordOutput struct {
+	Key     string         `json:"key"`
+	Version *versionOutput `json:"version,omitempty"`
+	Size    int            `json:"size"`
+	Value   *string        `json:"value,omitempty"`
+}
+
+type output struct {
+	Metadata *kv.DBMetadata `json:"metadata,omitempty"`
+	Session  *sessionOutput `json:"session,omitempty"`
+	Prefix   *prefixOutput  `json:"prefix,omitempty"`
+	Record   *recordOutput  `json:"record,omitempty"`
+}
+
+func execInspect(cmd *cobra.Command, _ []string) (err error) {
+	var provider encryption.KeyProvider
+	if Config.encryptionOptions.IsEnabled() {
+		if provider, err = encryption.NewKeyProvider(context.Background(), Config.encryptionOptions); err != nil {
+			return err
+		}
+		defer func() {
+			err = multierr.Append(err, provider.Close())
+		}()
+	}
+
+	inspector, err := kv.NewInspector(Config.dir, Config.namespace, Config.shard, provider)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Append(err, inspector.Close())
+	}()
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	metadata, err := inspector.Metadata()
+	if err != nil {
+		return err
+	}
+	if err = encoder.Encode(output{Metadata: &metadata}); err != nil {
+		return err
+	}
+
+	if err = writeSessions(inspector, encoder); err != nil {
+		return err
+	}
+
+	keyMin, keyMax := Config.keyMin, Config.keyMax
+	if Config.prefix != "" {
+		keyMin, keyMax = common.PrefixKeyRange(Config.prefix)
+	} else if keyMax == "" && !Config.includeInternal {
+		keyMax = oxiacommon.InternalKeyPrefix
+	}
+
+	if Config.keys || Config.values {
+		if err = inspector.Scan(keyMin, keyMax, func(record kv.Record) error {
+			return encoder.Encode(output{Record: newRecordOutput(record)})
+		}); err != nil {
+			return err
+		}
+	}
+
+	if Config.stats {
+		return writeStats(inspector, encoder, keyMin, keyMax)
+	}
+	return nil
+}
+
+func writeSessions(inspector *kv.Inspector, encoder *json.Encoder) error {
+	var sessions []*sessionOutput
+	if err := inspector.Scan(server.SessionKeyPrefix+"/", server.SessionKeyPrefix+"//", func(record kv.Record) error {
+		id, err := server.KeyToId(record.Key)
+		if err != nil {
+			return err
+		}
+		entry, err := record.StorageEntry()
+		if err != nil {
+			return err
+		}
+		metadata := &proto.SessionMetadata{}
+		if err = metadata.UnmarshalVT(entry.Value); err != nil {
+			return err
+		}
+		sessions = append(sessions, &sessionOutput{
+			Id:        int64(id),
+			Identity:  metadata.Identity,
+			Principal: metadata.GetPrincipal(),
+			TimeoutMs: metadata.TimeoutMs,
+		})
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		// The shadow keys of the ephemeral records of the session
+		sessionKey := server.SessionKey(server.SessionId(s.Id))
+		if err := inspector.Scan(sessionKey+"/", sessionKey+"//", func(kv.Record) error {
+			s.EphemeralRecords++
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := encoder.Encode(output{Session: s}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyPrefix returns the first levels of the key hierarchy of the key, up to
+// the given depth.
+func keyPrefix(key string, depth int) string {
+	end := 0
+	for i := 0; i < depth; i++ {
+		idx := strings.IndexByte(key[end:], '/')
+		if idx < 0 {
+			break
+		}
+		end += idx + 1
+	}
+	return key[:end]
+}
+
+func writeStats(inspector *kv.Inspector, encoder *json.Encoder, keyMin string, keyMax string) error {
+	stats := map[string]*prefixOutput{}
+	if err := inspector.Scan(keyMin, keyMax, func(record kv.Record) error {
+		prefix := keyPrefix(record.Key, Config.statsDepth)
+		s, ok := stats[prefix]
+		if !ok {
+			s = &prefixOutput{Prefix: prefix}
+			stats[prefix] = s
+		}
+		s.Records++
+		s.Bytes += int64(record.Size)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	prefixes := make([]string, 0, len(stats))
+	for prefix := range stats {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		if err := encoder.Encode(output{Prefix: stats[prefix]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newRecordOutput(record kv.Record) *recordOutput {
+	res := &recordOutput{
+		Key:  record.Key,
+		Size: record.Size,
+	}
+
+	value := record.Value
+	if entry, err := record.StorageEntry(); err == nil {
+		res.Version = &versionOutput{
+			VersionId:          entry.VersionId,
+			ModificationsCount: entry.ModificationsCount,
+			CreatedTimestamp:   time.UnixMilli(int64(entry.CreationTimestamp)),
+			ModifiedTimestamp:  time.UnixMilli(int64(entry.ModificationTimestamp)),
+			SessionId:          entry.SessionId,
+			ClientIdentity:     entry.ClientIdentity,
+			PartitionKey:       entry.PartitionKey,
+			Principal:          entry.Principal,
+		}
+		value = entry.Value
+	}
+
+	if Config.values {
+		v := string(value)
+		if Config.hexDump {
+			v = hex.EncodeToString(value)
+		}
+		res.Value = &v
+	}
+	return res
+}
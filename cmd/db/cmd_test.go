@@ -0,0 +1,130 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server"
+	"github.com/streamnative/oxia/server/kv"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	inspectCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
+	return strings.TrimSpace(actual.String()), err
+}
+
+func parseOutput(t *testing.T, out string) []output {
+	t.Helper()
+
+	var res []output
+	for _, line := range strings.Split(out, "\n") {
+		o := output{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &o))
+		res = append(res, o)
+	}
+	return res
+}
+
+func TestDB_Inspect(t *testing.T) {
+	dir := t.TempDir()
+	factory, err := kv.NewPebbleKVFactory(&kv.FactoryOptions{DataDir: dir, CacheSizeMB: 1})
+	assert.NoError(t, err)
+	db, err := kv.NewDB("ns-1", 2, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+
+	sessionMetadata, err := (&proto.SessionMetadata{TimeoutMs: 5000, Identity: "client-1", Principal: pb.String("alice")}).MarshalVT()
+	assert.NoError(t, err)
+	sessionId := int64(7)
+	_, err = db.ProcessWrite(&proto.WriteRequest{
+		Puts: []*proto.PutRequest{
+			{Key: "/users/a", Value: []byte("value-a")},
+			{Key: "/users/b", Value: []byte("value-b"), SessionId: &sessionId},
+			{Key: "/groups/a", Value: []byte("value-c")},
+			{Key: server.SessionKey(server.SessionId(sessionId)), Value: sessionMetadata},
+			{Key: server.ShadowKey(server.SessionId(sessionId), "/users/b"), Value: []byte{}},
+		},
+	}, 10, 0, kv.NoOpCallback)
+	assert.NoError(t, err)
+	assert.NoError(t, db.UpdateTerm(4))
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+
+	out, err := runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 --shard 2", dir))
+	assert.NoError(t, err)
+	res := parseOutput(t, out)
+	assert.Len(t, res, 2)
+	assert.Equal(t, &kv.DBMetadata{CommitOffset: 10, Term: 4}, res[0].Metadata)
+	assert.Equal(t, &sessionOutput{Id: 7, Identity: "client-1", Principal: "alice", TimeoutMs: 5000, EphemeralRecords: 1}, res[1].Session)
+
+	out, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 --shard 2 --values --prefix /users/", dir))
+	assert.NoError(t, err)
+	res = parseOutput(t, out)
+	assert.Len(t, res, 4)
+	assert.Equal(t, "/users/a", res[2].Record.Key)
+	assert.Equal(t, "value-a", *res[2].Record.Value)
+	assert.Nil(t, res[2].Record.Version.SessionId)
+	assert.Equal(t, "/users/b", res[3].Record.Key)
+	assert.Equal(t, sessionId, *res[3].Record.Version.SessionId)
+
+	out, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 --shard 2 --stats --stats-depth 2", dir))
+	assert.NoError(t, err)
+	res = parseOutput(t, out)
+	assert.Len(t, res, 4)
+	assert.Equal(t, "/groups/", res[2].Prefix.Prefix)
+	assert.EqualValues(t, 1, res[2].Prefix.Records)
+	assert.Equal(t, "/users/", res[3].Prefix.Prefix)
+	assert.EqualValues(t, 2, res[3].Prefix.Records)
+	assert.Positive(t, res[3].Prefix.Bytes)
+
+	out, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 --shard 2 --stats --include-internal", dir))
+	assert.NoError(t, err)
+	res = parseOutput(t, out)
+	var prefixes []string
+	for _, o := range res[2:] {
+		prefixes = append(prefixes, o.Prefix.Prefix)
+	}
+	assert.Equal(t, []string{"/", common.InternalKeyPrefix}, prefixes)
+
+	_, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 --shard 3", dir))
+	assert.Error(t, err)
+}
+
+func TestKeyPrefix(t *testing.T) {
+	assert.Equal(t, "", keyPrefix("a", 1))
+	assert.Equal(t, "/", keyPrefix("/a/b", 1))
+	assert.Equal(t, "/a/", keyPrefix("/a/b", 2))
+	assert.Equal(t, "/a/", keyPrefix("/a/b", 3))
+	assert.Equal(t, "a/b/", keyPrefix("a/b/c/d", 2))
+}
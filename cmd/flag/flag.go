@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/security"
 )
 
 func PublicAddr(cmd *cobra.Command, conf *string) {
@@ -33,3 +34,10 @@ func InternalAddr(cmd *cobra.Command, conf *string) {
 func MetricsAddr(cmd *cobra.Command, conf *string) {
 	cmd.Flags().StringVarP(conf, "metrics-addr", "m", fmt.Sprintf("0.0.0.0:%d", common.DefaultMetricsPort), "Metrics service bind address")
 }
+
+func IPFilter(cmd *cobra.Command, listener string, conf *security.IPFilterOption) {
+	cmd.Flags().StringSliceVar(&conf.Allowed, listener+"-allowed-cidrs", nil,
+		fmt.Sprintf("CIDRs allowed to connect to the %s service. All the addresses are allowed when empty", listener))
+	cmd.Flags().StringSliceVar(&conf.Denied, listener+"-denied-cidrs", nil,
+		fmt.Sprintf("CIDRs denied to connect to the %s service, even when allowed", listener))
+}
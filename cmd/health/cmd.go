@@ -16,6 +16,7 @@ package health
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/security"
 )
 
 type Config struct {
@@ -31,6 +33,7 @@ type Config struct {
 	Port    int
 	Timeout time.Duration
 	Service string
+	TLS     security.TLSOption
 }
 
 func NewConfig() Config {
@@ -58,12 +61,24 @@ func init() {
 	Cmd.Flags().IntVar(&config.Port, "port", config.Port, "Server port")
 	Cmd.Flags().DurationVar(&config.Timeout, "timeout", config.Timeout, "Health check timeout")
 	Cmd.Flags().StringVar(&config.Service, "service", config.Service, "Health check service")
+	Cmd.Flags().StringVar(&config.TLS.CertFile, "tls-cert-file", "", "Tls certificate file, when the server requires client auth")
+	Cmd.Flags().StringVar(&config.TLS.KeyFile, "tls-key-file", "", "Tls key file")
+	Cmd.Flags().StringVar(&config.TLS.TrustedCaFile, "tls-trusted-ca-file", "", "Tls trusted ca file, to verify the server")
+	Cmd.Flags().StringVar(&config.TLS.ServerName, "tls-server-name", "", "Tls server name")
+	Cmd.Flags().BoolVar(&config.TLS.InsecureSkipVerify, "tls-insecure-skip-verify", false, "Tls insecure skip verify")
 	Cmd.SilenceUsage = true
 	Cmd.SilenceErrors = true
 }
 
 func exec(*cobra.Command, []string) error {
-	clientPool := common.NewClientPool(nil, nil)
+	var tlsConf *tls.Config
+	if config.TLS.IsConfigured() {
+		var err error
+		if tlsConf, err = config.TLS.MakeClientTLSConf(); err != nil {
+			return err
+		}
+	}
+	clientPool := common.NewClientPool(tlsConf, nil)
 
 	serverAddress := fmt.Sprintf("%s:%d", config.Host, config.Port)
 
@@ -16,6 +16,7 @@ package health
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/streamnative/oxia/server/auth"
@@ -28,13 +29,14 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/streamnative/oxia/common/container"
+	"github.com/streamnative/oxia/common/security"
 )
 
 func TestHealthCmd(t *testing.T) {
 	_health := health.NewServer()
 	server, err := container.Default.StartGrpcServer("health", "localhost:0", func(registrar grpc.ServiceRegistrar) {
 		grpc_health_v1.RegisterHealthServer(registrar, _health)
-	}, nil, &auth.Options{})
+	}, nil, nil, &auth.Options{})
 	assert.NoError(t, err)
 	defer func() {
 		_ = server.Close()
@@ -68,3 +70,56 @@ func TestHealthCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthCmd_TLS(t *testing.T) {
+	certsDir := filepath.Join("..", "..", "tests", "security", "certs")
+	serverTLS := security.TLSOption{
+		CertFile:      filepath.Join(certsDir, "peer.crt"),
+		KeyFile:       filepath.Join(certsDir, "peer.key"),
+		TrustedCaFile: filepath.Join(certsDir, "ca.crt"),
+		ClientAuth:    true,
+	}
+	tlsConf, err := serverTLS.MakeServerTLSConf()
+	assert.NoError(t, err)
+
+	_health := health.NewServer()
+	server, err := container.Default.StartGrpcServer("health", "localhost:0", func(registrar grpc.ServiceRegistrar) {
+		grpc_health_v1.RegisterHealthServer(registrar, _health)
+	}, tlsConf, nil, &auth.Options{})
+	assert.NoError(t, err)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	portArg := fmt.Sprintf("--port=%d", server.Port())
+	caArg := "--tls-trusted-ca-file=" + filepath.Join(certsDir, "ca.crt")
+	certArgs := []string{
+		"--tls-cert-file=" + filepath.Join(certsDir, "client.crt"),
+		"--tls-key-file=" + filepath.Join(certsDir, "client.key"),
+	}
+
+	for _, test := range []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"plaintext", []string{"health", portArg}, true},
+		{"missing client certificate", []string{"health", portArg, caArg}, true},
+		{"mtls", append([]string{"health", portArg, caArg}, certArgs...), false},
+		{"wrong server name", append([]string{"health", portArg, caArg, "--tls-server-name=other"}, certArgs...), true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			config = NewConfig()
+			config.Host = "localhost"
+
+			Cmd.SetArgs(test.args)
+			err := Cmd.Execute()
+
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -22,13 +22,17 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/automaxprocs/maxprocs"
 
+	"github.com/streamnative/oxia/cmd/admin"
 	"github.com/streamnative/oxia/cmd/client"
 	"github.com/streamnative/oxia/cmd/coordinator"
+	"github.com/streamnative/oxia/cmd/db"
 	"github.com/streamnative/oxia/cmd/health"
 	"github.com/streamnative/oxia/cmd/pebble"
 	"github.com/streamnative/oxia/cmd/perf"
 	"github.com/streamnative/oxia/cmd/server"
+	"github.com/streamnative/oxia/cmd/shell"
 	"github.com/streamnative/oxia/cmd/standalone"
+	"github.com/streamnative/oxia/cmd/wal"
 	"github.com/streamnative/oxia/common"
 )
 
@@ -52,9 +56,18 @@ func (l LogLevelError) Error() string {
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&logLevelStr, "log-level", "l", common.DefaultLogLevel.String(), "Set logging level [debug|info|warn|error]")
 	rootCmd.PersistentFlags().BoolVarP(&common.LogJSON, "log-json", "j", false, "Print logs in JSON format")
+	rootCmd.PersistentFlags().StringVar((*string)(&common.LogRedaction.Mode), "log-redaction", string(common.LogRedactionNone),
+		"Redaction of the keys and the values in the logs above the debug level [none|drop|hash]")
+	rootCmd.PersistentFlags().StringSliceVar(&common.LogRedaction.KeyAttributes, "log-redaction-key-attributes",
+		common.DefaultRedactedKeyAttributes, "The attributes of the logs that hold the keys of the records")
+	rootCmd.PersistentFlags().StringSliceVar(&common.LogRedaction.ValueAttributes, "log-redaction-value-attributes",
+		common.DefaultRedactedValueAttributes, "The attributes of the logs that hold the values of the records")
+	rootCmd.PersistentFlags().StringVar(&common.LogRedaction.Salt, "log-redaction-salt", "",
+		"The salt of the hashes of the keys in the logs")
 	rootCmd.PersistentFlags().BoolVar(&common.PprofEnable, "profile", false, "Enable pprof profiler")
 	rootCmd.PersistentFlags().StringVar(&common.PprofBindAddress, "profile-bind-address", "127.0.0.1:6060", "Bind address for pprof")
 
+	rootCmd.AddCommand(admin.Cmd)
 	rootCmd.AddCommand(client.Cmd)
 	rootCmd.AddCommand(coordinator.Cmd)
 	rootCmd.AddCommand(health.Cmd)
@@ -62,6 +75,9 @@ func init() {
 	rootCmd.AddCommand(server.Cmd)
 	rootCmd.AddCommand(standalone.Cmd)
 	rootCmd.AddCommand(pebble.Cmd)
+	rootCmd.AddCommand(wal.Cmd)
+	rootCmd.AddCommand(db.Cmd)
+	rootCmd.AddCommand(shell.Cmd)
 }
 
 func configureLogLevel(_ *cobra.Command, _ []string) error {
@@ -70,6 +86,9 @@ func configureLogLevel(_ *cobra.Command, _ []string) error {
 		return LogLevelError(logLevelStr)
 	}
 	common.LogLevel = logLevel
+	if err := common.LogRedaction.Validate(); err != nil {
+		return err
+	}
 	common.ConfigureLogger()
 	return nil
 }
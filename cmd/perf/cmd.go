@@ -45,8 +45,15 @@ func init() {
 
 	Cmd.Flags().Float64VarP(&config.RequestRate, "rate", "r", 100.0, "Request rate, ops/s")
 	Cmd.Flags().Float64VarP(&config.ReadPercentage, "read-write-percent", "p", 80.0, "Percentage of read requests, compared to total requests")
-	Cmd.Flags().Uint32Var(&config.KeysCardinality, "keys-cardinality", 1000, "Batch linger in milliseconds")
+	Cmd.Flags().Uint32Var(&config.KeysCardinality, "keys-cardinality", 1000, "Number of distinct keys to read and write")
+	Cmd.Flags().StringVar((*string)(&config.KeyDistribution), "key-distribution", string(perf.KeyDistributionUniform),
+		fmt.Sprintf("Distribution of the keys to read and write: %s or %s", perf.KeyDistributionUniform, perf.KeyDistributionZipfian))
+	Cmd.Flags().Float64Var(&config.ZipfianExponent, "zipfian-exponent", 1.1, "Exponent of the zipfian key distribution, must be greater than 1")
 	Cmd.Flags().Uint32VarP(&config.ValueSize, "value-size", "s", 128, "Size of the values to write")
+	Cmd.Flags().Uint32Var(&config.ValueSizeMax, "value-size-max", 0, "When set, the size of the values to write is uniformly distributed between value-size and value-size-max")
+	Cmd.Flags().Uint32Var(&config.Namespaces, "namespaces", 1, "Number of namespaces to spread the traffic across. With more than one, the namespaces are named <namespace>-<i>")
+	Cmd.Flags().DurationVar(&config.WarmUp, "warm-up", 0, "Duration of the warm-up phase, during which the latencies are not recorded")
+	Cmd.Flags().StringVar(&config.HistogramOutputDir, "histogram-output-dir", "", "Directory where to write the HdrHistogram latency distributions of the run when it completes")
 
 	Cmd.Flags().DurationVar(&config.BatchLinger, "batch-linger", oxia.DefaultBatchLinger, "Batch linger time")
 	Cmd.Flags().IntVar(&config.MaxRequestsPerBatch, "max-requests-per-batch", oxia.DefaultMaxRequestsPerBatch, "Maximum requests per batch")
@@ -60,21 +67,31 @@ func exec(*cobra.Command, []string) {
 type closer struct {
 	ctx    context.Context
 	cancel context.CancelFunc
+	done   chan struct{}
 }
 
 func newCloser(ctx context.Context) *closer {
-	c := &closer{}
+	c := &closer{done: make(chan struct{})}
 	c.ctx, c.cancel = context.WithCancel(ctx)
 	return c
 }
 
 func (c *closer) Close() error {
 	c.cancel()
+	// Wait for the run to report its summary
+	<-c.done
 	return nil
 }
 
 func runPerf() (io.Closer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	closer := newCloser(context.Background())
-	go perf.New(config).Run(closer.ctx)
+	go func() {
+		defer close(closer.done)
+		perf.New(config).Run(closer.ctx)
+	}()
 	return closer, nil
 }
@@ -18,12 +18,14 @@ import (
 	"io"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/streamnative/oxia/cmd/flag"
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/server"
+	"github.com/streamnative/oxia/server/encryption"
 	"github.com/streamnative/oxia/server/kv"
 )
 
@@ -34,6 +36,10 @@ var (
 	serverTLS         = security.TLSOption{}
 	internalServerTLS = security.TLSOption{}
 
+	publicIPFilter   = security.IPFilterOption{}
+	internalIPFilter = security.IPFilterOption{}
+	metricsIPFilter  = security.IPFilterOption{}
+
 	Cmd = &cobra.Command{
 		Use:   "server",
 		Short: "Start a server",
@@ -48,14 +54,30 @@ func init() {
 	flag.PublicAddr(Cmd, &conf.PublicServiceAddr)
 	flag.InternalAddr(Cmd, &conf.InternalServiceAddr)
 	flag.MetricsAddr(Cmd, &conf.MetricsServiceAddr)
+	flag.IPFilter(Cmd, "public", &publicIPFilter)
+	flag.IPFilter(Cmd, "internal", &internalIPFilter)
+	flag.IPFilter(Cmd, "metrics", &metricsIPFilter)
 	Cmd.Flags().StringVar(&conf.DataDir, "data-dir", "./data/db", "Directory where to store data")
 	Cmd.Flags().StringVar(&conf.WalDir, "wal-dir", "./data/wal", "Directory for write-ahead-logs")
 	Cmd.Flags().DurationVar(&conf.WalRetentionTime, "wal-retention-time", 1*time.Hour, "Retention time for the entries in the write-ahead-log")
 	Cmd.Flags().BoolVar(&conf.WalSyncData, "wal-sync-data", true, "Whether to sync data in write-ahead-log")
+	Cmd.Flags().DurationVar(&conf.FollowerAckTimeout, "follower-ack-timeout", 0, "Time after which a follower that is not acknowledging entries is reported as unhealthy to the coordinator. 0 disables the check")
+	Cmd.Flags().DurationVar(&conf.ReplicationAckInterval, "replication-ack-interval", 0, "Max time a follower can delay the acknowledgment of the replicated entries, to coalesce multiple acks into one. 0 acks after each wal sync")
+	Cmd.Flags().Int64Var(&conf.ReplicationAckMaxEntries, "replication-ack-max-entries", 0, "Max number of replicated entries a follower can leave unacknowledged while coalescing acks. 0 means no limit")
+	Cmd.Flags().DurationVar(&conf.NotificationsSubscriberTimeout, "notifications-subscriber-timeout", 30*time.Second, "Max time a notifications subscriber can stall before being disconnected. 0 disables the eviction")
 	Cmd.Flags().Int64Var(&conf.DbBlockCacheMB, "db-cache-size-mb", kv.DefaultFactoryOptions.CacheSizeMB,
 		"Max size of the shared DB cache")
-	Cmd.Flags().StringVar(&conf.AuthOptions.ProviderName, "auth-provider-name", "", "Authentication provider name. supported: oidc")
-	Cmd.Flags().StringVar(&conf.AuthOptions.ProviderParams, "auth-provider-params", "", "Authentication provider params. \n oidc: "+"{\"allowedIssueURLs\":\"required1,required2\",\"allowedAudiences\":\"required1,required2\",\"userNameClaim\":\"optional(default:sub)\"}")
+	Cmd.Flags().StringVar(&conf.AuditLogPath, "audit-log-path", "", "File where the writes are recorded, for auditing. Empty disables the audit log")
+	Cmd.Flags().StringVar(&conf.AuthOptions.ProviderName, "auth-provider-name", "", "Authentication provider name. supported: oidc, token-file, mtls")
+	Cmd.Flags().StringVar(&conf.AuthOptions.ProviderParams, "auth-provider-params", "", "Authentication provider params. \n oidc: "+"{\"allowedIssueURLs\":\"required1,required2\",\"allowedAudiences\":\"required1,required2\",\"userNameClaim\":\"optional(default:sub)\",\"jwksURL\":\"optional\"}"+
+		"\n token-file: {\"path\":\"required\"}"+
+		"\n mtls: {\"principalField\":\"optional(commonName|dnsName|uri|email|spiffeId, default:commonName)\", \"trustDomains\":\"required with spiffeId, comma separated\"}")
+	Cmd.Flags().StringVar(&conf.EncryptionOptions.ProviderName, "encryption-provider-name", "", "Encryption at rest key provider name. supported: keyfile, vault, aws-kms, gcp-kms")
+	Cmd.Flags().StringVar(&conf.EncryptionOptions.ProviderParams, "encryption-provider-params", "", "Encryption at rest key provider params. \n keyfile: "+"{\"path\":\"required\"}"+
+		"\n vault: {\"address\":\"optional(default:$VAULT_ADDR)\",\"token\":\"optional(default:$VAULT_TOKEN)\",\"namespace\":\"optional\",\"mountPath\":\"optional(default:transit)\",\"keyName\":\"required\"}"+
+		"\n aws-kms: {\"region\":\"optional(default:$AWS_REGION)\",\"keyId\":\"required\",\"endpoint\":\"optional\"}"+
+		"\n gcp-kms: {\"keyName\":\"required\",\"endpoint\":\"optional\",\"accessToken\":\"optional\"}")
+	Cmd.Flags().DurationVar(&conf.EncryptionKeyRotationInterval, "encryption-key-rotation-interval", encryption.DefaultRotationInterval, "Max age of the data keys encrypting the wal and the database")
 
 	// server TLS section
 	Cmd.Flags().StringVar(&serverTLS.CertFile, "tls-cert-file", "", "Tls certificate file")
@@ -90,12 +112,19 @@ func exec(*cobra.Command, []string) {
 		if err := configureTLS(); err != nil {
 			return nil, err
 		}
+		if err := configureIPFilters(); err != nil {
+			return nil, err
+		}
 		return server.New(conf)
 	})
 }
 
 func configureTLS() error {
 	var err error
+	if internalServerTLS.ClientAuth && peerTLS.CertFile == "" {
+		// The replication between the servers would be rejected by the peers
+		return errors.New("peer tls must be configured when the internal server requires client auth")
+	}
 	if serverTLS.IsConfigured() {
 		if conf.ServerTLS, err = serverTLS.MakeServerTLSConf(); err != nil {
 			return err
@@ -113,3 +142,15 @@ func configureTLS() error {
 	}
 	return nil
 }
+
+func configureIPFilters() error {
+	var err error
+	if conf.PublicIPFilter, err = publicIPFilter.MakeIPFilter(); err != nil {
+		return err
+	}
+	if conf.InternalIPFilter, err = internalIPFilter.MakeIPFilter(); err != nil {
+		return err
+	}
+	conf.MetricsIPFilter, err = metricsIPFilter.MakeIPFilter()
+	return err
+}
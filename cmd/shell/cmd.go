@@ -0,0 +1,233 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"golang.org/x/term"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	historyFile string
+}
+
+func (flags *flags) Reset() {
+	flags.historyFile = defaultHistoryFile()
+}
+
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".oxia_history")
+}
+
+func init() {
+	common.AddFlags(Cmd.Flags())
+	Cmd.Flags().StringVar(&Config.historyFile, "history-file", defaultHistoryFile(), "The file where the history of the commands is kept. Empty to disable the history")
+}
+
+var Cmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Interactive shell",
+	Long: `Open an interactive shell to get, put, delete, list and watch the records of a namespace. The flags ` +
+		`of the commands are completed with the tab key, and the history of the commands is kept across the ` +
+		`sessions. When the input is not a terminal, the commands are read from it, one per line.`,
+	Args:         cobra.NoArgs,
+	RunE:         exec,
+	SilenceUsage: true,
+}
+
+func exec(cmd *cobra.Command, _ []string) (err error) {
+	s := newShell(cmd.OutOrStdout(), common.Config.Namespace)
+	defer func() {
+		err = multierr.Append(err, s.Close())
+	}()
+
+	if in, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(in.Fd())) {
+		return s.runTerminal(in, cmd.OutOrStdout(), Config.historyFile)
+	}
+	return s.run(cmd.InOrStdin())
+}
+
+// run executes the commands read from the input, without prompt nor history.
+func (s *Shell) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for !s.exit && scanner.Scan() {
+		if err := s.Execute(scanner.Text()); err != nil {
+			_, _ = fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// runTerminal executes the commands typed in the terminal, with line editing,
+// completion and history.
+func (s *Shell) runTerminal(in *os.File, out io.Writer, historyFile string) error {
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+
+	keys := make(chan []byte)
+	go readKeys(in, keys)
+
+	rw := &terminalIO{Writer: out, keys: keys}
+	t := term.NewTerminal(rw, "")
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		_ = t.SetSize(width, height)
+	}
+
+	history, err := readHistory(historyFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(t, "Failed to read the history: %v\n", err)
+	}
+	rw.replayHistory(t, history)
+
+	t.AutoCompleteCallback = s.autoComplete(t)
+	s.out = t
+	s.interrupt = keys
+
+	for !s.exit {
+		t.SetPrompt(s.prompt())
+		line, err := t.ReadLine()
+		if err == io.EOF {
+			return nil
+		} else if err != nil && err != term.ErrPasteIndicator {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := appendHistory(historyFile, line); err != nil {
+			_, _ = fmt.Fprintf(t, "Failed to write the history: %v\n", err)
+		}
+		if err := s.Execute(line); err != nil {
+			_, _ = fmt.Fprintf(t, "Error: %v\n", err)
+		}
+	}
+	return nil
+}
+
+func (s *Shell) autoComplete(out io.Writer) func(line string, pos int, key rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		word, candidates := s.Complete(line, pos)
+		if len(candidates) == 0 {
+			return "", 0, false
+		}
+
+		completion := commonPrefix(candidates)
+		if len(candidates) == 1 {
+			completion += " "
+		} else if completion == word {
+			_, _ = fmt.Fprintln(out, strings.Join(candidates, "  "))
+			return "", 0, false
+		}
+		start := pos - len(word)
+		return line[:start] + completion + line[pos:], start + len(completion), true
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// readKeys reads the input of the terminal in the background, so that the
+// keys can either be read by the terminal or interrupt a running command.
+func readKeys(in io.Reader, keys chan<- []byte) {
+	defer close(keys)
+	for {
+		buf := make([]byte, 256)
+		n, err := in.Read(buf)
+		if n > 0 {
+			keys <- buf[:n]
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+type terminalIO struct {
+	io.Writer
+	keys    <-chan []byte
+	pending []byte
+	replay  io.Reader
+}
+
+func (t *terminalIO) Read(p []byte) (int, error) {
+	if t.replay != nil {
+		return t.replay.Read(p)
+	}
+	if len(t.pending) == 0 {
+		keys, ok := <-t.keys
+		if !ok {
+			return 0, io.EOF
+		}
+		t.pending = keys
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+// replayHistory loads the history in the terminal, which has no method to do
+// it, by typing the lines of the history without echoing them.
+func (t *terminalIO) replayHistory(terminal *term.Terminal, history []string) {
+	if len(history) == 0 {
+		return
+	}
+
+	out := t.Writer
+	t.Writer = io.Discard
+	t.replay = strings.NewReader(strings.Join(history, "\r") + "\r")
+	for range history {
+		if _, err := terminal.ReadLine(); err != nil {
+			break
+		}
+	}
+	t.Writer = out
+	t.replay = nil
+}
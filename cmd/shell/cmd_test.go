@@ -0,0 +1,224 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/term"
+
+	"github.com/streamnative/oxia/oxia"
+	"github.com/streamnative/oxia/server"
+)
+
+func runCmd(cmd *cobra.Command, args string, stdin string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetIn(bytes.NewBufferString(stdin))
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestShell(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+	defer standaloneServer.Close()
+
+	script := `put /users/a value-a
+put /users/b "value b"
+put /users/a/settings x
+get /users/a
+get --hex /users/b
+list /users/
+list -s /users/b
+get /users/c
+delete /users/a -e 5
+delete /users/a
+list /users/
+put k "unterminated
+use
+use other
+get /users/b
+exit
+get /users/b`
+
+	out, err := runCmd(Cmd, fmt.Sprintf("-a localhost:%d", standaloneServer.RpcPort()), script)
+	assert.NoError(t, err)
+	lines := strings.Split(out, "\n")
+	assert.Len(t, lines, 15)
+
+	assert.Contains(t, lines[0], `"key":"/users/a"`)
+	assert.Equal(t, "value-a", lines[3])
+	assert.Contains(t, lines[4], "76 61 6c 75 65 20 62")
+	assert.Equal(t, []string{"/users/a", "/users/b"}, lines[5:7])
+	// The deeper keys sort after the keys of the level
+	assert.Equal(t, []string{"/users/b", "/users/a/settings"}, lines[7:9])
+	assert.Equal(t, "Error: key not found", lines[9])
+	assert.Equal(t, "Error: unexpected version id", lines[10])
+	assert.Equal(t, "/users/b", lines[11])
+	assert.Equal(t, "Error: unterminated quote or escape", lines[12])
+	assert.Equal(t, "default", lines[13])
+}
+
+func TestShell_Watch(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+	defer standaloneServer.Close()
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+
+	client, err := oxia.NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	// Keep writing while the shell is watching
+	done := make(chan any)
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(50 * time.Millisecond):
+				_, _, _ = client.Put(context.Background(), "/a", []byte("x"))
+				_, _, _ = client.Put(context.Background(), "/b", []byte("y"))
+			}
+		}
+	}()
+
+	out, err := runCmd(Cmd, "-a "+serviceAddress, "watch -k /b --include-value --count 1\nwatch --duration 10ms -k /c")
+	assert.NoError(t, err)
+	lines := strings.Split(out, "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"key":"/b"`)
+	assert.Contains(t, lines[0], `"value":"y"`)
+}
+
+func TestSplitArgs(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		expected []string
+	}{
+		{"", nil},
+		{"  get   a ", []string{"get", "a"}},
+		{`put a "b c"`, []string{"put", "a", "b c"}},
+		{`put a 'b "c"'`, []string{"put", "a", `b "c"`}},
+		{`put a b\ c\\`, []string{"put", "a", `b c\`}},
+		{`put a ''`, []string{"put", "a", ""}},
+		{`put 'a\b'`, []string{"put", `a\b`}},
+	} {
+		args, err := splitArgs(test.line)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, args, test.line)
+	}
+
+	_, err := splitArgs(`put a "b`)
+	assert.ErrorIs(t, err, errUnterminatedArg)
+	_, err = splitArgs(`put a b\`)
+	assert.ErrorIs(t, err, errUnterminatedArg)
+}
+
+func TestShell_Complete(t *testing.T) {
+	s := newShell(io.Discard, "default")
+
+	word, candidates := s.Complete("", 0)
+	assert.Equal(t, "", word)
+	assert.Contains(t, candidates, "get")
+	assert.Contains(t, candidates, "help")
+
+	word, candidates = s.Complete("de", 2)
+	assert.Equal(t, "de", word)
+	assert.Equal(t, []string{"delete"}, candidates)
+
+	word, candidates = s.Complete("get --h /a", 7)
+	assert.Equal(t, "--h", word)
+	assert.Equal(t, []string{"--help", "--hex"}, candidates)
+
+	_, candidates = s.Complete("watch --", 8)
+	assert.Equal(t, []string{"--count", "--duration", "--help", "--include-value", "--key-prefix"}, candidates)
+
+	_, candidates = s.Complete("get /a", 6)
+	assert.Empty(t, candidates)
+	_, candidates = s.Complete("unknown --", 10)
+	assert.Empty(t, candidates)
+
+	complete := s.autoComplete(io.Discard)
+	line, pos, ok := complete("watch --inc", 11, '\t')
+	assert.True(t, ok)
+	assert.Equal(t, "watch --include-value ", line)
+	assert.Equal(t, 22, pos)
+
+	_, _, ok = complete("get --he", 8, '\t')
+	assert.False(t, ok)
+	_, _, ok = complete("get", 3, 'a')
+	assert.False(t, ok)
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	history, err := readHistory(path)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	for i := 0; i < historySize+10; i++ {
+		assert.NoError(t, appendHistory(path, fmt.Sprintf("get /%d", i)))
+	}
+
+	history, err = readHistory(path)
+	assert.NoError(t, err)
+	assert.Len(t, history, historySize)
+	assert.Equal(t, "get /10", history[0])
+
+	// The file was truncated
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, historySize, strings.Count(string(content), "\n"))
+
+	history, err = readHistory("")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestTerminal_ReplayHistory(t *testing.T) {
+	keys := make(chan []byte, 1)
+	out := new(bytes.Buffer)
+	rw := &terminalIO{Writer: out, keys: keys}
+	terminal := term.NewTerminal(rw, "> ")
+
+	rw.replayHistory(terminal, []string{"get /a", "get /b"})
+	assert.Empty(t, out.String())
+
+	// Up arrow twice, then enter
+	keys <- []byte("\x1b[A\x1b[A\r")
+	line, err := terminal.ReadLine()
+	assert.NoError(t, err)
+	assert.Equal(t, "get /a", line)
+
+	close(keys)
+	_, err = terminal.ReadLine()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,453 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/cmd/client/common"
+	"github.com/streamnative/oxia/oxia"
+)
+
+var errUnterminatedArg = errors.New("unterminated quote or escape")
+
+// Shell runs the commands of the lines typed by the user, with a client
+// connected to the current namespace.
+type Shell struct {
+	out       io.Writer
+	namespace string
+	client    oxia.SyncClient
+	exit      bool
+
+	// interrupt receives the keys pressed by the user while a command is
+	// running. It's nil when the input is not a terminal.
+	interrupt <-chan []byte
+}
+
+func newShell(out io.Writer, namespace string) *Shell {
+	return &Shell{
+		out:       out,
+		namespace: namespace,
+	}
+}
+
+func (s *Shell) prompt() string {
+	return fmt.Sprintf("oxia:%s> ", s.namespace)
+}
+
+func (s *Shell) getClient() (oxia.SyncClient, error) {
+	if s.client == nil {
+		config := common.Config
+		config.Namespace = s.namespace
+		client, err := config.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		s.client = client
+	}
+	return s.client, nil
+}
+
+func (s *Shell) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+	return err
+}
+
+// Execute runs the command of a line. The errors of the command are returned
+// to be printed, they do not stop the shell.
+func (s *Shell) Execute(line string) error {
+	args, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	root := s.newRootCmd()
+	root.SetArgs(args)
+	root.SetOut(s.out)
+	root.SetErr(s.out)
+	return root.Execute()
+}
+
+// newRootCmd returns a new tree of the commands of the shell for each line,
+// so that no flag value is kept from one line to the next.
+func (s *Shell) newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "oxia",
+		Short:         "Oxia shell",
+		Long:          `Interactive shell to read and write the records of a namespace. Type "help COMMAND" for the usage of a command.`,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+
+	root.AddCommand(
+		s.newGetCmd(),
+		s.newPutCmd(),
+		s.newDeleteCmd(),
+		s.newListCmd(),
+		s.newWatchCmd(),
+		s.newUseCmd(),
+		&cobra.Command{
+			Use:     "exit",
+			Short:   "Exit the shell",
+			Aliases: []string{"quit"},
+			Args:    cobra.NoArgs,
+			Run: func(*cobra.Command, []string) {
+				s.exit = true
+			},
+		},
+	)
+	return root
+}
+
+func (s *Shell) newGetCmd() *cobra.Command {
+	var includeVersion, hexDump bool
+	var partitionKey string
+	cmd := &cobra.Command{
+		Use:   "get [flags] KEY",
+		Short: "Get the value of a record",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := s.getClient()
+			if err != nil {
+				return err
+			}
+			var options []oxia.GetOption
+			if partitionKey != "" {
+				options = append(options, oxia.PartitionKey(partitionKey))
+			}
+			key, value, version, err := client.Get(context.Background(), args[0], options...)
+			if err != nil {
+				return err
+			}
+
+			if hexDump {
+				common.WriteHexDump(cmd.OutOrStdout(), value)
+			} else {
+				common.WriteOutput(cmd.OutOrStdout(), value)
+			}
+			if includeVersion {
+				common.WriteOutput(cmd.OutOrStdout(), newOutputVersion(key, version))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&includeVersion, "include-version", "v", false, "Include the record version object")
+	cmd.Flags().BoolVar(&hexDump, "hex", false, "Print the value in HexDump format")
+	cmd.Flags().StringVarP(&partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	return cmd
+}
+
+func (s *Shell) newPutCmd() *cobra.Command {
+	var expectedVersion int64
+	var partitionKey string
+	cmd := &cobra.Command{
+		Use:   "put [flags] KEY VALUE",
+		Short: "Put the value of a record",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := s.getClient()
+			if err != nil {
+				return err
+			}
+			var options []oxia.PutOption
+			if expectedVersion != -1 {
+				options = append(options, oxia.ExpectedVersionId(expectedVersion))
+			}
+			if partitionKey != "" {
+				options = append(options, oxia.PartitionKey(partitionKey))
+			}
+			key, version, err := client.Put(context.Background(), args[0], []byte(args[1]), options...)
+			if err != nil {
+				return err
+			}
+			common.WriteOutput(cmd.OutOrStdout(), newOutputVersion(key, version))
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&expectedVersion, "expected-version", "e", -1, "Version of entry expected to be on the server")
+	cmd.Flags().StringVarP(&partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	return cmd
+}
+
+func (s *Shell) newDeleteCmd() *cobra.Command {
+	var expectedVersion int64
+	var partitionKey string
+	cmd := &cobra.Command{
+		Use:   "delete [flags] KEY",
+		Short: "Delete a record",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, err := s.getClient()
+			if err != nil {
+				return err
+			}
+			var options []oxia.DeleteOption
+			if expectedVersion != -1 {
+				options = append(options, oxia.ExpectedVersionId(expectedVersion))
+			}
+			if partitionKey != "" {
+				options = append(options, oxia.PartitionKey(partitionKey))
+			}
+			return client.Delete(context.Background(), args[0], options...)
+		},
+	}
+	cmd.Flags().Int64VarP(&expectedVersion, "expected-version", "e", -1, "Version of entry expected to be on the server")
+	cmd.Flags().StringVarP(&partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	return cmd
+}
+
+func (s *Shell) newListCmd() *cobra.Command {
+	var keyMin, keyMax, partitionKey string
+	cmd := &cobra.Command{
+		Use:   "list [flags] [PREFIX]",
+		Short: "List the keys starting with a prefix, or in a key range",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if keyMin != "" || keyMax != "" {
+					return errors.New("the prefix can not be used with a key range")
+				}
+				keyMin, keyMax = common.PrefixKeyRange(args[0])
+			} else if keyMax == "" {
+				// By default, do not list internal keys
+				keyMax = "__oxia/"
+			}
+
+			client, err := s.getClient()
+			if err != nil {
+				return err
+			}
+			var options []oxia.ListOption
+			if partitionKey != "" {
+				options = append(options, oxia.PartitionKey(partitionKey))
+			}
+			keys, err := client.List(context.Background(), keyMin, keyMax, options...)
+			if err != nil {
+				return err
+			}
+			common.WriteOutput(cmd.OutOrStdout(), keys)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&keyMin, "key-min", "s", "", "Key range minimum (inclusive)")
+	cmd.Flags().StringVarP(&keyMax, "key-max", "e", "", "Key range maximum (exclusive)")
+	cmd.Flags().StringVarP(&partitionKey, "partition-key", "p", "", "Partition Key to be used in override the shard routing")
+	return cmd
+}
+
+type notificationOutput struct {
+	Type      string  `json:"type"`
+	Key       string  `json:"key"`
+	VersionId int64   `json:"versionId"`
+	Value     *string `json:"value,omitempty"`
+}
+
+func (s *Shell) newWatchCmd() *cobra.Command {
+	var keyPrefixes []string
+	var includeValue bool
+	var count int
+	var duration time.Duration
+	cmd := &cobra.Command{
+		Use:   "watch [flags]",
+		Short: "Print the notifications of the changes of the records, until a key is pressed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) (err error) {
+			client, err := s.getClient()
+			if err != nil {
+				return err
+			}
+			var options []oxia.NotificationsOption
+			if len(keyPrefixes) > 0 {
+				options = append(options, oxia.KeyPrefixes(keyPrefixes...))
+			}
+			if includeValue {
+				options = append(options, oxia.IncludeValue())
+			}
+			notifications, err := client.GetNotifications(options...)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err = multierr.Append(err, notifications.Close())
+			}()
+
+			var timeout <-chan time.Time
+			if duration > 0 {
+				timer := time.NewTimer(duration)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+
+			for received := 0; count == 0 || received < count; received++ {
+				select {
+				case n, ok := <-notifications.Ch():
+					if !ok {
+						return nil
+					}
+					o := notificationOutput{Type: n.Type.String(), Key: n.Key, VersionId: n.VersionId}
+					if n.Value != nil {
+						value := string(n.Value)
+						o.Value = &value
+					}
+					common.WriteOutput(cmd.OutOrStdout(), o)
+				case <-s.interrupt:
+					return nil
+				case <-timeout:
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&keyPrefixes, "key-prefix", "k", nil, "Only follow the notifications for the keys starting with the prefix. Can be repeated")
+	cmd.Flags().BoolVar(&includeValue, "include-value", false, "Include the value of the records in the created and modified notifications")
+	cmd.Flags().IntVar(&count, "count", 0, "Stop after a number of notifications")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Stop after a duration")
+	return cmd
+}
+
+func (s *Shell) newUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [NAMESPACE]",
+		Short: "Switch to another namespace, or print the current one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), s.namespace)
+				return nil
+			}
+			if args[0] == s.namespace {
+				return nil
+			}
+			err := s.Close()
+			s.namespace = args[0]
+			return err
+		},
+	}
+}
+
+func newOutputVersion(key string, version oxia.Version) common.OutputVersion {
+	return common.OutputVersion{
+		Key:                key,
+		VersionId:          version.VersionId,
+		CreatedTimestamp:   time.UnixMilli(int64(version.CreatedTimestamp)),
+		ModifiedTimestamp:  time.UnixMilli(int64(version.ModifiedTimestamp)),
+		ModificationsCount: version.ModificationsCount,
+		Ephemeral:          version.Ephemeral,
+		ClientIdentity:     version.ClientIdentity,
+	}
+}
+
+// Complete returns the candidates to complete the word before the position
+// in the line: the names of the commands for the first word, and the names
+// of the flags of the command for the words starting with a dash.
+func (s *Shell) Complete(line string, pos int) (word string, candidates []string) {
+	before := line[:pos]
+	start := strings.LastIndexAny(before, " \t") + 1
+	word = before[start:]
+
+	fields := strings.Fields(before[:start])
+	root := s.newRootCmd()
+	if len(fields) == 0 {
+		for _, c := range root.Commands() {
+			candidates = append(candidates, c.Name())
+		}
+		candidates = append(candidates, "help")
+	} else if strings.HasPrefix(word, "-") {
+		cmd, _, err := root.Find(fields[:1])
+		if err != nil || cmd == root {
+			return word, nil
+		}
+		cmd.InitDefaultHelpFlag()
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			candidates = append(candidates, "--"+f.Name)
+		})
+	}
+
+	res := candidates[:0]
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			res = append(res, c)
+		}
+	}
+	sort.Strings(res)
+	return word, res
+}
+
+// splitArgs splits a line in words separated by spaces. The words can be
+// quoted with single or double quotes, to contain spaces, and a backslash
+// escapes the next character outside of single quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errUnterminatedArg
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
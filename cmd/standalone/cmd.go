@@ -48,6 +48,7 @@ func init() {
 	Cmd.Flags().DurationVar(&conf.NotificationsRetentionTime, "notifications-retention-time", 1*time.Hour, "Retention time for the db notifications to clients")
 	Cmd.Flags().Int64Var(&conf.DbBlockCacheMB, "db-cache-size-mb", kv.DefaultFactoryOptions.CacheSizeMB,
 		"Max size of the shared DB cache")
+	Cmd.Flags().StringVar(&conf.AuditLogPath, "audit-log-path", "", "File where the writes are recorded, for auditing. Empty disables the audit log")
 }
 
 func exec(*cobra.Command, []string) {
@@ -0,0 +1,165 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/streamnative/oxia/oxia"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server"
+	"github.com/streamnative/oxia/server/encryption"
+	"github.com/streamnative/oxia/server/wal"
+)
+
+var (
+	Config = flags{}
+)
+
+type flags struct {
+	dir               string
+	namespace         string
+	shard             int64
+	entries           bool
+	decode            bool
+	firstOffset       int64
+	lastOffset        int64
+	encryptionOptions encryption.Options
+}
+
+func (flags *flags) Reset() {
+	flags.dir = "./data/wal"
+	flags.namespace = oxia.DefaultNamespace
+	flags.shard = 0
+	flags.entries = false
+	flags.decode = false
+	flags.firstOffset = 0
+	flags.lastOffset = math.MaxInt64
+	flags.encryptionOptions = encryption.Options{}
+}
+
+func init() {
+	inspectCmd.Flags().StringVarP(&Config.dir, "dir", "d", "./data/wal", "The wal directory of the server")
+	inspectCmd.Flags().StringVarP(&Config.namespace, "namespace", "n", oxia.DefaultNamespace, "The namespace of the shard")
+	inspectCmd.Flags().Int64VarP(&Config.shard, "shard", "s", 0, "The shard to inspect")
+	inspectCmd.Flags().BoolVarP(&Config.entries, "entries", "e", false, "Print the header of each entry: offset, term, timestamp and size")
+	inspectCmd.Flags().BoolVar(&Config.decode, "decode", false, "Print the decoded value of each entry, implies --entries")
+	inspectCmd.Flags().Int64Var(&Config.firstOffset, "first-offset", 0, "The first offset of the entries to print")
+	inspectCmd.Flags().Int64Var(&Config.lastOffset, "last-offset", math.MaxInt64, "The last offset of the entries to print")
+	inspectCmd.Flags().StringVar(&Config.encryptionOptions.ProviderName, "encryption-provider-name", "",
+		"The key provider of the server, when the wal is encrypted. supported: keyfile, vault, aws-kms, gcp-kms")
+	inspectCmd.Flags().StringVar(&Config.encryptionOptions.ProviderParams, "encryption-provider-params", "",
+		"The params of the key provider, as given to the server")
+	if err := inspectCmd.MarkFlagRequired("shard"); err != nil {
+		panic(err)
+	}
+
+	Cmd.AddCommand(inspectCmd)
+}
+
+var Cmd = &cobra.Command{
+	Use:   "wal",
+	Short: "Wal utils",
+	Long:  `Tools to inspect the wal of a server offline`,
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Inspect the wal of a shard",
+	Long: `Print the stats of the segments of the wal of a shard and, optionally, its entries, one json object per line. ` +
+		`The files are only read, but the server should be stopped to get a consistent view.`,
+	Args:         cobra.NoArgs,
+	RunE:         execInspect,
+	SilenceUsage: true,
+}
+
+type segmentOutput struct {
+	Segment wal.SegmentStats `json:"segment"`
+}
+
+type entryOutput struct {
+	wal.EntryHeader
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func execInspect(cmd *cobra.Command, _ []string) error {
+	provider, keyring, err := openKeyring()
+	if err != nil {
+		return err
+	}
+	if provider != nil {
+		defer provider.Close()
+	}
+
+	inspector, err := wal.NewInspector(Config.dir, Config.namespace, Config.shard, keyring)
+	if err != nil {
+		return err
+	}
+
+	segments, err := inspector.Segments()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	for _, s := range segments {
+		if err = encoder.Encode(segmentOutput{Segment: s}); err != nil {
+			return err
+		}
+	}
+
+	if !Config.entries && !Config.decode {
+		return nil
+	}
+
+	return inspector.ReadEntries(Config.firstOffset, Config.lastOffset, func(header wal.EntryHeader, entry *proto.LogEntry) error {
+		output := entryOutput{EntryHeader: header}
+		if Config.decode {
+			value := &proto.LogEntryValue{}
+			if err := value.UnmarshalVT(entry.Value); err != nil {
+				return err
+			}
+			if output.Value, err = protojson.Marshal(value); err != nil {
+				return err
+			}
+		}
+		return encoder.Encode(output)
+	})
+}
+
+func openKeyring() (encryption.KeyProvider, *encryption.Keyring, error) {
+	if !Config.encryptionOptions.IsEnabled() {
+		return nil, nil, nil
+	}
+
+	ctx := context.Background()
+	provider, err := encryption.NewKeyProvider(ctx, Config.encryptionOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyring, err := encryption.OpenFileKeyringReadOnly(ctx, provider, filepath.Join(Config.dir, server.WalEncryptionKeysFile))
+	if err != nil {
+		return nil, nil, multierr.Append(err, provider.Close())
+	}
+	return provider, keyring, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/wal"
+)
+
+func runCmd(cmd *cobra.Command, args string) (string, error) {
+	actual := new(bytes.Buffer)
+	cmd.SetOut(actual)
+	cmd.SetErr(actual)
+	cmd.SetArgs(strings.Split(args, " "))
+	err := cmd.Execute()
+	Config.Reset()
+	return strings.TrimSpace(actual.String()), err
+}
+
+func TestWal_Inspect(t *testing.T) {
+	dir := t.TempDir()
+	f := wal.NewWalFactory(&wal.FactoryOptions{
+		BaseWalDir:  dir,
+		Retention:   1 * time.Hour,
+		SegmentSize: 128 * 1024,
+	})
+	w, err := f.NewWal("ns-1", 2, nil)
+	assert.NoError(t, err)
+
+	for i := int64(0); i < 3; i++ {
+		value, err := (&proto.LogEntryValue{
+			Value: &proto.LogEntryValue_Requests{
+				Requests: &proto.WriteRequests{
+					Writes: []*proto.WriteRequest{{
+						Puts: []*proto.PutRequest{{Key: fmt.Sprintf("key-%d", i), Value: []byte("value")}},
+					}},
+				},
+			},
+		}).MarshalVT()
+		assert.NoError(t, err)
+		assert.NoError(t, w.Append(&proto.LogEntry{Term: 1, Offset: i, Value: value, Timestamp: 5}))
+	}
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	out, err := runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 -s 2", dir))
+	assert.NoError(t, err)
+	lines := strings.Split(out, "\n")
+	assert.Len(t, lines, 1)
+	segment := segmentOutput{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &segment))
+	assert.EqualValues(t, 0, segment.Segment.BaseOffset)
+	assert.EqualValues(t, 2, segment.Segment.LastOffset)
+	assert.EqualValues(t, 3, segment.Segment.Entries)
+
+	out, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 -s 2 --entries --first-offset 1", dir))
+	assert.NoError(t, err)
+	lines = strings.Split(out, "\n")
+	assert.Len(t, lines, 3)
+	assert.Regexp(t, `^\{"offset":1,"term":1,"timestamp":5,"size":\d+\}$`, lines[1])
+	assert.Regexp(t, `^\{"offset":2,"term":1,"timestamp":5,"size":\d+\}$`, lines[2])
+
+	out, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 -s 2 --decode --first-offset 2 --last-offset 2", dir))
+	assert.NoError(t, err)
+	lines = strings.Split(out, "\n")
+	assert.Len(t, lines, 2)
+	entry := entryOutput{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.EqualValues(t, 2, entry.Offset)
+	assert.Contains(t, string(entry.Value), "key-2")
+
+	_, err = runCmd(Cmd, fmt.Sprintf("inspect -d %s -n ns-1 -s 3", dir))
+	assert.Error(t, err)
+}
@@ -22,9 +22,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/oxia/auth"
 
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -44,6 +44,7 @@ type ClientPool interface {
 	GetHealthRpc(target string) (grpc_health_v1.HealthClient, error)
 	GetCoordinationRpc(target string) (proto.OxiaCoordinationClient, error)
 	GetReplicationRpc(target string) (proto.OxiaLogReplicationClient, error)
+	GetAdminRpc(target string) (proto.OxiaAdminClient, error)
 }
 
 type clientPool struct {
@@ -119,6 +120,15 @@ func (cp *clientPool) GetReplicationRpc(target string) (proto.OxiaLogReplication
 	return proto.NewOxiaLogReplicationClient(cnx), nil
 }
 
+func (cp *clientPool) GetAdminRpc(target string) (proto.OxiaAdminClient, error) {
+	cnx, err := cp.getConnection(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.NewOxiaAdminClient(cnx), nil
+}
+
 func (cp *clientPool) getConnection(target string) (grpc.ClientConnInterface, error) {
 	cp.RLock()
 	cnx, ok := cp.connections[target]
@@ -143,7 +153,7 @@ func (cp *clientPool) getConnection(target string) (grpc.ClientConnInterface, er
 	// tls configure
 	tcs := insecure.NewCredentials()
 	if cp.tls != nil {
-		tcs = credentials.NewTLS(cp.tls)
+		tcs = security.NewClientCredentials(cp.tls)
 	}
 
 	options := []grpc.DialOption{
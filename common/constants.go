@@ -21,6 +21,10 @@ const (
 	MetadataShardId   = "shard-id"
 	DefaultNamespace  = "default"
 
+	// MetadataAuditReason is why a change was requested through the admin
+	// API, as recorded in the audit log of the coordinator
+	MetadataAuditReason = "audit-reason"
+
 	DefaultPublicPort   = 6648
 	DefaultInternalPort = 6649
 	DefaultMetricsPort  = 8080
@@ -31,6 +31,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/security"
 )
 
 const (
@@ -46,7 +47,7 @@ type GrpcServer interface {
 }
 
 type GrpcProvider interface {
-	StartGrpcServer(name, bindAddress string, registerFunc func(grpc.ServiceRegistrar), tlsConf *tls.Config, options *auth.Options) (GrpcServer, error)
+	StartGrpcServer(name, bindAddress string, registerFunc func(grpc.ServiceRegistrar), tlsConf *tls.Config, ipFilter *security.IPFilter, options *auth.Options) (GrpcServer, error)
 }
 
 var Default = &defaultProvider{}
@@ -54,8 +55,8 @@ var Default = &defaultProvider{}
 type defaultProvider struct {
 }
 
-func (*defaultProvider) StartGrpcServer(name, bindAddress string, registerFunc func(grpc.ServiceRegistrar), tlsConf *tls.Config, options *auth.Options) (GrpcServer, error) {
-	return newDefaultGrpcProvider(name, bindAddress, registerFunc, tlsConf, options)
+func (*defaultProvider) StartGrpcServer(name, bindAddress string, registerFunc func(grpc.ServiceRegistrar), tlsConf *tls.Config, ipFilter *security.IPFilter, options *auth.Options) (GrpcServer, error) {
+	return newDefaultGrpcProvider(name, bindAddress, registerFunc, tlsConf, ipFilter, options)
 }
 
 type defaultGrpcServer struct {
@@ -66,7 +67,7 @@ type defaultGrpcServer struct {
 }
 
 func newDefaultGrpcProvider(name, bindAddress string, registerFunc func(grpc.ServiceRegistrar),
-	tlsConf *tls.Config, authOptions *auth.Options) (GrpcServer, error) {
+	tlsConf *tls.Config, ipFilter *security.IPFilter, authOptions *auth.Options) (GrpcServer, error) {
 	tcs := insecure.NewCredentials()
 	if tlsConf != nil {
 		tcs = credentials.NewTLS(tlsConf)
@@ -95,6 +96,9 @@ func newDefaultGrpcProvider(name, bindAddress string, registerFunc func(grpc.Ser
 		unaryInterceptors = append(unaryInterceptors, delegator.GetUnaryInterceptor())
 		streamInterceptors = append(streamInterceptors, delegator.GetStreamInterceptor())
 	}
+	customUnary, customStream := customInterceptors(name)
+	unaryInterceptors = append(unaryInterceptors, customUnary...)
+	streamInterceptors = append(streamInterceptors, customStream...)
 
 	c := &defaultGrpcServer{
 		server: grpc.NewServer(
@@ -111,6 +115,7 @@ func newDefaultGrpcProvider(name, bindAddress string, registerFunc func(grpc.Ser
 	if err != nil {
 		return nil, err
 	}
+	listener = ipFilter.WrapListener(listener)
 
 	c.port = listener.Addr().(*net.TCPAddr).Port
 
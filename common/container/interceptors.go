@@ -0,0 +1,97 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// The names of the gRPC servers the custom interceptors can be registered on
+const (
+	// ServerPublic serves the clients on the storage servers
+	ServerPublic = "public"
+	// ServerInternal serves the coordinator and the replication between the
+	// storage servers
+	ServerInternal = "internal"
+	// ServerCoordinator serves the coordinator internal port
+	ServerCoordinator = "coordinator"
+	// ServerCoordinatorAdmin serves the coordinator admin API, when it has a
+	// dedicated port
+	ServerCoordinatorAdmin = "coordinator-admin"
+	// AllServers registers an interceptor on all the gRPC servers
+	AllServers = "*"
+)
+
+type interceptors struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+var (
+	interceptorsLock sync.Mutex
+	registered       = map[string]*interceptors{}
+)
+
+// RegisterUnaryInterceptor adds a custom interceptor to the unary calls of the
+// gRPC servers with the given name, or of all of them with AllServers. It is
+// meant to be called from the init function of a package linked into a custom
+// build, so that the interceptor is in place before the servers are started.
+//
+// The custom interceptors run after the authentication, so that they can get
+// the principal of the client with auth.GetPrincipal, in the order of their
+// registration, starting with the ones of all the servers.
+func RegisterUnaryInterceptor(server string, interceptor grpc.UnaryServerInterceptor) {
+	interceptorsLock.Lock()
+	defer interceptorsLock.Unlock()
+
+	i := getOrCreateInterceptors(server)
+	i.unary = append(i.unary, interceptor)
+}
+
+// RegisterStreamInterceptor adds a custom interceptor to the streaming calls
+// of the gRPC servers with the given name, or of all of them with AllServers,
+// like RegisterUnaryInterceptor.
+func RegisterStreamInterceptor(server string, interceptor grpc.StreamServerInterceptor) {
+	interceptorsLock.Lock()
+	defer interceptorsLock.Unlock()
+
+	i := getOrCreateInterceptors(server)
+	i.stream = append(i.stream, interceptor)
+}
+
+func getOrCreateInterceptors(server string) *interceptors {
+	i, ok := registered[server]
+	if !ok {
+		i = &interceptors{}
+		registered[server] = i
+	}
+	return i
+}
+
+// customInterceptors returns the interceptors registered for the server.
+func customInterceptors(server string) (unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor) {
+	interceptorsLock.Lock()
+	defer interceptorsLock.Unlock()
+
+	for _, name := range []string{AllServers, server} {
+		if i, ok := registered[name]; ok {
+			unary = append(unary, i.unary...)
+			stream = append(stream, i.stream...)
+		}
+	}
+	return unary, stream
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	oxiaauth "github.com/streamnative/oxia/oxia/auth"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+func TestCustomInterceptors(t *testing.T) {
+	var lock sync.Mutex
+	var calls []string
+	record := func(ctx context.Context, interceptor string) {
+		lock.Lock()
+		defer lock.Unlock()
+		principal, _ := auth.GetPrincipal(ctx)
+		calls = append(calls, interceptor+":"+principal)
+	}
+
+	RegisterUnaryInterceptor(AllServers, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		record(ctx, "all")
+		return handler(ctx, req)
+	})
+	RegisterUnaryInterceptor("test-interceptors", func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		record(ctx, "unary")
+		return handler(ctx, req)
+	})
+	RegisterStreamInterceptor("test-interceptors", func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		record(ss.Context(), "stream")
+		return handler(srv, ss)
+	})
+	RegisterUnaryInterceptor("test-other", func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		record(ctx, "other")
+		return handler(ctx, req)
+	})
+
+	tokens := filepath.Join(t.TempDir(), "tokens")
+	assert.NoError(t, os.WriteFile(tokens, []byte("user-1:token-1\n"), 0600))
+
+	healthServer := health.NewServer()
+	server, err := Default.StartGrpcServer("test-interceptors", "localhost:0", func(registrar grpc.ServiceRegistrar) {
+		grpc_health_v1.RegisterHealthServer(registrar, healthServer)
+	}, nil, nil, &auth.Options{
+		ProviderName:   auth.ProviderTokenFile,
+		ProviderParams: `{"path":"` + tokens + `"}`,
+	})
+	assert.NoError(t, err)
+	defer server.Close()
+
+	cnx, err := grpc.NewClient(fmt.Sprintf("localhost:%d", server.Port()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(oxiaauth.NewTokenAuthenticationWithToken("token-1", false)))
+	assert.NoError(t, err)
+	defer cnx.Close()
+	client := grpc_health_v1.NewHealthClient(cnx)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"all:user-1", "unary:user-1", "stream:user-1"}, calls)
+}
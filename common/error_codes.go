@@ -31,6 +31,14 @@ const (
 	CodeInvalidSession         codes.Code = 108
 	CodeInvalidSessionTimeout  codes.Code = 109
 	CodeNamespaceNotFound      codes.Code = 110
+	CodeNamespaceAlreadyExists codes.Code = 111
+	CodeNotLeaderCoordinator   codes.Code = 112
+	CodeValueTooLarge          codes.Code = 113
+	CodeRateLimited            codes.Code = 114
+	CodeSubscriberTooSlow      codes.Code = 115
+	CodeThrottled              codes.Code = 116
+	CodePermissionDenied       codes.Code = 117
+	CodeKeyAlreadyExists       codes.Code = 118
 )
 
 var (
@@ -45,4 +53,14 @@ var (
 	ErrorInvalidSession         = status.Error(CodeInvalidSession, "oxia: session not found")
 	ErrorInvalidSessionTimeout  = status.Error(CodeInvalidSessionTimeout, "oxia: invalid session timeout")
 	ErrorNamespaceNotFound      = status.Error(CodeNamespaceNotFound, "oxia: namespace not found")
+	ErrorNamespaceAlreadyExists = status.Error(CodeNamespaceAlreadyExists, "oxia: namespace already exists")
+	ErrorNotLeaderCoordinator   = status.Error(CodeNotLeaderCoordinator, "oxia: coordinator is not the leader")
+	ErrorValueTooLarge          = status.Error(CodeValueTooLarge, "oxia: value exceeds the max size of the namespace")
+	ErrorRateLimited            = status.Error(CodeRateLimited, "oxia: rate limit of the namespace exceeded")
+	ErrorTenantRateLimited      = status.Error(CodeRateLimited, "oxia: rate limit of the tenant exceeded")
+	ErrorSubscriberTooSlow      = status.Error(CodeSubscriberTooSlow, "oxia: notifications subscriber is too slow")
+	ErrorThrottled              = status.Error(CodeThrottled, "oxia: request rate of the client exceeded")
+	ErrorPermissionDenied       = status.Error(CodePermissionDenied, "oxia: permission denied on the namespace")
+	ErrorQuotaExceeded          = status.Error(codes.ResourceExhausted, "oxia: quota of the principal exceeded")
+	ErrorKeyAlreadyExists       = status.Error(CodeKeyAlreadyExists, "oxia: key of the merged shard already exists in the shard")
 )
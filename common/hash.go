@@ -15,9 +15,17 @@
 package common
 
 import (
+	"hash/fnv"
+
 	"github.com/zeebo/xxh3"
 )
 
 func Xxh332(key string) uint32 {
 	return uint32(xxh3.HashString(key))
 }
+
+func Fnv1a32(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
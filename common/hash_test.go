@@ -35,3 +35,19 @@ func TestXxh332(t *testing.T) {
 		})
 	}
 }
+
+func TestFnv1a32(t *testing.T) {
+	for _, test := range []struct {
+		key      string
+		expected uint32
+	}{
+		{"foo", 2851307223},
+		{"bar", 1991736602},
+		{"baz", 1857515650},
+	} {
+		t.Run(test.key, func(t *testing.T) {
+			hash := Fnv1a32(test.key)
+			assert.Equal(t, test.expected, hash)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+type LogRedactionMode string
+
+const (
+	// LogRedactionNone emits the keys and the values as they are
+	LogRedactionNone LogRedactionMode = "none"
+
+	// LogRedactionDrop removes the keys and the values from the logs above
+	// the debug level. The keys are hashed in the error logs, so that the
+	// errors on the same key can still be correlated.
+	LogRedactionDrop LogRedactionMode = "drop"
+
+	// LogRedactionHash replaces the keys by their hash in the logs above the
+	// debug level, and removes the values.
+	LogRedactionHash LogRedactionMode = "hash"
+)
+
+const redactedKeyHashLen = 16
+
+var (
+	// DefaultRedactedKeyAttributes are the attributes of the logs that hold
+	// the keys of the records.
+	DefaultRedactedKeyAttributes = []string{"key", "keys", "key-start", "key-end", "key-range", "prefix"}
+
+	// DefaultRedactedValueAttributes are the attributes of the logs that hold
+	// the values of the records.
+	DefaultRedactedValueAttributes = []string{"value"}
+
+	// LogRedaction Used for flags.
+	LogRedaction = LogRedactionPolicy{
+		Mode:            LogRedactionNone,
+		KeyAttributes:   DefaultRedactedKeyAttributes,
+		ValueAttributes: DefaultRedactedValueAttributes,
+	}
+)
+
+// LogRedactionPolicy describes how the sensitive data is redacted from the
+// logs, for the clusters that store sensitive identifiers in their keys.
+type LogRedactionPolicy struct {
+	Mode LogRedactionMode
+
+	// KeyAttributes and ValueAttributes are the names of the attributes that
+	// hold the keys and the values of the records
+	KeyAttributes   []string
+	ValueAttributes []string
+
+	// Salt is mixed in the hashes of the keys, so that the keys cannot be
+	// found by hashing the likely candidates
+	Salt string
+}
+
+func (p LogRedactionPolicy) Validate() error {
+	switch p.Mode {
+	case LogRedactionNone, LogRedactionDrop, LogRedactionHash:
+		return nil
+	default:
+		return errors.Errorf("unknown log redaction mode %q", p.Mode)
+	}
+}
+
+// NewRedactingHandler wraps a log handler to redact the keys and the values
+// from the records above the debug level, according to the policy. The
+// attributes added to the loggers with With are redacted as well, based on
+// the level of each record.
+func NewRedactingHandler(h slog.Handler, policy LogRedactionPolicy) slog.Handler {
+	if policy.Mode == "" || policy.Mode == LogRedactionNone {
+		return h
+	}
+	return &redactingHandler{inner: h, policy: policy}
+}
+
+type redactingHandler struct {
+	inner  slog.Handler
+	policy LogRedactionPolicy
+
+	// The attributes and the groups added to the logger, in order, which
+	// are only redacted when the level of the record is known
+	ops []redactingOp
+}
+
+type redactingOp struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level <= slog.LevelDebug {
+		return h.withOps(record.Level).Handle(ctx, record)
+	}
+
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		if a, ok := h.redact(a, record.Level); ok {
+			redacted.AddAttrs(a)
+		}
+		return true
+	})
+	return h.withOps(record.Level).Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) withOps(level slog.Level) slog.Handler {
+	inner := h.inner
+	for _, op := range h.ops {
+		if op.group != "" {
+			inner = inner.WithGroup(op.group)
+			continue
+		}
+
+		attrs := op.attrs
+		if level > slog.LevelDebug {
+			attrs = make([]slog.Attr, 0, len(op.attrs))
+			for _, a := range op.attrs {
+				if a, ok := h.redact(a, level); ok {
+					attrs = append(attrs, a)
+				}
+			}
+		}
+		inner = inner.WithAttrs(attrs)
+	}
+	return inner
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(redactingOp{attrs: attrs})
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return h.with(redactingOp{group: name})
+}
+
+func (h *redactingHandler) with(op redactingOp) slog.Handler {
+	return &redactingHandler{
+		inner:  h.inner,
+		policy: h.policy,
+		ops:    append(slices.Clip(h.ops), op),
+	}
+}
+
+// redact returns the attribute to emit in place of the given one, if any.
+func (h *redactingHandler) redact(a slog.Attr, level slog.Level) (slog.Attr, bool) {
+	if a.Value.Kind() == slog.KindGroup {
+		var attrs []any
+		for _, ga := range a.Value.Group() {
+			if ga, ok := h.redact(ga, level); ok {
+				attrs = append(attrs, ga)
+			}
+		}
+		return slog.Group(a.Key, attrs...), true
+	}
+
+	switch {
+	case slices.Contains(h.policy.ValueAttributes, a.Key):
+		return slog.Attr{}, false
+	case !slices.Contains(h.policy.KeyAttributes, a.Key):
+		return a, true
+	case h.policy.Mode == LogRedactionHash || level >= slog.LevelError:
+		return slog.Any(a.Key, h.hashKeys(a.Value.Resolve())), true
+	default:
+		return slog.Attr{}, false
+	}
+}
+
+func (h *redactingHandler) hashKeys(v slog.Value) any {
+	if keys, ok := v.Any().([]string); ok {
+		hashes := make([]string, len(keys))
+		for i, key := range keys {
+			hashes[i] = h.hashKey(key)
+		}
+		return hashes
+	}
+	if v.Kind() == slog.KindString {
+		return h.hashKey(v.String())
+	}
+	return h.hashKey(fmt.Sprint(v.Any()))
+}
+
+// hashKey returns a short HMAC of the key, which identifies the key in the
+// logs without revealing it.
+func (h *redactingHandler) hashKey(key string) string {
+	mac := hmac.New(sha256.New, []byte(h.policy.Salt))
+	mac.Write([]byte(key))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:redactedKeyHashLen]
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func redactedLogs(t *testing.T, mode LogRedactionMode, log func(logger *slog.Logger)) []map[string]any {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		LogRedactionPolicy{
+			Mode:            mode,
+			KeyAttributes:   DefaultRedactedKeyAttributes,
+			ValueAttributes: DefaultRedactedValueAttributes,
+			Salt:            "salt",
+		}))
+	log(logger)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRedactingHandler_Drop(t *testing.T) {
+	records := redactedLogs(t, LogRedactionDrop, func(logger *slog.Logger) {
+		logger = logger.With(slog.String("key", "/users/alice"), slog.Int64("shard", 1))
+		logger.Debug("debug", slog.String("value", "secret"))
+		logger.Info("info", slog.String("value", "secret"))
+		logger.Error("error", slog.Any("keys", []string{"/users/bob"}), slog.Group("req", slog.String("key", "/users/carol")))
+	})
+	assert.Len(t, records, 3)
+
+	// The debug logs are not redacted
+	assert.Equal(t, "/users/alice", records[0]["key"])
+	assert.Equal(t, "secret", records[0]["value"])
+
+	assert.NotContains(t, records[1], "key")
+	assert.NotContains(t, records[1], "value")
+	assert.EqualValues(t, 1, records[1]["shard"])
+
+	// The keys are hashed in the error logs
+	assert.Regexp(t, "^sha256:[0-9a-f]{16}$", records[2]["key"])
+	assert.Len(t, records[2]["keys"], 1)
+	assert.Regexp(t, "^sha256:[0-9a-f]{16}$", records[2]["keys"].([]any)[0])
+	assert.Regexp(t, "^sha256:[0-9a-f]{16}$", records[2]["req"].(map[string]any)["key"])
+	assert.NotContains(t, records[2]["req"].(map[string]any)["key"], "carol")
+}
+
+func TestRedactingHandler_Hash(t *testing.T) {
+	records := redactedLogs(t, LogRedactionHash, func(logger *slog.Logger) {
+		logger.Info("info", slog.String("key", "/users/alice"), slog.String("value", "secret"))
+		logger.Warn("warn", slog.String("key", "/users/alice"))
+		logger.Info("info", slog.String("key", "/users/bob"))
+	})
+	assert.Len(t, records, 3)
+
+	// The same key always has the same hash
+	assert.Regexp(t, "^sha256:[0-9a-f]{16}$", records[0]["key"])
+	assert.Equal(t, records[0]["key"], records[1]["key"])
+	assert.NotEqual(t, records[0]["key"], records[2]["key"])
+	assert.NotContains(t, records[0], "value")
+}
+
+func TestRedactingHandler_None(t *testing.T) {
+	h := slog.NewJSONHandler(&bytes.Buffer{}, nil)
+	assert.Equal(t, slog.Handler(h), NewRedactingHandler(h, LogRedactionPolicy{Mode: LogRedactionNone}))
+
+	assert.NoError(t, LogRedactionPolicy{Mode: LogRedactionDrop}.Validate())
+	assert.Error(t, LogRedactionPolicy{Mode: "mask"}.Validate())
+}
@@ -83,11 +83,12 @@ func ConfigureLogger() {
 		})
 	}
 
-	slogLogger := slog.New(
+	slogLogger := slog.New(NewRedactingHandler(
 		slogzerolog.Option{
 			Level:  LogLevel,
 			Logger: &zerologLogger,
 		}.NewZerologHandler(),
-	)
+		LogRedaction,
+	))
 	slog.SetDefault(slogLogger)
 }
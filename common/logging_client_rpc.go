@@ -98,6 +98,15 @@ func (l *loggingClientRpc) GetNotifications(ctx context.Context, in *proto.Notif
 	return res, err
 }
 
+func (l *loggingClientRpc) AckNotifications(ctx context.Context, in *proto.AckNotificationsRequest, opts ...grpc.CallOption) (
+	res *proto.AckNotificationsResponse, err error) {
+	if res, err = l.client.AckNotifications(ctx, in, opts...); err != nil {
+		return nil, l.decorateErr(err)
+	}
+
+	return res, err
+}
+
 func (l *loggingClientRpc) CreateSession(ctx context.Context, in *proto.CreateSessionRequest, opts ...grpc.CallOption) (
 	res *proto.CreateSessionResponse, err error) {
 	if res, err = l.client.CreateSession(ctx, in, opts...); err != nil {
@@ -124,3 +133,30 @@ func (l *loggingClientRpc) CloseSession(ctx context.Context, in *proto.CloseSess
 
 	return res, err
 }
+
+func (l *loggingClientRpc) GrantLease(ctx context.Context, in *proto.GrantLeaseRequest, opts ...grpc.CallOption) (
+	res *proto.GrantLeaseResponse, err error) {
+	if res, err = l.client.GrantLease(ctx, in, opts...); err != nil {
+		return nil, l.decorateErr(err)
+	}
+
+	return res, err
+}
+
+func (l *loggingClientRpc) RefreshLease(ctx context.Context, in *proto.LeaseRequest, opts ...grpc.CallOption) (
+	res *proto.RefreshLeaseResponse, err error) {
+	if res, err = l.client.RefreshLease(ctx, in, opts...); err != nil {
+		return nil, l.decorateErr(err)
+	}
+
+	return res, err
+}
+
+func (l *loggingClientRpc) RevokeLease(ctx context.Context, in *proto.LeaseRequest, opts ...grpc.CallOption) (
+	res *proto.RevokeLeaseResponse, err error) {
+	if res, err = l.client.RevokeLease(ctx, in, opts...); err != nil {
+		return nil, l.decorateErr(err)
+	}
+
+	return res, err
+}
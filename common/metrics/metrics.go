@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/security"
 )
 
 func init() {
@@ -92,7 +93,7 @@ type PrometheusMetrics struct {
 	port   int
 }
 
-func Start(bindAddress string) (*PrometheusMetrics, error) {
+func Start(bindAddress string, ipFilter *security.IPFilter) (*PrometheusMetrics, error) {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -100,6 +101,7 @@ func Start(bindAddress string) (*PrometheusMetrics, error) {
 	if err != nil {
 		return nil, err
 	}
+	listener = ipFilter.WrapListener(listener)
 
 	p := &PrometheusMetrics{
 		server: &http.Server{
@@ -21,10 +21,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common/security"
 )
 
 func TestPrometheusMetrics(t *testing.T) {
-	metrics, err := Start("localhost:0")
+	metrics, err := Start("localhost:0", nil)
 	assert.NoError(t, err)
 
 	url := fmt.Sprintf("http://localhost:%d/metrics", metrics.Port())
@@ -53,3 +55,18 @@ func TestPrometheusMetrics(t *testing.T) {
 		defer response2.Body.Close()
 	}
 }
+
+func TestPrometheusMetrics_IPFilter(t *testing.T) {
+	ipFilter, err := security.NewIPFilter(nil, []string{"127.0.0.0/8", "::1"})
+	assert.NoError(t, err)
+
+	metrics, err := Start("localhost:0", ipFilter)
+	assert.NoError(t, err)
+	defer metrics.Close()
+
+	response, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", metrics.Port()))
+	assert.Error(t, err)
+	if response != nil && response.Body != nil {
+		defer response.Body.Close()
+	}
+}
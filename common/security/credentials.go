@@ -0,0 +1,69 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"context"
+	libtls "crypto/tls"
+	"crypto/x509"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// NewClientCredentials returns the GRPC credentials of a client TLS
+// configuration. When the configuration was made by MakeClientTLSConf, each
+// handshake verifies the server with the latest trusted CA, so that it can be
+// rotated without restarting.
+func NewClientCredentials(tlsConf *libtls.Config) credentials.TransportCredentials {
+	certPool, ok := clientRootCAs.Load(tlsConf)
+	if !ok {
+		return credentials.NewTLS(tlsConf)
+	}
+	return &clientCredentials{
+		TransportCredentials: credentials.NewTLS(tlsConf),
+		base:                 tlsConf.Clone(),
+		certPool:             certPool.(*reloadable[*x509.CertPool]),
+	}
+}
+
+type clientCredentials struct {
+	credentials.TransportCredentials
+	base     *libtls.Config
+	certPool *reloadable[*x509.CertPool]
+}
+
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	rootCAs, err := c.certPool.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	conf := c.base.Clone()
+	conf.RootCAs = rootCAs
+	return credentials.NewTLS(conf).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	return &clientCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		base:                 c.base.Clone(),
+		certPool:             c.certPool,
+	}
+}
+
+func (c *clientCredentials) OverrideServerName(serverName string) error { //nolint:staticcheck
+	c.base.ServerName = serverName
+	return c.TransportCredentials.OverrideServerName(serverName) //nolint:staticcheck
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IPFilterOption are the ranges of addresses allowed and denied on a listener.
+type IPFilterOption struct {
+	Allowed []string
+	Denied  []string
+}
+
+func (o *IPFilterOption) MakeIPFilter() (*IPFilter, error) {
+	return NewIPFilter(o.Allowed, o.Denied)
+}
+
+// IPFilter accepts or rejects the connections based on the address of the
+// peer, as a defense in depth for the listeners exposed on shared networks.
+type IPFilter struct {
+	allowed []*net.IPNet
+	denied  []*net.IPNet
+}
+
+// NewIPFilter parses the allowed and denied CIDRs, single addresses are
+// accepted as well. The denied ranges take precedence over the allowed ones,
+// and when no range is allowed, all the addresses that are not denied are
+// accepted. With both lists empty, the filter is nil and accepts everything.
+func NewIPFilter(allowed, denied []string) (*IPFilter, error) {
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil, nil
+	}
+
+	f := &IPFilter{}
+	var err error
+	if f.allowed, err = parseCIDRs(allowed); err != nil {
+		return nil, err
+	}
+	if f.denied, err = parseCIDRs(denied); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, errors.Errorf("invalid ip address: %q", cidr)
+			}
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cidr: %q", cidr)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts returns whether the connections from the address are allowed.
+func (f *IPFilter) Accepts(ip net.IP) bool {
+	if f == nil {
+		return true
+	}
+	if containsIP(f.denied, ip) {
+		return false
+	}
+	return len(f.allowed) == 0 || containsIP(f.allowed, ip)
+}
+
+// WrapListener closes the rejected connections as soon as they're accepted,
+// before any handshake. A nil filter returns the listener as is.
+func (f *IPFilter) WrapListener(listener net.Listener) net.Listener {
+	if f == nil {
+		return listener
+	}
+	return &filteredListener{Listener: listener, filter: f}
+}
+
+type filteredListener struct {
+	net.Listener
+	filter *IPFilter
+}
+
+func (l *filteredListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); !ok || l.filter.Accepts(addr.IP) {
+			return conn, nil
+		}
+
+		slog.Debug(
+			"Rejected connection by the ip filter",
+			slog.String("listener", l.Addr().String()),
+			slog.String("peer", conn.RemoteAddr().String()),
+		)
+		_ = conn.Close()
+	}
+}
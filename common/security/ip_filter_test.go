@@ -0,0 +1,82 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPFilter(t *testing.T) {
+	f, err := NewIPFilter(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+	assert.True(t, f.Accepts(net.ParseIP("10.0.0.1")))
+
+	f, err = NewIPFilter([]string{"10.0.0.0/8", "192.168.1.5", "fd00::/8"}, []string{"10.1.0.0/16"})
+	assert.NoError(t, err)
+	assert.True(t, f.Accepts(net.ParseIP("10.0.0.1")))
+	assert.True(t, f.Accepts(net.ParseIP("192.168.1.5")))
+	assert.True(t, f.Accepts(net.ParseIP("fd00::1")))
+	assert.True(t, f.Accepts(net.ParseIP("::ffff:10.0.0.1")))
+	assert.False(t, f.Accepts(net.ParseIP("10.1.2.3")))
+	assert.False(t, f.Accepts(net.ParseIP("192.168.1.6")))
+	assert.False(t, f.Accepts(net.ParseIP("2001:db8::1")))
+
+	// Only denied ranges
+	f, err = NewIPFilter(nil, []string{"172.16.0.0/12"})
+	assert.NoError(t, err)
+	assert.True(t, f.Accepts(net.ParseIP("10.0.0.1")))
+	assert.False(t, f.Accepts(net.ParseIP("172.16.0.1")))
+
+	_, err = NewIPFilter([]string{"10.0.0.0/33"}, nil)
+	assert.Error(t, err)
+	_, err = NewIPFilter(nil, []string{"not-an-ip"})
+	assert.Error(t, err)
+}
+
+func TestIPFilter_WrapListener(t *testing.T) {
+	var f *IPFilter
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	assert.Equal(t, listener, f.WrapListener(listener))
+
+	f, err = NewIPFilter([]string{"10.0.0.0/8"}, nil)
+	assert.NoError(t, err)
+	filtered := f.WrapListener(listener)
+	defer filtered.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := filtered.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+		close(accepted)
+	}()
+
+	// The connection from the loopback is closed by the listener
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.NoError(t, conn.Close())
+
+	assert.NoError(t, filtered.Close())
+	_, ok := <-accepted
+	assert.False(t, ok)
+}
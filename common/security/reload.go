@@ -0,0 +1,125 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	libtls "crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// The state of the files a reloadable object was loaded from, to detect
+// when they're replaced, eg: by cert-manager renewing a certificate.
+type filesVersion []time.Time
+
+func statFiles(paths ...string) (filesVersion, error) {
+	version := make(filesVersion, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		version = append(version, info.ModTime())
+	}
+	return version, nil
+}
+
+func (v filesVersion) equal(other filesVersion) bool {
+	if len(v) != len(other) {
+		return false
+	}
+	for i := range v {
+		if !v[i].Equal(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// reloadable caches an object loaded from files, and loads it again when the
+// files are modified. The established connections are not affected, only
+// the next handshakes use the new object.
+type reloadable[T any] struct {
+	sync.Mutex
+	paths   []string
+	load    func() (T, error)
+	value   T
+	version filesVersion
+}
+
+func newReloadable[T any](load func() (T, error), paths ...string) (*reloadable[T], error) {
+	r := &reloadable[T]{
+		paths: paths,
+		load:  load,
+	}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *reloadable[T]) get() (T, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	version, err := statFiles(r.paths...)
+	if err != nil {
+		if r.version != nil {
+			// Keep using the last loaded value while the files are replaced
+			return r.value, nil
+		}
+		return r.value, err
+	}
+	if r.version != nil && version.equal(r.version) {
+		return r.value, nil
+	}
+
+	value, err := r.load()
+	if err != nil {
+		if r.version != nil {
+			slog.Warn(
+				"Failed to reload the tls files, keeping the previous ones",
+				slog.Any("files", r.paths),
+				slog.Any("error", err),
+			)
+			return r.value, nil
+		}
+		return value, err
+	}
+
+	if r.version != nil {
+		slog.Info(
+			"Reloaded the tls files",
+			slog.Any("files", r.paths),
+		)
+	}
+	r.value = value
+	r.version = version
+	return value, nil
+}
+
+func (tls *TLSOption) newKeyPair() (*reloadable[*libtls.Certificate], error) {
+	return newReloadable(func() (*libtls.Certificate, error) {
+		c, err := libtls.LoadX509KeyPair(tls.CertFile, tls.KeyFile)
+		return &c, err
+	}, tls.CertFile, tls.KeyFile)
+}
+
+func (tls *TLSOption) newCertPool() (*reloadable[*x509.CertPool], error) {
+	return newReloadable(tls.trustedCertPool, tls.TrustedCaFile)
+}
@@ -17,8 +17,8 @@ package security
 import (
 	libtls "crypto/tls"
 	"crypto/x509"
-	"encoding/pem"
 	"os"
+	"sync"
 
 	"github.com/pkg/errors"
 )
@@ -47,26 +47,25 @@ type TLSOption struct {
 var (
 	ErrInvalidTLSCertFile = errors.New("tls cert file path can not be empty")
 	ErrInvalidTLSKeyFile  = errors.New("tls key file path can not be empty")
+	ErrInvalidTLSCaFile   = errors.New("tls trusted ca file contains no certificate")
 )
 
+// The trusted CAs of the configurations made by MakeClientTLSConf, for the
+// client credentials to verify the servers with the latest ones.
+var clientRootCAs sync.Map // *libtls.Config -> *reloadable[*x509.CertPool]
+
+// IsConfigured tells whether TLS is enabled. A client only needs the trusted
+// CA to verify the servers, while the certificate is required on the servers
+// and for the clients that authenticate with a certificate.
 func (tls *TLSOption) IsConfigured() bool {
-	return tls.CertFile != ""
+	return tls.CertFile != "" || tls.TrustedCaFile != ""
 }
 
 func (tls *TLSOption) makeCommonConfig() (*libtls.Config, error) {
-	if tls.CertFile == "" {
-		return nil, ErrInvalidTLSCertFile
-	}
-	if tls.KeyFile == "" {
+	if tls.CertFile != "" && tls.KeyFile == "" {
 		return nil, ErrInvalidTLSKeyFile
 	}
 
-	// validate it first
-	_, err := libtls.LoadX509KeyPair(tls.CertFile, tls.KeyFile)
-	if err != nil {
-		return nil, err
-	}
-
 	var minVersion uint16 = libtls.VersionTLS12
 	if tls.MinVersion != 0 {
 		minVersion = tls.MinVersion
@@ -86,53 +85,73 @@ func (tls *TLSOption) makeCommonConfig() (*libtls.Config, error) {
 	return &tlsConf, nil
 }
 
+// The trusted CA file can be a bundle of multiple certificates, eg: while
+// rotating the CA.
 func (tls *TLSOption) trustedCertPool() (*x509.CertPool, error) {
 	certPool := x509.NewCertPool()
 	bPem, err := os.ReadFile(tls.TrustedCaFile)
 	if err != nil {
 		return nil, err
 	}
-	var block *pem.Block
-	block, _ = pem.Decode(bPem)
-	if block != nil {
-		cert, err := x509.ParseCertificate(block.Bytes)
-		if err != nil {
-			return nil, err
-		}
-		certPool.AddCert(cert)
+	if !certPool.AppendCertsFromPEM(bPem) {
+		return nil, errors.Wrap(ErrInvalidTLSCaFile, tls.TrustedCaFile)
 	}
 	return certPool, nil
 }
 
+// MakeClientTLSConf returns the configuration of a client. The certificate
+// is reloaded when its files change, so that it can be rotated without
+// restarting, and so is the trusted CA when the connections are made with
+// the credentials of NewClientCredentials.
 func (tls *TLSOption) MakeClientTLSConf() (*libtls.Config, error) {
 	tlsConf, err := tls.makeCommonConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(tls.TrustedCaFile) > 0 {
-		certPool, err := tls.trustedCertPool()
+	// The client only presents a certificate when it has one
+	if tls.CertFile != "" {
+		keyPair, err := tls.newKeyPair()
 		if err != nil {
 			return nil, err
 		}
-		tlsConf.RootCAs = certPool
+		tlsConf.GetClientCertificate = func(*libtls.CertificateRequestInfo) (*libtls.Certificate, error) {
+			return keyPair.get()
+		}
 	}
 
-	tlsConf.GetClientCertificate = func(unused *libtls.CertificateRequestInfo) (cert *libtls.Certificate, err error) {
-		c, err := libtls.LoadX509KeyPair(tls.CertFile, tls.KeyFile)
-		return &c, err
+	if len(tls.TrustedCaFile) > 0 {
+		certPool, err := tls.newCertPool()
+		if err != nil {
+			return nil, err
+		}
+		if tlsConf.RootCAs, err = certPool.get(); err != nil {
+			return nil, err
+		}
+		clientRootCAs.Store(tlsConf, certPool)
 	}
 	return tlsConf, nil
 }
 
+// MakeServerTLSConf returns the configuration of a server. The certificate
+// and the trusted CA of the clients are reloaded when their files change,
+// without affecting the established connections.
 func (tls *TLSOption) MakeServerTLSConf() (*libtls.Config, error) {
+	if tls.CertFile == "" {
+		return nil, ErrInvalidTLSCertFile
+	}
+
 	tlsConf, err := tls.makeCommonConfig()
 	if err != nil {
 		return nil, err
 	}
-	tlsConf.GetCertificate = func(clientHello *libtls.ClientHelloInfo) (cert *libtls.Certificate, err error) {
-		c, err := libtls.LoadX509KeyPair(tls.CertFile, tls.KeyFile)
-		return &c, err
+
+	keyPair, err := tls.newKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	tlsConf.GetCertificate = func(*libtls.ClientHelloInfo) (*libtls.Certificate, error) {
+		return keyPair.get()
 	}
 
 	// auth type upgrading
@@ -146,11 +165,24 @@ func (tls *TLSOption) MakeServerTLSConf() (*libtls.Config, error) {
 	tlsConf.ClientAuth = authType
 
 	if len(tls.TrustedCaFile) > 0 {
-		certPool, err := tls.trustedCertPool()
+		certPool, err := tls.newCertPool()
 		if err != nil {
 			return nil, err
 		}
-		tlsConf.ClientCAs = certPool
+		if tlsConf.ClientCAs, err = certPool.get(); err != nil {
+			return nil, err
+		}
+
+		base := tlsConf.Clone()
+		tlsConf.GetConfigForClient = func(*libtls.ClientHelloInfo) (*libtls.Config, error) {
+			clientCAs, err := certPool.get()
+			if err != nil {
+				return nil, err
+			}
+			conf := base.Clone()
+			conf.ClientCAs = clientCAs
+			return conf, nil
+		}
 	}
 
 	return tlsConf, nil
@@ -0,0 +1,240 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCert struct {
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	pem    []byte
+	keyPem []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	parentCert, parentKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return &testCert{
+		cert:   cert,
+		key:    key,
+		pem:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+// Replace the content of a file, making sure that its modification time
+// changes.
+func replaceFile(t *testing.T, path string, content []byte) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, content, 0600))
+	modTime := info.ModTime().Add(time.Second)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func writeFile(t *testing.T, name string, content ...[]byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	var data []byte
+	for _, c := range content {
+		data = append(data, c...)
+	}
+	assert.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+// Make a handshake with a server, on the loopback address, with the
+// authority the client connects to.
+func handshake(t *testing.T, clientConf, serverConf *tls.Config, authority string) error {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = tls.Server(conn, serverConf).Handshake()
+	}()
+
+	rawConn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, _, err := NewClientCredentials(clientConf).ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestTLSOption_CaBundle(t *testing.T) {
+	ca1 := newTestCert(t, "ca-1", nil)
+	ca2 := newTestCert(t, "ca-2", nil)
+	server := newTestCert(t, "server", ca2)
+
+	// A client that only verifies the servers doesn't need a certificate
+	option := &TLSOption{TrustedCaFile: writeFile(t, "ca.pem", ca1.pem, ca2.pem)}
+	assert.True(t, option.IsConfigured())
+
+	conf, err := option.MakeClientTLSConf()
+	assert.NoError(t, err)
+	assert.Nil(t, conf.GetClientCertificate)
+
+	// All the certificates of the bundle are trusted
+	_, err = server.cert.Verify(x509.VerifyOptions{Roots: conf.RootCAs, DNSName: "server"})
+	assert.NoError(t, err)
+
+	// The servers always need a certificate
+	_, err = option.MakeServerTLSConf()
+	assert.ErrorIs(t, err, ErrInvalidTLSCertFile)
+
+	option.TrustedCaFile = writeFile(t, "empty.pem", []byte("no certificate"))
+	_, err = option.MakeClientTLSConf()
+	assert.ErrorIs(t, err, ErrInvalidTLSCaFile)
+
+	assert.False(t, (&TLSOption{}).IsConfigured())
+}
+
+func TestTLSOption_Rotation(t *testing.T) {
+	ca1 := newTestCert(t, "ca-1", nil)
+	ca2 := newTestCert(t, "ca-2", nil)
+	server1 := newTestCert(t, "server", ca1)
+	server2 := newTestCert(t, "server", ca2)
+
+	option := &TLSOption{
+		CertFile:      writeFile(t, "server.crt", server1.pem),
+		KeyFile:       writeFile(t, "server.key", server1.keyPem),
+		TrustedCaFile: writeFile(t, "ca.pem", ca1.pem),
+	}
+
+	serverConf, err := option.MakeServerTLSConf()
+	assert.NoError(t, err)
+	clientConf, err := option.MakeClientTLSConf()
+	assert.NoError(t, err)
+
+	cert, err := serverConf.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, server1.cert.Raw, cert.Certificate[0])
+
+	serverWith := func(server *testCert) *tls.Config {
+		return &tls.Config{Certificates: []tls.Certificate{{
+			Certificate: [][]byte{server.cert.Raw},
+			PrivateKey:  server.key,
+		}}}
+	}
+	verify := func(server *testCert) error {
+		return handshake(t, clientConf, serverWith(server), "server:6648")
+	}
+	assert.NoError(t, verify(server1))
+	assert.Error(t, verify(server2))
+
+	// The new files are used by the next handshakes
+	replaceFile(t, option.CertFile, server2.pem)
+	replaceFile(t, option.KeyFile, server2.keyPem)
+	replaceFile(t, option.TrustedCaFile, ca2.pem)
+
+	cert, err = serverConf.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, server2.cert.Raw, cert.Certificate[0])
+
+	conf, err := serverConf.GetConfigForClient(nil)
+	assert.NoError(t, err)
+	_, err = server2.cert.Verify(x509.VerifyOptions{Roots: conf.ClientCAs, DNSName: "server"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, verify(server2))
+	assert.Error(t, verify(server1))
+
+	// An invalid file doesn't replace the current certificate
+	replaceFile(t, option.TrustedCaFile, []byte("invalid"))
+	assert.NoError(t, verify(server2))
+}
+
+func TestTLSOption_VerifyIPAddress(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+
+	serverOption := &TLSOption{
+		CertFile: writeFile(t, "server.crt", server.pem),
+		KeyFile:  writeFile(t, "server.key", server.keyPem),
+	}
+	serverConf, err := serverOption.MakeServerTLSConf()
+	assert.NoError(t, err)
+
+	clientOption := &TLSOption{TrustedCaFile: writeFile(t, "ca.pem", ca.pem)}
+	clientConf, err := clientOption.MakeClientTLSConf()
+	assert.NoError(t, err)
+
+	assert.NoError(t, handshake(t, clientConf, serverConf, "server:6648"))
+
+	// The certificate is not valid for the IP address the client connects to
+	err = handshake(t, clientConf, serverConf, "127.0.0.1:6648")
+	var hostnameErr x509.HostnameError
+	assert.ErrorAs(t, err, &hostnameErr)
+}
@@ -0,0 +1,866 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/impl"
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+// How often the shard loads are refreshed in the state streamed to the
+// standby coordinators.
+const stateStreamInterval = 5 * time.Second
+
+type adminRpcServer struct {
+	proto.UnimplementedOxiaAdminServer
+
+	// Returns the coordinator, or nil if this replica is not the leader
+	coordinator func() impl.Coordinator
+	log         *slog.Logger
+}
+
+func newAdminRpcServer(coordinator func() impl.Coordinator) *adminRpcServer {
+	return &adminRpcServer{
+		coordinator: coordinator,
+		log: slog.With(
+			slog.String("component", "admin-rpc-server"),
+		),
+	}
+}
+
+// peerRpcServer only exposes the state streamed to the standby coordinators,
+// for the internal port when the admin API is served on a dedicated one.
+type peerRpcServer struct {
+	proto.UnimplementedOxiaAdminServer
+
+	admin *adminRpcServer
+}
+
+func newPeerRpcServer(admin *adminRpcServer) *peerRpcServer {
+	return &peerRpcServer{admin: admin}
+}
+
+func (s *peerRpcServer) StreamCoordinatorState(req *proto.StreamCoordinatorStateRequest, stream proto.OxiaAdmin_StreamCoordinatorStateServer) error {
+	return s.admin.StreamCoordinatorState(req, stream)
+}
+
+func (s *adminRpcServer) CreateNamespace(ctx context.Context, req *proto.CreateNamespaceRequest) (*proto.CreateNamespaceResponse, error) {
+	s.log.Info(
+		"Received create namespace request",
+		slog.String("namespace", req.Namespace),
+		slog.Any("initial-shard-count", req.InitialShardCount),
+		slog.Any("replication-factor", req.ReplicationFactor),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.CreateNamespace(model.NamespaceConfig{
+		Name:              req.Namespace,
+		InitialShardCount: req.InitialShardCount,
+		ReplicationFactor: req.ReplicationFactor,
+		Tenant:            req.Tenant,
+	})
+	s.audit(ctx, c, impl.AuditRecord{
+		Action:    "create-namespace",
+		Namespace: req.Namespace,
+		Details:   fmt.Sprintf("%d shards, replication factor %d", req.InitialShardCount, req.ReplicationFactor),
+	}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	return &proto.CreateNamespaceResponse{}, nil
+}
+
+func (s *adminRpcServer) DeleteNamespace(ctx context.Context, req *proto.DeleteNamespaceRequest) (*proto.DeleteNamespaceResponse, error) {
+	s.log.Info(
+		"Received delete namespace request",
+		slog.String("namespace", req.Namespace),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.DeleteNamespace(req.Namespace)
+	s.audit(ctx, c, impl.AuditRecord{Action: "delete-namespace", Namespace: req.Namespace}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	return &proto.DeleteNamespaceResponse{}, nil
+}
+
+func (s *adminRpcServer) DecommissionServer(ctx context.Context, req *proto.DecommissionServerRequest) (*proto.DecommissionServerResponse, error) {
+	s.log.Info(
+		"Received decommission server request",
+		slog.String("server", req.Server),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	state, remainingShards, err := c.DecommissionServer(req.Server)
+	s.audit(ctx, c, impl.AuditRecord{Action: "decommission-server", Server: req.Server}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	res := &proto.DecommissionServerResponse{
+		State:           proto.DecommissionState_IN_PROGRESS,
+		RemainingShards: uint32(remainingShards),
+	}
+	if state == model.DecommissionStateCompleted {
+		res.State = proto.DecommissionState_COMPLETED
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) RebalanceCluster(ctx context.Context, req *proto.RebalanceClusterRequest) (*proto.RebalanceClusterResponse, error) {
+	s.log.Info(
+		"Received rebalance cluster request",
+		slog.Bool("dry-run", req.DryRun),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	actions, err := c.RebalanceCluster(req.DryRun)
+	if !req.DryRun {
+		s.audit(ctx, c, impl.AuditRecord{
+			Action:  "rebalance-cluster",
+			Details: fmt.Sprintf("%d replica moves", len(actions)),
+		}, err)
+	}
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	res := &proto.RebalanceClusterResponse{}
+	for _, a := range actions {
+		res.Moves = append(res.Moves, &proto.ReplicaMove{
+			Shard: a.Shard,
+			From:  a.From.Internal,
+			To:    a.To.Internal,
+		})
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) PlanRebalance(context.Context, *proto.PlanRebalanceRequest) (*proto.PlanRebalanceResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	plan, err := c.PlanRebalance()
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.PlanRebalanceResponse{
+		Plan: toProtoRebalancePlan(plan),
+	}, nil
+}
+
+func (s *adminRpcServer) ExecuteRebalancePlan(ctx context.Context, req *proto.ExecuteRebalancePlanRequest) (*proto.ExecuteRebalancePlanResponse, error) {
+	s.log.Info(
+		"Received execute rebalance plan request",
+		slog.Int64("plan", req.Id),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.ExecuteRebalancePlan(req.Id)
+	s.audit(ctx, c, impl.AuditRecord{Action: "execute-rebalance-plan", Details: fmt.Sprintf("plan %d", req.Id)}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.ExecuteRebalancePlanResponse{}, nil
+}
+
+func (s *adminRpcServer) GetRebalancePlan(context.Context, *proto.GetRebalancePlanRequest) (*proto.GetRebalancePlanResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	res := &proto.GetRebalancePlanResponse{}
+	if plan, ok := c.CurrentRebalancePlan(); ok {
+		res.Plan = toProtoRebalancePlan(plan)
+	}
+	return res, nil
+}
+
+func toProtoRebalancePlan(plan *impl.RebalancePlan) *proto.RebalancePlan {
+	res := &proto.RebalancePlan{
+		Id:               plan.Id,
+		State:            string(plan.State),
+		CreatedTimestamp: uint64(plan.CreatedAt.UnixMilli()),
+	}
+	for _, m := range plan.Moves {
+		state := string(m.State)
+		res.Moves = append(res.Moves, &proto.ReplicaMove{
+			Shard:          m.Shard,
+			From:           m.From.Internal,
+			To:             m.To.Internal,
+			EstimatedBytes: m.EstimatedBytes,
+			State:          &state,
+		})
+	}
+	return res
+}
+
+func (s *adminRpcServer) SetServerDrain(ctx context.Context, req *proto.SetServerDrainRequest) (*proto.SetServerDrainResponse, error) {
+	s.log.Info(
+		"Received set server drain request",
+		slog.String("server", req.Server),
+		slog.Bool("drain", req.Drain),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	leaderShards, err := c.SetServerDrain(req.Server, req.Drain)
+	s.audit(ctx, c, impl.AuditRecord{Action: "set-server-drain", Server: req.Server, Details: fmt.Sprintf("drain: %t", req.Drain)}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	return &proto.SetServerDrainResponse{
+		LeaderShards: uint32(leaderShards),
+	}, nil
+}
+
+func (s *adminRpcServer) ReplaceServer(ctx context.Context, req *proto.ReplaceServerRequest) (*proto.ReplaceServerResponse, error) {
+	s.log.Info(
+		"Received replace server request",
+		slog.String("old-server", req.OldServer),
+		slog.String("new-server", req.NewServer),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	shards, err := c.ReplaceServer(req.OldServer, req.NewServer)
+	s.audit(ctx, c, impl.AuditRecord{Action: "replace-server", Server: req.OldServer, Details: fmt.Sprintf("replaced by %s", req.NewServer)}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	return &proto.ReplaceServerResponse{
+		Shards: uint32(shards),
+	}, nil
+}
+
+func (s *adminRpcServer) ListNamespaces(context.Context, *proto.ListNamespacesRequest) (*proto.ListNamespacesResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	cs := c.ClusterStatus()
+	names := make([]string, 0, len(cs.Namespaces))
+	for name := range cs.Namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := &proto.ListNamespacesResponse{}
+	for _, name := range names {
+		nss := cs.Namespaces[name]
+		ni := &proto.NamespaceInfo{
+			Name:              name,
+			ReplicationFactor: nss.ReplicationFactor,
+			Shards:            uint32(len(nss.Shards)),
+			Dynamic:           nss.Dynamic,
+		}
+		if nss.DeletedAt != nil {
+			deletedTimestamp := uint64(nss.DeletedAt.UnixMilli())
+			ni.DeletedTimestamp = &deletedTimestamp
+		}
+		res.Namespaces = append(res.Namespaces, ni)
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) ListShards(_ context.Context, req *proto.ListShardsRequest) (*proto.ListShardsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	nss, ok := c.ClusterStatus().Namespaces[req.Namespace]
+	if !ok {
+		return nil, common.ErrorNamespaceNotFound
+	}
+
+	shards := make([]int64, 0, len(nss.Shards))
+	for shard := range nss.Shards {
+		shards = append(shards, shard)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+
+	res := &proto.ListShardsResponse{}
+	for _, shard := range shards {
+		sm := nss.Shards[shard]
+		si := &proto.ShardInfo{
+			Shard:        shard,
+			Status:       sm.Status.String(),
+			Term:         sm.Term,
+			Int32HashMin: sm.Int32HashRange.Min,
+			Int32HashMax: sm.Int32HashRange.Max,
+		}
+		if sm.Leader != nil {
+			si.Leader = &sm.Leader.Internal
+		}
+		for _, sa := range sm.Ensemble {
+			si.Ensemble = append(si.Ensemble, sa.Internal)
+		}
+		res.Shards = append(res.Shards, si)
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) ListServers(context.Context, *proto.ListServersRequest) (*proto.ListServersResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	res := &proto.ListServersResponse{}
+	for _, si := range c.ListServers() {
+		res.Servers = append(res.Servers, &proto.ServerInfo{
+			PublicAddress:   si.Address.Public,
+			InternalAddress: si.Address.Internal,
+			Running:         si.Running,
+			Draining:        si.Status.Draining,
+			Decommission:    string(si.Status.Decommission),
+			Replicas:        uint32(si.Replicas),
+			Leaders:         uint32(si.Leaders),
+			Labels:          si.Labels,
+		})
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) ListOperations(context.Context, *proto.ListOperationsRequest) (*proto.ListOperationsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	res := &proto.ListOperationsResponse{}
+	for _, op := range c.ListOperations() {
+		oi := &proto.OperationInfo{
+			Type:        string(op.Type),
+			Shard:       op.Shard,
+			Description: op.Description,
+		}
+		if op.Namespace != "" {
+			oi.Namespace = &op.Namespace
+		}
+		if op.Server != nil {
+			oi.Server = &op.Server.Internal
+		}
+		if !op.StartTime.IsZero() {
+			startTimestamp := uint64(op.StartTime.UnixMilli())
+			oi.StartTimestamp = &startTimestamp
+		}
+		res.Operations = append(res.Operations, oi)
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) TransferLeader(ctx context.Context, req *proto.TransferLeaderRequest) (*proto.TransferLeaderResponse, error) {
+	s.log.Info(
+		"Received transfer leader request",
+		slog.String("namespace", req.Namespace),
+		slog.Int64("shard", req.Shard),
+		slog.String("server", req.Server),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.TransferLeader(req.Namespace, req.Shard, req.Server)
+	s.audit(ctx, c, impl.AuditRecord{Action: "transfer-leader", Namespace: req.Namespace, Shard: &req.Shard, Server: req.Server}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.TransferLeaderResponse{}, nil
+}
+
+func (s *adminRpcServer) MergeShard(ctx context.Context, req *proto.MergeShardRequest) (*proto.MergeShardResponse, error) {
+	s.log.Info(
+		"Received merge shard request",
+		slog.String("namespace", req.Namespace),
+		slog.Int64("shard", req.Shard),
+		slog.Int64("into-shard", req.IntoShard),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.MergeShard(req.Namespace, req.Shard, req.IntoShard)
+	s.audit(ctx, c, impl.AuditRecord{Action: "merge-shard", Namespace: req.Namespace, Shard: &req.Shard,
+		Details: fmt.Sprintf("into shard %d", req.IntoShard)}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.MergeShardResponse{}, nil
+}
+
+func (s *adminRpcServer) ListSessions(_ context.Context, req *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	sessions, err := c.ListSessions(req.Namespace, req.Shard)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	res := &proto.ListSessionsResponse{}
+	for _, si := range sessions {
+		res.Sessions = append(res.Sessions, &proto.SessionInfo{
+			Shard:                  si.Shard,
+			SessionId:              si.SessionId,
+			Leader:                 si.Leader.Internal,
+			ClientIdentity:         si.ClientIdentity,
+			TimeoutMs:              uint32(si.Timeout.Milliseconds()),
+			CreatedTimestamp:       uint64(si.CreatedTime.UnixMilli()),
+			LastHeartbeatTimestamp: uint64(si.LastHeartbeat.UnixMilli()),
+			EphemeralKeys:          si.EphemeralKeys,
+		})
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) ForceCloseSession(ctx context.Context, req *proto.ForceCloseSessionRequest) (*proto.ForceCloseSessionResponse, error) {
+	s.log.Info(
+		"Received force close session request",
+		slog.String("namespace", req.Namespace),
+		slog.Int64("shard", req.Shard),
+		slog.Int64("session-id", req.SessionId),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.ForceCloseSession(req.Namespace, req.Shard, req.SessionId)
+	s.audit(ctx, c, impl.AuditRecord{
+		Action:    "force-close-session",
+		Namespace: req.Namespace,
+		Shard:     &req.Shard,
+		Details:   fmt.Sprintf("session %d", req.SessionId),
+	}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.ForceCloseSessionResponse{}, nil
+}
+
+func (s *adminRpcServer) SetClusterFreeze(ctx context.Context, req *proto.SetClusterFreezeRequest) (*proto.SetClusterFreezeResponse, error) {
+	s.log.Info(
+		"Received set cluster freeze request",
+		slog.Bool("freeze", req.Freeze),
+		slog.String("reason", req.Reason),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.SetClusterFreeze(req.Freeze, req.Reason)
+	s.audit(ctx, c, impl.AuditRecord{Action: "set-cluster-freeze", Details: fmt.Sprintf("freeze: %t", req.Freeze), Reason: req.Reason}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.SetClusterFreezeResponse{}, nil
+}
+
+func (s *adminRpcServer) SetKeyPrefixACL(ctx context.Context, req *proto.SetKeyPrefixACLRequest) (*proto.SetKeyPrefixACLResponse, error) {
+	if req.Acl == nil {
+		return nil, status.Error(codes.InvalidArgument, "the acl is not set")
+	}
+
+	s.log.Info(
+		"Received set key prefix acl request",
+		slog.String("namespace", req.Namespace),
+		slog.String("principal", req.Acl.Principal),
+		slog.String("prefix", req.Acl.Prefix),
+		slog.Any("permissions", req.Acl.Permissions),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	acl := model.KeyPrefixACL{
+		Principal: req.Acl.Principal,
+		Prefix:    req.Acl.Prefix,
+	}
+	for _, p := range req.Acl.Permissions {
+		acl.Permissions = append(acl.Permissions, model.Permission(p))
+	}
+
+	err := c.SetKeyPrefixACL(req.Namespace, acl)
+	s.audit(ctx, c, impl.AuditRecord{
+		Action:    "set-key-prefix-acl",
+		Namespace: req.Namespace,
+		Details:   fmt.Sprintf("principal %s, prefix %s, permissions %v", acl.Principal, acl.Prefix, acl.Permissions),
+	}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.SetKeyPrefixACLResponse{}, nil
+}
+
+func (s *adminRpcServer) RemoveKeyPrefixACL(ctx context.Context, req *proto.RemoveKeyPrefixACLRequest) (*proto.RemoveKeyPrefixACLResponse, error) {
+	s.log.Info(
+		"Received remove key prefix acl request",
+		slog.String("namespace", req.Namespace),
+		slog.String("principal", req.Principal),
+		slog.String("prefix", req.Prefix),
+	)
+
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	err := c.RemoveKeyPrefixACL(req.Namespace, req.Principal, req.Prefix)
+	s.audit(ctx, c, impl.AuditRecord{
+		Action:    "remove-key-prefix-acl",
+		Namespace: req.Namespace,
+		Details:   fmt.Sprintf("principal %s, prefix %s", req.Principal, req.Prefix),
+	}, err)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+	return &proto.RemoveKeyPrefixACLResponse{}, nil
+}
+
+func (s *adminRpcServer) ListKeyPrefixACLs(_ context.Context, req *proto.ListKeyPrefixACLsRequest) (*proto.ListKeyPrefixACLsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	acls, err := c.ListKeyPrefixACLs(req.Namespace)
+	if err != nil {
+		return nil, toAdminStatusError(err)
+	}
+
+	res := &proto.ListKeyPrefixACLsResponse{}
+	for _, acl := range acls {
+		info := &proto.KeyPrefixACL{
+			Principal: acl.Principal,
+			Prefix:    acl.Prefix,
+		}
+		for _, p := range acl.Permissions {
+			info.Permissions = append(info.Permissions, string(p))
+		}
+		res.Acls = append(res.Acls, info)
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) ListEvents(_ context.Context, req *proto.ListEventsRequest) (*proto.ListEventsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	events := c.ListEvents()
+	if req.Limit > 0 && len(events) > int(req.Limit) {
+		events = events[len(events)-int(req.Limit):]
+	}
+
+	res := &proto.ListEventsResponse{}
+	for _, e := range events {
+		res.Events = append(res.Events, toEventInfo(e))
+	}
+	return res, nil
+}
+
+func (s *adminRpcServer) WatchEvents(req *proto.WatchEventsRequest, stream proto.OxiaAdmin_WatchEventsServer) error {
+	c := s.coordinator()
+	if c == nil {
+		return common.ErrorNotLeaderCoordinator
+	}
+
+	recent, ch, stop := c.WatchEvents(req.IncludeRecent)
+	defer stop()
+
+	for _, e := range recent {
+		if err := stream.Send(toEventInfo(e)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case e, ok := <-ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "the events stream was interrupted by the coordinator")
+			}
+			if err := stream.Send(toEventInfo(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *adminRpcServer) StreamCoordinatorState(_ *proto.StreamCoordinatorStateRequest, stream proto.OxiaAdmin_StreamCoordinatorStateServer) error {
+	c := s.coordinator()
+	if c == nil {
+		return common.ErrorNotLeaderCoordinator
+	}
+
+	s.log.Info(
+		"Streaming the coordinator state to a standby coordinator",
+		slog.String("peer", callerIdentity(stream.Context())),
+	)
+
+	// Most of the state changes are recorded as events, while the shard
+	// loads are refreshed periodically
+	_, ch, stop := c.WatchEvents(false)
+	defer stop()
+
+	ticker := time.NewTicker(stateStreamInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		state, err := json.Marshal(c.State())
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if !bytes.Equal(state, last) {
+			if err := stream.Send(&proto.CoordinatorStateSnapshot{State: state}); err != nil {
+				return err
+			}
+			last = state
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case _, ok := <-ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "the state stream was interrupted by the coordinator")
+			}
+			drainEvents(ch)
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// Consume the events that are already queued, to send a single snapshot
+// after a burst of changes.
+func drainEvents(ch <-chan impl.Event) {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func toEventInfo(e impl.Event) *proto.EventInfo {
+	ei := &proto.EventInfo{
+		Timestamp:   uint64(e.Time.UnixMilli()),
+		Type:        string(e.Type),
+		Shard:       e.Shard,
+		Description: e.Description,
+	}
+	if e.Namespace != "" {
+		ei.Namespace = &e.Namespace
+	}
+	if e.Server != nil {
+		ei.Server = &e.Server.Internal
+	}
+	return ei
+}
+
+func (s *adminRpcServer) ListAuditRecords(_ context.Context, req *proto.ListAuditRecordsRequest) (*proto.ListAuditRecordsResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	records, err := c.ListAuditRecords(int(req.Limit))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &proto.ListAuditRecordsResponse{}
+	for _, r := range records {
+		ri := &proto.AuditRecordInfo{
+			Timestamp: uint64(r.Time.UnixMilli()),
+			Actor:     r.Actor,
+			Action:    r.Action,
+			Shard:     r.Shard,
+			Details:   r.Details,
+			Reason:    r.Reason,
+		}
+		if r.Namespace != "" {
+			ri.Namespace = &r.Namespace
+		}
+		if r.Server != "" {
+			ri.Server = &r.Server
+		}
+		if r.Error != "" {
+			ri.Error = &r.Error
+		}
+		res.Records = append(res.Records, ri)
+	}
+	return res, nil
+}
+
+// Record a change requested through the admin API in the audit log, along
+// with who requested it, why, and whether it was rejected.
+func (*adminRpcServer) audit(ctx context.Context, c impl.Coordinator, record impl.AuditRecord, err error) {
+	record.Actor = callerIdentity(ctx)
+	if record.Reason == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if reason := md.Get(common.MetadataAuditReason); len(reason) > 0 {
+				record.Reason = reason[0]
+			}
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	c.Audit(record)
+}
+
+// The caller is identified by its authenticated principal, or by the common
+// name of its client certificate when using mTLS, and by its address.
+func callerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	if principal, ok := auth.GetPrincipal(ctx); ok && principal != "" {
+		return fmt.Sprintf("%s (%s)", principal, p.Addr)
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		if cn := tlsInfo.State.PeerCertificates[0].Subject.CommonName; cn != "" {
+			return fmt.Sprintf("%s (%s)", cn, p.Addr)
+		}
+	}
+	return p.Addr.String()
+}
+
+func (s *adminRpcServer) ExportClusterStatus(context.Context, *proto.ExportClusterStatusRequest) (*proto.ExportClusterStatusResponse, error) {
+	c := s.coordinator()
+	if c == nil {
+		return nil, common.ErrorNotLeaderCoordinator
+	}
+
+	cs := c.ClusterStatus()
+	export, err := impl.ExportClusterStatus(&cs)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ExportClusterStatusResponse{
+		ClusterStatus: export,
+	}, nil
+}
+
+func toAdminStatusError(err error) error {
+	switch {
+	case errors.Is(err, impl.ErrNamespaceNotFound):
+		return common.ErrorNamespaceNotFound
+	case errors.Is(err, impl.ErrNamespaceAlreadyExists):
+		return common.ErrorNamespaceAlreadyExists
+	case errors.Is(err, impl.ErrServerNotFound), errors.Is(err, impl.ErrShardNotFound),
+		errors.Is(err, impl.ErrPlanNotFound), errors.Is(err, impl.ErrKeyPrefixACLNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrNamespaceNotDynamic), errors.Is(err, impl.ErrNotEnoughServers),
+		errors.Is(err, impl.ErrServersDraining), errors.Is(err, impl.ErrInvalidLeaderTransfer),
+		errors.Is(err, impl.ErrClusterFrozen), errors.Is(err, impl.ErrServerStillRunning),
+		errors.Is(err, impl.ErrInvalidReplacement), errors.Is(err, impl.ErrPlanNotPending),
+		errors.Is(err, impl.ErrPlanInProgress), errors.Is(err, impl.ErrPlanOutdated),
+		errors.Is(err, impl.ErrShardHasNoLeader), errors.Is(err, impl.ErrShardNotMergeable),
+		errors.Is(err, impl.ErrShardMergeInProgress):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, impl.ErrLeaderNotTransferred), errors.Is(err, impl.ErrMergedKeyExists):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, impl.ErrTenantQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, impl.ErrInvalidNamespaceConfig), errors.Is(err, impl.ErrAntiAffinityNotSatisfied),
+		errors.Is(err, impl.ErrPlacementNotSatisfied), errors.Is(err, impl.ErrInvalidKeyPrefixACL),
+		errors.Is(err, impl.ErrShardsNotAdjacent):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
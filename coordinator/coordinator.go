@@ -15,17 +15,27 @@
 package coordinator
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"sync"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"go.uber.org/multierr"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/container"
 	"github.com/streamnative/oxia/common/metrics"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/coordinator/impl"
 	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
 )
 
 type Config struct {
@@ -34,12 +44,49 @@ type Config struct {
 	PeerTLS                          *tls.Config
 	ServerTLS                        *tls.Config
 	MetricsServiceAddr               string
+	InternalIPFilter                 *security.IPFilter
+	MetricsIPFilter                  *security.IPFilter
 	MetadataProviderImpl             MetadataProviderImpl
 	K8SMetadataNamespace             string
 	K8SMetadataConfigMapName         string
 	FileMetadataPath                 string
+	EtcdEndpoints                    []string
+	EtcdTLS                          *tls.Config
+	EtcdUsername                     string
+	EtcdPassword                     string
+	OxiaMetadataServiceAddress       string
+	OxiaMetadataNamespace            string
+	OxiaMetadataTLS                  *tls.Config
+	OxiaMetadataToken                string
+	MetadataKey                      string
 	ClusterConfigProvider            func() (model.ClusterConfig, error)
 	ClusterConfigChangeNotifications chan any
+
+	// LeaderElection allows to run multiple coordinator replicas, with only
+	// one of them managing the cluster at any given time
+	LeaderElection bool
+
+	// Peers are the internal addresses of the other coordinator replicas.
+	// While on standby, a replica mirrors the in-memory state of the active
+	// one, to take over without having to rebuild it.
+	Peers []string
+
+	// ImportClusterStatusPath is a cluster status export, used to bootstrap
+	// the coordinator when the metadata provider doesn't hold any status,
+	// eg: after the loss of the metadata store
+	ImportClusterStatusPath string
+
+	// AuditLogPath is the file where the mutations of the cluster are
+	// appended. When not set, only the latest ones are kept in memory.
+	AuditLogPath string
+
+	// AdminServiceAddr is where the admin API is served, with its own TLS,
+	// authentication and address filter. When not set, the admin API is
+	// served on the internal port.
+	AdminServiceAddr string
+	AdminTLS         *tls.Config
+	AdminIPFilter    *security.IPFilter
+	AdminAuthOptions auth.Options
 }
 
 type MetadataProviderImpl string
@@ -50,11 +97,11 @@ func (m *MetadataProviderImpl) String() string {
 
 func (m *MetadataProviderImpl) Set(s string) error {
 	switch s {
-	case "memory", "configmap", "file":
+	case "memory", "configmap", "file", "etcd", "oxia":
 		*m = MetadataProviderImpl(s)
 		return nil
 	default:
-		return errors.New(`must be one of "memory", "configmap" or "file"`)
+		return errors.New(`must be one of "memory", "configmap", "file", "etcd" or "oxia"`)
 	}
 }
 
@@ -66,6 +113,8 @@ var (
 	Memory    MetadataProviderImpl = "memory"
 	Configmap MetadataProviderImpl = "configmap"
 	File      MetadataProviderImpl = "file"
+	Etcd      MetadataProviderImpl = "etcd"
+	Oxia      MetadataProviderImpl = "oxia"
 )
 
 func NewConfig() Config {
@@ -76,11 +125,27 @@ func NewConfig() Config {
 	}
 }
 
+// The max time since the mirroring of the active coordinator state was
+// interrupted, for the state to be used when taking over.
+const maxStandbyStateAge = time.Minute
+
 type Coordinator struct {
+	sync.Mutex
 	coordinator impl.Coordinator
 	clientPool  common.ClientPool
 	rpcServer   *rpcServer
+	adminServer *rpcServer
 	metrics     *metrics.PrometheusMetrics
+	auditLog    impl.AuditLog
+
+	// The state mirrored from the active coordinator, while on standby
+	standbyState *impl.CoordinatorState
+	// When the mirroring was interrupted, zero while it's streaming
+	standbyStateLost time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan any
 }
 
 func New(config Config) (*Coordinator, error) {
@@ -91,41 +156,328 @@ func New(config Config) (*Coordinator, error) {
 
 	s := &Coordinator{
 		clientPool: common.NewClientPool(config.PeerTLS, nil),
+		done:       make(chan any),
 	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 
 	var metadataProvider impl.MetadataProvider
+	var leaderElection impl.LeaderElection
 	switch config.MetadataProviderImpl {
 	case Memory:
 		metadataProvider = impl.NewMetadataProviderMemory()
 	case File:
 		metadataProvider = impl.NewMetadataProviderFile(config.FileMetadataPath)
+		leaderElection = impl.NewFileLeaderElection(config.FileMetadataPath + ".leader")
 	case Configmap:
 		k8sConfig := impl.NewK8SClientConfig()
-		metadataProvider = impl.NewMetadataProviderConfigMap(impl.NewK8SClientset(k8sConfig),
+		clientset := impl.NewK8SClientset(k8sConfig)
+		metadataProvider = impl.NewMetadataProviderConfigMap(clientset,
 			config.K8SMetadataNamespace, config.K8SMetadataConfigMapName)
+		leaderElection = impl.NewK8SLeaseLeaderElection(clientset,
+			config.K8SMetadataNamespace, config.K8SMetadataConfigMapName+"-leader", getIdentity())
+	case Etcd:
+		var err error
+		if metadataProvider, err = impl.NewMetadataProviderEtcd(config.EtcdEndpoints, config.MetadataKey,
+			config.EtcdTLS, config.EtcdUsername, config.EtcdPassword); err != nil {
+			return nil, err
+		}
+		leaderElection = impl.NewMetadataLeaderElection(metadataProvider, config.MetadataKey+"-leader", getIdentity())
+	case Oxia:
+		var err error
+		if metadataProvider, err = impl.NewMetadataProviderOxia(config.OxiaMetadataServiceAddress,
+			config.OxiaMetadataNamespace, config.MetadataKey, config.OxiaMetadataTLS, config.OxiaMetadataToken); err != nil {
+			return nil, err
+		}
+		leaderElection = impl.NewMetadataLeaderElection(metadataProvider, config.MetadataKey+"-leader", getIdentity())
+	}
+
+	var err error
+	var opts []impl.CoordinatorOption
+	if config.AuditLogPath != "" {
+		if s.auditLog, err = impl.NewFileAuditLog(config.AuditLogPath); err != nil {
+			return nil, err
+		}
+		opts = append(opts, impl.WithAuditLog(s.auditLog))
 	}
 
 	rpcClient := impl.NewRpcProvider(s.clientPool)
+	newCoordinator := func(standbyState *impl.CoordinatorState) (impl.Coordinator, error) {
+		if config.ImportClusterStatusPath != "" {
+			if err := importClusterStatus(metadataProvider, config.ImportClusterStatusPath); err != nil {
+				return nil, err
+			}
+		}
+		return impl.NewCoordinator(metadataProvider, config.ClusterConfigProvider, config.ClusterConfigChangeNotifications, rpcClient,
+			append(opts, impl.WithStandbyState(standbyState))...)
+	}
 
-	var err error
-	if s.coordinator, err = impl.NewCoordinator(metadataProvider, config.ClusterConfigProvider, config.ClusterConfigChangeNotifications, rpcClient); err != nil {
-		return nil, err
+	if config.LeaderElection {
+		if leaderElection == nil {
+			return nil, fmt.Errorf("leader election is not supported with the %s metadata provider", config.MetadataProviderImpl)
+		}
+
+		go common.DoWithLabels(
+			s.ctx,
+			map[string]string{
+				"oxia": "coordinator-leader-election",
+			},
+			func() { s.runLeaderElection(leaderElection, newCoordinator) },
+		)
+
+		if len(config.Peers) > 0 {
+			go common.DoWithLabels(
+				s.ctx,
+				map[string]string{
+					"oxia": "coordinator-standby",
+				},
+				func() { s.runStandby(config.Peers) },
+			)
+		}
+	} else {
+		close(s.done)
+		if s.coordinator, err = newCoordinator(nil); err != nil {
+			return nil, err
+		}
 	}
 
-	if s.rpcServer, err = newRpcServer(config.InternalServiceAddr, config.ServerTLS); err != nil {
-		return nil, err
+	admin := newAdminRpcServer(s.getCoordinator)
+	if config.AdminServiceAddr == "" {
+		if s.rpcServer, err = newRpcServer(container.ServerCoordinator, config.InternalServiceAddr, config.ServerTLS,
+			config.InternalIPFilter, &auth.Disabled, admin); err != nil {
+			return nil, err
+		}
+	} else {
+		// Only the standby coordinators are served on the internal port, so
+		// that the admin operations can't be reached through it
+		if s.rpcServer, err = newRpcServer(container.ServerCoordinator, config.InternalServiceAddr, config.ServerTLS,
+			config.InternalIPFilter, &auth.Disabled, newPeerRpcServer(admin)); err != nil {
+			return nil, err
+		}
+		if s.adminServer, err = newRpcServer(container.ServerCoordinatorAdmin, config.AdminServiceAddr, config.AdminTLS,
+			config.AdminIPFilter, &config.AdminAuthOptions, admin); err != nil {
+			return nil, err
+		}
 	}
 
-	if s.metrics, err = metrics.Start(config.MetricsServiceAddr); err != nil {
+	if s.metrics, err = metrics.Start(config.MetricsServiceAddr, config.MetricsIPFilter); err != nil {
 		return nil, err
 	}
 
 	return s, nil
 }
 
+func importClusterStatus(metadataProvider impl.MetadataProvider, path string) error {
+	export, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	imported, err := impl.ImportClusterStatus(metadataProvider, export)
+	if err != nil {
+		return err
+	}
+	if !imported {
+		slog.Warn(
+			"The cluster status export was not imported, since the metadata is already initialized",
+			slog.String("path", path),
+		)
+	}
+	return nil
+}
+
+// Keep campaigning for the leadership, and manage the cluster while being the leader.
+func (s *Coordinator) runLeaderElection(leaderElection impl.LeaderElection, newCoordinator func(*impl.CoordinatorState) (impl.Coordinator, error)) {
+	defer close(s.done)
+
+	for s.ctx.Err() == nil {
+		leaderElection.Run(s.ctx, func(ctx context.Context) {
+			s.lead(ctx, newCoordinator)
+		})
+	}
+}
+
+func (s *Coordinator) lead(ctx context.Context, newCoordinator func(*impl.CoordinatorState) (impl.Coordinator, error)) {
+	standbyState := s.takeStandbyState()
+
+	var c impl.Coordinator
+	err := backoff.RetryNotify(func() (err error) {
+		c, err = newCoordinator(standbyState)
+		return err
+	}, common.NewBackOff(ctx), func(err error, duration time.Duration) {
+		slog.Warn(
+			"Failed to start the coordinator, retrying later",
+			slog.Any("error", err),
+			slog.Duration("retry-after", duration),
+		)
+	})
+	if err != nil {
+		// The leadership was lost before being able to start
+		return
+	}
+
+	s.Lock()
+	s.coordinator = c
+	s.Unlock()
+
+	<-ctx.Done()
+
+	// Another coordinator replica might take over from here. The metadata
+	// versioning ensures that any update that we're still doing is rejected.
+	slog.Info("Stopping the coordinator after losing the leadership")
+	s.Lock()
+	s.coordinator = nil
+	s.Unlock()
+
+	if err := c.Close(); err != nil {
+		slog.Warn(
+			"Failed to close the coordinator",
+			slog.Any("error", err),
+		)
+	}
+}
+
+// Keep mirroring the state of the active coordinator, while this replica is
+// on standby. The active coordinator is found by trying all the peers.
+func (s *Coordinator) runStandby(peers []string) {
+	backOff := common.NewBackOff(s.ctx)
+	for s.ctx.Err() == nil {
+		if s.getCoordinator() != nil {
+			// This replica is the active one
+			backOff.Reset()
+			select {
+			case <-s.ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var err error
+		for _, peer := range peers {
+			if err = s.mirrorState(peer); err == nil {
+				backOff.Reset()
+				break
+			}
+		}
+
+		s.Lock()
+		if s.standbyStateLost.IsZero() {
+			s.standbyStateLost = time.Now()
+		}
+		s.Unlock()
+
+		if s.ctx.Err() != nil {
+			return
+		}
+		select {
+		case <-s.ctx.Done():
+		case <-time.After(backOff.NextBackOff()):
+		}
+	}
+}
+
+// Mirror the state streamed by the peer, until the stream is interrupted. A
+// nil error means that the state was received before the interruption.
+func (s *Coordinator) mirrorState(peer string) error {
+	rpc, err := s.clientPool.GetAdminRpc(peer)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	stream, err := rpc.StreamCoordinatorState(ctx, &proto.StreamCoordinatorStateRequest{})
+	if err != nil {
+		return err
+	}
+
+	mirrored := false
+	for {
+		snapshot, err := stream.Recv()
+		if err != nil {
+			if mirrored {
+				slog.Info(
+					"Stopped mirroring the state of the active coordinator",
+					slog.String("peer", peer),
+					slog.Any("error", err),
+				)
+				return nil
+			}
+			return err
+		}
+
+		state := &impl.CoordinatorState{}
+		if err := json.Unmarshal(snapshot.State, state); err != nil {
+			return err
+		}
+
+		if !mirrored {
+			slog.Info(
+				"Mirroring the state of the active coordinator",
+				slog.String("peer", peer),
+			)
+			mirrored = true
+		}
+
+		s.Lock()
+		s.standbyState = state
+		s.standbyStateLost = time.Time{}
+		s.Unlock()
+	}
+}
+
+// Returns the mirrored state to take over from, if it's recent enough.
+func (s *Coordinator) takeStandbyState() *impl.CoordinatorState {
+	s.Lock()
+	defer s.Unlock()
+
+	state := s.standbyState
+	s.standbyState = nil
+	if state != nil && !s.standbyStateLost.IsZero() && time.Since(s.standbyStateLost) > maxStandbyStateAge {
+		slog.Warn(
+			"Discarding the mirrored coordinator state, since it's outdated",
+			slog.Time("mirroring-interrupted-at", s.standbyStateLost),
+		)
+		return nil
+	}
+	return state
+}
+
+// Returns the coordinator, if this replica is currently the leader.
+func (s *Coordinator) getCoordinator() impl.Coordinator {
+	s.Lock()
+	defer s.Unlock()
+	return s.coordinator
+}
+
+func getIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Sprintf("coordinator-%d", os.Getpid())
+	}
+	return hostname
+}
+
 func (s *Coordinator) Close() error {
+	s.cancel()
+	<-s.done
+
+	var err error
+	s.Lock()
+	if s.coordinator != nil {
+		err = s.coordinator.Close()
+	}
+	s.Unlock()
+
+	if s.auditLog != nil {
+		err = multierr.Append(err, s.auditLog.Close())
+	}
+	if s.adminServer != nil {
+		err = multierr.Append(err, s.adminServer.Close())
+	}
+
 	return multierr.Combine(
-		s.coordinator.Close(),
+		err,
 		s.rpcServer.Close(),
 		s.clientPool.Close(),
 		s.metrics.Close(),
@@ -24,6 +24,8 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/streamnative/oxia/common/container"
+	"github.com/streamnative/oxia/common/security"
+	"github.com/streamnative/oxia/proto"
 )
 
 type rpcServer struct {
@@ -31,15 +33,17 @@ type rpcServer struct {
 	healthServer *health.Server
 }
 
-func newRpcServer(bindAddress string, tlsConf *tls.Config) (*rpcServer, error) {
+func newRpcServer(name string, bindAddress string, tlsConf *tls.Config, ipFilter *security.IPFilter,
+	authOptions *auth.Options, adminServer proto.OxiaAdminServer) (*rpcServer, error) {
 	server := &rpcServer{
 		healthServer: health.NewServer(),
 	}
 
 	var err error
-	server.grpcServer, err = container.Default.StartGrpcServer("coordinator", bindAddress, func(registrar grpc.ServiceRegistrar) {
+	server.grpcServer, err = container.Default.StartGrpcServer(name, bindAddress, func(registrar grpc.ServiceRegistrar) {
 		grpc_health_v1.RegisterHealthServer(registrar, server.healthServer)
-	}, tlsConf, &auth.Disabled)
+		proto.RegisterOxiaAdminServer(registrar, adminServer)
+	}, tlsConf, ipFilter, authOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +51,10 @@ func newRpcServer(bindAddress string, tlsConf *tls.Config) (*rpcServer, error) {
 	return server, nil
 }
 
+func (s *rpcServer) Port() int {
+	return s.grpcServer.Port()
+}
+
 func (s *rpcServer) Close() error {
 	s.healthServer.Shutdown()
 	return s.grpcServer.Close()
@@ -0,0 +1,81 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/impl"
+	"github.com/streamnative/oxia/oxia/auth"
+	"github.com/streamnative/oxia/proto"
+	serverauth "github.com/streamnative/oxia/server/auth"
+)
+
+func newAdminClient(t *testing.T, port int, options ...grpc.DialOption) proto.OxiaAdminClient {
+	t.Helper()
+
+	options = append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	cnx, err := grpc.NewClient(fmt.Sprintf("localhost:%d", port), options...)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = cnx.Close() })
+	return proto.NewOxiaAdminClient(cnx)
+}
+
+func TestRpcServer_DedicatedAdminListener(t *testing.T) {
+	tokens := filepath.Join(t.TempDir(), "tokens")
+	assert.NoError(t, os.WriteFile(tokens, []byte("operator:token-1\n"), 0600))
+
+	admin := newAdminRpcServer(func() impl.Coordinator { return nil })
+	internal, err := newRpcServer("coordinator", "localhost:0", nil, nil, &serverauth.Disabled, newPeerRpcServer(admin))
+	assert.NoError(t, err)
+	defer internal.Close()
+
+	adminServer, err := newRpcServer("coordinator-admin", "localhost:0", nil, nil, &serverauth.Options{
+		ProviderName:   serverauth.ProviderTokenFile,
+		ProviderParams: `{"path":"` + tokens + `"}`,
+	}, admin)
+	assert.NoError(t, err)
+	defer adminServer.Close()
+
+	// The admin operations are not reachable through the internal port
+	_, err = newAdminClient(t, internal.Port()).CreateNamespace(context.Background(), &proto.CreateNamespaceRequest{Namespace: "ns"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	// The state is still streamed to the standby coordinators
+	stream, err := newAdminClient(t, internal.Port()).StreamCoordinatorState(context.Background(), &proto.StreamCoordinatorStateRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, status.Code(common.ErrorNotLeaderCoordinator), status.Code(err))
+
+	// The admin port requires the clients to authenticate
+	_, err = newAdminClient(t, adminServer.Port()).CreateNamespace(context.Background(), &proto.CreateNamespaceRequest{Namespace: "ns"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	client := newAdminClient(t, adminServer.Port(),
+		grpc.WithPerRPCCredentials(auth.NewTokenAuthenticationWithToken("token-1", false)))
+	_, err = client.CreateNamespace(context.Background(), &proto.CreateNamespaceRequest{Namespace: "ns"})
+	assert.Equal(t, status.Code(common.ErrorNotLeaderCoordinator), status.Code(err))
+}
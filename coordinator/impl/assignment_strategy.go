@@ -0,0 +1,263 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+const defaultZoneLabel = "zone"
+
+// AssignmentStrategy decides on which servers the replicas of the shards are
+// placed. The strategies must honor the anti-affinity rules and the placement
+// constraints of the namespaces, which can be checked through the
+// AssignmentContext.
+type AssignmentStrategy interface {
+	// SelectEnsemble picks the servers for a new shard of the namespace.
+	SelectEnsemble(ac *AssignmentContext, namespace string, servers []model.ServerAddress,
+		replicationFactor uint32) ([]model.ServerAddress, error)
+
+	// SelectServer picks one of the candidates to add a replica of a shard
+	// with the given ensemble. None of the candidates is part of the ensemble.
+	SelectServer(ac *AssignmentContext, namespace string, ensemble []model.ServerAddress,
+		candidates []model.ServerAddress) (model.ServerAddress, bool)
+}
+
+// AssignmentStrategyFactory creates the strategy for the given cluster config.
+type AssignmentStrategyFactory func(config *model.ClusterConfig) AssignmentStrategy
+
+var assignmentStrategies = map[model.AssignmentStrategy]AssignmentStrategyFactory{
+	model.AssignmentStrategyUniform: func(*model.ClusterConfig) AssignmentStrategy {
+		return &uniformStrategy{}
+	},
+	model.AssignmentStrategyZoneAware: func(config *model.ClusterConfig) AssignmentStrategy {
+		zoneLabel := defaultZoneLabel
+		if config.Assignment != nil && config.Assignment.ZoneLabel != "" {
+			zoneLabel = config.Assignment.ZoneLabel
+		}
+		return &zoneAwareStrategy{zoneLabel: zoneLabel}
+	},
+	model.AssignmentStrategyLoadAware: func(*model.ClusterConfig) AssignmentStrategy {
+		return &loadAwareStrategy{}
+	},
+}
+
+// RegisterAssignmentStrategy makes a custom strategy available to be
+// selected in the cluster config.
+func RegisterAssignmentStrategy(name model.AssignmentStrategy, factory AssignmentStrategyFactory) {
+	assignmentStrategies[name] = factory
+}
+
+func getAssignmentStrategy(config *model.ClusterConfig) (AssignmentStrategyFactory, bool) {
+	name := model.AssignmentStrategyUniform
+	if config.Assignment != nil && config.Assignment.Strategy != "" {
+		name = config.Assignment.Strategy
+	}
+	factory, ok := assignmentStrategies[name]
+	return factory, ok
+}
+
+// AssignmentContext gives the strategies access to the current placement of
+// the replicas, which is updated as the strategies make their choices.
+type AssignmentContext struct {
+	// StartIdx is the position in the servers list where the round-robin
+	// placement of the new shards resumes
+	StartIdx uint32
+
+	policy          *placementPolicy
+	shardsPerServer map[model.ServerAddress]common.Set[int64]
+}
+
+func (p *placementPolicy) newAssignmentContext(shardsPerServer map[model.ServerAddress]common.Set[int64]) *AssignmentContext {
+	return &AssignmentContext{
+		policy:          p,
+		shardsPerServer: shardsPerServer,
+	}
+}
+
+// CanPlace checks that a replica can be placed on the candidate server,
+// next to the servers of the ensemble, without violating the anti-affinity
+// rules and the placement constraints of the namespace. With strictOnly, the
+// relaxed anti-affinity rules are ignored.
+func (ac *AssignmentContext) CanPlace(namespace string, ensemble []model.ServerAddress,
+	candidate model.ServerAddress, strictOnly bool) bool {
+	return ac.policy.canPlace(namespace, ensemble, candidate, strictOnly)
+}
+
+// Labels returns the labels of the server.
+func (ac *AssignmentContext) Labels(server model.ServerAddress) map[string]string {
+	return ac.policy.serverMetadata[server.Internal].Labels
+}
+
+// Replicas returns the number of shard replicas on the server.
+func (ac *AssignmentContext) Replicas(server model.ServerAddress) int {
+	if shards, ok := ac.shardsPerServer[server]; ok {
+		return shards.Count()
+	}
+	return 0
+}
+
+// Load returns the sum of the load scores of the shards with a replica on
+// the server. The shards whose load is not known yet don't contribute.
+func (ac *AssignmentContext) Load(server model.ServerAddress) float64 {
+	shards, ok := ac.shardsPerServer[server]
+	if !ok {
+		return 0
+	}
+
+	load := 0.0
+	for _, shard := range shards.GetSorted() {
+		load += ac.policy.shardScores[shard]
+	}
+	return load
+}
+
+func (ac *AssignmentContext) addReplicas(shard int64, ensemble []model.ServerAddress) {
+	for _, sa := range ensemble {
+		if shards, ok := ac.shardsPerServer[sa]; ok {
+			shards.Add(shard)
+		}
+	}
+}
+
+// Pick the first of the ordered candidates that can be placed next to the
+// ensemble, trying first to honor all the anti-affinity rules, and then
+// only the strict ones.
+func selectFirstPlaceable(ac *AssignmentContext, namespace string, ensemble []model.ServerAddress,
+	ordered []model.ServerAddress) (model.ServerAddress, bool) {
+	for _, strictOnly := range []bool{false, true} {
+		for _, candidate := range ordered {
+			if ac.CanPlace(namespace, ensemble, candidate, strictOnly) {
+				return candidate, true
+			}
+		}
+	}
+	return model.ServerAddress{}, false
+}
+
+// Build the ensemble one server at a time, so that each choice takes into
+// account the servers already picked.
+func selectEnsembleByServer(strategy AssignmentStrategy, ac *AssignmentContext, namespace string,
+	servers []model.ServerAddress, replicationFactor uint32) ([]model.ServerAddress, error) {
+	if err := ac.policy.checkEligibleServers(namespace, servers, replicationFactor); err != nil {
+		return nil, err
+	}
+
+	res := make([]model.ServerAddress, 0, replicationFactor)
+	for len(res) < int(replicationFactor) {
+		candidates := make([]model.ServerAddress, 0, len(servers))
+		for _, sa := range servers {
+			if !listContains(res, sa) {
+				candidates = append(candidates, sa)
+			}
+		}
+
+		sa, ok := strategy.SelectServer(ac, namespace, res, candidates)
+		if !ok {
+			return nil, errors.Wrapf(ErrAntiAffinityNotSatisfied, "namespace %s", namespace)
+		}
+		res = append(res, sa)
+	}
+	return res, nil
+}
+
+// uniformStrategy places the new shards in round-robin across the servers,
+// and adds the new replicas on the servers with the fewest of them.
+type uniformStrategy struct{}
+
+func (*uniformStrategy) SelectEnsemble(ac *AssignmentContext, namespace string, servers []model.ServerAddress,
+	replicationFactor uint32) ([]model.ServerAddress, error) {
+	return ac.policy.selectEnsemble(namespace, servers, ac.StartIdx, replicationFactor)
+}
+
+func (*uniformStrategy) SelectServer(ac *AssignmentContext, namespace string, ensemble []model.ServerAddress,
+	candidates []model.ServerAddress) (model.ServerAddress, bool) {
+	rankings := getServerRanking(ac.shardsPerServer)
+
+	// Start from the least loaded server
+	ordered := make([]model.ServerAddress, 0, len(candidates))
+	for i := len(rankings) - 1; i >= 0; i-- {
+		if listContains(candidates, rankings[i].Addr) {
+			ordered = append(ordered, rankings[i].Addr)
+		}
+	}
+	return selectFirstPlaceable(ac, namespace, ensemble, ordered)
+}
+
+// zoneAwareStrategy prefers the servers in the zones with the fewest members
+// of the ensemble, and then the servers with the fewest replicas. The
+// servers without the zone label are considered to be all in the same zone.
+type zoneAwareStrategy struct {
+	zoneLabel string
+}
+
+func (s *zoneAwareStrategy) SelectEnsemble(ac *AssignmentContext, namespace string, servers []model.ServerAddress,
+	replicationFactor uint32) ([]model.ServerAddress, error) {
+	return selectEnsembleByServer(s, ac, namespace, servers, replicationFactor)
+}
+
+func (s *zoneAwareStrategy) SelectServer(ac *AssignmentContext, namespace string, ensemble []model.ServerAddress,
+	candidates []model.ServerAddress) (model.ServerAddress, bool) {
+	membersPerZone := map[string]int{}
+	for _, sa := range ensemble {
+		membersPerZone[ac.Labels(sa)[s.zoneLabel]]++
+	}
+
+	ordered := append([]model.ServerAddress{}, candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		mi, mj := membersPerZone[ac.Labels(ordered[i])[s.zoneLabel]], membersPerZone[ac.Labels(ordered[j])[s.zoneLabel]]
+		if mi != mj {
+			return mi < mj
+		}
+		ri, rj := ac.Replicas(ordered[i]), ac.Replicas(ordered[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ordered[i].Internal < ordered[j].Internal
+	})
+	return selectFirstPlaceable(ac, namespace, ensemble, ordered)
+}
+
+// loadAwareStrategy prefers the servers with the lowest load, and then the
+// servers with the fewest replicas, which is all that is known when the
+// shard loads haven't been sampled yet.
+type loadAwareStrategy struct{}
+
+func (s *loadAwareStrategy) SelectEnsemble(ac *AssignmentContext, namespace string, servers []model.ServerAddress,
+	replicationFactor uint32) ([]model.ServerAddress, error) {
+	return selectEnsembleByServer(s, ac, namespace, servers, replicationFactor)
+}
+
+func (*loadAwareStrategy) SelectServer(ac *AssignmentContext, namespace string, ensemble []model.ServerAddress,
+	candidates []model.ServerAddress) (model.ServerAddress, bool) {
+	ordered := append([]model.ServerAddress{}, candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		li, lj := ac.Load(ordered[i]), ac.Load(ordered[j])
+		if li != lj {
+			return li < lj
+		}
+		ri, rj := ac.Replicas(ordered[i]), ac.Replicas(ordered[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ordered[i].Internal < ordered[j].Internal
+	})
+	return selectFirstPlaceable(ac, namespace, ensemble, ordered)
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestAssignment_ZoneAware(t *testing.T) {
+	newStatus, _, _, err := applyClusterChanges(&model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 2,
+			ReplicationFactor: 3,
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4, s5, s6},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "a", s3: "b", s4: "b", s5: "c", s6: "c",
+		}),
+		Assignment: &model.AssignmentConfig{Strategy: model.AssignmentStrategyZoneAware},
+	}, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// Each shard spans all the zones, and the second shard goes
+	// on the servers that don't have any replica yet
+	shards := newStatus.Namespaces["ns-1"].Shards
+	assert.Equal(t, []model.ServerAddress{s1, s3, s5}, shards[0].Ensemble)
+	assert.Equal(t, []model.ServerAddress{s2, s4, s6}, shards[1].Ensemble)
+}
+
+func TestAssignment_ZoneAwareCustomLabel(t *testing.T) {
+	config := &model.ClusterConfig{
+		Servers: []model.ServerAddress{s1, s2, s3},
+		ServerMetadata: map[string]model.ServerMetadata{
+			s1.Internal: {Labels: map[string]string{"rack": "r1"}},
+			s2.Internal: {Labels: map[string]string{"rack": "r1"}},
+			s3.Internal: {Labels: map[string]string{"rack": "r2"}},
+		},
+		Assignment: &model.AssignmentConfig{Strategy: model.AssignmentStrategyZoneAware, ZoneLabel: "rack"},
+	}
+	status := rfTestStatus(2, map[int64][]model.ServerAddress{
+		0: {s1},
+	}, &s1)
+	shardsPerServer, _ := getShardsPerServer(config.Servers, status)
+
+	// s3 is picked over s2, which has fewer replicas but the same rack as s1
+	to, ok := selectServerToAdd(newPlacementPolicy(config), "ns-1", []model.ServerAddress{s1}, shardsPerServer, config.Servers)
+	assert.True(t, ok)
+	assert.Equal(t, s3, to)
+}
+
+func TestAssignment_LoadAware(t *testing.T) {
+	config := &model.ClusterConfig{
+		Servers:    []model.ServerAddress{s1, s2, s3, s4},
+		Assignment: &model.AssignmentConfig{Strategy: model.AssignmentStrategyLoadAware},
+	}
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1, s2},
+		1: {s3},
+		2: {s3},
+	}, nil)
+
+	// Without loads, the server with the fewest replicas is picked
+	actions := computeReplicationFactorChanges(config, status, config.Servers, nil)
+	assert.Equal(t, EnsembleChangeAction{Namespace: "ns-1", Shard: 0, Add: &s4}, actions[0])
+
+	// s4 has a single replica, but it's the busiest one
+	status.Namespaces["ns-1"].Shards[3] = model.ShardMetadata{Status: model.ShardStatusSteadyState,
+		Ensemble: []model.ServerAddress{s4, s1, s2}}
+	actions = computeReplicationFactorChanges(config, status, config.Servers, map[int64]ShardLoad{
+		0: {WriteOpsRate: 10},
+		1: {WriteOpsRate: 10},
+		2: {WriteOpsRate: 10},
+		3: {WriteOpsRate: 100},
+	})
+	assert.Equal(t, EnsembleChangeAction{Namespace: "ns-1", Shard: 0, Add: &s3}, actions[0])
+}
+
+type firstServerStrategy struct{}
+
+func (s *firstServerStrategy) SelectEnsemble(ac *AssignmentContext, namespace string, servers []model.ServerAddress,
+	replicationFactor uint32) ([]model.ServerAddress, error) {
+	return selectEnsembleByServer(s, ac, namespace, servers, replicationFactor)
+}
+
+func (*firstServerStrategy) SelectServer(_ *AssignmentContext, _ string, _ []model.ServerAddress,
+	candidates []model.ServerAddress) (model.ServerAddress, bool) {
+	if len(candidates) == 0 {
+		return model.ServerAddress{}, false
+	}
+	return candidates[0], true
+}
+
+func TestAssignment_CustomStrategy(t *testing.T) {
+	RegisterAssignmentStrategy("first-server", func(*model.ClusterConfig) AssignmentStrategy {
+		return &firstServerStrategy{}
+	})
+
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 3,
+			ReplicationFactor: 2,
+		}},
+		Servers:    []model.ServerAddress{s1, s2, s3},
+		Assignment: &model.AssignmentConfig{Strategy: "first-server"},
+	}
+	assert.NoError(t, validateClusterConfig(config))
+
+	newStatus, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+	for _, shard := range newStatus.Namespaces["ns-1"].Shards {
+		assert.Equal(t, []model.ServerAddress{s1, s2}, shard.Ensemble)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The actor of the changes that the coordinator decides on its own.
+const AuditActorCoordinator = "coordinator"
+
+// AuditRecord is a mutation of the cluster, with who requested it and why.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Namespace string    `json:"namespace,omitempty"`
+	Shard     *int64    `json:"shard,omitempty"`
+	Server    string    `json:"server,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+
+	// Error is set when the requested change was rejected
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog is an append-only record of the mutations of the cluster.
+type AuditLog interface {
+	io.Closer
+
+	Append(record AuditRecord) error
+
+	// List returns the latest records, from the oldest to the newest. With a
+	// limit of 0, all the records are returned.
+	List(limit int) ([]AuditRecord, error)
+}
+
+type memoryAuditLog struct {
+	sync.Mutex
+	records []AuditRecord
+	size    int
+}
+
+// NewMemoryAuditLog keeps the latest records in memory, up to the given size.
+func NewMemoryAuditLog(size int) AuditLog {
+	return &memoryAuditLog{size: size}
+}
+
+func (l *memoryAuditLog) Append(record AuditRecord) error {
+	l.Lock()
+	defer l.Unlock()
+
+	l.records = append(l.records, withAuditTime(record))
+	if len(l.records) > l.size {
+		l.records = l.records[len(l.records)-l.size:]
+	}
+	return nil
+}
+
+func (l *memoryAuditLog) List(limit int) ([]AuditRecord, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	return append([]AuditRecord{}, latest(l.records, limit)...), nil
+}
+
+func (*memoryAuditLog) Close() error {
+	return nil
+}
+
+type fileAuditLog struct {
+	sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFileAuditLog appends the records to a file, as json objects one per
+// line. Each record is synced to the disk before returning.
+func NewFileAuditLog(path string) (AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the audit log")
+	}
+
+	return &fileAuditLog{
+		path: path,
+		file: file,
+	}, nil
+}
+
+func (l *fileAuditLog) Append(record AuditRecord) error {
+	b, err := json.Marshal(withAuditTime(record))
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if _, err = l.file.Write(append(b, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write the audit log")
+	}
+	return l.file.Sync()
+}
+
+func (l *fileAuditLog) List(limit int) ([]AuditRecord, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the audit log")
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.Wrap(err, "failed to read the audit log")
+		}
+
+		records = append(records, record)
+		if limit > 0 && len(records) > 2*limit {
+			records = append([]AuditRecord{}, latest(records, limit)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the audit log")
+	}
+
+	return latest(records, limit), nil
+}
+
+func (l *fileAuditLog) Close() error {
+	return l.file.Close()
+}
+
+func withAuditTime(record AuditRecord) AuditRecord {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	return record
+}
+
+func latest(records []AuditRecord, limit int) []AuditRecord {
+	if limit > 0 && len(records) > limit {
+		return records[len(records)-limit:]
+	}
+	return records
+}
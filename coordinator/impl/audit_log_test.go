@@ -0,0 +1,85 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAuditLog(t *testing.T) {
+	l := NewMemoryAuditLog(3)
+	for _, action := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, l.Append(AuditRecord{Actor: "admin", Action: action}))
+	}
+
+	records, err := l.List(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d"}, auditActions(records))
+	assert.False(t, records[0].Time.IsZero())
+
+	records, err = l.List(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, auditActions(records))
+
+	assert.NoError(t, l.Close())
+}
+
+func TestFileAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := NewFileAuditLog(path)
+	assert.NoError(t, err)
+
+	shard := int64(2)
+	recordTime := time.UnixMilli(1000).UTC()
+	assert.NoError(t, l.Append(AuditRecord{Time: recordTime, Actor: "admin", Action: "delete-namespace", Namespace: "ns-1", Reason: "INC-42"}))
+	assert.NoError(t, l.Append(AuditRecord{Time: recordTime, Actor: AuditActorCoordinator, Action: "leader-elected", Shard: &shard}))
+	assert.NoError(t, l.Close())
+
+	// The records are kept across restarts
+	l, err = NewFileAuditLog(path)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, l.Append(AuditRecord{Actor: "admin", Action: "set-server-drain", Error: "server not found"}))
+	}
+
+	records, err := l.List(0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 12)
+	assert.Equal(t, AuditRecord{Time: recordTime, Actor: "admin", Action: "delete-namespace", Namespace: "ns-1", Reason: "INC-42"}, records[0])
+	assert.Equal(t, &shard, records[1].Shard)
+
+	records, err = l.List(1)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "server not found", records[0].Error)
+
+	records, err = l.List(11)
+	assert.NoError(t, err)
+	assert.Equal(t, "leader-elected", records[0].Action)
+
+	assert.NoError(t, l.Close())
+}
+
+func auditActions(records []AuditRecord) []string {
+	res := make([]string, 0, len(records))
+	for _, r := range records {
+		res = append(res, r.Action)
+	}
+	return res
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+// toProtoNamespaceAuthorization resolves the roles, the principals of the
+// tenant and the key prefix ACLs into the permissions of each principal on
+// the namespace, as enforced by the shard leaders. The access is only
+// restricted when the authorization is configured, when the tenant of the
+// namespace has principals, or when the namespace has key prefix ACLs.
+func toProtoNamespaceAuthorization(authz *model.AuthorizationConfig, tenant *model.TenantConfig, namespace string,
+	acls []model.KeyPrefixACL) *proto.NamespaceAuthorization {
+	if tenant != nil && len(tenant.Principals) > 0 {
+		// The principals of the tenant are handled as a role on the namespace
+		tenantAuthz := &model.AuthorizationConfig{}
+		if authz != nil {
+			tenantAuthz.Roles = append(tenantAuthz.Roles, authz.Roles...)
+		}
+		tenantAuthz.Roles = append(tenantAuthz.Roles, model.Role{
+			Name:        tenant.Name,
+			Principals:  tenant.Principals,
+			Permissions: map[string][]model.Permission{namespace: tenant.Permissions},
+		})
+		authz = tenantAuthz
+	}
+	if authz == nil && len(acls) == 0 {
+		return nil
+	}
+
+	res := &proto.NamespaceAuthorization{
+		Principals: map[string]*proto.NamespacePermissions{},
+	}
+	for _, acl := range acls {
+		res.KeyPrefixes = append(res.KeyPrefixes, &proto.KeyPrefixPermissions{
+			Principal:   acl.Principal,
+			Prefix:      acl.Prefix,
+			Permissions: toProtoNamespacePermissions(&proto.NamespacePermissions{}, acl.Permissions),
+		})
+	}
+	if authz == nil {
+		return res
+	}
+
+	patterns := map[string]*proto.NamespacePermissions{}
+	for _, role := range authz.Roles {
+		permissions := append(append([]model.Permission{},
+			role.Permissions[model.AllNamespaces]...), role.Permissions[namespace]...)
+		if len(permissions) == 0 {
+			continue
+		}
+
+		for _, principal := range role.Principals {
+			if auth.IsPrincipalPattern(principal) {
+				patterns[principal] = toProtoNamespacePermissions(patterns[principal], permissions)
+				continue
+			}
+			res.Principals[principal] = toProtoNamespacePermissions(res.Principals[principal], permissions)
+		}
+	}
+
+	// The patterns are sorted, for the assignments to be stable
+	for pattern, permissions := range patterns {
+		res.PrincipalPatterns = append(res.PrincipalPatterns, &proto.PrincipalPatternPermissions{
+			Pattern:     pattern,
+			Permissions: permissions,
+		})
+	}
+	slices.SortFunc(res.PrincipalPatterns, func(a, b *proto.PrincipalPatternPermissions) int {
+		return strings.Compare(a.Pattern, b.Pattern)
+	})
+	return res
+}
+
+func toProtoNamespacePermissions(p *proto.NamespacePermissions, permissions []model.Permission) *proto.NamespacePermissions {
+	if p == nil {
+		p = &proto.NamespacePermissions{}
+	}
+	for _, permission := range permissions {
+		switch permission {
+		case model.PermissionRead:
+			p.Read = true
+		case model.PermissionWrite:
+			p.Write = true
+		case model.PermissionAdmin:
+			p.Admin = true
+		}
+	}
+	return p
+}
+
+func validateKeyPrefixACL(acl model.KeyPrefixACL) error {
+	switch {
+	case acl.Principal == "":
+		return errors.Wrap(ErrInvalidKeyPrefixACL, "the principal cannot be empty")
+	case auth.ValidatePrincipalPattern(acl.Principal) != nil:
+		return errors.Wrapf(ErrInvalidKeyPrefixACL, "invalid principal pattern %s", acl.Principal)
+	case !strings.HasSuffix(acl.Prefix, "/"):
+		return errors.Wrap(ErrInvalidKeyPrefixACL, "the prefix must end with /")
+	case len(acl.Permissions) == 0:
+		return errors.Wrap(ErrInvalidKeyPrefixACL, "the permissions cannot be empty")
+	}
+
+	for _, permission := range acl.Permissions {
+		switch permission {
+		case model.PermissionRead, model.PermissionWrite, model.PermissionAdmin:
+		default:
+			return errors.Wrapf(ErrInvalidKeyPrefixACL, "unknown permission %s", permission)
+		}
+	}
+	return nil
+}
+
+func (c *coordinator) SetKeyPrefixACL(namespace string, acl model.KeyPrefixACL) error {
+	if err := validateKeyPrefixACL(acl); err != nil {
+		return err
+	}
+
+	return c.updateKeyPrefixACLs(namespace, func(acls []model.KeyPrefixACL) ([]model.KeyPrefixACL, error) {
+		acls = slices.DeleteFunc(acls, func(existing model.KeyPrefixACL) bool {
+			return existing.Principal == acl.Principal && existing.Prefix == acl.Prefix
+		})
+		return append(acls, acl), nil
+	})
+}
+
+func (c *coordinator) RemoveKeyPrefixACL(namespace string, principal string, prefix string) error {
+	return c.updateKeyPrefixACLs(namespace, func(acls []model.KeyPrefixACL) ([]model.KeyPrefixACL, error) {
+		count := len(acls)
+		acls = slices.DeleteFunc(acls, func(existing model.KeyPrefixACL) bool {
+			return existing.Principal == principal && existing.Prefix == prefix
+		})
+		if len(acls) == count {
+			return nil, ErrKeyPrefixACLNotFound
+		}
+		return acls, nil
+	})
+}
+
+func (c *coordinator) ListKeyPrefixACLs(namespace string) ([]model.KeyPrefixACL, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	ns, ok := c.clusterStatus.Namespaces[namespace]
+	if !ok || ns.IsSoftDeleted() {
+		return nil, ErrNamespaceNotFound
+	}
+	return ns.Clone().KeyPrefixACLs, nil
+}
+
+func (c *coordinator) updateKeyPrefixACLs(namespace string,
+	update func(acls []model.KeyPrefixACL) ([]model.KeyPrefixACL, error)) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if ns, ok := c.clusterStatus.Namespaces[namespace]; !ok || ns.IsSoftDeleted() {
+		return ErrNamespaceNotFound
+	}
+
+	cs := c.clusterStatus.Clone()
+	ns := cs.Namespaces[namespace]
+	acls, err := update(ns.KeyPrefixACLs)
+	if err != nil {
+		return err
+	}
+	ns.KeyPrefixACLs = acls
+	cs.Namespaces[namespace] = ns
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+
+	c.log.Info(
+		"Updated the key prefix ACLs",
+		slog.String("namespace", namespace),
+		slog.Int("acls", len(acls)),
+	)
+
+	// The leaders get the new permissions with the shard assignments
+	c.computeNewAssignments()
+	return nil
+}
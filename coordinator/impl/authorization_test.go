@@ -0,0 +1,134 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+func TestToProtoNamespaceAuthorization(t *testing.T) {
+	assert.Nil(t, toProtoNamespaceAuthorization(nil, nil, "ns-1", nil))
+
+	authz := &model.AuthorizationConfig{
+		Roles: []model.Role{{
+			Name:       "team-a",
+			Principals: []string{"alice", "bob"},
+			Permissions: map[string][]model.Permission{
+				"ns-1":   {model.PermissionRead, model.PermissionWrite},
+				"shared": {model.PermissionRead},
+			},
+		}, {
+			Name:       "operators",
+			Principals: []string{"carol"},
+			Permissions: map[string][]model.Permission{
+				model.AllNamespaces: {model.PermissionAdmin},
+			},
+		}, {
+			Name:       "auditors",
+			Principals: []string{"bob"},
+			Permissions: map[string][]model.Permission{
+				model.AllNamespaces: {model.PermissionRead},
+			},
+		}},
+	}
+
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"alice": {Read: true, Write: true},
+		"bob":   {Read: true, Write: true},
+		"carol": {Admin: true},
+	}, toProtoNamespaceAuthorization(authz, nil, "ns-1", nil).Principals)
+
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"alice": {Read: true},
+		"bob":   {Read: true},
+		"carol": {Admin: true},
+	}, toProtoNamespaceAuthorization(authz, nil, "shared", nil).Principals)
+
+	// The namespaces without any role are closed to everyone else
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"bob":   {Read: true},
+		"carol": {Admin: true},
+	}, toProtoNamespaceAuthorization(authz, nil, "ns-2", nil).Principals)
+
+	// The key prefix ACLs alone restrict the access to the namespace
+	authorization := toProtoNamespaceAuthorization(nil, nil, "ns-1", []model.KeyPrefixACL{{
+		Principal:   "alice",
+		Prefix:      "/configs/a/",
+		Permissions: []model.Permission{model.PermissionRead, model.PermissionWrite},
+	}})
+	assert.Empty(t, authorization.Principals)
+	assert.Len(t, authorization.KeyPrefixes, 1)
+	assert.Equal(t, "alice", authorization.KeyPrefixes[0].Principal)
+	assert.Equal(t, "/configs/a/", authorization.KeyPrefixes[0].Prefix)
+	assert.True(t, authorization.KeyPrefixes[0].Permissions.Read)
+	assert.True(t, authorization.KeyPrefixes[0].Permissions.Write)
+	assert.False(t, authorization.KeyPrefixes[0].Permissions.Admin)
+}
+
+func TestToProtoNamespaceAuthorization_PrincipalPatterns(t *testing.T) {
+	authz := &model.AuthorizationConfig{
+		Roles: []model.Role{{
+			Name:       "team-a",
+			Principals: []string{"spiffe://example.org/ns/team-a/*", "alice"},
+			Permissions: map[string][]model.Permission{
+				"ns-1": {model.PermissionWrite},
+			},
+		}, {
+			Name:       "readers",
+			Principals: []string{"spiffe://example.org/ns/team-a/*", "spiffe://example.org/ns/*/reader"},
+			Permissions: map[string][]model.Permission{
+				model.AllNamespaces: {model.PermissionRead},
+			},
+		}},
+	}
+
+	authorization := toProtoNamespaceAuthorization(authz, nil, "ns-1", nil)
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"alice": {Write: true},
+	}, authorization.Principals)
+	assert.Len(t, authorization.PrincipalPatterns, 2)
+	assert.Equal(t, "spiffe://example.org/ns/*/reader", authorization.PrincipalPatterns[0].Pattern)
+	assert.Equal(t, &proto.NamespacePermissions{Read: true}, authorization.PrincipalPatterns[0].Permissions)
+	assert.Equal(t, "spiffe://example.org/ns/team-a/*", authorization.PrincipalPatterns[1].Pattern)
+	assert.Equal(t, &proto.NamespacePermissions{Read: true, Write: true}, authorization.PrincipalPatterns[1].Permissions)
+}
+
+func TestValidateKeyPrefixACL(t *testing.T) {
+	valid := model.KeyPrefixACL{
+		Principal:   "alice",
+		Prefix:      "/configs/a/",
+		Permissions: []model.Permission{model.PermissionWrite},
+	}
+	assert.NoError(t, validateKeyPrefixACL(valid))
+
+	for name, update := range map[string]func(acl *model.KeyPrefixACL){
+		"empty-principal":    func(acl *model.KeyPrefixACL) { acl.Principal = "" },
+		"invalid-pattern":    func(acl *model.KeyPrefixACL) { acl.Principal = "spiffe://example.org/[" },
+		"no-trailing-slash":  func(acl *model.KeyPrefixACL) { acl.Prefix = "/configs/a" },
+		"empty-permissions":  func(acl *model.KeyPrefixACL) { acl.Permissions = nil },
+		"unknown-permission": func(acl *model.KeyPrefixACL) { acl.Permissions = []model.Permission{"delete"} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			acl := valid
+			update(&acl)
+			assert.ErrorIs(t, validateKeyPrefixACL(acl), ErrInvalidKeyPrefixACL)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"log/slog"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+// validateClusterConfig checks the cluster config before it's applied, so that
+// a broken config update is rejected instead of being partially applied.
+func validateClusterConfig(config *model.ClusterConfig) error {
+	if len(config.Servers) == 0 {
+		return errors.Wrap(ErrInvalidClusterConfig, "the servers list cannot be empty")
+	}
+
+	publicAddresses := common.NewSet[string]()
+	internalAddresses := common.NewSet[string]()
+	for _, sa := range config.Servers {
+		if sa.Public == "" || sa.Internal == "" {
+			return errors.Wrap(ErrInvalidClusterConfig, "the server addresses cannot be empty")
+		}
+		if publicAddresses.Contains(sa.Public) || internalAddresses.Contains(sa.Internal) {
+			return errors.Wrapf(ErrInvalidClusterConfig, "duplicated server %s", sa.Internal)
+		}
+		publicAddresses.Add(sa.Public)
+		internalAddresses.Add(sa.Internal)
+	}
+
+	for _, label := range config.FailureDomains {
+		if label == "" {
+			return errors.Wrap(ErrInvalidClusterConfig, "the failure domain labels cannot be empty")
+		}
+	}
+
+	names := common.NewSet[string]()
+	for _, nc := range config.Namespaces {
+		if names.Contains(nc.Name) {
+			return errors.Wrapf(ErrInvalidClusterConfig, "duplicated namespace %s", nc.Name)
+		}
+		names.Add(nc.Name)
+
+		if err := validateNamespaceConfig(config, nc); err != nil {
+			return errors.Wrapf(ErrInvalidClusterConfig, "namespace %s: %v", nc.Name, err)
+		}
+	}
+
+	if lb := config.LoadBalancer; lb != nil {
+		if _, ok := getLoadScorer(lb.Policy); !ok {
+			return errors.Wrapf(ErrInvalidClusterConfig, "unknown load scoring policy %s", lb.Policy)
+		}
+		if lb.Threshold < 0 || lb.MaxConcurrentMoves < 0 {
+			return errors.Wrap(ErrInvalidClusterConfig, "the load balancer threshold and max concurrent moves cannot be negative")
+		}
+	}
+
+	if e := config.Elections; e != nil {
+		if e.MaxRoundsPerSecond < 0 || e.Burst < 0 || e.LeaderReturnTimeout < 0 || e.StuckTimeout < 0 {
+			return errors.Wrap(ErrInvalidClusterConfig, "the elections settings cannot be negative")
+		}
+	}
+
+	if _, err := parseMaintenanceWindows(config); err != nil {
+		return err
+	}
+
+	if err := validateAuthorization(config.Authorization); err != nil {
+		return err
+	}
+
+	if err := validateTenants(config); err != nil {
+		return err
+	}
+
+	if _, ok := getAssignmentStrategy(config); !ok {
+		return errors.Wrapf(ErrInvalidClusterConfig, "unknown assignment strategy %s", config.Assignment.Strategy)
+	}
+
+	if config.NamespaceDeletionGracePeriod < 0 {
+		return errors.Wrap(ErrInvalidClusterConfig, "the namespace deletion grace period cannot be negative")
+	}
+	return nil
+}
+
+func validateNamespaceConfig(config *model.ClusterConfig, nc model.NamespaceConfig) error {
+	switch {
+	case nc.Name == "":
+		return errors.Wrap(ErrInvalidNamespaceConfig, "the namespace name cannot be empty")
+	case nc.InitialShardCount == 0:
+		return errors.Wrap(ErrInvalidNamespaceConfig, "the initial shard count must be greater than zero")
+	case nc.ReplicationFactor == 0:
+		return errors.Wrap(ErrInvalidNamespaceConfig, "the replication factor must be greater than zero")
+	case int(nc.ReplicationFactor) > len(config.Servers):
+		return errors.Wrapf(ErrInvalidNamespaceConfig, "the replication factor cannot be greater than the number of servers (%d)",
+			len(config.Servers))
+	case nc.ReplicationMode != "" && nc.ReplicationMode != model.ReplicationModeSync && nc.ReplicationMode != model.ReplicationModeRelaxed:
+		return errors.Wrapf(ErrInvalidNamespaceConfig, "unknown replication mode %s", nc.ReplicationMode)
+	}
+
+	if err := validateNamespaceTenant(config, nc); err != nil {
+		return err
+	}
+
+	poolName := namespacePool(config, nc)
+	if pool := serversInPool(config, poolName); len(pool) < len(config.Servers) && int(nc.ReplicationFactor) > len(pool) {
+		return errors.Wrapf(ErrInvalidNamespaceConfig, "the replication factor cannot be greater than the number of servers in pool %q (%d)",
+			poolName, len(pool))
+	}
+
+	if err := validatePartitioning(nc); err != nil {
+		return err
+	}
+
+	for _, aa := range nc.AntiAffinities {
+		if len(aa.Labels) == 0 {
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the anti-affinity labels cannot be empty")
+		}
+		if aa.Mode != "" && aa.Mode != model.AntiAffinityModeStrict && aa.Mode != model.AntiAffinityModeRelaxed {
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "unknown anti-affinity mode %s", aa.Mode)
+		}
+	}
+
+	for _, pc := range nc.PlacementConstraints {
+		switch {
+		case pc.Label == "":
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the placement constraint label cannot be empty")
+		case len(pc.Values) == 0:
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "the placement constraint on label %s has no values", pc.Label)
+		case pc.Operator != "" && pc.Operator != model.PlacementOperatorIn && pc.Operator != model.PlacementOperatorNotIn:
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "unknown placement operator %s", pc.Operator)
+		}
+	}
+
+	if p := nc.Policies; p != nil {
+		if p.DefaultTTL < 0 || p.MaxValueSize < 0 || p.MaxWritesPerSecond < 0 || p.MaxReadsPerSecond < 0 ||
+			p.NotificationsRetention < 0 || p.MaxClientOpsPerSecond < 0 || p.MaxClientWriteBytesPerSecond < 0 {
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the namespace policies cannot be negative")
+		}
+		if p.SlidingTTL && p.DefaultTTL == 0 {
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the sliding TTL requires a default TTL")
+		}
+		for principal, q := range p.PrincipalQuotas {
+			switch {
+			case principal == "":
+				return errors.Wrap(ErrInvalidNamespaceConfig, "the principal of a quota cannot be empty")
+			case q.OpsPerSecond < 0 || q.WriteBytesPerSecond < 0 || q.MaxStorageBytes < 0:
+				return errors.Wrapf(ErrInvalidNamespaceConfig, "the quota of principal %s cannot be negative", principal)
+			}
+		}
+	}
+	return nil
+}
+
+// Log what is changing between the current and the new cluster config.
+func logClusterConfigChanges(log *slog.Logger, current *model.ClusterConfig, newConfig *model.ClusterConfig) {
+	var added, removed, replicationFactorChanged []string
+	for _, nc := range newConfig.Namespaces {
+		cnc := findNamespaceConfig(current, nc.Name)
+		switch {
+		case cnc == nil:
+			added = append(added, nc.Name)
+		case cnc.ReplicationFactor != nc.ReplicationFactor:
+			replicationFactorChanged = append(replicationFactorChanged, nc.Name)
+		}
+	}
+	for _, nc := range current.Namespaces {
+		if findNamespaceConfig(newConfig, nc.Name) == nil {
+			removed = append(removed, nc.Name)
+		}
+	}
+
+	var serversAdded, serversRemoved []string
+	for _, sa := range newConfig.Servers {
+		if !listContains(current.Servers, sa) {
+			serversAdded = append(serversAdded, sa.Internal)
+		}
+	}
+	for _, sa := range current.Servers {
+		if !listContains(newConfig.Servers, sa) {
+			serversRemoved = append(serversRemoved, sa.Internal)
+		}
+	}
+
+	log.Info(
+		"Applying cluster config changes",
+		slog.Any("namespaces-added", added),
+		slog.Any("namespaces-removed", removed),
+		slog.Any("namespaces-replication-factor-changed", replicationFactorChanged),
+		slog.Any("servers-added", serversAdded),
+		slog.Any("servers-removed", serversRemoved),
+	)
+}
+
+func validateAuthorization(authz *model.AuthorizationConfig) error {
+	if authz == nil {
+		return nil
+	}
+
+	for _, role := range authz.Roles {
+		if role.Name == "" {
+			return errors.Wrap(ErrInvalidClusterConfig, "the role name cannot be empty")
+		}
+		for _, principal := range role.Principals {
+			if principal == "" {
+				return errors.Wrapf(ErrInvalidClusterConfig, "role %s: the principals cannot be empty", role.Name)
+			}
+			if err := auth.ValidatePrincipalPattern(principal); err != nil {
+				return errors.Wrapf(ErrInvalidClusterConfig, "role %s: invalid principal pattern %s", role.Name, principal)
+			}
+		}
+		for namespace, permissions := range role.Permissions {
+			for _, permission := range permissions {
+				switch permission {
+				case model.PermissionRead, model.PermissionWrite, model.PermissionAdmin:
+				default:
+					return errors.Wrapf(ErrInvalidClusterConfig, "role %s: unknown permission %s on namespace %s",
+						role.Name, permission, namespace)
+				}
+			}
+		}
+	}
+	return nil
+}
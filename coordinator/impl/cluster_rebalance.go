@@ -30,11 +30,28 @@ type SwapNodeAction struct {
 
 // Make sure every server is assigned a similar number of shards
 // Output a list of actions to be taken to rebalance the cluster.
-func rebalanceCluster(servers []model.ServerAddress, currentStatus *model.ClusterStatus) []SwapNodeAction { //nolint:revive
+func rebalanceCluster(config *model.ClusterConfig, currentStatus *model.ClusterStatus) []SwapNodeAction {
+	return computeSwapActions(config, currentStatus, true, nil)
+}
+
+// drainRemovedServers only moves the replicas out of the servers that are not
+// part of the config anymore, without evening out the other servers.
+func drainRemovedServers(config *model.ClusterConfig, currentStatus *model.ClusterStatus) []SwapNodeAction {
+	return computeSwapActions(config, currentStatus, false, nil)
+}
+
+// computeSwapActions moves the replicas out of the removed servers and, with
+// evenOut, across the other servers. The excluded servers don't receive any
+// of the moved replicas.
+func computeSwapActions(config *model.ClusterConfig, currentStatus *model.ClusterStatus, //nolint:revive
+	evenOut bool, excludedTargets []model.ServerAddress) []SwapNodeAction {
 	res := make([]SwapNodeAction, 0)
 
+	servers := config.Servers
 	serversCount := len(servers)
 	shardsPerServer, deletedServers := getShardsPerServer(servers, currentStatus)
+	policy := newPlacementPolicy(config)
+	shards := getShardsPlacement(currentStatus)
 
 outer:
 	for {
@@ -62,26 +79,35 @@ outer:
 		// First try to reassign shards from the removed servers.
 		// We do it one by one, by placing in the lead loaded server
 		if len(deletedServers) > 0 {
-			ds, shards := getFirstEntry(deletedServers)
-
-			for j := serversCount - 1; j >= 0; j-- {
-				to := rankings[j]
-				eligibleShards := shards.Complement(to.Shards)
+			ds, dsShards := getFirstEntry(deletedServers)
+
+			// The replicas have to be moved out of the removed server, so
+			// we can fall back to ignore the relaxed anti-affinity rules
+			for _, strictOnly := range []bool{false, true} {
+				for j := serversCount - 1; j >= 0; j-- {
+					to := rankings[j]
+					if listContains(excludedTargets, to.Addr) {
+						continue
+					}
+					shard, ok := shards.findEligibleShard(policy, dsShards.Complement(to.Shards), ds, to.Addr, strictOnly)
+					if !ok {
+						continue
+					}
 
-				if !eligibleShards.IsEmpty() {
 					a := SwapNodeAction{
-						Shard: eligibleShards.GetSorted()[0],
+						Shard: shard,
 						From:  ds,
 						To:    to.Addr,
 					}
 
-					shards.Remove(a.Shard)
-					if shards.IsEmpty() {
+					dsShards.Remove(a.Shard)
+					if dsShards.IsEmpty() {
 						delete(deletedServers, ds)
 					} else {
-						deletedServers[ds] = shards
+						deletedServers[ds] = dsShards
 					}
 					shardsPerServer[a.To].Add(a.Shard)
+					shards.swap(a)
 
 					slog.Debug(
 						"Transfer from removed node",
@@ -97,28 +123,18 @@ outer:
 			break
 		}
 
-		// Find a shard from the most loaded server that can be moved to the
-		// least loaded server, with the constraint that multiple replicas of
-		// the same shard should not be assigned to one server
-		mostLoaded := rankings[0]
-		leastLoaded := rankings[serversCount-1]
-		if mostLoaded.Shards.Count() <= leastLoaded.Shards.Count()+1 {
+		if !evenOut {
 			break
 		}
 
-		eligibleShards := mostLoaded.Shards.Complement(leastLoaded.Shards)
-		if eligibleShards.IsEmpty() {
+		a, ok := findEvenOutSwap(policy, shards, rankings)
+		if !ok {
 			break
 		}
 
-		a := SwapNodeAction{
-			Shard: eligibleShards.GetSorted()[0],
-			From:  mostLoaded.Addr,
-			To:    leastLoaded.Addr,
-		}
-
 		shardsPerServer[a.From].Remove(a.Shard)
 		shardsPerServer[a.To].Add(a.Shard)
+		shards.swap(a)
 
 		slog.Debug(
 			"Swapping nodes",
@@ -131,6 +147,44 @@ outer:
 	return res
 }
 
+// findEvenOutSwap finds a shard from the most loaded server that can be moved
+// to the least loaded server, with the constraint that multiple replicas of
+// the same shard should not be assigned to one server. The replicas never
+// leave the pool of their namespace, so the servers are evened out within
+// each pool.
+func findEvenOutSwap(policy *placementPolicy, shards shardsPlacement, rankings []ServerRank) (SwapNodeAction, bool) {
+	servers := make([]model.ServerAddress, 0, len(rankings))
+	rankingsPerPool := map[string][]ServerRank{}
+	for _, r := range rankings {
+		pool := policy.poolOf(r.Addr)
+		rankingsPerPool[pool] = append(rankingsPerPool[pool], r)
+		servers = append(servers, r.Addr)
+	}
+
+	for _, pool := range policy.sortedPools(servers) {
+		poolRankings := rankingsPerPool[pool]
+		mostLoaded := poolRankings[0]
+		leastLoaded := poolRankings[len(poolRankings)-1]
+		if mostLoaded.Shards.Count() <= leastLoaded.Shards.Count()+1 {
+			continue
+		}
+
+		shard, ok := shards.findEligibleShard(policy, mostLoaded.Shards.Complement(leastLoaded.Shards),
+			mostLoaded.Addr, leastLoaded.Addr, false)
+		if !ok {
+			continue
+		}
+
+		return SwapNodeAction{
+			Shard: shard,
+			From:  mostLoaded.Addr,
+			To:    leastLoaded.Addr,
+		}, true
+	}
+
+	return SwapNodeAction{}, false
+}
+
 func getShardsPerServer(servers []model.ServerAddress, currentStatus *model.ClusterStatus) (
 	existingServers map[model.ServerAddress]common.Set[int64],
 	deletedServers map[model.ServerAddress]common.Set[int64]) {
@@ -162,6 +216,47 @@ func getShardsPerServer(servers []model.ServerAddress, currentStatus *model.Clus
 	return existingServers, deletedServers
 }
 
+type shardPlacement struct {
+	namespace string
+	ensemble  []model.ServerAddress
+}
+
+// shardsPlacement keeps track of the ensembles while computing the swap actions.
+type shardsPlacement map[int64]*shardPlacement
+
+func getShardsPlacement(currentStatus *model.ClusterStatus) shardsPlacement {
+	res := shardsPlacement{}
+	for ns, nss := range currentStatus.Namespaces {
+		for shardId, shard := range nss.Shards {
+			res[shardId] = &shardPlacement{
+				namespace: ns,
+				ensemble:  shard.Ensemble,
+			}
+		}
+	}
+	return res
+}
+
+// findEligibleShard returns the first of the candidate shards whose replica can be moved
+// from one server to the other, without violating the anti-affinity rules.
+func (sp shardsPlacement) findEligibleShard(policy *placementPolicy, candidates common.Set[int64],
+	from model.ServerAddress, to model.ServerAddress, strictOnly bool) (int64, bool) {
+	for _, shard := range candidates.GetSorted() {
+		p, ok := sp[shard]
+		if !ok || policy.canSwap(p.namespace, p.ensemble, from, to, strictOnly) {
+			return shard, true
+		}
+	}
+
+	return 0, false
+}
+
+func (sp shardsPlacement) swap(a SwapNodeAction) {
+	if p, ok := sp[a.Shard]; ok {
+		p.ensemble = replaceInList(p.ensemble, a.From, a.To)
+	}
+}
+
 type ServerRank struct {
 	Addr   model.ServerAddress
 	Shards common.Set[int64]
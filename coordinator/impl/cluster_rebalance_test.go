@@ -130,7 +130,7 @@ func TestClusterRebalance_Single(t *testing.T) {
 		},
 	}
 
-	actions := rebalanceCluster([]model.ServerAddress{s1, s2, s3, s4, s5}, cs)
+	actions := rebalanceCluster(&model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4, s5}}, cs)
 	assert.Equal(t, []SwapNodeAction{{
 		Shard: 0,
 		From:  s1,
@@ -153,7 +153,7 @@ func TestClusterRebalance_Multiple(t *testing.T) {
 		},
 	}
 
-	actions := rebalanceCluster([]model.ServerAddress{s1, s2, s3, s4, s5}, cs)
+	actions := rebalanceCluster(&model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4, s5}}, cs)
 	slog.Info(
 		"actions",
 		slog.Any("actions", actions),
@@ -189,7 +189,7 @@ func TestClusterRebalance_DoubleSize(t *testing.T) {
 		},
 	}
 
-	actions := rebalanceCluster([]model.ServerAddress{s1, s2, s3, s4, s5, s6}, cs)
+	actions := rebalanceCluster(&model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4, s5, s6}}, cs)
 	slog.Info(
 		"actions",
 		slog.Any("actions", actions),
@@ -249,7 +249,7 @@ func TestClusterRebalance_ShrinkOne(t *testing.T) {
 		},
 	}
 
-	actions := rebalanceCluster([]model.ServerAddress{s1, s2, s3, s4, s5}, cs)
+	actions := rebalanceCluster(&model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4, s5}}, cs)
 	slog.Info(
 		"actions",
 		slog.Any("actions", actions),
@@ -285,7 +285,7 @@ func TestClusterRebalance_ShrinkToHalf(t *testing.T) {
 		},
 	}
 
-	actions := rebalanceCluster([]model.ServerAddress{s1, s2, s3}, cs)
+	actions := rebalanceCluster(&model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3}}, cs)
 	slog.Info(
 		"actions",
 		slog.Any("actions", actions),
@@ -328,3 +328,23 @@ func TestClusterRebalance_ShrinkToHalf(t *testing.T) {
 		To:    s1,
 	}}, actions)
 }
+
+func TestClusterRebalance_DrainRemovedServersOnly(t *testing.T) {
+	cs := rfTestStatus(1, map[int64][]model.ServerAddress{
+		0: {s1},
+		1: {s1},
+		2: {s2},
+		3: {s2},
+	}, nil)
+
+	// The new servers are left empty, while the removed server is drained
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s3, s4}}
+	assert.Equal(t, []SwapNodeAction{
+		{Shard: 2, From: s2, To: s4},
+		{Shard: 3, From: s2, To: s3},
+	}, drainRemovedServers(config, cs))
+
+	config = &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4}}
+	assert.Empty(t, drainRemovedServers(config, cs))
+	assert.Len(t, rebalanceCluster(config, cs), 2)
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// The terms of the imported shards are moved ahead by this gap, since the
+// export might predate some leader elections. The new elections need a term
+// higher than the one already known by the storage servers.
+const importedTermGap = 1000
+
+var ErrInvalidClusterStatusExport = errors.New("invalid cluster status export")
+
+// ExportClusterStatus serializes the cluster status in a portable format, that
+// can be imported into any metadata provider.
+func ExportClusterStatus(status *model.ClusterStatus) ([]byte, error) {
+	return json.MarshalIndent(status, "", "  ")
+}
+
+// ImportClusterStatus stores an exported cluster status, when the metadata
+// provider doesn't hold any cluster status yet. It returns false if the
+// metadata was already initialized, in which case it's left untouched.
+func ImportClusterStatus(provider MetadataProvider, export []byte) (bool, error) {
+	status := &model.ClusterStatus{}
+	if err := json.Unmarshal(export, status); err != nil {
+		return false, errors.Wrap(ErrInvalidClusterStatusExport, err.Error())
+	}
+	if status.Namespaces == nil {
+		return false, errors.Wrap(ErrInvalidClusterStatusExport, "no namespaces found")
+	}
+
+	current, _, err := provider.Get()
+	if err != nil && !errors.Is(err, ErrMetadataNotInitialized) {
+		return false, err
+	}
+	if current != nil {
+		return false, nil
+	}
+
+	for _, nss := range status.Namespaces {
+		for shard, sm := range nss.Shards {
+			if sm.Status != model.ShardStatusDeleting {
+				sm.Status = model.ShardStatusUnknown
+			}
+			sm.Term += importedTermGap
+			sm.Leader = nil
+			nss.Shards[shard] = sm
+		}
+	}
+
+	if _, err := provider.Store(status, MetadataNotExists); err != nil {
+		if errors.Is(err, ErrMetadataBadVersion) {
+			// Another coordinator has initialized the metadata in the meantime
+			return false, nil
+		}
+		return false, err
+	}
+
+	slog.Info(
+		"Imported the cluster status",
+		slog.String("component", "coordinator"),
+		slog.Int("namespaces", len(status.Namespaces)),
+	)
+	return true, nil
+}
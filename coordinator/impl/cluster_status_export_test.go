@@ -0,0 +1,98 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestClusterStatus_ExportImport(t *testing.T) {
+	s1 := model.ServerAddress{Public: "s1:6648", Internal: "s1:6649"}
+	s2 := model.ServerAddress{Public: "s2:6648", Internal: "s2:6649"}
+	status := &model.ClusterStatus{
+		Namespaces: map[string]model.NamespaceStatus{
+			"ns-1": {
+				ReplicationFactor: 2,
+				Shards: map[int64]model.ShardMetadata{
+					0: {
+						Status:         model.ShardStatusSteadyState,
+						Term:           5,
+						Leader:         &s1,
+						Ensemble:       []model.ServerAddress{s1, s2},
+						Int32HashRange: model.Int32HashRange{Min: 0, Max: 100},
+					},
+					1: {
+						Status:         model.ShardStatusDeleting,
+						Term:           3,
+						Ensemble:       []model.ServerAddress{s1, s2},
+						Int32HashRange: model.Int32HashRange{Min: 101, Max: 200},
+					},
+				},
+			},
+		},
+		ShardIdGenerator: 2,
+		ServerIdx:        1,
+		Servers:          map[string]model.ServerStatus{s2.Internal: {Draining: true}},
+	}
+
+	export, err := ExportClusterStatus(status)
+	assert.NoError(t, err)
+
+	provider := NewMetadataProviderMemory()
+	imported, err := ImportClusterStatus(provider, export)
+	assert.NoError(t, err)
+	assert.True(t, imported)
+
+	cs, _, err := provider.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cs.ShardIdGenerator)
+	assert.Equal(t, uint32(1), cs.ServerIdx)
+	assert.Equal(t, status.Servers, cs.Servers)
+
+	// The shards go through a new leader election, with a higher term
+	shard := cs.Namespaces["ns-1"].Shards[0]
+	assert.Equal(t, model.ShardStatusUnknown, shard.Status)
+	assert.Equal(t, int64(5+importedTermGap), shard.Term)
+	assert.Nil(t, shard.Leader)
+	assert.Equal(t, []model.ServerAddress{s1, s2}, shard.Ensemble)
+	assert.Equal(t, model.Int32HashRange{Min: 0, Max: 100}, shard.Int32HashRange)
+	assert.Equal(t, model.ShardStatusDeleting, cs.Namespaces["ns-1"].Shards[1].Status)
+
+	// An initialized metadata is never overwritten
+	imported, err = ImportClusterStatus(provider, []byte(`{"namespaces":{}}`))
+	assert.NoError(t, err)
+	assert.False(t, imported)
+	cs, _, err = provider.Get()
+	assert.NoError(t, err)
+	assert.Len(t, cs.Namespaces, 1)
+}
+
+func TestClusterStatus_ImportInvalid(t *testing.T) {
+	provider := NewMetadataProviderMemory()
+
+	_, err := ImportClusterStatus(provider, []byte("not json"))
+	assert.ErrorIs(t, err, ErrInvalidClusterStatusExport)
+
+	_, err = ImportClusterStatus(provider, []byte(`{"shardIdGenerator":1}`))
+	assert.ErrorIs(t, err, ErrInvalidClusterStatusExport)
+
+	cs, _, err := provider.Get()
+	assert.NoError(t, err)
+	assert.Nil(t, cs)
+}
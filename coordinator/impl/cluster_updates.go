@@ -15,6 +15,10 @@
 package impl
 
 import (
+	"time"
+
+	"github.com/pkg/errors"
+
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/coordinator/model"
 )
@@ -41,7 +45,8 @@ func findNamespaceConfig(config *model.ClusterConfig, ns string) *model.Namespac
 func applyClusterChanges(config *model.ClusterConfig, currentStatus *model.ClusterStatus) (
 	newStatus *model.ClusterStatus,
 	shardsToAdd map[int64]string,
-	shardsToDelete []int64) {
+	shardsToDelete []int64,
+	err error) {
 	shardsToAdd = map[int64]string{}
 	shardsToDelete = []int64{}
 
@@ -54,48 +59,67 @@ func applyClusterChanges(config *model.ClusterConfig, currentStatus *model.Clust
 		newStatus.Namespaces[k] = v.Clone()
 	}
 
+	if currentStatus.Freeze != nil {
+		freeze := *currentStatus.Freeze
+		newStatus.Freeze = &freeze
+	}
+
+	// The status of the servers that were removed from the config is dropped
+	for _, sa := range config.Servers {
+		if ss, ok := currentStatus.Servers[sa.Internal]; ok {
+			if newStatus.Servers == nil {
+				newStatus.Servers = map[string]model.ServerStatus{}
+			}
+			newStatus.Servers[sa.Internal] = ss
+		}
+	}
+
+	// New shards are not placed on the servers being decommissioned
+	pc := placementConfig(config, currentStatus)
+	policy := newPlacementPolicy(pc)
+
 	// Check for new namespaces
 	for _, nc := range config.Namespaces {
-		nss, existing := currentStatus.Namespaces[nc.Name]
-		if existing {
+		if nss, existing := currentStatus.Namespaces[nc.Name]; existing {
+			if !nss.IsDeleting() {
+				updated := newStatus.Namespaces[nc.Name]
+				// The namespace might have been added back within the grace period
+				updated.DeletedAt = nil
+				// The ensembles of the shards are adjusted afterward to
+				// the new replication factor
+				updated.ReplicationFactor = nc.ReplicationFactor
+				newStatus.Namespaces[nc.Name] = updated
+			}
 			continue
 		}
 
 		// This is a new namespace
-		nss = model.NamespaceStatus{
-			Shards:            map[int64]model.ShardMetadata{},
-			ReplicationFactor: nc.ReplicationFactor,
+		if err = addNamespace(pc, policy, newStatus, nc, shardsToAdd); err != nil {
+			return nil, nil, nil, err
 		}
-		for _, shard := range common.GenerateShards(newStatus.ShardIdGenerator, nc.InitialShardCount) {
-			shardMetadata := model.ShardMetadata{
-				Status:   model.ShardStatusUnknown,
-				Term:     -1,
-				Leader:   nil,
-				Ensemble: getServers(config.Servers, newStatus.ServerIdx, nc.ReplicationFactor),
-				Int32HashRange: model.Int32HashRange{
-					Min: shard.Min,
-					Max: shard.Max,
-				},
-			}
-
-			nss.Shards[shard.Id] = shardMetadata
-			newStatus.ServerIdx = (newStatus.ServerIdx + nc.ReplicationFactor) % uint32(len(config.Servers))
-			shardsToAdd[shard.Id] = nc.Name
-		}
-		newStatus.Namespaces[nc.Name] = nss
-
-		newStatus.ShardIdGenerator += int64(nc.InitialShardCount)
 	}
 
 	// Check for any namespace that was removed
 	for name, ns := range currentStatus.Namespaces {
 		namespaceConfig := findNamespaceConfig(config, name)
-		if namespaceConfig != nil {
+		if namespaceConfig != nil || ns.Dynamic {
+			continue
+		}
+
+		if ns.IsSoftDeleted() {
+			// The data is already going to be deleted when the grace period is over
 			continue
 		}
 
-		// Keep the shards in the status and mark them as being deleted
 		nss := ns.Clone()
+		if config.NamespaceDeletionGracePeriod > 0 {
+			now := time.Now()
+			nss.DeletedAt = &now
+			newStatus.Namespaces[name] = nss
+			continue
+		}
+
+		// Keep the shards in the status and mark them as being deleted
 		for shardId, shard := range nss.Shards {
 			shard.Status = model.ShardStatusDeleting
 			nss.Shards[shardId] = shard
@@ -105,5 +129,71 @@ func applyClusterChanges(config *model.ClusterConfig, currentStatus *model.Clust
 		newStatus.Namespaces[name] = nss
 	}
 
-	return newStatus, shardsToAdd, shardsToDelete
+	return newStatus, shardsToAdd, shardsToDelete, nil
+}
+
+// Check whether any existing namespace was soft-deleted, restored or had its
+// replication factor changed.
+func namespacesChanged(currentStatus *model.ClusterStatus, newStatus *model.ClusterStatus) bool {
+	for name, nss := range newStatus.Namespaces {
+		current, ok := currentStatus.Namespaces[name]
+		if ok && (current.IsSoftDeleted() != nss.IsSoftDeleted() || current.ReplicationFactor != nss.ReplicationFactor) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create the shards of a new namespace and add them to the cluster status.
+func addNamespace(config *model.ClusterConfig, policy *placementPolicy, status *model.ClusterStatus,
+	nc model.NamespaceConfig, shardsToAdd map[int64]string) error {
+	if int(nc.ReplicationFactor) > len(config.Servers) {
+		return errors.Wrapf(ErrNoAvailableServers, "namespace %s", nc.Name)
+	}
+
+	nss := model.NamespaceStatus{
+		Shards:            map[int64]model.ShardMetadata{},
+		ReplicationFactor: nc.ReplicationFactor,
+		Partitioning:      nc.Partitioning,
+		Tenant:            nc.Tenant,
+	}
+	var keyRanges []model.KeyRange
+	if nc.Partitioning == model.PartitioningRange {
+		keyRanges = generateKeyRanges(nc.RangeSplitKeys)
+	}
+
+	shardsPerServer, _ := getShardsPerServer(config.Servers, status)
+	ac := policy.newAssignmentContext(shardsPerServer)
+	for i, shard := range common.GenerateShards(status.ShardIdGenerator, nc.InitialShardCount) {
+		ac.StartIdx = status.ServerIdx
+		ensemble, err := policy.strategy.SelectEnsemble(ac, nc.Name, config.Servers, nc.ReplicationFactor)
+		if err != nil {
+			return err
+		}
+		ac.addReplicas(shard.Id, ensemble)
+
+		shardMetadata := model.ShardMetadata{
+			Status:   model.ShardStatusUnknown,
+			Term:     -1,
+			Leader:   nil,
+			Ensemble: ensemble,
+			Int32HashRange: model.Int32HashRange{
+				Min: shard.Min,
+				Max: shard.Max,
+			},
+			ReplicationMode: nc.ReplicationMode,
+		}
+		if keyRanges != nil {
+			shardMetadata.KeyRange = &keyRanges[i]
+			shardMetadata.Int32HashRange = model.Int32HashRange{}
+		}
+
+		nss.Shards[shard.Id] = shardMetadata
+		status.ServerIdx = (status.ServerIdx + nc.ReplicationFactor) % uint32(len(config.Servers))
+		shardsToAdd[shard.Id] = nc.Name
+	}
+	status.Namespaces[nc.Name] = nss
+
+	status.ShardIdGenerator += int64(nc.InitialShardCount)
+	return nil
 }
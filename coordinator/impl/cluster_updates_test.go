@@ -18,6 +18,7 @@ import (
 	"math"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -34,7 +35,7 @@ var (
 )
 
 func TestClientUpdates_ClusterInit(t *testing.T) {
-	newStatus, shardsAdded, shardsToRemove := applyClusterChanges(&model.ClusterConfig{
+	newStatus, shardsAdded, shardsToRemove, err := applyClusterChanges(&model.ClusterConfig{
 		Namespaces: []model.NamespaceConfig{{
 			Name:              "ns-1",
 			InitialShardCount: 1,
@@ -46,6 +47,7 @@ func TestClientUpdates_ClusterInit(t *testing.T) {
 		}},
 		Servers: []model.ServerAddress{s1, s2, s3, s4},
 	}, model.NewClusterStatus())
+	assert.NoError(t, err)
 
 	assert.Equal(t, &model.ClusterStatus{
 		Namespaces: map[string]model.NamespaceStatus{
@@ -102,7 +104,7 @@ func TestClientUpdates_ClusterInit(t *testing.T) {
 }
 
 func TestClientUpdates_NamespaceAdded(t *testing.T) {
-	newStatus, shardsAdded, shardsToRemove := applyClusterChanges(&model.ClusterConfig{
+	newStatus, shardsAdded, shardsToRemove, err := applyClusterChanges(&model.ClusterConfig{
 		Namespaces: []model.NamespaceConfig{{
 			Name:              "ns-1",
 			InitialShardCount: 1,
@@ -131,6 +133,7 @@ func TestClientUpdates_NamespaceAdded(t *testing.T) {
 		},
 	}, ShardIdGenerator: 1,
 		ServerIdx: 3})
+	assert.NoError(t, err)
 
 	assert.Equal(t, &model.ClusterStatus{
 		Namespaces: map[string]model.NamespaceStatus{
@@ -187,7 +190,7 @@ func TestClientUpdates_NamespaceAdded(t *testing.T) {
 }
 
 func TestClientUpdates_NamespaceRemoved(t *testing.T) {
-	newStatus, shardsAdded, shardsToRemove := applyClusterChanges(&model.ClusterConfig{
+	newStatus, shardsAdded, shardsToRemove, err := applyClusterChanges(&model.ClusterConfig{
 		Namespaces: []model.NamespaceConfig{{
 			Name:              "ns-1",
 			InitialShardCount: 1,
@@ -239,6 +242,7 @@ func TestClientUpdates_NamespaceRemoved(t *testing.T) {
 		},
 		ShardIdGenerator: 3,
 		ServerIdx:        1})
+	assert.NoError(t, err)
 
 	assert.Equal(t, &model.ClusterStatus{
 		Namespaces: map[string]model.NamespaceStatus{
@@ -294,3 +298,54 @@ func TestClientUpdates_NamespaceRemoved(t *testing.T) {
 	assert.Equal(t, []int64{1, 2}, shardsToRemove)
 	assert.Equal(t, map[int64]string{}, shardsAdded)
 }
+
+func TestClientUpdates_NamespaceSoftDeleted(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 1,
+			ReplicationFactor: 1,
+		}, {
+			Name:              "ns-2",
+			InitialShardCount: 1,
+			ReplicationFactor: 1,
+		}},
+		Servers:                      []model.ServerAddress{s1, s2},
+		NamespaceDeletionGracePeriod: time.Hour,
+	}
+
+	status, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// Remove ns-2 from the config
+	config.Namespaces = config.Namespaces[:1]
+	newStatus, shardsAdded, shardsToRemove, err := applyClusterChanges(config, status)
+	assert.NoError(t, err)
+	assert.Empty(t, shardsAdded)
+	assert.Empty(t, shardsToRemove)
+
+	assert.False(t, newStatus.Namespaces["ns-1"].IsSoftDeleted())
+	assert.True(t, newStatus.Namespaces["ns-2"].IsSoftDeleted())
+	assert.False(t, newStatus.Namespaces["ns-2"].IsDeleting())
+	assert.Equal(t, model.ShardStatusUnknown, newStatus.Namespaces["ns-2"].Shards[1].Status)
+	assert.True(t, namespacesChanged(status, newStatus))
+
+	// Applying the same config again doesn't change the deletion time
+	newStatus2, _, _, err := applyClusterChanges(config, newStatus)
+	assert.NoError(t, err)
+	assert.Equal(t, newStatus.Namespaces["ns-2"].DeletedAt, newStatus2.Namespaces["ns-2"].DeletedAt)
+	assert.False(t, namespacesChanged(newStatus, newStatus2))
+
+	// Adding the namespace back within the grace period restores it
+	config.Namespaces = append(config.Namespaces, model.NamespaceConfig{
+		Name:              "ns-2",
+		InitialShardCount: 1,
+		ReplicationFactor: 1,
+	})
+	restoredStatus, shardsAdded, shardsToRemove, err := applyClusterChanges(config, newStatus2)
+	assert.NoError(t, err)
+	assert.Empty(t, shardsAdded)
+	assert.Empty(t, shardsToRemove)
+	assert.False(t, restoredStatus.Namespaces["ns-2"].IsSoftDeleted())
+	assert.Equal(t, status.Namespaces["ns-2"].Shards[1].Ensemble, restoredStatus.Namespaces["ns-2"].Shards[1].Ensemble)
+}
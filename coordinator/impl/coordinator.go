@@ -16,25 +16,71 @@ package impl
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
+	"google.golang.org/grpc/status"
 	pb "google.golang.org/protobuf/proto"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/metrics"
 	"github.com/streamnative/oxia/coordinator/model"
 	"github.com/streamnative/oxia/proto"
 )
 
 var (
-	ErrNamespaceNotFound = errors.New("namespace not found")
+	ErrNamespaceNotFound  = errors.New("namespace not found")
+	ErrNoAvailableServers = errors.New("no available servers")
+	ErrShardNotFound      = errors.New("shard not found")
+	ErrShardsNotAdjacent  = errors.New("shards hash ranges are not adjacent")
+	ErrShardNotMergeable  = errors.New("shard has client sessions or entries that are not applied yet")
+
+	ErrShardMergeInProgress = errors.New("a shard of the namespace is being merged")
+	ErrMergedKeyExists      = errors.New("a key of the merged shard already exists in the shard it's merged into")
+
+	ErrNamespaceAlreadyExists = errors.New("namespace already exists")
+	ErrNamespaceNotDynamic    = errors.New("namespace is defined in the cluster config")
+	ErrInvalidNamespaceConfig = errors.New("invalid namespace config")
+	ErrInvalidEnsembleChange  = errors.New("invalid ensemble change")
+	ErrInvalidClusterConfig   = errors.New("invalid cluster config")
+
+	ErrServerNotFound   = errors.New("server not found")
+	ErrNotEnoughServers = errors.New("not enough servers left for the replication factor")
+
+	ErrServersDraining       = errors.New("the cluster cannot be rebalanced while servers are draining")
+	ErrInvalidLeaderTransfer = errors.New("the new leader must be a member of the ensemble of a healthy shard")
+	ErrLeaderNotTransferred  = errors.New("a different leader was elected")
+	ErrClusterFrozen         = errors.New("the cluster is in a maintenance freeze")
+	ErrShardHasNoLeader      = errors.New("the shard has no leader")
+
+	ErrServerStillRunning = errors.New("only a server that is not running can be replaced")
+	ErrInvalidReplacement = errors.New("the new server must be a different running server, not drained nor decommissioned")
+
+	ErrPlanNotFound   = errors.New("rebalance plan not found")
+	ErrPlanNotPending = errors.New("the rebalance plan was already executed")
+	ErrPlanInProgress = errors.New("a rebalance plan is being executed")
+	ErrPlanOutdated   = errors.New("the cluster has changed since the rebalance plan was computed")
+
+	ErrInvalidKeyPrefixACL  = errors.New("invalid key prefix acl")
+	ErrKeyPrefixACLNotFound = errors.New("key prefix acl not found")
 )
 
+// The soft-deleted namespaces are checked at least this often, to remove
+// their data once the grace period is over.
+const namespaceCleanupMaxInterval = 30 * time.Second
+
+// How often the progress of the server decommissions is checked.
+const decommissionCheckInterval = 10 * time.Second
+
 type ShardAssignmentsProvider interface {
 	WaitForNextUpdate(ctx context.Context, currentValue *proto.ShardAssignments) (*proto.ShardAssignments, error)
 }
@@ -54,7 +100,142 @@ type Coordinator interface {
 
 	NodeAvailabilityListener
 
+	ElectionPolicy
+
+	// SelectNewNode picks the least loaded running server that is not already
+	// part of the ensemble, to replace the member `from`, while honoring the
+	// anti-affinity rules of the namespace
+	SelectNewNode(namespace string, ensemble []model.ServerAddress, from model.ServerAddress) (*model.ServerAddress, error)
+
+	// MergeShard copies the records of a shard into the adjacent shard `into`,
+	// which then takes over its hash range, and deletes the shard. The shard
+	// is fenced, and unavailable, while its records are copied. If the merge
+	// fails, the copied records are removed and a new leader is elected.
+	MergeShard(namespace string, shard int64, into int64) error
+
+	// CreateNamespace adds a namespace that is not part of the cluster config,
+	// with the given number of shards and replication factor.
+	CreateNamespace(nc model.NamespaceConfig) error
+
+	// DeleteNamespace deletes a namespace that was added with CreateNamespace,
+	// along with all its shards. When a deletion grace period is configured,
+	// the namespace is soft-deleted and its data is removed once the period is over.
+	DeleteNamespace(namespace string) error
+
+	// DecommissionServer starts moving all the replicas out of the server,
+	// identified by either its public or internal address. It can be called
+	// again to follow the progress, and it returns the current state along
+	// with the number of shards that still have a replica on the server.
+	DecommissionServer(server string) (model.DecommissionState, int, error)
+
+	// RebalanceCluster moves the shard replicas so that every server has a
+	// similar number of them, eg: after new servers were added. The moves are
+	// applied in the background, unless dryRun is set. It returns the
+	// planned moves.
+	RebalanceCluster(dryRun bool) ([]SwapNodeAction, error)
+
+	// PlanRebalance computes the moves that RebalanceCluster would apply,
+	// along with the size of the data to copy, for the operator to review.
+	// The plan replaces the previous one, unless it's being executed.
+	PlanRebalance() (*RebalancePlan, error)
+
+	// ExecuteRebalancePlan applies the moves of the latest plan in the
+	// background, one at a time. The plan is rejected if the moves it would
+	// compute now are different, since the cluster has changed.
+	ExecuteRebalancePlan(id int64) error
+
+	// CurrentRebalancePlan returns the latest plan, along with the progress
+	// of its execution
+	CurrentRebalancePlan() (*RebalancePlan, bool)
+
+	// SetServerDrain puts the server in maintenance, or brings it back. A
+	// drained server keeps its replicas, but the shards it leads are moved
+	// to other leaders and no new replica is placed on it. It returns the
+	// number of shards that are still led by the server.
+	SetServerDrain(server string, drain bool) (int, error)
+
+	// ReplaceServer moves all the replicas of a failed server to the new
+	// server, one shard at a time, in the background. The new server recovers
+	// the shards from their leaders, and the failed server is not contacted
+	// anymore. It returns the number of shards that are being moved.
+	ReplaceServer(oldServer string, newServer string) (int, error)
+
+	// ListServers returns the servers of the cluster config, along with their
+	// health and the number of shards they host
+	ListServers() []ServerInfo
+
+	// ListOperations returns the changes to the cluster that are in progress
+	ListOperations() []Operation
+
+	// ListEvents returns the latest decisions taken by the coordinator and
+	// the changes it observed, from the oldest to the newest
+	ListEvents() []Event
+
+	// WatchEvents returns the channel where the new events are published, and
+	// the function to stop watching. With includeRecent, the events already
+	// kept by the coordinator are returned as well. The channel is closed when
+	// the watcher falls too far behind, or when the coordinator is closed.
+	WatchEvents(includeRecent bool) ([]Event, <-chan Event, func())
+
+	// TransferLeader moves the leadership of the shard to the given member of
+	// its ensemble, identified by either its public or internal address. It
+	// waits for the new leader to catch up with the current one before
+	// electing it.
+	TransferLeader(namespace string, shard int64, server string) error
+
+	// SetClusterFreeze starts or ends a maintenance freeze. While the cluster
+	// is frozen, the coordinator doesn't move any shard replica or leader on
+	// its own, though the leader elections still happen. The changes that
+	// were held back are applied once the freeze is lifted.
+	SetClusterFreeze(freeze bool, reason string) error
+
+	// SetKeyPrefixACL grants permissions to a principal on the keys of the
+	// namespace starting with the prefix, replacing the permissions it had on
+	// the same prefix. The ACLs are stored with the namespace status, and
+	// they're applied by the shard leaders.
+	SetKeyPrefixACL(namespace string, acl model.KeyPrefixACL) error
+
+	// RemoveKeyPrefixACL removes the permissions of a principal on a prefix
+	RemoveKeyPrefixACL(namespace string, principal string, prefix string) error
+
+	// ListKeyPrefixACLs returns the permissions granted on the key prefixes
+	// of the namespace
+	ListKeyPrefixACLs(namespace string) ([]model.KeyPrefixACL, error)
+
+	// ListSessions returns the sessions that are active on the leaders of
+	// the shards of the namespace, or of a single shard when one is given
+	ListSessions(namespace string, shard *int64) ([]SessionInfo, error)
+
+	// ForceCloseSession closes a session on the shard leader, as if it had
+	// expired, and deletes its ephemeral records
+	ForceCloseSession(namespace string, shard int64, sessionId int64) error
+
 	ClusterStatus() model.ClusterStatus
+
+	// Audit appends a record to the audit log, eg: for the changes requested
+	// through the admin API. The events of the coordinator are recorded
+	// automatically.
+	Audit(record AuditRecord)
+
+	// ListAuditRecords returns the latest records of the audit log, from the
+	// oldest to the newest. With a limit of 0, all the records are returned.
+	ListAuditRecords(limit int) ([]AuditRecord, error)
+
+	// State returns the in-memory state of the coordinator, to be mirrored
+	// by the standby coordinator replicas.
+	State() CoordinatorState
+}
+
+// CoordinatorOption customizes the coordinator.
+type CoordinatorOption func(c *coordinator)
+
+// WithAuditLog records the mutations of the cluster in the given audit log,
+// instead of keeping the latest ones in memory. The audit log is not closed
+// along with the coordinator.
+func WithAuditLog(auditLog AuditLog) CoordinatorOption {
+	return func(c *coordinator) {
+		c.auditLog = auditLog
+	}
 }
 
 type coordinator struct {
@@ -64,7 +245,10 @@ type coordinator struct {
 	MetadataProvider
 	clusterConfigProvider func() (model.ClusterConfig, error)
 	model.ClusterConfig
+	maintenanceWindows    []*maintenanceWindow
 	clusterConfigChangeCh chan any
+	rebalanceCh           chan any
+	forceRebalance        atomic.Bool
 
 	shardControllers map[int64]ShardController
 	nodeControllers  map[string]NodeController
@@ -79,6 +263,21 @@ type coordinator struct {
 	rpc             RpcProvider
 	log             *slog.Logger
 
+	loadBalancerMoves metrics.Counter
+	nodeFailures      metrics.Counter
+	operations        *operationsTracker
+	events            *eventLog
+	auditLog          AuditLog
+	tenantMetrics     *tenantMetrics
+	rebalancePlan     *RebalancePlan
+	nextPlanId        int64
+	electionThrottle  *electionThrottle
+	standbyState      *CoordinatorState
+
+	// The shard loads mirrored from the previous coordinator, until they
+	// are sampled again
+	restoredLoads map[int64]ShardLoad
+
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -86,17 +285,27 @@ type coordinator struct {
 func NewCoordinator(metadataProvider MetadataProvider,
 	clusterConfigProvider func() (model.ClusterConfig, error),
 	clusterConfigNotificationsCh chan any,
-	rpc RpcProvider) (Coordinator, error) {
+	rpc RpcProvider, opts ...CoordinatorOption) (Coordinator, error) {
 	initialClusterConf, err := clusterConfigProvider()
 	if err != nil {
 		return nil, err
 	}
 
+	if err = validateClusterConfig(&initialClusterConf); err != nil {
+		return nil, err
+	}
+	maintenanceWindows, err := parseMaintenanceWindows(&initialClusterConf)
+	if err != nil {
+		return nil, err
+	}
+
 	c := &coordinator{
 		MetadataProvider:      metadataProvider,
 		clusterConfigProvider: clusterConfigProvider,
 		clusterConfigChangeCh: clusterConfigNotificationsCh,
+		rebalanceCh:           make(chan any, 1),
 		ClusterConfig:         initialClusterConf,
+		maintenanceWindows:    maintenanceWindows,
 		shardControllers:      make(map[int64]ShardController),
 		nodeControllers:       make(map[string]NodeController),
 		drainingNodes:         make(map[string]NodeController),
@@ -104,7 +313,20 @@ func NewCoordinator(metadataProvider MetadataProvider,
 		log: slog.With(
 			slog.String("component", "coordinator"),
 		),
+		loadBalancerMoves: metrics.NewCounter("oxia_coordinator_load_balancer_moves",
+			"The number of shard replicas moved to balance the load across the servers", "count", nil),
+		nodeFailures: metrics.NewCounter("oxia_coordinator_node_failures_detected",
+			"The number of times a server was detected as failed", "count", nil),
+		tenantMetrics:    newTenantMetrics(),
+		operations:       newOperationsTracker(),
+		events:           newEventLog(eventLogSize),
+		auditLog:         NewMemoryAuditLog(eventLogSize),
+		electionThrottle: newElectionThrottle(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.events.onRecord = c.auditEvent
 
 	c.ctx, c.cancel = context.WithCancel(context.Background())
 
@@ -119,6 +341,8 @@ func NewCoordinator(metadataProvider MetadataProvider,
 		c.nodeControllers[sa.Internal] = NewNodeController(sa, c, c, c.rpc)
 	}
 
+	c.restoreStandbyState()
+
 	if c.clusterStatus == nil {
 		// Before initializing the cluster, it's better to make sure we
 		// have all the nodes available, otherwise the coordinator might be
@@ -137,6 +361,17 @@ func NewCoordinator(metadataProvider MetadataProvider,
 
 	c.initialShardController()
 
+	if plan := c.rebalancePlan; plan != nil && plan.State == PlanStateExecuting {
+		c.log.Info(
+			"Resuming the execution of the rebalance plan",
+			slog.Int64("plan", plan.Id),
+			slog.Int("done-moves", plan.DoneMoves()),
+		)
+		c.startRebalancePlan(plan)
+	}
+
+	c.resumeMerges()
+
 	go common.DoWithLabels(
 		c.ctx,
 		map[string]string{
@@ -145,6 +380,22 @@ func NewCoordinator(metadataProvider MetadataProvider,
 		c.waitForExternalEvents,
 	)
 
+	go common.DoWithLabels(
+		c.ctx,
+		map[string]string{
+			"oxia": "coordinator-load-balancer",
+		},
+		c.runLoadBalancer,
+	)
+
+	go common.DoWithLabels(
+		c.ctx,
+		map[string]string{
+			"oxia": "coordinator-namespace-cleanup",
+		},
+		c.runNamespaceCleanup,
+	)
+
 	return c, nil
 }
 
@@ -198,9 +449,11 @@ func (c *coordinator) initialAssignment() error {
 		slog.Any("clusterConfig", c.ClusterConfig),
 	)
 
-	clusterStatus, _, _ := applyClusterChanges(&c.ClusterConfig, model.NewClusterStatus())
+	clusterStatus, _, _, err := applyClusterChanges(&c.ClusterConfig, model.NewClusterStatus())
+	if err != nil {
+		return err
+	}
 
-	var err error
 	if c.metadataVersion, err = c.MetadataProvider.Store(clusterStatus, MetadataNotExists); err != nil {
 		return err
 	}
@@ -216,11 +469,12 @@ func (c *coordinator) applyNewClusterConfig() error {
 		slog.Any("metadataVersion", c.metadataVersion),
 	)
 
-	clusterStatus, shardsToAdd, shardsToDelete := applyClusterChanges(&c.ClusterConfig, c.clusterStatus)
-
-	if len(shardsToAdd) > 0 || len(shardsToDelete) > 0 {
-		var err error
+	clusterStatus, shardsToAdd, shardsToDelete, err := applyClusterChanges(&c.ClusterConfig, c.clusterStatus)
+	if err != nil {
+		return err
+	}
 
+	if len(shardsToAdd) > 0 || len(shardsToDelete) > 0 || namespacesChanged(c.clusterStatus, clusterStatus) {
 		if c.metadataVersion, err = c.MetadataProvider.Store(clusterStatus, c.metadataVersion); err != nil {
 			return err
 		}
@@ -231,6 +485,8 @@ func (c *coordinator) applyNewClusterConfig() error {
 }
 
 func (c *coordinator) Close() error {
+	c.cancel()
+
 	var err error
 
 	for _, sc := range c.shardControllers {
@@ -244,10 +500,20 @@ func (c *coordinator) Close() error {
 	for _, nc := range c.drainingNodes {
 		err = multierr.Append(err, nc.Close())
 	}
+
+	c.events.close()
+	c.tenantMetrics.close()
 	return err
 }
 
 func (c *coordinator) NodeBecameUnavailable(node model.ServerAddress) {
+	c.nodeFailures.Inc()
+	c.events.record(Event{
+		Type:        EventNodeFailed,
+		Server:      &node,
+		Description: "the server stopped responding to the health checks",
+	})
+
 	c.Lock()
 
 	if nc, ok := c.drainingNodes[node.Internal]; ok {
@@ -291,7 +557,7 @@ func (c *coordinator) InitiateLeaderElection(namespace string, shard int64, meta
 		return ErrNamespaceNotFound
 	}
 
-	ns.Shards[shard] = metadata
+	ns.Shards[shard] = withCurrentHashRange(ns, shard, metadata)
 
 	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
 	if err != nil {
@@ -299,6 +565,15 @@ func (c *coordinator) InitiateLeaderElection(namespace string, shard int64, meta
 	}
 
 	c.metadataVersion = newMetadataVersion
+	// Keep the new term, for the next updates of the cluster status not to
+	// store the previous one
+	c.clusterStatus = cs
+	c.events.record(Event{
+		Type:        EventLeaderElectionStarted,
+		Namespace:   namespace,
+		Shard:       &shard,
+		Description: fmt.Sprintf("term %d", metadata.Term),
+	})
 	return nil
 }
 
@@ -312,7 +587,8 @@ func (c *coordinator) ElectedLeader(namespace string, shard int64, metadata mode
 		return ErrNamespaceNotFound
 	}
 
-	ns.Shards[shard] = metadata
+	previousEnsemble := ns.Shards[shard].Ensemble
+	ns.Shards[shard] = withCurrentHashRange(ns, shard, metadata)
 
 	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
 	if err != nil {
@@ -321,11 +597,38 @@ func (c *coordinator) ElectedLeader(namespace string, shard int64, metadata mode
 
 	c.metadataVersion = newMetadataVersion
 	c.clusterStatus = cs
+	if !sameEnsemble(previousEnsemble, metadata.Ensemble) {
+		c.events.record(Event{
+			Type:        EventEnsembleChanged,
+			Namespace:   namespace,
+			Shard:       &shard,
+			Description: fmt.Sprintf("%s -> %s", ensembleString(previousEnsemble), ensembleString(metadata.Ensemble)),
+		})
+	}
+	c.events.record(Event{
+		Type:        EventLeaderElected,
+		Namespace:   namespace,
+		Shard:       &shard,
+		Server:      metadata.Leader,
+		Description: fmt.Sprintf("term %d", metadata.Term),
+	})
 
 	c.computeNewAssignments()
 	return nil
 }
 
+// The hash range of the shards and their merges are only changed by the
+// coordinator (eg: when dropping empty shards), so we're keeping them over the
+// metadata updates coming from the shard controllers.
+func withCurrentHashRange(ns model.NamespaceStatus, shard int64, metadata model.ShardMetadata) model.ShardMetadata {
+	if current, ok := ns.Shards[shard]; ok {
+		metadata.Int32HashRange = current.Int32HashRange
+		metadata.KeyRange = current.KeyRange
+		metadata.Merge = current.Merge
+	}
+	return metadata
+}
+
 func (c *coordinator) ShardDeleted(namespace string, shard int64) error {
 	c.Lock()
 	defer c.Unlock()
@@ -348,6 +651,11 @@ func (c *coordinator) ShardDeleted(namespace string, shard int64) error {
 
 	c.metadataVersion = newMetadataVersion
 	c.clusterStatus = cs
+	c.events.record(Event{
+		Type:      EventShardDeleted,
+		Namespace: namespace,
+		Shard:     &shard,
+	})
 
 	c.computeNewAssignments()
 	return nil
@@ -361,154 +669,1779 @@ func (c *coordinator) computeNewAssignments() {
 
 	// Update the leader for the shards on all the namespaces
 	for name, ns := range c.clusterStatus.Namespaces {
+		if ns.IsSoftDeleted() {
+			// The namespace is not visible to the clients anymore
+			continue
+		}
+
 		nsAssignments := &proto.NamespaceShardsAssignment{
 			Assignments:    make([]*proto.ShardAssignment, 0),
-			ShardKeyRouter: proto.ShardKeyRouter_XXHASH3,
+			ShardKeyRouter: shardKeyRouter(ns.Partitioning),
+		}
+		if nc := findNamespaceConfig(&c.ClusterConfig, name); nc != nil {
+			nsAssignments.Policies = toProtoNamespacePolicies(nc.Policies)
+		}
+		tenant := findTenantConfig(&c.ClusterConfig, namespaceTenant(&c.ClusterConfig, name, ns))
+		if tenant != nil {
+			if nsAssignments.Policies == nil {
+				nsAssignments.Policies = &proto.NamespacePolicies{}
+			}
+			nsAssignments.Policies.Tenant = toProtoTenantPolicies(tenant)
+		}
+		if authz := toProtoNamespaceAuthorization(c.ClusterConfig.Authorization, tenant, name, ns.KeyPrefixACLs); authz != nil {
+			if nsAssignments.Policies == nil {
+				nsAssignments.Policies = &proto.NamespacePolicies{}
+			}
+			nsAssignments.Policies.Authorization = authz
 		}
 
 		for shard, a := range ns.Shards {
-			var leader string
-			if a.Leader != nil {
-				leader = a.Leader.Public
-			}
 			if a.Status != model.ShardStatusDeleting {
-				nsAssignments.Assignments = append(nsAssignments.Assignments,
-					&proto.ShardAssignment{
-						ShardId: shard,
-						Leader:  leader,
-						ShardBoundaries: &proto.ShardAssignment_Int32HashRange{
-							Int32HashRange: &proto.Int32HashRange{
-								MinHashInclusive: a.Int32HashRange.Min,
-								MaxHashInclusive: a.Int32HashRange.Max,
-							},
-						},
-					},
-				)
+				nsAssignments.Assignments = append(nsAssignments.Assignments, toShardAssignment(shard, a))
 			}
 		}
 
 		c.assignments.Namespaces[name] = nsAssignments
 	}
 
+	c.tenantMetrics.update(tenantsUsage(&c.ClusterConfig, c.clusterStatus))
 	c.assignmentsChanged.Broadcast()
 }
 
-func (c *coordinator) ClusterStatus() model.ClusterStatus {
+func (c *coordinator) WaitForElectionRound(ctx context.Context, servers []model.ServerAddress) error {
+	c.Lock()
+	config := c.ClusterConfig.Elections
+	c.Unlock()
+
+	return c.electionThrottle.wait(ctx, config, servers)
+}
+
+func (c *coordinator) LeaderReturnTimeout() time.Duration {
 	c.Lock()
 	defer c.Unlock()
-	return *c.clusterStatus.Clone()
+
+	if c.ClusterConfig.Elections == nil {
+		return 0
+	}
+	return c.ClusterConfig.Elections.LeaderReturnTimeout
 }
 
-func (c *coordinator) waitForExternalEvents() {
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
+func (c *coordinator) IsDraining(server model.ServerAddress) bool {
+	c.Lock()
+	defer c.Unlock()
 
-		case <-c.clusterConfigChangeCh:
-			c.log.Info("Received cluster config change event")
-			if err := c.handleClusterConfigUpdated(); err != nil {
-				c.log.Warn(
-					"Failed to update cluster config",
-					slog.Any("error", err),
-				)
-			}
+	return c.clusterStatus.IsDraining(server)
+}
 
-			if err := c.rebalanceCluster(); err != nil {
-				c.log.Warn(
-					"Failed to rebalance cluster",
-					slog.Any("error", err),
-				)
-			}
-		}
+func (c *coordinator) StuckElectionTimeout() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.ClusterConfig.Elections == nil || c.ClusterConfig.Elections.StuckTimeout == 0 {
+		return defaultStuckElectionTimeout
 	}
+	return c.ClusterConfig.Elections.StuckTimeout
 }
 
-func (c *coordinator) handleClusterConfigUpdated() error {
+func (c *coordinator) SelectNewNode(namespace string, ensemble []model.ServerAddress, from model.ServerAddress) (*model.ServerAddress, error) {
 	c.Lock()
 	defer c.Unlock()
 
-	newClusterConfig, err := c.clusterConfigProvider()
-	if err != nil {
-		return errors.Wrap(err, "failed to read cluster configuration")
+	if c.clusterStatus.IsFrozen() {
+		return nil, ErrClusterFrozen
 	}
 
-	if reflect.DeepEqual(newClusterConfig, c.ClusterConfig) {
-		c.log.Info("No cluster config changes detected")
-		return nil
+	pc := placementConfig(&c.ClusterConfig, c.clusterStatus)
+	shardsPerServer, _ := getShardsPerServer(pc.Servers, c.clusterStatus)
+	policy := newPlacementPolicy(pc).withLoads(c.shardLoads())
+
+	candidates := make([]model.ServerAddress, 0, len(pc.Servers))
+	for _, sa := range pc.Servers {
+		if nc, ok := c.nodeControllers[sa.Internal]; ok && nc.Status() == Running && !listContains(ensemble, sa) {
+			candidates = append(candidates, sa)
+		}
+	}
+
+	others := make([]model.ServerAddress, 0, len(ensemble))
+	for _, sa := range ensemble {
+		if sa != from {
+			others = append(others, sa)
+		}
+	}
+
+	candidate, ok := policy.strategy.SelectServer(policy.newAssignmentContext(shardsPerServer), namespace, others, candidates)
+	if !ok {
+		return nil, ErrNoAvailableServers
+	}
+	return &candidate, nil
+}
+
+func (c *coordinator) MergeShard(namespace string, shard int64, into int64) error {
+	c.Lock()
+	if c.clusterStatus.IsFrozen() {
+		c.Unlock()
+		return ErrClusterFrozen
+	}
+	ns, ok := c.clusterStatus.Namespaces[namespace]
+	if !ok || ns.IsSoftDeleted() {
+		c.Unlock()
+		return ErrNamespaceNotFound
+	}
+
+	shardMetadata, shardOk := ns.Shards[shard]
+	intoMetadata, intoOk := ns.Shards[into]
+	sc := c.shardControllers[shard]
+	if !shardOk || !intoOk || sc == nil ||
+		shardMetadata.Status == model.ShardStatusDeleting || intoMetadata.Status == model.ShardStatusDeleting {
+		c.Unlock()
+		return ErrShardNotFound
+	}
+
+	for _, sm := range ns.Shards {
+		if sm.Merge != nil {
+			c.Unlock()
+			return ErrShardMergeInProgress
+		}
+	}
+	if _, err := extendShardRange(intoMetadata, shardMetadata); err != nil {
+		c.Unlock()
+		return err
+	}
+	if intoMetadata.Leader == nil {
+		c.Unlock()
+		return ErrShardHasNoLeader
+	}
+
+	// The intent is stored before the shard is fenced, so that a new
+	// coordinator finds the records that were staged in the shard `into`
+	err := c.storeShardMerge(namespace, shard, &model.ShardMerge{Into: into, Phase: model.ShardMergePhaseCopying})
+	c.Unlock()
+	if err != nil {
+		return err
 	}
 
 	c.log.Info(
-		"Detected change in cluster config",
-		slog.Any("clusterConfig", c.ClusterConfig),
-		slog.Any("newClusterConfig", newClusterConfig),
-		slog.Any("metadataVersion", c.metadataVersion),
+		"Merging shard",
+		slog.String("namespace", namespace),
+		slog.Int64("shard", shard),
+		slog.Int64("into-shard", into),
 	)
 
-	c.checkClusterNodeChanges(newClusterConfig)
+	// Stop the writes on the shard that is going away. A new leader is
+	// elected if it fails.
+	term, replicas, err := sc.FenceForMerge()
+	if err != nil {
+		_ = c.updateShardMerge(namespace, shard, nil)
+		return err
+	}
 
-	clusterStatus, shardsToAdd, shardsToDelete := applyClusterChanges(&newClusterConfig, c.clusterStatus)
+	records, err := c.copyRecords(namespace, shard, term, replicas, into)
+	if err == nil {
+		err = c.updateShardMerge(namespace, shard, &model.ShardMerge{Into: into, Phase: model.ShardMergePhaseCommitting})
+	}
+	if err != nil {
+		c.abortMerge(namespace, shard, into, sc, err)
+		if status.Code(err) == common.CodeKeyAlreadyExists {
+			return ErrMergedKeyExists
+		}
+		return err
+	}
 
-	for shard, namespace := range shardsToAdd {
-		shardMetadata := clusterStatus.Namespaces[namespace].Shards[shard]
-		c.shardControllers[shard] = NewShardController(namespace, shard, shardMetadata, c.rpc, c)
-		slog.Info(
-			"Added new shard",
-			slog.Int64("shard", shard),
+	if err = c.completeMerge(namespace, shard, into, sc); err != nil {
+		return err
+	}
+
+	c.log.Info(
+		"Merged shard",
+		slog.String("namespace", namespace),
+		slog.Int64("shard", shard),
+		slog.Int64("into-shard", into),
+		slog.Int("records", records),
+	)
+	return nil
+}
+
+// copyRecords exports the records of the fenced shard from one of its
+// replicas and stages them in the shard `into`, through its leader. The
+// staged records are not reachable by the clients until the merge is
+// committed. It returns the number of records that were copied.
+func (c *coordinator) copyRecords(namespace string, shard int64, term int64, replicas []model.ServerAddress,
+	into int64) (int, error) {
+	var err error
+	for _, replica := range replicas {
+		records := 0
+		if err = c.copyRecordsFrom(namespace, shard, term, replica, into, &records); err == nil {
+			return records, nil
+		}
+
+		c.log.Warn(
+			"Failed to copy the records of the shard",
 			slog.String("namespace", namespace),
-			slog.Any("shard-metadata", shardMetadata),
+			slog.Int64("shard", shard),
+			slog.Any("server", replica),
+			slog.Any("error", err),
 		)
+		if status.Code(err) == common.CodeKeyAlreadyExists {
+			break
+		}
 	}
+	return 0, err
+}
 
-	for _, shard := range shardsToDelete {
-		s, ok := c.shardControllers[shard]
-		if ok {
-			s.DeleteShard()
-		}
+func (c *coordinator) copyRecordsFrom(namespace string, shard int64, term int64, replica model.ServerAddress,
+	into int64, records *int) error {
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+
+	stream, err := c.rpc.ExportRecords(ctx, replica, &proto.ExportRecordsRequest{
+		Namespace: namespace,
+		ShardId:   shard,
+		Term:      term,
+	})
+	if err != nil {
+		return err
 	}
 
-	c.ClusterConfig = newClusterConfig
-	c.clusterStatus = clusterStatus
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
 
-	c.computeNewAssignments()
-	return nil
+		if err = c.importRecords(ctx, namespace, into, &proto.ImportRecordsRequest{
+			MergedShardId: shard,
+			Records:       res.Records,
+		}); err != nil {
+			return err
+		}
+		*records += len(res.Records)
+	}
 }
 
-//nolint:unparam
-func (c *coordinator) rebalanceCluster() error {
+// importRecords sends the request to the leader of the shard `into`, in its
+// current term.
+func (c *coordinator) importRecords(ctx context.Context, namespace string, into int64, req *proto.ImportRecordsRequest) error {
 	c.Lock()
-	actions := rebalanceCluster(c.ClusterConfig.Servers, c.clusterStatus)
+	intoMetadata, ok := c.clusterStatus.Namespaces[namespace].Shards[into]
 	c.Unlock()
+	if !ok {
+		return backoff.Permanent(ErrShardNotFound)
+	}
+	if intoMetadata.Leader == nil {
+		return ErrShardHasNoLeader
+	}
 
-	for _, swapAction := range actions {
-		c.log.Info(
-			"Applying swap action",
-			slog.Any("swap-action", swapAction),
-		)
+	req.Namespace = namespace
+	req.ShardId = into
+	req.Term = intoMetadata.Term
+	_, err := c.rpc.ImportRecords(ctx, *intoMetadata.Leader, req)
+	return err
+}
 
-		c.Lock()
-		sc, ok := c.shardControllers[swapAction.Shard]
-		c.Unlock()
-		if !ok {
-			c.log.Warn(
-				"Shard controller not found",
-				slog.Int64("shard", swapAction.Shard),
-			)
-			continue
+// completeMerge moves the staged records into the key space of the shard
+// `into`, extends its range over the range of the merged shard, and deletes
+// the merged shard. The merge is retried until it completes, or until the
+// coordinator is closed, in which case it's resumed by the next coordinator.
+// It's aborted if any of the keys was written in the shard `into` since the
+// records were staged.
+func (c *coordinator) completeMerge(namespace string, shard int64, into int64, sc ShardController) error {
+	var intoMetadata model.ShardMetadata
+	err := backoff.RetryNotify(func() error {
+		// Committing again a merge that was already committed has no effect
+		err := c.importRecords(c.ctx, namespace, into, &proto.ImportRecordsRequest{
+			MergedShardId: shard,
+			Commit:        true,
+		})
+		if status.Code(err) == common.CodeKeyAlreadyExists {
+			return backoff.Permanent(err)
+		} else if err != nil {
+			return err
 		}
 
-		if err := sc.SwapNode(swapAction.From, swapAction.To); err != nil {
-			c.log.Warn(
-				"Failed to swap node",
-				slog.Any("error", err),
-				slog.Any("swap-action", swapAction),
-			)
+		c.Lock()
+		defer c.Unlock()
+		if intoMetadata, err = c.storeMergedShard(namespace, shard, into); err != nil {
+			return err
 		}
+		c.computeNewAssignments()
+		return nil
+	}, common.NewBackOff(c.ctx), func(err error, duration time.Duration) {
+		c.log.Warn(
+			"Failed to complete the merge of the shard, retrying later",
+			slog.String("namespace", namespace),
+			slog.Int64("shard", shard),
+			slog.Int64("into-shard", into),
+			slog.Duration("retry-after", duration),
+			slog.Any("error", err),
+		)
+	})
+
+	if status.Code(err) == common.CodeKeyAlreadyExists {
+		c.abortMerge(namespace, shard, into, sc, err)
+		return ErrMergedKeyExists
+	} else if err != nil {
+		return err
 	}
 
+	sc.DeleteShard()
+
+	c.log.Info(
+		"Completed the merge of the shard",
+		slog.String("namespace", namespace),
+		slog.Int64("shard", shard),
+		slog.Int64("into-shard", into),
+		slog.Any("hash-range", intoMetadata.Int32HashRange),
+		slog.Any("key-range", intoMetadata.KeyRange),
+	)
 	return nil
 }
 
+// storeMergedShard extends the range of the shard `into` over the range of
+// the merged shard, which is marked for deletion.
+// Must be called with the lock held.
+func (c *coordinator) storeMergedShard(namespace string, shard int64, into int64) (model.ShardMetadata, error) {
+	cs := c.clusterStatus.Clone()
+	nss := cs.Namespaces[namespace]
+
+	intoMetadata, err := extendShardRange(nss.Shards[into], nss.Shards[shard])
+	if err != nil {
+		return intoMetadata, err
+	}
+	nss.Shards[into] = intoMetadata
+
+	shardMetadata := nss.Shards[shard]
+	shardMetadata.Status = model.ShardStatusDeleting
+	shardMetadata.Merge = nil
+	nss.Shards[shard] = shardMetadata
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return intoMetadata, err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+	return intoMetadata, nil
+}
+
+// storeShardMerge stores the intent of merging the shard, or clears it when
+// `merge` is nil.
+// Must be called with the lock held.
+func (c *coordinator) storeShardMerge(namespace string, shard int64, merge *model.ShardMerge) error {
+	cs := c.clusterStatus.Clone()
+	nss, ok := cs.Namespaces[namespace]
+	if !ok {
+		return ErrNamespaceNotFound
+	}
+	shardMetadata, ok := nss.Shards[shard]
+	if !ok {
+		return ErrShardNotFound
+	}
+
+	shardMetadata.Merge = merge
+	nss.Shards[shard] = shardMetadata
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+	return nil
+}
+
+// updateShardMerge stores the new phase of the merge, retrying until it
+// succeeds or until the coordinator is closed.
+func (c *coordinator) updateShardMerge(namespace string, shard int64, merge *model.ShardMerge) error {
+	return backoff.RetryNotify(func() error {
+		c.Lock()
+		defer c.Unlock()
+		err := c.storeShardMerge(namespace, shard, merge)
+		if errors.Is(err, ErrNamespaceNotFound) || errors.Is(err, ErrShardNotFound) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, common.NewBackOff(c.ctx), func(err error, duration time.Duration) {
+		c.log.Warn(
+			"Failed to store the merge of the shard, retrying later",
+			slog.String("namespace", namespace),
+			slog.Int64("shard", shard),
+			slog.Any("merge", merge),
+			slog.Duration("retry-after", duration),
+			slog.Any("error", err),
+		)
+	})
+}
+
+// abortMerge elects a new leader for the fenced shard and drops the records
+// that were staged in the shard `into`. The abort is stored first, so that
+// a new coordinator doesn't resume the commit of the merge once the shard is
+// written again.
+func (c *coordinator) abortMerge(namespace string, shard int64, into int64, sc ShardController, err error) {
+	c.log.Warn(
+		"Failed to merge shard, electing a new leader",
+		slog.String("namespace", namespace),
+		slog.Int64("shard", shard),
+		slog.Int64("into-shard", into),
+		slog.Any("error", err),
+	)
+
+	if err := c.updateShardMerge(namespace, shard, &model.ShardMerge{Into: into, Phase: model.ShardMergePhaseAborting}); err != nil {
+		return
+	}
+	sc.ElectLeader()
+	c.dropMergedRecords(namespace, shard, into)
+}
+
+// dropMergedRecords removes the records that were staged in the shard `into`
+// and clears the intent of merging the shard.
+func (c *coordinator) dropMergedRecords(namespace string, shard int64, into int64) {
+	if err := backoff.RetryNotify(func() error {
+		return c.importRecords(c.ctx, namespace, into, &proto.ImportRecordsRequest{
+			MergedShardId: shard,
+			Abort:         true,
+		})
+	}, common.NewBackOff(c.ctx), func(err error, duration time.Duration) {
+		c.log.Warn(
+			"Failed to remove the staged records of the merged shard, retrying later",
+			slog.String("namespace", namespace),
+			slog.Int64("shard", shard),
+			slog.Int64("into-shard", into),
+			slog.Duration("retry-after", duration),
+			slog.Any("error", err),
+		)
+	}); err != nil {
+		return
+	}
+
+	_ = c.updateShardMerge(namespace, shard, nil)
+}
+
+// resumeMerges completes the merges that were being committed when the
+// previous coordinator stopped, and aborts the other ones.
+func (c *coordinator) resumeMerges() {
+	c.Lock()
+	defer c.Unlock()
+
+	for namespace, ns := range c.clusterStatus.Namespaces {
+		for shard, sm := range ns.Shards {
+			if sm.Merge == nil {
+				continue
+			}
+
+			c.log.Info(
+				"Resuming the merge of the shard",
+				slog.String("namespace", namespace),
+				slog.Int64("shard", shard),
+				slog.Int64("into-shard", sm.Merge.Into),
+				slog.Any("phase", sm.Merge.Phase),
+			)
+
+			sc := c.shardControllers[shard]
+			merge := *sm.Merge
+			go common.DoWithLabels(
+				c.ctx,
+				map[string]string{
+					"oxia":      "coordinator-resume-merge",
+					"namespace": namespace,
+					"shard":     fmt.Sprintf("%d", shard),
+				},
+				func() {
+					if merge.Phase == model.ShardMergePhaseCommitting {
+						_ = c.completeMerge(namespace, shard, merge.Into, sc)
+					} else {
+						// The shard elects a leader on its own
+						c.dropMergedRecords(namespace, shard, merge.Into)
+					}
+				},
+			)
+		}
+	}
+}
+
+func (c *coordinator) CreateNamespace(nc model.NamespaceConfig) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := validateNamespaceConfig(&c.ClusterConfig, nc); err != nil {
+		return err
+	}
+
+	if _, ok := c.clusterStatus.Namespaces[nc.Name]; ok || findNamespaceConfig(&c.ClusterConfig, nc.Name) != nil {
+		return ErrNamespaceAlreadyExists
+	}
+
+	if err := checkNewTenantNamespace(&c.ClusterConfig, c.clusterStatus, nc); err != nil {
+		return err
+	}
+
+	cs := c.clusterStatus.Clone()
+	shardsToAdd := map[int64]string{}
+	pc := placementConfig(&c.ClusterConfig, c.clusterStatus)
+	// The new namespace is placed according to its own config, eg: in the
+	// pool of its tenant
+	pc.Namespaces = append(slices.Clone(pc.Namespaces), nc)
+	if err := addNamespace(pc, newPlacementPolicy(pc).withLoads(c.shardLoads()), cs, nc, shardsToAdd); err != nil {
+		return err
+	}
+
+	nss := cs.Namespaces[nc.Name]
+	nss.Dynamic = true
+	cs.Namespaces[nc.Name] = nss
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+
+	for shard := range shardsToAdd {
+		c.shardControllers[shard] = NewShardController(nc.Name, shard, nss.Shards[shard], c.rpc, c)
+	}
+
+	c.computeNewAssignments()
+	c.events.record(Event{
+		Type:        EventNamespaceCreated,
+		Namespace:   nc.Name,
+		Description: fmt.Sprintf("%d shards, replication factor %d", nc.InitialShardCount, nc.ReplicationFactor),
+	})
+
+	c.log.Info(
+		"Created namespace",
+		slog.String("namespace", nc.Name),
+		slog.String("tenant", nc.Tenant),
+		slog.Any("initial-shard-count", nc.InitialShardCount),
+		slog.Any("replication-factor", nc.ReplicationFactor),
+	)
+	return nil
+}
+
+func (c *coordinator) DeleteNamespace(namespace string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	ns, ok := c.clusterStatus.Namespaces[namespace]
+	if !ok || ns.IsSoftDeleted() {
+		return ErrNamespaceNotFound
+	}
+
+	if !ns.Dynamic {
+		return ErrNamespaceNotDynamic
+	}
+
+	if c.ClusterConfig.NamespaceDeletionGracePeriod <= 0 {
+		return c.deleteNamespaceShards(namespace)
+	}
+
+	cs := c.clusterStatus.Clone()
+	nss := cs.Namespaces[namespace]
+	now := time.Now()
+	nss.DeletedAt = &now
+	cs.Namespaces[namespace] = nss
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+	c.computeNewAssignments()
+	c.events.record(Event{
+		Type:        EventNamespaceDeleted,
+		Namespace:   namespace,
+		Description: fmt.Sprintf("soft-deleted, the data is removed after %s", c.ClusterConfig.NamespaceDeletionGracePeriod),
+	})
+
+	c.log.Info(
+		"Soft-deleted namespace",
+		slog.String("namespace", namespace),
+		slog.Duration("grace-period", c.ClusterConfig.NamespaceDeletionGracePeriod),
+	)
+	return nil
+}
+
+// Mark all the shards of the namespace as being deleted, and remove their data
+// from the servers. This is called while already holding the lock on the coordinator.
+func (c *coordinator) deleteNamespaceShards(namespace string) error {
+	cs := c.clusterStatus.Clone()
+	nss := cs.Namespaces[namespace]
+	for shard, sm := range nss.Shards {
+		sm.Status = model.ShardStatusDeleting
+		nss.Shards[shard] = sm
+	}
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+	c.computeNewAssignments()
+
+	for shard := range nss.Shards {
+		if sc, ok := c.shardControllers[shard]; ok {
+			sc.DeleteShard()
+		}
+	}
+	c.events.record(Event{
+		Type:        EventNamespaceDeleted,
+		Namespace:   namespace,
+		Description: fmt.Sprintf("deleting %d shards", len(nss.Shards)),
+	})
+
+	c.log.Info(
+		"Deleting namespace",
+		slog.String("namespace", namespace),
+	)
+	return nil
+}
+
+func (c *coordinator) runNamespaceCleanup() {
+	for {
+		c.Lock()
+		interval := namespaceCleanupMaxInterval
+		if gp := c.ClusterConfig.NamespaceDeletionGracePeriod; gp > 0 && gp < interval {
+			interval = gp
+		}
+		c.Unlock()
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(interval):
+			c.deleteExpiredNamespaces(time.Now())
+		}
+	}
+}
+
+// Delete the data of the soft-deleted namespaces whose grace period is over.
+// The deletions are postponed while the cluster is frozen.
+func (c *coordinator) deleteExpiredNamespaces(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.clusterStatus.IsFrozen() {
+		return
+	}
+
+	for name, ns := range c.clusterStatus.Namespaces {
+		if !ns.IsSoftDeleted() || ns.IsDeleting() ||
+			now.Before(ns.DeletedAt.Add(c.ClusterConfig.NamespaceDeletionGracePeriod)) {
+			continue
+		}
+
+		if err := c.deleteNamespaceShards(name); err != nil {
+			c.log.Warn(
+				"Failed to delete namespace",
+				slog.String("namespace", name),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (c *coordinator) DecommissionServer(server string) (model.DecommissionState, int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	sa := c.findServer(server)
+	if sa == nil {
+		return "", 0, ErrServerNotFound
+	}
+
+	state := c.clusterStatus.Servers[sa.Internal].Decommission
+	if state == "" {
+		if !canDecommission(&c.ClusterConfig, c.clusterStatus, *sa) {
+			return "", 0, ErrNotEnoughServers
+		}
+
+		cs := c.clusterStatus.Clone()
+		if cs.Servers == nil {
+			cs.Servers = map[string]model.ServerStatus{}
+		}
+		state = model.DecommissionStateInProgress
+		cs.Servers[sa.Internal] = model.ServerStatus{Decommission: state}
+
+		newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+		if err != nil {
+			return "", 0, err
+		}
+
+		c.metadataVersion = newMetadataVersion
+		c.clusterStatus = cs
+
+		c.log.Info(
+			"Started decommissioning server",
+			slog.Any("server", sa),
+		)
+		c.events.record(Event{
+			Type:        EventServerStatusChanged,
+			Server:      sa,
+			Description: "decommission started",
+		})
+	}
+
+	if state == model.DecommissionStateInProgress {
+		// Move the replicas out of the server in the background
+		c.triggerRebalance()
+	}
+
+	return state, shardsOnServer(c.clusterStatus, *sa), nil
+}
+
+// Find a server of the cluster config by either its public or internal address.
+func (c *coordinator) findServer(server string) *model.ServerAddress {
+	for _, sa := range c.ClusterConfig.Servers {
+		if sa.Public == server || sa.Internal == server {
+			return &sa
+		}
+	}
+	return nil
+}
+
+func (c *coordinator) SetServerDrain(server string, drain bool) (int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	sa := c.findServer(server)
+	if sa == nil {
+		return 0, ErrServerNotFound
+	}
+
+	if c.clusterStatus.IsDraining(*sa) != drain {
+		cs := c.clusterStatus.Clone()
+		if cs.Servers == nil {
+			cs.Servers = map[string]model.ServerStatus{}
+		}
+
+		ss := cs.Servers[sa.Internal]
+		ss.Draining = drain
+		if ss == (model.ServerStatus{}) {
+			delete(cs.Servers, sa.Internal)
+		} else {
+			cs.Servers[sa.Internal] = ss
+		}
+
+		newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+		if err != nil {
+			return 0, err
+		}
+
+		c.metadataVersion = newMetadataVersion
+		c.clusterStatus = cs
+
+		c.log.Info(
+			"Changed server drain mode",
+			slog.Any("server", sa),
+			slog.Bool("draining", drain),
+		)
+		c.events.record(Event{
+			Type:        EventServerStatusChanged,
+			Server:      sa,
+			Description: fmt.Sprintf("draining: %t", drain),
+		})
+	}
+
+	if drain {
+		// Move the leaders out of the server in the background
+		c.triggerRebalance()
+	}
+
+	return leadersOnServer(c.clusterStatus, *sa), nil
+}
+
+func (c *coordinator) ReplaceServer(oldServer string, newServer string) (int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.clusterStatus.IsFrozen() {
+		return 0, ErrClusterFrozen
+	}
+
+	from := c.findServer(oldServer)
+	to := c.findServer(newServer)
+	if from == nil || to == nil {
+		return 0, ErrServerNotFound
+	}
+
+	if nc, ok := c.nodeControllers[from.Internal]; ok && nc.Status() == Running {
+		return 0, ErrServerStillRunning
+	}
+	if nc, ok := c.nodeControllers[to.Internal]; *from == *to || !ok || nc.Status() != Running ||
+		c.clusterStatus.IsDraining(*to) || c.clusterStatus.IsDecommissioned(*to) {
+		return 0, ErrInvalidReplacement
+	}
+
+	var shards []int64
+	for _, ns := range sortedNamespaces(c.clusterStatus) {
+		nss := c.clusterStatus.Namespaces[ns]
+		for _, shard := range sortedShards(nss) {
+			sm := nss.Shards[shard]
+			if sm.Status != model.ShardStatusDeleting && listContains(sm.Ensemble, *from) {
+				shards = append(shards, shard)
+			}
+		}
+	}
+
+	c.log.Info(
+		"Replacing failed server",
+		slog.Any("old-server", from),
+		slog.Any("new-server", to),
+		slog.Int("shards", len(shards)),
+	)
+
+	go common.DoWithLabels(
+		c.ctx,
+		map[string]string{
+			"oxia":       "coordinator-replace-server",
+			"old-server": from.Internal,
+		},
+		func() {
+			for _, shard := range shards {
+				c.replaceShardReplica(shard, *from, *to)
+			}
+		},
+	)
+
+	return len(shards), nil
+}
+
+// Move the replica of the shard from the failed server to the new server, or
+// to another server picked by the assignment strategy if the new server is
+// already part of the ensemble.
+func (c *coordinator) replaceShardReplica(shard int64, from model.ServerAddress, to model.ServerAddress) {
+	c.Lock()
+	sc := c.shardControllers[shard]
+	namespace, sm, _ := c.findShard(shard)
+	c.Unlock()
+
+	if sc == nil || !listContains(sm.Ensemble, from) {
+		return
+	}
+
+	if listContains(sm.Ensemble, to) {
+		other, err := c.SelectNewNode(namespace, sm.Ensemble, from)
+		if err != nil {
+			c.log.Warn(
+				"No server available to replace the failed server replica",
+				slog.Any("error", err),
+				slog.Int64("shard", shard),
+				slog.Any("old-server", from),
+			)
+			return
+		}
+		to = *other
+	}
+
+	done := c.trackOperation(OperationReplicaMove, shard, from,
+		fmt.Sprintf("replacement: %s -> %s", from.Internal, to.Internal))
+	defer done()
+	if err := sc.ReplaceNode(from, to); err != nil {
+		c.log.Warn(
+			"Failed to replace the failed server replica",
+			slog.Any("error", err),
+			slog.Int64("shard", shard),
+			slog.Any("old-server", from),
+			slog.Any("new-server", to),
+		)
+	}
+}
+
+func (c *coordinator) drainingServers() []model.ServerAddress {
+	var res []model.ServerAddress
+	for _, sa := range c.ClusterConfig.Servers {
+		if c.clusterStatus.IsDraining(sa) {
+			res = append(res, sa)
+		}
+	}
+	return res
+}
+
+// moveLeadersOffDrainedServers transfers the leadership of the shards led by
+// the drained servers to the running followers that lead the fewest shards.
+func (c *coordinator) moveLeadersOffDrainedServers() {
+	c.Lock()
+	draining := c.drainingServers()
+	if len(draining) == 0 || c.clusterStatus.IsFrozen() {
+		c.Unlock()
+		return
+	}
+
+	isRunning := func(sa model.ServerAddress) bool {
+		nc, ok := c.nodeControllers[sa.Internal]
+		return ok && nc.Status() == Running
+	}
+	transfers := computeLeaderTransfers(c.clusterStatus, draining, isRunning)
+	controllers := make(map[int64]ShardController)
+	for _, t := range transfers {
+		controllers[t.Shard] = c.shardControllers[t.Shard]
+	}
+	c.Unlock()
+
+	for _, t := range transfers {
+		sc := controllers[t.Shard]
+		if sc == nil {
+			continue
+		}
+
+		c.log.Info(
+			"Moving the shard leader off a drained server",
+			slog.Any("leader-transfer", t),
+		)
+		done := c.trackOperation(OperationLeaderTransfer, t.Shard, t.From,
+			fmt.Sprintf("drain: %s -> %s", t.From.Internal, t.To.Internal))
+		err := sc.TransferLeader(t.To)
+		done()
+		if err != nil {
+			c.log.Warn(
+				"Failed to move the shard leader off a drained server",
+				slog.Any("error", err),
+				slog.Any("leader-transfer", t),
+			)
+		}
+	}
+}
+
+func (c *coordinator) TransferLeader(namespace string, shard int64, server string) error {
+	c.Lock()
+	nss, ok := c.clusterStatus.Namespaces[namespace]
+	if !ok || nss.IsSoftDeleted() {
+		c.Unlock()
+		return ErrNamespaceNotFound
+	}
+	sm, ok := nss.Shards[shard]
+	sc := c.shardControllers[shard]
+	if !ok || sc == nil {
+		c.Unlock()
+		return ErrShardNotFound
+	}
+	to := c.findServer(server)
+	if to == nil {
+		c.Unlock()
+		return ErrServerNotFound
+	}
+
+	nc, ok := c.nodeControllers[to.Internal]
+	switch {
+	case !ok || nc.Status() != Running:
+		c.Unlock()
+		return errors.Wrapf(ErrInvalidLeaderTransfer, "server %s is not running", to.Internal)
+	case c.clusterStatus.IsDraining(*to) || c.clusterStatus.IsDecommissioned(*to):
+		c.Unlock()
+		return errors.Wrapf(ErrInvalidLeaderTransfer, "server %s is in maintenance", to.Internal)
+	}
+	from := model.ServerAddress{}
+	if sm.Leader != nil {
+		from = *sm.Leader
+	}
+	c.Unlock()
+
+	c.log.Info(
+		"Transferring the shard leadership",
+		slog.String("namespace", namespace),
+		slog.Int64("shard", shard),
+		slog.Any("from", from),
+		slog.Any("to", to),
+	)
+
+	done := c.trackOperation(OperationLeaderTransfer, shard, from,
+		fmt.Sprintf("manual: %s -> %s", from.Internal, to.Internal))
+	defer done()
+	return sc.TransferLeader(*to)
+}
+
+func (c *coordinator) RebalanceCluster(dryRun bool) ([]SwapNodeAction, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.drainingServers()) > 0 {
+		return nil, ErrServersDraining
+	}
+	if !dryRun && c.clusterStatus.IsFrozen() {
+		return nil, ErrClusterFrozen
+	}
+
+	actions := rebalanceCluster(activeConfig(&c.ClusterConfig, c.clusterStatus), c.clusterStatus)
+	if !dryRun && len(actions) > 0 {
+		c.forceRebalance.Store(true)
+		c.triggerRebalance()
+	}
+	return actions, nil
+}
+
+func (c *coordinator) PlanRebalance() (*RebalancePlan, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.drainingServers()) > 0 {
+		return nil, ErrServersDraining
+	}
+	if c.rebalancePlan != nil && c.rebalancePlan.State == PlanStateExecuting {
+		return nil, ErrPlanInProgress
+	}
+
+	actions := rebalanceCluster(activeConfig(&c.ClusterConfig, c.clusterStatus), c.clusterStatus)
+	c.nextPlanId++
+	c.rebalancePlan = newRebalancePlan(c.nextPlanId, actions, c.shardLoads())
+
+	c.log.Info(
+		"Computed rebalance plan",
+		slog.Int64("plan", c.rebalancePlan.Id),
+		slog.Int("moves", len(actions)),
+		slog.Int64("estimated-bytes", c.rebalancePlan.EstimatedBytes()),
+	)
+	return c.rebalancePlan.Clone(), nil
+}
+
+func (c *coordinator) ExecuteRebalancePlan(id int64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	plan := c.rebalancePlan
+	switch {
+	case plan == nil || plan.Id != id:
+		return ErrPlanNotFound
+	case plan.State == PlanStateExecuting:
+		return ErrPlanInProgress
+	case plan.State != PlanStatePending:
+		return ErrPlanNotPending
+	case c.clusterStatus.IsFrozen():
+		return ErrClusterFrozen
+	case len(c.drainingServers()) > 0:
+		return ErrServersDraining
+	}
+
+	actions := rebalanceCluster(activeConfig(&c.ClusterConfig, c.clusterStatus), c.clusterStatus)
+	if !slices.Equal(actions, plan.Actions()) {
+		return ErrPlanOutdated
+	}
+
+	plan.State = PlanStateExecuting
+	c.log.Info(
+		"Executing rebalance plan",
+		slog.Int64("plan", plan.Id),
+		slog.Int("moves", len(plan.Moves)),
+	)
+
+	c.startRebalancePlan(plan)
+	return nil
+}
+
+func (c *coordinator) startRebalancePlan(plan *RebalancePlan) {
+	go common.DoWithLabels(
+		c.ctx,
+		map[string]string{
+			"oxia": "coordinator-rebalance-plan",
+			"plan": fmt.Sprintf("%d", plan.Id),
+		},
+		func() { c.executeRebalancePlan(plan) },
+	)
+}
+
+// Apply the moves of the plan one at a time, skipping the ones that don't
+// match the ensembles anymore. The plan is only modified with the
+// coordinator lock held.
+func (c *coordinator) executeRebalancePlan(plan *RebalancePlan) {
+	for i := range plan.Moves {
+		move := plan.Moves[i].SwapNodeAction
+
+		c.Lock()
+		if plan.Moves[i].State != MoveStatePending {
+			// Already applied by the previous coordinator
+			c.Unlock()
+			continue
+		}
+		if c.clusterStatus.IsFrozen() || c.ctx.Err() != nil {
+			plan.State = PlanStateAborted
+			c.Unlock()
+			c.log.Warn(
+				"Aborted the execution of the rebalance plan",
+				slog.Int64("plan", plan.Id),
+			)
+			return
+		}
+
+		sc := c.shardControllers[move.Shard]
+		_, sm, ok := c.findShard(move.Shard)
+		if sc == nil || !ok || sm.Status == model.ShardStatusDeleting ||
+			!listContains(sm.Ensemble, move.From) || listContains(sm.Ensemble, move.To) {
+			plan.Moves[i].State = MoveStateSkipped
+			c.Unlock()
+			continue
+		}
+		plan.Moves[i].State = MoveStateInProgress
+		c.Unlock()
+
+		done := c.trackOperation(OperationReplicaMove, move.Shard, move.From,
+			fmt.Sprintf("rebalance plan %d, move %d/%d: %s -> %s", plan.Id, i+1, len(plan.Moves),
+				move.From.Internal, move.To.Internal))
+		err := sc.SwapNode(move.From, move.To)
+		done()
+
+		c.Lock()
+		if err != nil {
+			c.log.Warn(
+				"Failed to apply the move of the rebalance plan",
+				slog.Any("error", err),
+				slog.Int64("plan", plan.Id),
+				slog.Any("swap-action", move),
+			)
+			plan.Moves[i].State = MoveStateFailed
+		} else {
+			plan.Moves[i].State = MoveStateCompleted
+		}
+		c.Unlock()
+	}
+
+	c.Lock()
+	plan.State = PlanStateCompleted
+	c.Unlock()
+
+	c.log.Info(
+		"Completed the execution of the rebalance plan",
+		slog.Int64("plan", plan.Id),
+	)
+}
+
+func (c *coordinator) CurrentRebalancePlan() (*RebalancePlan, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.rebalancePlan == nil {
+		return nil, false
+	}
+	return c.rebalancePlan.Clone(), true
+}
+
+// Find the namespace and the metadata of a shard in the cluster status.
+func (c *coordinator) findShard(shard int64) (string, model.ShardMetadata, bool) {
+	for ns, nss := range c.clusterStatus.Namespaces {
+		if sm, ok := nss.Shards[shard]; ok {
+			return ns, sm, true
+		}
+	}
+	return "", model.ShardMetadata{}, false
+}
+
+func (c *coordinator) SetClusterFreeze(freeze bool, reason string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.clusterStatus.IsFrozen() == freeze {
+		return nil
+	}
+
+	cs := c.clusterStatus.Clone()
+	if freeze {
+		cs.Freeze = &model.FreezeStatus{
+			Since:  time.Now(),
+			Reason: reason,
+		}
+	} else {
+		cs.Freeze = nil
+	}
+
+	newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion)
+	if err != nil {
+		return err
+	}
+
+	c.metadataVersion = newMetadataVersion
+	c.clusterStatus = cs
+
+	c.log.Info(
+		"Changed cluster maintenance freeze",
+		slog.Bool("frozen", freeze),
+		slog.String("reason", reason),
+	)
+	description := "unfrozen"
+	if freeze {
+		description = "frozen"
+		if reason != "" {
+			description += ": " + reason
+		}
+	}
+	c.events.record(Event{
+		Type:        EventClusterFreezeChanged,
+		Description: description,
+	})
+
+	if !freeze {
+		// Catch up with the changes that were held back
+		c.triggerRebalance()
+	}
+	return nil
+}
+
+func (c *coordinator) triggerRebalance() {
+	select {
+	case c.rebalanceCh <- nil:
+	default:
+		// There's already a pending rebalance
+	}
+}
+
+// checkDecommissions marks the servers as decommissioned once they don't hold
+// any replica and all the shards are healthy again. It returns true if some
+// replica still has to be moved out of the servers being decommissioned.
+func (c *coordinator) checkDecommissions() bool {
+	c.Lock()
+	var completed []string
+	pendingReplicas := false
+	for _, sa := range c.ClusterConfig.Servers {
+		if c.clusterStatus.Servers[sa.Internal].Decommission != model.DecommissionStateInProgress {
+			continue
+		}
+
+		if shardsOnServer(c.clusterStatus, sa) > 0 {
+			pendingReplicas = true
+			continue
+		}
+
+		completed = append(completed, sa.Internal)
+	}
+
+	isRunning := func(sa model.ServerAddress) bool {
+		nc, ok := c.nodeControllers[sa.Internal]
+		return ok && nc.Status() == Running
+	}
+	if len(completed) > 0 && hasHealthyQuorums(c.clusterStatus, isRunning) {
+		cs := c.clusterStatus.Clone()
+		for _, server := range completed {
+			cs.Servers[server] = model.ServerStatus{Decommission: model.DecommissionStateCompleted}
+		}
+
+		if newMetadataVersion, err := c.MetadataProvider.Store(cs, c.metadataVersion); err != nil {
+			c.log.Warn(
+				"Failed to store the decommissioned servers",
+				slog.Any("error", err),
+			)
+		} else {
+			c.metadataVersion = newMetadataVersion
+			c.clusterStatus = cs
+			c.log.Info(
+				"Servers decommissioned, they can be removed from the cluster config",
+				slog.Any("servers", completed),
+			)
+			for _, server := range completed {
+				c.events.record(Event{
+					Type:        EventServerStatusChanged,
+					Server:      c.findServer(server),
+					Description: "decommission completed",
+				})
+			}
+		}
+	}
+	c.Unlock()
+
+	return pendingReplicas
+}
+
+func (c *coordinator) ListServers() []ServerInfo {
+	c.Lock()
+	defer c.Unlock()
+
+	res := make([]ServerInfo, 0, len(c.ClusterConfig.Servers))
+	for _, sa := range c.ClusterConfig.Servers {
+		nc, ok := c.nodeControllers[sa.Internal]
+		res = append(res, ServerInfo{
+			Address:  sa,
+			Running:  ok && nc.Status() == Running,
+			Labels:   c.ClusterConfig.ServerMetadata[sa.Internal].Labels,
+			Status:   c.clusterStatus.Servers[sa.Internal],
+			Replicas: shardsOnServer(c.clusterStatus, sa),
+			Leaders:  leadersOnServer(c.clusterStatus, sa),
+		})
+	}
+	return res
+}
+
+func (c *coordinator) ListOperations() []Operation {
+	c.Lock()
+	res := statusOperations(&c.ClusterConfig, c.clusterStatus)
+	if plan := c.rebalancePlan; plan != nil && plan.State == PlanStateExecuting {
+		res = append(res, Operation{
+			Type:        OperationRebalancePlan,
+			Description: fmt.Sprintf("plan %d: %d/%d moves done", plan.Id, plan.DoneMoves(), len(plan.Moves)),
+		})
+	}
+	c.Unlock()
+
+	return append(res, c.operations.list()...)
+}
+
+// trackOperation records an operation on a shard, until the returned function
+// is called. It must be called without holding the coordinator lock.
+func (c *coordinator) trackOperation(opType OperationType, shard int64, server model.ServerAddress, description string) func() {
+	c.Lock()
+	namespace := ""
+	for ns, nss := range c.clusterStatus.Namespaces {
+		if _, ok := nss.Shards[shard]; ok {
+			namespace = ns
+			break
+		}
+	}
+	c.Unlock()
+
+	c.events.record(Event{
+		Type:        EventOperationStarted,
+		Namespace:   namespace,
+		Shard:       &shard,
+		Server:      &server,
+		Description: fmt.Sprintf("%s: %s", opType, description),
+	})
+	done := c.operations.start(Operation{
+		Type:        opType,
+		Namespace:   namespace,
+		Shard:       &shard,
+		Server:      &server,
+		Description: description,
+	})
+	return func() {
+		done()
+		c.events.record(Event{
+			Type:        EventOperationCompleted,
+			Namespace:   namespace,
+			Shard:       &shard,
+			Server:      &server,
+			Description: fmt.Sprintf("%s: %s", opType, description),
+		})
+	}
+}
+
+func (c *coordinator) ListEvents() []Event {
+	return c.events.list()
+}
+
+func (c *coordinator) WatchEvents(includeRecent bool) ([]Event, <-chan Event, func()) {
+	return c.events.watch(includeRecent)
+}
+
+func (c *coordinator) Audit(record AuditRecord) {
+	if err := c.auditLog.Append(record); err != nil {
+		c.log.Error(
+			"Failed to append to the audit log",
+			slog.Any("error", err),
+			slog.Any("record", record),
+		)
+	}
+}
+
+// Every decision of the coordinator is recorded in the audit log.
+func (c *coordinator) auditEvent(e Event) {
+	record := AuditRecord{
+		Time:      e.Time,
+		Actor:     AuditActorCoordinator,
+		Action:    string(e.Type),
+		Namespace: e.Namespace,
+		Shard:     e.Shard,
+		Details:   e.Description,
+	}
+	if e.Server != nil {
+		record.Server = e.Server.Internal
+	}
+	c.Audit(record)
+}
+
+func (c *coordinator) ListAuditRecords(limit int) ([]AuditRecord, error) {
+	return c.auditLog.List(limit)
+}
+
+func (c *coordinator) ClusterStatus() model.ClusterStatus {
+	c.Lock()
+	defer c.Unlock()
+	return *c.clusterStatus.Clone()
+}
+
+func (c *coordinator) waitForExternalEvents() {
+	// The replication factor changes that failed are retried with a
+	// backoff, without holding back the other events
+	rfBackoff := common.NewBackOff(c.ctx)
+	var rfRetry <-chan time.Time
+	adjustReplicationFactors := func() {
+		if c.adjustReplicationFactors() {
+			rfBackoff.Reset()
+			rfRetry = nil
+		} else {
+			rfRetry = time.After(rfBackoff.NextBackOff())
+		}
+	}
+
+	// Complete any replication factor change that was interrupted
+	// by a restart of the coordinator
+	adjustReplicationFactors()
+
+	ticker := time.NewTicker(decommissionCheckInterval)
+	defer ticker.Stop()
+
+	windowOpen := true
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-c.rebalanceCh:
+			if err := c.rebalanceCluster(); err != nil {
+				c.log.Warn(
+					"Failed to rebalance cluster",
+					slog.Any("error", err),
+				)
+			}
+			c.checkDecommissions()
+			c.moveLeadersOffDrainedServers()
+			adjustReplicationFactors()
+
+		case <-rfRetry:
+			adjustReplicationFactors()
+
+		case <-ticker.C:
+			// Retry moving the replicas that are still left
+			if c.checkDecommissions() {
+				c.triggerRebalance()
+			}
+
+			// Failed leader elections might have picked a drained server
+			c.moveLeadersOffDrainedServers()
+
+			// Catch up with the rebalancing that was held back until
+			// the maintenance window opened
+			c.Lock()
+			open := inMaintenanceWindow(c.maintenanceWindows, time.Now())
+			c.Unlock()
+			if open && !windowOpen {
+				c.log.Info("Maintenance window opened")
+				c.triggerRebalance()
+			}
+			windowOpen = open
+
+		case <-c.clusterConfigChangeCh:
+			c.log.Info("Received cluster config change event")
+			if err := c.handleClusterConfigUpdated(); err != nil {
+				c.log.Warn(
+					"Failed to update cluster config",
+					slog.Any("error", err),
+				)
+			}
+
+			if err := c.rebalanceCluster(); err != nil {
+				c.log.Warn(
+					"Failed to rebalance cluster",
+					slog.Any("error", err),
+				)
+			}
+
+			adjustReplicationFactors()
+		}
+	}
+}
+
+func (c *coordinator) handleClusterConfigUpdated() error {
+	c.Lock()
+	defer c.Unlock()
+
+	newClusterConfig, err := c.clusterConfigProvider()
+	if err != nil {
+		return errors.Wrap(err, "failed to read cluster configuration")
+	}
+
+	if reflect.DeepEqual(newClusterConfig, c.ClusterConfig) {
+		c.log.Info("No cluster config changes detected")
+		return nil
+	}
+
+	c.log.Info(
+		"Detected change in cluster config",
+		slog.Any("clusterConfig", c.ClusterConfig),
+		slog.Any("newClusterConfig", newClusterConfig),
+		slog.Any("metadataVersion", c.metadataVersion),
+	)
+
+	// Keep running with the current config until the new one is fixed
+	if err = validateClusterConfig(&newClusterConfig); err != nil {
+		return err
+	}
+
+	maintenanceWindows, err := parseMaintenanceWindows(&newClusterConfig)
+	if err != nil {
+		return err
+	}
+
+	logClusterConfigChanges(c.log, &c.ClusterConfig, &newClusterConfig)
+
+	clusterStatus, shardsToAdd, shardsToDelete, err := applyClusterChanges(&newClusterConfig, c.clusterStatus)
+	if err != nil {
+		return errors.Wrap(err, "failed to apply the new cluster configuration")
+	}
+
+	if namespacesChanged(c.clusterStatus, clusterStatus) {
+		if c.metadataVersion, err = c.MetadataProvider.Store(clusterStatus, c.metadataVersion); err != nil {
+			return errors.Wrap(err, "failed to store the namespaces changes")
+		}
+	}
+
+	c.checkClusterNodeChanges(newClusterConfig)
+
+	for shard, namespace := range shardsToAdd {
+		shardMetadata := clusterStatus.Namespaces[namespace].Shards[shard]
+		c.shardControllers[shard] = NewShardController(namespace, shard, shardMetadata, c.rpc, c)
+		slog.Info(
+			"Added new shard",
+			slog.Int64("shard", shard),
+			slog.String("namespace", namespace),
+			slog.Any("shard-metadata", shardMetadata),
+		)
+	}
+
+	for _, shard := range shardsToDelete {
+		s, ok := c.shardControllers[shard]
+		if ok {
+			s.DeleteShard()
+		}
+	}
+
+	c.ClusterConfig = newClusterConfig
+	c.maintenanceWindows = maintenanceWindows
+	c.events.record(Event{
+		Type:        EventClusterConfigChanged,
+		Description: fmt.Sprintf("%d namespaces, %d servers", len(newClusterConfig.Namespaces), len(newClusterConfig.Servers)),
+	})
+	c.clusterStatus = clusterStatus
+
+	c.computeNewAssignments()
+	return nil
+}
+
+// rebalanceCluster moves the replicas out of the removed servers and, unless
+// the auto-rebalance is disabled or it's outside the maintenance windows,
+// evens out the number of replicas across the servers. Nothing is moved while
+// the cluster is frozen.
+//
+//nolint:unparam
+func (c *coordinator) rebalanceCluster() error {
+	c.Lock()
+	if c.clusterStatus.IsFrozen() {
+		c.Unlock()
+		return nil
+	}
+	force := c.forceRebalance.Swap(false)
+	ac := activeConfig(&c.ClusterConfig, c.clusterStatus)
+	draining := c.drainingServers()
+	// The replicas on the servers in maintenance must stay where they are
+	autoRebalance := !c.ClusterConfig.DisableAutoRebalance && inMaintenanceWindow(c.maintenanceWindows, time.Now())
+	evenOut := (force || autoRebalance) && len(draining) == 0
+	actions := computeSwapActions(ac, c.clusterStatus, evenOut, draining)
+	c.Unlock()
+
+	for _, swapAction := range actions {
+		c.log.Info(
+			"Applying swap action",
+			slog.Any("swap-action", swapAction),
+		)
+
+		c.Lock()
+		sc, ok := c.shardControllers[swapAction.Shard]
+		c.Unlock()
+		if !ok {
+			c.log.Warn(
+				"Shard controller not found",
+				slog.Int64("shard", swapAction.Shard),
+			)
+			continue
+		}
+
+		done := c.trackOperation(OperationReplicaMove, swapAction.Shard, swapAction.From,
+			fmt.Sprintf("rebalance: %s -> %s", swapAction.From.Internal, swapAction.To.Internal))
+		err := sc.SwapNode(swapAction.From, swapAction.To)
+		done()
+		if err != nil {
+			c.log.Warn(
+				"Failed to swap node",
+				slog.Any("error", err),
+				slog.Any("swap-action", swapAction),
+			)
+		}
+	}
+
+	return nil
+}
+
+// adjustReplicationFactors grows or shrinks the shard ensembles to match the
+// replication factor of their namespace, changing one member at a time. It
+// returns false if some of the changes failed and need to be retried.
+func (c *coordinator) adjustReplicationFactors() bool {
+	for {
+		c.Lock()
+		if c.clusterStatus.IsFrozen() {
+			c.Unlock()
+			return true
+		}
+
+		pc := placementConfig(&c.ClusterConfig, c.clusterStatus)
+		available := make([]model.ServerAddress, 0, len(pc.Servers))
+		for _, sa := range pc.Servers {
+			if nc, ok := c.nodeControllers[sa.Internal]; ok && nc.Status() == Running {
+				available = append(available, sa)
+			}
+		}
+
+		actions := computeReplicationFactorChanges(pc, c.clusterStatus, available, c.shardLoads())
+		controllers := make(map[int64]ShardController)
+		for _, a := range actions {
+			controllers[a.Shard] = c.shardControllers[a.Shard]
+		}
+		c.Unlock()
+
+		if len(actions) == 0 {
+			return true
+		}
+
+		applied := 0
+		for _, a := range actions {
+			sc := controllers[a.Shard]
+			if sc == nil {
+				continue
+			}
+
+			c.log.Info(
+				"Changing shard ensemble to match the replication factor",
+				slog.Any("ensemble-change", a),
+			)
+
+			var err error
+			if a.Add != nil {
+				done := c.trackOperation(OperationEnsembleChange, a.Shard, *a.Add,
+					fmt.Sprintf("replication factor: adding %s", a.Add.Internal))
+				err = sc.AddNode(*a.Add)
+				done()
+			} else {
+				done := c.trackOperation(OperationEnsembleChange, a.Shard, *a.Remove,
+					fmt.Sprintf("replication factor: removing %s", a.Remove.Internal))
+				err = sc.RemoveNode(*a.Remove)
+				done()
+			}
+
+			if err != nil {
+				c.log.Warn(
+					"Failed to change shard ensemble",
+					slog.Any("error", err),
+					slog.Any("ensemble-change", a),
+				)
+				continue
+			}
+			applied++
+		}
+
+		if c.ctx.Err() != nil {
+			return true
+		}
+
+		// Stop if no progress can be made, the changes are retried later
+		if applied == 0 {
+			return false
+		}
+	}
+}
+
+func (c *coordinator) runLoadBalancer() {
+	for {
+		c.Lock()
+		interval := loadBalancerDisabledCheckInterval
+		if lb := c.ClusterConfig.LoadBalancer; lb != nil && lb.Interval > 0 {
+			interval = lb.Interval
+		}
+		c.Unlock()
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(interval):
+			c.balanceLoad()
+		}
+	}
+}
+
+// Move the shard replicas across the servers, based on the load of the shards
+// reported by their leaders.
+// shardLoads returns the load of the shards that were sampled. It must be
+// called while holding the coordinator lock.
+func (c *coordinator) shardLoads() map[int64]ShardLoad {
+	loads := map[int64]ShardLoad{}
+	for shard, sc := range c.shardControllers {
+		if load, ok := sc.Load(); ok {
+			loads[shard] = load
+		} else if load, ok := c.restoredLoads[shard]; ok {
+			loads[shard] = load
+		}
+	}
+	return loads
+}
+
+func (c *coordinator) balanceLoad() {
+	c.Lock()
+	if c.ClusterConfig.LoadBalancer == nil || c.ClusterConfig.LoadBalancer.Interval <= 0 || c.clusterStatus.IsFrozen() ||
+		!inMaintenanceWindow(c.maintenanceWindows, time.Now()) {
+		c.Unlock()
+		return
+	}
+
+	scorer, ok := getLoadScorer(c.ClusterConfig.LoadBalancer.Policy)
+	if !ok {
+		c.Unlock()
+		c.log.Warn(
+			"Unknown load scoring policy",
+			slog.Any("policy", c.ClusterConfig.LoadBalancer.Policy),
+		)
+		return
+	}
+
+	actions := computeLoadBalancingMoves(placementConfig(&c.ClusterConfig, c.clusterStatus), c.clusterStatus, c.shardLoads(), scorer)
+	controllers := make(map[int64]ShardController)
+	for _, a := range actions {
+		controllers[a.Shard] = c.shardControllers[a.Shard]
+	}
+	c.Unlock()
+
+	// The number of actions is capped by the max concurrent moves, so we
+	// can apply all of them in parallel
+	wg := sync.WaitGroup{}
+	for _, a := range actions {
+		sc := controllers[a.Shard]
+		if sc == nil {
+			continue
+		}
+
+		c.log.Info(
+			"Moving shard replica to balance the load",
+			slog.Any("swap-action", a),
+		)
+
+		wg.Add(1)
+		go func(a SwapNodeAction) {
+			defer wg.Done()
+			done := c.trackOperation(OperationReplicaMove, a.Shard, a.From,
+				fmt.Sprintf("load balancing: %s -> %s", a.From.Internal, a.To.Internal))
+			defer done()
+			if err := sc.SwapNode(a.From, a.To); err != nil {
+				c.log.Warn(
+					"Failed to move shard replica",
+					slog.Any("error", err),
+					slog.Any("swap-action", a),
+				)
+				return
+			}
+			c.loadBalancerMoves.Inc()
+		}(a)
+	}
+	wg.Wait()
+}
+
 func (*coordinator) findServerByInternalAddress(newClusterConfig model.ClusterConfig, server string) *model.ServerAddress {
 	for _, s := range newClusterConfig.Servers {
 		if server == s.Internal {
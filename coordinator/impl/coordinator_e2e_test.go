@@ -16,13 +16,16 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/common"
@@ -206,6 +209,8 @@ func TestCoordinator_LeaderFailover(t *testing.T) {
 	assert.Equal(t, version1, version2)
 	assert.NoError(t, client.Close())
 
+	_, watchCh, stopWatch := coordinator.WatchEvents(false)
+
 	// Stop the leader to cause a leader election
 	assert.NoError(t, servers[leader].Close())
 	delete(servers, leader)
@@ -228,6 +233,36 @@ func TestCoordinator_LeaderFailover(t *testing.T) {
 	assert.Equal(t, version1, version3)
 	assert.NoError(t, client.Close())
 
+	// The failure and the new election are recorded in the event log
+	events := map[EventType][]Event{}
+	for _, e := range coordinator.ListEvents() {
+		events[e.Type] = append(events[e.Type], e)
+	}
+	assert.Equal(t, &leader, events[EventNodeFailed][0].Server)
+	shard := coordinator.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+	lastElected := events[EventLeaderElected][len(events[EventLeaderElected])-1]
+	assert.Equal(t, shard.Leader, lastElected.Server)
+	assert.Equal(t, fmt.Sprintf("term %d", shard.Term), lastElected.Description)
+
+	// The same events were streamed to the watcher
+	stopWatch()
+	var watched []EventType
+	for e := range watchCh {
+		watched = append(watched, e.Type)
+	}
+	assert.Contains(t, watched, EventNodeFailed)
+	assert.Contains(t, watched, EventLeaderElected)
+
+	// And in the audit log, as decisions of the coordinator
+	records, err := coordinator.ListAuditRecords(0)
+	assert.NoError(t, err)
+	audited := map[string]string{}
+	for _, r := range records {
+		audited[r.Action] = r.Actor
+	}
+	assert.Equal(t, AuditActorCoordinator, audited[string(EventNodeFailed)])
+	assert.Equal(t, AuditActorCoordinator, audited[string(EventLeaderElected)])
+
 	assert.NoError(t, coordinator.Close())
 	assert.NoError(t, clientPool.Close())
 
@@ -723,7 +758,9 @@ func TestCoordinator_ShrinkCluster(t *testing.T) {
 	assert.Eventually(t, func() bool {
 		for _, ns := range c.ClusterStatus().Namespaces {
 			for _, shard := range ns.Shards {
-				return shard.Term > 0
+				if shard.Term == 0 || shard.Leader == nil {
+					return false
+				}
 			}
 		}
 		return true
@@ -769,3 +806,1703 @@ func checkServerLists(t *testing.T, expected, actual []model.ServerAddress) {
 		assert.True(t, ok)
 	}
 }
+
+func TestCoordinator_MergeShard(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 3,
+			InitialShardCount: 3,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil }, nil, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	waitForSteadyState := func() {
+		assert.Eventually(t, func() bool {
+			for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+				if shard.Status != model.ShardStatusSteadyState {
+					return false
+				}
+			}
+			return true
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	waitForSteadyState()
+
+	assert.ErrorIs(t, c.MergeShard("my-ns-does-not-exist", 1, 0), ErrNamespaceNotFound)
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 5, 0), ErrShardNotFound)
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 2, 0), ErrShardsNotAdjacent)
+
+	assert.NoError(t, c.SetClusterFreeze(true, "INC-42"))
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 1, 0), ErrClusterFrozen)
+	assert.NoError(t, c.SetClusterFreeze(false, ""))
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		_, _, err := client.Put(ctx, fmt.Sprintf("key-%d", i), []byte(fmt.Sprintf("value-%d", i)))
+		assert.NoError(t, err)
+	}
+
+	assertRecords := func() {
+		for i := 0; i < 100; i++ {
+			assert.Eventually(t, func() bool {
+				_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+				return err == nil && string(value) == fmt.Sprintf("value-%d", i)
+			}, 10*time.Second, 10*time.Millisecond)
+		}
+
+		// No record is left twice in the shards
+		keys, err := client.List(ctx, "a", "z")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 100)
+	}
+
+	shard2Range := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[2].Int32HashRange
+
+	// The records of the shard are copied into its neighbour
+	assert.NoError(t, c.MergeShard(common.DefaultNamespace, 1, 0))
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[1]
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+
+	nsStatus := c.ClusterStatus().Namespaces[common.DefaultNamespace]
+	assert.Equal(t, 2, len(nsStatus.Shards))
+	assert.EqualValues(t, 0, nsStatus.Shards[0].Int32HashRange.Min)
+	assert.EqualValues(t, shard2Range.Min-1, nsStatus.Shards[0].Int32HashRange.Max)
+
+	assertRecords()
+
+	// The shard was already deleted
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 1, 0), ErrShardNotFound)
+
+	// The ephemeral records leave a live session on the shard
+	sessionClient, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+	for i := 100; i < 200; i++ {
+		_, _, err := sessionClient.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"), oxia.Ephemeral())
+		assert.NoError(t, err)
+	}
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 2, 0), ErrShardNotMergeable)
+	waitForSteadyState()
+
+	// Once its sessions are closed, the shard can be merged
+	assert.NoError(t, sessionClient.Close())
+	assertRecords()
+	assert.NoError(t, c.MergeShard(common.DefaultNamespace, 2, 0))
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[2]
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+
+	nsStatus = c.ClusterStatus().Namespaces[common.DefaultNamespace]
+	assert.Equal(t, 1, len(nsStatus.Shards))
+	assert.EqualValues(t, 0, nsStatus.Shards[0].Int32HashRange.Min)
+	assert.EqualValues(t, math.MaxUint32, nsStatus.Shards[0].Int32HashRange.Max)
+
+	assertRecords()
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+// failingMergeMetadataProvider fails to store the cluster status when a
+// shard is being deleted, while failMerge is set.
+type failingMergeMetadataProvider struct {
+	MetadataProvider
+	failMerge atomic.Bool
+}
+
+func (m *failingMergeMetadataProvider) Store(cs *model.ClusterStatus, expectedVersion Version) (Version, error) {
+	if m.failMerge.Load() {
+		for _, ns := range cs.Namespaces {
+			for _, shard := range ns.Shards {
+				if shard.Status == model.ShardStatusDeleting {
+					return MetadataNotExists, errors.New("failed to store")
+				}
+			}
+		}
+	}
+	return m.MetadataProvider.Store(cs, expectedVersion)
+}
+
+func TestCoordinator_MergeShardStoreFailure(t *testing.T) {
+	s1, sa1 := newServer(t)
+
+	metadataProvider := &failingMergeMetadataProvider{MetadataProvider: NewMetadataProviderMemory()}
+	metadataProvider.failMerge.Store(true)
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 3,
+		}},
+		Servers: []model.ServerAddress{sa1},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	newCoordinator := func() Coordinator {
+		c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil }, nil, NewRpcProvider(clientPool))
+		assert.NoError(t, err)
+		return c
+	}
+	c := newCoordinator()
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		_, _, err := client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	waitForCommitting := func(shard int64) {
+		assert.Eventually(t, func() bool {
+			merge := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[shard].Merge
+			return merge != nil && merge.Phase == model.ShardMergePhaseCommitting
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+	waitForMerged := func(shard int64) {
+		assert.Eventually(t, func() bool {
+			_, ok := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[shard]
+			return !ok
+		}, 10*time.Second, 10*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			keys, err := client.List(ctx, "a", "z")
+			return err == nil && len(keys) == 100
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	// The new ranges fail to be stored once the records are committed, and
+	// the merge is retried until it completes
+	mergeErr := make(chan error, 1)
+	go func() { mergeErr <- c.MergeShard(common.DefaultNamespace, 1, 0) }()
+	waitForCommitting(1)
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 2, 0), ErrShardMergeInProgress)
+
+	metadataProvider.failMerge.Store(false)
+	assert.NoError(t, <-mergeErr)
+	waitForMerged(1)
+
+	// The merge is resumed by a new coordinator
+	metadataProvider.failMerge.Store(true)
+	go func() { mergeErr <- c.MergeShard(common.DefaultNamespace, 2, 0) }()
+	waitForCommitting(2)
+	assert.NoError(t, c.Close())
+	assert.Error(t, <-mergeErr)
+
+	metadataProvider.failMerge.Store(false)
+	c = newCoordinator()
+	waitForMerged(2)
+
+	nsStatus := c.ClusterStatus().Namespaces[common.DefaultNamespace]
+	assert.Len(t, nsStatus.Shards, 1)
+	assert.Nil(t, nsStatus.Shards[0].Merge)
+	assert.EqualValues(t, math.MaxUint32, nsStatus.Shards[0].Int32HashRange.Max)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+}
+
+func TestCoordinator_MergeShardKeyExists(t *testing.T) {
+	s1, sa1 := newServer(t)
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 2,
+		}},
+		Servers: []model.ServerAddress{sa1},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil }, nil, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	// A record of the shard 1 is also written in the shard 0, through a
+	// partition key that is routed to the shard 0
+	shard0Range := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0].Int32HashRange
+	inShard0 := func(key string) bool {
+		return common.Xxh332(key) <= shard0Range.Max
+	}
+	key := "key-0"
+	for i := 1; inShard0(key); i++ {
+		key = fmt.Sprintf("key-%d", i)
+	}
+	partitionKey := "partition-0"
+	for i := 1; !inShard0(partitionKey); i++ {
+		partitionKey = fmt.Sprintf("partition-%d", i)
+	}
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, key, []byte("value-1"))
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, key, []byte("value-0"), oxia.PartitionKey(partitionKey))
+	assert.NoError(t, err)
+
+	// The merge is aborted, and both records are kept
+	assert.ErrorIs(t, c.MergeShard(common.DefaultNamespace, 1, 0), ErrMergedKeyExists)
+
+	assert.Eventually(t, func() bool {
+		shard := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[1]
+		return shard.Status == model.ShardStatusSteadyState && shard.Leader != nil && shard.Merge == nil
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		_, value, _, err := client.Get(ctx, key)
+		return err == nil && string(value) == "value-1"
+	}, 10*time.Second, 10*time.Millisecond)
+	_, value, _, err := client.Get(ctx, key, oxia.PartitionKey(partitionKey))
+	assert.NoError(t, err)
+	assert.Equal(t, "value-0", string(value))
+
+	// Once the duplicated key is removed, the shard can be merged
+	assert.NoError(t, client.Delete(ctx, key, oxia.PartitionKey(partitionKey)))
+	assert.NoError(t, c.MergeShard(common.DefaultNamespace, 1, 0))
+
+	assert.Eventually(t, func() bool {
+		_, value, _, err := client.Get(ctx, key)
+		return err == nil && string(value) == "value-1"
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+}
+
+func TestCoordinator_CreateDeleteNamespace(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{InitialShardCount: 1, ReplicationFactor: 1}), ErrInvalidNamespaceConfig)
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", ReplicationFactor: 1}), ErrInvalidNamespaceConfig)
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 1, ReplicationFactor: 4}), ErrInvalidNamespaceConfig)
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: common.DefaultNamespace, InitialShardCount: 1, ReplicationFactor: 1}), ErrNamespaceAlreadyExists)
+
+	assert.NoError(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 2, ReplicationFactor: 3}))
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 2, ReplicationFactor: 3}), ErrNamespaceAlreadyExists)
+
+	assert.Eventually(t, func() bool {
+		ns := c.ClusterStatus().Namespaces["ns-1"]
+		for _, shard := range ns.Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return false
+			}
+		}
+		return len(ns.Shards) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.True(t, c.ClusterStatus().Namespaces["ns-1"].Dynamic)
+
+	client, err := oxia.NewSyncClient(sa1.Public, oxia.WithNamespace("ns-1"))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "my-key", []byte("value"))
+	assert.NoError(t, err)
+	_, value, _, err := client.Get(ctx, "my-key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.NoError(t, client.Close())
+
+	// The dynamic namespace is kept when the cluster config changes
+	configLock.Lock()
+	clusterConfig.Namespaces = append(clusterConfig.Namespaces, model.NamespaceConfig{
+		Name:              "ns-2",
+		ReplicationFactor: 1,
+		InitialShardCount: 1,
+	})
+	configLock.Unlock()
+	configChangesCh <- nil
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces["ns-2"]
+		return ok
+	}, 10*time.Second, 10*time.Millisecond)
+	for _, shard := range c.ClusterStatus().Namespaces["ns-1"].Shards {
+		assert.NotEqual(t, model.ShardStatusDeleting, shard.Status)
+	}
+
+	assert.ErrorIs(t, c.DeleteNamespace("ns-does-not-exist"), ErrNamespaceNotFound)
+	assert.ErrorIs(t, c.DeleteNamespace(common.DefaultNamespace), ErrNamespaceNotDynamic)
+
+	assert.NoError(t, c.DeleteNamespace("ns-1"))
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces["ns-1"]
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_DeleteNamespaceGracePeriod(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 1,
+		}},
+		Servers:                      []model.ServerAddress{sa1, sa2, sa3},
+		NamespaceDeletionGracePeriod: 2 * time.Second,
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil }, nil, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 2, ReplicationFactor: 3}))
+	assert.Eventually(t, func() bool {
+		for _, shard := range c.ClusterStatus().Namespaces["ns-1"].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, c.DeleteNamespace("ns-1"))
+	assert.ErrorIs(t, c.DeleteNamespace("ns-1"), ErrNamespaceNotFound)
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 1, ReplicationFactor: 1}), ErrNamespaceAlreadyExists)
+
+	// The namespace is not visible to the clients, though the shards are still there
+	ns := c.ClusterStatus().Namespaces["ns-1"]
+	assert.True(t, ns.IsSoftDeleted())
+	assert.False(t, ns.IsDeleting())
+	assert.Equal(t, 2, len(ns.Shards))
+
+	assignments, err := c.WaitForNextUpdate(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, assignments.Namespaces, "ns-1")
+	assert.Contains(t, assignments.Namespaces, common.DefaultNamespace)
+
+	// Once the grace period is over, the data is deleted
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces["ns-1"]
+		return !ok
+	}, 40*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_ChangeReplicationFactor(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	ensembleSize := func() int {
+		ns := c.ClusterStatus().Namespaces[common.DefaultNamespace]
+		if ns.Shards[0].Status != model.ShardStatusSteadyState {
+			return -1
+		}
+		return len(ns.Shards[0].Ensemble)
+	}
+
+	// Wait for the shard to be ready
+	assert.Eventually(t, func() bool {
+		return ensembleSize() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "my-key", []byte("value"))
+	assert.NoError(t, err)
+
+	setReplicationFactor := func(rf uint32) {
+		configLock.Lock()
+		clusterConfig.Namespaces = []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: rf,
+			InitialShardCount: 1,
+		}}
+		configLock.Unlock()
+		configChangesCh <- nil
+	}
+
+	// An invalid config is rejected and the current one is kept
+	setReplicationFactor(4)
+	assert.Never(t, func() bool {
+		return ensembleSize() != 1
+	}, 1*time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 1, c.ClusterStatus().Namespaces[common.DefaultNamespace].ReplicationFactor)
+
+	setReplicationFactor(3)
+	assert.Eventually(t, func() bool {
+		return ensembleSize() == 3
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 3, c.ClusterStatus().Namespaces[common.DefaultNamespace].ReplicationFactor)
+
+	_, value, _, err := client.Get(ctx, "my-key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	setReplicationFactor(1)
+	assert.Eventually(t, func() bool {
+		return ensembleSize() == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	_, value, _, err = client.Get(ctx, "my-key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_DecommissionServer(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 2,
+			InitialShardCount: 3,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	// Wait for all shards to be ready
+	assert.Eventually(t, func() bool {
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	client, err := oxia.NewSyncClient(sa2.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	_, _, err = c.DecommissionServer("s-does-not-exist")
+	assert.ErrorIs(t, err, ErrServerNotFound)
+
+	state, _, err := c.DecommissionServer(sa1.Internal)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DecommissionStateInProgress, state)
+
+	// With only 2 servers left, none of them can be decommissioned
+	_, _, err = c.DecommissionServer(sa2.Public)
+	assert.ErrorIs(t, err, ErrNotEnoughServers)
+
+	assert.Eventually(t, func() bool {
+		state, remaining, err := c.DecommissionServer(sa1.Public)
+		return err == nil && state == model.DecommissionStateCompleted && remaining == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+		assert.NotContains(t, shard.Ensemble, sa1)
+		assert.Len(t, shard.Ensemble, 2)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}
+
+	// Once the server is removed from the config, its status is dropped
+	configLock.Lock()
+	clusterConfig.Servers = []model.ServerAddress{sa2, sa3}
+	configLock.Unlock()
+	configChangesCh <- nil
+
+	assert.Eventually(t, func() bool {
+		return len(c.ClusterStatus().Servers) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_RebalanceOnExpansion(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	s4, sa4 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+		sa4: s4,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 4,
+		}},
+		Servers:              []model.ServerAddress{sa1, sa2},
+		DisableAutoRebalance: true,
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	replicasPerServer := func() map[model.ServerAddress]int {
+		res := map[model.ServerAddress]int{}
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return nil
+			}
+			for _, sa := range shard.Ensemble {
+				res[sa]++
+			}
+		}
+		return res
+	}
+
+	// With the auto-rebalance disabled, the new servers are left empty
+	configLock.Lock()
+	clusterConfig.Servers = []model.ServerAddress{sa1, sa2, sa3, sa4}
+	configLock.Unlock()
+	configChangesCh <- nil
+
+	assert.Never(t, func() bool {
+		r := replicasPerServer()
+		return r != nil && (r[sa3] > 0 || r[sa4] > 0)
+	}, 1*time.Second, 10*time.Millisecond)
+
+	moves, err := c.RebalanceCluster(true)
+	assert.NoError(t, err)
+	assert.Len(t, moves, 2)
+	assert.Equal(t, map[model.ServerAddress]int{sa1: 2, sa2: 2}, replicasPerServer())
+
+	moves, err = c.RebalanceCluster(false)
+	assert.NoError(t, err)
+	assert.Len(t, moves, 2)
+
+	assert.Eventually(t, func() bool {
+		r := replicasPerServer()
+		return r[sa1] == 1 && r[sa2] == 1 && r[sa3] == 1 && r[sa4] == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	moves, err = c.RebalanceCluster(true)
+	assert.NoError(t, err)
+	assert.Empty(t, moves)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_RebalancePlan(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	s4, sa4 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+		sa4: s4,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 4,
+		}},
+		Servers:              []model.ServerAddress{sa1, sa2},
+		DisableAutoRebalance: true,
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	_, found := c.CurrentRebalancePlan()
+	assert.False(t, found)
+
+	setServers := func(servers ...model.ServerAddress) {
+		configLock.Lock()
+		clusterConfig.Servers = servers
+		configLock.Unlock()
+		configChangesCh <- nil
+	}
+
+	setServers(sa1, sa2, sa3)
+	var plan *RebalancePlan
+	assert.Eventually(t, func() bool {
+		plan, err = c.PlanRebalance()
+		return err == nil && len(plan.Moves) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, PlanStatePending, plan.State)
+	assert.Equal(t, MoveStatePending, plan.Moves[0].State)
+	assert.Equal(t, sa3, plan.Moves[0].To)
+
+	// The plan doesn't match the cluster anymore
+	setServers(sa1, sa2, sa3, sa4)
+	assert.Eventually(t, func() bool {
+		return errors.Is(c.ExecuteRebalancePlan(plan.Id), ErrPlanOutdated)
+	}, 10*time.Second, 10*time.Millisecond)
+
+	previousPlan := plan
+	plan, err = c.PlanRebalance()
+	assert.NoError(t, err)
+	assert.Equal(t, previousPlan.Id+1, plan.Id)
+	assert.Len(t, plan.Moves, 2)
+	assert.ErrorIs(t, c.ExecuteRebalancePlan(previousPlan.Id), ErrPlanNotFound)
+
+	assert.NoError(t, c.ExecuteRebalancePlan(plan.Id))
+
+	assert.Eventually(t, func() bool {
+		current, found := c.CurrentRebalancePlan()
+		return found && current.State == PlanStateCompleted
+	}, 30*time.Second, 10*time.Millisecond)
+
+	current, _ := c.CurrentRebalancePlan()
+	assert.Equal(t, 2, current.DoneMoves())
+	for _, m := range current.Moves {
+		assert.Equal(t, MoveStateCompleted, m.State)
+	}
+	assert.ErrorIs(t, c.ExecuteRebalancePlan(plan.Id), ErrPlanNotPending)
+
+	replicas := map[model.ServerAddress]int{}
+	for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+		for _, sa := range shard.Ensemble {
+			replicas[sa]++
+		}
+	}
+	assert.Equal(t, map[model.ServerAddress]int{sa1: 1, sa2: 1, sa3: 1, sa4: 1}, replicas)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_TakeOverStandbyState(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 4,
+		}},
+		Servers:              []model.ServerAddress{sa1, sa2},
+		DisableAutoRebalance: true,
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+	configProvider := func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}
+
+	c1, err := NewCoordinator(metadataProvider, configProvider, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	configLock.Lock()
+	clusterConfig.Servers = []model.ServerAddress{sa1, sa2, sa3}
+	configLock.Unlock()
+	configChangesCh <- nil
+
+	var plan *RebalancePlan
+	assert.Eventually(t, func() bool {
+		plan, err = c1.PlanRebalance()
+		return err == nil && len(plan.Moves) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The coordinator fails right after the plan is approved, before
+	// applying any of its moves
+	state := c1.State()
+	assert.Equal(t, plan.Id, state.NextPlanId)
+	assert.NotEmpty(t, state.Events)
+	state.RebalancePlan.State = PlanStateExecuting
+	assert.NoError(t, c1.Close())
+
+	// The state is streamed to the standby coordinator as json
+	b, err := json.Marshal(state)
+	assert.NoError(t, err)
+	mirrored := &CoordinatorState{}
+	assert.NoError(t, json.Unmarshal(b, mirrored))
+
+	c2, err := NewCoordinator(metadataProvider, configProvider, configChangesCh, NewRpcProvider(clientPool),
+		WithStandbyState(mirrored))
+	assert.NoError(t, err)
+
+	// The events of the previous coordinator are kept
+	events := c2.ListEvents()
+	assert.GreaterOrEqual(t, len(events), len(state.Events))
+	for i, e := range state.Events {
+		assert.Equal(t, e.Type, events[i].Type)
+		assert.True(t, e.Time.Equal(events[i].Time))
+	}
+
+	// And the execution of the plan is resumed
+	assert.Eventually(t, func() bool {
+		current, found := c2.CurrentRebalancePlan()
+		return found && current.State == PlanStateCompleted
+	}, 30*time.Second, 10*time.Millisecond)
+	current, _ := c2.CurrentRebalancePlan()
+	assert.Equal(t, plan.Id, current.Id)
+	assert.Equal(t, MoveStateCompleted, current.Moves[0].State)
+
+	replicas := map[model.ServerAddress]int{}
+	for _, shard := range c2.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+		for _, sa := range shard.Ensemble {
+			replicas[sa]++
+		}
+	}
+	assert.Equal(t, 1, replicas[sa3])
+
+	next, err := c2.PlanRebalance()
+	assert.NoError(t, err)
+	assert.Equal(t, plan.Id+1, next.Id)
+
+	assert.NoError(t, c2.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_DrainServer(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 3,
+			InitialShardCount: 3,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	client, err := oxia.NewSyncClient(sa2.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	_, err = c.SetServerDrain("s-does-not-exist", true)
+	assert.ErrorIs(t, err, ErrServerNotFound)
+
+	_, err = c.SetServerDrain(sa1.Public, true)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		leaderShards, err := c.SetServerDrain(sa1.Public, true)
+		return err == nil && leaderShards == 0
+	}, 30*time.Second, 100*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState || shard.Leader == nil {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// The replicas are kept on the drained server
+	for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+		assert.Contains(t, shard.Ensemble, sa1)
+		assert.NotEqual(t, sa1, *shard.Leader)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}
+
+	serverInfos := c.ListServers()
+	assert.Len(t, serverInfos, 3)
+	assert.Equal(t, sa1, serverInfos[0].Address)
+	assert.True(t, serverInfos[0].Running)
+	assert.True(t, serverInfos[0].Status.Draining)
+	assert.Equal(t, 3, serverInfos[0].Replicas)
+	assert.Equal(t, 0, serverInfos[0].Leaders)
+	assert.Equal(t, 3, serverInfos[1].Leaders+serverInfos[2].Leaders)
+
+	assert.Equal(t, []Operation{{
+		Type:        OperationDrain,
+		Server:      &sa1,
+		Description: "0 shards still led by the server",
+	}}, c.ListOperations())
+
+	// No new replica is placed on the drained server
+	assert.ErrorIs(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 1, ReplicationFactor: 3}),
+		ErrNoAvailableServers)
+	assert.NoError(t, c.CreateNamespace(model.NamespaceConfig{Name: "ns-1", InitialShardCount: 2, ReplicationFactor: 2}))
+	for _, shard := range c.ClusterStatus().Namespaces["ns-1"].Shards {
+		assert.NotContains(t, shard.Ensemble, sa1)
+	}
+
+	_, err = c.RebalanceCluster(true)
+	assert.ErrorIs(t, err, ErrServersDraining)
+
+	_, err = c.SetServerDrain(sa1.Internal, false)
+	assert.NoError(t, err)
+	assert.Empty(t, c.ClusterStatus().Servers)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_ReplaceServer(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	s4, sa4 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+		sa4: s4,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 3,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3, sa4},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	var shard model.ShardMetadata
+	assert.Eventually(t, func() bool {
+		shard = c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+		return shard.Status == model.ShardStatusSteadyState
+	}, 10*time.Second, 10*time.Millisecond)
+	leader := *shard.Leader
+	var spare model.ServerAddress
+	for _, sa := range clusterConfig.Servers {
+		if !listContains(shard.Ensemble, sa) {
+			spare = sa
+		}
+	}
+	var survivor model.ServerAddress
+	for _, sa := range shard.Ensemble {
+		if sa != leader {
+			survivor = sa
+		}
+	}
+
+	client, err := oxia.NewSyncClient(survivor.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	_, err = c.ReplaceServer("s-does-not-exist", spare.Public)
+	assert.ErrorIs(t, err, ErrServerNotFound)
+	_, err = c.ReplaceServer(leader.Public, spare.Public)
+	assert.ErrorIs(t, err, ErrServerStillRunning)
+
+	// Lose the leader of the shard, along with its data
+	assert.NoError(t, servers[leader].Close())
+
+	assert.Eventually(t, func() bool {
+		_, err = c.ReplaceServer(leader.Public, leader.Internal)
+		return errors.Is(err, ErrInvalidReplacement)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	shards, err := c.ReplaceServer(leader.Public, spare.Public)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, shards)
+
+	assert.Eventually(t, func() bool {
+		shard = c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+		return shard.Status == model.ShardStatusSteadyState &&
+			listContains(shard.Ensemble, spare) && !listContains(shard.Ensemble, leader)
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// The failed server is neither fenced nor asked to delete the shard
+	assert.Empty(t, shard.RemovedNodes)
+
+	for i := 0; i < 10; i++ {
+		_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for sa, s := range servers {
+		if sa != leader {
+			assert.NoError(t, s.Close())
+		}
+	}
+}
+func TestCoordinator_TransferLeader(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 2,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+
+	shard := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+	var follower, outsider model.ServerAddress
+	for _, sa := range clusterConfig.Servers {
+		switch {
+		case !listContains(shard.Ensemble, sa):
+			outsider = sa
+		case sa != *shard.Leader:
+			follower = sa
+		}
+	}
+
+	assert.ErrorIs(t, c.TransferLeader("ns-does-not-exist", 0, follower.Public), ErrNamespaceNotFound)
+	assert.ErrorIs(t, c.TransferLeader(common.DefaultNamespace, 5, follower.Public), ErrShardNotFound)
+	assert.ErrorIs(t, c.TransferLeader(common.DefaultNamespace, 0, "s-does-not-exist"), ErrServerNotFound)
+	assert.ErrorIs(t, c.TransferLeader(common.DefaultNamespace, 0, outsider.Public), ErrInvalidLeaderTransfer)
+
+	assert.NoError(t, c.TransferLeader(common.DefaultNamespace, 0, follower.Internal))
+	shard = c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+	assert.Equal(t, follower, *shard.Leader)
+	assert.Empty(t, c.ListOperations())
+
+	// Transferring to the current leader is a no-op
+	assert.NoError(t, c.TransferLeader(common.DefaultNamespace, 0, follower.Public))
+
+	assert.Eventually(t, func() bool {
+		_, value, _, err := client.Get(ctx, "key-0")
+		return err == nil && string(value) == "value"
+	}, 10*time.Second, 100*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_NotificationsLeaderFailover(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 2,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	notifications, err := client.GetNotifications()
+	assert.NoError(t, err)
+
+	nextKey := func() string {
+		select {
+		case n := <-notifications.Ch():
+			return n.Key
+		case <-time.After(10 * time.Second):
+			assert.FailNow(t, "Shouldn't have timed out")
+			return ""
+		}
+	}
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "key-0", []byte("value"))
+	assert.NoError(t, err)
+	assert.Equal(t, "key-0", nextKey())
+
+	shard := c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards[0]
+	var follower model.ServerAddress
+	for _, sa := range shard.Ensemble {
+		if sa != *shard.Leader {
+			follower = sa
+		}
+	}
+	assert.NoError(t, c.TransferLeader(common.DefaultNamespace, 0, follower.Internal))
+
+	// Write through a new client, that gets the updated assignments
+	writer, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, _, err := writer.Put(ctx, "key-1", []byte("value"))
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// The same subscription continues on the new leader, without missing
+	// or repeating any notification
+	assert.Equal(t, "key-1", nextKey())
+
+	assert.NoError(t, notifications.Close())
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_Sessions(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 2,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public, oxia.WithIdentity("client-1"))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"), oxia.Ephemeral(), oxia.PartitionKey("x"))
+		assert.NoError(t, err)
+	}
+
+	sessions, err := c.ListSessions(common.DefaultNamespace, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	si := sessions[0]
+	assert.Equal(t, "client-1", si.ClientIdentity)
+	assert.EqualValues(t, 3, si.EphemeralKeys)
+	assert.Equal(t, oxia.DefaultSessionTimeout, si.Timeout)
+	assert.WithinDuration(t, time.Now(), si.CreatedTime, time.Minute)
+	assert.WithinDuration(t, time.Now(), si.LastHeartbeat, time.Minute)
+
+	otherShard := 1 - si.Shard
+	sessions, err = c.ListSessions(common.DefaultNamespace, &otherShard)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = c.ListSessions("ns-2", nil)
+	assert.ErrorIs(t, err, ErrNamespaceNotFound)
+
+	assert.NoError(t, c.ForceCloseSession(common.DefaultNamespace, si.Shard, si.SessionId))
+	for i := 0; i < 3; i++ {
+		_, _, _, err = client.Get(ctx, fmt.Sprintf("key-%d", i), oxia.PartitionKey("x"))
+		assert.ErrorIs(t, err, oxia.ErrKeyNotFound)
+	}
+
+	sessions, err = c.ListSessions(common.DefaultNamespace, &si.Shard)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+	assert.NoError(t, s2.Close())
+}
+
+func TestCoordinator_ClientThrottling(t *testing.T) {
+	s1, sa1 := newServer(t)
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 1,
+			Policies: &model.NamespacePolicies{
+				MaxClientOpsPerSecond: 5,
+			},
+		}},
+		Servers: []model.ServerAddress{sa1},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client1, err := oxia.NewSyncClient(sa1.Public, oxia.WithBatchLinger(0))
+	assert.NoError(t, err)
+	client2, err := oxia.NewSyncClient(sa1.Public, oxia.WithBatchLinger(0))
+	assert.NoError(t, err)
+
+	// The policies are distributed with the shard assignments
+	ctx := context.Background()
+	assert.Eventually(t, func() bool {
+		for i := 0; i < 10; i++ {
+			if _, _, err := client1.Put(ctx, "key-1", []byte("value")); errors.Is(err, oxia.ErrThrottled) {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// The other clients are not affected
+	_, _, err = client2.Put(ctx, "key-2", []byte("value"))
+	assert.NoError(t, err)
+
+	// The throttled client can write again once its rate is back under the limit
+	time.Sleep(1 * time.Second)
+	_, _, err = client1.Put(ctx, "key-1", []byte("value"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, client1.Close())
+	assert.NoError(t, client2.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+}
+
+func TestCoordinator_RangePartitioning(t *testing.T) {
+	s1, sa1 := newServer(t)
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			ReplicationFactor: 1,
+			InitialShardCount: 3,
+			Partitioning:      model.PartitioningRange,
+			RangeSplitKeys:    []string{"g", "p"},
+		}},
+		Servers: []model.ServerAddress{sa1},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public, oxia.WithNamespace("ns-1"))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for _, key := range []string{"c", "a", "e", "b", "h"} {
+		_, _, err = client.Put(ctx, key, []byte("value-"+key))
+		assert.NoError(t, err)
+	}
+
+	// The last shard can only be merged into its neighbour
+	assert.ErrorIs(t, c.MergeShard("ns-1", 2, 0), ErrShardsNotAdjacent)
+	assert.NoError(t, c.MergeShard("ns-1", 2, 1))
+	assert.Eventually(t, func() bool {
+		_, ok := c.ClusterStatus().Namespaces["ns-1"].Shards[2]
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, &model.KeyRange{Min: "g"}, c.ClusterStatus().Namespaces["ns-1"].Shards[1].KeyRange)
+
+	assert.Eventually(t, func() bool {
+		_, _, err := client.Put(ctx, "x", []byte("value-x"))
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	var keys []string
+	for res := range client.RangeScan(ctx, "a", "z") {
+		assert.NoError(t, res.Err)
+		keys = append(keys, res.Key)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "e", "h", "x"}, keys)
+
+	keys, err = client.List(ctx, "b", "d")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, keys)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+}
+
+func TestCoordinator_ClusterFreeze(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+	s4, sa4 := newServer(t)
+	servers := map[model.ServerAddress]*server.Server{
+		sa1: s1,
+		sa2: s2,
+		sa3: s3,
+		sa4: s4,
+	}
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 4,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2},
+	}
+	configLock := sync.Mutex{}
+	configChangesCh := make(chan any)
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) {
+		configLock.Lock()
+		defer configLock.Unlock()
+		return clusterConfig, nil
+	}, configChangesCh, NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	replicasPerServer := func() map[model.ServerAddress]int {
+		res := map[model.ServerAddress]int{}
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return nil
+			}
+			for _, sa := range shard.Ensemble {
+				res[sa]++
+			}
+		}
+		return res
+	}
+
+	assert.NoError(t, c.SetClusterFreeze(true, "INC-42"))
+	assert.True(t, c.ClusterStatus().IsFrozen())
+
+	operations := c.ListOperations()
+	assert.Len(t, operations, 1)
+	assert.Equal(t, OperationFreeze, operations[0].Type)
+	assert.Equal(t, "INC-42", operations[0].Description)
+
+	// The new servers are left empty while the cluster is frozen
+	configLock.Lock()
+	clusterConfig.Servers = []model.ServerAddress{sa1, sa2, sa3, sa4}
+	configLock.Unlock()
+	configChangesCh <- nil
+
+	assert.Never(t, func() bool {
+		r := replicasPerServer()
+		return r != nil && (r[sa3] > 0 || r[sa4] > 0)
+	}, 1*time.Second, 10*time.Millisecond)
+
+	_, err = c.RebalanceCluster(false)
+	assert.ErrorIs(t, err, ErrClusterFrozen)
+	moves, err := c.RebalanceCluster(true)
+	assert.NoError(t, err)
+	assert.Len(t, moves, 2)
+
+	// The held back moves are applied once the freeze is lifted
+	assert.NoError(t, c.SetClusterFreeze(false, ""))
+	assert.False(t, c.ClusterStatus().IsFrozen())
+
+	assert.Eventually(t, func() bool {
+		r := replicasPerServer()
+		return r[sa1] == 1 && r[sa2] == 1 && r[sa3] == 1 && r[sa4] == 1
+	}, 30*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+
+	for _, serverObj := range servers {
+		assert.NoError(t, serverObj.Close())
+	}
+}
+
+func TestCoordinator_ImportClusterStatus(t *testing.T) {
+	s1, sa1 := newServer(t)
+	s2, sa2 := newServer(t)
+	s3, sa3 := newServer(t)
+
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 3,
+			InitialShardCount: 2,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(NewMetadataProviderMemory(), func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, _, err = client.Put(ctx, fmt.Sprintf("key-%d", i), []byte("value"))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, client.Close())
+
+	exportedStatus := c.ClusterStatus()
+	export, err := ExportClusterStatus(&exportedStatus)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+
+	// Bootstrap a new coordinator, after losing the metadata store
+	metadataProvider := NewMetadataProviderMemory()
+	imported, err := ImportClusterStatus(metadataProvider, export)
+	assert.NoError(t, err)
+	assert.True(t, imported)
+
+	c, err = NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		for _, shard := range c.ClusterStatus().Namespaces[common.DefaultNamespace].Shards {
+			if shard.Status != model.ShardStatusSteadyState {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+
+	status := c.ClusterStatus()
+	for id, shard := range status.Namespaces[common.DefaultNamespace].Shards {
+		exportedShard := exportedStatus.Namespaces[common.DefaultNamespace].Shards[id]
+		assert.Equal(t, exportedShard.Ensemble, shard.Ensemble)
+		assert.Equal(t, exportedShard.Int32HashRange, shard.Int32HashRange)
+		assert.Greater(t, shard.Term, exportedShard.Term)
+	}
+
+	client, err = oxia.NewSyncClient(sa2.Public)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, value, _, err := client.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("value"), value)
+	}
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+	assert.NoError(t, s2.Close())
+	assert.NoError(t, s3.Close())
+}
+
+func TestCoordinator_KeyPrefixACLs(t *testing.T) {
+	s1, sa1 := newServer(t)
+
+	metadataProvider := NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 1,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1},
+	}
+	clientPool := common.NewClientPool(nil, nil)
+
+	c, err := NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil },
+		make(chan any), NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+
+	client, err := oxia.NewSyncClient(sa1.Public, oxia.WithBatchLinger(0))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "/configs/a/x", []byte("value"))
+	assert.NoError(t, err)
+
+	acl := model.KeyPrefixACL{
+		Principal:   "team-a",
+		Prefix:      "/configs/a/",
+		Permissions: []model.Permission{model.PermissionWrite},
+	}
+	assert.ErrorIs(t, c.SetKeyPrefixACL("unknown", acl), ErrNamespaceNotFound)
+	assert.ErrorIs(t, c.SetKeyPrefixACL(common.DefaultNamespace, model.KeyPrefixACL{Principal: "team-a", Prefix: "/configs"}),
+		ErrInvalidKeyPrefixACL)
+	assert.NoError(t, c.SetKeyPrefixACL(common.DefaultNamespace, acl))
+
+	// Setting the ACL again replaces the permissions of the principal on the prefix
+	acl.Permissions = []model.Permission{model.PermissionRead, model.PermissionWrite}
+	assert.NoError(t, c.SetKeyPrefixACL(common.DefaultNamespace, acl))
+
+	acls, err := c.ListKeyPrefixACLs(common.DefaultNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.KeyPrefixACL{acl}, acls)
+
+	// The ACLs are stored in the metadata
+	cs, _, err := metadataProvider.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.KeyPrefixACL{acl}, cs.Namespaces[common.DefaultNamespace].KeyPrefixACLs)
+
+	// The requests that are not authenticated are rejected, once the leader
+	// gets the ACLs with the shard assignments
+	assert.Eventually(t, func() bool {
+		_, _, err := client.Put(ctx, "/configs/a/x", []byte("value"))
+		return errors.Is(err, oxia.ErrPermissionDenied)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.ErrorIs(t, c.RemoveKeyPrefixACL(common.DefaultNamespace, "team-b", "/configs/a/"), ErrKeyPrefixACLNotFound)
+	assert.NoError(t, c.RemoveKeyPrefixACL(common.DefaultNamespace, "team-a", "/configs/a/"))
+
+	assert.Eventually(t, func() bool {
+		_, _, err := client.Put(ctx, "/configs/a/x", []byte("value"))
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, c.Close())
+	assert.NoError(t, clientPool.Close())
+	assert.NoError(t, s1.Close())
+}
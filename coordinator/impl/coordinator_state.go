@@ -0,0 +1,121 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// CoordinatorState is the in-memory state of the active coordinator, that is
+// not kept in the metadata store. It's mirrored by the standby coordinator
+// replicas, so that they can take over without rebuilding it.
+type CoordinatorState struct {
+	// MetadataVersion is the version of the cluster status when the state
+	// was taken
+	MetadataVersion Version
+
+	// UnavailableServers are the internal addresses of the servers that
+	// are failing the health checks
+	UnavailableServers []string
+
+	// ShardLoads are the latest loads reported by the shard leaders
+	ShardLoads map[int64]ShardLoad
+
+	Events        []Event
+	RebalancePlan *RebalancePlan
+	NextPlanId    int64
+}
+
+// WithStandbyState starts the coordinator from the state mirrored from the
+// previous active coordinator. The cluster status is still read from the
+// metadata store, which is the source of truth.
+func WithStandbyState(state *CoordinatorState) CoordinatorOption {
+	return func(c *coordinator) {
+		c.standbyState = state
+	}
+}
+
+func (c *coordinator) State() CoordinatorState {
+	c.Lock()
+	defer c.Unlock()
+
+	state := CoordinatorState{
+		MetadataVersion:    c.metadataVersion,
+		UnavailableServers: []string{},
+		ShardLoads:         c.shardLoads(),
+		Events:             c.events.list(),
+		NextPlanId:         c.nextPlanId,
+	}
+	for addr, nc := range c.nodeControllers {
+		if nc.Status() == NotRunning {
+			state.UnavailableServers = append(state.UnavailableServers, addr)
+		}
+	}
+	sort.Strings(state.UnavailableServers)
+
+	if c.rebalancePlan != nil {
+		state.RebalancePlan = c.rebalancePlan.Clone()
+	}
+	return state
+}
+
+// Take over the state of the previous coordinator. It must be called after
+// creating the node controllers, and before creating the shard controllers.
+func (c *coordinator) restoreStandbyState() {
+	state := c.standbyState
+	if state == nil {
+		return
+	}
+
+	if state.MetadataVersion != c.metadataVersion {
+		// The previous coordinator might have failed before the latest
+		// changes were mirrored: the parts of the state that depend on the
+		// cluster status are validated again before being used
+		c.log.Warn(
+			"The mirrored coordinator state is behind the cluster status",
+			slog.Any("mirrored-version", state.MetadataVersion),
+			slog.Any("metadata-version", c.metadataVersion),
+		)
+	}
+
+	c.events.restore(state.Events)
+
+	for _, addr := range state.UnavailableServers {
+		if nc, ok := c.nodeControllers[addr]; ok {
+			nc.SetStatus(NotRunning)
+		}
+	}
+	c.restoredLoads = state.ShardLoads
+
+	c.nextPlanId = state.NextPlanId
+	if plan := state.RebalancePlan; plan != nil {
+		c.rebalancePlan = plan.Clone()
+		for i := range c.rebalancePlan.Moves {
+			// The interrupted move is validated again, and skipped if it
+			// was already applied
+			if c.rebalancePlan.Moves[i].State == MoveStateInProgress {
+				c.rebalancePlan.Moves[i].State = MoveStatePending
+			}
+		}
+	}
+
+	c.log.Info(
+		"Restored the state mirrored from the previous coordinator",
+		slog.Int("events", len(state.Events)),
+		slog.Any("unavailable-servers", state.UnavailableServers),
+		slog.Int("shard-loads", len(state.ShardLoads)),
+	)
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// ElectionPolicy spreads the leader elections over time, so that a burst of
+// server restarts doesn't take all the shards to a new term at once.
+type ElectionPolicy interface {
+	// WaitForElectionRound blocks until a new election round can involve all
+	// the given servers, or the context is done
+	WaitForElectionRound(ctx context.Context, servers []model.ServerAddress) error
+
+	// LeaderReturnTimeout is how long to wait for a failed leader to come
+	// back, before electing a new one
+	LeaderReturnTimeout() time.Duration
+
+	// StuckElectionTimeout is how long a shard can stay without a leader,
+	// before a different ensemble is tried
+	StuckElectionTimeout() time.Duration
+
+	// IsDraining tells whether the server is drained, in which case it is
+	// only elected leader when no other server can be
+	IsDraining(server model.ServerAddress) bool
+}
+
+// Used when the stuck timeout is not set in the cluster config
+const defaultStuckElectionTimeout = 5 * time.Minute
+
+// electionThrottle keeps a rate limiter for each server, identified by its
+// internal address.
+type electionThrottle struct {
+	sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newElectionThrottle() *electionThrottle {
+	return &electionThrottle{
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+func (t *electionThrottle) wait(ctx context.Context, config *model.ElectionsConfig, servers []model.ServerAddress) error {
+	if config == nil || config.MaxRoundsPerSecond <= 0 {
+		return nil
+	}
+
+	// Always wait on the servers in the same order, so that the rounds that
+	// share some servers are queued fairly
+	sorted := make([]model.ServerAddress, len(servers))
+	copy(sorted, servers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Internal < sorted[j].Internal })
+
+	for _, sa := range sorted {
+		if err := t.limiter(sa, config).Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *electionThrottle) limiter(sa model.ServerAddress, config *model.ElectionsConfig) *rate.Limiter {
+	t.Lock()
+	defer t.Unlock()
+
+	limit := rate.Limit(config.MaxRoundsPerSecond)
+	burst := config.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	l, ok := t.limiters[sa.Internal]
+	if !ok {
+		l = rate.NewLimiter(limit, burst)
+		t.limiters[sa.Internal] = l
+	} else if l.Limit() != limit || l.Burst() != burst {
+		// The config has changed
+		l.SetLimit(limit)
+		l.SetBurst(burst)
+	}
+	return l
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestElectionThrottle(t *testing.T) {
+	s1 := model.ServerAddress{Public: "s1:6648", Internal: "s1:6649"}
+	s2 := model.ServerAddress{Public: "s2:6648", Internal: "s2:6649"}
+	s3 := model.ServerAddress{Public: "s3:6648", Internal: "s3:6649"}
+	ctx := context.Background()
+	throttle := newElectionThrottle()
+
+	// Not throttled without a rate
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, throttle.wait(ctx, nil, []model.ServerAddress{s1, s2}))
+		assert.NoError(t, throttle.wait(ctx, &model.ElectionsConfig{}, []model.ServerAddress{s1, s2}))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	config := &model.ElectionsConfig{MaxRoundsPerSecond: 10}
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, throttle.wait(ctx, config, []model.ServerAddress{s1, s2}))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+
+	// The other servers are not affected
+	start = time.Now()
+	assert.NoError(t, throttle.wait(ctx, config, []model.ServerAddress{s3}))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// The queued rounds are abandoned when the context is done
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	s4 := model.ServerAddress{Public: "s4:6648", Internal: "s4:6649"}
+	config = &model.ElectionsConfig{MaxRoundsPerSecond: 0.1, Burst: 1}
+	assert.NoError(t, throttle.wait(ctx, config, []model.ServerAddress{s4}))
+	assert.Error(t, throttle.wait(ctx, config, []model.ServerAddress{s4}))
+}
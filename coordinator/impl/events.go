@@ -0,0 +1,204 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// The max number of events kept by the coordinator.
+const eventLogSize = 1000
+
+// The max number of events that a watcher can fall behind, before being
+// disconnected.
+const eventWatcherBufferSize = 100
+
+type EventType string
+
+const (
+	EventLeaderElectionStarted EventType = "leader-election-started"
+	EventLeaderElected         EventType = "leader-elected"
+	EventNodeFailed            EventType = "node-failed"
+	EventShardDeleted          EventType = "shard-deleted"
+	EventClusterConfigChanged  EventType = "cluster-config-changed"
+	EventOperationStarted      EventType = "operation-started"
+	EventOperationCompleted    EventType = "operation-completed"
+	EventServerStatusChanged   EventType = "server-status-changed"
+	EventClusterFreezeChanged  EventType = "cluster-freeze-changed"
+	EventEnsembleChanged       EventType = "ensemble-changed"
+	EventNamespaceCreated      EventType = "namespace-created"
+	EventNamespaceDeleted      EventType = "namespace-deleted"
+)
+
+// Event is a decision taken by the coordinator, or a change it observed in
+// the cluster.
+type Event struct {
+	Time        time.Time
+	Type        EventType
+	Namespace   string
+	Shard       *int64
+	Server      *model.ServerAddress
+	Description string
+}
+
+// eventLog keeps the latest events in memory, dropping the oldest ones once
+// it's full. The events are lost when the coordinator restarts.
+type eventLog struct {
+	sync.Mutex
+	events []Event
+	next   int
+	full   bool
+
+	watchers     map[int64]chan Event
+	nextWatcher  int64
+	watcherLimit int
+
+	// Called with each event, outside the lock
+	onRecord func(Event)
+}
+
+func newEventLog(size int) *eventLog {
+	return &eventLog{
+		events:       make([]Event, size),
+		watchers:     map[int64]chan Event{},
+		watcherLimit: eventWatcherBufferSize,
+	}
+}
+
+func (l *eventLog) record(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	l.append(e)
+	if l.onRecord != nil {
+		l.onRecord(e)
+	}
+}
+
+func (l *eventLog) append(e Event) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.add(e)
+	for id, ch := range l.watchers {
+		select {
+		case ch <- e:
+		default:
+			// Don't let a slow watcher hold back the coordinator
+			close(ch)
+			delete(l.watchers, id)
+		}
+	}
+}
+
+// restore adds the events of the previous coordinator, without publishing
+// them again to the watchers nor to the audit log.
+func (l *eventLog) restore(events []Event) {
+	l.Lock()
+	defer l.Unlock()
+
+	for _, e := range events {
+		l.add(e)
+	}
+}
+
+func (l *eventLog) add(e Event) {
+	l.events[l.next] = e
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// watch returns the channel where the new events are published, along with
+// the events already in the log, if includeRecent is set. The channel is
+// closed when the watcher falls behind, when the log is closed, or when the
+// returned function is called.
+func (l *eventLog) watch(includeRecent bool) ([]Event, <-chan Event, func()) {
+	l.Lock()
+	defer l.Unlock()
+
+	var recent []Event
+	if includeRecent {
+		recent = l.listLocked()
+	}
+
+	id := l.nextWatcher
+	l.nextWatcher++
+	ch := make(chan Event, l.watcherLimit)
+	l.watchers[id] = ch
+
+	return recent, ch, func() {
+		l.Lock()
+		defer l.Unlock()
+		if _, ok := l.watchers[id]; ok {
+			close(ch)
+			delete(l.watchers, id)
+		}
+	}
+}
+
+// close disconnects all the watchers.
+func (l *eventLog) close() {
+	l.Lock()
+	defer l.Unlock()
+
+	for id, ch := range l.watchers {
+		close(ch)
+		delete(l.watchers, id)
+	}
+}
+
+// list returns the events from the oldest to the newest.
+func (l *eventLog) list() []Event {
+	l.Lock()
+	defer l.Unlock()
+	return l.listLocked()
+}
+
+func (l *eventLog) listLocked() []Event {
+	if !l.full {
+		return append([]Event{}, l.events[:l.next]...)
+	}
+
+	res := make([]Event, 0, len(l.events))
+	res = append(res, l.events[l.next:]...)
+	return append(res, l.events[:l.next]...)
+}
+
+func sameEnsemble(a []model.ServerAddress, b []model.ServerAddress) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, sa := range a {
+		if !listContains(b, sa) {
+			return false
+		}
+	}
+	return true
+}
+
+func ensembleString(ensemble []model.ServerAddress) string {
+	servers := make([]string, 0, len(ensemble))
+	for _, sa := range ensemble {
+		servers = append(servers, sa.Internal)
+	}
+	return "[" + strings.Join(servers, ", ") + "]"
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestEventLog(t *testing.T) {
+	l := newEventLog(3)
+	assert.Empty(t, l.list())
+
+	l.record(Event{Type: EventNodeFailed, Server: &s1})
+	l.record(Event{Type: EventLeaderElected, Server: &s2})
+
+	events := l.list()
+	assert.Len(t, events, 2)
+	assert.Equal(t, EventNodeFailed, events[0].Type)
+	assert.Equal(t, EventLeaderElected, events[1].Type)
+	assert.False(t, events[0].Time.IsZero())
+
+	// The oldest events are dropped once the log is full
+	for i := 0; i < 4; i++ {
+		l.record(Event{Type: EventClusterConfigChanged, Time: time.UnixMilli(int64(i))})
+	}
+	events = l.list()
+	assert.Len(t, events, 3)
+	for i, e := range events {
+		assert.Equal(t, EventClusterConfigChanged, e.Type)
+		assert.Equal(t, time.UnixMilli(int64(i+1)), e.Time)
+	}
+}
+
+func TestEventLog_Watch(t *testing.T) {
+	l := newEventLog(10)
+	l.watcherLimit = 2
+	l.record(Event{Type: EventNodeFailed, Server: &s1})
+
+	recent, ch, stop := l.watch(true)
+	assert.Len(t, recent, 1)
+	assert.Equal(t, EventNodeFailed, recent[0].Type)
+
+	l.record(Event{Type: EventLeaderElected, Server: &s2})
+	e := <-ch
+	assert.Equal(t, EventLeaderElected, e.Type)
+	stop()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// The watchers that fall behind are disconnected
+	recent, ch, stop = l.watch(false)
+	defer stop()
+	assert.Empty(t, recent)
+	for i := 0; i < 3; i++ {
+		l.record(Event{Type: EventClusterConfigChanged})
+	}
+	assert.Len(t, l.watchers, 0)
+	count := 0
+	for range ch {
+		count++
+	}
+	assert.Equal(t, 2, count)
+
+	_, ch, _ = l.watch(false)
+	l.close()
+	_, ok = <-ch
+	assert.False(t, ok)
+}
+
+func TestEventLog_Restore(t *testing.T) {
+	l := newEventLog(3)
+	recorded := 0
+	l.onRecord = func(Event) { recorded++ }
+	_, ch, stop := l.watch(false)
+	defer stop()
+
+	l.restore([]Event{
+		{Type: EventNodeFailed, Server: &s1, Time: time.UnixMilli(1)},
+		{Type: EventLeaderElected, Server: &s2, Time: time.UnixMilli(2)},
+	})
+	l.record(Event{Type: EventClusterConfigChanged, Time: time.UnixMilli(3)})
+
+	events := l.list()
+	assert.Len(t, events, 3)
+	assert.Equal(t, EventNodeFailed, events[0].Type)
+	assert.Equal(t, EventClusterConfigChanged, events[2].Type)
+
+	// The restored events are not published again
+	assert.Equal(t, 1, recorded)
+	assert.Len(t, ch, 1)
+}
+
+func TestEnsembleString(t *testing.T) {
+	assert.True(t, sameEnsemble([]model.ServerAddress{s1, s2}, []model.ServerAddress{s2, s1}))
+	assert.False(t, sameEnsemble([]model.ServerAddress{s1, s2}, []model.ServerAddress{s1, s3}))
+	assert.False(t, sameEnsemble([]model.ServerAddress{s1, s2}, []model.ServerAddress{s1}))
+	assert.Equal(t, "[s1:6649, s2:6649]", ensembleString([]model.ServerAddress{s1, s2}))
+}
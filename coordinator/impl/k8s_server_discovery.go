@@ -0,0 +1,268 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+const (
+	// The label of the server pods holding their zone, as set by the chart
+	k8sZoneLabel = "oxia_zone"
+
+	k8sWatchRetryDelay = 5 * time.Second
+)
+
+// K8SServerDiscoveryOptions identifies the server pods, and how they are
+// reached through their headless service.
+type K8SServerDiscoveryOptions struct {
+	Namespace     string
+	LabelSelector string
+	Service       string
+	PublicPort    int
+	InternalPort  int
+}
+
+// K8SServerDiscovery adds the server pods of a Kubernetes namespace to the
+// servers of the cluster config, so that the servers added by an autoscaler
+// join the cluster, and get their share of the shards, without the config
+// being edited.
+//
+// A pod joins once it is ready, and stays a server until its StatefulSet is
+// scaled down below it, so that the restart of a server does not move its
+// replicas away.
+type K8SServerDiscovery struct {
+	sync.Mutex
+	kc      kubernetes.Interface
+	options K8SServerDiscoveryOptions
+	joined  map[string]corev1.Pod
+	servers []string
+	log     *slog.Logger
+}
+
+func NewK8SServerDiscovery(kc kubernetes.Interface, options K8SServerDiscoveryOptions) *K8SServerDiscovery {
+	return &K8SServerDiscovery{
+		kc:      kc,
+		options: options,
+		joined:  map[string]corev1.Pod{},
+		log: slog.With(
+			slog.String("component", "k8s-server-discovery"),
+			slog.String("k8s-namespace", options.Namespace),
+			slog.String("selector", options.LabelSelector),
+		),
+	}
+}
+
+// Merge appends the discovered servers to the ones of the cluster config,
+// along with their zone, unless they are already listed.
+func (d *K8SServerDiscovery) Merge(cc *model.ClusterConfig) error {
+	pods, err := d.discover(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		server := d.serverAddress(pod.Name)
+		if slices.ContainsFunc(cc.Servers, func(s model.ServerAddress) bool { return s.Internal == server.Internal }) {
+			continue
+		}
+		cc.Servers = append(cc.Servers, server)
+
+		zone, ok := pod.Labels[k8sZoneLabel]
+		if !ok {
+			continue
+		}
+		if cc.ServerMetadata == nil {
+			cc.ServerMetadata = map[string]model.ServerMetadata{}
+		}
+		sm := cc.ServerMetadata[server.Internal]
+		if _, ok := sm.Labels["zone"]; !ok {
+			labels := map[string]string{"zone": zone}
+			for k, v := range sm.Labels {
+				labels[k] = v
+			}
+			sm.Labels = labels
+		}
+		cc.ServerMetadata[server.Internal] = sm
+	}
+	return nil
+}
+
+// Watch calls onChange each time a server pod joins or leaves, until the
+// context is done.
+func (d *K8SServerDiscovery) Watch(ctx context.Context, onChange func()) {
+	for ctx.Err() == nil {
+		if err := d.watch(ctx, onChange); err != nil {
+			d.log.Warn("Failed to watch the server pods", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(k8sWatchRetryDelay):
+		}
+	}
+}
+
+func (d *K8SServerDiscovery) watch(ctx context.Context, onChange func()) error {
+	w, err := d.kc.CoreV1().Pods(d.options.Namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: d.options.LabelSelector,
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	// The pods may have changed while the watch was not set up
+	if err := d.refreshAndNotify(ctx, onChange); err != nil {
+		return err
+	}
+	for range w.ResultChan() {
+		if err := d.refreshAndNotify(ctx, onChange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *K8SServerDiscovery) refreshAndNotify(ctx context.Context, onChange func()) error {
+	changed, err := d.refresh(ctx)
+	if err != nil {
+		return err
+	}
+	if changed {
+		onChange()
+	}
+	return nil
+}
+
+// Lists the pods again, and tells whether the servers are not the same as
+// the last time they were discovered.
+func (d *K8SServerDiscovery) refresh(ctx context.Context) (bool, error) {
+	pods, err := d.discover(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	servers := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		servers = append(servers, pod.Name)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	if slices.Equal(servers, d.servers) {
+		return false, nil
+	}
+
+	d.log.Info(
+		"The server pods have changed",
+		slog.Any("previous", d.servers),
+		slog.Any("current", servers),
+	)
+	d.servers = servers
+	return true, nil
+}
+
+// Lists the pods that have been ready, and that are still part of their
+// StatefulSet. The pods deleted to be restarted are kept, as they are
+// recreated with the same name, unlike the ones removed by a scale down.
+func (d *K8SServerDiscovery) discover(ctx context.Context) ([]corev1.Pod, error) {
+	listOptions := metav1.ListOptions{LabelSelector: d.options.LabelSelector}
+	statefulSets, err := d.kc.AppsV1().StatefulSets(d.options.Namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	list, err := d.kc.CoreV1().Pods(d.options.Namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := map[string]int{}
+	for _, sts := range statefulSets.Items {
+		replicas[sts.Name] = 1
+		if sts.Spec.Replicas != nil {
+			replicas[sts.Name] = int(*sts.Spec.Replicas)
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for _, pod := range list.Items {
+		if isPodReady(&pod) {
+			d.joined[pod.Name] = pod
+		}
+	}
+
+	var pods []corev1.Pod
+	for name, pod := range d.joined {
+		sts, ordinal, ok := statefulSetOrdinal(&pod)
+		if !ok || ordinal >= replicas[sts] {
+			delete(d.joined, name)
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return pods, nil
+}
+
+func (d *K8SServerDiscovery) serverAddress(pod string) model.ServerAddress {
+	return model.ServerAddress{
+		Public: fmt.Sprintf("%s.%s.%s.svc.cluster.local:%d",
+			pod, d.options.Service, d.options.Namespace, d.options.PublicPort),
+		Internal: fmt.Sprintf("%s.%s:%d", pod, d.options.Service, d.options.InternalPort),
+	}
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Returns the StatefulSet that owns the pod, and the ordinal of the pod in it.
+func statefulSetOrdinal(pod *corev1.Pod) (string, int, bool) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind != "StatefulSet" {
+			continue
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, owner.Name+"-"))
+		if err != nil {
+			return "", 0, false
+		}
+		return owner.Name, ordinal, true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+var testServerLabels = map[string]string{"app.kubernetes.io/component": "server"}
+
+func testStatefulSet(name string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: testServerLabels},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+}
+
+func testServerPod(sts string, name string, ready bool, labels map[string]string) *corev1.Pod {
+	podLabels := map[string]string{}
+	for k, v := range testServerLabels {
+		podLabels[k] = v
+	}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ns",
+			Labels:          podLabels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: sts}},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func discoveredServers(t *testing.T, d *K8SServerDiscovery, static ...model.ServerAddress) []string {
+	t.Helper()
+	cc := model.ClusterConfig{Servers: static}
+	assert.NoError(t, d.Merge(&cc))
+
+	var servers []string
+	for _, s := range cc.Servers {
+		servers = append(servers, s.Internal)
+	}
+	return servers
+}
+
+func TestK8SServerDiscovery(t *testing.T) {
+	ctx := context.Background()
+	kc := fake.NewSimpleClientset(
+		testStatefulSet("oxia", 3),
+		testServerPod("oxia", "oxia-0", true, nil),
+		testServerPod("oxia", "oxia-1", true, nil),
+		testServerPod("oxia", "oxia-2", false, nil),
+	)
+	d := NewK8SServerDiscovery(kc, K8SServerDiscoveryOptions{
+		Namespace:     "ns",
+		LabelSelector: "app.kubernetes.io/component=server",
+		Service:       "oxia-svc",
+		PublicPort:    6648,
+		InternalPort:  6649,
+	})
+
+	// The servers already in the config are not listed twice
+	static := model.ServerAddress{Public: "oxia-0.oxia-svc.ns.svc.cluster.local:6648", Internal: "oxia-0.oxia-svc:6649"}
+	assert.Equal(t, []string{"oxia-0.oxia-svc:6649", "oxia-1.oxia-svc:6649"}, discoveredServers(t, d, static))
+
+	cc := model.ClusterConfig{}
+	assert.NoError(t, d.Merge(&cc))
+	assert.Equal(t, model.ServerAddress{
+		Public:   "oxia-1.oxia-svc.ns.svc.cluster.local:6648",
+		Internal: "oxia-1.oxia-svc:6649",
+	}, cc.Servers[1])
+
+	changes := make(chan any, 10)
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go d.Watch(wctx, func() { changes <- nil })
+
+	// The initial servers are notified once the watch is set up
+	<-changes
+
+	// A pod joins once it is ready
+	_, err := kc.CoreV1().Pods("ns").Update(ctx, testServerPod("oxia", "oxia-2", true, nil), metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(changes) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"oxia-0.oxia-svc:6649", "oxia-1.oxia-svc:6649", "oxia-2.oxia-svc:6649"},
+		discoveredServers(t, d))
+
+	// A restarted pod stays a server
+	assert.NoError(t, kc.CoreV1().Pods("ns").Delete(ctx, "oxia-1", metav1.DeleteOptions{}))
+	assert.Equal(t, []string{"oxia-0.oxia-svc:6649", "oxia-1.oxia-svc:6649", "oxia-2.oxia-svc:6649"},
+		discoveredServers(t, d))
+
+	// The pods removed by a scale down leave
+	_, err = kc.AppsV1().StatefulSets("ns").Update(ctx, testStatefulSet("oxia", 1), metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"oxia-0.oxia-svc:6649"}, discoveredServers(t, d))
+}
+
+func TestK8SServerDiscovery_Zones(t *testing.T) {
+	kc := fake.NewSimpleClientset(
+		testStatefulSet("oxia-a", 1),
+		testStatefulSet("oxia-b", 1),
+		testServerPod("oxia-a", "oxia-a-0", true, map[string]string{"oxia_zone": "a"}),
+		testServerPod("oxia-b", "oxia-b-0", true, map[string]string{"oxia_zone": "b"}),
+		testServerPod("oxia-b", "oxia-b-1", true, map[string]string{"oxia_zone": "b"}),
+	)
+	d := NewK8SServerDiscovery(kc, K8SServerDiscoveryOptions{
+		Namespace:     "ns",
+		LabelSelector: "app.kubernetes.io/component=server",
+		Service:       "oxia-svc",
+		PublicPort:    6648,
+		InternalPort:  6649,
+	})
+
+	cc := model.ClusterConfig{
+		ServerMetadata: map[string]model.ServerMetadata{
+			"oxia-b-0.oxia-svc:6649": {Labels: map[string]string{"rack": "r1"}},
+		},
+	}
+	assert.NoError(t, d.Merge(&cc))
+
+	// oxia-b-1 is not part of its StatefulSet anymore
+	assert.Len(t, cc.Servers, 2)
+	assert.Equal(t, map[string]model.ServerMetadata{
+		"oxia-a-0.oxia-svc:6649": {Labels: map[string]string{"zone": "a"}},
+		"oxia-b-0.oxia-svc:6649": {Labels: map[string]string{"zone": "b", "rack": "r1"}},
+	}, cc.ServerMetadata)
+}
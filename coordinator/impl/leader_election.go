@@ -0,0 +1,268 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/fslock"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	leaseDuration      = 15 * time.Second
+	leaseRenewDeadline = 10 * time.Second
+	leaseRetryPeriod   = 2 * time.Second
+)
+
+// LeaderElection makes sure that only one of the coordinator replicas is
+// managing the cluster at any given time.
+type LeaderElection interface {
+	// Run campaigns for the leadership and calls onStartedLeading once it's
+	// acquired. The context passed to onStartedLeading is canceled when the
+	// leadership is lost. Run returns after onStartedLeading has returned, or
+	// when ctx is canceled.
+	Run(ctx context.Context, onStartedLeading func(ctx context.Context))
+}
+
+// Leader election based on a lease record, kept in a Kubernetes lease object
+// or in a key of the metadata store.
+type leaseLeaderElection struct {
+	lock resourcelock.Interface
+	log  *slog.Logger
+}
+
+func NewK8SLeaseLeaderElection(kc kubernetes.Interface, namespace string, name string, identity string) LeaderElection {
+	return newLeaseLeaderElection(&resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Client: kc.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}, slog.String("lease", name))
+}
+
+// NewMetadataLeaderElection keeps the lease record in the given key of the
+// metadata store, next to the cluster status. It returns nil if the metadata
+// provider doesn't support it.
+func NewMetadataLeaderElection(provider MetadataProvider, key string, identity string) LeaderElection {
+	store, ok := provider.(metadataKeyStore)
+	if !ok {
+		return nil
+	}
+
+	return newLeaseLeaderElection(&metadataKeyLock{
+		store:    store,
+		key:      key,
+		identity: identity,
+		version:  MetadataNotExists,
+	}, slog.String("key", key))
+}
+
+func newLeaseLeaderElection(lock resourcelock.Interface, lockAttr slog.Attr) LeaderElection {
+	return &leaseLeaderElection{
+		lock: lock,
+		log: slog.With(
+			slog.String("component", "leader-election"),
+			lockAttr,
+			slog.String("identity", lock.Identity()),
+		),
+	}
+}
+
+func (le *leaseLeaderElection) Run(ctx context.Context, onStartedLeading func(ctx context.Context)) {
+	var started atomic.Bool
+	done := make(chan any)
+
+	le.log.Info("Waiting to acquire the leadership")
+
+	// The callback is invoked in a separate go-routine, we need to wait for
+	// it to be completed, before giving the chance to start a new term.
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            le.lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   leaseRenewDeadline,
+		RetryPeriod:     leaseRetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				started.Store(true)
+				defer close(done)
+
+				le.log.Info("Acquired the leadership")
+				onStartedLeading(ctx)
+			},
+			OnStoppedLeading: func() {
+				le.log.Info("Lost the leadership")
+			},
+		},
+	})
+
+	if started.Load() {
+		<-done
+	}
+}
+
+// metadataKeyStore is implemented by the metadata providers that support
+// conditional updates on arbitrary keys.
+type metadataKeyStore interface {
+	getKey(ctx context.Context, key string) (value []byte, version Version, err error)
+	putKey(ctx context.Context, key string, value []byte, expectedVersion Version) (newVersion Version, err error)
+}
+
+// metadataKeyLock keeps the lease record in a key of the metadata store, and
+// relies on the version of the key for the conditional updates. Just like for
+// the Kubernetes lease object, the lease expiration is tracked with the local
+// clock of each replica, from the last time the record was observed to change.
+type metadataKeyLock struct {
+	store    metadataKeyStore
+	key      string
+	identity string
+
+	// The version of the record that was last read or written
+	version Version
+}
+
+func (l *metadataKeyLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	value, version, err := l.store.getKey(ctx, l.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == MetadataNotExists {
+		return nil, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "leader-record"}, l.key)
+	}
+
+	record := &resourcelock.LeaderElectionRecord{}
+	if err = json.Unmarshal(value, record); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid leader election record")
+	}
+
+	l.version = version
+	return record, value, nil
+}
+
+func (l *metadataKeyLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	return l.put(ctx, ler, MetadataNotExists)
+}
+
+func (l *metadataKeyLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	return l.put(ctx, ler, l.version)
+}
+
+func (l *metadataKeyLock) put(ctx context.Context, ler resourcelock.LeaderElectionRecord, expectedVersion Version) error {
+	value, err := json.Marshal(ler)
+	if err != nil {
+		return err
+	}
+
+	version, err := l.store.putKey(ctx, l.key, value, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	l.version = version
+	return nil
+}
+
+func (*metadataKeyLock) RecordEvent(string) {}
+
+func (l *metadataKeyLock) Identity() string {
+	return l.identity
+}
+
+func (l *metadataKeyLock) Describe() string {
+	return l.key
+}
+
+// Leader election based on a lock on a local file, for the coordinator replicas
+// that share the same file system.
+type fileLeaderElection struct {
+	path          string
+	retryInterval time.Duration
+	log           *slog.Logger
+}
+
+func NewFileLeaderElection(path string) LeaderElection {
+	return &fileLeaderElection{
+		path:          path,
+		retryInterval: leaseRetryPeriod,
+		log: slog.With(
+			slog.String("component", "leader-election"),
+			slog.String("path", path),
+		),
+	}
+}
+
+func (le *fileLeaderElection) Run(ctx context.Context, onStartedLeading func(ctx context.Context)) {
+	le.log.Info("Waiting to acquire the leadership")
+
+	lock := fslock.New(le.path)
+	for {
+		err := le.tryLock(lock)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, fslock.ErrLocked) {
+			le.log.Warn(
+				"Failed to acquire the leader lock",
+				slog.Any("error", err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(le.retryInterval):
+		}
+	}
+
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			le.log.Warn(
+				"Failed to release the leader lock",
+				slog.Any("error", err),
+			)
+		}
+	}()
+
+	// The file lock is held until the process goes away, so the
+	// leadership is only lost when we're shutting down
+	le.log.Info("Acquired the leadership")
+	onStartedLeading(ctx)
+}
+
+func (le *fileLeaderElection) tryLock(lock *fslock.Lock) error {
+	if err := os.MkdirAll(filepath.Dir(le.path), 0755); err != nil {
+		return err
+	}
+
+	return lock.TryLock()
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testLeaderElection(t *testing.T, le1 LeaderElection, le2 LeaderElection) {
+	t.Helper()
+
+	var leader atomic.Int32
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	done1 := make(chan any)
+	go func() {
+		le1.Run(ctx1, func(ctx context.Context) {
+			leader.Store(1)
+			<-ctx.Done()
+			leader.Store(0)
+		})
+		close(done1)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return leader.Load() == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	done2 := make(chan any)
+	go func() {
+		le2.Run(ctx2, func(ctx context.Context) {
+			leader.Store(2)
+			<-ctx.Done()
+		})
+		close(done2)
+	}()
+
+	// The second replica stays on standby
+	time.Sleep(500 * time.Millisecond)
+	assert.EqualValues(t, 1, leader.Load())
+
+	// The first replica goes away and the second one takes over
+	cancel1()
+	<-done1
+
+	assert.Eventually(t, func() bool {
+		return leader.Load() == 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	cancel2()
+	<-done2
+}
+
+func TestFileLeaderElection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster-status.json.leader")
+
+	newElection := func() LeaderElection {
+		return &fileLeaderElection{
+			path:          path,
+			retryInterval: 10 * time.Millisecond,
+			log:           slog.Default(),
+		}
+	}
+
+	testLeaderElection(t, newElection(), newElection())
+}
+
+func TestK8SLeaseLeaderElection(t *testing.T) {
+	kc := fake.NewSimpleClientset()
+
+	testLeaderElection(t,
+		NewK8SLeaseLeaderElection(kc, "ns", "oxia-leader", "coordinator-0"),
+		NewK8SLeaseLeaderElection(kc, "ns", "oxia-leader", "coordinator-1"),
+	)
+}
+
+func TestMetadataLeaderElection(t *testing.T) {
+	for _, name := range []string{"etcd", "oxia"} {
+		t.Run(name, func(t *testing.T) {
+			m := metadataProviders[name](t)
+			defer m.Close()
+
+			testLeaderElection(t,
+				NewMetadataLeaderElection(m, "/oxia/cluster-status-leader", "coordinator-0"),
+				NewMetadataLeaderElection(m, "/oxia/cluster-status-leader", "coordinator-1"),
+			)
+
+			// The cluster status is left untouched
+			cs, version, err := m.Get()
+			assert.NoError(t, err)
+			assert.Nil(t, cs)
+			assert.Equal(t, MetadataNotExists, version)
+		})
+	}
+
+	assert.Nil(t, NewMetadataLeaderElection(NewMetadataProviderMemory(), "leader", "coordinator-0"))
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+const (
+	defaultLoadBalancerThreshold          = 0.2
+	defaultLoadBalancerMaxConcurrentMoves = 1
+
+	// How often to check whether the load balancer got enabled in the
+	// cluster config
+	loadBalancerDisabledCheckInterval = 30 * time.Second
+)
+
+// LoadScorer converts the load of a shard into a single score. The load
+// balancer tries to equalize the sum of the scores across the servers.
+type LoadScorer func(load ShardLoad) float64
+
+var loadScorers = map[model.LoadScoringPolicy]LoadScorer{
+	model.LoadScoringPolicyOps: func(load ShardLoad) float64 {
+		return load.WriteOpsRate + load.ReadOpsRate
+	},
+	model.LoadScoringPolicyBytes: func(load ShardLoad) float64 {
+		return load.WriteBytesRate
+	},
+	model.LoadScoringPolicyDisk: func(load ShardLoad) float64 {
+		return float64(load.DiskBytes)
+	},
+}
+
+// RegisterLoadScorer makes a custom scoring policy available to be
+// selected in the load balancer configuration.
+func RegisterLoadScorer(policy model.LoadScoringPolicy, scorer LoadScorer) {
+	loadScorers[policy] = scorer
+}
+
+func getLoadScorer(policy model.LoadScoringPolicy) (LoadScorer, bool) {
+	if policy == "" {
+		policy = model.LoadScoringPolicyOps
+	}
+	scorer, ok := loadScorers[policy]
+	return scorer, ok
+}
+
+type serverLoad struct {
+	addr   model.ServerAddress
+	score  float64
+	shards []int64
+}
+
+// Move the replicas of the shards, from the servers with the highest
+// load to the ones with the lowest load, until all the servers are within
+// the threshold from the average load of their pool, or until the max number of moves
+// is reached.
+func computeLoadBalancingMoves(config *model.ClusterConfig, status *model.ClusterStatus,
+	loads map[int64]ShardLoad, scorer LoadScorer) []SwapNodeAction {
+	lbConfig := config.LoadBalancer
+	threshold := lbConfig.Threshold
+	if threshold <= 0 {
+		threshold = defaultLoadBalancerThreshold
+	}
+	maxMoves := lbConfig.MaxConcurrentMoves
+	if maxMoves <= 0 {
+		maxMoves = defaultLoadBalancerMaxConcurrentMoves
+	}
+
+	servers := map[model.ServerAddress]*serverLoad{}
+	for _, sa := range config.Servers {
+		servers[sa] = &serverLoad{addr: sa}
+	}
+
+	shardScores := map[int64]float64{}
+	movable := map[int64]bool{}
+	for _, ns := range status.Namespaces {
+		for shard, sm := range ns.Shards {
+			score := scorer(loads[shard])
+			shardScores[shard] = score
+			// There's no point in moving the data of a namespace that is being deleted
+			movable[shard] = sm.Status == model.ShardStatusSteadyState && !ns.IsSoftDeleted()
+
+			for _, sa := range sm.Ensemble {
+				if sl, ok := servers[sa]; ok {
+					sl.score += score
+					sl.shards = append(sl.shards, shard)
+				}
+			}
+		}
+	}
+
+	policy := newPlacementPolicy(config)
+	placement := getShardsPlacement(status)
+	res := make([]SwapNodeAction, 0)
+
+	// The replicas never leave the pool of their namespace, so the load is
+	// balanced separately within each pool
+	for _, pool := range policy.sortedPools(config.Servers) {
+		poolServers := map[model.ServerAddress]*serverLoad{}
+		for sa, sl := range servers {
+			if policy.poolOf(sa) == pool {
+				poolServers[sa] = sl
+			}
+		}
+
+		res = append(res, balanceServersLoad(policy, placement, poolServers, shardScores, movable,
+			threshold, maxMoves-len(res))...)
+	}
+
+	return res
+}
+
+// balanceServersLoad moves the replicas across the given servers, which are
+// all in the same pool.
+func balanceServersLoad(policy *placementPolicy, placement shardsPlacement, //nolint:revive
+	servers map[model.ServerAddress]*serverLoad, shardScores map[int64]float64, movable map[int64]bool,
+	threshold float64, maxMoves int) []SwapNodeAction {
+	if len(servers) < 2 {
+		return nil
+	}
+
+	total := 0.0
+	for _, sl := range servers {
+		total += sl.score
+		slices.Sort(sl.shards)
+	}
+	avg := total / float64(len(servers))
+	if avg == 0 {
+		return nil
+	}
+
+	res := make([]SwapNodeAction, 0)
+
+	for len(res) < maxMoves {
+		rankings := getServerLoadRanking(servers)
+		mostLoaded := rankings[0]
+		leastLoaded := rankings[len(rankings)-1]
+
+		if mostLoaded.score <= avg*(1+threshold) {
+			break
+		}
+
+		// Pick the shard that gets the two servers closer to each other
+		target := (mostLoaded.score - leastLoaded.score) / 2
+		bestShard := int64(-1)
+		bestDistance := math.MaxFloat64
+		for _, shard := range mostLoaded.shards {
+			score := shardScores[shard]
+			if !movable[shard] || score <= 0 || score >= mostLoaded.score-leastLoaded.score ||
+				slices.Contains(leastLoaded.shards, shard) {
+				continue
+			}
+
+			p := placement[shard]
+			if !policy.canSwap(p.namespace, p.ensemble, mostLoaded.addr, leastLoaded.addr, false) {
+				continue
+			}
+
+			if d := math.Abs(score - target); d < bestDistance {
+				bestShard = shard
+				bestDistance = d
+			}
+		}
+
+		if bestShard < 0 {
+			break
+		}
+
+		a := SwapNodeAction{
+			Shard: bestShard,
+			From:  mostLoaded.addr,
+			To:    leastLoaded.addr,
+		}
+
+		mostLoaded.score -= shardScores[bestShard]
+		mostLoaded.shards = slices.DeleteFunc(mostLoaded.shards, func(s int64) bool { return s == bestShard })
+		leastLoaded.score += shardScores[bestShard]
+		leastLoaded.shards = append(leastLoaded.shards, bestShard)
+		placement.swap(a)
+
+		// A shard is moved at most once per round
+		movable[bestShard] = false
+		res = append(res, a)
+	}
+
+	return res
+}
+
+func getServerLoadRanking(servers map[model.ServerAddress]*serverLoad) []*serverLoad {
+	res := make([]*serverLoad, 0, len(servers))
+	for _, sl := range servers {
+		res = append(res, sl)
+	}
+
+	// Rank the servers from the most loaded to the least loaded
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].score != res[j].score {
+			return res[i].score > res[j].score
+		}
+
+		// Ensure predictable sorting
+		return res[i].addr.Internal < res[j].addr.Internal
+	})
+	return res
+}
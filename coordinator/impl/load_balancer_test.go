@@ -0,0 +1,191 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+func loadTestStatus(ensembles map[int64][]model.ServerAddress) *model.ClusterStatus {
+	shards := map[int64]model.ShardMetadata{}
+	for shard, ensemble := range ensembles {
+		shards[shard] = model.ShardMetadata{
+			Status:   model.ShardStatusSteadyState,
+			Ensemble: ensemble,
+		}
+	}
+
+	return &model.ClusterStatus{
+		Namespaces: map[string]model.NamespaceStatus{
+			"ns-1": {
+				ReplicationFactor: 1,
+				Shards:            shards,
+			},
+		},
+	}
+}
+
+func opsLoad(ops float64) ShardLoad {
+	return ShardLoad{WriteOpsRate: ops}
+}
+
+func TestLoadBalancer_Balanced(t *testing.T) {
+	config := &model.ClusterConfig{
+		Servers:      []model.ServerAddress{s1, s2, s3},
+		LoadBalancer: &model.LoadBalancerConfig{Interval: time.Minute},
+	}
+
+	status := loadTestStatus(map[int64][]model.ServerAddress{
+		0: {s1}, 1: {s2}, 2: {s3},
+	})
+
+	scorer, _ := getLoadScorer("")
+	actions := computeLoadBalancingMoves(config, status, map[int64]ShardLoad{
+		0: opsLoad(100), 1: opsLoad(90), 2: opsLoad(110),
+	}, scorer)
+	assert.Empty(t, actions)
+
+	// No load information available
+	actions = computeLoadBalancingMoves(config, status, map[int64]ShardLoad{}, scorer)
+	assert.Empty(t, actions)
+}
+
+func TestLoadBalancer_MoveHotShards(t *testing.T) {
+	config := &model.ClusterConfig{
+		Servers:      []model.ServerAddress{s1, s2, s3},
+		LoadBalancer: &model.LoadBalancerConfig{Interval: time.Minute},
+	}
+
+	status := loadTestStatus(map[int64][]model.ServerAddress{
+		0: {s1}, 1: {s1}, 2: {s2}, 3: {s3},
+	})
+	loads := map[int64]ShardLoad{
+		0: opsLoad(100), 1: opsLoad(100), 2: opsLoad(10), 3: opsLoad(10),
+	}
+
+	scorer, _ := getLoadScorer(model.LoadScoringPolicyOps)
+	actions := computeLoadBalancingMoves(config, status, loads, scorer)
+	assert.Equal(t, []SwapNodeAction{{Shard: 0, From: s1, To: s3}}, actions)
+
+	// Allow more moves in a single round
+	config.LoadBalancer.MaxConcurrentMoves = 5
+	actions = computeLoadBalancingMoves(config, status, loads, scorer)
+	assert.Equal(t, []SwapNodeAction{
+		{Shard: 0, From: s1, To: s3},
+		{Shard: 3, From: s3, To: s2},
+	}, actions)
+
+	// Shards that are not in steady state are not moved
+	sm := status.Namespaces["ns-1"].Shards[0]
+	sm.Status = model.ShardStatusElection
+	status.Namespaces["ns-1"].Shards[0] = sm
+	config.LoadBalancer.MaxConcurrentMoves = 1
+	actions = computeLoadBalancingMoves(config, status, loads, scorer)
+	assert.Equal(t, []SwapNodeAction{{Shard: 1, From: s1, To: s3}}, actions)
+}
+
+func TestLoadBalancer_ScoringPolicy(t *testing.T) {
+	config := &model.ClusterConfig{
+		Servers: []model.ServerAddress{s1, s2},
+		LoadBalancer: &model.LoadBalancerConfig{
+			Interval: time.Minute,
+			Policy:   model.LoadScoringPolicyDisk,
+		},
+	}
+
+	status := loadTestStatus(map[int64][]model.ServerAddress{
+		0: {s1}, 1: {s1}, 2: {s2},
+	})
+	loads := map[int64]ShardLoad{
+		0: {WriteOpsRate: 1000, DiskBytes: 10},
+		1: {WriteOpsRate: 1000, DiskBytes: 1000},
+		2: {WriteOpsRate: 10, DiskBytes: 10},
+	}
+
+	scorer, ok := getLoadScorer(config.LoadBalancer.Policy)
+	assert.True(t, ok)
+	actions := computeLoadBalancingMoves(config, status, loads, scorer)
+	assert.Equal(t, []SwapNodeAction{{Shard: 0, From: s1, To: s2}}, actions)
+
+	_, ok = getLoadScorer("unknown")
+	assert.False(t, ok)
+}
+
+func TestLoadBalancer_AntiAffinity(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			ReplicationFactor: 2,
+			AntiAffinities:    zoneAntiAffinity(model.AntiAffinityModeStrict),
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "b", s3: "c", s4: "c",
+		}),
+		LoadBalancer: &model.LoadBalancerConfig{Interval: time.Minute},
+	}
+
+	status := loadTestStatus(map[int64][]model.ServerAddress{
+		0: {s1, s3}, 1: {s1, s2}, 2: {s2, s3},
+	})
+	loads := map[int64]ShardLoad{
+		0: opsLoad(100), 1: opsLoad(100), 2: opsLoad(10),
+	}
+
+	// Shard 0 already has a replica in the zone of s4
+	scorer, _ := getLoadScorer(model.LoadScoringPolicyOps)
+	actions := computeLoadBalancingMoves(config, status, loads, scorer)
+	assert.Equal(t, []SwapNodeAction{{Shard: 1, From: s1, To: s4}}, actions)
+}
+
+func TestLoadSampler(t *testing.T) {
+	ls := loadSampler{}
+	now := time.Now()
+
+	_, ok := ls.get()
+	assert.False(t, ok)
+
+	ls.add(1, &proto.ShardLoad{WriteOps: 100, ReadOps: 50, WriteBytes: 1000, DiskBytes: 5}, now)
+	_, ok = ls.get()
+	assert.False(t, ok)
+
+	ls.add(1, &proto.ShardLoad{WriteOps: 300, ReadOps: 70, WriteBytes: 3000, DiskBytes: 6}, now.Add(10*time.Second))
+	load, ok := ls.get()
+	assert.True(t, ok)
+	assert.Equal(t, ShardLoad{
+		WriteOpsRate:   20,
+		ReadOpsRate:    2,
+		WriteBytesRate: 200,
+		DiskBytes:      6,
+	}, load)
+
+	// A new leader starts its counters from scratch. The previous load
+	// is kept until the next sample
+	ls.add(2, &proto.ShardLoad{WriteOps: 10, DiskBytes: 6}, now.Add(20*time.Second))
+	load2, ok := ls.get()
+	assert.True(t, ok)
+	assert.Equal(t, load, load2)
+
+	ls.add(2, &proto.ShardLoad{WriteOps: 110, DiskBytes: 7}, now.Add(30*time.Second))
+	load, ok = ls.get()
+	assert.True(t, ok)
+	assert.Equal(t, ShardLoad{WriteOpsRate: 10, DiskBytes: 7}, load)
+}
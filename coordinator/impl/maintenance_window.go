@@ -0,0 +1,237 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+const maxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+// cronSchedule is a parsed cron expression. Each field is the set of the
+// values it matches.
+type cronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// Like in cron, when both the day of the month and the day of the week
+	// are restricted, a day matches if either of them matches
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields in cron expression %q", expr)
+	}
+
+	s := &cronSchedule{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}
+	var err error
+	if s.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if s.daysOfMonth, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+
+	// Both 0 and 7 are Sunday
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges (`1-5`)
+// and steps (`*/15` or `0-30/10`).
+func parseCronField(field string, low int, high int) (uint64, error) {
+	var res uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step in cron field %q", field)
+			}
+		}
+
+		start, end := low, high
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, errors.Errorf("invalid value in cron field %q", field)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(to); err != nil {
+					return 0, errors.Errorf("invalid range in cron field %q", field)
+				}
+			} else if hasStep {
+				end = high
+			}
+		}
+
+		if start < low || end > high || start > end {
+			return 0, errors.Errorf("cron field %q is out of the range %d-%d", field, low, high)
+		}
+		for v := start; v <= end; v += step {
+			res |= 1 << v
+		}
+	}
+	return res, nil
+}
+
+func (s *cronSchedule) matches(t time.Time) bool {
+	return s.minutes&(1<<t.Minute()) != 0 && s.hours&(1<<t.Hour()) != 0 && s.matchesDay(t)
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	if s.months&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.daysOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.daysOfWeek&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.anyDayOfMonth:
+		return dayOfWeek
+	case s.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+// lastStart returns the most recent time matched by the schedule, at or
+// before `now`, going back to the day of `since` at most.
+func (s *cronSchedule) lastStart(now time.Time, since time.Time) (time.Time, bool) {
+	year, month, day := now.Date()
+	hour, minute := now.Hour(), now.Minute()
+	for d := day; ; d, hour, minute = d-1, 23, 59 {
+		midnight := time.Date(year, month, d, 0, 0, 0, 0, now.Location())
+		if midnight.AddDate(0, 0, 1).Before(since) {
+			return time.Time{}, false
+		}
+		if !s.matchesDay(midnight) {
+			continue
+		}
+
+		for h := hour; h >= 0; h, minute = h-1, 59 {
+			if s.hours&(1<<h) == 0 {
+				continue
+			}
+			for m := minute; m >= 0; m-- {
+				if s.minutes&(1<<m) == 0 {
+					continue
+				}
+				// The local times skipped by a DST change are normalized
+				// forward, possibly after now
+				if t := time.Date(year, month, d, h, m, 0, 0, now.Location()); !t.After(now) {
+					return t, true
+				}
+			}
+		}
+	}
+}
+
+type maintenanceWindow struct {
+	schedule *cronSchedule
+	duration time.Duration
+	location *time.Location
+}
+
+func parseMaintenanceWindow(w model.MaintenanceWindow) (*maintenanceWindow, error) {
+	if w.Duration < time.Minute || w.Duration > maxMaintenanceWindowDuration {
+		return nil, errors.Errorf("the duration must be between 1 minute and %s", maxMaintenanceWindowDuration)
+	}
+
+	schedule, err := parseCronSchedule(w.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if w.Timezone != "" {
+		if location, err = time.LoadLocation(w.Timezone); err != nil {
+			return nil, errors.Wrapf(err, "invalid timezone %s", w.Timezone)
+		}
+	}
+
+	return &maintenanceWindow{
+		schedule: schedule,
+		duration: w.Duration,
+		location: location,
+	}, nil
+}
+
+// parseMaintenanceWindows parses the maintenance windows of the cluster
+// config, once when it's applied, since loading the timezones reads the
+// system files.
+func parseMaintenanceWindows(config *model.ClusterConfig) ([]*maintenanceWindow, error) {
+	var windows []*maintenanceWindow
+	for _, mw := range config.MaintenanceWindows {
+		w, err := parseMaintenanceWindow(mw)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidClusterConfig, "maintenance window %q: %v", mw.Schedule, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// isOpen checks whether the window started in the last `duration`.
+func (w *maintenanceWindow) isOpen(now time.Time) bool {
+	now = now.In(w.location)
+	since := now.Add(-w.duration)
+	start, ok := w.schedule.lastStart(now, since)
+	return ok && start.After(since)
+}
+
+// inMaintenanceWindow tells whether the heavy operations are allowed to run.
+func inMaintenanceWindow(windows []*maintenanceWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	for _, w := range windows {
+		if w.isOpen(now) {
+			return true
+		}
+	}
+	return false
+}
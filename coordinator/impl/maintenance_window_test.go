@@ -0,0 +1,136 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestCronSchedule(t *testing.T) {
+	for _, test := range []struct {
+		expr    string
+		time    time.Time
+		matches bool
+	}{
+		{"* * * * *", time.Date(2024, 3, 4, 10, 17, 0, 0, time.UTC), true},
+		{"0 2 * * *", time.Date(2024, 3, 4, 2, 0, 0, 0, time.UTC), true},
+		{"0 2 * * *", time.Date(2024, 3, 4, 2, 1, 0, 0, time.UTC), false},
+		{"*/15 * * * *", time.Date(2024, 3, 4, 2, 45, 0, 0, time.UTC), true},
+		{"*/15 * * * *", time.Date(2024, 3, 4, 2, 50, 0, 0, time.UTC), false},
+		{"0-30/10 * * * *", time.Date(2024, 3, 4, 2, 20, 0, 0, time.UTC), true},
+		{"0-30/10 * * * *", time.Date(2024, 3, 4, 2, 40, 0, 0, time.UTC), false},
+		{"0 22 * * 1-5", time.Date(2024, 3, 8, 22, 0, 0, 0, time.UTC), true},  // Friday
+		{"0 22 * * 1-5", time.Date(2024, 3, 9, 22, 0, 0, 0, time.UTC), false}, // Saturday
+		{"0 0 * * 7", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), true},     // Sunday
+		{"0 0 1,15 * *", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 1 * 1", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true}, // Either the day of the month or of the week
+		{"0 0 * 6 *", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), false},
+	} {
+		schedule, err := parseCronSchedule(test.expr)
+		assert.NoError(t, err)
+		assert.Equal(t, test.matches, schedule.matches(test.time), "%s at %s", test.expr, test.time)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := parseCronSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1}}
+	assert.True(t, inMaintenanceWindow(nil, time.Now()))
+
+	config.MaintenanceWindows = []model.MaintenanceWindow{{
+		Schedule: "0 23 * * *",
+		Duration: 3 * time.Hour,
+	}, {
+		Schedule: "30 12 * * 6",
+		Duration: time.Hour,
+		Timezone: "America/New_York",
+	}}
+	assert.NoError(t, validateClusterConfig(config))
+	windows, err := parseMaintenanceWindows(config)
+	assert.NoError(t, err)
+
+	// The window opened on the previous day
+	assert.True(t, inMaintenanceWindow(windows, time.Date(2024, 3, 5, 1, 59, 0, 0, time.UTC)))
+	assert.False(t, inMaintenanceWindow(windows, time.Date(2024, 3, 5, 2, 0, 0, 0, time.UTC)))
+	assert.False(t, inMaintenanceWindow(windows, time.Date(2024, 3, 4, 22, 59, 59, 0, time.UTC)))
+	assert.True(t, inMaintenanceWindow(windows, time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC)))
+
+	// 12:30 in New York is 17:30 UTC
+	assert.False(t, inMaintenanceWindow(windows, time.Date(2024, 3, 9, 12, 45, 0, 0, time.UTC)))
+	assert.True(t, inMaintenanceWindow(windows, time.Date(2024, 3, 9, 17, 45, 0, 0, time.UTC)))
+
+	for _, mw := range []model.MaintenanceWindow{
+		{Schedule: "0 2 * * *"},
+		{Schedule: "0 2 * * *", Duration: 8 * 24 * time.Hour},
+		{Schedule: "0 2 * *", Duration: time.Hour},
+		{Schedule: "0 2 * * *", Duration: time.Hour, Timezone: "Mars/Olympus"},
+	} {
+		config.MaintenanceWindows = []model.MaintenanceWindow{mw}
+		assert.ErrorIs(t, validateClusterConfig(config), ErrInvalidClusterConfig)
+	}
+}
+
+func TestMaintenanceWindow_LastStart(t *testing.T) {
+	for _, test := range []struct {
+		expr     string
+		duration time.Duration
+		now      time.Time
+		open     bool
+	}{
+		// The window opened on the last day of the previous month
+		{"0 23 31 * *", 3 * time.Hour, time.Date(2024, 4, 1, 1, 0, 0, 0, time.UTC), true},
+		{"0 23 31 * *", 3 * time.Hour, time.Date(2024, 4, 1, 2, 0, 0, 0, time.UTC), false},
+		{"0 2 1 * *", 7 * 24 * time.Hour, time.Date(2024, 3, 7, 12, 0, 0, 0, time.UTC), true},
+		{"0 2 1 * *", 7 * 24 * time.Hour, time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), false},
+		{"*/20 4-6 * * 1", time.Hour, time.Date(2024, 3, 4, 7, 39, 0, 0, time.UTC), true},
+		{"*/20 4-6 * * 1", time.Hour, time.Date(2024, 3, 4, 7, 40, 0, 0, time.UTC), false},
+		{"*/20 4-6 * * 1", time.Hour, time.Date(2024, 3, 4, 3, 59, 0, 0, time.UTC), false},
+	} {
+		w, err := parseMaintenanceWindow(model.MaintenanceWindow{Schedule: test.expr, Duration: test.duration})
+		assert.NoError(t, err)
+		assert.Equal(t, test.open, w.isOpen(test.now), "%s at %s", test.expr, test.now)
+	}
+
+	// Same as checking every minute of the window
+	for _, expr := range []string{"0 23 * * *", "30 12 * * 6", "*/15 2-3 1,15 * *", "0 0 1 * 1"} {
+		w, err := parseMaintenanceWindow(model.MaintenanceWindow{
+			Schedule: expr,
+			Duration: 26 * time.Hour,
+			Timezone: "America/New_York",
+		})
+		assert.NoError(t, err)
+
+		for now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); now.Month() == time.March; now = now.Add(37 * time.Minute) {
+			local := now.In(w.location)
+			scan := false
+			for s := local.Truncate(time.Minute); local.Sub(s) < w.duration; s = s.Add(-time.Minute) {
+				if w.schedule.matches(s) {
+					scan = true
+					break
+				}
+			}
+			assert.Equal(t, scan, w.isOpen(now), "%s at %s", expr, now)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// metadataProviderEtcd keeps the cluster status in an etcd key, relying on
+// the version of the key (the number of times it was modified) for the
+// conditional updates.
+type metadataProviderEtcd struct {
+	client *clientv3.Client
+	key    string
+}
+
+// NewMetadataProviderEtcd creates a provider for the given etcd endpoints. The
+// TLS config is only needed when etcd is served over TLS, and the username
+// when the etcd authentication is enabled.
+func NewMetadataProviderEtcd(endpoints []string, key string, tlsConf *tls.Config, username string, password string) (MetadataProvider, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: rpcTimeout,
+		TLS:         tlsConf,
+		Username:    username,
+		Password:    password,
+		Logger:      zap.NewNop(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create etcd client for metadata")
+	}
+
+	return &metadataProviderEtcd{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+func (m *metadataProviderEtcd) Close() error {
+	return m.client.Close()
+}
+
+func (m *metadataProviderEtcd) Get() (cs *model.ClusterStatus, version Version, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	value, version, err := m.getKey(ctx, m.key)
+	if err != nil || version == MetadataNotExists {
+		return nil, MetadataNotExists, err
+	}
+
+	cs = &model.ClusterStatus{}
+	if err = json.Unmarshal(value, cs); err != nil {
+		return nil, MetadataNotExists, err
+	}
+
+	return cs, version, nil
+}
+
+func (m *metadataProviderEtcd) Store(cs *model.ClusterStatus, expectedVersion Version) (newVersion Version, err error) {
+	value, err := json.Marshal(cs)
+	if err != nil {
+		return MetadataNotExists, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	return m.putKey(ctx, m.key, value, expectedVersion)
+}
+
+func (m *metadataProviderEtcd) getKey(ctx context.Context, key string) (value []byte, version Version, err error) {
+	res, err := m.client.Get(ctx, key)
+	if err != nil {
+		return nil, MetadataNotExists, err
+	}
+
+	if len(res.Kvs) == 0 {
+		return nil, MetadataNotExists, nil
+	}
+
+	// The version of a key starts from 1 when it's created
+	kv := res.Kvs[0]
+	return kv.Value, Version(strconv.FormatInt(kv.Version-1, 10)), nil
+}
+
+func (m *metadataProviderEtcd) putKey(ctx context.Context, key string, value []byte, expectedVersion Version) (newVersion Version, err error) {
+	expected, err := strconv.ParseInt(string(expectedVersion), 10, 64)
+	if err != nil {
+		return MetadataNotExists, ErrMetadataBadVersion
+	}
+
+	// A key version of 0 means that the key does not exist
+	res, err := m.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Version(key), "=", expected+1)).
+		Then(clientv3.OpPut(key, string(value))).
+		Commit()
+	if err != nil {
+		return MetadataNotExists, err
+	}
+
+	if !res.Succeeded {
+		return MetadataNotExists, ErrMetadataBadVersion
+	}
+
+	return Version(strconv.FormatInt(expected+1, 10)), nil
+}
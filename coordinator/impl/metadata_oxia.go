@@ -0,0 +1,121 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/oxia"
+	"github.com/streamnative/oxia/oxia/auth"
+)
+
+// metadataProviderOxia keeps the cluster status in a record of a separate
+// Oxia cluster, relying on the record version id for the conditional updates.
+type metadataProviderOxia struct {
+	client oxia.SyncClient
+	key    string
+}
+
+// NewMetadataProviderOxia creates a provider for the given Oxia cluster. The
+// TLS config is only needed when the cluster is served over TLS, and the token
+// when its authentication is enabled.
+func NewMetadataProviderOxia(serviceAddress string, namespace string, key string, tlsConf *tls.Config, token string) (MetadataProvider, error) {
+	options := []oxia.ClientOption{oxia.WithNamespace(namespace)}
+	if tlsConf != nil {
+		options = append(options, oxia.WithTLS(tlsConf))
+	}
+	if token != "" {
+		options = append(options, oxia.WithAuthentication(auth.NewTokenAuthenticationWithToken(token, tlsConf != nil)))
+	}
+
+	client, err := oxia.NewSyncClient(serviceAddress, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create oxia client for metadata")
+	}
+
+	return &metadataProviderOxia{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+func (m *metadataProviderOxia) Close() error {
+	return m.client.Close()
+}
+
+func (m *metadataProviderOxia) Get() (cs *model.ClusterStatus, version Version, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	value, version, err := m.getKey(ctx, m.key)
+	if err != nil || version == MetadataNotExists {
+		return nil, MetadataNotExists, err
+	}
+
+	cs = &model.ClusterStatus{}
+	if err = json.Unmarshal(value, cs); err != nil {
+		return nil, MetadataNotExists, err
+	}
+
+	return cs, version, nil
+}
+
+func (m *metadataProviderOxia) Store(cs *model.ClusterStatus, expectedVersion Version) (newVersion Version, err error) {
+	value, err := json.Marshal(cs)
+	if err != nil {
+		return MetadataNotExists, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	return m.putKey(ctx, m.key, value, expectedVersion)
+}
+
+func (m *metadataProviderOxia) getKey(ctx context.Context, key string) (value []byte, version Version, err error) {
+	_, value, v, err := m.client.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, oxia.ErrKeyNotFound) {
+			return nil, MetadataNotExists, nil
+		}
+		return nil, MetadataNotExists, err
+	}
+
+	return value, Version(strconv.FormatInt(v.VersionId, 10)), nil
+}
+
+func (m *metadataProviderOxia) putKey(ctx context.Context, key string, value []byte, expectedVersion Version) (newVersion Version, err error) {
+	versionId, err := strconv.ParseInt(string(expectedVersion), 10, 64)
+	if err != nil {
+		return MetadataNotExists, ErrMetadataBadVersion
+	}
+
+	// MetadataNotExists matches the version id of a record that doesn't exist
+	_, v, err := m.client.Put(ctx, key, value, oxia.ExpectedVersionId(versionId))
+	if err != nil {
+		if errors.Is(err, oxia.ErrUnexpectedVersionId) {
+			return MetadataNotExists, ErrMetadataBadVersion
+		}
+		return MetadataNotExists, err
+	}
+
+	return Version(strconv.FormatInt(v.VersionId, 10)), nil
+}
@@ -15,13 +15,27 @@
 package impl
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/server"
 )
 
 var (
@@ -46,9 +60,79 @@ var (
 
 			return NewMetadataProviderConfigMap(_fake, "ns", "n")
 		},
+		"etcd": func(t *testing.T) MetadataProvider {
+			t.Helper()
+
+			endpoint, _ := newEtcdServer(t, false)
+			m, err := NewMetadataProviderEtcd([]string{endpoint}, "/oxia/cluster-status", nil, "", "")
+			assert.NoError(t, err)
+			return m
+		},
+		"oxia": func(t *testing.T) MetadataProvider {
+			t.Helper()
+
+			standalone, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+			assert.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, standalone.Close())
+			})
+
+			m, err := NewMetadataProviderOxia(fmt.Sprintf("localhost:%d", standalone.RpcPort()),
+				common.DefaultNamespace, "/oxia/cluster-status", nil, "")
+			assert.NoError(t, err)
+			return m
+		},
 	}
 )
 
+// newEtcdServer starts an embedded single node etcd server and returns its
+// client endpoint. With autoTLS, the clients are served over TLS, with a self
+// signed certificate that is trusted by the returned TLS config.
+func newEtcdServer(t *testing.T, autoTLS bool) (endpoint string, tlsConf *tls.Config) {
+	t.Helper()
+
+	scheme := "http"
+	if autoTLS {
+		scheme = "https"
+	}
+	clientUrl := url.URL{Scheme: scheme, Host: "localhost:0"}
+	peerUrl := url.URL{Scheme: "http", Host: "localhost:0"}
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	cfg.ListenClientUrls = []url.URL{clientUrl}
+	cfg.AdvertiseClientUrls = []url.URL{clientUrl}
+	cfg.ListenPeerUrls = []url.URL{peerUrl}
+	cfg.AdvertisePeerUrls = []url.URL{peerUrl}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	cfg.ClientAutoTLS = autoTLS
+	cfg.SelfSignedCertValidity = 1
+
+	etcd, err := embed.StartEtcd(cfg)
+	assert.NoError(t, err)
+	t.Cleanup(etcd.Close)
+
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("etcd server is not ready")
+	}
+
+	if autoTLS {
+		cert, err := os.ReadFile(etcd.Config().ClientTLSInfo.CertFile)
+		assert.NoError(t, err)
+		pool := x509.NewCertPool()
+		assert.True(t, pool.AppendCertsFromPEM(cert))
+		tlsConf = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	// The self signed certificate is only valid for the host name
+	_, port, err := net.SplitHostPort(etcd.Clients[0].Addr().String())
+	assert.NoError(t, err)
+	return net.JoinHostPort("localhost", port), tlsConf
+}
+
 func TestMetadataProvider(t *testing.T) {
 	for name, provider := range metadataProviders {
 		t.Run(name, func(t *testing.T) {
@@ -82,3 +166,73 @@ func TestMetadataProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestMetadataProvider_StaleVersion(t *testing.T) {
+	// The fake k8s client doesn't update the resource version on patches
+	for _, name := range []string{"memory", "file", "etcd", "oxia"} {
+		t.Run(name, func(t *testing.T) {
+			m := metadataProviders[name](t)
+
+			version, err := m.Store(&model.ClusterStatus{}, MetadataNotExists)
+			assert.NoError(t, err)
+
+			newVersion, err := m.Store(&model.ClusterStatus{}, version)
+			assert.NoError(t, err)
+			assert.NotEqual(t, version, newVersion)
+
+			_, err = m.Store(&model.ClusterStatus{}, version)
+			assert.ErrorIs(t, err, ErrMetadataBadVersion)
+
+			_, err = m.Store(&model.ClusterStatus{}, MetadataNotExists)
+			assert.ErrorIs(t, err, ErrMetadataBadVersion)
+
+			assert.NoError(t, m.Close())
+		})
+	}
+}
+
+func TestMetadataProviderEtcd_TLSAndAuth(t *testing.T) {
+	endpoint, tlsConf := newEtcdServer(t, true)
+
+	// Enable the authentication, with a root user
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints: []string{endpoint},
+		TLS:       tlsConf,
+		Logger:    zap.NewNop(),
+	})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = client.RoleAdd(ctx, "root")
+	assert.NoError(t, err)
+	_, err = client.UserAdd(ctx, "root", "secret")
+	assert.NoError(t, err)
+	_, err = client.UserGrantRole(ctx, "root", "root")
+	assert.NoError(t, err)
+	_, err = client.AuthEnable(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, client.Close())
+
+	m, err := NewMetadataProviderEtcd([]string{endpoint}, "/oxia/cluster-status", tlsConf, "root", "secret")
+	assert.NoError(t, err)
+	version := MetadataNotExists
+	for i := 0; i < 5; i++ {
+		version, err = m.Store(&model.ClusterStatus{}, version)
+		assert.NoError(t, err)
+
+		_, v, err := m.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, version, v)
+	}
+	assert.NoError(t, m.Close())
+
+	// The client authenticates as soon as it's created
+	_, err = NewMetadataProviderEtcd([]string{endpoint}, "/oxia/cluster-status", tlsConf, "root", "wrong")
+	assert.ErrorIs(t, err, rpctypes.ErrAuthFailed)
+
+	m, err = NewMetadataProviderEtcd([]string{endpoint}, "/oxia/cluster-status", tlsConf, "", "")
+	assert.NoError(t, err)
+	_, _, err = m.Get()
+	assert.ErrorIs(t, err, rpctypes.ErrUserEmpty)
+	assert.NoError(t, m.Close())
+}
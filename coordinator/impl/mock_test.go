@@ -181,6 +181,13 @@ func (m *mockPerNodeChannels) AddFollowerResponse(err error) {
 	}{&proto.AddFollowerResponse{}, err}
 }
 
+func (m *mockPerNodeChannels) DeleteShardResponse(err error) {
+	m.deleteShardResponses <- struct {
+		*proto.DeleteShardResponse
+		error
+	}{&proto.DeleteShardResponse{}, err}
+}
+
 func newMockPerNodeChannels() *mockPerNodeChannels {
 	return &mockPerNodeChannels{
 		newTermRequests: make(chan *proto.NewTermRequest, 100),
@@ -198,6 +205,11 @@ func newMockPerNodeChannels() *mockPerNodeChannels {
 			*proto.GetStatusResponse
 			error
 		}, 100),
+		deleteShardRequests: make(chan *proto.DeleteShardRequest, 100),
+		deleteShardResponses: make(chan struct {
+			*proto.DeleteShardResponse
+			error
+		}, 100),
 		addFollowerRequests: make(chan *proto.AddFollowerRequest, 100),
 		addFollowerResponses: make(chan struct {
 			*proto.AddFollowerResponse
@@ -379,6 +391,22 @@ func (r *mockRpcProvider) AddFollower(ctx context.Context, node model.ServerAddr
 	}
 }
 
+func (*mockRpcProvider) ListShardSessions(context.Context, model.ServerAddress, *proto.ListShardSessionsRequest) (*proto.ListShardSessionsResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*mockRpcProvider) CloseSession(context.Context, model.ServerAddress, *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*mockRpcProvider) ExportRecords(context.Context, model.ServerAddress, *proto.ExportRecordsRequest) (proto.OxiaCoordination_ExportRecordsClient, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*mockRpcProvider) ImportRecords(context.Context, model.ServerAddress, *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (r *mockRpcProvider) GetHealthClient(node model.ServerAddress) (grpc_health_v1.HealthClient, error) {
 	return r.GetNode(node).healthClient, nil
 }
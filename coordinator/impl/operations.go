@@ -0,0 +1,177 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+type OperationType string
+
+const (
+	OperationReplicaMove       OperationType = "replica-move"
+	OperationEnsembleChange    OperationType = "ensemble-change"
+	OperationLeaderTransfer    OperationType = "leader-transfer"
+	OperationLeaderElection    OperationType = "leader-election"
+	OperationShardDeletion     OperationType = "shard-deletion"
+	OperationNamespaceDeletion OperationType = "namespace-deletion"
+	OperationDecommission      OperationType = "server-decommission"
+	OperationDrain             OperationType = "server-drain"
+	OperationFreeze            OperationType = "cluster-freeze"
+	OperationRebalancePlan     OperationType = "rebalance-plan"
+)
+
+// Operation is a change to the cluster that the coordinator is carrying out.
+type Operation struct {
+	Type        OperationType
+	Namespace   string
+	Shard       *int64
+	Server      *model.ServerAddress
+	Description string
+
+	// StartTime is only known for the operations started by this coordinator
+	StartTime time.Time
+}
+
+// ServerInfo describes a server of the cluster, as seen by the coordinator.
+type ServerInfo struct {
+	Address  model.ServerAddress
+	Running  bool
+	Labels   map[string]string
+	Status   model.ServerStatus
+	Replicas int
+	Leaders  int
+}
+
+// operationsTracker keeps the long-running operations that are carried out
+// outside the coordinator lock, like the ensemble changes.
+type operationsTracker struct {
+	sync.Mutex
+	nextId     int64
+	operations map[int64]Operation
+}
+
+func newOperationsTracker() *operationsTracker {
+	return &operationsTracker{
+		operations: map[int64]Operation{},
+	}
+}
+
+// start records the operation and returns the function to call once it's done.
+func (t *operationsTracker) start(op Operation) func() {
+	t.Lock()
+	defer t.Unlock()
+
+	op.StartTime = time.Now()
+	id := t.nextId
+	t.nextId++
+	t.operations[id] = op
+
+	return func() {
+		t.Lock()
+		defer t.Unlock()
+		delete(t.operations, id)
+	}
+}
+
+func (t *operationsTracker) list() []Operation {
+	t.Lock()
+	defer t.Unlock()
+
+	ids := make([]int64, 0, len(t.operations))
+	for id := range t.operations {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	res := make([]Operation, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, t.operations[id])
+	}
+	return res
+}
+
+// Derive the operations that are recorded in the cluster status.
+func statusOperations(config *model.ClusterConfig, status *model.ClusterStatus) []Operation {
+	var res []Operation
+
+	if status.IsFrozen() {
+		description := "no replica or leader is moved by the coordinator"
+		if status.Freeze.Reason != "" {
+			description = status.Freeze.Reason
+		}
+		res = append(res, Operation{
+			Type:        OperationFreeze,
+			Description: description,
+			StartTime:   status.Freeze.Since,
+		})
+	}
+
+	for _, ns := range sortedNamespaces(status) {
+		nss := status.Namespaces[ns]
+		if nss.IsSoftDeleted() && !nss.IsDeleting() {
+			res = append(res, Operation{
+				Type:        OperationNamespaceDeletion,
+				Namespace:   ns,
+				Description: fmt.Sprintf("soft-deleted at %s, waiting for the grace period", nss.DeletedAt.Format(time.RFC3339)),
+			})
+		}
+
+		for _, shard := range sortedShards(nss) {
+			sm := nss.Shards[shard]
+			var opType OperationType
+			switch sm.Status {
+			case model.ShardStatusElection:
+				opType = OperationLeaderElection
+			case model.ShardStatusDeleting:
+				opType = OperationShardDeletion
+			default:
+				continue
+			}
+
+			res = append(res, Operation{
+				Type:        opType,
+				Namespace:   ns,
+				Shard:       &shard,
+				Description: fmt.Sprintf("term %d", sm.Term),
+			})
+		}
+	}
+
+	for _, sa := range config.Servers {
+		ss := status.Servers[sa.Internal]
+		if ss.Decommission == model.DecommissionStateInProgress {
+			res = append(res, Operation{
+				Type:        OperationDecommission,
+				Server:      &sa,
+				Description: fmt.Sprintf("%d shards remaining", shardsOnServer(status, sa)),
+			})
+		}
+		if ss.Draining {
+			res = append(res, Operation{
+				Type:        OperationDrain,
+				Server:      &sa,
+				Description: fmt.Sprintf("%d shards still led by the server", leadersOnServer(status, sa)),
+			})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestOperationsTracker(t *testing.T) {
+	tracker := newOperationsTracker()
+	assert.Empty(t, tracker.list())
+
+	shard := int64(1)
+	done1 := tracker.start(Operation{Type: OperationReplicaMove, Shard: &shard, Description: "op-1"})
+	done2 := tracker.start(Operation{Type: OperationLeaderTransfer, Shard: &shard, Description: "op-2"})
+
+	ops := tracker.list()
+	assert.Len(t, ops, 2)
+	assert.Equal(t, "op-1", ops[0].Description)
+	assert.Equal(t, "op-2", ops[1].Description)
+	assert.False(t, ops[0].StartTime.IsZero())
+
+	done1()
+	ops = tracker.list()
+	assert.Len(t, ops, 1)
+	assert.Equal(t, "op-2", ops[0].Description)
+
+	done2()
+	assert.Empty(t, tracker.list())
+}
+
+func TestStatusOperations(t *testing.T) {
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3}}
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1, s2, s3},
+		1: {s1, s2, s3},
+	}, &s1)
+	assert.Empty(t, statusOperations(config, status))
+
+	deletedAt := time.UnixMilli(0).UTC()
+	status.Namespaces["ns-1"].Shards[1] = model.ShardMetadata{Status: model.ShardStatusElection, Term: 4}
+	status.Namespaces["ns-2"] = model.NamespaceStatus{
+		ReplicationFactor: 1,
+		DeletedAt:         &deletedAt,
+		Shards:            map[int64]model.ShardMetadata{2: {Status: model.ShardStatusSteadyState}},
+	}
+	status.Servers = map[string]model.ServerStatus{
+		s2.Internal: {Decommission: model.DecommissionStateInProgress},
+		s3.Internal: {Draining: true},
+	}
+
+	shard1 := int64(1)
+	assert.Equal(t, []Operation{
+		{Type: OperationLeaderElection, Namespace: "ns-1", Shard: &shard1, Description: "term 4"},
+		{Type: OperationNamespaceDeletion, Namespace: "ns-2",
+			Description: "soft-deleted at 1970-01-01T00:00:00Z, waiting for the grace period"},
+		{Type: OperationDecommission, Server: &s2, Description: "1 shards remaining"},
+		{Type: OperationDrain, Server: &s3, Description: "0 shards still led by the server"},
+	}, statusOperations(config, status))
+
+	// Once the shards of the namespace are being deleted, they are
+	// reported instead of the namespace
+	status.Namespaces["ns-2"].Shards[2] = model.ShardMetadata{Status: model.ShardStatusDeleting, Term: 1}
+	shard2 := int64(2)
+	ops := statusOperations(config, status)
+	assert.Equal(t, Operation{Type: OperationShardDeletion, Namespace: "ns-2", Shard: &shard2, Description: "term 1"}, ops[1])
+
+	// The maintenance freeze comes first
+	status.Freeze = &model.FreezeStatus{Since: deletedAt}
+	ops = statusOperations(config, status)
+	assert.Equal(t, Operation{Type: OperationFreeze, Description: "no replica or leader is moved by the coordinator",
+		StartTime: deletedAt}, ops[0])
+}
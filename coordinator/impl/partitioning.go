@@ -0,0 +1,169 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common/compare"
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+func validatePartitioning(nc model.NamespaceConfig) error {
+	switch nc.Partitioning {
+	case "", model.PartitioningXxHash3, model.PartitioningFnv1a:
+		if len(nc.RangeSplitKeys) > 0 {
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the range split keys are only supported with the range partitioning")
+		}
+		return nil
+
+	case model.PartitioningRange:
+		if int(nc.InitialShardCount) != len(nc.RangeSplitKeys)+1 {
+			return errors.Wrap(ErrInvalidNamespaceConfig, "the initial shard count must be one more than the number of range split keys")
+		}
+		for i, key := range nc.RangeSplitKeys {
+			if key == "" {
+				return errors.Wrap(ErrInvalidNamespaceConfig, "the range split keys cannot be empty")
+			}
+			if i > 0 && compare.CompareWithSlash([]byte(nc.RangeSplitKeys[i-1]), []byte(key)) >= 0 {
+				return errors.Wrap(ErrInvalidNamespaceConfig, "the range split keys must be sorted and unique")
+			}
+		}
+		return nil
+
+	default:
+		return errors.Wrapf(ErrInvalidNamespaceConfig, "unknown partitioning scheme %s", nc.Partitioning)
+	}
+}
+
+// Generate the key ranges of the shards of a namespace with the range
+// partitioning. The first and the last ranges are unbounded.
+func generateKeyRanges(splitKeys []string) []model.KeyRange {
+	res := make([]model.KeyRange, 0, len(splitKeys)+1)
+	minKey := ""
+	for _, key := range splitKeys {
+		res = append(res, model.KeyRange{Min: minKey, Max: key})
+		minKey = key
+	}
+	return append(res, model.KeyRange{Min: minKey})
+}
+
+func shardKeyRouter(scheme model.PartitioningScheme) proto.ShardKeyRouter {
+	switch scheme {
+	case model.PartitioningFnv1a:
+		return proto.ShardKeyRouter_FNV1A_32
+	case model.PartitioningRange:
+		return proto.ShardKeyRouter_KEY_RANGE
+	default:
+		return proto.ShardKeyRouter_XXHASH3
+	}
+}
+
+func toProtoNamespacePolicies(p *model.NamespacePolicies) *proto.NamespacePolicies {
+	if p == nil {
+		return nil
+	}
+
+	var quotas map[string]*proto.PrincipalQuota
+	for principal, q := range p.PrincipalQuotas {
+		if quotas == nil {
+			quotas = map[string]*proto.PrincipalQuota{}
+		}
+		quotas[principal] = &proto.PrincipalQuota{
+			OpsPerSecond:        q.OpsPerSecond,
+			WriteBytesPerSecond: q.WriteBytesPerSecond,
+			MaxStorageBytes:     uint64(q.MaxStorageBytes),
+		}
+	}
+
+	return &proto.NamespacePolicies{
+		DefaultTtlMs:                 uint64(p.DefaultTTL.Milliseconds()),
+		SlidingTtl:                   p.SlidingTTL,
+		MaxValueSize:                 uint64(p.MaxValueSize),
+		MaxWritesPerSecond:           p.MaxWritesPerSecond,
+		MaxReadsPerSecond:            p.MaxReadsPerSecond,
+		NotificationsRetentionMs:     uint64(p.NotificationsRetention.Milliseconds()),
+		MaxClientOpsPerSecond:        p.MaxClientOpsPerSecond,
+		MaxClientWriteBytesPerSecond: p.MaxClientWriteBytesPerSecond,
+		PrincipalQuotas:              quotas,
+	}
+}
+
+func toShardAssignment(shard int64, sm model.ShardMetadata) *proto.ShardAssignment {
+	var leader string
+	if sm.Leader != nil {
+		leader = sm.Leader.Public
+	}
+
+	assignment := &proto.ShardAssignment{
+		ShardId: shard,
+		Leader:  leader,
+	}
+	if sm.KeyRange != nil {
+		assignment.ShardBoundaries = &proto.ShardAssignment_KeyRange{
+			KeyRange: &proto.KeyRange{
+				MinKeyInclusive: sm.KeyRange.Min,
+				MaxKeyExclusive: sm.KeyRange.Max,
+			},
+		}
+	} else {
+		assignment.ShardBoundaries = &proto.ShardAssignment_Int32HashRange{
+			Int32HashRange: &proto.Int32HashRange{
+				MinHashInclusive: sm.Int32HashRange.Min,
+				MaxHashInclusive: sm.Int32HashRange.Max,
+			},
+		}
+	}
+	return assignment
+}
+
+// Merge the range of the shard `right` into the adjacent shard `left`.
+func mergeShardRanges(left model.ShardMetadata, right model.ShardMetadata) (model.ShardMetadata, error) {
+	if left.KeyRange != nil || right.KeyRange != nil {
+		if left.KeyRange == nil || right.KeyRange == nil ||
+			left.KeyRange.Max == "" || left.KeyRange.Max != right.KeyRange.Min {
+			return left, ErrShardsNotAdjacent
+		}
+
+		left.KeyRange = &model.KeyRange{Min: left.KeyRange.Min, Max: right.KeyRange.Max}
+		return left, nil
+	}
+
+	// The range of the last shard must not wrap around to the first one
+	if left.Int32HashRange.Max == math.MaxUint32 || left.Int32HashRange.Max+1 != right.Int32HashRange.Min {
+		return left, ErrShardsNotAdjacent
+	}
+	left.Int32HashRange.Max = right.Int32HashRange.Max
+	return left, nil
+}
+
+// Extend the range of the shard `into` over the range of the adjacent shard
+// `shard`, which can be either before or after it.
+func extendShardRange(into model.ShardMetadata, shard model.ShardMetadata) (model.ShardMetadata, error) {
+	if merged, err := mergeShardRanges(into, shard); err == nil {
+		return merged, nil
+	}
+
+	merged, err := mergeShardRanges(shard, into)
+	if err != nil {
+		return into, err
+	}
+	into.Int32HashRange = merged.Int32HashRange
+	into.KeyRange = merged.KeyRange
+	return into, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+func TestValidatePartitioning(t *testing.T) {
+	for _, nc := range []model.NamespaceConfig{
+		{InitialShardCount: 2},
+		{InitialShardCount: 2, Partitioning: model.PartitioningFnv1a},
+		{InitialShardCount: 1, Partitioning: model.PartitioningRange},
+		{InitialShardCount: 3, Partitioning: model.PartitioningRange, RangeSplitKeys: []string{"g", "p"}},
+		{InitialShardCount: 3, Partitioning: model.PartitioningRange, RangeSplitKeys: []string{"z", "a/b"}},
+	} {
+		assert.NoError(t, validatePartitioning(nc), "%+v", nc)
+	}
+
+	for _, nc := range []model.NamespaceConfig{
+		{InitialShardCount: 2, Partitioning: "foo"},
+		{InitialShardCount: 2, RangeSplitKeys: []string{"g"}},
+		{InitialShardCount: 2, Partitioning: model.PartitioningRange},
+		{InitialShardCount: 2, Partitioning: model.PartitioningRange, RangeSplitKeys: []string{""}},
+		{InitialShardCount: 3, Partitioning: model.PartitioningRange, RangeSplitKeys: []string{"p", "g"}},
+		{InitialShardCount: 3, Partitioning: model.PartitioningRange, RangeSplitKeys: []string{"g", "g"}},
+	} {
+		assert.ErrorIs(t, validatePartitioning(nc), ErrInvalidNamespaceConfig, "%+v", nc)
+	}
+}
+
+func TestPartitioning_RangeShards(t *testing.T) {
+	newStatus, _, _, err := applyClusterChanges(&model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 3,
+			ReplicationFactor: 1,
+			Partitioning:      model.PartitioningRange,
+			RangeSplitKeys:    []string{"g", "p"},
+		}},
+		Servers: []model.ServerAddress{s1},
+	}, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	nss := newStatus.Namespaces["ns-1"]
+	assert.Equal(t, model.PartitioningRange, nss.Partitioning)
+	assert.Equal(t, &model.KeyRange{Min: "", Max: "g"}, nss.Shards[0].KeyRange)
+	assert.Equal(t, &model.KeyRange{Min: "g", Max: "p"}, nss.Shards[1].KeyRange)
+	assert.Equal(t, &model.KeyRange{Min: "p", Max: ""}, nss.Shards[2].KeyRange)
+
+	assert.Equal(t, proto.ShardKeyRouter_KEY_RANGE, shardKeyRouter(nss.Partitioning))
+	assert.Equal(t, &proto.ShardAssignment_KeyRange{
+		KeyRange: &proto.KeyRange{MinKeyInclusive: "g", MaxKeyExclusive: "p"},
+	}, toShardAssignment(1, nss.Shards[1]).ShardBoundaries)
+
+	// The hash partitioned namespaces are not changed
+	assert.Equal(t, proto.ShardKeyRouter_XXHASH3, shardKeyRouter(""))
+	assert.Equal(t, proto.ShardKeyRouter_FNV1A_32, shardKeyRouter(model.PartitioningFnv1a))
+}
+
+func TestPartitioning_MergeShardRanges(t *testing.T) {
+	left := model.ShardMetadata{KeyRange: &model.KeyRange{Min: "", Max: "g"}}
+	right := model.ShardMetadata{KeyRange: &model.KeyRange{Min: "g", Max: "p"}}
+	last := model.ShardMetadata{KeyRange: &model.KeyRange{Min: "p", Max: ""}}
+
+	merged, err := mergeShardRanges(left, right)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.KeyRange{Min: "", Max: "p"}, merged.KeyRange)
+	// The original metadata is not modified
+	assert.Equal(t, "g", left.KeyRange.Max)
+
+	_, err = mergeShardRanges(left, last)
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+	_, err = mergeShardRanges(last, left)
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+	_, err = mergeShardRanges(left, model.ShardMetadata{Int32HashRange: model.Int32HashRange{Min: 10}})
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+
+	merged, err = mergeShardRanges(
+		model.ShardMetadata{Int32HashRange: model.Int32HashRange{Min: 0, Max: 9}},
+		model.ShardMetadata{Int32HashRange: model.Int32HashRange{Min: 10, Max: 20}})
+	assert.NoError(t, err)
+	assert.Equal(t, model.Int32HashRange{Min: 0, Max: 20}, merged.Int32HashRange)
+}
+
+func TestPartitioning_ExtendShardRange(t *testing.T) {
+	first := model.ShardMetadata{Term: 1, Int32HashRange: model.Int32HashRange{Min: 0, Max: 9}}
+	second := model.ShardMetadata{Term: 2, Int32HashRange: model.Int32HashRange{Min: 10, Max: 20}}
+	third := model.ShardMetadata{Term: 3, Int32HashRange: model.Int32HashRange{Min: 21, Max: 30}}
+
+	extended, err := extendShardRange(first, second)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, extended.Term)
+	assert.Equal(t, model.Int32HashRange{Min: 0, Max: 20}, extended.Int32HashRange)
+
+	// The shard can also be extended over the shard before it
+	extended, err = extendShardRange(second, first)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, extended.Term)
+	assert.Equal(t, model.Int32HashRange{Min: 0, Max: 20}, extended.Int32HashRange)
+
+	_, err = extendShardRange(first, third)
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+	_, err = extendShardRange(third, first)
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+
+	// The last shard is not adjacent to the first one
+	last := model.ShardMetadata{Int32HashRange: model.Int32HashRange{Min: 31, Max: math.MaxUint32}}
+	_, err = extendShardRange(first, last)
+	assert.ErrorIs(t, err, ErrShardsNotAdjacent)
+}
+
+func TestToProtoNamespacePolicies(t *testing.T) {
+	assert.Nil(t, toProtoNamespacePolicies(nil))
+
+	p := toProtoNamespacePolicies(&model.NamespacePolicies{
+		DefaultTTL:             1 * time.Hour,
+		MaxValueSize:           1024,
+		MaxWritesPerSecond:     100,
+		NotificationsRetention: 5 * time.Minute,
+		MaxClientOpsPerSecond:  10,
+		PrincipalQuotas: map[string]model.PrincipalQuota{
+			model.AllPrincipals: {OpsPerSecond: 50},
+			"user-1":            {WriteBytesPerSecond: 1024, MaxStorageBytes: 1 << 20},
+		},
+	})
+	assert.EqualValues(t, 3_600_000, p.DefaultTtlMs)
+	assert.EqualValues(t, 1024, p.MaxValueSize)
+	assert.EqualValues(t, 100, p.MaxWritesPerSecond)
+	assert.Zero(t, p.MaxReadsPerSecond)
+	assert.EqualValues(t, 300_000, p.NotificationsRetentionMs)
+	assert.EqualValues(t, 10, p.MaxClientOpsPerSecond)
+	assert.Zero(t, p.MaxClientWriteBytesPerSecond)
+	assert.EqualValues(t, 50, p.PrincipalQuotas["*"].OpsPerSecond)
+	assert.EqualValues(t, 1024, p.PrincipalQuotas["user-1"].WriteBytesPerSecond)
+	assert.EqualValues(t, 1<<20, p.PrincipalQuotas["user-1"].MaxStorageBytes)
+}
@@ -0,0 +1,263 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+var (
+	ErrAntiAffinityNotSatisfied = errors.New("not enough servers to satisfy the anti-affinity rules")
+	ErrPlacementNotSatisfied    = errors.New("not enough servers to satisfy the placement constraints")
+)
+
+// placementPolicy decides where the replicas of a shard can be placed, so
+// that they are spread across the failure domains of the cluster and
+// according to the anti-affinity rules of the namespace, and only on the servers that satisfy
+// its placement constraints and in its pool. The choice among the servers that satisfy the
+// rules is left to the assignment strategy of the cluster.
+type placementPolicy struct {
+	serverMetadata map[string]model.ServerMetadata
+	antiAffinities map[string][]model.AntiAffinity
+	constraints    map[string][]model.PlacementConstraint
+	pools          map[string]string
+	failureDomains []string
+	strategy       AssignmentStrategy
+	scorer         LoadScorer
+
+	// Whether any of the servers is not in the default pool
+	hasPools bool
+
+	// The load score of each shard, when known
+	shardScores map[int64]float64
+}
+
+func newPlacementPolicy(config *model.ClusterConfig) *placementPolicy {
+	p := &placementPolicy{
+		serverMetadata: config.ServerMetadata,
+		antiAffinities: map[string][]model.AntiAffinity{},
+		constraints:    map[string][]model.PlacementConstraint{},
+		pools:          map[string]string{},
+		failureDomains: config.FailureDomains,
+		strategy:       &uniformStrategy{},
+		shardScores:    map[int64]float64{},
+	}
+
+	// The cluster config is validated before being applied
+	if factory, ok := getAssignmentStrategy(config); ok {
+		p.strategy = factory(config)
+	}
+
+	var policy model.LoadScoringPolicy
+	if config.LoadBalancer != nil {
+		policy = config.LoadBalancer.Policy
+	}
+	p.scorer, _ = getLoadScorer(policy)
+
+	for _, nc := range config.Namespaces {
+		if len(nc.AntiAffinities) > 0 {
+			p.antiAffinities[nc.Name] = nc.AntiAffinities
+		}
+		if len(nc.PlacementConstraints) > 0 {
+			p.constraints[nc.Name] = nc.PlacementConstraints
+		}
+		if pool := namespacePool(config, nc); pool != "" {
+			p.pools[nc.Name] = pool
+		}
+	}
+	for _, sm := range config.ServerMetadata {
+		if sm.Pool != "" {
+			p.hasPools = true
+		}
+	}
+	return p
+}
+
+// poolOf returns the pool of the server, the default pool being "".
+func (p *placementPolicy) poolOf(server model.ServerAddress) string {
+	return p.serverMetadata[server.Internal].Pool
+}
+
+// sortedPools returns the distinct pools of the servers.
+func (p *placementPolicy) sortedPools(servers []model.ServerAddress) []string {
+	pools := common.NewSet[string]()
+	for _, sa := range servers {
+		pools.Add(p.poolOf(sa))
+	}
+	return pools.GetSorted()
+}
+
+func serversInPool(config *model.ClusterConfig, pool string) []model.ServerAddress {
+	res := make([]model.ServerAddress, 0, len(config.Servers))
+	for _, sa := range config.Servers {
+		if config.ServerMetadata[sa.Internal].Pool == pool {
+			res = append(res, sa)
+		}
+	}
+	return res
+}
+
+// isRestricted tells whether the replicas of the namespace cannot be
+// placed on any of the servers.
+func (p *placementPolicy) isRestricted(namespace string) bool {
+	return p.hasPools || len(p.constraints[namespace]) > 0
+}
+
+// withLoads makes the load of the shards available to the assignment strategy.
+func (p *placementPolicy) withLoads(loads map[int64]ShardLoad) *placementPolicy {
+	if p.scorer == nil {
+		return p
+	}
+	for shard, load := range loads {
+		p.shardScores[shard] = p.scorer(load)
+	}
+	return p
+}
+
+// selectEnsemble picks the servers for a new shard, going through the
+// servers in round-robin order, starting at startIdx.
+func (p *placementPolicy) selectEnsemble(namespace string, servers []model.ServerAddress,
+	startIdx uint32, replicationFactor uint32) ([]model.ServerAddress, error) {
+	if err := p.checkEligibleServers(namespace, servers, replicationFactor); err != nil {
+		return nil, err
+	}
+	if len(p.antiAffinities[namespace]) == 0 && len(p.failureDomains) == 0 && !p.isRestricted(namespace) {
+		return getServers(servers, startIdx, replicationFactor), nil
+	}
+
+	n := len(servers)
+	res := make([]model.ServerAddress, 0, replicationFactor)
+
+	// Try first to honor all the rules, and then fall back to
+	// honor only the strict ones
+	for _, strictOnly := range []bool{false, true} {
+		for i := 0; i < n && len(res) < int(replicationFactor); i++ {
+			candidate := servers[(int(startIdx)+i)%n]
+			if listContains(res, candidate) || !p.canPlace(namespace, res, candidate, strictOnly) {
+				continue
+			}
+
+			res = append(res, candidate)
+		}
+	}
+
+	if len(res) < int(replicationFactor) {
+		return nil, errors.Wrapf(ErrAntiAffinityNotSatisfied, "namespace %s", namespace)
+	}
+	return res, nil
+}
+
+// canSwap checks whether the replica on the server `from` can be moved
+// to the server `to`, without violating the anti-affinity rules.
+func (p *placementPolicy) canSwap(namespace string, ensemble []model.ServerAddress,
+	from model.ServerAddress, to model.ServerAddress, strictOnly bool) bool {
+	others := make([]model.ServerAddress, 0, len(ensemble))
+	for _, sa := range ensemble {
+		if sa != from {
+			others = append(others, sa)
+		}
+	}
+
+	return p.canPlace(namespace, others, to, strictOnly)
+}
+
+// isEligible checks that the server is in the pool of the namespace and that
+// it satisfies all the placement constraints of the namespace.
+func (p *placementPolicy) isEligible(namespace string, server model.ServerAddress) bool {
+	if p.poolOf(server) != p.pools[namespace] {
+		return false
+	}
+
+	labels := p.serverMetadata[server.Internal].Labels
+	for _, c := range p.constraints[namespace] {
+		if !c.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEligibleServers fails when there are fewer servers in the pool and
+// satisfying the placement constraints of the namespace than the replication
+// factor.
+func (p *placementPolicy) checkEligibleServers(namespace string, servers []model.ServerAddress,
+	replicationFactor uint32) error {
+	if !p.isRestricted(namespace) {
+		return nil
+	}
+
+	eligible := 0
+	for _, sa := range servers {
+		if p.isEligible(namespace, sa) {
+			eligible++
+		}
+	}
+	if eligible < int(replicationFactor) {
+		return errors.Wrapf(ErrPlacementNotSatisfied, "namespace %s", namespace)
+	}
+	return nil
+}
+
+// canPlace checks that the candidate server satisfies the placement
+// constraints, and that it doesn't share the value of any of the failure
+// domains or of the anti-affinity labels with the servers in the ensemble.
+// With strictOnly, the relaxed rules are ignored.
+func (p *placementPolicy) canPlace(namespace string, ensemble []model.ServerAddress,
+	candidate model.ServerAddress, strictOnly bool) bool {
+	if !p.isEligible(namespace, candidate) {
+		return false
+	}
+
+	// The failure domains are always strict
+	for _, label := range p.failureDomains {
+		if p.sharesLabel(ensemble, candidate, label) {
+			return false
+		}
+	}
+
+	for _, aa := range p.antiAffinities[namespace] {
+		if strictOnly && !aa.IsStrict() {
+			continue
+		}
+
+		for _, label := range aa.Labels {
+			if p.sharesLabel(ensemble, candidate, label) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// sharesLabel checks whether any server of the ensemble has the same value
+// as the candidate for the label. The servers without the label don't share
+// it with any other server.
+func (p *placementPolicy) sharesLabel(ensemble []model.ServerAddress, candidate model.ServerAddress, label string) bool {
+	value, ok := p.serverMetadata[candidate.Internal].Labels[label]
+	if !ok {
+		return false
+	}
+
+	for _, sa := range ensemble {
+		if v, ok := p.serverMetadata[sa.Internal].Labels[label]; ok && v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func zones(zonesPerServer map[model.ServerAddress]string) map[string]model.ServerMetadata {
+	res := map[string]model.ServerMetadata{}
+	for sa, zone := range zonesPerServer {
+		res[sa.Internal] = model.ServerMetadata{Labels: map[string]string{"zone": zone}}
+	}
+	return res
+}
+
+func zoneAntiAffinity(mode model.AntiAffinityMode) []model.AntiAffinity {
+	return []model.AntiAffinity{{
+		Labels: []string{"zone"},
+		Mode:   mode,
+	}}
+}
+
+func TestPlacement_SelectEnsemble(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			ReplicationFactor: 3,
+			AntiAffinities:    zoneAntiAffinity(model.AntiAffinityModeStrict),
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4, s5, s6},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "a", s3: "b", s4: "b", s5: "c", s6: "c",
+		}),
+	}
+
+	policy := newPlacementPolicy(config)
+
+	ensemble, err := policy.selectEnsemble("ns-1", config.Servers, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ServerAddress{s1, s3, s5}, ensemble)
+
+	ensemble, err = policy.selectEnsemble("ns-1", config.Servers, 3, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ServerAddress{s4, s5, s1}, ensemble)
+
+	// Namespaces without rules keep the plain round-robin placement
+	ensemble, err = policy.selectEnsemble("ns-2", config.Servers, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ServerAddress{s1, s2, s3}, ensemble)
+}
+
+func TestPlacement_StrictNotSatisfied(t *testing.T) {
+	_, _, _, err := applyClusterChanges(&model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 1,
+			ReplicationFactor: 3,
+			AntiAffinities:    zoneAntiAffinity(model.AntiAffinityModeStrict),
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "a", s3: "b", s4: "b",
+		}),
+	}, model.NewClusterStatus())
+
+	assert.ErrorIs(t, err, ErrAntiAffinityNotSatisfied)
+}
+
+func TestPlacement_Relaxed(t *testing.T) {
+	newStatus, _, _, err := applyClusterChanges(&model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 1,
+			ReplicationFactor: 3,
+			AntiAffinities:    zoneAntiAffinity(model.AntiAffinityModeRelaxed),
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "a", s3: "b", s4: "b",
+		}),
+	}, model.NewClusterStatus())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ServerAddress{s1, s3, s2}, newStatus.Namespaces["ns-1"].Shards[0].Ensemble)
+}
+
+func TestPlacement_RebalanceRemovedServer(t *testing.T) {
+	cs := &model.ClusterStatus{
+		Namespaces: map[string]model.NamespaceStatus{
+			"ns-1": {
+				ReplicationFactor: 3,
+				Shards: map[int64]model.ShardMetadata{
+					0: {Ensemble: []model.ServerAddress{s1, s2, s3}},
+				},
+			},
+		},
+	}
+
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 1,
+			ReplicationFactor: 3,
+			AntiAffinities:    zoneAntiAffinity(model.AntiAffinityModeStrict),
+		}},
+		// s3 is removed
+		Servers: []model.ServerAddress{s1, s2, s4, s5, s6},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "b", s3: "c", s4: "c", s5: "b", s6: "a",
+		}),
+	}
+
+	// The replica can only be moved to the server in the same zone
+	actions := rebalanceCluster(config, cs)
+	assert.Equal(t, []SwapNodeAction{{
+		Shard: 0,
+		From:  s3,
+		To:    s4,
+	}}, actions)
+}
+
+func TestPlacement_Constraints(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 2,
+			ReplicationFactor: 2,
+			PlacementConstraints: []model.PlacementConstraint{{
+				Label:  "zone",
+				Values: []string{"a", "b"},
+			}},
+		}, {
+			Name:              "ns-2",
+			InitialShardCount: 1,
+			ReplicationFactor: 2,
+			PlacementConstraints: []model.PlacementConstraint{{
+				Label:    "zone",
+				Operator: model.PlacementOperatorNotIn,
+				Values:   []string{"a"},
+			}},
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: zones(map[model.ServerAddress]string{
+			s1: "a", s2: "c", s3: "b",
+		}),
+	}
+
+	newStatus, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// The servers without the label only satisfy the "not-in" constraints
+	for _, sm := range newStatus.Namespaces["ns-1"].Shards {
+		assert.ElementsMatch(t, []model.ServerAddress{s1, s3}, sm.Ensemble)
+	}
+	for _, sm := range newStatus.Namespaces["ns-2"].Shards {
+		assert.NotContains(t, sm.Ensemble, s1)
+	}
+
+	// The replicas are never moved to the servers that don't satisfy the constraints
+	policy := newPlacementPolicy(config)
+	assert.False(t, policy.canSwap("ns-1", []model.ServerAddress{s1, s3}, s3, s2, true))
+	assert.True(t, policy.canSwap("ns-2", []model.ServerAddress{s2, s3}, s3, s4, true))
+
+	config.Namespaces[0].ReplicationFactor = 3
+	_, _, _, err = applyClusterChanges(config, model.NewClusterStatus())
+	assert.ErrorIs(t, err, ErrPlacementNotSatisfied)
+}
+
+func TestPlacement_Pools(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 2,
+			ReplicationFactor: 2,
+		}, {
+			Name:              "ns-2",
+			InitialShardCount: 2,
+			ReplicationFactor: 2,
+			Pool:              "eu",
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: map[string]model.ServerMetadata{
+			s3.Internal: {Pool: "eu"},
+			s4.Internal: {Pool: "eu"},
+		},
+	}
+	assert.NoError(t, validateClusterConfig(config))
+
+	newStatus, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	for _, sm := range newStatus.Namespaces["ns-1"].Shards {
+		assert.ElementsMatch(t, []model.ServerAddress{s1, s2}, sm.Ensemble)
+	}
+	for _, sm := range newStatus.Namespaces["ns-2"].Shards {
+		assert.ElementsMatch(t, []model.ServerAddress{s3, s4}, sm.Ensemble)
+	}
+
+	// The replicas are never moved across pools
+	policy := newPlacementPolicy(config)
+	assert.False(t, policy.canSwap("ns-1", []model.ServerAddress{s1, s2}, s2, s3, true))
+	assert.False(t, policy.canSwap("ns-2", []model.ServerAddress{s3, s4}, s4, s1, true))
+
+	config.Namespaces[1].ReplicationFactor = 3
+	_, _, _, err = applyClusterChanges(config, model.NewClusterStatus())
+	assert.ErrorIs(t, err, ErrPlacementNotSatisfied)
+}
+
+func TestPlacement_RebalanceWithinPools(t *testing.T) {
+	cs := &model.ClusterStatus{
+		Namespaces: map[string]model.NamespaceStatus{
+			"ns-1": {
+				ReplicationFactor: 1,
+				Shards: map[int64]model.ShardMetadata{
+					0: {Ensemble: []model.ServerAddress{s1}},
+					1: {Ensemble: []model.ServerAddress{s1}},
+					2: {Ensemble: []model.ServerAddress{s1}},
+					3: {Ensemble: []model.ServerAddress{s1}},
+				},
+			},
+			"ns-2": {
+				ReplicationFactor: 1,
+				Shards: map[int64]model.ShardMetadata{
+					4: {Ensemble: []model.ServerAddress{s3}},
+					5: {Ensemble: []model.ServerAddress{s3}},
+				},
+			},
+		},
+	}
+
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{
+			{Name: "ns-1", InitialShardCount: 4, ReplicationFactor: 1},
+			{Name: "ns-2", InitialShardCount: 2, ReplicationFactor: 1, Pool: "eu"},
+		},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: map[string]model.ServerMetadata{
+			s3.Internal: {Pool: "eu"},
+			s4.Internal: {Pool: "eu"},
+		},
+	}
+
+	// Each pool is evened out on its own, even if the least loaded server of
+	// the whole cluster is in the other pool
+	actions := rebalanceCluster(config, cs)
+	assert.Equal(t, []SwapNodeAction{
+		{Shard: 0, From: s1, To: s2},
+		{Shard: 1, From: s1, To: s2},
+		{Shard: 4, From: s3, To: s4},
+	}, actions)
+}
+
+func TestPlacement_FailureDomains(t *testing.T) {
+	hosts := map[model.ServerAddress]string{s1: "h1", s2: "h1", s3: "h2", s4: "h2", s5: "h3"}
+	serverMetadata := map[string]model.ServerMetadata{}
+	for sa, host := range hosts {
+		serverMetadata[sa.Internal] = model.ServerMetadata{Labels: map[string]string{"host": host}}
+	}
+
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 3,
+			ReplicationFactor: 3,
+		}},
+		Servers:        []model.ServerAddress{s1, s2, s3, s4, s5},
+		ServerMetadata: serverMetadata,
+		FailureDomains: []string{"host"},
+	}
+	assert.NoError(t, validateClusterConfig(config))
+
+	newStatus, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// No two replicas of a shard share a host, even without anti-affinity rules
+	for _, sm := range newStatus.Namespaces["ns-1"].Shards {
+		assert.Len(t, sm.Ensemble, 3)
+		shardHosts := map[string]bool{}
+		for _, sa := range sm.Ensemble {
+			shardHosts[hosts[sa]] = true
+		}
+		assert.Len(t, shardHosts, 3)
+	}
+
+	// The failure domains apply to the namespaces that are not in the config too
+	policy := newPlacementPolicy(config)
+	assert.False(t, policy.canPlace("dynamic", []model.ServerAddress{s1}, s2, true))
+	assert.True(t, policy.canPlace("dynamic", []model.ServerAddress{s1}, s3, true))
+	assert.False(t, policy.canSwap("ns-1", []model.ServerAddress{s1, s3, s5}, s5, s4, true))
+
+	// There are only 3 hosts
+	config.Namespaces[0].ReplicationFactor = 4
+	_, _, _, err = applyClusterChanges(config, model.NewClusterStatus())
+	assert.ErrorIs(t, err, ErrAntiAffinityNotSatisfied)
+}
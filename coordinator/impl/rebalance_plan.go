@@ -0,0 +1,123 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"time"
+)
+
+type PlanState string
+
+const (
+	// PlanStatePending is set until the operator approves the plan
+	PlanStatePending PlanState = "pending"
+	// PlanStateExecuting is set while the moves are applied, one at a time
+	PlanStateExecuting PlanState = "executing"
+	// PlanStateCompleted is set once every move was either applied, or skipped
+	PlanStateCompleted PlanState = "completed"
+	// PlanStateAborted is set if the execution was interrupted, eg: by a
+	// maintenance freeze. The remaining moves are not applied.
+	PlanStateAborted PlanState = "aborted"
+)
+
+type MoveState string
+
+const (
+	MoveStatePending    MoveState = "pending"
+	MoveStateInProgress MoveState = "in-progress"
+	MoveStateCompleted  MoveState = "completed"
+	MoveStateFailed     MoveState = "failed"
+	// MoveStateSkipped is set when the ensemble of the shard has changed
+	// since the plan was computed, and the move doesn't apply anymore
+	MoveStateSkipped MoveState = "skipped"
+)
+
+// PlannedMove is a replica move of a rebalance plan.
+type PlannedMove struct {
+	SwapNodeAction
+
+	// EstimatedBytes is the size of the shard data that the new replica has
+	// to copy, as last reported by the shard leader, if known
+	EstimatedBytes *int64
+
+	State MoveState
+}
+
+// RebalancePlan is a list of replica moves, that are computed in advance for
+// the operator to review, and applied only once the plan is approved.
+type RebalancePlan struct {
+	Id        int64
+	CreatedAt time.Time
+	State     PlanState
+	Moves     []PlannedMove
+}
+
+func newRebalancePlan(id int64, actions []SwapNodeAction, loads map[int64]ShardLoad) *RebalancePlan {
+	plan := &RebalancePlan{
+		Id:        id,
+		CreatedAt: time.Now(),
+		State:     PlanStatePending,
+		Moves:     make([]PlannedMove, 0, len(actions)),
+	}
+
+	for _, a := range actions {
+		move := PlannedMove{SwapNodeAction: a, State: MoveStatePending}
+		if load, ok := loads[a.Shard]; ok {
+			diskBytes := load.DiskBytes
+			move.EstimatedBytes = &diskBytes
+		}
+		plan.Moves = append(plan.Moves, move)
+	}
+	return plan
+}
+
+// Actions returns the replica moves of the plan.
+func (p *RebalancePlan) Actions() []SwapNodeAction {
+	res := make([]SwapNodeAction, 0, len(p.Moves))
+	for _, m := range p.Moves {
+		res = append(res, m.SwapNodeAction)
+	}
+	return res
+}
+
+// DoneMoves returns the number of moves that are not pending nor in progress.
+func (p *RebalancePlan) DoneMoves() int {
+	count := 0
+	for _, m := range p.Moves {
+		if m.State != MoveStatePending && m.State != MoveStateInProgress {
+			count++
+		}
+	}
+	return count
+}
+
+// EstimatedBytes returns the total size of the shard data to copy, for the
+// moves whose shard size is known.
+func (p *RebalancePlan) EstimatedBytes() int64 {
+	var total int64
+	for _, m := range p.Moves {
+		if m.EstimatedBytes != nil {
+			total += *m.EstimatedBytes
+		}
+	}
+	return total
+}
+
+func (p *RebalancePlan) Clone() *RebalancePlan {
+	r := *p
+	r.Moves = make([]PlannedMove, len(p.Moves))
+	copy(r.Moves, p.Moves)
+	return &r
+}
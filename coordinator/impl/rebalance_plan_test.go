@@ -0,0 +1,54 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestRebalancePlan(t *testing.T) {
+	s1 := model.ServerAddress{Public: "s1:6648", Internal: "s1:6649"}
+	s2 := model.ServerAddress{Public: "s2:6648", Internal: "s2:6649"}
+	actions := []SwapNodeAction{
+		{Shard: 0, From: s1, To: s2},
+		{Shard: 1, From: s1, To: s2},
+		{Shard: 2, From: s2, To: s1},
+	}
+	loads := map[int64]ShardLoad{
+		0: {DiskBytes: 100},
+		2: {DiskBytes: 50},
+	}
+
+	plan := newRebalancePlan(7, actions, loads)
+	assert.EqualValues(t, 7, plan.Id)
+	assert.Equal(t, PlanStatePending, plan.State)
+	assert.Equal(t, actions, plan.Actions())
+	assert.EqualValues(t, 100, *plan.Moves[0].EstimatedBytes)
+	assert.Nil(t, plan.Moves[1].EstimatedBytes)
+	assert.EqualValues(t, 50, *plan.Moves[2].EstimatedBytes)
+	assert.EqualValues(t, 150, plan.EstimatedBytes())
+	assert.Equal(t, 0, plan.DoneMoves())
+
+	clone := plan.Clone()
+	plan.Moves[0].State = MoveStateCompleted
+	plan.Moves[1].State = MoveStateInProgress
+	plan.Moves[2].State = MoveStateSkipped
+	assert.Equal(t, 2, plan.DoneMoves())
+	assert.Equal(t, 0, clone.DoneMoves())
+}
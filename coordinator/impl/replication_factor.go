@@ -0,0 +1,143 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"math"
+	"sort"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// EnsembleChangeAction adds or removes one member of the ensemble of a shard.
+type EnsembleChangeAction struct {
+	Namespace string
+	Shard     int64
+	Add       *model.ServerAddress
+	Remove    *model.ServerAddress
+}
+
+// computeReplicationFactorChanges finds the shards whose ensemble size doesn't
+// match the replication factor of their namespace. At most one member of each
+// shard ensemble is changed per invocation, so that the quorum is never
+// changed by more than one server at a time.
+//
+// The new replicas are only placed on the available servers, as chosen by
+// the assignment strategy, which can take into account the shard loads.
+func computeReplicationFactorChanges(config *model.ClusterConfig, currentStatus *model.ClusterStatus,
+	available []model.ServerAddress, loads map[int64]ShardLoad) []EnsembleChangeAction {
+	res := make([]EnsembleChangeAction, 0)
+	shardsPerServer, _ := getShardsPerServer(config.Servers, currentStatus)
+	policy := newPlacementPolicy(config).withLoads(loads)
+
+	for _, ns := range sortedNamespaces(currentStatus) {
+		nss := currentStatus.Namespaces[ns]
+		if nss.IsSoftDeleted() {
+			continue
+		}
+
+		for _, shard := range sortedShards(nss) {
+			sm := nss.Shards[shard]
+			if sm.Status == model.ShardStatusDeleting {
+				continue
+			}
+
+			rf := int(nss.ReplicationFactor)
+			switch {
+			case len(sm.Ensemble) < rf:
+				to, ok := selectServerToAdd(policy, ns, sm.Ensemble, shardsPerServer, available)
+				if !ok {
+					continue
+				}
+
+				shardsPerServer[to].Add(shard)
+				res = append(res, EnsembleChangeAction{Namespace: ns, Shard: shard, Add: &to})
+
+			case len(sm.Ensemble) > rf:
+				from := selectServerToRemove(sm, shardsPerServer)
+				if s, ok := shardsPerServer[from]; ok {
+					s.Remove(shard)
+				}
+				res = append(res, EnsembleChangeAction{Namespace: ns, Shard: shard, Remove: &from})
+			}
+		}
+	}
+
+	return res
+}
+
+// Let the assignment strategy pick one of the available servers that are
+// not part of the ensemble.
+func selectServerToAdd(policy *placementPolicy, namespace string, ensemble []model.ServerAddress,
+	shardsPerServer map[model.ServerAddress]common.Set[int64], available []model.ServerAddress) (model.ServerAddress, bool) {
+	candidates := make([]model.ServerAddress, 0, len(available))
+	for _, sa := range available {
+		if _, ok := shardsPerServer[sa]; ok && !listContains(ensemble, sa) {
+			candidates = append(candidates, sa)
+		}
+	}
+
+	return policy.strategy.SelectServer(policy.newAssignmentContext(shardsPerServer), namespace, ensemble, candidates)
+}
+
+// Pick the most loaded member of the ensemble, avoiding the current leader
+// to not cause an unnecessary leader change.
+func selectServerToRemove(sm model.ShardMetadata, shardsPerServer map[model.ServerAddress]common.Set[int64]) model.ServerAddress {
+	candidates := make([]model.ServerAddress, 0, len(sm.Ensemble))
+	for _, sa := range sm.Ensemble {
+		if sm.Leader == nil || sa != *sm.Leader {
+			candidates = append(candidates, sa)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, sm.Ensemble...)
+	}
+
+	load := func(sa model.ServerAddress) int {
+		if s, ok := shardsPerServer[sa]; ok {
+			return s.Count()
+		}
+		// The servers that are not in the config anymore go first
+		return math.MaxInt
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		li, lj := load(candidates[i]), load(candidates[j])
+		if li != lj {
+			return li > lj
+		}
+		return candidates[i].Internal < candidates[j].Internal
+	})
+	return candidates[0]
+}
+
+func sortedNamespaces(status *model.ClusterStatus) []string {
+	res := make([]string, 0, len(status.Namespaces))
+	for ns := range status.Namespaces {
+		res = append(res, ns)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func sortedShards(nss model.NamespaceStatus) []int64 {
+	res := make([]int64, 0, len(nss.Shards))
+	for shard := range nss.Shards {
+		res = append(res, shard)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
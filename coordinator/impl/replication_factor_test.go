@@ -0,0 +1,158 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func rfTestStatus(rf uint32, ensembles map[int64][]model.ServerAddress, leader *model.ServerAddress) *model.ClusterStatus {
+	shards := map[int64]model.ShardMetadata{}
+	for shard, ensemble := range ensembles {
+		shards[shard] = model.ShardMetadata{
+			Status:   model.ShardStatusSteadyState,
+			Leader:   leader,
+			Ensemble: ensemble,
+		}
+	}
+
+	return &model.ClusterStatus{
+		Namespaces: map[string]model.NamespaceStatus{
+			"ns-1": {
+				ReplicationFactor: rf,
+				Shards:            shards,
+			},
+		},
+	}
+}
+
+func TestReplicationFactor_Grow(t *testing.T) {
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3, s4}}
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1},
+		1: {s1, s2, s3},
+	}, &s1)
+
+	// Only one member is added per shard, on the least loaded server
+	actions := computeReplicationFactorChanges(config, status, config.Servers, nil)
+	assert.Equal(t, []EnsembleChangeAction{{Namespace: "ns-1", Shard: 0, Add: &s4}}, actions)
+
+	// The unavailable servers are not considered
+	actions = computeReplicationFactorChanges(config, status, []model.ServerAddress{s1, s2}, nil)
+	assert.Equal(t, []EnsembleChangeAction{{Namespace: "ns-1", Shard: 0, Add: &s2}}, actions)
+
+	actions = computeReplicationFactorChanges(config, status, []model.ServerAddress{s1}, nil)
+	assert.Empty(t, actions)
+}
+
+func TestReplicationFactor_Shrink(t *testing.T) {
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3}}
+	status := rfTestStatus(1, map[int64][]model.ServerAddress{
+		0: {s1, s2, s3},
+		1: {s1, s3},
+	}, &s1)
+
+	// The leader is never removed, and the most loaded server goes first
+	actions := computeReplicationFactorChanges(config, status, config.Servers, nil)
+	assert.Equal(t, []EnsembleChangeAction{
+		{Namespace: "ns-1", Shard: 0, Remove: &s3},
+		{Namespace: "ns-1", Shard: 1, Remove: &s3},
+	}, actions)
+}
+
+func TestReplicationFactor_SkipDeleted(t *testing.T) {
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3}}
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1},
+	}, &s1)
+
+	sm := status.Namespaces["ns-1"].Shards[0]
+	sm.Status = model.ShardStatusDeleting
+	status.Namespaces["ns-1"].Shards[0] = sm
+
+	assert.Empty(t, computeReplicationFactorChanges(config, status, config.Servers, nil))
+}
+
+func TestValidateClusterConfig(t *testing.T) {
+	valid := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{Name: "ns-1", InitialShardCount: 1, ReplicationFactor: 2}},
+		Servers:    []model.ServerAddress{s1, s2},
+	}
+	assert.NoError(t, validateClusterConfig(&valid))
+
+	for name, update := range map[string]func(c *model.ClusterConfig){
+		"no-servers":        func(c *model.ClusterConfig) { c.Servers = nil },
+		"empty-address":     func(c *model.ClusterConfig) { c.Servers = append(c.Servers, model.ServerAddress{Public: "s3:6648"}) },
+		"duplicated-server": func(c *model.ClusterConfig) { c.Servers = append(c.Servers, s1) },
+		"duplicated-ns":     func(c *model.ClusterConfig) { c.Namespaces = append(c.Namespaces, c.Namespaces[0]) },
+		"rf-too-big":        func(c *model.ClusterConfig) { c.Namespaces[0].ReplicationFactor = 3 },
+		"no-shards":         func(c *model.ClusterConfig) { c.Namespaces[0].InitialShardCount = 0 },
+		"unknown-lb-policy": func(c *model.ClusterConfig) { c.LoadBalancer = &model.LoadBalancerConfig{Policy: "foo"} },
+		"negative-grace":    func(c *model.ClusterConfig) { c.NamespaceDeletionGracePeriod = -1 },
+		"unknown-strategy":  func(c *model.ClusterConfig) { c.Assignment = &model.AssignmentConfig{Strategy: "foo"} },
+		"empty-failure-domain": func(c *model.ClusterConfig) {
+			c.FailureDomains = []string{""}
+		},
+		"rf-too-big-for-pool": func(c *model.ClusterConfig) {
+			c.ServerMetadata = map[string]model.ServerMetadata{s2.Internal: {Pool: "eu"}}
+			c.Namespaces[0].Pool = "eu"
+		},
+		"negative-namespace-policy": func(c *model.ClusterConfig) {
+			c.Namespaces[0].Policies = &model.NamespacePolicies{MaxValueSize: -1}
+		},
+		"negative-principal-quota": func(c *model.ClusterConfig) {
+			c.Namespaces[0].Policies = &model.NamespacePolicies{PrincipalQuotas: map[string]model.PrincipalQuota{
+				"user-1": {MaxStorageBytes: -1},
+			}}
+		},
+		"sliding-ttl-without-ttl": func(c *model.ClusterConfig) {
+			c.Namespaces[0].Policies = &model.NamespacePolicies{SlidingTTL: true}
+		},
+		"unknown-permission": func(c *model.ClusterConfig) {
+			c.Authorization = &model.AuthorizationConfig{Roles: []model.Role{{
+				Name:        "role-1",
+				Principals:  []string{"user-1"},
+				Permissions: map[string][]model.Permission{"ns-1": {"delete"}},
+			}}}
+		},
+		"empty-role-name": func(c *model.ClusterConfig) {
+			c.Authorization = &model.AuthorizationConfig{Roles: []model.Role{{Principals: []string{"user-1"}}}}
+		},
+		"negative-election-rate": func(c *model.ClusterConfig) {
+			c.Elections = &model.ElectionsConfig{MaxRoundsPerSecond: -1}
+		},
+		"empty-anti-affinity": func(c *model.ClusterConfig) {
+			c.Namespaces[0].AntiAffinities = []model.AntiAffinity{{Mode: model.AntiAffinityModeStrict}}
+		},
+		"empty-placement-values": func(c *model.ClusterConfig) {
+			c.Namespaces[0].PlacementConstraints = []model.PlacementConstraint{{Label: "zone"}}
+		},
+		"unknown-placement-operator": func(c *model.ClusterConfig) {
+			c.Namespaces[0].PlacementConstraints = []model.PlacementConstraint{{Label: "zone", Operator: "foo", Values: []string{"a"}}}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			config.Servers = append([]model.ServerAddress{}, valid.Servers...)
+			config.Namespaces = append([]model.NamespaceConfig{}, valid.Namespaces...)
+			update(&config)
+			assert.ErrorIs(t, validateClusterConfig(&config), ErrInvalidClusterConfig)
+		})
+	}
+}
@@ -34,6 +34,10 @@ type RpcProvider interface {
 	AddFollower(ctx context.Context, node model.ServerAddress, req *proto.AddFollowerRequest) (*proto.AddFollowerResponse, error)
 	GetStatus(ctx context.Context, node model.ServerAddress, req *proto.GetStatusRequest) (*proto.GetStatusResponse, error)
 	DeleteShard(ctx context.Context, node model.ServerAddress, req *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error)
+	ListShardSessions(ctx context.Context, node model.ServerAddress, req *proto.ListShardSessionsRequest) (*proto.ListShardSessionsResponse, error)
+	CloseSession(ctx context.Context, node model.ServerAddress, req *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error)
+	ExportRecords(ctx context.Context, node model.ServerAddress, req *proto.ExportRecordsRequest) (proto.OxiaCoordination_ExportRecordsClient, error)
+	ImportRecords(ctx context.Context, node model.ServerAddress, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error)
 
 	GetHealthClient(node model.ServerAddress) (grpc_health_v1.HealthClient, error)
 }
@@ -115,6 +119,53 @@ func (r *rpcProvider) DeleteShard(ctx context.Context, node model.ServerAddress,
 	return rpc.DeleteShard(ctx, req)
 }
 
+func (r *rpcProvider) ListShardSessions(ctx context.Context, node model.ServerAddress, req *proto.ListShardSessionsRequest) (*proto.ListShardSessionsResponse, error) {
+	rpc, err := r.pool.GetCoordinationRpc(node.Internal)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	return rpc.ListShardSessions(ctx, req)
+}
+
+func (r *rpcProvider) CloseSession(ctx context.Context, node model.ServerAddress, req *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+	rpc, err := r.pool.GetCoordinationRpc(node.Internal)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	return rpc.CloseSession(ctx, req)
+}
+
+func (r *rpcProvider) ExportRecords(ctx context.Context, node model.ServerAddress, req *proto.ExportRecordsRequest) (proto.OxiaCoordination_ExportRecordsClient, error) {
+	rpc, err := r.pool.GetCoordinationRpc(node.Internal)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream lasts as long as the records are being read, and it's
+	// canceled with the context of the caller
+	return rpc.ExportRecords(ctx, req)
+}
+
+func (r *rpcProvider) ImportRecords(ctx context.Context, node model.ServerAddress, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	rpc, err := r.pool.GetCoordinationRpc(node.Internal)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	return rpc.ImportRecords(ctx, req)
+}
+
 func (r *rpcProvider) GetHealthClient(node model.ServerAddress) (grpc_health_v1.HealthClient, error) {
 	return r.pool.GetHealthRpc(node.Internal)
 }
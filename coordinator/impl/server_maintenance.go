@@ -0,0 +1,181 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+// activeConfig returns a copy of the cluster config without the servers that
+// are being decommissioned. Their replicas are moved out, as it happens for the
+// servers that are removed from the cluster config.
+func activeConfig(config *model.ClusterConfig, status *model.ClusterStatus) *model.ClusterConfig {
+	return filterServers(config, func(sa model.ServerAddress) bool {
+		return !status.IsDecommissioned(sa)
+	})
+}
+
+// placementConfig returns a copy of the cluster config with only the servers
+// where new replicas can be placed, excluding the ones being decommissioned
+// or drained.
+func placementConfig(config *model.ClusterConfig, status *model.ClusterStatus) *model.ClusterConfig {
+	return filterServers(config, func(sa model.ServerAddress) bool {
+		return !status.IsDecommissioned(sa) && !status.IsDraining(sa)
+	})
+}
+
+func filterServers(config *model.ClusterConfig, include func(sa model.ServerAddress) bool) *model.ClusterConfig {
+	res := *config
+	res.Servers = make([]model.ServerAddress, 0, len(config.Servers))
+	for _, sa := range config.Servers {
+		if include(sa) {
+			res.Servers = append(res.Servers, sa)
+		}
+	}
+	return &res
+}
+
+// Count the shards that still have a replica on the server.
+func shardsOnServer(status *model.ClusterStatus, server model.ServerAddress) int {
+	count := 0
+	for _, nss := range status.Namespaces {
+		for _, sm := range nss.Shards {
+			if listContains(sm.Ensemble, server) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// hasHealthyQuorums checks that every shard has a leader and that a majority
+// of its ensemble is running.
+func hasHealthyQuorums(status *model.ClusterStatus, isRunning func(server model.ServerAddress) bool) bool {
+	for _, nss := range status.Namespaces {
+		if nss.IsSoftDeleted() {
+			continue
+		}
+
+		for _, sm := range nss.Shards {
+			if sm.Status == model.ShardStatusDeleting {
+				continue
+			}
+			if sm.Status != model.ShardStatusSteadyState || sm.Leader == nil {
+				return false
+			}
+
+			running := 0
+			for _, sa := range sm.Ensemble {
+				if isRunning(sa) {
+					running++
+				}
+			}
+			if running < len(sm.Ensemble)/2+1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Check that the remaining servers are enough to host all the replicas, once
+// the server is decommissioned.
+func canDecommission(config *model.ClusterConfig, status *model.ClusterStatus, server model.ServerAddress) bool {
+	remaining := 0
+	for _, sa := range placementConfig(config, status).Servers {
+		if sa != server {
+			remaining++
+		}
+	}
+
+	for _, nss := range status.Namespaces {
+		if !nss.IsDeleting() && int(nss.ReplicationFactor) > remaining {
+			return false
+		}
+	}
+	return true
+}
+
+// Count the shards that are led by the server. The shards of the server that
+// are electing a leader are counted too, since it can still be elected.
+func leadersOnServer(status *model.ClusterStatus, server model.ServerAddress) int {
+	count := 0
+	for _, nss := range status.Namespaces {
+		for _, sm := range nss.Shards {
+			switch {
+			case sm.Status == model.ShardStatusSteadyState:
+				if sm.Leader != nil && *sm.Leader == server {
+					count++
+				}
+			case sm.Status != model.ShardStatusDeleting && listContains(sm.Ensemble, server):
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// LeaderTransferAction moves the leadership of a shard to another member of
+// its ensemble.
+type LeaderTransferAction struct {
+	Shard int64
+	From  model.ServerAddress
+	To    model.ServerAddress
+}
+
+// computeLeaderTransfers picks a new leader for the shards that are led by the
+// drained servers, among the running followers that lead the fewest shards.
+func computeLeaderTransfers(status *model.ClusterStatus, draining []model.ServerAddress,
+	isRunning func(server model.ServerAddress) bool) []LeaderTransferAction {
+	leadersPerServer := map[model.ServerAddress]int{}
+	for _, nss := range status.Namespaces {
+		for _, sm := range nss.Shards {
+			if sm.Leader != nil {
+				leadersPerServer[*sm.Leader]++
+			}
+		}
+	}
+
+	res := make([]LeaderTransferAction, 0)
+	for _, ns := range sortedNamespaces(status) {
+		nss := status.Namespaces[ns]
+		for _, shard := range sortedShards(nss) {
+			sm := nss.Shards[shard]
+			if sm.Status != model.ShardStatusSteadyState || sm.Leader == nil || !listContains(draining, *sm.Leader) {
+				continue
+			}
+
+			var to *model.ServerAddress
+			for _, sa := range sm.Ensemble {
+				if listContains(draining, sa) || !isRunning(sa) {
+					continue
+				}
+				if to == nil || leadersPerServer[sa] < leadersPerServer[*to] ||
+					(leadersPerServer[sa] == leadersPerServer[*to] && sa.Internal < to.Internal) {
+					candidate := sa
+					to = &candidate
+				}
+			}
+			if to == nil {
+				continue
+			}
+
+			leadersPerServer[*sm.Leader]--
+			leadersPerServer[*to]++
+			res = append(res, LeaderTransferAction{Shard: shard, From: *sm.Leader, To: *to})
+		}
+	}
+	return res
+}
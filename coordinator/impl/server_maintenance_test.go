@@ -0,0 +1,124 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+)
+
+func TestDecommission_NewShardsAvoidServer(t *testing.T) {
+	config := &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "ns-1",
+			InitialShardCount: 4,
+			ReplicationFactor: 2,
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3},
+	}
+	status := model.NewClusterStatus()
+	status.Servers = map[string]model.ServerStatus{
+		s1.Internal: {Decommission: model.DecommissionStateInProgress},
+	}
+
+	newStatus, _, _, err := applyClusterChanges(config, status)
+	assert.NoError(t, err)
+	assert.Equal(t, status.Servers, newStatus.Servers)
+	for _, shard := range newStatus.Namespaces["ns-1"].Shards {
+		assert.NotContains(t, shard.Ensemble, s1)
+	}
+	assert.Equal(t, 0, shardsOnServer(newStatus, s1))
+	assert.Equal(t, 4, shardsOnServer(newStatus, s2))
+
+	// Not enough servers left for the replication factor
+	assert.True(t, canDecommission(config, newStatus, s1))
+	assert.False(t, canDecommission(config, newStatus, s2))
+
+	config.Namespaces[0].ReplicationFactor = 3
+	_, _, _, err = applyClusterChanges(config, status)
+	assert.ErrorIs(t, err, ErrNoAvailableServers)
+
+	// The status is dropped once the server is removed from the config
+	config.Namespaces[0].ReplicationFactor = 2
+	config.Servers = []model.ServerAddress{s2, s3}
+	newStatus, _, _, err = applyClusterChanges(config, status)
+	assert.NoError(t, err)
+	assert.Empty(t, newStatus.Servers)
+}
+
+func TestDecommission_HealthyQuorums(t *testing.T) {
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1, s2, s3},
+	}, &s1)
+
+	running := map[model.ServerAddress]bool{s1: true, s2: true}
+	isRunning := func(sa model.ServerAddress) bool { return running[sa] }
+	assert.True(t, hasHealthyQuorums(status, isRunning))
+
+	running[s2] = false
+	assert.False(t, hasHealthyQuorums(status, isRunning))
+
+	running[s2] = true
+	sm := status.Namespaces["ns-1"].Shards[0]
+	sm.Status = model.ShardStatusElection
+	status.Namespaces["ns-1"].Shards[0] = sm
+	assert.False(t, hasHealthyQuorums(status, isRunning))
+}
+
+func TestDrain_LeaderTransfers(t *testing.T) {
+	status := rfTestStatus(3, map[int64][]model.ServerAddress{
+		0: {s1, s2, s3},
+		1: {s1, s2, s3},
+		2: {s1, s2, s3},
+	}, &s1)
+	sm := status.Namespaces["ns-1"].Shards[2]
+	sm.Leader = &s3
+	status.Namespaces["ns-1"].Shards[2] = sm
+
+	isRunning := func(model.ServerAddress) bool { return true }
+
+	// The leaders are spread over the followers that lead the fewest shards
+	assert.Equal(t, []LeaderTransferAction{
+		{Shard: 0, From: s1, To: s2},
+		{Shard: 1, From: s1, To: s2},
+	}, computeLeaderTransfers(status, []model.ServerAddress{s1}, isRunning))
+
+	// The servers that are not running are skipped
+	assert.Equal(t, []LeaderTransferAction{
+		{Shard: 0, From: s1, To: s3},
+		{Shard: 1, From: s1, To: s3},
+	}, computeLeaderTransfers(status, []model.ServerAddress{s1}, func(sa model.ServerAddress) bool { return sa != s2 }))
+
+	// Nothing can be done when all the followers are drained
+	assert.Empty(t, computeLeaderTransfers(status, []model.ServerAddress{s1, s2, s3}, isRunning))
+
+	// The shards electing a leader can still be led by any of their servers
+	assert.Equal(t, 2, leadersOnServer(status, s1))
+	assert.Equal(t, 0, leadersOnServer(status, s2))
+	sm.Status = model.ShardStatusElection
+	sm.Leader = nil
+	status.Namespaces["ns-1"].Shards[2] = sm
+	assert.Equal(t, 3, leadersOnServer(status, s1))
+	assert.Equal(t, 1, leadersOnServer(status, s2))
+	assert.Empty(t, computeLeaderTransfers(status, []model.ServerAddress{s2}, isRunning))
+
+	status.Servers = map[string]model.ServerStatus{s1.Internal: {Draining: true}}
+	config := &model.ClusterConfig{Servers: []model.ServerAddress{s1, s2, s3}}
+	assert.Equal(t, []model.ServerAddress{s1, s2, s3}, activeConfig(config, status).Servers)
+	assert.Equal(t, []model.ServerAddress{s2, s3}, placementConfig(config, status).Servers)
+}
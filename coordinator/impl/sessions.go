@@ -0,0 +1,117 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+// SessionInfo describes a client session, as reported by the shard leader.
+type SessionInfo struct {
+	Shard          int64
+	SessionId      int64
+	Leader         model.ServerAddress
+	ClientIdentity string
+	Timeout        time.Duration
+	CreatedTime    time.Time
+
+	// LastHeartbeat is only tracked since the current leader took over the shard
+	LastHeartbeat time.Time
+	EphemeralKeys int64
+}
+
+// Find the leader of each of the requested shards of the namespace.
+func (c *coordinator) shardLeaders(namespace string, shard *int64) (map[int64]model.ServerAddress, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	nss, ok := c.clusterStatus.Namespaces[namespace]
+	if !ok || nss.IsSoftDeleted() {
+		return nil, ErrNamespaceNotFound
+	}
+
+	shards := sortedShards(nss)
+	if shard != nil {
+		if _, ok := nss.Shards[*shard]; !ok {
+			return nil, ErrShardNotFound
+		}
+		shards = []int64{*shard}
+	}
+
+	res := map[int64]model.ServerAddress{}
+	for _, s := range shards {
+		sm := nss.Shards[s]
+		if sm.Leader == nil || sm.Status != model.ShardStatusSteadyState {
+			return nil, errors.Wrapf(ErrShardHasNoLeader, "shard %d", s)
+		}
+		res[s] = *sm.Leader
+	}
+	return res, nil
+}
+
+func (c *coordinator) ListSessions(namespace string, shard *int64) ([]SessionInfo, error) {
+	leaders, err := c.shardLeaders(namespace, shard)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]int64, 0, len(leaders))
+	for s := range leaders {
+		shards = append(shards, s)
+	}
+	slices.Sort(shards)
+
+	res := make([]SessionInfo, 0)
+	for _, s := range shards {
+		leader := leaders[s]
+		resp, err := c.rpc.ListShardSessions(c.ctx, leader, &proto.ListShardSessionsRequest{ShardId: s})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the sessions of shard %d", s)
+		}
+
+		for _, ss := range resp.Sessions {
+			res = append(res, SessionInfo{
+				Shard:          s,
+				SessionId:      ss.SessionId,
+				Leader:         leader,
+				ClientIdentity: ss.ClientIdentity,
+				Timeout:        time.Duration(ss.TimeoutMs) * time.Millisecond,
+				CreatedTime:    time.UnixMilli(int64(ss.CreatedTimestamp)),
+				LastHeartbeat:  time.UnixMilli(int64(ss.LastHeartbeatTimestamp)),
+				EphemeralKeys:  ss.EphemeralKeys,
+			})
+		}
+	}
+	return res, nil
+}
+
+func (c *coordinator) ForceCloseSession(namespace string, shard int64, sessionId int64) error {
+	leaders, err := c.shardLeaders(namespace, &shard)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.rpc.CloseSession(c.ctx, leaders[shard], &proto.CloseSessionRequest{
+		ShardId:   shard,
+		SessionId: sessionId,
+	})
+	return err
+}
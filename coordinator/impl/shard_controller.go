@@ -20,12 +20,15 @@ import (
 	"io"
 	"log/slog"
 	"math/rand"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 
 	"github.com/streamnative/oxia/common"
@@ -42,13 +45,38 @@ const (
 	// Timeout when waiting for followers to catchup with leader.
 	catchupTimeout = 5 * time.Minute
 
+	// Interval at which the leader is asked for followers that stopped
+	// acknowledging entries, and for the load served by the shard.
+	defaultFollowersHealthCheckInterval = 30 * time.Second
+
+	// How often a failed leader is probed, while waiting for it to return
+	leaderReturnCheckInterval = 500 * time.Millisecond
+
 	chanBufferSize = 100
 )
 
-type swapNodeRequest struct {
-	from model.ServerAddress
-	to   model.ServerAddress
-	res  chan error
+// Adds and/or removes a member of the ensemble.
+type ensembleChangeRequest struct {
+	add    *model.ServerAddress
+	remove *model.ServerAddress
+	// The removed member has failed and must not be contacted anymore
+	failed bool
+	res    chan error
+}
+
+type fenceRequest struct {
+	res chan fenceResult
+}
+
+type fenceResult struct {
+	term     int64
+	replicas []model.ServerAddress
+	err      error
+}
+
+type leaderTransferRequest struct {
+	to  model.ServerAddress
+	res chan error
 }
 
 type newTermAndAddFollowerRequest struct {
@@ -65,8 +93,39 @@ type ShardController interface {
 	HandleNodeFailure(failedNode model.ServerAddress)
 
 	SwapNode(from model.ServerAddress, to model.ServerAddress) error
+
+	// ReplaceNode swaps a failed member of the ensemble with a new server.
+	// Unlike SwapNode, the failed member is neither fenced nor asked to
+	// delete its copy of the shard, since it's not reachable anymore.
+	ReplaceNode(from model.ServerAddress, to model.ServerAddress) error
+
+	// AddNode adds a new member to the ensemble of the shard, and waits
+	// for it to catch up with the leader
+	AddNode(node model.ServerAddress) error
+
+	// RemoveNode removes a member from the ensemble of the shard
+	RemoveNode(node model.ServerAddress) error
+
+	// TransferLeader elects the given member of the ensemble as the new
+	// leader, once it has caught up with the current leader
+	TransferLeader(to model.ServerAddress) error
+
 	DeleteShard()
 
+	// FenceForMerge moves the ensemble to a new term, without electing any
+	// leader, so that no more records are written in the shard. It returns
+	// the new term and the members whose records can be exported, which have
+	// the latest entries and no client session. If there is none, a new
+	// leader is elected and ErrShardNotMergeable is returned.
+	FenceForMerge() (int64, []model.ServerAddress, error)
+
+	// ElectLeader elects a new leader, eg: once the shard was fenced for a
+	// merge that did not complete
+	ElectLeader()
+
+	// Load returns the latest load served by the shard, if known
+	Load() (ShardLoad, bool)
+
 	Term() int64
 	Leader() *model.ServerAddress
 	Status() model.ShardStatus
@@ -82,8 +141,21 @@ type shardController struct {
 
 	deleteOp                chan any
 	nodeFailureOp           chan model.ServerAddress
-	swapNodeOp              chan swapNodeRequest
+	ensembleChangeOp        chan ensembleChangeRequest
+	fenceOp                 chan fenceRequest
+	electLeaderOp           chan any
 	newTermAndAddFollowerOp chan newTermAndAddFollowerRequest
+	leaderTransferOp        chan leaderTransferRequest
+
+	// The leader to pick in the next election, if it has all the entries
+	preferredLeader *model.ServerAddress
+
+	// When the shard lost its leader, and how many times each server failed
+	// since then, to detect and recover the shards that are stuck
+	electionStartedAt time.Time
+	electionFailures  map[model.ServerAddress]int
+	lastEscalation    time.Time
+	retryingElection  bool
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -92,25 +164,43 @@ type shardController struct {
 	currentElectionCancel context.CancelFunc
 	log                   *slog.Logger
 
-	leaderElectionLatency metrics.LatencyHistogram
-	newTermQuorumLatency  metrics.LatencyHistogram
-	becomeLeaderLatency   metrics.LatencyHistogram
-	leaderElectionsFailed metrics.Counter
-	termGauge             metrics.Gauge
+	followersHealthCheckInterval time.Duration
+	loadSampler                  loadSampler
+
+	leaderElectionLatency      metrics.LatencyHistogram
+	newTermQuorumLatency       metrics.LatencyHistogram
+	becomeLeaderLatency        metrics.LatencyHistogram
+	leaderElectionsFailed      metrics.Counter
+	leaderElections            metrics.Counter
+	fencingRounds              metrics.Counter
+	recoveryLatency            metrics.LatencyHistogram
+	unhealthyFollowersReplaced metrics.Counter
+	stuckShardEscalations      metrics.Counter
+	termGauge                  metrics.Gauge
+	stuckGauge                 metrics.Gauge
 }
 
 func NewShardController(namespace string, shard int64, shardMetadata model.ShardMetadata, rpc RpcProvider, coordinator Coordinator) ShardController {
+	return newShardController(namespace, shard, shardMetadata, rpc, coordinator, defaultFollowersHealthCheckInterval)
+}
+
+func newShardController(namespace string, shard int64, shardMetadata model.ShardMetadata, rpc RpcProvider, coordinator Coordinator,
+	followersHealthCheckInterval time.Duration) ShardController {
 	labels := metrics.LabelsForShard(namespace, shard)
 	s := &shardController{
-		namespace:               namespace,
-		shard:                   shard,
-		shardMetadata:           shardMetadata,
-		rpc:                     rpc,
-		coordinator:             coordinator,
-		deleteOp:                make(chan any, chanBufferSize),
-		nodeFailureOp:           make(chan model.ServerAddress, chanBufferSize),
-		swapNodeOp:              make(chan swapNodeRequest, chanBufferSize),
-		newTermAndAddFollowerOp: make(chan newTermAndAddFollowerRequest, chanBufferSize),
+		namespace:                    namespace,
+		shard:                        shard,
+		shardMetadata:                shardMetadata,
+		rpc:                          rpc,
+		coordinator:                  coordinator,
+		followersHealthCheckInterval: followersHealthCheckInterval,
+		deleteOp:                     make(chan any, chanBufferSize),
+		nodeFailureOp:                make(chan model.ServerAddress, chanBufferSize),
+		ensembleChangeOp:             make(chan ensembleChangeRequest, chanBufferSize),
+		fenceOp:                      make(chan fenceRequest, chanBufferSize),
+		electLeaderOp:                make(chan any, chanBufferSize),
+		newTermAndAddFollowerOp:      make(chan newTermAndAddFollowerRequest, chanBufferSize),
+		leaderTransferOp:             make(chan leaderTransferRequest, chanBufferSize),
 		log: slog.With(
 			slog.String("component", "shard-controller"),
 			slog.String("namespace", namespace),
@@ -121,16 +211,33 @@ func NewShardController(namespace string, shard int64, shardMetadata model.Shard
 			"The time it takes to elect a leader for the shard", labels),
 		leaderElectionsFailed: metrics.NewCounter("oxia_coordinator_leader_election_failed",
 			"The number of failed leader elections", "count", labels),
+		leaderElections: metrics.NewCounter("oxia_coordinator_leader_elections",
+			"The number of leader elections that were started", "count", labels),
+		fencingRounds: metrics.NewCounter("oxia_coordinator_fencing_rounds",
+			"The number of rounds to take the ensemble of nodes to a new term", "count", labels),
+		recoveryLatency: metrics.NewLatencyHistogram("oxia_coordinator_shard_recovery_latency",
+			"The time it takes for the shard to have a new leader, after the failure of the previous one", labels),
 		newTermQuorumLatency: metrics.NewLatencyHistogram("oxia_coordinator_new_term_quorum_latency",
 			"The time it takes to take the ensemble of nodes to a new term", labels),
 		becomeLeaderLatency: metrics.NewLatencyHistogram("oxia_coordinator_become_leader_latency",
 			"The time it takes for the new elected leader to start", labels),
+		unhealthyFollowersReplaced: metrics.NewCounter("oxia_coordinator_unhealthy_followers_replaced",
+			"The number of unhealthy followers that were replaced in the ensemble", "count", labels),
+		stuckShardEscalations: metrics.NewCounter("oxia_coordinator_stuck_shard_escalations",
+			"The number of members of the ensemble that were replaced, after the shard got stuck in leader election", "count", labels),
 	}
 
 	s.termGauge = metrics.NewGauge("oxia_coordinator_term",
 		"The term of the shard", "count", labels, func() int64 {
 			return s.shardMetadata.Term
 		})
+	s.stuckGauge = metrics.NewGauge("oxia_coordinator_shard_stuck",
+		"Whether the shard has been without a leader for longer than the stuck timeout", "count", labels, func() int64 {
+			if s.isStuck() {
+				return 1
+			}
+			return 0
+		})
 
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 
@@ -156,6 +263,14 @@ func (s *shardController) run() {
 	switch {
 	case s.shardMetadata.Status == model.ShardStatusDeleting:
 		s.DeleteShard()
+	case s.shardMetadata.Merge != nil && s.shardMetadata.Merge.Phase == model.ShardMergePhaseCommitting:
+		// The shard stays fenced while its records are moved into the shard
+		// it's merged into. The coordinator elects a leader if the merge is
+		// aborted.
+		s.log.Info(
+			"Shard is being merged, not electing a leader",
+			slog.Int64("into-shard", s.shardMetadata.Merge.Into),
+		)
 	case s.shardMetadata.Leader == nil || s.shardMetadata.Status != model.ShardStatusSteadyState:
 		s.electLeaderWithRetries()
 	default:
@@ -174,22 +289,37 @@ func (s *shardController) run() {
 		slog.Any("leader", s.shardMetadata.Leader),
 	)
 
+	followersHealthCheck := time.NewTicker(s.followersHealthCheckInterval)
+	defer followersHealthCheck.Stop()
+
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 
+		case <-followersHealthCheck.C:
+			s.checkFollowersHealth()
+
 		case <-s.deleteOp:
 			s.deleteShardWithRetries()
 
 		case n := <-s.nodeFailureOp:
 			s.handleNodeFailure(n)
 
-		case sw := <-s.swapNodeOp:
-			s.swapNode(sw.from, sw.to, sw.res)
+		case ec := <-s.ensembleChangeOp:
+			s.changeEnsemble(ec.add, ec.remove, ec.failed, ec.res)
+
+		case f := <-s.fenceOp:
+			s.fenceForMerge(f.res)
+
+		case <-s.electLeaderOp:
+			s.electLeaderWithRetries()
 
 		case a := <-s.newTermAndAddFollowerOp:
 			s.internalNewTermAndAddFollower(a.ctx, a.node, a.res)
+
+		case lt := <-s.leaderTransferOp:
+			s.transferLeader(lt.to, lt.res)
 		}
 	}
 }
@@ -211,10 +341,155 @@ func (s *shardController) handleNodeFailure(failedNode model.ServerAddress) {
 			"Detected failure on shard leader",
 			slog.Any("leader", failedNode),
 		)
+
+		timer := s.recoveryLatency.Timer()
+		if s.waitForLeaderToReturn(failedNode) {
+			// Keep the leadership where it was, to not move the load of
+			// the restarted server to the other ones
+			s.preferredLeader = &failedNode
+		}
 		s.electLeaderWithRetries()
+		s.preferredLeader = nil
+		if s.ctx.Err() == nil {
+			timer.Done()
+		}
+	}
+}
+
+// Wait for the failed leader to be reachable again, up to the configured
+// timeout. It returns true if the leader came back in time.
+func (s *shardController) waitForLeaderToReturn(leader model.ServerAddress) bool {
+	timeout := s.coordinator.LeaderReturnTimeout()
+	if timeout <= 0 {
+		return false
+	}
+
+	s.log.Info(
+		"Waiting for the failed leader to return",
+		slog.Any("leader", leader),
+		slog.Duration("timeout", timeout),
+	)
+
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(leaderReturnCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.isServing(ctx, leader) {
+			s.log.Info(
+				"The failed leader has returned",
+				slog.Any("leader", leader),
+			)
+			return true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return false
+		}
 	}
 }
 
+func (s *shardController) isServing(ctx context.Context, server model.ServerAddress) bool {
+	health, err := s.rpc.GetHealthClient(server)
+	if err != nil {
+		return false
+	}
+
+	pingCtx, pingCancel := context.WithTimeout(ctx, healthCheckProbeTimeout)
+	defer pingCancel()
+	res, err := health.Check(pingCtx, &grpc_health_v1.HealthCheckRequest{Service: ""})
+	return err == nil && res.Status == grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// Ask the leader whether any of the followers stopped acknowledging entries,
+// and if so, replace it with a different server. The load reported by the
+// leader is sampled as well.
+func (s *shardController) checkFollowersHealth() {
+	leader := s.shardMetadata.Leader
+	if leader == nil || s.shardMetadata.Status != model.ShardStatusSteadyState {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, healthCheckProbeTimeout)
+	defer cancel()
+
+	res, err := s.rpc.GetStatus(ctx, *leader, &proto.GetStatusRequest{ShardId: s.shard})
+	if err != nil {
+		s.log.Debug(
+			"Failed to get the leader status",
+			slog.Any("error", err),
+			slog.Any("leader", leader),
+		)
+		return
+	}
+
+	if res.Term != s.shardMetadata.Term {
+		return
+	}
+
+	s.loadSampler.add(res.Term, res.Load, time.Now())
+
+	for _, follower := range res.UnhealthyFollowers {
+		var from *model.ServerAddress
+		for _, sa := range s.shardMetadata.Ensemble {
+			if sa.Internal == follower {
+				from = &sa
+				break
+			}
+		}
+
+		if from == nil {
+			continue
+		}
+
+		to, err := s.coordinator.SelectNewNode(s.namespace, s.shardMetadata.Ensemble, *from)
+		if errors.Is(err, ErrClusterFrozen) {
+			s.log.Debug(
+				"Follower is unhealthy, though it's not replaced while the cluster is frozen",
+				slog.Any("follower", from),
+			)
+			return
+		}
+		if err != nil {
+			s.log.Error(
+				"Follower is unhealthy but there is no server available to replace it",
+				slog.Any("error", err),
+				slog.Any("follower", from),
+			)
+			continue
+		}
+
+		s.log.Warn(
+			"Replacing unhealthy follower",
+			slog.Any("from", from),
+			slog.Any("to", to),
+		)
+		s.unhealthyFollowersReplaced.Inc()
+
+		// Replace one follower at a time, the next ones will be picked up
+		// in the following checks
+		swapRes := make(chan error, 1)
+		s.changeEnsemble(to, from, false, swapRes)
+		if err = <-swapRes; err != nil {
+			s.log.Warn(
+				"Failed to replace unhealthy follower",
+				slog.Any("error", err),
+				slog.Any("from", from),
+				slog.Any("to", to),
+			)
+		}
+		return
+	}
+}
+
+func (s *shardController) Load() (ShardLoad, bool) {
+	return s.loadSampler.get()
+}
+
 func (s *shardController) verifyCurrentEnsemble() bool {
 	// Ideally, we shouldn't need to trigger a new leader election if a follower
 	// is out of sync. We should just go back into the retry-to-fence follower
@@ -267,7 +542,17 @@ func (s *shardController) verifyCurrentEnsemble() bool {
 }
 
 func (s *shardController) electLeaderWithRetries() {
-	_ = backoff.RetryNotify(s.electLeader, common.NewBackOff(s.ctx),
+	s.retryingElection = true
+	defer func() { s.retryingElection = false }()
+
+	// The escalation of a stuck shard elects a leader on its own
+	escalated := false
+	_ = backoff.RetryNotify(func() error {
+		if escalated {
+			return nil
+		}
+		return s.electLeader()
+	}, common.NewBackOff(s.ctx),
 		func(err error, duration time.Duration) {
 			s.leaderElectionsFailed.Inc()
 			s.log.Warn(
@@ -275,11 +560,124 @@ func (s *shardController) electLeaderWithRetries() {
 				slog.Any("error", err),
 				slog.Duration("retry-after", duration),
 			)
+			escalated = s.escalateIfStuck()
 		})
 }
 
+// The shard is stuck when it has been without a leader for longer than the
+// stuck timeout.
+func (s *shardController) isStuck() bool {
+	timeout := s.coordinator.StuckElectionTimeout()
+
+	s.shardMetadataMutex.Lock()
+	defer s.shardMetadataMutex.Unlock()
+	return timeout > 0 && !s.electionStartedAt.IsZero() && time.Since(s.electionStartedAt) > timeout
+}
+
+func (s *shardController) recordElectionFailure(server model.ServerAddress) {
+	if s.electionFailures == nil {
+		s.electionFailures = map[model.ServerAddress]int{}
+	}
+	s.electionFailures[server]++
+}
+
+// Once the shard is stuck, replace the member of the ensemble that failed
+// the most in the election rounds with a different server. This is repeated
+// at most once per stuck timeout, until a leader is elected. It returns
+// whether the ensemble change has elected a leader.
+func (s *shardController) escalateIfStuck() bool {
+	if !s.isStuck() || time.Since(s.lastEscalation) < s.coordinator.StuckElectionTimeout() {
+		return false
+	}
+	s.lastEscalation = time.Now()
+
+	// The server addresses cannot be used as json keys in the logs
+	failures := make(map[string]int, len(s.electionFailures))
+	for sa, count := range s.electionFailures {
+		failures[sa.Internal] = count
+	}
+	s.log.Warn(
+		"Shard is stuck without a leader",
+		slog.Time("since", s.electionStartedAt),
+		slog.Any("failures", failures),
+	)
+
+	from, ok := s.selectFailingMember()
+	if !ok {
+		s.log.Warn("No member of the ensemble can be safely replaced, keep retrying")
+		return false
+	}
+
+	to, err := s.coordinator.SelectNewNode(s.namespace, s.shardMetadata.Ensemble, from)
+	if err != nil {
+		s.log.Warn(
+			"Failed to select a server to replace the failing member of the ensemble",
+			slog.Any("error", err),
+			slog.Any("member", from),
+		)
+		return false
+	}
+
+	// A member that is still reachable is fenced and then cleaned up along
+	// with the other removed nodes, while one that is gone is just dropped
+	failed := !s.isServing(s.ctx, from)
+
+	s.log.Warn(
+		"Replacing the failing member of the ensemble of the stuck shard",
+		slog.Any("from", from),
+		slog.Any("to", to),
+		slog.Bool("failed", failed),
+	)
+	s.stuckShardEscalations.Inc()
+	delete(s.electionFailures, from)
+
+	res := make(chan error, 1)
+	s.changeEnsemble(to, &from, failed, res)
+	if err = <-res; err != nil {
+		s.log.Warn(
+			"Failed to replace the failing member of the ensemble of the stuck shard",
+			slog.Any("error", err),
+			slog.Any("from", from),
+			slog.Any("to", to),
+		)
+	}
+
+	return s.shardMetadata.Status == model.ShardStatusSteadyState
+}
+
+// Pick the member of the ensemble with the most failures. It's only replaced
+// if all the other members are reachable, so that the next election still
+// finds all the committed entries.
+func (s *shardController) selectFailingMember() (model.ServerAddress, bool) {
+	var candidate *model.ServerAddress
+	for _, sa := range s.shardMetadata.Ensemble {
+		failures := s.electionFailures[sa]
+		if failures > 0 && (candidate == nil || failures > s.electionFailures[*candidate] ||
+			(failures == s.electionFailures[*candidate] && sa.Internal < candidate.Internal)) {
+			candidate = &sa
+		}
+	}
+	if candidate == nil {
+		return model.ServerAddress{}, false
+	}
+
+	for _, sa := range s.shardMetadata.Ensemble {
+		if sa != *candidate && !s.isServing(s.ctx, sa) {
+			return model.ServerAddress{}, false
+		}
+	}
+	return *candidate, true
+}
+
 func (s *shardController) electLeader() error {
+	// Queue the round if the servers are already involved in many elections
+	if err := s.coordinator.WaitForElectionRound(s.ctx,
+		mergeLists(s.shardMetadata.Ensemble, s.shardMetadata.RemovedNodes)); err != nil {
+		return err
+	}
+
 	timer := s.leaderElectionLatency.Timer()
+	s.leaderElections.Inc()
 
 	if s.currentElectionCancel != nil {
 		// Cancel any pending activity from the previous election
@@ -292,6 +690,9 @@ func (s *shardController) electLeader() error {
 	s.shardMetadata.Status = model.ShardStatusElection
 	s.shardMetadata.Leader = nil
 	s.shardMetadata.Term++
+	if s.electionStartedAt.IsZero() {
+		s.electionStartedAt = time.Now()
+	}
 	s.shardMetadataMutex.Unlock()
 
 	s.log.Info(
@@ -304,12 +705,12 @@ func (s *shardController) electLeader() error {
 	}
 
 	// Send NewTerm to all the ensemble members
-	fr, err := s.newTermQuorum()
+	fr, err := s.newTermQuorum(false)
 	if err != nil {
 		return err
 	}
 
-	newLeader, followers := s.selectNewLeader(fr)
+	newLeader, followers := s.selectNewLeader(headEntryIds(fr))
 
 	if s.log.Enabled(context.Background(), slog.LevelInfo) {
 		f := make([]struct {
@@ -331,6 +732,7 @@ func (s *shardController) electLeader() error {
 	}
 
 	if err = s.becomeLeader(newLeader, followers); err != nil {
+		s.recordElectionFailure(newLeader)
 		return err
 	}
 
@@ -352,7 +754,10 @@ func (s *shardController) electLeader() error {
 
 	s.shardMetadataMutex.Lock()
 	s.shardMetadata = metadata
+	s.electionStartedAt = time.Time{}
 	s.shardMetadataMutex.Unlock()
+	s.electionFailures = nil
+	s.lastEscalation = time.Time{}
 
 	s.log.Info(
 		"Elected new leader",
@@ -463,7 +868,7 @@ func (s *shardController) newTermAndAddFollower(ctx context.Context, node model.
 }
 
 func (s *shardController) internalNewTermAndAddFollower(ctx context.Context, node model.ServerAddress, res chan error) {
-	fr, err := s.newTerm(ctx, node)
+	fr, err := s.newTerm(ctx, node, false)
 	if err != nil {
 		res <- err
 		return
@@ -476,8 +881,8 @@ func (s *shardController) internalNewTermAndAddFollower(ctx context.Context, nod
 	}
 
 	if err = s.addFollower(*s.shardMetadata.Leader, node.Internal, &proto.EntryId{
-		Term:   fr.Term,
-		Offset: fr.Offset,
+		Term:   fr.HeadEntryId.Term,
+		Offset: fr.HeadEntryId.Offset,
 	}); err != nil {
 		res <- err
 		return
@@ -486,7 +891,7 @@ func (s *shardController) internalNewTermAndAddFollower(ctx context.Context, nod
 	s.log.Info(
 		"Successfully rejoined the quorum",
 		slog.Any("follower", node),
-		slog.Int64("term", fr.Term),
+		slog.Int64("term", fr.HeadEntryId.Term),
 	)
 
 	res <- nil
@@ -494,8 +899,9 @@ func (s *shardController) internalNewTermAndAddFollower(ctx context.Context, nod
 
 // Send NewTerm to all the ensemble members in parallel and wait for
 // a majority of them to reply successfully.
-func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryId, error) { //nolint:revive
+func (s *shardController) newTermQuorum(checkMergeable bool) (map[model.ServerAddress]*proto.NewTermResponse, error) { //nolint:revive
 	timer := s.newTermQuorumLatency.Timer()
+	s.fencingRounds.Inc()
 
 	fencingQuorum := mergeLists(s.shardMetadata.Ensemble, s.shardMetadata.RemovedNodes)
 	fencingQuorumSize := len(fencingQuorum)
@@ -508,7 +914,7 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 	// Channel to receive responses or errors from each server
 	ch := make(chan struct {
 		model.ServerAddress
-		*proto.EntryId
+		*proto.NewTermResponse
 		error
 	}, fencingQuorumSize)
 
@@ -522,7 +928,7 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 				"shard": fmt.Sprintf("%d", s.shard),
 				"node":  sa.Internal,
 			}, func() {
-				entryId, err := s.newTerm(ctx, serverAddress)
+				newTermResponse, err := s.newTerm(ctx, serverAddress, checkMergeable)
 				if err != nil {
 					s.log.Warn(
 						"Failed to newTerm node",
@@ -533,15 +939,15 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 					s.log.Info(
 						"Processed newTerm response",
 						slog.Any("server-address", serverAddress),
-						slog.Any("entry-id", entryId),
+						slog.Any("entry-id", newTermResponse.GetHeadEntryId()),
 					)
 				}
 
 				ch <- struct {
 					model.ServerAddress
-					*proto.EntryId
+					*proto.NewTermResponse
 					error
-				}{serverAddress, entryId, err}
+				}{serverAddress, newTermResponse, err}
 			},
 		)
 	}
@@ -549,9 +955,18 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 	successResponses := 0
 	totalResponses := 0
 
-	res := make(map[model.ServerAddress]*proto.EntryId)
+	res := make(map[model.ServerAddress]*proto.NewTermResponse)
 	var err error
 
+	// The removed nodes are part of the fencing quorum, though we don't
+	// consider them as candidates for leader/followers, since they're not
+	// counted in the replication factor
+	addResponse := func(sa model.ServerAddress, r *proto.NewTermResponse) {
+		if listContains(s.shardMetadata.Ensemble, sa) {
+			res[sa] = r
+		}
+	}
+
 	// Wait for a majority to respond
 	for successResponses < majority && totalResponses < fencingQuorumSize {
 		r := <-ch
@@ -559,13 +974,10 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 		totalResponses++
 		if r.error == nil {
 			successResponses++
-
-			// We don't consider the removed nodes as candidates for leader/followers
-			if listContains(s.shardMetadata.Ensemble, r.ServerAddress) {
-				res[r.ServerAddress] = r.EntryId
-			}
+			addResponse(r.ServerAddress, r.NewTermResponse)
 		} else {
 			err = multierr.Append(err, r.error)
+			s.recordElectionFailure(r.ServerAddress)
 		}
 	}
 
@@ -580,9 +992,10 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 		case r := <-ch:
 			totalResponses++
 			if r.error == nil {
-				res[r.ServerAddress] = r.EntryId
+				addResponse(r.ServerAddress, r.NewTermResponse)
 			} else {
 				err = multierr.Append(err, r.error)
+				s.recordElectionFailure(r.ServerAddress)
 			}
 
 		case <-time.After(quorumFencingGracePeriod):
@@ -595,17 +1008,49 @@ func (s *shardController) newTermQuorum() (map[model.ServerAddress]*proto.EntryI
 	return res, nil
 }
 
-func (s *shardController) newTerm(ctx context.Context, node model.ServerAddress) (*proto.EntryId, error) {
-	res, err := s.rpc.NewTerm(ctx, node, &proto.NewTermRequest{
-		Namespace: s.namespace,
-		ShardId:   s.shard,
-		Term:      s.shardMetadata.Term,
+func (s *shardController) newTerm(ctx context.Context, node model.ServerAddress, checkMergeable bool) (*proto.NewTermResponse, error) {
+	return s.rpc.NewTerm(ctx, node, &proto.NewTermRequest{
+		Namespace:      s.namespace,
+		ShardId:        s.shard,
+		Term:           s.shardMetadata.Term,
+		CheckMergeable: checkMergeable,
 	})
-	if err != nil {
-		return nil, err
+}
+
+func headEntryIds(newTermResponses map[model.ServerAddress]*proto.NewTermResponse) map[model.ServerAddress]*proto.EntryId {
+	res := make(map[model.ServerAddress]*proto.EntryId, len(newTermResponses))
+	for sa, r := range newTermResponses {
+		res[sa] = r.HeadEntryId
+	}
+	return res
+}
+
+// mergeableReplicas returns the members whose records can be exported to
+// merge the shard, from the responses to NewTerm. The replicas with the
+// highest entry in the wal hold all the committed entries and one of them
+// would become the leader, so their records are the ones of the shard, once
+// they have applied their whole wal.
+func mergeableReplicas(newTermResponses map[model.ServerAddress]*proto.NewTermResponse) []model.ServerAddress {
+	var currentMax int64 = -1
+	var res []model.ServerAddress
+
+	for sa, r := range newTermResponses {
+		switch {
+		case r.HeadEntryId.Offset < currentMax:
+			continue
+		case r.HeadEntryId.Offset > currentMax:
+			currentMax = r.HeadEntryId.Offset
+			res = nil
+		}
+		if r.Mergeable {
+			res = append(res, sa)
+		}
 	}
 
-	return res.HeadEntryId, nil
+	slices.SortFunc(res, func(a, b model.ServerAddress) int {
+		return strings.Compare(a.Internal, b.Internal)
+	})
+	return res
 }
 
 func (s *shardController) deleteShardRpc(ctx context.Context, node model.ServerAddress) error {
@@ -618,7 +1063,7 @@ func (s *shardController) deleteShardRpc(ctx context.Context, node model.ServerA
 	return err
 }
 
-func (*shardController) selectNewLeader(newTermResponses map[model.ServerAddress]*proto.EntryId) (
+func (s *shardController) selectNewLeader(newTermResponses map[model.ServerAddress]*proto.EntryId) (
 	leader model.ServerAddress, followers map[model.ServerAddress]*proto.EntryId) {
 	// Select all the nodes that have the highest entry in the wal
 	var currentMax int64 = -1
@@ -637,8 +1082,23 @@ func (*shardController) selectNewLeader(newTermResponses map[model.ServerAddress
 		}
 	}
 
-	// Select a random leader among the nodes with the highest entry in the wal
+	// The drained servers are left out, unless they are the only candidates
+	var notDraining []model.ServerAddress
+	for _, sa := range candidates {
+		if !s.coordinator.IsDraining(sa) {
+			notDraining = append(notDraining, sa)
+		}
+	}
+	if len(notDraining) > 0 {
+		candidates = notDraining
+	}
+
+	// Select a random leader among the nodes with the highest entry in the wal,
+	// unless a specific leader was requested
 	leader = candidates[rand.Intn(len(candidates))] //nolint:gosec
+	if s.preferredLeader != nil && listContains(candidates, *s.preferredLeader) {
+		leader = *s.preferredLeader
+	}
 	followers = make(map[model.ServerAddress]*proto.EntryId)
 	for a, e := range newTermResponses {
 		if a != leader {
@@ -662,6 +1122,7 @@ func (s *shardController) becomeLeader(leader model.ServerAddress, followers map
 		Term:              s.shardMetadata.Term,
 		ReplicationFactor: uint32(len(s.shardMetadata.Ensemble)),
 		FollowerMaps:      followersMap,
+		ReplicationMode:   toProtoReplicationMode(s.shardMetadata.ReplicationMode),
 	}); err != nil {
 		return err
 	}
@@ -684,6 +1145,72 @@ func (s *shardController) addFollower(leader model.ServerAddress, follower strin
 	return nil
 }
 
+func (s *shardController) FenceForMerge() (int64, []model.ServerAddress, error) {
+	res := make(chan fenceResult, 1)
+	select {
+	case s.fenceOp <- fenceRequest{res: res}:
+	case <-s.ctx.Done():
+		return 0, nil, s.ctx.Err()
+	}
+
+	select {
+	case r := <-res:
+		return r.term, r.replicas, r.err
+	case <-s.ctx.Done():
+		return 0, nil, s.ctx.Err()
+	}
+}
+
+func (s *shardController) fenceForMerge(res chan fenceResult) {
+	s.log.Info("Fencing shard to merge it")
+
+	s.shardMetadataMutex.Lock()
+	s.shardMetadata.Status = model.ShardStatusElection
+	s.shardMetadata.Leader = nil
+	s.shardMetadata.Term++
+	s.shardMetadataMutex.Unlock()
+
+	if err := s.coordinator.InitiateLeaderElection(s.namespace, s.shard, s.shardMetadata); err != nil {
+		s.electLeaderWithRetries()
+		res <- fenceResult{err: err}
+		return
+	}
+
+	// Once the ensemble is in the new term, no more entries can be
+	// written in the shard
+	fr, err := s.newTermQuorum(true)
+	if err != nil {
+		s.electLeaderWithRetries()
+		res <- fenceResult{err: err}
+		return
+	}
+
+	replicas := mergeableReplicas(fr)
+	if len(replicas) == 0 {
+		s.log.Info(
+			"Shard cannot be merged, electing a new leader",
+			slog.Any("head-entry-ids", headEntryIds(fr)),
+		)
+		s.electLeaderWithRetries()
+		res <- fenceResult{err: ErrShardNotMergeable}
+		return
+	}
+
+	s.log.Info(
+		"Fenced shard to merge it",
+		slog.Int64("term", s.shardMetadata.Term),
+		slog.Any("replicas", replicas),
+	)
+	res <- fenceResult{term: s.shardMetadata.Term, replicas: replicas}
+}
+
+func (s *shardController) ElectLeader() {
+	select {
+	case s.electLeaderOp <- nil:
+	case <-s.ctx.Done():
+	}
+}
+
 func (s *shardController) DeleteShard() {
 	s.deleteOp <- nil
 }
@@ -721,6 +1248,21 @@ func (s *shardController) deleteShard() error {
 		)
 	}
 
+	// The nodes that were removed from the ensemble might still have a copy
+	// of the data. They might also be gone for good, so we don't insist on them.
+	for _, sa := range s.shardMetadata.RemovedNodes {
+		ctx, cancel := context.WithTimeout(s.ctx, rpcTimeout)
+		err := s.deleteShardRpc(ctx, sa)
+		cancel()
+		if err != nil {
+			s.log.Warn(
+				"Failed to delete shard from removed node",
+				slog.Any("error", err),
+				slog.String("node", sa.Internal),
+			)
+		}
+	}
+
 	s.log.Info("Successfully deleted shard from all the nodes")
 	return multierr.Combine(
 		s.coordinator.ShardDeleted(s.namespace, s.shard),
@@ -749,35 +1291,75 @@ func (s *shardController) Status() model.ShardStatus {
 func (s *shardController) Close() error {
 	s.cancel()
 	s.termGauge.Unregister()
+	s.stuckGauge.Unregister()
 	return nil
 }
 
 func (s *shardController) SwapNode(from model.ServerAddress, to model.ServerAddress) error {
+	return s.requestEnsembleChange(&to, &from, false)
+}
+
+func (s *shardController) ReplaceNode(from model.ServerAddress, to model.ServerAddress) error {
+	return s.requestEnsembleChange(&to, &from, true)
+}
+
+func (s *shardController) AddNode(node model.ServerAddress) error {
+	return s.requestEnsembleChange(&node, nil, false)
+}
+
+func (s *shardController) RemoveNode(node model.ServerAddress) error {
+	return s.requestEnsembleChange(nil, &node, false)
+}
+
+func (s *shardController) requestEnsembleChange(add *model.ServerAddress, remove *model.ServerAddress, failed bool) error {
 	res := make(chan error)
-	s.swapNodeOp <- swapNodeRequest{
-		from: from,
-		to:   to,
-		res:  res,
+	s.ensembleChangeOp <- ensembleChangeRequest{
+		add:    add,
+		remove: remove,
+		failed: failed,
+		res:    res,
 	}
 
 	return <-res
 }
 
-func (s *shardController) swapNode(from model.ServerAddress, to model.ServerAddress, res chan error) {
+func (s *shardController) changeEnsemble(add *model.ServerAddress, remove *model.ServerAddress, failed bool, res chan error) {
 	s.shardMetadataMutex.Lock()
-	s.shardMetadata.RemovedNodes = append(s.shardMetadata.RemovedNodes, from)
-	s.shardMetadata.Ensemble = replaceInList(s.shardMetadata.Ensemble, from, to)
+	if (add != nil && listContains(s.shardMetadata.Ensemble, *add)) ||
+		(remove != nil && !listContains(s.shardMetadata.Ensemble, *remove)) {
+		s.shardMetadataMutex.Unlock()
+		res <- ErrInvalidEnsembleChange
+		return
+	}
+
+	switch {
+	case add != nil && remove != nil:
+		if !failed {
+			s.shardMetadata.RemovedNodes = append(s.shardMetadata.RemovedNodes, *remove)
+		}
+		s.shardMetadata.Ensemble = replaceInList(s.shardMetadata.Ensemble, *remove, *add)
+	case add != nil:
+		s.shardMetadata.Ensemble = append(s.shardMetadata.Ensemble, *add)
+	case remove != nil:
+		s.shardMetadata.RemovedNodes = append(s.shardMetadata.RemovedNodes, *remove)
+		s.shardMetadata.Ensemble = removeFromList(s.shardMetadata.Ensemble, *remove)
+	}
 	s.shardMetadataMutex.Unlock()
 
 	s.log.Info(
-		"Swapping node",
+		"Changing ensemble",
 		slog.Any("removed-nodes", s.shardMetadata.RemovedNodes),
 		slog.Any("new-ensemble", s.shardMetadata.Ensemble),
-		slog.Any("from", from),
-		slog.Any("to", to),
+		slog.Any("added", add),
+		slog.Any("removed", remove),
 	)
 	if err := s.electLeader(); err != nil {
 		res <- err
+		// Don't leave the shard without a leader, unless the change was
+		// made while the election is already being retried
+		if !s.retryingElection {
+			s.electLeaderWithRetries()
+		}
 		return
 	}
 
@@ -798,13 +1380,73 @@ func (s *shardController) swapNode(from model.ServerAddress, to model.ServerAddr
 	}
 
 	s.log.Info(
-		"Successfully swapped node",
-		slog.Any("from", from),
-		slog.Any("to", to),
+		"Successfully changed ensemble",
+		slog.Any("added", add),
+		slog.Any("removed", remove),
 	)
 	res <- nil
 }
 
+func (s *shardController) TransferLeader(to model.ServerAddress) error {
+	res := make(chan error, 1)
+	s.leaderTransferOp <- leaderTransferRequest{
+		to:  to,
+		res: res,
+	}
+
+	select {
+	case err := <-res:
+		return err
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *shardController) transferLeader(to model.ServerAddress, res chan error) {
+	s.shardMetadataMutex.Lock()
+	leader := s.shardMetadata.Leader
+	valid := s.shardMetadata.Status == model.ShardStatusSteadyState && leader != nil &&
+		listContains(s.shardMetadata.Ensemble, to)
+	s.shardMetadataMutex.Unlock()
+
+	if !valid {
+		res <- ErrInvalidLeaderTransfer
+		return
+	}
+	if *leader == to {
+		res <- nil
+		return
+	}
+
+	// The new leader must have all the entries that the current
+	// leader has, otherwise it would not be picked in the election
+	if err := s.waitForFollowersToCatchUp(s.ctx, *leader, []model.ServerAddress{to}); err != nil {
+		res <- err
+		return
+	}
+
+	s.log.Info(
+		"Transferring the shard leadership",
+		slog.Any("current-leader", leader),
+		slog.Any("new-leader", to),
+	)
+
+	s.preferredLeader = &to
+	err := s.electLeader()
+	s.preferredLeader = nil
+	if err != nil {
+		res <- err
+		return
+	}
+
+	if *s.shardMetadata.Leader != to {
+		// New entries were written before the ensemble was fenced
+		res <- errors.Wrapf(ErrLeaderNotTransferred, "%s was elected", s.shardMetadata.Leader.Internal)
+		return
+	}
+	res <- nil
+}
+
 func (s *shardController) isFollowerCatchUp(ctx context.Context, server model.ServerAddress, leaderHeadOffset int64) error {
 	fs, err := s.rpc.GetStatus(ctx, server, &proto.GetStatusRequest{ShardId: s.shard})
 	if err != nil {
@@ -860,6 +1502,13 @@ func (s *shardController) waitForFollowersToCatchUp(ctx context.Context, leader
 	return nil
 }
 
+func toProtoReplicationMode(mode model.ReplicationMode) proto.ReplicationMode {
+	if mode == model.ReplicationModeRelaxed {
+		return proto.ReplicationMode_RELAXED
+	}
+	return proto.ReplicationMode_SYNC
+}
+
 func listContains(list []model.ServerAddress, sa model.ServerAddress) bool {
 	for _, item := range list {
 		if item.Public == sa.Public && item.Internal == sa.Internal {
@@ -878,6 +1527,16 @@ func mergeLists[T any](lists ...[]T) []T {
 	return res
 }
 
+func removeFromList(list []model.ServerAddress, sa model.ServerAddress) []model.ServerAddress {
+	var res []model.ServerAddress
+	for _, item := range list {
+		if item.Public != sa.Public || item.Internal != sa.Internal {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
 func replaceInList(list []model.ServerAddress, oldServerAddress, newServerAddress model.ServerAddress) []model.ServerAddress {
 	var res []model.ServerAddress
 	for _, item := range list {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/coordinator/model"
@@ -289,14 +290,310 @@ func TestShardController_VerifyFollowersWereAllFenced(t *testing.T) {
 	assert.NoError(t, sc.Close())
 }
 
+func TestShardController_RelaxedReplicationMode(t *testing.T) {
+	var shard int64 = 5
+	rpc := newMockRpcProvider()
+	coordinator := newMockCoordinator()
+
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+
+	sc := NewShardController(common.DefaultNamespace, shard, model.ShardMetadata{
+		Status:          model.ShardStatusUnknown,
+		Term:            1,
+		Leader:          nil,
+		Ensemble:        []model.ServerAddress{s1, s2, s3},
+		ReplicationMode: model.ReplicationModeRelaxed,
+	}, rpc, coordinator)
+
+	rpc.GetNode(s1).NewTermResponse(1, 0, nil)
+	rpc.GetNode(s2).NewTermResponse(1, -1, nil)
+	rpc.GetNode(s3).NewTermResponse(1, -1, nil)
+
+	rpc.GetNode(s1).BecomeLeaderResponse(nil)
+
+	// The replication mode of the shard is passed to the leader
+	r := <-rpc.GetNode(s1).becomeLeaderRequests
+	assert.EqualValues(t, 2, r.Term)
+	assert.Equal(t, proto.ReplicationMode_RELAXED, r.ReplicationMode)
+
+	assert.Eventually(t, func() bool {
+		return sc.Status() == model.ShardStatusSteadyState
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, sc.Close())
+}
+
+func TestShardController_RemovedNodesAreNotFollowers(t *testing.T) {
+	var shard int64 = 5
+	rpc := newMockRpcProvider()
+	coordinator := newMockCoordinator()
+
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+
+	rpc.GetNode(s1).NewTermResponse(1, 1, nil)
+	rpc.GetNode(s2).NewTermResponse(1, 0, nil)
+	rpc.GetNode(s1).BecomeLeaderResponse(nil)
+	rpc.GetNode(s3).DeleteShardResponse(nil)
+
+	sc := NewShardController(common.DefaultNamespace, shard, model.ShardMetadata{
+		Status:       model.ShardStatusUnknown,
+		Term:         1,
+		Leader:       nil,
+		Ensemble:     []model.ServerAddress{s1, s2},
+		RemovedNodes: []model.ServerAddress{s3},
+	}, rpc, coordinator)
+
+	// s3 is still fenced, though it's not part of the ensemble anymore. It
+	// responds after the majority of the fencing quorum
+	rpc.GetNode(s3).expectNewTermRequest(t, shard, 2)
+	time.Sleep(50 * time.Millisecond)
+	rpc.GetNode(s3).NewTermResponse(1, 2, nil)
+
+	r := <-rpc.GetNode(s1).becomeLeaderRequests
+	assert.EqualValues(t, 2, r.ReplicationFactor)
+	assert.Len(t, r.FollowerMaps, 1)
+	assert.Contains(t, r.FollowerMaps, s2.Internal)
+
+	// The shard is then deleted from the removed node
+	select {
+	case req := <-rpc.GetNode(s3).deleteShardRequests:
+		assert.EqualValues(t, shard, req.ShardId)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "expected delete shard request")
+	}
+
+	select {
+	case elected := <-coordinator.(*mockCoordinator).electedLeaders:
+		assert.Equal(t, s1, *elected.metadata.Leader)
+		assert.Nil(t, elected.metadata.RemovedNodes)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "expected elected leader")
+	}
+
+	assert.NoError(t, sc.Close())
+}
+
+func TestShardController_ReplaceUnhealthyFollower(t *testing.T) {
+	var shard int64 = 5
+	rpc := newMockRpcProvider()
+	coordinator := newMockCoordinator()
+
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+	s4 := model.ServerAddress{Public: "s4:9091", Internal: "s4:8191"}
+	coordinator.(*mockCoordinator).newNode = &s4
+
+	sc := newShardController(common.DefaultNamespace, shard, model.ShardMetadata{
+		Status:   model.ShardStatusUnknown,
+		Term:     1,
+		Leader:   nil,
+		Ensemble: []model.ServerAddress{s1, s2, s3},
+	}, rpc, coordinator, 100*time.Millisecond)
+
+	rpc.GetNode(s1).NewTermResponse(1, 0, nil)
+	rpc.GetNode(s2).NewTermResponse(1, -1, nil)
+	rpc.GetNode(s3).NewTermResponse(1, -1, nil)
+	rpc.GetNode(s1).BecomeLeaderResponse(nil)
+
+	rpc.GetNode(s1).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s2).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s3).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s1).expectBecomeLeaderRequest(t, shard, 2, 3)
+
+	assert.Eventually(t, func() bool {
+		return sc.Status() == model.ShardStatusSteadyState
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// The leader reports s3 as not acknowledging entries
+	n1 := rpc.GetNode(s1)
+	n1.getStatusResponses <- struct {
+		*proto.GetStatusResponse
+		error
+	}{&proto.GetStatusResponse{
+		Term:               2,
+		Status:             proto.ServingStatus_LEADER,
+		UnhealthyFollowers: []string{s3.Internal},
+	}, nil}
+
+	r := <-n1.getStatusRequests
+	assert.EqualValues(t, shard, r.ShardId)
+
+	// s3 gets swapped with s4, which triggers a new election including
+	// the removed node
+	rpc.GetNode(s4).expectNewTermRequest(t, shard, 3)
+	rpc.GetNode(s3).expectNewTermRequest(t, shard, 3)
+
+	assert.NoError(t, sc.Close())
+}
+
+func TestShardController_WaitForLeaderToReturn(t *testing.T) {
+	var shard int64 = 5
+	rpc := newMockRpcProvider()
+	coordinator := newMockCoordinator()
+	coordinator.(*mockCoordinator).leaderReturnTimeout = 10 * time.Second
+
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+
+	sc := NewShardController(common.DefaultNamespace, shard, model.ShardMetadata{
+		Status:   model.ShardStatusUnknown,
+		Term:     1,
+		Leader:   nil,
+		Ensemble: []model.ServerAddress{s1, s2, s3},
+	}, rpc, coordinator)
+
+	rpc.GetNode(s1).NewTermResponse(1, 0, nil)
+	rpc.GetNode(s2).NewTermResponse(1, -1, nil)
+	rpc.GetNode(s3).NewTermResponse(1, -1, nil)
+	rpc.GetNode(s1).BecomeLeaderResponse(nil)
+
+	rpc.GetNode(s1).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s2).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s3).expectNewTermRequest(t, shard, 2)
+	rpc.GetNode(s1).expectBecomeLeaderRequest(t, shard, 2, 3)
+
+	assert.Eventually(t, func() bool {
+		return sc.Status() == model.ShardStatusSteadyState
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// The leader restarts
+	rpc.GetNode(s1).healthClient.SetError(errors.New("failed to connect"))
+	sc.HandleNodeFailure(s1)
+
+	// No election is started while waiting for the leader to return
+	assert.Never(t, func() bool {
+		return len(rpc.GetNode(s2).newTermRequests) > 0
+	}, 1*time.Second, 100*time.Millisecond)
+
+	// All the members have the same entries, and the returned leader
+	// is preferred
+	rpc.GetNode(s1).NewTermResponse(2, 0, nil)
+	rpc.GetNode(s2).NewTermResponse(2, 0, nil)
+	rpc.GetNode(s3).NewTermResponse(2, 0, nil)
+	rpc.GetNode(s1).BecomeLeaderResponse(nil)
+	rpc.GetNode(s1).healthClient.SetStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+
+	rpc.GetNode(s1).expectNewTermRequest(t, shard, 3)
+	rpc.GetNode(s2).expectNewTermRequest(t, shard, 3)
+	rpc.GetNode(s3).expectNewTermRequest(t, shard, 3)
+	rpc.GetNode(s1).expectBecomeLeaderRequest(t, shard, 3, 3)
+
+	assert.Eventually(t, func() bool {
+		return sc.Status() == model.ShardStatusSteadyState
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, s1, *sc.Leader())
+
+	assert.NoError(t, sc.Close())
+}
+
+func TestShardController_ReplaceFailingMemberOfStuckShard(t *testing.T) {
+	var shard int64 = 5
+	rpc := newMockRpcProvider()
+	coordinator := newMockCoordinator()
+	coordinator.(*mockCoordinator).stuckElectionTimeout = 1 * time.Second
+
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+	s4 := model.ServerAddress{Public: "s4:9091", Internal: "s4:8191"}
+	coordinator.(*mockCoordinator).newNode = &s4
+
+	// s1 has the most entries, though it keeps failing to become leader
+	for i := 0; i < 30; i++ {
+		rpc.GetNode(s1).NewTermResponse(1, 5, nil)
+		rpc.GetNode(s2).NewTermResponse(1, 0, nil)
+		rpc.GetNode(s3).NewTermResponse(1, 0, nil)
+		rpc.GetNode(s1).BecomeLeaderResponse(errors.New("failed to open the wal"))
+	}
+	rpc.GetNode(s4).NewTermResponse(-1, -1, nil)
+	rpc.GetNode(s2).BecomeLeaderResponse(nil)
+	rpc.GetNode(s3).BecomeLeaderResponse(nil)
+	rpc.GetNode(s1).DeleteShardResponse(nil)
+
+	sc := NewShardController(common.DefaultNamespace, shard, model.ShardMetadata{
+		Status:   model.ShardStatusUnknown,
+		Term:     1,
+		Leader:   nil,
+		Ensemble: []model.ServerAddress{s1, s2, s3},
+	}, rpc, coordinator)
+
+	assert.Eventually(t, func() bool {
+		return sc.(*shardController).isStuck()
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Once stuck, s1 is replaced by s4
+	assert.Eventually(t, func() bool {
+		return sc.Status() == model.ShardStatusSteadyState
+	}, 10*time.Second, 100*time.Millisecond)
+
+	var elected sCoordinatorEvents
+	select {
+	case elected = <-coordinator.(*mockCoordinator).electedLeaders:
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "no leader was elected")
+	}
+	assert.ElementsMatch(t, []model.ServerAddress{s2, s3, s4}, elected.metadata.Ensemble)
+	assert.Contains(t, []model.ServerAddress{s2, s3}, *elected.metadata.Leader)
+	assert.Empty(t, elected.metadata.RemovedNodes)
+	assert.False(t, sc.(*shardController).isStuck())
+
+	// The replaced member is still reachable, so its copy of the shard is
+	// deleted once the new leader is elected
+	select {
+	case req := <-rpc.GetNode(s1).deleteShardRequests:
+		assert.Equal(t, shard, req.ShardId)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "the replaced member was not cleaned up")
+	}
+
+	assert.NoError(t, sc.Close())
+}
+
 type sCoordinatorEvents struct {
 	shard    int64
 	metadata model.ShardMetadata
 }
 
+func TestShardController_SelectNewLeaderSkipsDrainedServers(t *testing.T) {
+	s1 := model.ServerAddress{Public: "s1:9091", Internal: "s1:8191"}
+	s2 := model.ServerAddress{Public: "s2:9091", Internal: "s2:8191"}
+	s3 := model.ServerAddress{Public: "s3:9091", Internal: "s3:8191"}
+
+	coordinator := newMockCoordinator().(*mockCoordinator)
+	coordinator.draining = []model.ServerAddress{s1, s2}
+	sc := &shardController{coordinator: coordinator}
+
+	for i := 0; i < 10; i++ {
+		leader, followers := sc.selectNewLeader(map[model.ServerAddress]*proto.EntryId{
+			s1: {Term: 1, Offset: 5},
+			s2: {Term: 1, Offset: 5},
+			s3: {Term: 1, Offset: 5},
+		})
+		assert.Equal(t, s3, leader)
+		assert.Len(t, followers, 2)
+	}
+
+	// A drained server is still elected when it's the only one with all the entries
+	leader, _ := sc.selectNewLeader(map[model.ServerAddress]*proto.EntryId{
+		s1: {Term: 1, Offset: 5},
+		s3: {Term: 1, Offset: 4},
+	})
+	assert.Equal(t, s1, leader)
+}
+
 type mockCoordinator struct {
 	sync.Mutex
 	err                      error
+	newNode                  *model.ServerAddress
+	leaderReturnTimeout      time.Duration
+	stuckElectionTimeout     time.Duration
+	draining                 []model.ServerAddress
 	initiatedLeaderElections chan sCoordinatorEvents
 	electedLeaders           chan sCoordinatorEvents
 }
@@ -356,3 +653,130 @@ func (m *mockCoordinator) ShardDeleted(namespace string, shard int64) error {
 func (m *mockCoordinator) NodeBecameUnavailable(node model.ServerAddress) {
 	panic("not implemented")
 }
+
+func (m *mockCoordinator) MergeShard(namespace string, shard int64, into int64) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) CreateNamespace(nc model.NamespaceConfig) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) DeleteNamespace(namespace string) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) DecommissionServer(server string) (model.DecommissionState, int, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) RebalanceCluster(dryRun bool) ([]SwapNodeAction, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) PlanRebalance() (*RebalancePlan, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ExecuteRebalancePlan(int64) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) CurrentRebalancePlan() (*RebalancePlan, bool) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) SetServerDrain(server string, drain bool) (int, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ReplaceServer(string, string) (int, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListServers() []ServerInfo {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) TransferLeader(string, int64, string) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) SetClusterFreeze(bool, string) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) SetKeyPrefixACL(string, model.KeyPrefixACL) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) RemoveKeyPrefixACL(string, string, string) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListKeyPrefixACLs(string) ([]model.KeyPrefixACL, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListEvents() []Event {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) WatchEvents(bool) ([]Event, <-chan Event, func()) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) Audit(AuditRecord) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListAuditRecords(int) ([]AuditRecord, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) State() CoordinatorState {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListOperations() []Operation {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ListSessions(string, *int64) ([]SessionInfo, error) {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) ForceCloseSession(string, int64, int64) error {
+	panic("not implemented")
+}
+
+func (m *mockCoordinator) WaitForElectionRound(context.Context, []model.ServerAddress) error {
+	return nil
+}
+
+func (m *mockCoordinator) LeaderReturnTimeout() time.Duration {
+	m.Lock()
+	defer m.Unlock()
+	return m.leaderReturnTimeout
+}
+
+func (m *mockCoordinator) IsDraining(server model.ServerAddress) bool {
+	m.Lock()
+	defer m.Unlock()
+	return listContains(m.draining, server)
+}
+
+func (m *mockCoordinator) StuckElectionTimeout() time.Duration {
+	m.Lock()
+	defer m.Unlock()
+	return m.stuckElectionTimeout
+}
+
+func (m *mockCoordinator) SelectNewNode(namespace string, ensemble []model.ServerAddress, from model.ServerAddress) (*model.ServerAddress, error) {
+	m.Lock()
+	defer m.Unlock()
+	if m.newNode == nil {
+		return nil, ErrNoAvailableServers
+	}
+	return m.newNode, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/streamnative/oxia/proto"
+)
+
+// ShardLoad is the load served by a shard, with the rates computed
+// between two consecutive samples taken from the shard leader.
+type ShardLoad struct {
+	WriteOpsRate   float64
+	ReadOpsRate    float64
+	WriteBytesRate float64
+	DiskBytes      int64
+}
+
+// loadSampler turns the cumulative counters reported by the leader
+// into rates.
+type loadSampler struct {
+	term       int64
+	lastSample *proto.ShardLoad
+	lastTime   time.Time
+
+	current atomic.Pointer[ShardLoad]
+}
+
+func (ls *loadSampler) add(term int64, sample *proto.ShardLoad, now time.Time) {
+	if sample == nil {
+		return
+	}
+
+	last := ls.lastSample
+	isContinuation := last != nil && ls.term == term &&
+		sample.WriteOps >= last.WriteOps &&
+		sample.ReadOps >= last.ReadOps &&
+		sample.WriteBytes >= last.WriteBytes
+
+	elapsed := now.Sub(ls.lastTime).Seconds()
+
+	ls.term = term
+	ls.lastSample = sample
+	ls.lastTime = now
+
+	// The counters were reset by a different leader, we need to wait
+	// for the next sample
+	if !isContinuation || elapsed <= 0 {
+		return
+	}
+
+	ls.current.Store(&ShardLoad{
+		WriteOpsRate:   float64(sample.WriteOps-last.WriteOps) / elapsed,
+		ReadOpsRate:    float64(sample.ReadOps-last.ReadOps) / elapsed,
+		WriteBytesRate: float64(sample.WriteBytes-last.WriteBytes) / elapsed,
+		DiskBytes:      sample.DiskBytes,
+	})
+}
+
+func (ls *loadSampler) get() (ShardLoad, bool) {
+	load := ls.current.Load()
+	if load == nil {
+		return ShardLoad{}, false
+	}
+	return *load, true
+}
@@ -0,0 +1,253 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/metrics"
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+var ErrTenantQuotaExceeded = errors.New("the quota of the tenant is exceeded")
+
+func findTenantConfig(config *model.ClusterConfig, tenant string) *model.TenantConfig {
+	for i := range config.Tenants {
+		if config.Tenants[i].Name == tenant {
+			return &config.Tenants[i]
+		}
+	}
+	return nil
+}
+
+// namespacePool returns the pool of the servers of the namespace, which
+// defaults to the pool of its tenant.
+func namespacePool(config *model.ClusterConfig, nc model.NamespaceConfig) string {
+	if nc.Pool != "" {
+		return nc.Pool
+	}
+	if t := findTenantConfig(config, nc.Tenant); t != nil {
+		return t.Pool
+	}
+	return ""
+}
+
+// namespaceTenant returns the tenant of a namespace, from the cluster config
+// or, for the dynamic namespaces, from their status.
+func namespaceTenant(config *model.ClusterConfig, namespace string, nss model.NamespaceStatus) string {
+	if nc := findNamespaceConfig(config, namespace); nc != nil {
+		return nc.Tenant
+	}
+	return nss.Tenant
+}
+
+func validateTenants(config *model.ClusterConfig) error {
+	names := common.NewSet[string]()
+	pools := map[string]string{}
+	for _, t := range config.Tenants {
+		switch {
+		case t.Name == "":
+			return errors.Wrap(ErrInvalidClusterConfig, "the tenant name cannot be empty")
+		case names.Contains(t.Name):
+			return errors.Wrapf(ErrInvalidClusterConfig, "duplicated tenant %s", t.Name)
+		case t.MaxNamespaces < 0 || t.MaxShards < 0 || t.MaxOpsPerSecond < 0 || t.MaxWriteBytesPerSecond < 0:
+			return errors.Wrapf(ErrInvalidClusterConfig, "tenant %s: the limits cannot be negative", t.Name)
+		}
+		names.Add(t.Name)
+
+		if t.Pool != "" {
+			if other, ok := pools[t.Pool]; ok {
+				return errors.Wrapf(ErrInvalidClusterConfig, "tenant %s: pool %s is dedicated to tenant %s", t.Name, t.Pool, other)
+			}
+			pools[t.Pool] = t.Name
+		}
+
+		for _, principal := range t.Principals {
+			if principal == "" {
+				return errors.Wrapf(ErrInvalidClusterConfig, "tenant %s: the principals cannot be empty", t.Name)
+			}
+			if err := auth.ValidatePrincipalPattern(principal); err != nil {
+				return errors.Wrapf(ErrInvalidClusterConfig, "tenant %s: invalid principal pattern %s", t.Name, principal)
+			}
+		}
+		for _, permission := range t.Permissions {
+			switch permission {
+			case model.PermissionRead, model.PermissionWrite, model.PermissionAdmin:
+			default:
+				return errors.Wrapf(ErrInvalidClusterConfig, "tenant %s: unknown permission %s", t.Name, permission)
+			}
+		}
+
+		var namespaces, shards int
+		for _, nc := range config.Namespaces {
+			if nc.Tenant == t.Name {
+				namespaces++
+				shards += int(nc.InitialShardCount)
+			}
+		}
+		if err := checkTenantQuota(&t, namespaces, shards); err != nil {
+			return errors.Wrap(ErrInvalidClusterConfig, err.Error())
+		}
+	}
+	return nil
+}
+
+// validateNamespaceTenant checks that the tenant of the namespace exists, and
+// that the namespace is not placed in the pool dedicated to another tenant.
+func validateNamespaceTenant(config *model.ClusterConfig, nc model.NamespaceConfig) error {
+	if nc.Tenant != "" {
+		t := findTenantConfig(config, nc.Tenant)
+		if t == nil {
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "unknown tenant %s", nc.Tenant)
+		}
+		if t.Pool != "" && nc.Pool != "" && nc.Pool != t.Pool {
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "the namespaces of tenant %s must be in pool %s", t.Name, t.Pool)
+		}
+	}
+
+	pool := namespacePool(config, nc)
+	for _, t := range config.Tenants {
+		if t.Pool != "" && t.Pool == pool && t.Name != nc.Tenant {
+			return errors.Wrapf(ErrInvalidNamespaceConfig, "pool %s is dedicated to tenant %s", pool, t.Name)
+		}
+	}
+	return nil
+}
+
+func checkTenantQuota(t *model.TenantConfig, namespaces int, shards int) error {
+	switch {
+	case t.MaxNamespaces > 0 && namespaces > t.MaxNamespaces:
+		return errors.Wrapf(ErrTenantQuotaExceeded, "tenant %s: %d namespaces, the max is %d", t.Name, namespaces, t.MaxNamespaces)
+	case t.MaxShards > 0 && shards > t.MaxShards:
+		return errors.Wrapf(ErrTenantQuotaExceeded, "tenant %s: %d shards, the max is %d", t.Name, shards, t.MaxShards)
+	}
+	return nil
+}
+
+type tenantUsage struct {
+	namespaces int
+	shards     int
+}
+
+// tenantsUsage counts the namespaces and the shards of each tenant, including
+// the dynamic namespaces. The soft-deleted namespaces are not counted.
+func tenantsUsage(config *model.ClusterConfig, status *model.ClusterStatus) map[string]tenantUsage {
+	res := map[string]tenantUsage{}
+	for _, t := range config.Tenants {
+		res[t.Name] = tenantUsage{}
+	}
+	for name, nss := range status.Namespaces {
+		if nss.IsSoftDeleted() {
+			continue
+		}
+		tenant := namespaceTenant(config, name, nss)
+		if u, ok := res[tenant]; ok {
+			u.namespaces++
+			u.shards += len(nss.Shards)
+			res[tenant] = u
+		}
+	}
+	return res
+}
+
+// checkNewTenantNamespace applies the quotas of the tenant to a namespace
+// created through the admin API.
+func checkNewTenantNamespace(config *model.ClusterConfig, status *model.ClusterStatus, nc model.NamespaceConfig) error {
+	t := findTenantConfig(config, nc.Tenant)
+	if t == nil {
+		return nil
+	}
+
+	u := tenantsUsage(config, status)[t.Name]
+	return checkTenantQuota(t, u.namespaces+1, u.shards+int(nc.InitialShardCount))
+}
+
+func toProtoTenantPolicies(t *model.TenantConfig) *proto.TenantPolicies {
+	return &proto.TenantPolicies{
+		Name:                   t.Name,
+		MaxOpsPerSecond:        t.MaxOpsPerSecond,
+		MaxWriteBytesPerSecond: t.MaxWriteBytesPerSecond,
+	}
+}
+
+// tenantMetrics reports the namespaces and the shards of each tenant. The
+// counts are computed with the assignments, so that the gauges don't need
+// the lock of the coordinator. The gauges are registered and unregistered
+// without holding the lock of the counts, which their callbacks take.
+type tenantMetrics struct {
+	sync.Mutex
+	usage map[string]tenantUsage
+
+	gaugesLock sync.Mutex
+	gauges     map[string][]metrics.Gauge
+}
+
+func newTenantMetrics() *tenantMetrics {
+	return &tenantMetrics{
+		usage:  map[string]tenantUsage{},
+		gauges: map[string][]metrics.Gauge{},
+	}
+}
+
+func (m *tenantMetrics) update(usage map[string]tenantUsage) {
+	m.Lock()
+	m.usage = usage
+	m.Unlock()
+
+	m.gaugesLock.Lock()
+	defer m.gaugesLock.Unlock()
+
+	for tenant, gauges := range m.gauges {
+		if _, ok := usage[tenant]; !ok {
+			for _, g := range gauges {
+				g.Unregister()
+			}
+			delete(m.gauges, tenant)
+		}
+	}
+
+	for tenant := range usage {
+		if _, ok := m.gauges[tenant]; ok {
+			continue
+		}
+
+		labels := map[string]any{"tenant": tenant}
+		m.gauges[tenant] = []metrics.Gauge{
+			metrics.NewGauge("oxia_coordinator_tenant_namespaces",
+				"The number of namespaces of the tenant", "count", labels, func() int64 {
+					return int64(m.get(tenant).namespaces)
+				}),
+			metrics.NewGauge("oxia_coordinator_tenant_shards",
+				"The number of shards of the namespaces of the tenant", "count", labels, func() int64 {
+					return int64(m.get(tenant).shards)
+				}),
+		}
+	}
+}
+
+func (m *tenantMetrics) get(tenant string) tenantUsage {
+	m.Lock()
+	defer m.Unlock()
+	return m.usage[tenant]
+}
+
+func (m *tenantMetrics) close() {
+	m.update(map[string]tenantUsage{})
+}
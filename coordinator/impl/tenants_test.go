@@ -0,0 +1,171 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/coordinator/model"
+	"github.com/streamnative/oxia/proto"
+)
+
+func tenantsConfig() *model.ClusterConfig {
+	return &model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              "shared",
+			InitialShardCount: 1,
+			ReplicationFactor: 2,
+		}, {
+			Name:              "team-a-1",
+			InitialShardCount: 2,
+			ReplicationFactor: 2,
+			Tenant:            "team-a",
+		}, {
+			Name:              "team-b-1",
+			InitialShardCount: 1,
+			ReplicationFactor: 2,
+			Tenant:            "team-b",
+		}},
+		Servers: []model.ServerAddress{s1, s2, s3, s4},
+		ServerMetadata: map[string]model.ServerMetadata{
+			s3.Internal: {Pool: "team-a"},
+			s4.Internal: {Pool: "team-a"},
+		},
+		Tenants: []model.TenantConfig{{
+			Name:          "team-a",
+			Pool:          "team-a",
+			MaxNamespaces: 2,
+			MaxShards:     3,
+		}, {
+			Name: "team-b",
+		}},
+	}
+}
+
+func TestTenants_Placement(t *testing.T) {
+	config := tenantsConfig()
+	assert.NoError(t, validateClusterConfig(config))
+
+	newStatus, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// The namespaces of the tenant are placed in its pool, and the others
+	// are kept out of it
+	for _, sm := range newStatus.Namespaces["team-a-1"].Shards {
+		assert.ElementsMatch(t, []model.ServerAddress{s3, s4}, sm.Ensemble)
+	}
+	for _, ns := range []string{"shared", "team-b-1"} {
+		for _, sm := range newStatus.Namespaces[ns].Shards {
+			assert.ElementsMatch(t, []model.ServerAddress{s1, s2}, sm.Ensemble)
+		}
+	}
+	assert.Equal(t, "team-a", newStatus.Namespaces["team-a-1"].Tenant)
+}
+
+func TestTenants_Validation(t *testing.T) {
+	for name, update := range map[string]func(config *model.ClusterConfig){
+		"empty-name":         func(c *model.ClusterConfig) { c.Tenants[1].Name = "" },
+		"duplicated-tenant":  func(c *model.ClusterConfig) { c.Tenants[1].Name = "team-a" },
+		"negative-limit":     func(c *model.ClusterConfig) { c.Tenants[1].MaxOpsPerSecond = -1 },
+		"shared-pool":        func(c *model.ClusterConfig) { c.Tenants[1].Pool = "team-a" },
+		"unknown-permission": func(c *model.ClusterConfig) { c.Tenants[1].Permissions = []model.Permission{"delete"} },
+		"invalid-principal":  func(c *model.ClusterConfig) { c.Tenants[1].Principals = []string{"spiffe://example.org/["} },
+		"unknown-tenant":     func(c *model.ClusterConfig) { c.Namespaces[2].Tenant = "team-c" },
+		"other-pool":         func(c *model.ClusterConfig) { c.Namespaces[1].Pool = "eu" },
+		"dedicated-pool":     func(c *model.ClusterConfig) { c.Namespaces[0].Pool = "team-a" },
+		"max-namespaces": func(c *model.ClusterConfig) {
+			c.Tenants[0].MaxNamespaces = 1
+			c.Namespaces = append(c.Namespaces, model.NamespaceConfig{
+				Name: "team-a-2", InitialShardCount: 1, ReplicationFactor: 2, Tenant: "team-a",
+			})
+		},
+		"max-shards": func(c *model.ClusterConfig) {
+			c.Namespaces = append(c.Namespaces, model.NamespaceConfig{
+				Name: "team-a-2", InitialShardCount: 2, ReplicationFactor: 2, Tenant: "team-a",
+			})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := tenantsConfig()
+			update(config)
+			assert.Error(t, validateClusterConfig(config))
+		})
+	}
+}
+
+func TestTenants_Quotas(t *testing.T) {
+	config := tenantsConfig()
+	status, _, _, err := applyClusterChanges(config, model.NewClusterStatus())
+	assert.NoError(t, err)
+
+	// The dynamic namespaces count against the quotas of their tenant
+	assert.NoError(t, checkNewTenantNamespace(config, status, model.NamespaceConfig{
+		Name: "team-a-2", InitialShardCount: 1, ReplicationFactor: 2, Tenant: "team-a",
+	}))
+	assert.ErrorIs(t, checkNewTenantNamespace(config, status, model.NamespaceConfig{
+		Name: "team-a-2", InitialShardCount: 2, ReplicationFactor: 2, Tenant: "team-a",
+	}), ErrTenantQuotaExceeded)
+
+	nss := status.Namespaces["team-b-1"].Clone()
+	nss.Tenant = "team-a"
+	status.Namespaces["team-a-dynamic"] = nss
+	assert.Equal(t, map[string]tenantUsage{
+		"team-a": {namespaces: 2, shards: 3},
+		"team-b": {namespaces: 1, shards: 1},
+	}, tenantsUsage(config, status))
+	assert.ErrorIs(t, checkNewTenantNamespace(config, status, model.NamespaceConfig{
+		Name: "team-a-2", InitialShardCount: 1, ReplicationFactor: 2, Tenant: "team-a",
+	}), ErrTenantQuotaExceeded)
+
+	// The namespaces without tenant are not limited
+	assert.NoError(t, checkNewTenantNamespace(config, status, model.NamespaceConfig{
+		Name: "other", InitialShardCount: 10, ReplicationFactor: 2,
+	}))
+}
+
+func TestTenants_Authorization(t *testing.T) {
+	tenant := &model.TenantConfig{
+		Name:        "team-a",
+		Principals:  []string{"alice", "spiffe://example.org/ns/team-a/*"},
+		Permissions: []model.Permission{model.PermissionRead, model.PermissionWrite},
+	}
+	authz := &model.AuthorizationConfig{
+		Roles: []model.Role{{
+			Name:       "operators",
+			Principals: []string{"carol"},
+			Permissions: map[string][]model.Permission{
+				model.AllNamespaces: {model.PermissionAdmin},
+			},
+		}},
+	}
+
+	// The principals of the tenant restrict the namespace on their own
+	authorization := toProtoNamespaceAuthorization(nil, tenant, "team-a-1", nil)
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"alice": {Read: true, Write: true},
+	}, authorization.Principals)
+	assert.Len(t, authorization.PrincipalPatterns, 1)
+
+	authorization = toProtoNamespaceAuthorization(authz, tenant, "team-a-1", nil)
+	assert.Equal(t, map[string]*proto.NamespacePermissions{
+		"alice": {Read: true, Write: true},
+		"carol": {Admin: true},
+	}, authorization.Principals)
+	assert.Len(t, authz.Roles, 1)
+
+	assert.Nil(t, toProtoNamespaceAuthorization(nil, &model.TenantConfig{Name: "team-b"}, "team-b-1", nil))
+}
@@ -14,13 +14,401 @@
 
 package model
 
+import (
+	"slices"
+	"time"
+)
+
 type ClusterConfig struct {
 	Namespaces []NamespaceConfig `json:"namespaces" yaml:"namespaces"`
 	Servers    []ServerAddress   `json:"servers" yaml:"servers"`
+
+	// ServerMetadata associates the servers, identified by their internal
+	// address, with the labels that describe where they are running
+	ServerMetadata map[string]ServerMetadata `json:"serverMetadata,omitempty" yaml:"serverMetadata,omitempty"`
+
+	// FailureDomains are the labels of the servers that identify the
+	// underlying failure domains (eg: `host`, `hypervisor` or `chassis`).
+	// Two replicas of the same shard are never placed on servers sharing
+	// the value of any of these labels, in all the namespaces.
+	FailureDomains []string `json:"failureDomains,omitempty" yaml:"failureDomains,omitempty"`
+
+	// LoadBalancer enables the periodic rebalancing of the shards based
+	// on the load they're serving
+	LoadBalancer *LoadBalancerConfig `json:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
+
+	// Assignment selects the strategy used to place the replicas of the
+	// new shards, and the new replicas of the existing shards
+	Assignment *AssignmentConfig `json:"assignment,omitempty" yaml:"assignment,omitempty"`
+
+	// DisableAutoRebalance stops the coordinator from moving the shard
+	// replicas to even out the servers when the cluster config changes, eg:
+	// when new servers are added. The rebalancing can still be triggered
+	// through the admin API. The replicas on removed or decommissioned
+	// servers are always moved.
+	DisableAutoRebalance bool `json:"disableAutoRebalance,omitempty" yaml:"disableAutoRebalance,omitempty"`
+
+	// NamespaceDeletionGracePeriod is how long a deleted namespace is kept in
+	// soft-deleted state, before the data of its shards is removed from the
+	// servers. Namespaces are deleted right away when it's not set.
+	NamespaceDeletionGracePeriod time.Duration `json:"namespaceDeletionGracePeriod,omitempty" yaml:"namespaceDeletionGracePeriod,omitempty"`
+
+	// Elections throttles the leader elections, to limit the number of shards
+	// that are unavailable at the same time when several servers restart in a
+	// short window, eg: during a rolling upgrade
+	Elections *ElectionsConfig `json:"elections,omitempty" yaml:"elections,omitempty"`
+
+	// MaintenanceWindows restrict the heavy operations that the coordinator
+	// starts on its own, like the automatic rebalancing and the load
+	// balancing, to the given windows. They can run at any time when no
+	// window is configured.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+
+	// Authorization restricts the access to the namespaces to the principals
+	// authenticated by the servers. The namespaces are open to all the
+	// clients when it's not set.
+	Authorization *AuthorizationConfig `json:"authorization,omitempty" yaml:"authorization,omitempty"`
+
+	// Tenants group the namespaces of the teams sharing the cluster, with
+	// limits and permissions that apply to all of their namespaces
+	Tenants []TenantConfig `json:"tenants,omitempty" yaml:"tenants,omitempty"`
 }
 
 type NamespaceConfig struct {
 	Name              string `json:"name" yaml:"name"`
 	InitialShardCount uint32 `json:"initialShardCount" yaml:"initialShardCount"`
 	ReplicationFactor uint32 `json:"replicationFactor" yaml:"replicationFactor"`
+
+	// AntiAffinities are the rules used to spread the replicas of each shard
+	// across different failure domains (eg: zones or racks)
+	AntiAffinities []AntiAffinity `json:"antiAffinities,omitempty" yaml:"antiAffinities,omitempty"`
+
+	// PlacementConstraints restrict the servers that can hold the replicas of
+	// the shards of the namespace, based on their labels (eg: to keep the
+	// namespace on the servers with `disk: ssd`)
+	PlacementConstraints []PlacementConstraint `json:"placementConstraints,omitempty" yaml:"placementConstraints,omitempty"`
+
+	// Pool pins the replicas of the shards of the namespace to the servers of
+	// the pool. Defaults to the pool of the servers that have no pool.
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
+
+	// ReplicationMode is either "sync" or "relaxed". Defaults to "sync".
+	// The mode is recorded in the metadata of the shards when they're created.
+	ReplicationMode ReplicationMode `json:"replicationMode,omitempty" yaml:"replicationMode,omitempty"`
+
+	// Partitioning is the scheme used to route the keys to the shards, either
+	// "xxhash3", "fnv1a" or "range". Defaults to "xxhash3". The scheme is
+	// recorded when the namespace is created, and it cannot be changed later.
+	Partitioning PartitioningScheme `json:"partitioning,omitempty" yaml:"partitioning,omitempty"`
+
+	// RangeSplitKeys are the boundaries between the shards of a namespace with
+	// the "range" partitioning, in the order of the keys. The initial shard
+	// count must be one more than the number of split keys.
+	RangeSplitKeys []string `json:"rangeSplitKeys,omitempty" yaml:"rangeSplitKeys,omitempty"`
+
+	// Policies are the guardrails that the shard leaders apply to the
+	// requests of the namespace
+	Policies *NamespacePolicies `json:"policies,omitempty" yaml:"policies,omitempty"`
+
+	// Tenant is the name of the tenant the namespace belongs to, if any
+	Tenant string `json:"tenant,omitempty" yaml:"tenant,omitempty"`
 }
+
+// TenantConfig describes a tenant of a shared cluster, eg: a team of the
+// organization, and the guarantees of isolation of its namespaces.
+type TenantConfig struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Pool is the pool of servers dedicated to the tenant. The namespaces of
+	// the tenant are placed in it, and the namespaces of the other tenants
+	// cannot be placed there.
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
+
+	// MaxNamespaces and MaxShards limit the number of namespaces of the
+	// tenant, and the total number of shards of its namespaces
+	MaxNamespaces int `json:"maxNamespaces,omitempty" yaml:"maxNamespaces,omitempty"`
+	MaxShards     int `json:"maxShards,omitempty" yaml:"maxShards,omitempty"`
+
+	// Principals are granted the Permissions on all the namespaces of the
+	// tenant. Once they're set, the namespaces of the tenant are restricted
+	// to the principals with a permission on them.
+	Principals  []string     `json:"principals,omitempty" yaml:"principals,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+
+	// MaxOpsPerSecond and MaxWriteBytesPerSecond limit the rate of all the
+	// namespaces of the tenant combined, on each server. The requests in
+	// excess are rejected.
+	MaxOpsPerSecond        float64 `json:"maxOpsPerSecond,omitempty" yaml:"maxOpsPerSecond,omitempty"`
+	MaxWriteBytesPerSecond float64 `json:"maxWriteBytesPerSecond,omitempty" yaml:"maxWriteBytesPerSecond,omitempty"`
+}
+
+type NamespacePolicies struct {
+	// DefaultTTL is the time after which the records that are not modified
+	// anymore are deleted. The ephemeral records are not affected.
+	DefaultTTL time.Duration `json:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty"`
+
+	// SlidingTTL resets the expiry of the records when they're read, eg: for
+	// caches or presence maps where the entries that are in use are kept.
+	SlidingTTL bool `json:"slidingTTL,omitempty" yaml:"slidingTTL,omitempty"`
+
+	// MaxValueSize is the max size in bytes of the values of the records.
+	// The write requests with larger values are rejected.
+	MaxValueSize int64 `json:"maxValueSize,omitempty" yaml:"maxValueSize,omitempty"`
+
+	// MaxWritesPerSecond and MaxReadsPerSecond limit the rate of operations
+	// in each shard of the namespace. The requests in excess are rejected.
+	MaxWritesPerSecond float64 `json:"maxWritesPerSecond,omitempty" yaml:"maxWritesPerSecond,omitempty"`
+	MaxReadsPerSecond  float64 `json:"maxReadsPerSecond,omitempty" yaml:"maxReadsPerSecond,omitempty"`
+
+	// MaxClientOpsPerSecond and MaxClientWriteBytesPerSecond limit the rate
+	// of each client connection in each shard, so that a single client
+	// cannot take over a shared shard. The requests in excess are throttled.
+	MaxClientOpsPerSecond        float64 `json:"maxClientOpsPerSecond,omitempty" yaml:"maxClientOpsPerSecond,omitempty"`
+	MaxClientWriteBytesPerSecond float64 `json:"maxClientWriteBytesPerSecond,omitempty" yaml:"maxClientWriteBytesPerSecond,omitempty"`
+
+	// NotificationsRetention is how long the notifications are kept,
+	// overriding the retention configured on the servers
+	NotificationsRetention time.Duration `json:"notificationsRetention,omitempty" yaml:"notificationsRetention,omitempty"`
+
+	// PrincipalQuotas limit the authenticated principals in each shard, by
+	// principal. The quota of the `*` principal applies to the principals
+	// without their own. The requests in excess are rejected.
+	PrincipalQuotas map[string]PrincipalQuota `json:"principalQuotas,omitempty" yaml:"principalQuotas,omitempty"`
+}
+
+// AllPrincipals is the principal of the quota that applies to the principals
+// without their own
+const AllPrincipals = "*"
+
+type PrincipalQuota struct {
+	// OpsPerSecond is the max number of operations per second, reads and
+	// writes combined
+	OpsPerSecond float64 `json:"opsPerSecond,omitempty" yaml:"opsPerSecond,omitempty"`
+
+	// WriteBytesPerSecond is the max number of bytes written per second,
+	// counting the keys and the values
+	WriteBytesPerSecond float64 `json:"writeBytesPerSecond,omitempty" yaml:"writeBytesPerSecond,omitempty"`
+
+	// MaxStorageBytes is the max size of the records written by the
+	// principal, counting the keys and the values
+	MaxStorageBytes int64 `json:"maxStorageBytes,omitempty" yaml:"maxStorageBytes,omitempty"`
+}
+
+type PartitioningScheme string
+
+const (
+	// PartitioningXxHash3 spreads the keys across the shards by their
+	// xxHash3 hash, with each shard owning a range of the hashes.
+	PartitioningXxHash3 PartitioningScheme = "xxhash3"
+
+	// PartitioningFnv1a spreads the keys by their 32 bits FNV-1a hash.
+	PartitioningFnv1a PartitioningScheme = "fnv1a"
+
+	// PartitioningRange assigns a contiguous range of keys to each shard, in
+	// the same order in which the keys are listed. The scans only hit the
+	// shards that overlap with the scanned range, at the cost of a less even
+	// distribution of the keys.
+	PartitioningRange PartitioningScheme = "range"
+)
+
+type ReplicationMode string
+
+const (
+	// ReplicationModeSync acknowledges the writes once they are stored
+	// by a quorum of the replicas.
+	ReplicationModeSync ReplicationMode = "sync"
+
+	// ReplicationModeRelaxed acknowledges the writes once they are synced
+	// on the leader, while the followers receive them asynchronously. The
+	// latest writes can be lost if the leader fails.
+	ReplicationModeRelaxed ReplicationMode = "relaxed"
+)
+
+type ServerMetadata struct {
+	// Labels of the server, eg: `zone: us-east-1a` or `rack: r1`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Pool is the group of servers the server belongs to (eg: per region or
+	// per hardware class). The servers without a pool are in the default pool.
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
+}
+
+type AntiAffinityMode string
+
+const (
+	// AntiAffinityModeStrict never places two replicas of the same shard on
+	// servers that share the value of the label. The shards creation fails
+	// if there are not enough distinct values.
+	AntiAffinityModeStrict AntiAffinityMode = "strict"
+
+	// AntiAffinityModeRelaxed spreads the replicas when possible, though
+	// it falls back to placing them on the same label value otherwise.
+	AntiAffinityModeRelaxed AntiAffinityMode = "relaxed"
+)
+
+type AntiAffinity struct {
+	// Labels on which the replicas of a shard must not overlap
+	Labels []string `json:"labels" yaml:"labels"`
+
+	// Mode is either "strict" or "relaxed". Defaults to "strict".
+	Mode AntiAffinityMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+}
+
+func (a *AntiAffinity) IsStrict() bool {
+	return a.Mode != AntiAffinityModeRelaxed
+}
+
+type PlacementOperator string
+
+const (
+	// PlacementOperatorIn only accepts the servers where the label is set
+	// to one of the values
+	PlacementOperatorIn PlacementOperator = "in"
+
+	// PlacementOperatorNotIn rejects the servers where the label is set to
+	// one of the values. The servers without the label are accepted.
+	PlacementOperatorNotIn PlacementOperator = "not-in"
+)
+
+type PlacementConstraint struct {
+	// Label of the servers that is checked by the constraint
+	Label string `json:"label" yaml:"label"`
+
+	// Operator is either "in" or "not-in". Defaults to "in".
+	Operator PlacementOperator `json:"operator,omitempty" yaml:"operator,omitempty"`
+
+	// Values of the label that are accepted or rejected
+	Values []string `json:"values" yaml:"values"`
+}
+
+// Matches checks whether a server with the given labels satisfies the constraint.
+func (c *PlacementConstraint) Matches(labels map[string]string) bool {
+	value, ok := labels[c.Label]
+	found := ok && slices.Contains(c.Values, value)
+
+	if c.Operator == PlacementOperatorNotIn {
+		return !found
+	}
+	return found
+}
+
+type AssignmentStrategy string
+
+const (
+	// AssignmentStrategyUniform places the replicas of the new shards in
+	// round-robin across the servers, and the new replicas of the existing
+	// shards on the servers with the fewest replicas
+	AssignmentStrategyUniform AssignmentStrategy = "uniform"
+
+	// AssignmentStrategyZoneAware spreads the replicas of each shard across
+	// as many zones as possible, picking the servers with the fewest replicas
+	// within each zone
+	AssignmentStrategyZoneAware AssignmentStrategy = "zone-aware"
+
+	// AssignmentStrategyLoadAware places the replicas on the servers with
+	// the lowest load, as scored by the load balancer policy
+	AssignmentStrategyLoadAware AssignmentStrategy = "load-aware"
+)
+
+type AssignmentConfig struct {
+	// Strategy is the name of the assignment strategy. Defaults to "uniform".
+	Strategy AssignmentStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// ZoneLabel is the server label that identifies the zone of each
+	// server, for the zone-aware strategy. Defaults to "zone".
+	ZoneLabel string `json:"zoneLabel,omitempty" yaml:"zoneLabel,omitempty"`
+}
+
+type LoadScoringPolicy string
+
+const (
+	// LoadScoringPolicyOps scores the shards by the rate of read and write operations
+	LoadScoringPolicyOps LoadScoringPolicy = "ops"
+
+	// LoadScoringPolicyBytes scores the shards by the rate of written bytes
+	LoadScoringPolicyBytes LoadScoringPolicy = "bytes"
+
+	// LoadScoringPolicyDisk scores the shards by the space they use on disk
+	LoadScoringPolicyDisk LoadScoringPolicy = "disk"
+)
+
+type ElectionsConfig struct {
+	// MaxRoundsPerSecond is the max number of election rounds per second that
+	// involve each server. The rounds in excess are queued. The elections are
+	// not throttled when it's not set.
+	MaxRoundsPerSecond float64 `json:"maxRoundsPerSecond,omitempty" yaml:"maxRoundsPerSecond,omitempty"`
+
+	// Burst is the number of election rounds that can involve each server at
+	// once, before being throttled. Defaults to 1.
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+
+	// LeaderReturnTimeout is how long to wait for a failed leader to come
+	// back, eg: after a restart, before electing a new leader. The returned
+	// leader is preferred in the election, if it has all the entries.
+	LeaderReturnTimeout time.Duration `json:"leaderReturnTimeout,omitempty" yaml:"leaderReturnTimeout,omitempty"`
+
+	// StuckTimeout is how long a shard can stay without a leader before it's
+	// considered stuck. The members of the ensemble that keep failing the
+	// election of a stuck shard are replaced. Defaults to 5 minutes.
+	StuckTimeout time.Duration `json:"stuckTimeout,omitempty" yaml:"stuckTimeout,omitempty"`
+}
+
+type MaintenanceWindow struct {
+	// Schedule is the cron expression of the start of the window, with the
+	// minute, hour, day of the month, month and day of the week fields,
+	// eg: `0 2 * * 1-5` for 2am on the weekdays
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// Duration of the window, up to a week
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// Timezone of the schedule, eg: `Europe/Berlin`. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+type LoadBalancerConfig struct {
+	// Interval between two load balancing rounds. The load balancer is
+	// disabled when the interval is not set.
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// Policy used to score the load of each shard. Defaults to "ops".
+	Policy LoadScoringPolicy `json:"policy,omitempty" yaml:"policy,omitempty"`
+
+	// MaxConcurrentMoves is the max number of shard replicas that are moved
+	// in each round. Defaults to 1.
+	MaxConcurrentMoves int `json:"maxConcurrentMoves,omitempty" yaml:"maxConcurrentMoves,omitempty"`
+
+	// Threshold is how much the load of a server can exceed the average
+	// load, before moving shards away from it. Defaults to 0.2 (20%).
+	Threshold float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+type AuthorizationConfig struct {
+	Roles []Role `json:"roles" yaml:"roles"`
+}
+
+// Role grants permissions on the namespaces to a set of principals.
+type Role struct {
+	Name       string   `json:"name" yaml:"name"`
+	Principals []string `json:"principals" yaml:"principals"`
+
+	// Permissions are the permissions granted on each namespace. The
+	// permissions on `*` apply to all the namespaces.
+	Permissions map[string][]Permission `json:"permissions" yaml:"permissions"`
+}
+
+type Permission string
+
+const (
+	// PermissionRead allows to read the records and to get the notifications
+	PermissionRead Permission = "read"
+
+	// PermissionWrite allows to write the records and to manage the sessions
+	PermissionWrite Permission = "write"
+
+	// PermissionAdmin grants all the permissions on the namespace
+	PermissionAdmin Permission = "admin"
+)
+
+// AllNamespaces is the key of the permissions that apply to all the namespaces.
+const AllNamespaces = "*"
@@ -14,6 +14,8 @@
 
 package model
 
+import "time"
+
 type ServerAddress struct {
 	// Public is the endpoint that is advertised to clients
 	Public string `json:"public" yaml:"public"`
@@ -30,6 +32,15 @@ type Int32HashRange struct {
 	Max uint32 `json:"max"`
 }
 
+// KeyRange is the range of keys of a shard, with the "range" partitioning.
+type KeyRange struct {
+	// The minimum inclusive key, empty for the first shard
+	Min string `json:"min" yaml:"min"`
+
+	// The maximum exclusive key, empty for the last shard
+	Max string `json:"max" yaml:"max"`
+}
+
 type ShardMetadata struct {
 	Status         ShardStatus     `json:"status" yaml:"status"`
 	Term           int64           `json:"term" yaml:"term"`
@@ -37,17 +48,119 @@ type ShardMetadata struct {
 	Ensemble       []ServerAddress `json:"ensemble" yaml:"ensemble"`
 	RemovedNodes   []ServerAddress `json:"removedNodes" yaml:"removedNodes"`
 	Int32HashRange Int32HashRange  `json:"int32HashRange" yaml:"int32HashRange"`
+
+	// KeyRange is only set for the shards of the namespaces with the
+	// "range" partitioning, in place of the hash range
+	KeyRange *KeyRange `json:"keyRange,omitempty" yaml:"keyRange,omitempty"`
+
+	ReplicationMode ReplicationMode `json:"replicationMode,omitempty" yaml:"replicationMode,omitempty"`
+
+	// Merge is set on the shard that is being merged into another shard,
+	// until the merge is completed or aborted
+	Merge *ShardMerge `json:"merge,omitempty" yaml:"merge,omitempty"`
+}
+
+type ShardMergePhase string
+
+const (
+	// ShardMergePhaseCopying is set while the records of the shard are
+	// staged in the shard it's merged into
+	ShardMergePhaseCopying ShardMergePhase = "copying"
+
+	// ShardMergePhaseCommitting is set once all the records are staged, while
+	// they're moved into the key space of the shard it's merged into. The
+	// merge can't be aborted anymore and the shard stays fenced.
+	ShardMergePhaseCommitting ShardMergePhase = "committing"
+
+	// ShardMergePhaseAborting is set while the staged records are dropped
+	ShardMergePhaseAborting ShardMergePhase = "aborting"
+)
+
+// ShardMerge is the intent of merging a shard into an adjacent shard, kept in
+// the cluster status for the merge to be resumed, or cleaned up, by a new
+// coordinator.
+type ShardMerge struct {
+	Into  int64           `json:"into" yaml:"into"`
+	Phase ShardMergePhase `json:"phase" yaml:"phase"`
 }
 
 type NamespaceStatus struct {
 	ReplicationFactor uint32                  `json:"replicationFactor" yaml:"replicationFactor"`
 	Shards            map[int64]ShardMetadata `json:"shards" yaml:"shards"`
+
+	// Dynamic namespaces are created through the admin API and they are not
+	// part of the cluster config
+	Dynamic bool `json:"dynamic,omitempty" yaml:"dynamic,omitempty"`
+
+	// DeletedAt is set when the namespace is soft-deleted. Its shards are not
+	// assigned to clients anymore, though their data is kept until the
+	// deletion grace period is over.
+	DeletedAt *time.Time `json:"deletedAt,omitempty" yaml:"deletedAt,omitempty"`
+
+	// Partitioning is the scheme used to route the keys to the shards. It's
+	// empty for the namespaces created before the scheme was configurable,
+	// which use "xxhash3".
+	Partitioning PartitioningScheme `json:"partitioning,omitempty" yaml:"partitioning,omitempty"`
+
+	// KeyPrefixACLs are the permissions granted to the principals on the
+	// keys of the namespace starting with a prefix
+	KeyPrefixACLs []KeyPrefixACL `json:"keyPrefixACLs,omitempty" yaml:"keyPrefixACLs,omitempty"`
+
+	// Tenant is the tenant of the namespace, as configured when it was
+	// created. The tenant of the namespaces of the cluster config is taken
+	// from the config.
+	Tenant string `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+}
+
+// KeyPrefixACL grants permissions to a principal on the keys starting with a
+// prefix. The prefixes end with `/`, so that they match whole levels of the
+// hierarchy of the keys.
+type KeyPrefixACL struct {
+	Principal   string       `json:"principal" yaml:"principal"`
+	Prefix      string       `json:"prefix" yaml:"prefix"`
+	Permissions []Permission `json:"permissions" yaml:"permissions"`
+}
+
+type DecommissionState string
+
+const (
+	// DecommissionStateInProgress is set while the replicas are being moved
+	// out of the server
+	DecommissionStateInProgress DecommissionState = "in-progress"
+
+	// DecommissionStateCompleted is set once the server doesn't hold any
+	// replica, and it can be removed from the cluster config
+	DecommissionStateCompleted DecommissionState = "completed"
+)
+
+type ServerStatus struct {
+	Decommission DecommissionState `json:"decommission,omitempty" yaml:"decommission,omitempty"`
+
+	// Draining is set while the server is in maintenance. The shards are led
+	// by other servers and no new replica is placed on it, though the existing
+	// replicas are kept.
+	Draining bool `json:"draining,omitempty" yaml:"draining,omitempty"`
 }
 
 type ClusterStatus struct {
 	Namespaces       map[string]NamespaceStatus `json:"namespaces" yaml:"namespaces"`
 	ShardIdGenerator int64                      `json:"shardIdGenerator" yaml:"shardIdGenerator"`
 	ServerIdx        uint32                     `json:"serverIdx" yaml:"serverIdx"`
+
+	// Servers keeps the status of the servers, by their internal address.
+	// Only the servers with a non-default status are present.
+	Servers map[string]ServerStatus `json:"servers,omitempty" yaml:"servers,omitempty"`
+
+	// Freeze is set while the cluster is in a maintenance freeze
+	Freeze *FreezeStatus `json:"freeze,omitempty" yaml:"freeze,omitempty"`
+}
+
+// FreezeStatus describes a maintenance freeze of the cluster. While the
+// cluster is frozen, the coordinator doesn't move any shard replica or leader
+// on its own, though the leader elections still happen.
+type FreezeStatus struct {
+	Since  time.Time `json:"since" yaml:"since"`
+	Reason string    `json:"reason,omitempty" yaml:"reason,omitempty"`
 }
 
 func NewClusterStatus() *ClusterStatus {
@@ -73,11 +186,23 @@ func (sm ShardMetadata) Clone() ShardMetadata {
 		Ensemble:       make([]ServerAddress, len(sm.Ensemble)),
 		RemovedNodes:   make([]ServerAddress, len(sm.RemovedNodes)),
 		Int32HashRange: sm.Int32HashRange.Clone(),
+
+		ReplicationMode: sm.ReplicationMode,
 	}
 
 	copy(r.Ensemble, sm.Ensemble)
 	copy(r.RemovedNodes, sm.RemovedNodes)
 
+	if sm.KeyRange != nil {
+		keyRange := *sm.KeyRange
+		r.KeyRange = &keyRange
+	}
+
+	if sm.Merge != nil {
+		merge := *sm.Merge
+		r.Merge = &merge
+	}
+
 	return r
 }
 
@@ -85,15 +210,43 @@ func (n NamespaceStatus) Clone() NamespaceStatus {
 	r := NamespaceStatus{
 		Shards:            make(map[int64]ShardMetadata),
 		ReplicationFactor: n.ReplicationFactor,
+		Dynamic:           n.Dynamic,
+		Partitioning:      n.Partitioning,
+		Tenant:            n.Tenant,
+	}
+
+	if n.DeletedAt != nil {
+		deletedAt := *n.DeletedAt
+		r.DeletedAt = &deletedAt
 	}
 
 	for shard, sm := range n.Shards {
 		r.Shards[shard] = sm.Clone()
 	}
 
+	for _, acl := range n.KeyPrefixACLs {
+		acl.Permissions = append([]Permission{}, acl.Permissions...)
+		r.KeyPrefixACLs = append(r.KeyPrefixACLs, acl)
+	}
+
 	return r
 }
 
+func (n NamespaceStatus) IsSoftDeleted() bool {
+	return n.DeletedAt != nil
+}
+
+// IsDeleting returns true once the shards of the namespace are being deleted
+// from the servers.
+func (n NamespaceStatus) IsDeleting() bool {
+	for _, sm := range n.Shards {
+		if sm.Status == ShardStatusDeleting {
+			return true
+		}
+	}
+	return false
+}
+
 func (c ClusterStatus) Clone() *ClusterStatus {
 	r := &ClusterStatus{
 		Namespaces:       make(map[string]NamespaceStatus),
@@ -105,5 +258,31 @@ func (c ClusterStatus) Clone() *ClusterStatus {
 		r.Namespaces[name] = n.Clone()
 	}
 
+	if c.Servers != nil {
+		r.Servers = make(map[string]ServerStatus, len(c.Servers))
+		for addr, ss := range c.Servers {
+			r.Servers[addr] = ss
+		}
+	}
+
+	if c.Freeze != nil {
+		freeze := *c.Freeze
+		r.Freeze = &freeze
+	}
+
 	return r
 }
+
+func (c ClusterStatus) IsFrozen() bool {
+	return c.Freeze != nil
+}
+
+// IsDecommissioned returns true if the server is being decommissioned, or if
+// it was already decommissioned.
+func (c ClusterStatus) IsDecommissioned(server ServerAddress) bool {
+	return c.Servers[server.Internal].Decommission != ""
+}
+
+func (c ClusterStatus) IsDraining(server ServerAddress) bool {
+	return c.Servers[server.Internal].Draining
+}
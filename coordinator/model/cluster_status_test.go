@@ -16,6 +16,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,12 +46,17 @@ func TestClusterStatus_Clone(t *testing.T) {
 							Public:   "r1",
 							Internal: "r1",
 						}},
+						Merge: &ShardMerge{Into: 1, Phase: ShardMergePhaseCopying},
 					},
 				},
 			},
 		},
 		ShardIdGenerator: 5,
 		ServerIdx:        7,
+		Servers: map[string]ServerStatus{
+			"f2": {Decommission: DecommissionStateInProgress},
+		},
+		Freeze: &FreezeStatus{Since: time.UnixMilli(1000), Reason: "incident"},
 	}
 
 	cs2 := cs1.Clone()
@@ -64,6 +70,16 @@ func TestClusterStatus_Clone(t *testing.T) {
 	assert.Equal(t, cs1.Namespaces["test-ns"].Shards[0], cs2.Namespaces["test-ns"].Shards[0])
 	assert.NotSame(t, cs1.Namespaces["test-ns"].Shards[0], cs2.Namespaces["test-ns"].Shards[0])
 
+	assert.NotSame(t, cs1.Namespaces["test-ns"].Shards[0].Merge, cs2.Namespaces["test-ns"].Shards[0].Merge)
+
 	assert.Equal(t, cs1.ShardIdGenerator, cs2.ShardIdGenerator)
 	assert.Equal(t, cs1.ServerIdx, cs2.ServerIdx)
+
+	cs2.Servers["f1"] = ServerStatus{Decommission: DecommissionStateCompleted}
+	assert.False(t, cs1.IsDecommissioned(ServerAddress{Public: "f1", Internal: "f1"}))
+	assert.True(t, cs1.IsDecommissioned(ServerAddress{Public: "f2", Internal: "f2"}))
+
+	assert.NotSame(t, cs1.Freeze, cs2.Freeze)
+	cs2.Freeze = nil
+	assert.True(t, cs1.IsFrozen())
 }
@@ -20,6 +20,27 @@ Coordinator labels
 app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
 app.kubernetes.io/part-of: oxia
 app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- with include "oxia-cluster.coordinator.extraLabels" . | trim }}
+{{ . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Coordinator user provided labels, the per component ones taking precedence over the global ones
+*/}}
+{{- define "oxia-cluster.coordinator.extraLabels" -}}
+{{- with merge (dict) (.Values.coordinator.labels | default dict) (.Values.labels | default dict) }}
+{{ toYaml . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Coordinator user provided annotations, the per component ones taking precedence over the global ones
+*/}}
+{{- define "oxia-cluster.coordinator.annotations" -}}
+{{- with merge (dict) (.Values.coordinator.annotations | default dict) (.Values.annotations | default dict) }}
+{{ toYaml . }}
+{{- end }}
 {{- end }}
 
 {{/*
@@ -38,6 +59,27 @@ Server labels
 {{ include "oxia-cluster.server.selectorLabels" . }}
 app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
 app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- with include "oxia-cluster.server.extraLabels" . | trim }}
+{{ . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Server user provided labels, the per component ones taking precedence over the global ones
+*/}}
+{{- define "oxia-cluster.server.extraLabels" -}}
+{{- with merge (dict) (.Values.server.labels | default dict) (.Values.labels | default dict) }}
+{{ toYaml . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Server user provided annotations, the per component ones taking precedence over the global ones
+*/}}
+{{- define "oxia-cluster.server.annotations" -}}
+{{- with merge (dict) (.Values.server.annotations | default dict) (.Values.annotations | default dict) }}
+{{ toYaml . }}
+{{- end }}
 {{- end }}
 
 {{/*
@@ -49,14 +91,262 @@ app.kubernetes.io/component: server
 app.kubernetes.io/instance: {{ .Release.Name }}
 {{- end }}
 
+{{/*
+Labels of the pods run by the chart alongside the cluster, eg: the tests, with
+a component of their own so that they are not selected by the services and the
+disruption budgets of the cluster
+*/}}
+{{- define "oxia-cluster.component.labels" -}}
+app.kubernetes.io/name: {{ .root.Chart.Name }}
+app.kubernetes.io/component: {{ .component }}
+app.kubernetes.io/instance: {{ .root.Release.Name }}
+app.kubernetes.io/version: {{ .root.Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .root.Release.Service }}
+{{- with .root.Values.labels }}
+{{ toYaml . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Arguments of the client commands, to connect to the servers of the cluster
+*/}}
+{{- define "oxia-cluster.client-args" -}}
+-a {{ .Release.Name }}:{{ .Values.server.ports.public }}
+{{- if .Values.tls.enabled }} --tls-trusted-ca-file=/etc/oxia/tls/ca.crt{{ end }}
+{{- end }}
+
+{{/*
+Arguments of the admin commands, to connect to the coordinator with its
+certificate mounted in the given directory
+*/}}
+{{- define "oxia-cluster.admin-args" -}}
+-a {{ .root.Release.Name }}-coordinator:{{ .root.Values.coordinator.ports.internal }}
+{{- if .root.Values.tls.enabled }} --tls-cert-file={{ .dir }}/tls.crt --tls-key-file={{ .dir }}/tls.key --tls-trusted-ca-file={{ .dir }}/ca.crt{{ end }}
+{{- end }}
+
+{{/*
+Service account of the coordinator
+*/}}
+{{- define "oxia-cluster.coordinator.serviceAccountName" -}}
+{{ .Values.coordinator.serviceAccount.name | default (printf "%s-coordinator" .Release.Name) }}
+{{- end }}
+
+{{/*
+Service account of the servers
+*/}}
+{{- define "oxia-cluster.server.serviceAccountName" -}}
+{{ .Values.server.serviceAccount.name | default .Release.Name }}
+{{- end }}
+
+{{/*
+Number of servers per StatefulSet listed in the cluster config, the minimum
+number of replicas when they are autoscaled
+*/}}
+{{- define "oxia-cluster.server.replicas" -}}
+{{- if .Values.server.autoscaling.enabled }}
+{{- .Values.server.autoscaling.minReplicas | int }}
+{{- else }}
+{{- .Values.server.replicas | int }}
+{{- end }}
+{{- end }}
+
+{{/*
+Names of the server pods, across the StatefulSets of all the zones, as a json array
+*/}}
+{{- define "oxia-cluster.server.podNames" -}}
+{{- $pods := list }}
+{{- range $zone := $.Values.topology.zones | default (list "") }}
+{{- $name := $.Release.Name }}
+{{- if $zone }}
+{{- $name = printf "%s-%s" $.Release.Name $zone }}
+{{- end }}
+{{- range until (int (include "oxia-cluster.server.replicas" $)) }}
+{{- $pods = append $pods (printf "%s-%d" $name .) }}
+{{- end }}
+{{- end }}
+{{- toJson $pods }}
+{{- end }}
+
+{{/*
+Cluster config of the coordinator
+*/}}
+{{- define "oxia-cluster.coordinator.config" -}}
+namespaces:
+  {{- range .Values.namespaces }}
+  {{- $ns := merge (dict) . (dict "initialShardCount" $.Values.initialShardCount "replicationFactor" $.Values.replicationFactor) }}
+  - {{ toYaml $ns | indent 4 | trim }}
+  {{- else }}
+  - name: default
+    initialShardCount: {{ .Values.initialShardCount }}
+    replicationFactor: {{ .Values.replicationFactor }}
+  {{- end }}
+servers:
+  {{- $vars := dict "name" .Release.Name "namespace" .Release.Namespace "public" .Values.server.ports.public "internal" .Values.server.ports.internal }}
+  {{- range $i, $pod := include "oxia-cluster.server.podNames" . | fromJsonArray }}
+  {{- if $.Values.externalAccess.enabled }}
+  {{- $port := $vars.public }}
+  {{- if eq $.Values.externalAccess.type "NodePort" }}
+  {{- $port = add $.Values.externalAccess.nodePortBase $i }}
+  {{- end }}
+  - public: {{ $pod }}.{{ $.Values.externalAccess.domain }}:{{ $port }}
+  {{- else }}
+  - public: {{ $pod }}.{{ $vars.name }}-svc.{{ $vars.namespace }}.svc.cluster.local:{{ $vars.public }}
+  {{- end }}
+    internal: {{ $pod }}.{{ $vars.name }}-svc:{{ $vars.internal }}
+  {{- end }}
+{{- with .Values.topology.zones }}
+serverMetadata:
+  {{- range $zone := . }}
+  {{- range until (int (include "oxia-cluster.server.replicas" $)) }}
+  {{ $vars.name }}-{{ $zone }}-{{ . }}.{{ $vars.name }}-svc:{{ $vars.internal }}:
+    labels:
+      zone: {{ $zone }}
+  {{- end }}
+  {{- end }}
+{{- if $.Values.topology.spreadReplicas }}
+failureDomains:
+  - zone
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Affinity of the pods, with the anti-affinity preset across the hosts added
+unless the affinity already has a pod anti-affinity
+*/}}
+{{- define "oxia-cluster.affinity" -}}
+{{- $affinity := deepCopy (.affinity | default dict) }}
+{{- if and (ne .antiAffinity "none") (not (hasKey $affinity "podAntiAffinity")) }}
+{{- $term := dict "topologyKey" "kubernetes.io/hostname" "labelSelector" (dict "matchLabels" (.selectorLabels | fromYaml)) }}
+{{- if eq .antiAffinity "required" }}
+{{- $_ := set $affinity "podAntiAffinity" (dict "requiredDuringSchedulingIgnoredDuringExecution" (list $term)) }}
+{{- else }}
+{{- $_ := set $affinity "podAntiAffinity" (dict "preferredDuringSchedulingIgnoredDuringExecution" (list (dict "weight" 100 "podAffinityTerm" $term))) }}
+{{- end }}
+{{- end }}
+{{- with $affinity }}
+{{- toYaml . }}
+{{- end }}
+{{- end }}
+
+{{/*
+Image of the containers, pinned to the digest when it is set
+*/}}
+{{- define "oxia-cluster.image" -}}
+{{- if .Values.image.digest -}}
+{{ .Values.image.repository }}@{{ .Values.image.digest }}
+{{- else -}}
+{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}
+{{- end }}
+{{- end }}
+
+{{/*
+Checks of the values that cannot be expressed in values.schema.json
+*/}}
+{{- define "oxia-cluster.validate" -}}
+{{- $zones := len (.Values.topology.zones | default (list "")) }}
+{{- $servers := mul (int (include "oxia-cluster.server.replicas" .)) $zones }}
+{{- $maxReplicationFactor := $servers }}
+{{- if and .Values.topology.zones .Values.topology.spreadReplicas }}
+{{- $maxReplicationFactor = $zones }}
+{{- end }}
+{{- if gt (int .Values.replicationFactor) $maxReplicationFactor }}
+{{- fail (printf "replicationFactor (%d) cannot be greater than the number of servers or of zones to spread the replicas across (%d)" (int .Values.replicationFactor) $maxReplicationFactor) }}
+{{- end }}
+{{- $names := dict }}
+{{- range .Values.namespaces }}
+{{- if hasKey $names .name }}
+{{- fail (printf "namespace %q is listed more than once" .name) }}
+{{- end }}
+{{- $_ := set $names .name true }}
+{{- if gt (int (.replicationFactor | default $.Values.replicationFactor)) $maxReplicationFactor }}
+{{- fail (printf "the replicationFactor of namespace %q cannot be greater than the number of servers or of zones to spread the replicas across (%d)" .name $maxReplicationFactor) }}
+{{- end }}
+{{- end }}
+{{- if and .Values.tls.enabled .Values.tls.certManager.enabled (not .Values.tls.certManager.issuerRef.name) }}
+{{- fail "tls.certManager.issuerRef.name must be set with tls.certManager.enabled" }}
+{{- end }}
+{{- if and .Values.externalAccess.enabled (not .Values.externalAccess.domain) }}
+{{- fail "externalAccess.domain must be set with externalAccess.enabled" }}
+{{- end }}
+{{- if and .Values.externalAccess.enabled (eq .Values.externalAccess.type "NodePort") (not .Values.externalAccess.nodePortBase) }}
+{{- fail "externalAccess.nodePortBase must be set with the NodePort type" }}
+{{- end }}
+{{- if and .Values.backup.enabled (not .Values.backup.volume) }}
+{{- fail "backup.volume must be set with backup.enabled" }}
+{{- end }}
+{{- if and .Values.restore.enabled (not .Values.backup.volume) }}
+{{- fail "backup.volume must be set with restore.enabled" }}
+{{- end }}
+{{- if and .Values.restore.enabled (not .Values.restore.backup) }}
+{{- fail "restore.backup must be set with restore.enabled" }}
+{{- end }}
+{{- if and .Values.tls.certManager.enabled (not .Values.tls.enabled) }}
+{{- fail "tls.certManager.enabled requires tls.enabled" }}
+{{- end }}
+{{- with .Values.server.autoscaling }}
+{{- if .enabled }}
+{{- if lt (int .maxReplicas) (int .minReplicas) }}
+{{- fail "server.autoscaling.maxReplicas cannot be lower than server.autoscaling.minReplicas" }}
+{{- end }}
+{{- if not (or .targetCPUUtilizationPercentage .metrics) }}
+{{- fail "server.autoscaling requires targetCPUUtilizationPercentage or metrics" }}
+{{- end }}
+{{- if $.Values.externalAccess.enabled }}
+{{- fail "server.autoscaling cannot be enabled with externalAccess" }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{/*
+Lowest replication factor of the namespaces, with the same defaults as in the
+cluster config of the coordinator
+*/}}
+{{- define "oxia-cluster.minReplicationFactor" -}}
+{{- $min := int .Values.replicationFactor }}
+{{- range $i, $ns := .Values.namespaces }}
+{{- $rf := int ($ns.replicationFactor | default $.Values.replicationFactor) }}
+{{- if or (eq $i 0) (lt $rf $min) }}
+{{- $min = $rf }}
+{{- end }}
+{{- end }}
+{{- $min }}
+{{- end }}
+
+{{/*
+Name of the secret holding the tls certificate of the servers
+*/}}
+{{- define "oxia-cluster.server.tlsSecretName" -}}
+{{ .Values.tls.server.secretName | default (printf "%s-server-tls" .Release.Name) }}
+{{- end }}
+
+{{/*
+Name of the secret holding the tls certificate of the coordinator
+*/}}
+{{- define "oxia-cluster.coordinator.tlsSecretName" -}}
+{{ .Values.tls.coordinator.secretName | default (printf "%s-coordinator-tls" .Release.Name) }}
+{{- end }}
+
+{{/*
+Arguments of the health probes to connect to a tls port, the certificate is
+verified against the given server name
+*/}}
+{{- define "oxia-cluster.probe-tls-args" -}}
+{{- if .tlsServerName -}}
+, "--tls-cert-file=/etc/oxia/tls/tls.crt", "--tls-key-file=/etc/oxia/tls/tls.key", "--tls-trusted-ca-file=/etc/oxia/tls/ca.crt", "--tls-server-name={{ .tlsServerName }}"
+{{- end }}
+{{- end }}
+
 {{/*
 Probe
 */}}
 {{- define "oxia-cluster.probe" -}}
 exec:
-  command: ["oxia", "health", "--port={{ . }}"]
-initialDelaySeconds: 10
-timeoutSeconds: 10
+  command: ["oxia", "health", "--port={{ .port }}"{{ include "oxia-cluster.probe-tls-args" . }}]
+{{- with .settings }}
+{{ toYaml . }}
+{{- end }}
 {{- end }}
 
 
@@ -65,9 +355,10 @@ Probe
 */}}
 {{- define "oxia-cluster.readiness-probe" -}}
 exec:
-  command: ["oxia", "health", "--port={{ . }}", "--service=oxia-readiness"]
-initialDelaySeconds: 10
-timeoutSeconds: 10
+  command: ["oxia", "health", "--port={{ .port }}", "--service=oxia-readiness"{{ include "oxia-cluster.probe-tls-args" . }}]
+{{- with .settings }}
+{{ toYaml . }}
+{{- end }}
 {{- end }}
 
 {{/*
@@ -75,8 +366,9 @@ Probe
 */}}
 {{- define "oxia-cluster.startup-probe" -}}
 exec:
-  command: ["oxia", "health", "--port={{ . }}"]
-initialDelaySeconds: 60
-timeoutSeconds: 10
+  command: ["oxia", "health", "--port={{ .port }}"{{ include "oxia-cluster.probe-tls-args" . }}]
+{{- with .settings }}
+{{ toYaml . }}
+{{- end }}
 {{- end }}
 
@@ -98,6 +98,22 @@ func (m *maelstromCoordinatorRpcProvider) DeleteShard(ctx context.Context, node
 	return res.(*proto.DeleteShardResponse), nil
 }
 
+func (*maelstromCoordinatorRpcProvider) ListShardSessions(context.Context, model.ServerAddress, *proto.ListShardSessionsRequest) (*proto.ListShardSessionsResponse, error) {
+	panic("not implemented")
+}
+
+func (*maelstromCoordinatorRpcProvider) CloseSession(context.Context, model.ServerAddress, *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+	panic("not implemented")
+}
+
+func (*maelstromCoordinatorRpcProvider) ExportRecords(context.Context, model.ServerAddress, *proto.ExportRecordsRequest) (proto.OxiaCoordination_ExportRecordsClient, error) {
+	panic("not implemented")
+}
+
+func (*maelstromCoordinatorRpcProvider) ImportRecords(context.Context, model.ServerAddress, *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	panic("not implemented")
+}
+
 func (m *maelstromCoordinatorRpcProvider) GetHealthClient(node model.ServerAddress) (grpc_health_v1.HealthClient, error) {
 	return &maelstromHealthCheckClient{
 		provider: m,
@@ -31,6 +31,7 @@ import (
 	pb "google.golang.org/protobuf/proto"
 
 	"github.com/streamnative/oxia/common/container"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/proto"
 )
 
@@ -55,7 +56,7 @@ func newMaelstromGrpcProvider() *maelstromGrpcProvider {
 }
 
 func (m *maelstromGrpcProvider) StartGrpcServer(name, _ string, registerFunc func(grpc.ServiceRegistrar),
-	_ *tls.Config, _ *auth.Options) (container.GrpcServer, error) {
+	_ *tls.Config, _ *security.IPFilter, _ *auth.Options) (container.GrpcServer, error) {
 	slog.Info(
 		"Start Grpc server",
 		slog.String("name", name),
@@ -0,0 +1,833 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oxia
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+// AdminClient is used to manage an Oxia cluster, through the admin API that is
+// exposed by the coordinator.
+type AdminClient interface {
+	io.Closer
+
+	// CreateNamespace creates a new namespace with the given number of shards
+	// and replication factor, without having to change the cluster config.
+	CreateNamespace(ctx context.Context, namespace string, initialShardCount uint32, replicationFactor uint32) error
+
+	// CreateTenantNamespace creates a new namespace that belongs to a tenant
+	// of the cluster config, within the quotas of the tenant.
+	CreateTenantNamespace(ctx context.Context, tenant string, namespace string, initialShardCount uint32, replicationFactor uint32) error
+
+	// DeleteNamespace deletes a namespace that was created with CreateNamespace,
+	// along with all the records it contains.
+	DeleteNamespace(ctx context.Context, namespace string) error
+
+	// DecommissionServer starts moving all the shard replicas out of a server,
+	// identified by its public or internal address. It returns without waiting
+	// for the replicas to be moved, and it can be called again to check whether
+	// the server can be removed from the cluster config.
+	DecommissionServer(ctx context.Context, server string) (DecommissionStatus, error)
+
+	// RebalanceCluster moves the shard replicas so that all the servers have a
+	// similar number of them, eg: after adding new servers. The moves are
+	// applied in the background, unless dryRun is set, and they are returned.
+	RebalanceCluster(ctx context.Context, dryRun bool) ([]ReplicaMove, error)
+
+	// PlanRebalance computes the moves of a rebalance, along with the size of
+	// the data to copy, without applying them. The plan replaces the previous
+	// one, and it's only applied once approved with ExecuteRebalancePlan.
+	PlanRebalance(ctx context.Context) (RebalancePlan, error)
+
+	// ExecuteRebalancePlan applies the moves of the plan in the background, one
+	// at a time. The plan is rejected if the cluster has changed since it was
+	// computed.
+	ExecuteRebalancePlan(ctx context.Context, id int64) error
+
+	// GetRebalancePlan returns the latest plan, with the progress of its
+	// execution, or nil if no plan was computed.
+	GetRebalancePlan(ctx context.Context) (*RebalancePlan, error)
+
+	// SetServerDrain puts a server in maintenance, or brings it back. The shards
+	// led by a drained server are moved to other leaders in the background,
+	// and no new replica is placed on it. It returns the number of shards
+	// that are still led by the server.
+	SetServerDrain(ctx context.Context, server string, drain bool) (uint32, error)
+
+	// ReplaceServer moves all the replicas of a failed server to a new server,
+	// one shard at a time. It returns without waiting for the new server to
+	// recover the shards, along with the number of shards being moved.
+	ReplaceServer(ctx context.Context, oldServer string, newServer string) (uint32, error)
+
+	// ListNamespaces returns all the namespaces of the cluster.
+	ListNamespaces(ctx context.Context) ([]NamespaceInfo, error)
+
+	// ListShards returns the shards of a namespace, with their ensembles and leaders.
+	ListShards(ctx context.Context, namespace string) ([]ShardInfo, error)
+
+	// ListServers returns the servers of the cluster, with their health.
+	ListServers(ctx context.Context) ([]ServerInfo, error)
+
+	// ListOperations returns the changes to the cluster that are in progress.
+	ListOperations(ctx context.Context) ([]OperationInfo, error)
+
+	// TransferLeader moves the leadership of a shard to another member of its
+	// ensemble, identified by its public or internal address. The new leader
+	// is only elected once it has caught up with the current one.
+	TransferLeader(ctx context.Context, namespace string, shard int64, server string) error
+
+	// MergeShard copies the records of a shard into the adjacent shard
+	// `into`, which then takes over its hash range, and deletes the shard.
+	// The request is rejected if the shard holds any client session.
+	MergeShard(ctx context.Context, namespace string, shard int64, into int64) error
+
+	// ListSessions returns the client sessions that are active on the shards
+	// of a namespace, or on a single shard if one is given.
+	ListSessions(ctx context.Context, namespace string, shard *int64) ([]SessionInfo, error)
+
+	// ForceCloseSession closes a client session, as if it had expired. Its
+	// ephemeral records are deleted.
+	ForceCloseSession(ctx context.Context, namespace string, shard int64, sessionId int64) error
+
+	// SetClusterFreeze starts or ends a maintenance freeze, during which the
+	// coordinator doesn't move any shard replica or leader on its own. The
+	// leader elections still happen while the cluster is frozen.
+	SetClusterFreeze(ctx context.Context, freeze bool, reason string) error
+
+	// SetKeyPrefixACL grants permissions to a principal on the keys of a
+	// namespace starting with a prefix, replacing the permissions it had on
+	// the same prefix.
+	SetKeyPrefixACL(ctx context.Context, namespace string, acl KeyPrefixACL) error
+
+	// RemoveKeyPrefixACL removes the permissions of a principal on a prefix.
+	RemoveKeyPrefixACL(ctx context.Context, namespace string, principal string, prefix string) error
+
+	// ListKeyPrefixACLs returns the permissions granted on the key prefixes
+	// of a namespace.
+	ListKeyPrefixACLs(ctx context.Context, namespace string) ([]KeyPrefixACL, error)
+
+	// ListEvents returns the latest decisions taken by the coordinator and the
+	// changes it observed, from the oldest to the newest. With a limit of 0,
+	// all the events kept by the coordinator are returned.
+	ListEvents(ctx context.Context, limit uint32) ([]EventInfo, error)
+
+	// WatchEvents calls the handler with each of the coordinator events, as
+	// they happen. With includeRecent, it's called first with the events
+	// already kept by the coordinator. It blocks until the context is
+	// cancelled or the stream is interrupted, eg: when the coordinator
+	// changes.
+	WatchEvents(ctx context.Context, includeRecent bool, handler func(EventInfo)) error
+
+	// ExportClusterStatus returns the full cluster status, serialized as json.
+	// The export can be used to bootstrap a new coordinator after the loss of
+	// the metadata store.
+	ExportClusterStatus(ctx context.Context) ([]byte, error)
+
+	// ListAuditRecords returns the latest records of the audit log, from the
+	// oldest to the newest. With a limit of 0, all the records are returned.
+	ListAuditRecords(ctx context.Context, limit uint32) ([]AuditRecord, error)
+}
+
+// ReplicaMove is the move of the replica of a shard from one server to another,
+// identified by their internal addresses.
+type ReplicaMove struct {
+	Shard int64  `json:"shard"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+
+	// EstimatedBytes is the size of the shard data to copy, if known. It's
+	// only set in the rebalance plans.
+	EstimatedBytes *int64 `json:"estimatedBytes,omitempty"`
+
+	// State is the progress of the move in a rebalance plan
+	State string `json:"state,omitempty"`
+}
+
+// RebalancePlan is a list of replica moves computed in advance, that are
+// only applied once the plan is approved.
+type RebalancePlan struct {
+	Id        int64         `json:"id"`
+	State     string        `json:"state"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Moves     []ReplicaMove `json:"moves"`
+}
+
+// DecommissionStatus is the progress of a server decommission.
+type DecommissionStatus struct {
+	// Completed is true once the server can be removed from the cluster
+	Completed bool
+
+	// RemainingShards is the number of shards that still have a replica on the server
+	RemainingShards uint32
+}
+
+// NamespaceInfo describes a namespace of the cluster.
+type NamespaceInfo struct {
+	Name              string `json:"name"`
+	ReplicationFactor uint32 `json:"replicationFactor"`
+	Shards            uint32 `json:"shards"`
+
+	// Dynamic is true for the namespaces created through the admin API
+	Dynamic bool `json:"dynamic,omitempty"`
+
+	// DeletedAt is set when the namespace is soft-deleted
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// ShardInfo describes a shard and its ensemble. The servers are identified
+// by their internal addresses.
+type ShardInfo struct {
+	Shard        int64    `json:"shard"`
+	Status       string   `json:"status"`
+	Term         int64    `json:"term"`
+	Leader       string   `json:"leader,omitempty"`
+	Ensemble     []string `json:"ensemble"`
+	Int32HashMin uint32   `json:"int32HashMin"`
+	Int32HashMax uint32   `json:"int32HashMax"`
+}
+
+// ServerInfo describes a server of the cluster, as seen by the coordinator.
+type ServerInfo struct {
+	PublicAddress   string `json:"publicAddress"`
+	InternalAddress string `json:"internalAddress"`
+
+	// Running is true if the server is reachable by the coordinator
+	Running      bool   `json:"running"`
+	Draining     bool   `json:"draining,omitempty"`
+	Decommission string `json:"decommission,omitempty"`
+
+	// Replicas is the number of shards with a replica on the server
+	Replicas uint32 `json:"replicas"`
+
+	// Leaders is the number of shards led by the server
+	Leaders uint32            `json:"leaders"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// OperationInfo describes a change to the cluster that is in progress.
+type OperationInfo struct {
+	Type        string     `json:"type"`
+	Namespace   string     `json:"namespace,omitempty"`
+	Shard       *int64     `json:"shard,omitempty"`
+	Server      string     `json:"server,omitempty"`
+	Description string     `json:"description"`
+	StartTime   *time.Time `json:"startTime,omitempty"`
+}
+
+// SessionInfo describes a client session, as reported by the shard leader.
+type SessionInfo struct {
+	Shard     int64 `json:"shard"`
+	SessionId int64 `json:"sessionId"`
+
+	// Leader is the internal address of the shard leader
+	Leader         string        `json:"leader"`
+	ClientIdentity string        `json:"clientIdentity"`
+	Timeout        time.Duration `json:"timeout"`
+	CreatedTime    time.Time     `json:"createdTime"`
+
+	// LastHeartbeat is only tracked since the current leader took over the shard
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+	EphemeralKeys int64     `json:"ephemeralKeys"`
+}
+
+// KeyPrefixACL grants permissions to a principal on the keys starting with a
+// prefix, which must end with `/`. The permissions are "read", "write" and
+// "admin".
+type KeyPrefixACL struct {
+	Principal   string   `json:"principal"`
+	Prefix      string   `json:"prefix"`
+	Permissions []string `json:"permissions"`
+}
+
+// EventInfo describes a decision taken by the coordinator, or a change it
+// observed in the cluster.
+type EventInfo struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Shard       *int64    `json:"shard,omitempty"`
+	Server      string    `json:"server,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// AuditRecord is a change to the cluster, as recorded in the audit log of
+// the coordinator: either requested through the admin API or decided by the
+// coordinator itself.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Namespace string    `json:"namespace,omitempty"`
+	Shard     *int64    `json:"shard,omitempty"`
+	Server    string    `json:"server,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+
+	// Error is set when the change was rejected
+	Error string `json:"error,omitempty"`
+}
+
+// WithAuditReason returns a context whose admin requests carry the reason of
+// the change, which is recorded in the audit log of the coordinator.
+func WithAuditReason(ctx context.Context, reason string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, common.MetadataAuditReason, reason)
+}
+
+type adminClientImpl struct {
+	options    clientOptions
+	clientPool common.ClientPool
+}
+
+// NewAdminClient creates a new client for the admin API of the coordinator
+//
+// CoordinatorAddress is the target host:port of the coordinator internal service.
+//
+// The TLS and authentication options are honored, while the other ClientOption
+// arguments are ignored.
+// Example:
+//
+//	client, err := oxia.NewAdminClient("my-oxia-coordinator:6649")
+func NewAdminClient(coordinatorAddress string, opts ...ClientOption) (AdminClient, error) {
+	options, err := newClientOptions(coordinatorAddress, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminClientImpl{
+		options:    options,
+		clientPool: common.NewClientPool(options.tls, options.authentication),
+	}, nil
+}
+
+func (c *adminClientImpl) Close() error {
+	return c.clientPool.Close()
+}
+
+func (c *adminClientImpl) CreateNamespace(ctx context.Context, namespace string, initialShardCount uint32, replicationFactor uint32) error {
+	return c.CreateTenantNamespace(ctx, "", namespace, initialShardCount, replicationFactor)
+}
+
+func (c *adminClientImpl) CreateTenantNamespace(ctx context.Context, tenant string, namespace string,
+	initialShardCount uint32, replicationFactor uint32) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.CreateNamespace(ctx, &proto.CreateNamespaceRequest{
+		Namespace:         namespace,
+		InitialShardCount: initialShardCount,
+		ReplicationFactor: replicationFactor,
+		Tenant:            tenant,
+	})
+	return err
+}
+
+func (c *adminClientImpl) DeleteNamespace(ctx context.Context, namespace string) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.DeleteNamespace(ctx, &proto.DeleteNamespaceRequest{
+		Namespace: namespace,
+	})
+	return err
+}
+
+func (c *adminClientImpl) DecommissionServer(ctx context.Context, server string) (DecommissionStatus, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return DecommissionStatus{}, err
+	}
+
+	res, err := rpc.DecommissionServer(ctx, &proto.DecommissionServerRequest{
+		Server: server,
+	})
+	if err != nil {
+		return DecommissionStatus{}, err
+	}
+
+	return DecommissionStatus{
+		Completed:       res.State == proto.DecommissionState_COMPLETED,
+		RemainingShards: res.RemainingShards,
+	}, nil
+}
+
+func (c *adminClientImpl) RebalanceCluster(ctx context.Context, dryRun bool) ([]ReplicaMove, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.RebalanceCluster(ctx, &proto.RebalanceClusterRequest{
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	moves := make([]ReplicaMove, 0, len(res.Moves))
+	for _, m := range res.Moves {
+		moves = append(moves, ReplicaMove{
+			Shard: m.Shard,
+			From:  m.From,
+			To:    m.To,
+		})
+	}
+	return moves, nil
+}
+
+func (c *adminClientImpl) PlanRebalance(ctx context.Context) (RebalancePlan, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return RebalancePlan{}, err
+	}
+
+	res, err := rpc.PlanRebalance(ctx, &proto.PlanRebalanceRequest{})
+	if err != nil {
+		return RebalancePlan{}, err
+	}
+	return toRebalancePlan(res.Plan), nil
+}
+
+func (c *adminClientImpl) ExecuteRebalancePlan(ctx context.Context, id int64) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.ExecuteRebalancePlan(ctx, &proto.ExecuteRebalancePlanRequest{Id: id})
+	return err
+}
+
+func (c *adminClientImpl) GetRebalancePlan(ctx context.Context) (*RebalancePlan, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.GetRebalancePlan(ctx, &proto.GetRebalancePlanRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if res.Plan == nil {
+		return nil, nil
+	}
+
+	plan := toRebalancePlan(res.Plan)
+	return &plan, nil
+}
+
+func toRebalancePlan(plan *proto.RebalancePlan) RebalancePlan {
+	res := RebalancePlan{
+		Id:        plan.Id,
+		State:     plan.State,
+		CreatedAt: time.UnixMilli(int64(plan.CreatedTimestamp)),
+		Moves:     make([]ReplicaMove, 0, len(plan.Moves)),
+	}
+	for _, m := range plan.Moves {
+		res.Moves = append(res.Moves, ReplicaMove{
+			Shard:          m.Shard,
+			From:           m.From,
+			To:             m.To,
+			EstimatedBytes: m.EstimatedBytes,
+			State:          m.GetState(),
+		})
+	}
+	return res
+}
+
+func (c *adminClientImpl) SetServerDrain(ctx context.Context, server string, drain bool) (uint32, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := rpc.SetServerDrain(ctx, &proto.SetServerDrainRequest{
+		Server: server,
+		Drain:  drain,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.LeaderShards, nil
+}
+
+func (c *adminClientImpl) ReplaceServer(ctx context.Context, oldServer string, newServer string) (uint32, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := rpc.ReplaceServer(ctx, &proto.ReplaceServerRequest{
+		OldServer: oldServer,
+		NewServer: newServer,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.Shards, nil
+}
+
+func (c *adminClientImpl) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListNamespaces(ctx, &proto.ListNamespacesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]NamespaceInfo, 0, len(res.Namespaces))
+	for _, n := range res.Namespaces {
+		ni := NamespaceInfo{
+			Name:              n.Name,
+			ReplicationFactor: n.ReplicationFactor,
+			Shards:            n.Shards,
+			Dynamic:           n.Dynamic,
+		}
+		if n.DeletedTimestamp != nil {
+			deletedAt := time.UnixMilli(int64(*n.DeletedTimestamp))
+			ni.DeletedAt = &deletedAt
+		}
+		namespaces = append(namespaces, ni)
+	}
+	return namespaces, nil
+}
+
+func (c *adminClientImpl) ListShards(ctx context.Context, namespace string) ([]ShardInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListShards(ctx, &proto.ListShardsRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]ShardInfo, 0, len(res.Shards))
+	for _, s := range res.Shards {
+		shards = append(shards, ShardInfo{
+			Shard:        s.Shard,
+			Status:       s.Status,
+			Term:         s.Term,
+			Leader:       s.GetLeader(),
+			Ensemble:     s.Ensemble,
+			Int32HashMin: s.Int32HashMin,
+			Int32HashMax: s.Int32HashMax,
+		})
+	}
+	return shards, nil
+}
+
+func (c *adminClientImpl) ListServers(ctx context.Context) ([]ServerInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListServers(ctx, &proto.ListServersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make([]ServerInfo, 0, len(res.Servers))
+	for _, s := range res.Servers {
+		servers = append(servers, ServerInfo{
+			PublicAddress:   s.PublicAddress,
+			InternalAddress: s.InternalAddress,
+			Running:         s.Running,
+			Draining:        s.Draining,
+			Decommission:    s.Decommission,
+			Replicas:        s.Replicas,
+			Leaders:         s.Leaders,
+			Labels:          s.Labels,
+		})
+	}
+	return servers, nil
+}
+
+func (c *adminClientImpl) ListOperations(ctx context.Context) ([]OperationInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListOperations(ctx, &proto.ListOperationsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]OperationInfo, 0, len(res.Operations))
+	for _, o := range res.Operations {
+		oi := OperationInfo{
+			Type:        o.Type,
+			Namespace:   o.GetNamespace(),
+			Shard:       o.Shard,
+			Server:      o.GetServer(),
+			Description: o.Description,
+		}
+		if o.StartTimestamp != nil {
+			startTime := time.UnixMilli(int64(*o.StartTimestamp))
+			oi.StartTime = &startTime
+		}
+		operations = append(operations, oi)
+	}
+	return operations, nil
+}
+
+func (c *adminClientImpl) TransferLeader(ctx context.Context, namespace string, shard int64, server string) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.TransferLeader(ctx, &proto.TransferLeaderRequest{
+		Namespace: namespace,
+		Shard:     shard,
+		Server:    server,
+	})
+	return err
+}
+
+func (c *adminClientImpl) MergeShard(ctx context.Context, namespace string, shard int64, into int64) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.MergeShard(ctx, &proto.MergeShardRequest{
+		Namespace: namespace,
+		Shard:     shard,
+		IntoShard: into,
+	})
+	return err
+}
+
+func (c *adminClientImpl) ListSessions(ctx context.Context, namespace string, shard *int64) ([]SessionInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListSessions(ctx, &proto.ListSessionsRequest{
+		Namespace: namespace,
+		Shard:     shard,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]SessionInfo, 0, len(res.Sessions))
+	for _, s := range res.Sessions {
+		sessions = append(sessions, SessionInfo{
+			Shard:          s.Shard,
+			SessionId:      s.SessionId,
+			Leader:         s.Leader,
+			ClientIdentity: s.ClientIdentity,
+			Timeout:        time.Duration(s.TimeoutMs) * time.Millisecond,
+			CreatedTime:    time.UnixMilli(int64(s.CreatedTimestamp)),
+			LastHeartbeat:  time.UnixMilli(int64(s.LastHeartbeatTimestamp)),
+			EphemeralKeys:  s.EphemeralKeys,
+		})
+	}
+	return sessions, nil
+}
+
+func (c *adminClientImpl) ForceCloseSession(ctx context.Context, namespace string, shard int64, sessionId int64) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.ForceCloseSession(ctx, &proto.ForceCloseSessionRequest{
+		Namespace: namespace,
+		Shard:     shard,
+		SessionId: sessionId,
+	})
+	return err
+}
+
+func (c *adminClientImpl) SetClusterFreeze(ctx context.Context, freeze bool, reason string) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.SetClusterFreeze(ctx, &proto.SetClusterFreezeRequest{
+		Freeze: freeze,
+		Reason: reason,
+	})
+	return err
+}
+
+func (c *adminClientImpl) SetKeyPrefixACL(ctx context.Context, namespace string, acl KeyPrefixACL) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.SetKeyPrefixACL(ctx, &proto.SetKeyPrefixACLRequest{
+		Namespace: namespace,
+		Acl: &proto.KeyPrefixACL{
+			Principal:   acl.Principal,
+			Prefix:      acl.Prefix,
+			Permissions: acl.Permissions,
+		},
+	})
+	return err
+}
+
+func (c *adminClientImpl) RemoveKeyPrefixACL(ctx context.Context, namespace string, principal string, prefix string) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.RemoveKeyPrefixACL(ctx, &proto.RemoveKeyPrefixACLRequest{
+		Namespace: namespace,
+		Principal: principal,
+		Prefix:    prefix,
+	})
+	return err
+}
+
+func (c *adminClientImpl) ListKeyPrefixACLs(ctx context.Context, namespace string) ([]KeyPrefixACL, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListKeyPrefixACLs(ctx, &proto.ListKeyPrefixACLsRequest{Namespace: namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	acls := make([]KeyPrefixACL, 0, len(res.Acls))
+	for _, acl := range res.Acls {
+		acls = append(acls, KeyPrefixACL{
+			Principal:   acl.Principal,
+			Prefix:      acl.Prefix,
+			Permissions: acl.Permissions,
+		})
+	}
+	return acls, nil
+}
+
+func (c *adminClientImpl) ListEvents(ctx context.Context, limit uint32) ([]EventInfo, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListEvents(ctx, &proto.ListEventsRequest{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]EventInfo, 0, len(res.Events))
+	for _, e := range res.Events {
+		events = append(events, toEventInfo(e))
+	}
+	return events, nil
+}
+
+func (c *adminClientImpl) WatchEvents(ctx context.Context, includeRecent bool, handler func(EventInfo)) error {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return err
+	}
+
+	stream, err := rpc.WatchEvents(ctx, &proto.WatchEventsRequest{IncludeRecent: includeRecent})
+	if err != nil {
+		return err
+	}
+
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		handler(toEventInfo(e))
+	}
+}
+
+func toEventInfo(e *proto.EventInfo) EventInfo {
+	return EventInfo{
+		Time:        time.UnixMilli(int64(e.Timestamp)),
+		Type:        e.Type,
+		Namespace:   e.GetNamespace(),
+		Shard:       e.Shard,
+		Server:      e.GetServer(),
+		Description: e.Description,
+	}
+}
+
+func (c *adminClientImpl) ExportClusterStatus(ctx context.Context) ([]byte, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ExportClusterStatus(ctx, &proto.ExportClusterStatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.ClusterStatus, nil
+}
+
+func (c *adminClientImpl) ListAuditRecords(ctx context.Context, limit uint32) ([]AuditRecord, error) {
+	rpc, err := c.clientPool.GetAdminRpc(c.options.serviceAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rpc.ListAuditRecords(ctx, &proto.ListAuditRecordsRequest{Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]AuditRecord, 0, len(res.Records))
+	for _, r := range res.Records {
+		records = append(records, AuditRecord{
+			Time:      time.UnixMilli(int64(r.Timestamp)),
+			Actor:     r.Actor,
+			Action:    r.Action,
+			Namespace: r.GetNamespace(),
+			Shard:     r.Shard,
+			Server:    r.GetServer(),
+			Details:   r.Details,
+			Reason:    r.Reason,
+			Error:     r.GetError(),
+		})
+	}
+	return records, nil
+}
@@ -114,19 +114,39 @@ func (c *clientImpl) Close() error {
 func (c *clientImpl) Put(key string, value []byte, options ...PutOption) <-chan PutResult {
 	ch := make(chan PutResult, 1)
 
-	callback := func(response *proto.PutResponse, err error) {
+	c.put(key, value, nil, options, func(response *proto.PutResponse, err error) {
 		if err != nil {
 			ch <- PutResult{Err: err}
 		} else {
 			ch <- toPutResult(key, response)
 		}
 		close(ch)
-	}
+	})
+	return ch
+}
+
+func (c *clientImpl) Increment(key string, delta int64, options ...PutOption) <-chan IncrementResult {
+	ch := make(chan IncrementResult, 1)
+
+	c.put(key, nil, &delta, options, func(response *proto.PutResponse, err error) {
+		if err != nil {
+			ch <- IncrementResult{Err: err}
+		} else {
+			ch <- toIncrementResult(response)
+		}
+		close(ch)
+	})
+	return ch
+}
 
+func (c *clientImpl) put(key string, value []byte, incrementDelta *int64, options []PutOption, callback func(*proto.PutResponse, error)) {
 	opts, err := newPutOptions(options)
+	if err == nil && incrementDelta != nil && len(opts.sequenceKeysDeltas) > 0 {
+		err = errors.Wrap(ErrInvalidOptions, "an increment cannot use sequential keys")
+	}
 	if err != nil {
 		callback(nil, err)
-		return ch
+		return
 	}
 
 	shardId := c.getShardForKey(key, opts)
@@ -136,9 +156,11 @@ func (c *clientImpl) Put(key string, value []byte, options ...PutOption) <-chan
 		ExpectedVersionId:  opts.expectedVersion,
 		SequenceKeysDeltas: opts.sequenceKeysDeltas,
 		PartitionKey:       opts.partitionKey,
+		IncrementDelta:     incrementDelta,
 		Callback:           callback,
 	}
-	if opts.ephemeral {
+	switch {
+	case opts.ephemeral:
 		putCall.ClientIdentity = &c.options.identity
 		c.sessions.executeWithSessionId(shardId, func(sessionId int64, err error) {
 			if err != nil {
@@ -148,10 +170,29 @@ func (c *clientImpl) Put(key string, value []byte, options ...PutOption) <-chan
 			putCall.SessionId = &sessionId
 			c.writeBatchManager.Get(shardId).Add(putCall)
 		})
-	} else {
+	case opts.lease != nil:
+		l := opts.lease.(*lease)
+		putCall.ClientIdentity = &c.options.identity
+		putCall.Callback = func(response *proto.PutResponse, err error) {
+			if err == nil && response.Status == proto.Status_SESSION_DOES_NOT_EXIST {
+				// The lease session was not refreshed in time
+				go l.expire(c.ctx)
+				response = nil
+				err = ErrLeaseExpired
+			}
+			callback(response, err)
+		}
+		l.executeWithSessionId(shardId, func(sessionId int64, err error) {
+			if err != nil {
+				callback(nil, err)
+				return
+			}
+			putCall.SessionId = &sessionId
+			c.writeBatchManager.Get(shardId).Add(putCall)
+		})
+	default:
 		c.writeBatchManager.Get(shardId).Add(putCall)
 	}
-	return ch
 }
 
 func (c *clientImpl) Delete(key string, options ...DeleteOption) <-chan error {
@@ -184,7 +225,8 @@ func (c *clientImpl) DeleteRange(minKeyInclusive string, maxKeyExclusive string,
 	}
 
 	// If there is no partition key, we will make the request to delete-range on all the shards
-	shardIds := c.shardManager.GetAll()
+	// that can contain the range
+	shardIds := c.shardManager.GetRange(minKeyInclusive, maxKeyExclusive)
 	wg := common.NewWaitGroup(len(shardIds))
 
 	for _, shardId := range shardIds {
@@ -360,7 +402,7 @@ func (c *clientImpl) List(ctx context.Context, minKeyInclusive string, maxKeyExc
 		}()
 	} else {
 		// Do the list on all shards and aggregate the responses
-		shardIds := c.shardManager.GetAll()
+		shardIds := c.shardManager.GetRange(minKeyInclusive, maxKeyExclusive)
 
 		wg := common.NewWaitGroup(len(shardIds))
 		for _, shardId := range shardIds {
@@ -425,7 +467,7 @@ func (c *clientImpl) RangeScan(ctx context.Context, minKeyInclusive string, maxK
 		}()
 	} else {
 		// Do the list on all shards and aggregate the responses
-		shardIds := c.shardManager.GetAll()
+		shardIds := c.shardManager.GetRange(minKeyInclusive, maxKeyExclusive)
 		channels := make([]chan GetResult, len(shardIds))
 
 		for i, shardId := range shardIds {
@@ -501,8 +543,13 @@ func (c *clientImpl) getShardForKey(key string, options baseOptionsIf) int64 {
 	return c.shardManager.Get(key)
 }
 
-func (c *clientImpl) GetNotifications() (Notifications, error) {
-	nm, err := newNotifications(c.ctx, c.options, c.clientPool, c.shardManager)
+func (c *clientImpl) GetNotifications(options ...NotificationsOption) (Notifications, error) {
+	opts, err := newNotificationsOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	nm, err := newNotifications(c.ctx, c.options, opts, c.clientPool, c.shardManager)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to create notification stream")
 	}
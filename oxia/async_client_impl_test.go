@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
 	"github.com/streamnative/oxia/server"
 )
 
@@ -260,6 +262,57 @@ func TestAsyncClientImpl_Sessions(t *testing.T) {
 	assert.NoError(t, standaloneServer.Close())
 }
 
+func TestAsyncClientImpl_SessionEvents(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	events := make(chan SessionEvent, 10)
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewAsyncClient(serviceAddress, WithBatchLinger(0),
+		WithSessionListener(func(e SessionEvent) { events <- e }))
+	assert.NoError(t, err)
+
+	nextEvent := func() SessionEvent {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(10 * time.Second):
+			assert.FailNow(t, "Shouldn't have timed out")
+			return SessionEvent{}
+		}
+	}
+
+	res := <-client.Put("/x", []byte("x"), Ephemeral())
+	assert.NoError(t, res.Err)
+
+	e := nextEvent()
+	assert.Equal(t, SessionEstablished, e.Type)
+	shard, sessionId := e.Shard, e.SessionId
+
+	// Close the session on the server side, as if it had expired
+	ci := client.(*clientImpl)
+	rpc, err := ci.clientPool.GetClientRpc(ci.shardManager.Leader(shard))
+	assert.NoError(t, err)
+	_, err = rpc.CloseSession(context.Background(), &proto.CloseSessionRequest{ShardId: shard, SessionId: sessionId})
+	assert.NoError(t, err)
+
+	e = nextEvent()
+	assert.Equal(t, SessionExpired, e.Type)
+	assert.Equal(t, sessionId, e.SessionId)
+	assert.Error(t, e.Err)
+
+	res = <-client.Put("/x", []byte("x"), Ephemeral())
+	assert.NoError(t, res.Err)
+
+	e = nextEvent()
+	assert.Equal(t, SessionReacquired, e.Type)
+	assert.Equal(t, shard, e.Shard)
+	assert.NotEqual(t, sessionId, e.SessionId)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
 func TestAsyncClientImpl_OverrideEphemeral(t *testing.T) {
 	client, err := NewSyncClient(serviceAddress,
 		WithSessionTimeout(5*time.Second),
@@ -370,6 +423,213 @@ func TestSyncClientImpl_SessionNotifications(t *testing.T) {
 	assert.NoError(t, standaloneServer.Close())
 }
 
+func TestSyncClientImpl_NotificationsKeyPrefixes(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	notifications, err := client.GetNotifications(KeyPrefixes("/a/"))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "/b/1", []byte("0"))
+	assert.NoError(t, err)
+	_, v, err := client.Put(ctx, "/a/1", []byte("1"))
+	assert.NoError(t, err)
+
+	select {
+	case n := <-notifications.Ch():
+		assert.Equal(t, KeyCreated, n.Type)
+		assert.Equal(t, "/a/1", n.Key)
+		assert.Equal(t, v.VersionId, n.VersionId)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "read from channel timed out")
+	}
+
+	assert.NoError(t, notifications.Close())
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestSyncClientImpl_NotificationsStartFromOffsets(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	notifications, err := client.GetNotifications()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "/a", []byte("0"))
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, "/b", []byte("1"))
+	assert.NoError(t, err)
+
+	var last *Notification
+	for i := 0; i < 2; i++ {
+		select {
+		case last = <-notifications.Ch():
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "read from channel timed out")
+		}
+	}
+	assert.Equal(t, "/b", last.Key)
+	assert.NoError(t, notifications.Close())
+
+	// Notifications written while no subscriber is active
+	_, _, err = client.Put(ctx, "/c", []byte("2"))
+	assert.NoError(t, err)
+
+	notifications, err = client.GetNotifications(StartFromOffsets(map[int64]int64{last.Shard: last.Offset}))
+	assert.NoError(t, err)
+
+	for _, key := range []string{"/b", "/c"} {
+		select {
+		case n := <-notifications.Ch():
+			assert.Equal(t, key, n.Key)
+			assert.Equal(t, last.Shard, n.Shard)
+			assert.GreaterOrEqual(t, n.Offset, last.Offset)
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "read from channel timed out")
+		}
+	}
+
+	assert.NoError(t, notifications.Close())
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestSyncClientImpl_NotificationsEventTypes(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	_, err = client.GetNotifications(EventTypes(NotificationType(10)))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	filtered, err := client.GetNotifications(EventTypes(KeyModified, KeyRangeDeleted), IncludeValue())
+	assert.NoError(t, err)
+	all, err := client.GetNotifications()
+	assert.NoError(t, err)
+
+	next := func(notifications Notifications) *Notification {
+		select {
+		case n := <-notifications.Ch():
+			return n
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "read from channel timed out")
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	_, _, err = client.Put(ctx, "/a", []byte("0"))
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, "/a", []byte("1"))
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, "/b", []byte("2"))
+	assert.NoError(t, err)
+	assert.NoError(t, client.Delete(ctx, "/b"))
+
+	n := next(filtered)
+	assert.Equal(t, KeyModified, n.Type)
+	assert.Equal(t, "/a", n.Key)
+	assert.Equal(t, []byte("1"), n.Value)
+
+	assert.NoError(t, client.DeleteRange(ctx, "/a", "/b"))
+
+	n = next(filtered)
+	assert.Equal(t, KeyRangeDeleted, n.Type)
+	assert.Equal(t, "/a", n.Key)
+	assert.Nil(t, n.Value)
+
+	// Without selecting them, the range deletions are received as deletions
+	var types []NotificationType
+	for i := 0; i < 5; i++ {
+		n = next(all)
+		assert.Nil(t, n.Value)
+		types = append(types, n.Type)
+	}
+	assert.Equal(t, []NotificationType{KeyCreated, KeyModified, KeyCreated, KeyDeleted, KeyDeleted}, types)
+
+	assert.NoError(t, filtered.Close())
+	assert.NoError(t, all.Close())
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestSyncClientImpl_NotificationsConsumerName(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	next := func(notifications Notifications) *Notification {
+		select {
+		case n := <-notifications.Ch():
+			return n
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "read from channel timed out")
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	notifications, err := client.GetNotifications()
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, "/a", []byte("0"))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, notifications.Ack(ctx, next(notifications)), ErrInvalidOptions)
+	assert.NoError(t, notifications.Close())
+
+	notifications, err = client.GetNotifications(ConsumerName("consumer-1"))
+	assert.NoError(t, err)
+
+	for _, key := range []string{"/b", "/c", "/d"} {
+		_, _, err = client.Put(ctx, key, []byte("0"))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "/b", next(notifications).Key)
+	n := next(notifications)
+	assert.Equal(t, "/c", n.Key)
+	assert.NoError(t, notifications.Ack(ctx, n))
+	assert.NoError(t, notifications.Close())
+
+	_, _, err = client.Put(ctx, "/e", []byte("0"))
+	assert.NoError(t, err)
+
+	// The consumer resumes after the last acknowledged notification
+	notifications, err = client.GetNotifications(ConsumerName("consumer-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/d", next(notifications).Key)
+	n = next(notifications)
+	assert.Equal(t, "/e", n.Key)
+	assert.NoError(t, notifications.Ack(ctx, n))
+	assert.NoError(t, notifications.Close())
+
+	// A different consumer only receives the new notifications
+	notifications, err = client.GetNotifications(ConsumerName("consumer-2"))
+	assert.NoError(t, err)
+	_, _, err = client.Put(ctx, "/f", []byte("0"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/f", next(notifications).Key)
+	assert.NoError(t, notifications.Close())
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
 func TestSyncClientImpl_FloorCeilingGet(t *testing.T) {
 	config := server.NewTestConfig(t.TempDir())
 	// Test with multiple shards to ensure correctness across shards
@@ -660,6 +920,46 @@ func TestSyncClientImpl_PartitionRouting(t *testing.T) {
 	assert.NoError(t, standaloneServer.Close())
 }
 
+func TestSyncClientImpl_SequentialEphemeralKeys(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client1, err := NewSyncClient(serviceAddress, WithSessionTimeout(2*time.Second))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	key1, version, err := client1.Put(ctx, "/election/candidate", []byte("c-1"),
+		SequenceKeysDeltas(1), PartitionKey("election"), Ephemeral())
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("/election/candidate-%020d", 1), key1)
+	assert.True(t, version.Ephemeral)
+
+	assert.NoError(t, client1.Close())
+
+	client2, err := NewSyncClient(serviceAddress, WithSessionTimeout(2*time.Second))
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, _, _, err := client2.Get(ctx, key1, PartitionKey("election"))
+		return errors.Is(err, ErrKeyNotFound)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// The sequence is not reused after the ephemeral record is removed
+	key2, _, err := client2.Put(ctx, "/election/candidate", []byte("c-2"),
+		SequenceKeysDeltas(1), PartitionKey("election"), Ephemeral())
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("/election/candidate-%020d", 2), key2)
+
+	keys, err := client2.List(ctx, "/election/", "/election//", PartitionKey("election"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{key2}, keys)
+
+	assert.NoError(t, client2.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
 func TestSyncClientImpl_SequentialKeys(t *testing.T) {
 	config := server.NewTestConfig(t.TempDir())
 	// Test with multiple shards to ensure correctness across shards
@@ -723,6 +1023,48 @@ func TestSyncClientImpl_SequentialKeys(t *testing.T) {
 	assert.NoError(t, standaloneServer.Close())
 }
 
+func TestSyncClientImpl_Increment(t *testing.T) {
+	standaloneServer, err := server.NewStandalone(server.NewTestConfig(t.TempDir()))
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	value, version, err := client.Increment(ctx, "counter", 3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, value)
+	assert.EqualValues(t, 0, version.ModificationsCount)
+
+	// Concurrent increments are all applied
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.Increment(ctx, "counter", 1)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, res, _, err := client.Get(ctx, "counter")
+	assert.NoError(t, err)
+	assert.Equal(t, "13", string(res))
+
+	_, _, err = client.Put(ctx, "not-a-counter", []byte("value"))
+	assert.NoError(t, err)
+	_, _, err = client.Increment(ctx, "not-a-counter", 1)
+	assert.ErrorIs(t, err, ErrInvalidCounterValue)
+
+	_, _, err = client.Increment(ctx, "counter", 1, PartitionKey("x"), SequenceKeysDeltas(1))
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
 func TestSyncClientImpl_RangeScan(t *testing.T) {
 	config := server.NewTestConfig(t.TempDir())
 	// Test with multiple shards to ensure correctness across shards
@@ -18,7 +18,9 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 
+	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/oxia/internal/batch"
 )
 
@@ -37,6 +39,32 @@ var (
 
 	ErrInvalidOptions = errors.New("invalid options")
 
+	// ErrInvalidCounterValue The value of the record is not a valid counter for
+	// an increment, or the increment would overflow it.
+	ErrInvalidCounterValue = errors.New("invalid counter value")
+
+	// ErrSessionNotFound The session of an ephemeral record doesn't exist anymore.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrLeaseExpired The lease was not refreshed in time, or was revoked by another client
+	// sharing it, and its records were deleted.
+	ErrLeaseExpired = errors.New("lease expired")
+
+	// ErrLeaseRevoked The lease was explicitly revoked and its records were deleted.
+	ErrLeaseRevoked = errors.New("lease revoked")
+
+	// ErrThrottled The client exceeded the rate of operations allowed to each
+	// client by the policies of the namespace.
+	ErrThrottled = common.ErrorThrottled
+
+	// ErrPermissionDenied The principal of the client doesn't have the
+	// permission for the operation on the namespace.
+	ErrPermissionDenied = common.ErrorPermissionDenied
+
+	// ErrQuotaExceeded The principal of the client exceeded its rate or its
+	// storage quota on the namespace.
+	ErrQuotaExceeded = common.ErrorQuotaExceeded
+
 	// ErrRequestTooLarge is returned when a request is larger than the maximum batch size.
 	ErrRequestTooLarge = batch.ErrRequestTooLarge
 
@@ -71,6 +99,14 @@ type AsyncClient interface {
 	// current version id of the record
 	Put(key string, value []byte, options ...PutOption) <-chan PutResult
 
+	// Increment atomically adds the delta to the counter stored in the record,
+	// creating it from 0 if it doesn't exist, and returns the new value.
+	//
+	// The counter is stored as a decimal integer. The same options as Put can
+	// be used, except for [SequenceKeysDeltas].
+	// Returns [ErrInvalidCounterValue] if the existing value is not a valid counter
+	Increment(key string, delta int64, options ...PutOption) <-chan IncrementResult
+
 	// Delete removes the key and its associated value from the data store.
 	//
 	// The Delete operation can be made conditional on that the record hasn't changed from
@@ -105,7 +141,13 @@ type AsyncClient interface {
 
 	// GetNotifications creates a new subscription to receive the notifications
 	// from Oxia for any change that is applied to the database
-	GetNotifications() (Notifications, error)
+	GetNotifications(options ...NotificationsOption) (Notifications, error)
+
+	// GrantLease creates a new lease with the given time-to-live, or joins the
+	// lease with the same name granted by other clients. The records attached
+	// to the lease with [WithLease] are deleted once the lease expires or is
+	// revoked.
+	GrantLease(name string, ttl time.Duration) (Lease, error)
 }
 
 // SyncClient is the main interface to perform operations with Oxia.
@@ -133,6 +175,14 @@ type SyncClient interface {
 	// current version id of the record
 	Put(ctx context.Context, key string, value []byte, options ...PutOption) (insertedKey string, version Version, err error)
 
+	// Increment atomically adds the delta to the counter stored in the record,
+	// creating it from 0 if it doesn't exist, and returns the new value.
+	//
+	// The counter is stored as a decimal integer. The same options as Put can
+	// be used, except for [SequenceKeysDeltas].
+	// Returns [ErrInvalidCounterValue] if the existing value is not a valid counter
+	Increment(ctx context.Context, key string, delta int64, options ...PutOption) (value int64, version Version, err error)
+
 	// Delete removes the key and its associated value from the data store.
 	//
 	// The Delete operation can be made conditional on that the record hasn't changed from
@@ -166,7 +216,48 @@ type SyncClient interface {
 
 	// GetNotifications creates a new subscription to receive the notifications
 	// from Oxia for any change that is applied to the database
-	GetNotifications() (Notifications, error)
+	GetNotifications(options ...NotificationsOption) (Notifications, error)
+
+	// GrantLease creates a new lease with the given time-to-live, or joins the
+	// lease with the same name granted by other clients. The records attached
+	// to the lease with [WithLease] are deleted once the lease expires or is
+	// revoked.
+	GrantLease(name string, ttl time.Duration) (Lease, error)
+}
+
+// Lease is an expiration that is shared by a group of records, possibly in
+// different shards. Unlike the session of the client, a lease is not kept
+// alive automatically: the application decides when to refresh it.
+//
+// A lease is kept by the servers, under its name, in each shard where it has
+// records. The clients that grant a lease with the same name share it: any of
+// them can refresh it, for the records of all of them, or revoke it. Each
+// shard deletes the records of the lease once it's not refreshed within its
+// TTL, and the client revokes the lease in the other shards once it finds it
+// expired in one of them.
+type Lease interface {
+	// Name returns the name given to the lease
+	Name() string
+
+	// TTL returns the time after which the lease expires, if not refreshed
+	TTL() time.Duration
+
+	// Refresh extends the lease by its time-to-live, for the records of all
+	// the clients that share it.
+	// Returns [ErrLeaseExpired] if the lease was not refreshed in time. In
+	// that case, all the records attached to the lease get deleted.
+	Refresh(ctx context.Context) error
+
+	// Revoke ends the lease and deletes all the records attached to it
+	Revoke(ctx context.Context) error
+
+	// Done returns a channel that is closed once the lease has expired or
+	// was revoked
+	Done() <-chan struct{}
+
+	// Err returns [ErrLeaseExpired] or [ErrLeaseRevoked] once the lease has
+	// ended, nil while it's active
+	Err() error
 }
 
 // Version includes some information regarding the state of a record.
@@ -212,6 +303,20 @@ type PutResult struct {
 	Err error
 }
 
+// IncrementResult structure is wrapping the new value of the counter, its version
+// information and an eventual error as results for an `Increment` operation in
+// the [AsyncClient].
+type IncrementResult struct {
+	// The value of the counter after the increment
+	Value int64
+
+	// The Version information
+	Version Version
+
+	// The error if the `Increment` operation failed
+	Err error
+}
+
 // GetResult structure is wrapping a record, with its Key and Value, its version information and
 // an eventual error as results for a `Get` operation in the [AsyncClient].
 type GetResult struct {
@@ -244,6 +349,11 @@ type Notifications interface {
 
 	// Ch exposes the channel where all the notification events are published
 	Ch() <-chan *Notification
+
+	// Ack acknowledges that the notification, and all the notifications
+	// before it in the same shard, were processed. It's only available to
+	// the durable consumers subscribed with [ConsumerName].
+	Ack(ctx context.Context, notification *Notification) error
 }
 
 // NotificationType represents the type of the notification event.
@@ -256,6 +366,10 @@ const (
 	KeyModified
 	// KeyDeleted A record was deleted.
 	KeyDeleted
+	// KeyRangeDeleted A record was deleted by a [SyncClient.DeleteRange]. It's
+	// only received when selected with [EventTypes], otherwise the range
+	// deletions are received as KeyDeleted.
+	KeyRangeDeleted
 )
 
 func (n NotificationType) String() string {
@@ -266,6 +380,8 @@ func (n NotificationType) String() string {
 		return "KeyModified"
 	case KeyDeleted:
 		return "KeyDeleted"
+	case KeyRangeDeleted:
+		return "KeyRangeDeleted"
 	}
 
 	return "Unknown"
@@ -281,4 +397,69 @@ type Notification struct {
 
 	// The current VersionId of the record, or -1 for a KeyDeleted event
 	VersionId int64
+
+	// The shard of the record
+	Shard int64
+
+	// The offset of the entry that generated the notification in the log of
+	// the shard. It can be passed to [StartFromOffsets] to resume the
+	// notifications after a restart of the application
+	Offset int64
+
+	// The value of the record, only set with the [IncludeValue] option for the
+	// KeyCreated and KeyModified events. It's nil if the record was modified
+	// again since, in which case a later notification follows.
+	Value []byte
+}
+
+// SessionEventType represents the type of change in the lifecycle of a session.
+type SessionEventType int
+
+const (
+	// SessionEstablished A session was created for the first time on a shard.
+	SessionEstablished SessionEventType = iota
+	// SessionHeartbeatFailing The heartbeats of the session are failing, the session
+	// might expire if they don't succeed before the session timeout.
+	SessionHeartbeatFailing
+	// SessionHeartbeatRecovered The heartbeats of the session are succeeding again.
+	SessionHeartbeatRecovered
+	// SessionExpired The session expired and the ephemeral records created with it
+	// are getting deleted.
+	SessionExpired
+	// SessionReacquired A new session was created on a shard, after the previous one
+	// expired.
+	SessionReacquired
+)
+
+func (t SessionEventType) String() string {
+	switch t {
+	case SessionEstablished:
+		return "SessionEstablished"
+	case SessionHeartbeatFailing:
+		return "SessionHeartbeatFailing"
+	case SessionHeartbeatRecovered:
+		return "SessionHeartbeatRecovered"
+	case SessionExpired:
+		return "SessionExpired"
+	case SessionReacquired:
+		return "SessionReacquired"
+	}
+
+	return "Unknown"
+}
+
+// SessionEvent represents a change in the lifecycle of the session of the client
+// on one shard.
+type SessionEvent struct {
+	// The type of the change
+	Type SessionEventType
+
+	// The shard of the session
+	Shard int64
+
+	// The id of the session
+	SessionId int64
+
+	// The error that caused the change, if any
+	Err error
 }
@@ -19,23 +19,26 @@ import (
 )
 
 func toShard(assignment *proto.ShardAssignment) Shard {
-	return Shard{
-		Id:        assignment.ShardId,
-		Leader:    assignment.Leader,
-		HashRange: toHashRange(assignment),
+	shard := Shard{
+		Id:     assignment.ShardId,
+		Leader: assignment.Leader,
 	}
-}
 
-func toHashRange(assignment *proto.ShardAssignment) HashRange {
 	switch boundaries := assignment.ShardBoundaries.(type) {
 	case *proto.ShardAssignment_Int32HashRange:
-		return HashRange{
+		shard.HashRange = HashRange{
 			MinInclusive: boundaries.Int32HashRange.MinHashInclusive,
 			MaxInclusive: boundaries.Int32HashRange.MaxHashInclusive,
 		}
+	case *proto.ShardAssignment_KeyRange:
+		shard.KeyRange = &KeyRange{
+			MinInclusive: boundaries.KeyRange.MinKeyInclusive,
+			MaxExclusive: boundaries.KeyRange.MaxKeyExclusive,
+		}
 	default:
 		panic("unknown shard boundary")
 	}
+	return shard
 }
 
 func hashRange(minInclusive uint32, maxInclusive uint32) HashRange {
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"sync"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/proto"
@@ -98,6 +100,11 @@ func (e *executorImpl) rpc(shardId *int64) (proto.OxiaClientClient, error) {
 	var target string
 	if shardId != nil {
 		target = e.ShardManager.Leader(*shardId)
+		if target == "" {
+			// The leader election is still in progress, the request is
+			// retried once the new leader is known
+			return nil, status.Errorf(codes.Unavailable, "shard %d has no leader", *shardId)
+		}
 	} else {
 		target = e.ServiceAddress
 	}
@@ -113,7 +120,7 @@ func (e *executorImpl) writeStream(shardId *int64) (*streamWrapper, error) {
 	e.RLock()
 
 	sw, ok := e.writeStreams[*shardId]
-	if ok {
+	if ok && !sw.closed() {
 		e.RUnlock()
 		return sw, nil
 	}
@@ -0,0 +1,45 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+func TestExecutor_ShardWithoutLeader(t *testing.T) {
+	clientPool := common.NewClientPool(nil, nil)
+	defer clientPool.Close()
+
+	sm := &shardManagerImpl{
+		shards: map[int64]Shard{1: {Id: 1}},
+	}
+	e := NewExecutor(context.Background(), common.DefaultNamespace, clientPool, sm, "localhost:6648")
+
+	// The requests are failed with a retriable error until the shard has a leader
+	var shardId int64 = 1
+	_, err := e.ExecuteWrite(context.Background(), &proto.WriteRequest{ShardId: &shardId})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = e.ExecuteRead(context.Background(), &proto.ReadRequest{ShardId: &shardId})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -34,7 +34,20 @@ type ShardManager interface {
 	io.Closer
 	Get(key string) int64
 	GetAll() []int64
+
+	// GetRange returns the shards that can contain keys in the range
+	// [minKeyInclusive, maxKeyExclusive). That's all the shards, unless the
+	// namespace is partitioned by key ranges.
+	GetRange(minKeyInclusive string, maxKeyExclusive string) []int64
 	Leader(shardId int64) string
+
+	// LookupLeader returns the leader of the shard, if the shard is still
+	// part of the namespace
+	LookupLeader(shardId int64) (leader string, ok bool)
+
+	// Changed returns a channel that is closed at the next update of the
+	// shard assignments
+	Changed() <-chan struct{}
 }
 
 type shardManagerImpl struct {
@@ -42,16 +55,21 @@ type shardManagerImpl struct {
 	updatedWg common.WaitGroup
 
 	shardStrategy  ShardStrategy
+	routerStrategy ShardStrategy
 	clientPool     common.ClientPool
 	serviceAddress string
 	namespace      string
 	shards         map[int64]Shard
+	changed        chan struct{}
 	ctx            context.Context
 	cancel         context.CancelFunc
 	logger         *slog.Logger
 	requestTimeout time.Duration
 }
 
+// NewShardManager creates a shard manager that keeps track of the shard
+// assignments of the namespace. The shardStrategy is used to route the keys,
+// unless the namespace uses a different key router.
 func NewShardManager(shardStrategy ShardStrategy, clientPool common.ClientPool,
 	serviceAddress string, namespace string, requestTimeout time.Duration) (ShardManager, error) {
 	sm := &shardManagerImpl{
@@ -60,6 +78,7 @@ func NewShardManager(shardStrategy ShardStrategy, clientPool common.ClientPool,
 		clientPool:     clientPool,
 		serviceAddress: serviceAddress,
 		shards:         make(map[int64]Shard),
+		changed:        make(chan struct{}),
 		requestTimeout: requestTimeout,
 		logger: slog.With(
 			slog.String("component", "shardManager"),
@@ -103,7 +122,11 @@ func (s *shardManagerImpl) Get(key string) int64 {
 	s.RLock()
 	defer s.RUnlock()
 
-	predicate := s.shardStrategy.Get(key)
+	strategy := s.shardStrategy
+	if s.routerStrategy != nil {
+		strategy = s.routerStrategy
+	}
+	predicate := strategy.Get(key)
 
 	for _, shard := range s.shards {
 		if predicate(shard) {
@@ -124,6 +147,19 @@ func (s *shardManagerImpl) GetAll() []int64 {
 	return shardIds
 }
 
+func (s *shardManagerImpl) GetRange(minKeyInclusive string, maxKeyExclusive string) []int64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	shardIds := make([]int64, 0, len(s.shards))
+	for shardId, shard := range s.shards {
+		if shard.KeyRange == nil || shard.KeyRange.overlaps(minKeyInclusive, maxKeyExclusive) {
+			shardIds = append(shardIds, shardId)
+		}
+	}
+	return shardIds
+}
+
 func (s *shardManagerImpl) Leader(shardId int64) string {
 	s.RLock()
 	defer s.RUnlock()
@@ -134,6 +170,21 @@ func (s *shardManagerImpl) Leader(shardId int64) string {
 	panic("shard not found")
 }
 
+func (s *shardManagerImpl) LookupLeader(shardId int64) (leader string, ok bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	shard, ok := s.shards[shardId]
+	return shard.Leader, ok
+}
+
+func (s *shardManagerImpl) Changed() <-chan struct{} {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.changed
+}
+
 func (s *shardManagerImpl) isClosed() bool {
 	return s.ctx.Err() != nil
 }
@@ -204,35 +255,50 @@ func (s *shardManagerImpl) receive(backOff backoff.BackOff) error {
 		for i, assignment := range assignments.Assignments {
 			shards[i] = toShard(assignment)
 		}
-		s.update(shards)
+		s.update(assignments.ShardKeyRouter, shards)
 		backOff.Reset()
 	}
 }
 
-func (s *shardManagerImpl) update(updates []Shard) {
+func (s *shardManagerImpl) update(router proto.ShardKeyRouter, updates []Shard) {
 	s.Lock()
 	defer s.Unlock()
 
+	s.routerStrategy, _ = newRouterShardStrategy(router)
+
 	for _, update := range updates {
-		if _, ok := s.shards[update.Id]; !ok {
-			// delete overlaps
-			for shardId, existing := range s.shards {
-				if overlap(update.HashRange, existing.HashRange) {
-					s.logger.Info(
-						"Deleting shard as it overlaps",
-						slog.Any("Existing", existing),
-						slog.Any("Update", update),
-					)
-					delete(s.shards, shardId)
-				}
+		// delete overlaps, including the shards that were merged into an
+		// existing one
+		for shardId, existing := range s.shards {
+			if shardId != update.Id && shardsOverlap(update, existing) {
+				s.logger.Info(
+					"Deleting shard as it overlaps",
+					slog.Any("Existing", existing),
+					slog.Any("Update", update),
+				)
+				delete(s.shards, shardId)
 			}
 		}
 		s.shards[update.Id] = update
 	}
 
+	close(s.changed)
+	s.changed = make(chan struct{})
 	s.updatedWg.Done()
 }
 
+func shardsOverlap(a Shard, b Shard) bool {
+	switch {
+	case a.KeyRange != nil && b.KeyRange != nil:
+		return a.KeyRange.overlaps(b.KeyRange.MinInclusive, b.KeyRange.MaxExclusive)
+	case a.KeyRange == nil && b.KeyRange == nil:
+		return overlap(a.HashRange, b.HashRange)
+	default:
+		// The shards of a namespace are either all hash or key ranges
+		return true
+	}
+}
+
 func overlap(a HashRange, b HashRange) bool {
 	return !(a.MinInclusive > b.MaxInclusive || a.MaxInclusive < b.MinInclusive)
 }
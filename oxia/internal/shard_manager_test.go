@@ -16,12 +16,14 @@ package internal
 
 import (
 	"fmt"
+	"log/slog"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
 	"github.com/streamnative/oxia/server"
 )
 
@@ -67,3 +69,45 @@ func TestOverlap(t *testing.T) {
 		assert.Equal(t, overlap(item.a, item.b), item.isOverlap)
 	}
 }
+
+func TestShardManager_KeyRanges(t *testing.T) {
+	sm := &shardManagerImpl{
+		shardStrategy: &testShardStrategy{},
+		shards:        map[int64]Shard{},
+		changed:       make(chan struct{}),
+		updatedWg:     common.NewWaitGroup(1),
+		logger:        slog.Default(),
+	}
+
+	sm.update(proto.ShardKeyRouter_KEY_RANGE, []Shard{
+		{Id: 0, KeyRange: &KeyRange{MaxExclusive: "m"}},
+		{Id: 1, KeyRange: &KeyRange{MinInclusive: "m"}},
+	})
+
+	assert.EqualValues(t, 0, sm.Get("a"))
+	assert.EqualValues(t, 1, sm.Get("x"))
+	assert.Equal(t, []int64{0}, sm.GetRange("a", "c"))
+	assert.ElementsMatch(t, []int64{0, 1}, sm.GetRange("a", "z"))
+	assert.Equal(t, []int64{1}, sm.GetRange("n", ""))
+
+	// The shards that overlap with a merged one are removed
+	changed := sm.Changed()
+	sm.update(proto.ShardKeyRouter_KEY_RANGE, []Shard{
+		{Id: 0, KeyRange: &KeyRange{}, Leader: "leader-0"},
+	})
+	assert.Equal(t, []int64{0}, sm.GetAll())
+	assert.EqualValues(t, 0, sm.Get("x"))
+
+	// The watchers are notified of the update
+	select {
+	case <-changed:
+	default:
+		assert.Fail(t, "the update should have been notified")
+	}
+
+	leader, ok := sm.LookupLeader(0)
+	assert.True(t, ok)
+	assert.Equal(t, "leader-0", leader)
+	_, ok = sm.LookupLeader(1)
+	assert.False(t, ok)
+}
@@ -22,9 +22,19 @@ type Shard struct {
 	Id        int64
 	Leader    string
 	HashRange HashRange
+
+	// KeyRange is only set for the namespaces partitioned by key ranges
+	KeyRange *KeyRange
 }
 
 type HashRange struct {
 	MinInclusive uint32
 	MaxInclusive uint32
 }
+
+// KeyRange is a range of keys, in the order in which the keys are listed.
+// An empty MaxExclusive means that the range is unbounded.
+type KeyRange struct {
+	MinInclusive string
+	MaxExclusive string
+}
@@ -16,6 +16,8 @@ package internal
 
 import (
 	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/compare"
+	"github.com/streamnative/oxia/proto"
 )
 
 type shardStrategyImpl struct {
@@ -35,3 +37,41 @@ func (s *shardStrategyImpl) Get(key string) func(Shard) bool {
 		return hashRange.MinInclusive <= hash && hash <= hashRange.MaxInclusive
 	}
 }
+
+// Returns the built-in strategy for the given key router, if there is one
+// that differs from the default xxHash3 strategy.
+func newRouterShardStrategy(router proto.ShardKeyRouter) (ShardStrategy, bool) {
+	switch router {
+	case proto.ShardKeyRouter_FNV1A_32:
+		return &shardStrategyImpl{hashFunc: common.Fnv1a32}, true
+	case proto.ShardKeyRouter_KEY_RANGE:
+		return &keyRangeShardStrategy{}, true
+	default:
+		return nil, false
+	}
+}
+
+type keyRangeShardStrategy struct{}
+
+func (*keyRangeShardStrategy) Get(key string) func(Shard) bool {
+	return func(shard Shard) bool {
+		return shard.KeyRange != nil && shard.KeyRange.contains(key)
+	}
+}
+
+func (r *KeyRange) contains(key string) bool {
+	return compare.CompareWithSlash([]byte(r.MinInclusive), []byte(key)) <= 0 &&
+		(r.MaxExclusive == "" || compare.CompareWithSlash([]byte(key), []byte(r.MaxExclusive)) < 0)
+}
+
+// Checks whether the key range overlaps with [minInclusive, maxExclusive),
+// where an empty maxExclusive means that the range is unbounded.
+func (r *KeyRange) overlaps(minInclusive string, maxExclusive string) bool {
+	if r.MaxExclusive != "" && compare.CompareWithSlash([]byte(minInclusive), []byte(r.MaxExclusive)) >= 0 {
+		return false
+	}
+	if maxExclusive != "" && compare.CompareWithSlash([]byte(r.MinInclusive), []byte(maxExclusive)) >= 0 {
+		return false
+	}
+	return true
+}
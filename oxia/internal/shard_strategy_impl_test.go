@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/proto"
 )
 
 func TestShardStrategy(t *testing.T) {
@@ -48,3 +50,71 @@ func TestShardStrategy(t *testing.T) {
 		assert.Equal(t, item.match, predicate(shard))
 	}
 }
+
+func TestKeyRangeShardStrategy(t *testing.T) {
+	strategy, ok := newRouterShardStrategy(proto.ShardKeyRouter_KEY_RANGE)
+	assert.True(t, ok)
+
+	shards := []Shard{
+		{Id: 0, KeyRange: &KeyRange{MinInclusive: "", MaxExclusive: "g"}},
+		{Id: 1, KeyRange: &KeyRange{MinInclusive: "g", MaxExclusive: "p"}},
+		{Id: 2, KeyRange: &KeyRange{MinInclusive: "p", MaxExclusive: ""}},
+	}
+
+	for _, item := range []struct {
+		key   string
+		shard int64
+	}{
+		{"", 0},
+		{"a", 0},
+		{"g", 1},
+		{"o", 1},
+		{"p", 2},
+		{"zzz", 2},
+		// The keys with a slash are listed after the ones without
+		{"a/b", 2},
+	} {
+		predicate := strategy.Get(item.key)
+		for _, shard := range shards {
+			assert.Equal(t, item.shard == shard.Id, predicate(shard), "key %q shard %d", item.key, shard.Id)
+		}
+	}
+}
+
+func TestRouterShardStrategy(t *testing.T) {
+	strategy, ok := newRouterShardStrategy(proto.ShardKeyRouter_FNV1A_32)
+	assert.True(t, ok)
+
+	// fnv1a("foo") = 2851307223
+	predicate := strategy.Get("foo")
+	assert.True(t, predicate(Shard{HashRange: hashRange(2851307223, 2851307223)}))
+	assert.False(t, predicate(Shard{HashRange: hashRange(0, 2851307222)}))
+
+	_, ok = newRouterShardStrategy(proto.ShardKeyRouter_XXHASH3)
+	assert.False(t, ok)
+}
+
+func TestKeyRangeOverlaps(t *testing.T) {
+	r := &KeyRange{MinInclusive: "g", MaxExclusive: "p"}
+	for _, item := range []struct {
+		minInclusive string
+		maxExclusive string
+		overlaps     bool
+	}{
+		{"a", "c", false},
+		{"a", "g", false},
+		{"a", "h", true},
+		{"h", "i", true},
+		{"o", "z", true},
+		{"p", "z", false},
+		{"a", "", true},
+		{"q", "", false},
+	} {
+		assert.Equal(t, item.overlaps, r.overlaps(item.minInclusive, item.maxExclusive),
+			"[%q, %q)", item.minInclusive, item.maxExclusive)
+	}
+
+	unbounded := &KeyRange{MinInclusive: "p"}
+	assert.True(t, unbounded.overlaps("x", "y"))
+	assert.False(t, unbounded.overlaps("a", "p"))
+}
@@ -16,7 +16,6 @@ package internal
 
 import (
 	"context"
-	"io"
 	"log/slog"
 	"sync"
 
@@ -29,6 +28,9 @@ type streamWrapper struct {
 
 	stream          proto.OxiaClient_WriteStreamClient
 	pendingRequests []common.Future[*proto.WriteResponse]
+
+	// The error that closed the stream, if any
+	err error
 }
 
 func newStreamWrapper(stream proto.OxiaClient_WriteStreamClient) *streamWrapper {
@@ -38,7 +40,6 @@ func newStreamWrapper(stream proto.OxiaClient_WriteStreamClient) *streamWrapper
 	}
 
 	go sw.handleResponses()
-	go sw.handleStreamClosed()
 	return sw
 }
 
@@ -46,33 +47,48 @@ func (sw *streamWrapper) Send(ctx context.Context, req *proto.WriteRequest) (*pr
 	f := common.NewFuture[*proto.WriteResponse]()
 
 	sw.Lock()
-	sw.pendingRequests = append(sw.pendingRequests, f)
+	if sw.err != nil {
+		sw.Unlock()
+		return nil, sw.err
+	}
+
 	if err := sw.stream.Send(req); err != nil {
 		sw.Unlock()
 		return nil, err
 	}
+	sw.pendingRequests = append(sw.pendingRequests, f)
 
 	sw.Unlock()
 
 	return f.Wait(ctx)
 }
 
-func (sw *streamWrapper) handleStreamClosed() {
-	<-sw.stream.Context().Done()
+// closed reports whether the stream was closed, in which case a new stream
+// must be used for the next requests.
+func (sw *streamWrapper) closed() bool {
+	sw.Lock()
+	defer sw.Unlock()
+	return sw.err != nil
+}
 
-	// Fail all pending requests
+// Fail all the pending requests with the error that closed the stream, eg:
+// a request rejected by the server.
+func (sw *streamWrapper) fail(err error) {
 	sw.Lock()
+	defer sw.Unlock()
+
+	sw.err = err
 	for _, f := range sw.pendingRequests {
-		f.Fail(io.EOF)
+		f.Fail(err)
 	}
 	sw.pendingRequests = nil
-	sw.Unlock()
 }
 
 func (sw *streamWrapper) handleResponses() {
 	for {
 		response, err := sw.stream.Recv()
 		if err != nil {
+			sw.fail(err)
 			return
 		}
 
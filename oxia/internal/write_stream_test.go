@@ -0,0 +1,94 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+type testWriteStream struct {
+	grpc.ClientStream
+	requests  chan *proto.WriteRequest
+	responses chan *proto.WriteResponse
+	err       chan error
+}
+
+func newTestWriteStream() *testWriteStream {
+	return &testWriteStream{
+		requests:  make(chan *proto.WriteRequest, 10),
+		responses: make(chan *proto.WriteResponse, 10),
+		err:       make(chan error, 1),
+	}
+}
+
+func (s *testWriteStream) Send(req *proto.WriteRequest) error {
+	s.requests <- req
+	return nil
+}
+
+func (s *testWriteStream) Recv() (*proto.WriteResponse, error) {
+	select {
+	case res := <-s.responses:
+		return res, nil
+	case err := <-s.err:
+		return nil, err
+	}
+}
+
+func TestStreamWrapper_Send(t *testing.T) {
+	stream := newTestWriteStream()
+	sw := newStreamWrapper(stream)
+
+	stream.responses <- &proto.WriteResponse{Puts: []*proto.PutResponse{{}}}
+	res, err := sw.Send(context.Background(), &proto.WriteRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, res.Puts, 1)
+	assert.False(t, sw.closed())
+}
+
+func TestStreamWrapper_Closed(t *testing.T) {
+	stream := newTestWriteStream()
+	sw := newStreamWrapper(stream)
+
+	// The pending request fails with the error that closed the stream
+	errCh := make(chan error)
+	go func() {
+		_, err := sw.Send(context.Background(), &proto.WriteRequest{})
+		errCh <- err
+	}()
+	<-stream.requests
+	stream.err <- common.ErrorRateLimited
+
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, common.ErrorRateLimited)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "the pending request did not fail")
+	}
+	assert.True(t, sw.closed())
+
+	// The next requests fail without being sent
+	_, err := sw.Send(context.Background(), &proto.WriteRequest{})
+	assert.ErrorIs(t, err, common.ErrorRateLimited)
+	assert.Empty(t, stream.requests)
+}
@@ -0,0 +1,332 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oxia
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/status"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+// A lease is kept by the servers, as a record named after it in each of the
+// shards where it has records, which the records are attached to. The client
+// tracks the shards where it granted the lease, to detect when it expires, and
+// refreshes and revokes the lease by its name in all the shards, so that the
+// clients sharing the lease keep the records of one another.
+type lease struct {
+	sync.Mutex
+	name            string
+	ttl             time.Duration
+	client          *clientImpl
+	sessionsByShard map[int64]*leaseSession
+	log             *slog.Logger
+
+	// Fires when the lease is due to expire, unless it's refreshed before
+	expiryTimer *time.Timer
+
+	// The reason why the lease is not valid anymore, nil while it's active
+	err  error
+	done chan struct{}
+}
+
+type leaseSession struct {
+	created   chan struct{}
+	shardId   int64
+	sessionId int64
+	err       error
+}
+
+func (c *clientImpl) GrantLease(name string, ttl time.Duration) (Lease, error) {
+	if ttl < common.MinSessionTimeout || ttl > common.MaxSessionTimeout {
+		return nil, errors.Wrap(ErrInvalidOptions, fmt.Sprintf("the lease TTL must be between %v and %v",
+			common.MinSessionTimeout, common.MaxSessionTimeout))
+	}
+
+	return &lease{
+		name:            name,
+		ttl:             ttl,
+		client:          c,
+		sessionsByShard: map[int64]*leaseSession{},
+		done:            make(chan struct{}),
+		log: slog.With(
+			slog.String("component", "oxia-lease"),
+			slog.String("lease", name),
+		),
+	}, nil
+}
+
+func (l *lease) Name() string {
+	return l.name
+}
+
+func (l *lease) TTL() time.Duration {
+	return l.ttl
+}
+
+func (l *lease) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *lease) Err() error {
+	l.Lock()
+	defer l.Unlock()
+	return l.err
+}
+
+// extend moves the expiry of the lease to one TTL after the given time, at
+// which the lease was granted or refreshed. The first grant only sets the
+// expiry, so that the lease expires with the oldest one.
+func (l *lease) extend(since time.Time, firstSession bool) {
+	l.Lock()
+	defer l.Unlock()
+	if l.err != nil {
+		return
+	}
+
+	delay := time.Until(since.Add(l.ttl))
+	switch {
+	case l.expiryTimer == nil:
+		l.expiryTimer = time.AfterFunc(delay, func() {
+			// The servers delete the records on their own, unless the
+			// lease is refreshed by the other clients that share it
+			l.log.Warn("Lease not refreshed in time")
+			_ = l.close(l.client.ctx, ErrLeaseExpired, false)
+		})
+	case !firstSession:
+		l.expiryTimer.Reset(delay)
+	}
+}
+
+func (l *lease) executeWithSessionId(shardId int64, callback func(int64, error)) {
+	l.Lock()
+	if l.err != nil {
+		err := l.err
+		l.Unlock()
+		callback(-1, err)
+		return
+	}
+
+	ls, found := l.sessionsByShard[shardId]
+	if !found {
+		ls = &leaseSession{created: make(chan struct{}), shardId: shardId}
+		l.sessionsByShard[shardId] = ls
+		go common.DoWithLabels(
+			l.client.ctx,
+			map[string]string{
+				"oxia":  "lease-grant",
+				"lease": l.name,
+				"shard": fmt.Sprintf("%d", shardId),
+			},
+			func() { l.grant(ls) },
+		)
+	}
+	l.Unlock()
+
+	<-ls.created
+	if ls.err != nil {
+		callback(-1, ls.err)
+	} else {
+		callback(ls.sessionId, nil)
+	}
+}
+
+func (l *lease) grant(ls *leaseSession) {
+	var grantedAt time.Time
+	err := backoff.RetryNotify(func() error {
+		rpc, err := l.getRpc(ls.shardId)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(l.client.ctx, l.client.options.requestTimeout)
+		defer cancel()
+		grantedAt = time.Now()
+		res, err := rpc.GrantLease(ctx, &proto.GrantLeaseRequest{
+			ShardId:        ls.shardId,
+			Name:           l.name,
+			TtlMs:          uint32(l.ttl.Milliseconds()),
+			ClientIdentity: l.client.options.identity,
+		})
+		switch status.Code(err) {
+		case common.CodeInvalidSessionTimeout, common.CodePermissionDenied:
+			// The lease was granted with another TTL, or by another principal
+			return backoff.Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+		ls.sessionId = res.SessionId
+		return nil
+	}, common.NewBackOff(l.client.ctx), func(err error, duration time.Duration) {
+		l.log.Warn(
+			"Error while granting the lease",
+			slog.Int64("shard", ls.shardId),
+			slog.Any("error", err),
+			slog.Duration("retry-after", duration),
+		)
+	})
+
+	if err != nil {
+		ls.err = err
+
+		// Let the next records retry
+		l.Lock()
+		if l.sessionsByShard[ls.shardId] == ls {
+			delete(l.sessionsByShard, ls.shardId)
+		}
+		l.Unlock()
+	} else {
+		l.log.Debug(
+			"Granted lease",
+			slog.Int64("shard", ls.shardId),
+			slog.Int64("session-id", ls.sessionId),
+		)
+		l.extend(grantedAt, true)
+	}
+	close(ls.created)
+}
+
+// The shards where the lease was granted by this client, with the id of the
+// lease in each of them. The ones still being granted are refreshed by their
+// grant.
+func (l *lease) grantedShards() (map[int64]int64, error) {
+	l.Lock()
+	defer l.Unlock()
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	res := make(map[int64]int64, len(l.sessionsByShard))
+	for _, ls := range l.sessionsByShard {
+		select {
+		case <-ls.created:
+			if ls.err == nil {
+				res[ls.shardId] = ls.sessionId
+			}
+		default:
+		}
+	}
+	return res, nil
+}
+
+// Refresh extends the lease in all the shards, including the ones where it
+// was only granted by the other clients that share it.
+func (l *lease) Refresh(ctx context.Context) error {
+	refreshedAt := time.Now()
+	granted, err := l.grantedShards()
+	if err != nil {
+		return err
+	}
+
+	for _, shardId := range l.client.shardManager.GetAll() {
+		rpc, err := l.getRpc(shardId)
+		if err != nil {
+			return err
+		}
+
+		request := &proto.LeaseRequest{ShardId: shardId, Name: l.name}
+		sessionId, isGranted := granted[shardId]
+		if isGranted {
+			request.SessionId = &sessionId
+		}
+		_, err = rpc.RefreshLease(ctx, request)
+		switch {
+		case status.Code(err) == common.CodeInvalidSession && isGranted:
+			l.expire(ctx)
+			return ErrLeaseExpired
+		case status.Code(err) == common.CodeInvalidSession:
+			// The lease has no records in the shard
+		case err != nil:
+			return err
+		}
+	}
+
+	if len(granted) > 0 {
+		l.extend(refreshedAt, false)
+	}
+	return l.Err()
+}
+
+// Once the lease has expired in one of the shards, it's revoked in the other
+// shards too, so that all its records are deleted.
+func (l *lease) expire(ctx context.Context) {
+	l.log.Warn("Lease expired")
+	if err := l.close(ctx, ErrLeaseExpired, true); err != nil {
+		l.log.Warn(
+			"Failed to revoke the expired lease",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (l *lease) Revoke(ctx context.Context) error {
+	return l.close(ctx, ErrLeaseRevoked, true)
+}
+
+func (l *lease) close(ctx context.Context, reason error, revoke bool) error {
+	l.Lock()
+	if l.err != nil {
+		l.Unlock()
+		return nil
+	}
+	l.err = reason
+	sessions := l.sessionsByShard
+	l.sessionsByShard = map[int64]*leaseSession{}
+	if l.expiryTimer != nil {
+		l.expiryTimer.Stop()
+	}
+	close(l.done)
+	l.Unlock()
+
+	if !revoke {
+		return nil
+	}
+
+	// Wait for the grants in progress, for their shards to be revoked too
+	for _, ls := range sessions {
+		select {
+		case <-ls.created:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var err error
+	for _, shardId := range l.client.shardManager.GetAll() {
+		rpc, e := l.getRpc(shardId)
+		if e == nil {
+			_, e = rpc.RevokeLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: l.name})
+		}
+		if status.Code(e) != common.CodeInvalidSession {
+			err = multierr.Append(err, e)
+		}
+	}
+	return err
+}
+
+func (l *lease) getRpc(shardId int64) (proto.OxiaClientClient, error) {
+	leader := l.client.shardManager.Leader(shardId)
+	return l.client.clientPool.GetClientRpc(leader)
+}
@@ -0,0 +1,253 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oxia
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server"
+)
+
+func TestLease_RefreshAndExpire(t *testing.T) {
+	config := server.NewTestConfig(t.TempDir())
+	config.NumShards = 3
+	standaloneServer, err := server.NewStandalone(config)
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.GrantLease("invalid", time.Millisecond)
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	lease, err := client.GrantLease("workers", 2*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "workers", lease.Name())
+	assert.Equal(t, 2*time.Second, lease.TTL())
+
+	_, _, err = client.Put(ctx, "/x", []byte("x"), WithLease(lease), Ephemeral())
+	assert.ErrorIs(t, err, ErrInvalidOptions)
+
+	keys := []string{"/a", "/b", "/c", "/d"}
+	for _, key := range keys {
+		_, version, err := client.Put(ctx, key, []byte(key), WithLease(lease))
+		assert.NoError(t, err)
+		assert.True(t, version.Ephemeral)
+	}
+
+	// The records are kept while the lease is refreshed
+	for i := 0; i < 6; i++ {
+		time.Sleep(500 * time.Millisecond)
+		assert.NoError(t, lease.Refresh(ctx))
+	}
+	for _, key := range keys {
+		_, _, _, err = client.Get(ctx, key)
+		assert.NoError(t, err)
+	}
+
+	// All the records go away together once the lease is not refreshed
+	assert.Eventually(t, func() bool {
+		for _, key := range keys {
+			if _, _, _, err := client.Get(ctx, key); err == nil {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 100*time.Millisecond)
+
+	select {
+	case <-lease.Done():
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "the lease was not expired")
+	}
+	assert.ErrorIs(t, lease.Err(), ErrLeaseExpired)
+	assert.ErrorIs(t, lease.Refresh(ctx), ErrLeaseExpired)
+	_, _, err = client.Put(ctx, "/a", []byte("a"), WithLease(lease))
+	assert.ErrorIs(t, err, ErrLeaseExpired)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestLease_Revoke(t *testing.T) {
+	config := server.NewTestConfig(t.TempDir())
+	config.NumShards = 3
+	standaloneServer, err := server.NewStandalone(config)
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	lease, err := client.GrantLease("workers", time.Minute)
+	assert.NoError(t, err)
+
+	otherLease, err := client.GrantLease("others", time.Minute)
+	assert.NoError(t, err)
+
+	keys := []string{"/a", "/b", "/c", "/d"}
+	for _, key := range keys {
+		_, _, err := client.Put(ctx, key, []byte(key), WithLease(lease))
+		assert.NoError(t, err)
+	}
+	_, _, err = client.Put(ctx, "/other", []byte("other"), WithLease(otherLease))
+	assert.NoError(t, err)
+
+	assert.NoError(t, lease.Err())
+	assert.NoError(t, lease.Revoke(ctx))
+	for _, key := range keys {
+		_, _, _, err = client.Get(ctx, key)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	<-lease.Done()
+	assert.ErrorIs(t, lease.Err(), ErrLeaseRevoked)
+
+	// The other leases are not affected
+	_, _, _, err = client.Get(ctx, "/other")
+	assert.NoError(t, err)
+
+	assert.NoError(t, lease.Revoke(ctx))
+	assert.ErrorIs(t, lease.Refresh(ctx), ErrLeaseRevoked)
+	_, _, err = client.Put(ctx, "/a", []byte("a"), WithLease(lease))
+	assert.ErrorIs(t, err, ErrLeaseRevoked)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestLease_ExpireWithOneShard(t *testing.T) {
+	config := server.NewTestConfig(t.TempDir())
+	config.NumShards = 3
+	standaloneServer, err := server.NewStandalone(config)
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	workers, err := client.GrantLease("workers", time.Minute)
+	assert.NoError(t, err)
+
+	keys := []string{"/a", "/b", "/c", "/d"}
+	for _, key := range keys {
+		_, _, err := client.Put(ctx, key, []byte(key), WithLease(workers))
+		assert.NoError(t, err)
+	}
+
+	// The lease goes away in a single shard
+	l := workers.(*lease)
+	l.Lock()
+	assert.Less(t, 1, len(l.sessionsByShard))
+	var ls *leaseSession
+	for _, s := range l.sessionsByShard {
+		ls = s
+		break
+	}
+	l.Unlock()
+	rpc, err := l.getRpc(ls.shardId)
+	assert.NoError(t, err)
+	_, err = rpc.RevokeLease(ctx, &proto.LeaseRequest{ShardId: ls.shardId, Name: "workers"})
+	assert.NoError(t, err)
+
+	// The whole lease is expired, and the records in the other shards are
+	// deleted as well
+	assert.ErrorIs(t, workers.Refresh(ctx), ErrLeaseExpired)
+	<-workers.Done()
+	assert.ErrorIs(t, workers.Err(), ErrLeaseExpired)
+	assert.Eventually(t, func() bool {
+		for _, key := range keys {
+			if _, _, _, err := client.Get(ctx, key); err == nil {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, client.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
+
+func TestLease_SharedByClients(t *testing.T) {
+	config := server.NewTestConfig(t.TempDir())
+	config.NumShards = 3
+	standaloneServer, err := server.NewStandalone(config)
+	assert.NoError(t, err)
+
+	serviceAddress := fmt.Sprintf("localhost:%d", standaloneServer.RpcPort())
+	client1, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+	client2, err := NewSyncClient(serviceAddress)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	lease1, err := client1.GrantLease("workers", 2*time.Second)
+	assert.NoError(t, err)
+	lease2, err := client2.GrantLease("workers", 2*time.Second)
+	assert.NoError(t, err)
+	otherTTL, err := client2.GrantLease("workers", 3*time.Second)
+	assert.NoError(t, err)
+
+	keys1 := []string{"/a", "/b", "/c", "/d"}
+	for _, key := range keys1 {
+		_, _, err := client1.Put(ctx, key, []byte(key), WithLease(lease1))
+		assert.NoError(t, err)
+	}
+	_, _, err = client2.Put(ctx, "/e", []byte("e"), WithLease(lease2))
+	assert.NoError(t, err)
+
+	// The lease exists with another TTL
+	_, _, err = client2.Put(ctx, "/a", []byte("a"), WithLease(otherTTL))
+	assert.Error(t, err)
+
+	// The records of both clients are kept while any of them refreshes the
+	// lease
+	for i := 0; i < 6; i++ {
+		time.Sleep(500 * time.Millisecond)
+		assert.NoError(t, lease2.Refresh(ctx))
+	}
+	for _, key := range append(keys1, "/e") {
+		_, _, _, err = client2.Get(ctx, key)
+		assert.NoError(t, err)
+	}
+
+	// The client that didn't refresh the lease is told it's expired
+	<-lease1.Done()
+	assert.ErrorIs(t, lease1.Err(), ErrLeaseExpired)
+
+	// Revoking the lease deletes the records of all the clients
+	assert.NoError(t, lease2.Revoke(ctx))
+	for _, key := range append(keys1, "/e") {
+		_, _, _, err = client1.Get(ctx, key)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+
+	assert.NoError(t, client1.Close())
+	assert.NoError(t, client2.Close())
+	assert.NoError(t, standaloneServer.Close())
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -30,10 +32,20 @@ import (
 )
 
 type notifications struct {
+	sync.Mutex
 	multiplexCh  chan *Notification
-	closeCh      chan any
 	shardManager internal.ShardManager
 	clientPool   common.ClientPool
+	keyPrefixes  []string
+	types        []proto.NotificationType
+	includeValue bool
+	startOffsets map[int64]int64
+	consumerName *string
+
+	// The managers of the shards currently in the namespace
+	shards        map[int64]*shardNotificationsManager
+	shardsWg      sync.WaitGroup
+	watcherClosed chan any
 
 	initWaitGroup common.WaitGroup
 	ctx           context.Context
@@ -43,25 +55,44 @@ type notifications struct {
 	cancelMultiplexChanClosed context.CancelFunc
 }
 
-func newNotifications(ctx context.Context, options clientOptions, clientPool common.ClientPool, shardManager internal.ShardManager) (*notifications, error) {
+func newNotifications(ctx context.Context, options clientOptions, notificationsOpts *notificationsOptions,
+	clientPool common.ClientPool, shardManager internal.ShardManager) (*notifications, error) {
 	nm := &notifications{
 		multiplexCh:  make(chan *Notification, 100),
-		closeCh:      make(chan any),
 		shardManager: shardManager,
 		clientPool:   clientPool,
+		keyPrefixes:  notificationsOpts.keyPrefixes,
+		types:        toProtoNotificationTypes(notificationsOpts.types),
+		includeValue: notificationsOpts.includeValue,
+		startOffsets: notificationsOpts.startOffsets,
+
+		shards:        map[int64]*shardNotificationsManager{},
+		watcherClosed: make(chan any),
+	}
+	if notificationsOpts.consumerName != "" {
+		nm.consumerName = &notificationsOpts.consumerName
 	}
 
 	nm.ctx, nm.cancel = context.WithCancel(ctx)
 	nm.ctxMultiplexChanClosed, nm.cancelMultiplexChanClosed = context.WithCancel(context.Background())
 
 	// Create a notification manager for each shard
+	changed := shardManager.Changed()
 	shards := shardManager.GetAll()
 	nm.initWaitGroup = common.NewWaitGroup(len(shards))
 
 	for _, shard := range shards {
-		newShardNotificationsManager(shard, nm)
+		nm.startShard(shard, false)
 	}
 
+	go common.DoWithLabels(
+		nm.ctx,
+		map[string]string{
+			"oxia": "notifications-shards-watcher",
+		},
+		func() { nm.watchShards(changed) },
+	)
+
 	go common.DoWithLabels(
 		nm.ctx,
 		map[string]string{
@@ -69,10 +100,10 @@ func newNotifications(ctx context.Context, options clientOptions, clientPool com
 		},
 		func() {
 			// Wait until all the shards managers are done before
-			// closing the user-facing channel
-			for i := 0; i < len(shards); i++ {
-				<-nm.closeCh
-			}
+			// closing the user-facing channel. No manager is started
+			// once the watcher is closed.
+			<-nm.watcherClosed
+			nm.shardsWg.Wait()
 
 			close(nm.multiplexCh)
 			nm.cancelMultiplexChanClosed()
@@ -90,10 +121,90 @@ func newNotifications(ctx context.Context, options clientOptions, clientPool com
 	return nm, nil
 }
 
+func (nm *notifications) startShard(shard int64, added bool) {
+	nm.Lock()
+	defer nm.Unlock()
+
+	nm.shardsWg.Add(1)
+	nm.shards[shard] = newShardNotificationsManager(shard, nm, added)
+}
+
+// Follow the updates of the shard assignments, so that the subscription
+// survives the leader changes and the shards being split or merged.
+func (nm *notifications) watchShards(changed <-chan struct{}) {
+	defer close(nm.watcherClosed)
+
+	for {
+		select {
+		case <-changed:
+			changed = nm.shardManager.Changed()
+			nm.updateShards()
+
+		case <-nm.ctx.Done():
+			return
+		}
+	}
+}
+
+func (nm *notifications) updateShards() {
+	current := common.NewSetFrom(nm.shardManager.GetAll())
+
+	nm.Lock()
+	var added []int64
+	for _, shard := range current.GetSorted() {
+		snm, ok := nm.shards[shard]
+		if !ok {
+			added = append(added, shard)
+			continue
+		}
+
+		// Don't wait for the stream to the old leader to fail
+		if leader, ok := nm.shardManager.LookupLeader(shard); ok {
+			snm.leaderChanged(leader)
+		}
+	}
+
+	for shard, snm := range nm.shards {
+		if !current.Contains(shard) {
+			snm.log.Info("Shard was removed, closing its notifications")
+			snm.cancel()
+			delete(nm.shards, shard)
+		}
+	}
+	nm.Unlock()
+
+	for _, shard := range added {
+		nm.startShard(shard, true)
+	}
+}
+
 func (nm *notifications) Ch() <-chan *Notification {
 	return nm.multiplexCh
 }
 
+func (nm *notifications) Ack(ctx context.Context, notification *Notification) error {
+	if nm.consumerName == nil {
+		return fmt.Errorf("%w: the notifications can only be acknowledged by a durable consumer", ErrInvalidOptions)
+	}
+
+	leader, ok := nm.shardManager.LookupLeader(notification.Shard)
+	if !ok {
+		return fmt.Errorf("shard %d is not part of the namespace anymore", notification.Shard)
+	}
+
+	rpc, err := nm.clientPool.GetClientRpc(leader)
+	if err != nil {
+		return err
+	}
+
+	_, err = rpc.AckNotifications(ctx, &proto.AckNotificationsRequest{
+		ShardId:      notification.Shard,
+		ConsumerName: *nm.consumerName,
+		Offset:       notification.Offset,
+	})
+	return err
+}
+
 func (nm *notifications) Close() error {
 	// Interrupt the go-routines receiving notifications on all the shards
 	nm.cancel()
@@ -112,28 +223,45 @@ func (nm *notifications) Close() error {
 
 // Manages the notifications for a specific shard.
 type shardNotificationsManager struct {
+	sync.Mutex
 	shard              int64
 	ctx                context.Context
+	cancel             context.CancelFunc
 	nm                 *notifications
 	backoff            backoff.BackOff
 	lastOffsetReceived int64
+	startOffset        *int64
 	initialized        bool
 	log                *slog.Logger
+
+	// The leader serving the current stream, and how to interrupt it
+	leader       string
+	cancelStream context.CancelFunc
 }
 
-func newShardNotificationsManager(shard int64, nm *notifications) *shardNotificationsManager {
+// A shard that is added after the subscription was created gets all its
+// notifications, from the first offset.
+func newShardNotificationsManager(shard int64, nm *notifications, added bool) *shardNotificationsManager {
 	snm := &shardNotificationsManager{
 		shard:              shard,
-		ctx:                nm.ctx,
 		nm:                 nm,
 		lastOffsetReceived: -1,
-		backoff:            common.NewBackOffWithInitialInterval(nm.ctx, 1*time.Second),
 		log: slog.With(
 			slog.String("component", "oxia-notifications-manager"),
 			slog.Int64("shard", shard),
 		),
 	}
 
+	snm.ctx, snm.cancel = context.WithCancel(nm.ctx)
+	snm.backoff = common.NewBackOffWithInitialInterval(snm.ctx, 1*time.Second)
+
+	if offset, ok := nm.startOffsets[shard]; ok {
+		snm.startOffset = &offset
+	} else if added {
+		snm.initialized = true
+		snm.startOffset = new(int64)
+	}
+
 	go common.DoWithLabels(
 		snm.ctx,
 		map[string]string{
@@ -165,7 +293,24 @@ func (snm *shardNotificationsManager) getNotificationsWithRetries() { //nolint:r
 		})
 
 	// Signal that this shard notification manager is now closed
-	snm.nm.closeCh <- nil
+	snm.nm.shardsWg.Done()
+}
+
+// Interrupt the current stream if the shard has moved to a different
+// leader, so that it's immediately resumed from the new one.
+func (snm *shardNotificationsManager) leaderChanged(leader string) {
+	snm.Lock()
+	defer snm.Unlock()
+
+	if snm.cancelStream != nil && snm.leader != leader {
+		snm.log.Info(
+			"Shard leader changed, moving the notifications stream",
+			slog.String("old-leader", snm.leader),
+			slog.String("new-leader", leader),
+		)
+		snm.cancelStream()
+		snm.cancelStream = nil
+	}
 }
 
 func (snm *shardNotificationsManager) multiplexNotificationBatch(nb *proto.NotificationBatch) error {
@@ -181,9 +326,18 @@ func (snm *shardNotificationsManager) multiplexNotificationBatch(nb *proto.Notif
 		return nil
 	}
 
+	// Deliver the notifications of merged batches in the order of the entries
+	notifications := make([]*Notification, 0, len(nb.Notifications))
 	for key, n := range nb.Notifications {
+		notifications = append(notifications, convertNotification(nb, key, n))
+	}
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].Offset < notifications[j].Offset
+	})
+
+	for _, n := range notifications {
 		select {
-		case snm.nm.multiplexCh <- convertNotification(key, n):
+		case snm.nm.multiplexCh <- n:
 
 		// Unblock from channel write when we're closing down
 		case <-snm.ctx.Done():
@@ -229,21 +383,39 @@ func (snm *shardNotificationsManager) multiplexNotifications(notifications proto
 }
 
 func (snm *shardNotificationsManager) getNotifications() error {
-	leader := snm.nm.shardManager.Leader(snm.shard)
+	leader, ok := snm.nm.shardManager.LookupLeader(snm.shard)
+	if !ok {
+		return errors.New("shard is not part of the namespace anymore")
+	}
 
 	rpc, err := snm.nm.clientPool.GetClientRpc(leader)
 	if err != nil {
 		return err
 	}
 
+	ctx, cancel := context.WithCancel(snm.ctx)
+	defer cancel()
+
+	snm.Lock()
+	snm.leader = leader
+	snm.cancelStream = cancel
+	snm.Unlock()
+
 	var startOffsetExclusive *int64
 	if snm.lastOffsetReceived >= 0 {
 		startOffsetExclusive = &snm.lastOffsetReceived
+	} else if snm.startOffset != nil {
+		offset := *snm.startOffset - 1
+		startOffsetExclusive = &offset
 	}
 
-	notifications, err := rpc.GetNotifications(snm.ctx, &proto.NotificationsRequest{
+	notifications, err := rpc.GetNotifications(ctx, &proto.NotificationsRequest{
 		ShardId:              snm.shard,
 		StartOffsetExclusive: startOffsetExclusive,
+		KeyPrefixes:          snm.nm.keyPrefixes,
+		Types:                snm.nm.types,
+		IncludeValue:         snm.nm.includeValue,
+		ConsumerName:         snm.nm.consumerName,
 	})
 	if err != nil {
 		if snm.ctx.Err() != nil {
@@ -254,9 +426,34 @@ func (snm *shardNotificationsManager) getNotifications() error {
 
 	snm.backoff.Reset()
 
+	if !snm.initialized && startOffsetExclusive != nil {
+		// The server doesn't send the first dummy notification when resuming
+		// from an offset
+		snm.log.Debug("Initialized the notification manager", slog.Int64("start-offset", *snm.startOffset))
+		snm.initialized = true
+		snm.nm.initWaitGroup.Done()
+	}
+
 	return snm.multiplexNotifications(notifications)
 }
 
+func toProtoNotificationTypes(types []NotificationType) []proto.NotificationType {
+	res := make([]proto.NotificationType, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case KeyCreated:
+			res = append(res, proto.NotificationType_KEY_CREATED)
+		case KeyModified:
+			res = append(res, proto.NotificationType_KEY_MODIFIED)
+		case KeyDeleted:
+			res = append(res, proto.NotificationType_KEY_DELETED)
+		case KeyRangeDeleted:
+			res = append(res, proto.NotificationType_KEY_RANGE_DELETED)
+		}
+	}
+	return res
+}
+
 func convertNotificationType(t proto.NotificationType) NotificationType {
 	switch t {
 	case proto.NotificationType_KEY_CREATED:
@@ -265,19 +462,28 @@ func convertNotificationType(t proto.NotificationType) NotificationType {
 		return KeyModified
 	case proto.NotificationType_KEY_DELETED:
 		return KeyDeleted
+	case proto.NotificationType_KEY_RANGE_DELETED:
+		return KeyRangeDeleted
 	default:
 		panic("Invalid notification type")
 	}
 }
 
-func convertNotification(key string, n *proto.Notification) *Notification {
+func convertNotification(nb *proto.NotificationBatch, key string, n *proto.Notification) *Notification {
 	versionId := int64(-1)
 	if n.VersionId != nil {
 		versionId = *n.VersionId
 	}
+	offset := nb.Offset
+	if n.Offset != nil {
+		offset = *n.Offset
+	}
 	return &Notification{
 		Type:      convertNotificationType(n.Type),
 		Key:       key,
 		VersionId: versionId,
+		Shard:     nb.ShardId,
+		Offset:    offset,
+		Value:     n.Value,
 	}
 }
@@ -49,6 +49,7 @@ var (
 	ErrInvalidOptionNamespace           = errors.New("Namespace cannot be empty")
 	ErrInvalidOptionTLS                 = errors.New("Tls cannot be empty")
 	ErrInvalidOptionAuthentication      = errors.New("Authentication cannot be empty")
+	ErrInvalidOptionSessionListener     = errors.New("SessionListener cannot be nil")
 )
 
 // clientOptions contains options for the Oxia client.
@@ -64,6 +65,7 @@ type clientOptions struct {
 	identity            string
 	tls                 *tls.Config
 	authentication      auth.Authentication
+	sessionListener     func(SessionEvent)
 }
 
 func defaultIdentity() string {
@@ -213,3 +215,17 @@ func WithAuthentication(authentication auth.Authentication) ClientOption {
 		return options, nil
 	})
 }
+
+// WithSessionListener registers a function that is called with the changes in the
+// lifecycle of the sessions of the client, so that the applications relying on the
+// ephemeral records can stop acting on them while the session is not valid.
+// The listener is called synchronously and must not block.
+func WithSessionListener(listener func(SessionEvent)) ClientOption {
+	return clientOptionFunc(func(options clientOptions) (clientOptions, error) {
+		if listener == nil {
+			return options, ErrInvalidOptionSessionListener
+		}
+		options.sessionListener = listener
+		return options, nil
+	})
+}
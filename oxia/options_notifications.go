@@ -0,0 +1,143 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oxia
+
+import "github.com/pkg/errors"
+
+type notificationsOptions struct {
+	keyPrefixes  []string
+	types        []NotificationType
+	includeValue bool
+	startOffsets map[int64]int64
+	consumerName string
+}
+
+// NotificationsOption represents an option for the [SyncClient.GetNotifications] operation.
+type NotificationsOption interface {
+	applyNotifications(opts *notificationsOptions)
+}
+
+func newNotificationsOptions(opts []NotificationsOption) (*notificationsOptions, error) {
+	notificationsOpts := &notificationsOptions{}
+	for _, opt := range opts {
+		opt.applyNotifications(notificationsOpts)
+	}
+
+	for _, t := range notificationsOpts.types {
+		if t < KeyCreated || t > KeyRangeDeleted {
+			return nil, errors.Wrapf(ErrInvalidOptions, "invalid notification type %d", t)
+		}
+	}
+	return notificationsOpts, nil
+}
+
+type keyPrefixesOpt struct {
+	keyPrefixes []string
+}
+
+func (o *keyPrefixesOpt) applyNotifications(opts *notificationsOptions) {
+	opts.keyPrefixes = append(opts.keyPrefixes, o.keyPrefixes...)
+}
+
+// KeyPrefixes only subscribes to the notifications for the keys that start with
+// any of the given prefixes. The filtering is applied by the servers, so the other
+// notifications are never sent to the client.
+func KeyPrefixes(prefixes ...string) NotificationsOption {
+	return &keyPrefixesOpt{
+		keyPrefixes: prefixes,
+	}
+}
+
+type eventTypesOpt struct {
+	types []NotificationType
+}
+
+func (o *eventTypesOpt) applyNotifications(opts *notificationsOptions) {
+	opts.types = append(opts.types, o.types...)
+}
+
+// EventTypes only subscribes to the notifications of the given types, eg: to
+// ignore the deletions. Like the key prefixes, the filtering is applied by the
+// servers. Selecting [KeyRangeDeleted] tells apart the records deleted by a
+// range deletion, which are otherwise received as [KeyDeleted].
+func EventTypes(types ...NotificationType) NotificationsOption {
+	return &eventTypesOpt{
+		types: types,
+	}
+}
+
+type includeValueOpt struct{}
+
+func (*includeValueOpt) applyNotifications(opts *notificationsOptions) {
+	opts.includeValue = true
+}
+
+// IncludeValue requests the value of the records in the KeyCreated and
+// KeyModified notifications, to avoid a Get for each of them, eg: to maintain
+// a cache. The value is only included when the record was not modified again
+// by the time the notification is sent, as a later notification follows.
+func IncludeValue() NotificationsOption {
+	return &includeValueOpt{}
+}
+
+type startFromOffsetsOpt struct {
+	offsets map[int64]int64
+}
+
+func (o *startFromOffsetsOpt) applyNotifications(opts *notificationsOptions) {
+	if opts.startOffsets == nil {
+		opts.startOffsets = map[int64]int64{}
+	}
+	for shard, offset := range o.offsets {
+		opts.startOffsets[shard] = offset
+	}
+}
+
+// StartFromOffsets resumes the notifications of each shard from the given
+// offset, inclusive, instead of only receiving the new ones. Passing the
+// [Notification.Offset] of the last notification processed for each shard
+// ensures that no notification is missed, though the ones generated by that
+// same entry can be received again.
+//
+// The notifications are only kept by the servers for the configured
+// notifications retention time. The shards that are not in the map start
+// from the new notifications.
+func StartFromOffsets(offsets map[int64]int64) NotificationsOption {
+	return &startFromOffsetsOpt{
+		offsets: offsets,
+	}
+}
+
+type consumerNameOpt struct {
+	name string
+}
+
+func (o *consumerNameOpt) applyNotifications(opts *notificationsOptions) {
+	opts.consumerName = o.name
+}
+
+// ConsumerName subscribes as a durable consumer. The servers store the
+// offsets acknowledged with [Notifications.Ack] for each consumer name, and
+// the notifications resume after them when a consumer with the same name
+// subscribes again, eg: after the application is restarted.
+//
+// The shards for which an offset is given with [StartFromOffsets] start from
+// that offset instead. The consumers that never acknowledged a notification
+// start from the new notifications.
+func ConsumerName(name string) NotificationsOption {
+	return &consumerNameOpt{
+		name: name,
+	}
+}
@@ -20,6 +20,7 @@ type putOptions struct {
 	baseOptions
 	expectedVersion    *int64
 	ephemeral          bool
+	lease              Lease
 	sequenceKeysDeltas []uint64
 }
 
@@ -48,6 +49,15 @@ func newPutOptions(opts []PutOption) (*putOptions, error) {
 		}
 	}
 
+	if putOpts.lease != nil {
+		if _, ok := putOpts.lease.(*lease); !ok {
+			return nil, errors.Wrap(ErrInvalidOptions, "the lease was not granted by an Oxia client")
+		}
+		if putOpts.ephemeral {
+			return nil, errors.Wrap(ErrInvalidOptions, "a record cannot be both ephemeral and attached to a lease")
+		}
+	}
+
 	return putOpts, nil
 }
 
@@ -77,6 +87,22 @@ func Ephemeral() PutOption {
 	return ephemeralFlag
 }
 
+type withLease struct {
+	lease Lease
+}
+
+func (w *withLease) applyPut(opts *putOptions) {
+	opts.lease = w.lease
+}
+
+// WithLease attaches the record to a lease created with [SyncClient.GrantLease].
+// The record is deleted once the lease expires or is revoked. Like the
+// [Ephemeral] records, the record is detached from the lease when it's
+// overwritten by a Put without the lease.
+func WithLease(l Lease) PutOption {
+	return &withLease{l}
+}
+
 type sequenceKeysDeltas struct {
 	sequenceKeysDeltas []uint64
 }
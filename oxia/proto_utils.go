@@ -35,6 +35,18 @@ func toPutResult(originalKey string, r *proto.PutResponse) PutResult {
 	return pr
 }
 
+func toIncrementResult(r *proto.PutResponse) IncrementResult {
+	if err := toError(r.Status); err != nil {
+		return IncrementResult{
+			Err: err,
+		}
+	}
+	return IncrementResult{
+		Value:   r.GetIncrementedValue(),
+		Version: toVersion(r.Version),
+	}
+}
+
 func toDeleteResult(r *proto.DeleteResponse) error {
 	return toError(r.Status)
 }
@@ -90,6 +102,10 @@ func toError(status proto.Status) error {
 		return ErrUnexpectedVersionId
 	case proto.Status_KEY_NOT_FOUND:
 		return ErrKeyNotFound
+	case proto.Status_INVALID_COUNTER_VALUE:
+		return ErrInvalidCounterValue
+	case proto.Status_SESSION_DOES_NOT_EXIST:
+		return ErrSessionNotFound
 	default:
 		return ErrUnknownStatus
 	}
@@ -39,6 +39,7 @@ func newSessions(ctx context.Context, shardManager internal.ShardManager, pool c
 		shardManager:    shardManager,
 		pool:            pool,
 		sessionsByShard: map[int64]*clientSession{},
+		expiredShards:   map[int64]bool{},
 		clientOpts:      options,
 		log: slog.With(
 			slog.String("component", "oxia-session-manager"),
@@ -57,6 +58,22 @@ type sessions struct {
 	sessionsByShard map[int64]*clientSession
 	log             *slog.Logger
 	clientOpts      clientOptions
+
+	// The shards where the last session expired, to tell apart the sessions
+	// that are reacquired
+	expiredShards map[int64]bool
+}
+
+func (s *sessions) notify(eventType SessionEventType, shardId int64, sessionId int64, err error) {
+	if s.clientOpts.sessionListener == nil {
+		return
+	}
+	s.clientOpts.sessionListener(SessionEvent{
+		Type:      eventType,
+		Shard:     shardId,
+		SessionId: sessionId,
+		Err:       err,
+	})
 }
 
 func (s *sessions) executeWithSessionId(shardId int64, callback func(int64, error)) {
@@ -72,15 +89,17 @@ func (s *sessions) executeWithSessionId(shardId int64, callback func(int64, erro
 
 func (s *sessions) startSession(shardId int64) *clientSession {
 	cs := &clientSession{
-		shardId:  shardId,
-		sessions: s,
-		started:  make(chan error),
+		shardId:    shardId,
+		sessions:   s,
+		started:    make(chan error),
+		reacquired: s.expiredShards[shardId],
 		log: slog.With(
 			slog.String("component", "session"),
 			slog.Int64("shard", shardId),
 		),
 	}
 
+	delete(s.expiredShards, shardId)
 	cs.ctx, cs.cancel = context.WithCancel(s.ctx)
 
 	cs.log.Debug("Creating session")
@@ -113,6 +132,9 @@ type clientSession struct {
 	sessions  *sessions
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// Whether the session replaces one that expired
+	reacquired bool
 }
 
 func (cs *clientSession) executeWithId(callback func(int64, error)) {
@@ -191,9 +213,21 @@ func (cs *clientSession) createSession() error {
 			"session": fmt.Sprintf("%x016", cs.sessionId),
 		},
 		func() {
+			if cs.reacquired {
+				cs.sessions.notify(SessionReacquired, cs.shardId, sessionId, nil)
+			} else {
+				cs.sessions.notify(SessionEstablished, cs.shardId, sessionId, nil)
+			}
+
+			failing := false
 			backOff := common.NewBackOff(cs.sessions.ctx)
 			err := backoff.RetryNotify(func() error {
-				err := cs.keepAlive()
+				err := cs.keepAlive(func() {
+					if failing {
+						failing = false
+						cs.sessions.notify(SessionHeartbeatRecovered, cs.shardId, sessionId, nil)
+					}
+				})
 				if status.Code(err) == common.CodeInvalidSession {
 					cs.log.Error(
 						"Session is no longer valid",
@@ -205,6 +239,7 @@ func (cs *clientSession) createSession() error {
 					cs.Lock()
 					defer cs.Unlock()
 					delete(cs.sessions.sessionsByShard, cs.shardId)
+					cs.sessions.expiredShards[cs.shardId] = true
 					return backoff.Permanent(err)
 				}
 				return err
@@ -214,9 +249,15 @@ func (cs *clientSession) createSession() error {
 					slog.Any("error", err),
 					slog.Duration("retry-after", duration),
 				)
+				if !failing {
+					failing = true
+					cs.sessions.notify(SessionHeartbeatFailing, cs.shardId, sessionId, err)
+				}
 			})
 
-			if err != nil && !errors.Is(err, context.Canceled) {
+			if status.Code(err) == common.CodeInvalidSession {
+				cs.sessions.notify(SessionExpired, cs.shardId, sessionId, err)
+			} else if err != nil && !errors.Is(err, context.Canceled) {
 				cs.log.Error(
 					"Failed to keep alive session",
 					slog.Any("error", err),
@@ -243,16 +284,20 @@ func (cs *clientSession) Close() error {
 	ctx, cancel := context.WithTimeout(cs.sessions.ctx, cs.sessions.clientOpts.requestTimeout)
 	defer cancel()
 
-	if _, err = rpc.CloseSession(ctx, &proto.CloseSessionRequest{
+	_, err = rpc.CloseSession(ctx, &proto.CloseSessionRequest{
 		ShardId:   cs.shardId,
 		SessionId: cs.sessionId,
-	}); err != nil {
-		return err
+	})
+	if status.Code(err) == common.CodeInvalidSession {
+		// The session has already expired, or was closed by an admin
+		return nil
 	}
-	return nil
+	return err
 }
 
-func (cs *clientSession) keepAlive() error {
+// Send the heartbeats of the session until it's closed, calling onHeartbeat
+// after each successful one.
+func (cs *clientSession) keepAlive(onHeartbeat func()) error {
 	cs.sessions.Lock()
 	cs.Lock()
 	timeout := cs.sessions.clientOpts.sessionTimeout
@@ -282,6 +327,7 @@ func (cs *clientSession) keepAlive() error {
 			if err != nil {
 				return err
 			}
+			onHeartbeat()
 		case <-ctx.Done():
 			return nil
 		}
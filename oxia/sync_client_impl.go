@@ -17,6 +17,7 @@ package oxia
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.uber.org/multierr"
 )
@@ -87,6 +88,15 @@ func (c *syncClientImpl) Put(ctx context.Context, key string, value []byte, opti
 	}
 }
 
+func (c *syncClientImpl) Increment(ctx context.Context, key string, delta int64, options ...PutOption) (int64, Version, error) {
+	select {
+	case r := <-c.asyncClient.Increment(key, delta, options...):
+		return r.Value, r.Version, r.Err
+	case <-ctx.Done():
+		return 0, Version{}, ctx.Err()
+	}
+}
+
 func (c *syncClientImpl) Delete(ctx context.Context, key string, options ...DeleteOption) error {
 	select {
 	case err := <-c.asyncClient.Delete(key, options...):
@@ -133,6 +143,10 @@ func (c *syncClientImpl) RangeScan(ctx context.Context, minKeyInclusive string,
 	return c.asyncClient.RangeScan(ctx, minKeyInclusive, maxKeyExclusive, options...)
 }
 
-func (c *syncClientImpl) GetNotifications() (Notifications, error) {
-	return c.asyncClient.GetNotifications()
+func (c *syncClientImpl) GetNotifications(options ...NotificationsOption) (Notifications, error) {
+	return c.asyncClient.GetNotifications(options...)
+}
+
+func (c *syncClientImpl) GrantLease(name string, ttl time.Duration) (Lease, error) {
+	return c.asyncClient.GrantLease(name, ttl)
 }
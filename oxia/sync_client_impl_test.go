@@ -17,6 +17,7 @@ package oxia
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,6 +31,10 @@ func (c *neverCompleteAsyncClient) Put(key string, value []byte, options ...PutO
 	return make(chan PutResult)
 }
 
+func (c *neverCompleteAsyncClient) Increment(key string, delta int64, options ...PutOption) <-chan IncrementResult {
+	return make(chan IncrementResult)
+}
+
 func (c *neverCompleteAsyncClient) Delete(key string, options ...DeleteOption) <-chan error {
 	return make(chan error)
 }
@@ -50,7 +55,11 @@ func (c *neverCompleteAsyncClient) RangeScan(ctx context.Context, minKeyInclusiv
 	panic("not implemented")
 }
 
-func (c *neverCompleteAsyncClient) GetNotifications() (Notifications, error) {
+func (c *neverCompleteAsyncClient) GetNotifications(...NotificationsOption) (Notifications, error) {
+	panic("not implemented")
+}
+
+func (c *neverCompleteAsyncClient) GrantLease(string, time.Duration) (Lease, error) {
 	panic("not implemented")
 }
 
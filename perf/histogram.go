@@ -0,0 +1,155 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+)
+
+const (
+	// Values below subBucketCount are recorded exactly, larger values are
+	// recorded with a relative precision of 1/subBucketHalfCount (~1.5%).
+	subBucketBits      = 7
+	subBucketCount     = 1 << subBucketBits
+	subBucketHalfCount = subBucketCount / 2
+	histogramBuckets   = subBucketCount + (64-subBucketBits)*subBucketHalfCount
+)
+
+// histogram is a log-linear latency histogram, modeled after HdrHistogram,
+// that records values in microseconds.
+type histogram struct {
+	counts     [histogramBuckets]int64
+	totalCount int64
+	sum        float64
+	sumSquares float64
+	max        int64
+}
+
+func newHistogram() *histogram {
+	return &histogram{}
+}
+
+func bucketIndex(value int64) int {
+	if value < subBucketCount {
+		return int(value)
+	}
+	shift := bits.Len64(uint64(value)) - subBucketBits
+	sub := int(value >> shift)
+	return subBucketCount + (shift-1)*subBucketHalfCount + (sub - subBucketHalfCount)
+}
+
+// bucketHighestValue returns the highest value that is recorded in the bucket.
+func bucketHighestValue(index int) int64 {
+	if index < subBucketCount {
+		return int64(index)
+	}
+	shift := (index-subBucketCount)/subBucketHalfCount + 1
+	sub := int64((index-subBucketCount)%subBucketHalfCount + subBucketHalfCount)
+	return (sub+1)<<shift - 1
+}
+
+func (h *histogram) Record(value int64) {
+	if value < 0 {
+		value = 0
+	}
+	h.counts[bucketIndex(value)]++
+	h.totalCount++
+	h.sum += float64(value)
+	h.sumSquares += float64(value) * float64(value)
+	if value > h.max {
+		h.max = value
+	}
+}
+
+func (h *histogram) TotalCount() int64 {
+	return h.totalCount
+}
+
+func (h *histogram) Mean() float64 {
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.sum / float64(h.totalCount)
+}
+
+func (h *histogram) StdDev() float64 {
+	if h.totalCount == 0 {
+		return 0
+	}
+	mean := h.Mean()
+	return math.Sqrt(math.Max(0, h.sumSquares/float64(h.totalCount)-mean*mean))
+}
+
+func (h *histogram) Max() int64 {
+	return h.max
+}
+
+// ValueAtPercentile returns the value below which the given percentage
+// (0-100) of the recorded values fall.
+func (h *histogram) ValueAtPercentile(percentile float64) int64 {
+	if h.totalCount == 0 {
+		return 0
+	}
+	target := int64(math.Ceil(percentile / 100 * float64(h.totalCount)))
+	if target < 1 {
+		target = 1
+	}
+
+	var count int64
+	for i, c := range h.counts {
+		count += c
+		if count >= target {
+			return min(bucketHighestValue(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// WritePercentileDistribution writes the recorded values, in milliseconds,
+// using the HdrHistogram percentile distribution format, so that the output
+// can be plotted and compared with the usual HdrHistogram tools.
+func (h *histogram) WritePercentileDistribution(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)"); err != nil {
+		return err
+	}
+
+	var count int64
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		count += c
+		value := float64(min(bucketHighestValue(i), h.max)) / 1000.0
+		percentile := float64(count) / float64(h.totalCount)
+
+		var err error
+		if count == h.totalCount {
+			_, err = fmt.Fprintf(w, "%12.3f %1.12f %10d\n", value, percentile, count)
+		} else {
+			_, err = fmt.Fprintf(w, "%12.3f %1.12f %10d %14.2f\n", value, percentile, count, 1/(1-percentile))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n"+
+		"#[Max     = %12.3f, Total count    = %12d]\n",
+		h.Mean()/1000.0, h.StdDev()/1000.0, float64(h.max)/1000.0, h.totalCount)
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_BucketIndex(t *testing.T) {
+	for _, value := range []int64{0, 1, 127, 128, 129, 255, 256, 1000, 123_456, 1 << 40} {
+		index := bucketIndex(value)
+		assert.LessOrEqual(t, value, bucketHighestValue(index))
+		if index > 0 {
+			assert.Greater(t, value, bucketHighestValue(index-1))
+		}
+	}
+
+	assert.Less(t, bucketIndex(1<<62), histogramBuckets)
+}
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h := newHistogram()
+	assert.EqualValues(t, 0, h.ValueAtPercentile(50))
+
+	for i := int64(1); i <= 10_000; i++ {
+		h.Record(i)
+	}
+
+	assert.EqualValues(t, 10_000, h.TotalCount())
+	assert.EqualValues(t, 10_000, h.Max())
+	assert.InDelta(t, 5_000.5, h.Mean(), 0.001)
+	assert.InEpsilon(t, 5_000, h.ValueAtPercentile(50), 0.02)
+	assert.InEpsilon(t, 9_900, h.ValueAtPercentile(99), 0.02)
+	assert.EqualValues(t, 10_000, h.ValueAtPercentile(100))
+}
+
+func TestHistogram_WritePercentileDistribution(t *testing.T) {
+	h := newHistogram()
+	// Recorded with the precision of its bucket
+	h.Record(1_000)
+	h.Record(2_000)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, h.WritePercentileDistribution(buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 6)
+	assert.Contains(t, lines[0], "Percentile")
+	assert.Equal(t, []string{"1.007", "0.500000000000", "1", "2.00"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"2.000", "1.000000000000", "2"}, strings.Fields(lines[3]))
+	assert.Contains(t, lines[5], "Total count")
+}
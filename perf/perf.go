@@ -21,6 +21,7 @@ import (
 	"log/slog"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync/atomic"
 	"time"
 
@@ -36,7 +37,16 @@ type Config struct {
 	RequestRate     float64
 	ReadPercentage  float64
 	KeysCardinality uint32
+	KeyDistribution KeyDistribution
+	ZipfianExponent float64
 	ValueSize       uint32
+	ValueSizeMax    uint32
+	Namespaces      uint32
+	WarmUp          time.Duration
+
+	// HistogramOutputDir is the directory where the latency histograms of
+	// the whole run get written to when the run completes
+	HistogramOutputDir string
 
 	BatchLinger         time.Duration
 	MaxRequestsPerBatch int
@@ -70,25 +80,41 @@ func (p *perf) Run(ctx context.Context) {
 		p.keys[i] = fmt.Sprintf("key-%d", i)
 	}
 
-	client, err := oxia.NewAsyncClient(p.config.ServiceAddr, //nolint:contextcheck
-		oxia.WithNamespace(p.config.Namespace),
-		oxia.WithBatchLinger(p.config.BatchLinger),
-		oxia.WithMaxRequestsPerBatch(p.config.MaxRequestsPerBatch),
-		oxia.WithRequestTimeout(p.config.RequestTimeout),
-	)
-	if err != nil {
-		slog.Error(
-			"Failed to create Oxia client",
-			slog.Any("error", err),
+	var clients []oxia.AsyncClient
+	for _, namespace := range p.config.namespaces() {
+		client, err := oxia.NewAsyncClient(p.config.ServiceAddr, //nolint:contextcheck
+			oxia.WithNamespace(namespace),
+			oxia.WithBatchLinger(p.config.BatchLinger),
+			oxia.WithMaxRequestsPerBatch(p.config.MaxRequestsPerBatch),
+			oxia.WithRequestTimeout(p.config.RequestTimeout),
 		)
-		os.Exit(1)
+		if err != nil {
+			slog.Error(
+				"Failed to create Oxia client",
+				slog.Any("error", err),
+				slog.String("namespace", namespace),
+			)
+			os.Exit(1)
+		}
+		clients = append(clients, client)
 	}
 
 	writeLatencyCh := make(chan int64)
-	go p.generateWriteTraffic(ctx, client, writeLatencyCh)
+	go p.generateWriteTraffic(ctx, clients, writeLatencyCh)
 
 	readLatencyCh := make(chan int64)
-	go p.generateReadTraffic(ctx, client, readLatencyCh)
+	go p.generateReadTraffic(ctx, clients, readLatencyCh)
+
+	warmUpDeadline := time.Now().Add(p.config.WarmUp)
+	if p.config.WarmUp > 0 {
+		slog.Info(
+			"Warming up, the latencies will not be recorded until the warm-up is completed",
+			slog.Duration("warm-up", p.config.WarmUp),
+		)
+	}
+
+	writeHistogram := newHistogram()
+	readHistogram := newHistogram()
 
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -129,34 +155,103 @@ func (p *perf) Run(ctx context.Context) {
 			readOps = 0
 
 		case wl := <-writeLatencyCh:
+			if time.Now().Before(warmUpDeadline) {
+				continue
+			}
 			writeOps++
 			wq.Insert(float64(wl) / 1000.0) // Convert to millis
+			writeHistogram.Record(wl)
 
 		case rl := <-readLatencyCh:
+			if time.Now().Before(warmUpDeadline) {
+				continue
+			}
 			readOps++
 			rq.Insert(float64(rl) / 1000.0) // Convert to millis
+			readHistogram.Record(rl)
 
 		case <-ctx.Done():
+			p.logSummary("Write", writeHistogram)
+			p.logSummary("Read", readHistogram)
+			p.writeHistogram("write-latency.hgrm", writeHistogram)
+			p.writeHistogram("read-latency.hgrm", readHistogram)
+
+			for _, client := range clients {
+				_ = client.Close()
+			}
 			return
 		}
 	}
 }
 
-func (p *perf) generateWriteTraffic(ctx context.Context, client oxia.AsyncClient, latencyCh chan int64) {
+func (*perf) logSummary(operation string, h *histogram) {
+	slog.Info(fmt.Sprintf("%s summary - Total ops: %d  Latency ms: 50%% %5.1f - 95%% %5.1f - 99%% %5.1f - 99.9%% %5.1f - max %6.1f",
+		operation,
+		h.TotalCount(),
+		float64(h.ValueAtPercentile(50))/1000.0,
+		float64(h.ValueAtPercentile(95))/1000.0,
+		float64(h.ValueAtPercentile(99))/1000.0,
+		float64(h.ValueAtPercentile(99.9))/1000.0,
+		float64(h.Max())/1000.0,
+	))
+}
+
+func (p *perf) writeHistogram(name string, h *histogram) {
+	if p.config.HistogramOutputDir == "" {
+		return
+	}
+
+	path := filepath.Join(p.config.HistogramOutputDir, name)
+	if err := writeHistogramFile(path, h); err != nil {
+		slog.Warn(
+			"Failed to write the latency histogram",
+			slog.Any("error", err),
+			slog.String("path", path),
+		)
+		return
+	}
+
+	slog.Info(
+		"Latency histogram written",
+		slog.String("path", path),
+	)
+}
+
+func writeHistogramFile(path string, h *histogram) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := h.WritePercentileDistribution(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (p *perf) generateWriteTraffic(ctx context.Context, clients []oxia.AsyncClient, latencyCh chan int64) {
 	writeRate := p.config.RequestRate * (100.0 - p.config.ReadPercentage) / 100
 	limiter := rate.NewLimiter(rate.Limit(writeRate), int(writeRate))
 
-	value := make([]byte, p.config.ValueSize)
+	keys := newKeyGenerator(p.config)
+	valueSizes := newValueSizeGenerator(p.config)
+	value := make([]byte, valueSizes.max)
 
 	for {
 		if err := limiter.Wait(ctx); err != nil {
 			return
 		}
 
-		key := p.keys[rand.Intn(int(p.config.KeysCardinality))] //nolint:gosec
+		key := p.keys[keys.Next()]
+		client := clients[rand.Intn(len(clients))] //nolint:gosec
 
 		start := time.Now()
-		ch := client.Put(key, value)
+		ch := client.Put(key, value[:valueSizes.Next()])
 		go func() {
 			r := <-ch
 			if r.Err != nil {
@@ -179,16 +274,19 @@ func (p *perf) generateWriteTraffic(ctx context.Context, client oxia.AsyncClient
 	}
 }
 
-func (p *perf) generateReadTraffic(ctx context.Context, client oxia.AsyncClient, latencyCh chan int64) {
+func (p *perf) generateReadTraffic(ctx context.Context, clients []oxia.AsyncClient, latencyCh chan int64) {
 	readRate := p.config.RequestRate * p.config.ReadPercentage / 100
 	limiter := rate.NewLimiter(rate.Limit(readRate), int(readRate))
 
+	keys := newKeyGenerator(p.config)
+
 	for {
 		if err := limiter.Wait(ctx); err != nil {
 			return
 		}
 
-		key := p.keys[rand.Intn(int(p.config.KeysCardinality))] //nolint:gosec
+		key := p.keys[keys.Next()]
+		client := clients[rand.Intn(len(clients))] //nolint:gosec
 
 		start := time.Now()
 		ch := client.Get(key)
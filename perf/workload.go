@@ -0,0 +1,134 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type KeyDistribution string
+
+const (
+	KeyDistributionUniform KeyDistribution = "uniform"
+	KeyDistributionZipfian KeyDistribution = "zipfian"
+)
+
+// keyGenerator picks the index of the next key to use. It is not safe for
+// concurrent use.
+type keyGenerator interface {
+	Next() int
+}
+
+type uniformKeyGenerator struct {
+	rand        *rand.Rand
+	cardinality int
+}
+
+func (g *uniformKeyGenerator) Next() int {
+	return g.rand.Intn(g.cardinality)
+}
+
+// zipfianKeyGenerator makes the keys with a lower index much more likely
+// to be picked, to simulate a workload with hot keys.
+type zipfianKeyGenerator struct {
+	zipf *rand.Zipf
+}
+
+func (g *zipfianKeyGenerator) Next() int {
+	return int(g.zipf.Uint64())
+}
+
+func newKeyGenerator(config Config) keyGenerator {
+	r := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	if config.KeyDistribution == KeyDistributionZipfian {
+		return &zipfianKeyGenerator{
+			zipf: rand.NewZipf(r, config.ZipfianExponent, 1, uint64(config.KeysCardinality-1)),
+		}
+	}
+
+	return &uniformKeyGenerator{
+		rand:        r,
+		cardinality: int(config.KeysCardinality),
+	}
+}
+
+// valueSizeGenerator picks the size of the next value to write, uniformly
+// distributed between the minimum and the maximum value size. It is not safe
+// for concurrent use.
+type valueSizeGenerator struct {
+	rand *rand.Rand
+	min  uint32
+	max  uint32
+}
+
+func newValueSizeGenerator(config Config) *valueSizeGenerator {
+	return &valueSizeGenerator{
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+		min:  config.ValueSize,
+		max:  max(config.ValueSize, config.ValueSizeMax),
+	}
+}
+
+func (g *valueSizeGenerator) Next() uint32 {
+	if g.max == g.min {
+		return g.min
+	}
+	return g.min + uint32(g.rand.Int63n(int64(g.max-g.min)+1))
+}
+
+func (c *Config) Validate() error {
+	if c.KeysCardinality == 0 {
+		return errors.New("keys cardinality must be greater than 0")
+	}
+	switch c.KeyDistribution {
+	case KeyDistributionUniform:
+	case KeyDistributionZipfian:
+		if c.ZipfianExponent <= 1 {
+			return errors.New("zipfian exponent must be greater than 1")
+		}
+	default:
+		return fmt.Errorf("unknown key distribution %q, expected %q or %q",
+			c.KeyDistribution, KeyDistributionUniform, KeyDistributionZipfian)
+	}
+	if c.ValueSizeMax != 0 && c.ValueSizeMax < c.ValueSize {
+		return errors.New("max value size must not be smaller than the value size")
+	}
+	if c.Namespaces == 0 {
+		return errors.New("number of namespaces must be greater than 0")
+	}
+	if c.ReadPercentage < 0 || c.ReadPercentage > 100 {
+		return errors.New("read percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+// namespaces returns the namespaces to spread the traffic across. With more
+// than one namespace, they are named after the configured one, with an index
+// suffix.
+func (c *Config) namespaces() []string {
+	if c.Namespaces <= 1 {
+		return []string{c.Namespace}
+	}
+
+	namespaces := make([]string, c.Namespaces)
+	for i := range namespaces {
+		namespaces[i] = fmt.Sprintf("%s-%d", c.Namespace, i)
+	}
+	return namespaces
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyGenerator(t *testing.T) {
+	for _, distribution := range []KeyDistribution{KeyDistributionUniform, KeyDistributionZipfian} {
+		g := newKeyGenerator(Config{KeysCardinality: 10, KeyDistribution: distribution, ZipfianExponent: 1.1})
+		for i := 0; i < 1000; i++ {
+			k := g.Next()
+			assert.GreaterOrEqual(t, k, 0)
+			assert.Less(t, k, 10)
+		}
+	}
+}
+
+func TestValueSizeGenerator(t *testing.T) {
+	g := newValueSizeGenerator(Config{ValueSize: 10})
+	assert.EqualValues(t, 10, g.Next())
+
+	g = newValueSizeGenerator(Config{ValueSize: 10, ValueSizeMax: 20})
+	for i := 0; i < 1000; i++ {
+		s := g.Next()
+		assert.GreaterOrEqual(t, s, uint32(10))
+		assert.LessOrEqual(t, s, uint32(20))
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{
+		KeysCardinality: 10,
+		KeyDistribution: KeyDistributionUniform,
+		Namespaces:      1,
+		ReadPercentage:  80,
+	}
+	assert.NoError(t, valid.Validate())
+
+	for _, update := range []func(c *Config){
+		func(c *Config) { c.KeysCardinality = 0 },
+		func(c *Config) { c.KeyDistribution = "normal" },
+		func(c *Config) { c.KeyDistribution = KeyDistributionZipfian; c.ZipfianExponent = 1 },
+		func(c *Config) { c.ValueSize = 10; c.ValueSizeMax = 5 },
+		func(c *Config) { c.Namespaces = 0 },
+		func(c *Config) { c.ReadPercentage = 101 },
+	} {
+		c := valid
+		update(&c)
+		assert.Error(t, c.Validate())
+	}
+}
+
+func TestConfig_Namespaces(t *testing.T) {
+	c := Config{Namespace: "ns", Namespaces: 1}
+	assert.Equal(t, []string{"ns"}, c.namespaces())
+
+	c.Namespaces = 3
+	assert.Equal(t, []string{"ns-0", "ns-1", "ns-2"}, c.namespaces())
+}
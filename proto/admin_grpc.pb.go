@@ -0,0 +1,1158 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: admin.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// OxiaAdminClient is the client API for OxiaAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OxiaAdminClient interface {
+	// Create a new namespace, without having to change the cluster config
+	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error)
+	// Delete a namespace that was created through the admin API, along with
+	// all its shards
+	DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*DeleteNamespaceResponse, error)
+	// Start moving all the shard replicas out of a server, so that it can be
+	// removed from the cluster. The call returns immediately and it can be
+	// repeated to follow the progress of the decommission.
+	DecommissionServer(ctx context.Context, in *DecommissionServerRequest, opts ...grpc.CallOption) (*DecommissionServerResponse, error)
+	// Move the shard replicas so that all the servers have a similar number
+	// of them, eg: after adding new servers to the cluster
+	RebalanceCluster(ctx context.Context, in *RebalanceClusterRequest, opts ...grpc.CallOption) (*RebalanceClusterResponse, error)
+	// Compute the moves that a rebalance would apply, along with the size of
+	// the data to copy, without applying them
+	PlanRebalance(ctx context.Context, in *PlanRebalanceRequest, opts ...grpc.CallOption) (*PlanRebalanceResponse, error)
+	// Apply the moves of a rebalance plan in the background, one at a time
+	ExecuteRebalancePlan(ctx context.Context, in *ExecuteRebalancePlanRequest, opts ...grpc.CallOption) (*ExecuteRebalancePlanResponse, error)
+	// Get the latest rebalance plan, with the progress of its execution
+	GetRebalancePlan(ctx context.Context, in *GetRebalancePlanRequest, opts ...grpc.CallOption) (*GetRebalancePlanResponse, error)
+	// Put a server in maintenance, or bring it back. A drained server keeps its
+	// replicas, but it doesn't lead any shard and no new replica is placed on it
+	SetServerDrain(ctx context.Context, in *SetServerDrainRequest, opts ...grpc.CallOption) (*SetServerDrainResponse, error)
+	// Move all the replicas of a failed server to a new server, which recovers
+	// the shards from their leaders
+	ReplaceServer(ctx context.Context, in *ReplaceServerRequest, opts ...grpc.CallOption) (*ReplaceServerResponse, error)
+	// List the namespaces of the cluster
+	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	// List the shards of a namespace, with their ensembles, leaders and terms
+	ListShards(ctx context.Context, in *ListShardsRequest, opts ...grpc.CallOption) (*ListShardsResponse, error)
+	// List the servers of the cluster, with their health and the shards they host
+	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
+	// List the operations that the coordinator is carrying out
+	ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error)
+	// Transfer the leadership of a shard to another member of its ensemble,
+	// once the new leader has caught up with the current one
+	TransferLeader(ctx context.Context, in *TransferLeaderRequest, opts ...grpc.CallOption) (*TransferLeaderResponse, error)
+	// Merge a shard into an adjacent shard of the same namespace: its records
+	// are copied into the adjacent shard, which then takes over its hash
+	// range, and the merged shard is deleted
+	MergeShard(ctx context.Context, in *MergeShardRequest, opts ...grpc.CallOption) (*MergeShardResponse, error)
+	// Start or end a maintenance freeze. While the cluster is frozen, the
+	// coordinator doesn't move any shard replica or leader on its own, though
+	// the leader elections still happen
+	SetClusterFreeze(ctx context.Context, in *SetClusterFreezeRequest, opts ...grpc.CallOption) (*SetClusterFreezeResponse, error)
+	// List the latest decisions taken by the coordinator and the changes it
+	// observed in the cluster, like the leader elections and the failed servers
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// Stream the coordinator events as they happen, eg: the leader elections,
+	// the replica moves and the ensemble changes. The stream is closed by the
+	// coordinator when the watcher falls too far behind.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (OxiaAdmin_WatchEventsClient, error)
+	// Export the full cluster status, with the namespaces and the shard
+	// metadata, to bootstrap a new coordinator after the loss of the
+	// metadata store
+	ExportClusterStatus(ctx context.Context, in *ExportClusterStatusRequest, opts ...grpc.CallOption) (*ExportClusterStatusResponse, error)
+	// List the latest records of the audit log, with all the mutations of the
+	// cluster, who requested them and why
+	ListAuditRecords(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error)
+	// Stream the in-memory state of the active coordinator, to the standby
+	// coordinator replicas that mirror it to take over without delay. A new
+	// snapshot is sent whenever the state changes.
+	StreamCoordinatorState(ctx context.Context, in *StreamCoordinatorStateRequest, opts ...grpc.CallOption) (OxiaAdmin_StreamCoordinatorStateClient, error)
+	// List the sessions that are active on the shard leaders of a namespace,
+	// with their client identity and the number of their ephemeral records
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// Close a session on the shard leader, as if it had expired. Its
+	// ephemeral records are deleted.
+	ForceCloseSession(ctx context.Context, in *ForceCloseSessionRequest, opts ...grpc.CallOption) (*ForceCloseSessionResponse, error)
+	// Grant permissions to a principal on the keys of a namespace that start
+	// with a prefix, replacing the permissions it had on the same prefix
+	SetKeyPrefixACL(ctx context.Context, in *SetKeyPrefixACLRequest, opts ...grpc.CallOption) (*SetKeyPrefixACLResponse, error)
+	// Remove the permissions of a principal on a key prefix
+	RemoveKeyPrefixACL(ctx context.Context, in *RemoveKeyPrefixACLRequest, opts ...grpc.CallOption) (*RemoveKeyPrefixACLResponse, error)
+	// List the permissions granted on the key prefixes of a namespace
+	ListKeyPrefixACLs(ctx context.Context, in *ListKeyPrefixACLsRequest, opts ...grpc.CallOption) (*ListKeyPrefixACLsResponse, error)
+}
+
+type oxiaAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOxiaAdminClient(cc grpc.ClientConnInterface) OxiaAdminClient {
+	return &oxiaAdminClient{cc}
+}
+
+func (c *oxiaAdminClient) CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error) {
+	out := new(CreateNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/CreateNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*DeleteNamespaceResponse, error) {
+	out := new(DeleteNamespaceResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/DeleteNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) DecommissionServer(ctx context.Context, in *DecommissionServerRequest, opts ...grpc.CallOption) (*DecommissionServerResponse, error) {
+	out := new(DecommissionServerResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/DecommissionServer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) RebalanceCluster(ctx context.Context, in *RebalanceClusterRequest, opts ...grpc.CallOption) (*RebalanceClusterResponse, error) {
+	out := new(RebalanceClusterResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/RebalanceCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) PlanRebalance(ctx context.Context, in *PlanRebalanceRequest, opts ...grpc.CallOption) (*PlanRebalanceResponse, error) {
+	out := new(PlanRebalanceResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/PlanRebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ExecuteRebalancePlan(ctx context.Context, in *ExecuteRebalancePlanRequest, opts ...grpc.CallOption) (*ExecuteRebalancePlanResponse, error) {
+	out := new(ExecuteRebalancePlanResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ExecuteRebalancePlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) GetRebalancePlan(ctx context.Context, in *GetRebalancePlanRequest, opts ...grpc.CallOption) (*GetRebalancePlanResponse, error) {
+	out := new(GetRebalancePlanResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/GetRebalancePlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) SetServerDrain(ctx context.Context, in *SetServerDrainRequest, opts ...grpc.CallOption) (*SetServerDrainResponse, error) {
+	out := new(SetServerDrainResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/SetServerDrain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ReplaceServer(ctx context.Context, in *ReplaceServerRequest, opts ...grpc.CallOption) (*ReplaceServerResponse, error) {
+	out := new(ReplaceServerResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ReplaceServer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error) {
+	out := new(ListNamespacesResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListShards(ctx context.Context, in *ListShardsRequest, opts ...grpc.CallOption) (*ListShardsResponse, error) {
+	out := new(ListShardsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListShards", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error) {
+	out := new(ListServersResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListServers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error) {
+	out := new(ListOperationsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) TransferLeader(ctx context.Context, in *TransferLeaderRequest, opts ...grpc.CallOption) (*TransferLeaderResponse, error) {
+	out := new(TransferLeaderResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/TransferLeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) MergeShard(ctx context.Context, in *MergeShardRequest, opts ...grpc.CallOption) (*MergeShardResponse, error) {
+	out := new(MergeShardResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/MergeShard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) SetClusterFreeze(ctx context.Context, in *SetClusterFreezeRequest, opts ...grpc.CallOption) (*SetClusterFreezeResponse, error) {
+	out := new(SetClusterFreezeResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/SetClusterFreeze", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (OxiaAdmin_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OxiaAdmin_ServiceDesc.Streams[0], "/admin.OxiaAdmin/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &oxiaAdminWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OxiaAdmin_WatchEventsClient interface {
+	Recv() (*EventInfo, error)
+	grpc.ClientStream
+}
+
+type oxiaAdminWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *oxiaAdminWatchEventsClient) Recv() (*EventInfo, error) {
+	m := new(EventInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *oxiaAdminClient) ExportClusterStatus(ctx context.Context, in *ExportClusterStatusRequest, opts ...grpc.CallOption) (*ExportClusterStatusResponse, error) {
+	out := new(ExportClusterStatusResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ExportClusterStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListAuditRecords(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error) {
+	out := new(ListAuditRecordsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListAuditRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) StreamCoordinatorState(ctx context.Context, in *StreamCoordinatorStateRequest, opts ...grpc.CallOption) (OxiaAdmin_StreamCoordinatorStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OxiaAdmin_ServiceDesc.Streams[1], "/admin.OxiaAdmin/StreamCoordinatorState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &oxiaAdminStreamCoordinatorStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OxiaAdmin_StreamCoordinatorStateClient interface {
+	Recv() (*CoordinatorStateSnapshot, error)
+	grpc.ClientStream
+}
+
+type oxiaAdminStreamCoordinatorStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *oxiaAdminStreamCoordinatorStateClient) Recv() (*CoordinatorStateSnapshot, error) {
+	m := new(CoordinatorStateSnapshot)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *oxiaAdminClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ForceCloseSession(ctx context.Context, in *ForceCloseSessionRequest, opts ...grpc.CallOption) (*ForceCloseSessionResponse, error) {
+	out := new(ForceCloseSessionResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ForceCloseSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) SetKeyPrefixACL(ctx context.Context, in *SetKeyPrefixACLRequest, opts ...grpc.CallOption) (*SetKeyPrefixACLResponse, error) {
+	out := new(SetKeyPrefixACLResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/SetKeyPrefixACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) RemoveKeyPrefixACL(ctx context.Context, in *RemoveKeyPrefixACLRequest, opts ...grpc.CallOption) (*RemoveKeyPrefixACLResponse, error) {
+	out := new(RemoveKeyPrefixACLResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/RemoveKeyPrefixACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaAdminClient) ListKeyPrefixACLs(ctx context.Context, in *ListKeyPrefixACLsRequest, opts ...grpc.CallOption) (*ListKeyPrefixACLsResponse, error) {
+	out := new(ListKeyPrefixACLsResponse)
+	err := c.cc.Invoke(ctx, "/admin.OxiaAdmin/ListKeyPrefixACLs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OxiaAdminServer is the server API for OxiaAdmin service.
+// All implementations must embed UnimplementedOxiaAdminServer
+// for forward compatibility
+type OxiaAdminServer interface {
+	// Create a new namespace, without having to change the cluster config
+	CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error)
+	// Delete a namespace that was created through the admin API, along with
+	// all its shards
+	DeleteNamespace(context.Context, *DeleteNamespaceRequest) (*DeleteNamespaceResponse, error)
+	// Start moving all the shard replicas out of a server, so that it can be
+	// removed from the cluster. The call returns immediately and it can be
+	// repeated to follow the progress of the decommission.
+	DecommissionServer(context.Context, *DecommissionServerRequest) (*DecommissionServerResponse, error)
+	// Move the shard replicas so that all the servers have a similar number
+	// of them, eg: after adding new servers to the cluster
+	RebalanceCluster(context.Context, *RebalanceClusterRequest) (*RebalanceClusterResponse, error)
+	// Compute the moves that a rebalance would apply, along with the size of
+	// the data to copy, without applying them
+	PlanRebalance(context.Context, *PlanRebalanceRequest) (*PlanRebalanceResponse, error)
+	// Apply the moves of a rebalance plan in the background, one at a time
+	ExecuteRebalancePlan(context.Context, *ExecuteRebalancePlanRequest) (*ExecuteRebalancePlanResponse, error)
+	// Get the latest rebalance plan, with the progress of its execution
+	GetRebalancePlan(context.Context, *GetRebalancePlanRequest) (*GetRebalancePlanResponse, error)
+	// Put a server in maintenance, or bring it back. A drained server keeps its
+	// replicas, but it doesn't lead any shard and no new replica is placed on it
+	SetServerDrain(context.Context, *SetServerDrainRequest) (*SetServerDrainResponse, error)
+	// Move all the replicas of a failed server to a new server, which recovers
+	// the shards from their leaders
+	ReplaceServer(context.Context, *ReplaceServerRequest) (*ReplaceServerResponse, error)
+	// List the namespaces of the cluster
+	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
+	// List the shards of a namespace, with their ensembles, leaders and terms
+	ListShards(context.Context, *ListShardsRequest) (*ListShardsResponse, error)
+	// List the servers of the cluster, with their health and the shards they host
+	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
+	// List the operations that the coordinator is carrying out
+	ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error)
+	// Transfer the leadership of a shard to another member of its ensemble,
+	// once the new leader has caught up with the current one
+	TransferLeader(context.Context, *TransferLeaderRequest) (*TransferLeaderResponse, error)
+	// Merge a shard into an adjacent shard of the same namespace: its records
+	// are copied into the adjacent shard, which then takes over its hash
+	// range, and the merged shard is deleted
+	MergeShard(context.Context, *MergeShardRequest) (*MergeShardResponse, error)
+	// Start or end a maintenance freeze. While the cluster is frozen, the
+	// coordinator doesn't move any shard replica or leader on its own, though
+	// the leader elections still happen
+	SetClusterFreeze(context.Context, *SetClusterFreezeRequest) (*SetClusterFreezeResponse, error)
+	// List the latest decisions taken by the coordinator and the changes it
+	// observed in the cluster, like the leader elections and the failed servers
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	// Stream the coordinator events as they happen, eg: the leader elections,
+	// the replica moves and the ensemble changes. The stream is closed by the
+	// coordinator when the watcher falls too far behind.
+	WatchEvents(*WatchEventsRequest, OxiaAdmin_WatchEventsServer) error
+	// Export the full cluster status, with the namespaces and the shard
+	// metadata, to bootstrap a new coordinator after the loss of the
+	// metadata store
+	ExportClusterStatus(context.Context, *ExportClusterStatusRequest) (*ExportClusterStatusResponse, error)
+	// List the latest records of the audit log, with all the mutations of the
+	// cluster, who requested them and why
+	ListAuditRecords(context.Context, *ListAuditRecordsRequest) (*ListAuditRecordsResponse, error)
+	// Stream the in-memory state of the active coordinator, to the standby
+	// coordinator replicas that mirror it to take over without delay. A new
+	// snapshot is sent whenever the state changes.
+	StreamCoordinatorState(*StreamCoordinatorStateRequest, OxiaAdmin_StreamCoordinatorStateServer) error
+	// List the sessions that are active on the shard leaders of a namespace,
+	// with their client identity and the number of their ephemeral records
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// Close a session on the shard leader, as if it had expired. Its
+	// ephemeral records are deleted.
+	ForceCloseSession(context.Context, *ForceCloseSessionRequest) (*ForceCloseSessionResponse, error)
+	// Grant permissions to a principal on the keys of a namespace that start
+	// with a prefix, replacing the permissions it had on the same prefix
+	SetKeyPrefixACL(context.Context, *SetKeyPrefixACLRequest) (*SetKeyPrefixACLResponse, error)
+	// Remove the permissions of a principal on a key prefix
+	RemoveKeyPrefixACL(context.Context, *RemoveKeyPrefixACLRequest) (*RemoveKeyPrefixACLResponse, error)
+	// List the permissions granted on the key prefixes of a namespace
+	ListKeyPrefixACLs(context.Context, *ListKeyPrefixACLsRequest) (*ListKeyPrefixACLsResponse, error)
+	mustEmbedUnimplementedOxiaAdminServer()
+}
+
+// UnimplementedOxiaAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedOxiaAdminServer struct {
+}
+
+func (UnimplementedOxiaAdminServer) CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNamespace not implemented")
+}
+func (UnimplementedOxiaAdminServer) DeleteNamespace(context.Context, *DeleteNamespaceRequest) (*DeleteNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNamespace not implemented")
+}
+func (UnimplementedOxiaAdminServer) DecommissionServer(context.Context, *DecommissionServerRequest) (*DecommissionServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecommissionServer not implemented")
+}
+func (UnimplementedOxiaAdminServer) RebalanceCluster(context.Context, *RebalanceClusterRequest) (*RebalanceClusterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebalanceCluster not implemented")
+}
+func (UnimplementedOxiaAdminServer) PlanRebalance(context.Context, *PlanRebalanceRequest) (*PlanRebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanRebalance not implemented")
+}
+func (UnimplementedOxiaAdminServer) ExecuteRebalancePlan(context.Context, *ExecuteRebalancePlanRequest) (*ExecuteRebalancePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteRebalancePlan not implemented")
+}
+func (UnimplementedOxiaAdminServer) GetRebalancePlan(context.Context, *GetRebalancePlanRequest) (*GetRebalancePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRebalancePlan not implemented")
+}
+func (UnimplementedOxiaAdminServer) SetServerDrain(context.Context, *SetServerDrainRequest) (*SetServerDrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetServerDrain not implemented")
+}
+func (UnimplementedOxiaAdminServer) ReplaceServer(context.Context, *ReplaceServerRequest) (*ReplaceServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceServer not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNamespaces not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListShards(context.Context, *ListShardsRequest) (*ListShardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShards not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperations not implemented")
+}
+func (UnimplementedOxiaAdminServer) TransferLeader(context.Context, *TransferLeaderRequest) (*TransferLeaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferLeader not implemented")
+}
+func (UnimplementedOxiaAdminServer) MergeShard(context.Context, *MergeShardRequest) (*MergeShardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeShard not implemented")
+}
+func (UnimplementedOxiaAdminServer) SetClusterFreeze(context.Context, *SetClusterFreezeRequest) (*SetClusterFreezeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetClusterFreeze not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedOxiaAdminServer) WatchEvents(*WatchEventsRequest, OxiaAdmin_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedOxiaAdminServer) ExportClusterStatus(context.Context, *ExportClusterStatusRequest) (*ExportClusterStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportClusterStatus not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListAuditRecords(context.Context, *ListAuditRecordsRequest) (*ListAuditRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditRecords not implemented")
+}
+func (UnimplementedOxiaAdminServer) StreamCoordinatorState(*StreamCoordinatorStateRequest, OxiaAdmin_StreamCoordinatorStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCoordinatorState not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedOxiaAdminServer) ForceCloseSession(context.Context, *ForceCloseSessionRequest) (*ForceCloseSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceCloseSession not implemented")
+}
+func (UnimplementedOxiaAdminServer) SetKeyPrefixACL(context.Context, *SetKeyPrefixACLRequest) (*SetKeyPrefixACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyPrefixACL not implemented")
+}
+func (UnimplementedOxiaAdminServer) RemoveKeyPrefixACL(context.Context, *RemoveKeyPrefixACLRequest) (*RemoveKeyPrefixACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveKeyPrefixACL not implemented")
+}
+func (UnimplementedOxiaAdminServer) ListKeyPrefixACLs(context.Context, *ListKeyPrefixACLsRequest) (*ListKeyPrefixACLsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeyPrefixACLs not implemented")
+}
+func (UnimplementedOxiaAdminServer) mustEmbedUnimplementedOxiaAdminServer() {}
+
+// UnsafeOxiaAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OxiaAdminServer will
+// result in compilation errors.
+type UnsafeOxiaAdminServer interface {
+	mustEmbedUnimplementedOxiaAdminServer()
+}
+
+func RegisterOxiaAdminServer(s grpc.ServiceRegistrar, srv OxiaAdminServer) {
+	s.RegisterService(&OxiaAdmin_ServiceDesc, srv)
+}
+
+func _OxiaAdmin_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_DeleteNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).DeleteNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/DeleteNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).DeleteNamespace(ctx, req.(*DeleteNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_DecommissionServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).DecommissionServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/DecommissionServer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).DecommissionServer(ctx, req.(*DecommissionServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_RebalanceCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).RebalanceCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/RebalanceCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).RebalanceCluster(ctx, req.(*RebalanceClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_PlanRebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanRebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).PlanRebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/PlanRebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).PlanRebalance(ctx, req.(*PlanRebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ExecuteRebalancePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRebalancePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ExecuteRebalancePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ExecuteRebalancePlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ExecuteRebalancePlan(ctx, req.(*ExecuteRebalancePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_GetRebalancePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRebalancePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).GetRebalancePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/GetRebalancePlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).GetRebalancePlan(ctx, req.(*GetRebalancePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_SetServerDrain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetServerDrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).SetServerDrain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/SetServerDrain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).SetServerDrain(ctx, req.(*SetServerDrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ReplaceServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ReplaceServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ReplaceServer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ReplaceServer(ctx, req.(*ReplaceServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListShards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListShards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListShards",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListShards(ctx, req.(*ListShardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListServers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListServers(ctx, req.(*ListServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListOperations(ctx, req.(*ListOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_TransferLeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferLeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).TransferLeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/TransferLeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).TransferLeader(ctx, req.(*TransferLeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_MergeShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeShardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).MergeShard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/MergeShard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).MergeShard(ctx, req.(*MergeShardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_SetClusterFreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClusterFreezeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).SetClusterFreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/SetClusterFreeze",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).SetClusterFreeze(ctx, req.(*SetClusterFreezeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OxiaAdminServer).WatchEvents(m, &oxiaAdminWatchEventsServer{stream})
+}
+
+type OxiaAdmin_WatchEventsServer interface {
+	Send(*EventInfo) error
+	grpc.ServerStream
+}
+
+type oxiaAdminWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *oxiaAdminWatchEventsServer) Send(m *EventInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OxiaAdmin_ExportClusterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportClusterStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ExportClusterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ExportClusterStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ExportClusterStatus(ctx, req.(*ExportClusterStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListAuditRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListAuditRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListAuditRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListAuditRecords(ctx, req.(*ListAuditRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_StreamCoordinatorState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCoordinatorStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OxiaAdminServer).StreamCoordinatorState(m, &oxiaAdminStreamCoordinatorStateServer{stream})
+}
+
+type OxiaAdmin_StreamCoordinatorStateServer interface {
+	Send(*CoordinatorStateSnapshot) error
+	grpc.ServerStream
+}
+
+type oxiaAdminStreamCoordinatorStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *oxiaAdminStreamCoordinatorStateServer) Send(m *CoordinatorStateSnapshot) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OxiaAdmin_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ForceCloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceCloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ForceCloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ForceCloseSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ForceCloseSession(ctx, req.(*ForceCloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_SetKeyPrefixACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyPrefixACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).SetKeyPrefixACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/SetKeyPrefixACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).SetKeyPrefixACL(ctx, req.(*SetKeyPrefixACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_RemoveKeyPrefixACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveKeyPrefixACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).RemoveKeyPrefixACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/RemoveKeyPrefixACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).RemoveKeyPrefixACL(ctx, req.(*RemoveKeyPrefixACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaAdmin_ListKeyPrefixACLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeyPrefixACLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaAdminServer).ListKeyPrefixACLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.OxiaAdmin/ListKeyPrefixACLs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaAdminServer).ListKeyPrefixACLs(ctx, req.(*ListKeyPrefixACLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OxiaAdmin_ServiceDesc is the grpc.ServiceDesc for OxiaAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OxiaAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.OxiaAdmin",
+	HandlerType: (*OxiaAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _OxiaAdmin_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "DeleteNamespace",
+			Handler:    _OxiaAdmin_DeleteNamespace_Handler,
+		},
+		{
+			MethodName: "DecommissionServer",
+			Handler:    _OxiaAdmin_DecommissionServer_Handler,
+		},
+		{
+			MethodName: "RebalanceCluster",
+			Handler:    _OxiaAdmin_RebalanceCluster_Handler,
+		},
+		{
+			MethodName: "PlanRebalance",
+			Handler:    _OxiaAdmin_PlanRebalance_Handler,
+		},
+		{
+			MethodName: "ExecuteRebalancePlan",
+			Handler:    _OxiaAdmin_ExecuteRebalancePlan_Handler,
+		},
+		{
+			MethodName: "GetRebalancePlan",
+			Handler:    _OxiaAdmin_GetRebalancePlan_Handler,
+		},
+		{
+			MethodName: "SetServerDrain",
+			Handler:    _OxiaAdmin_SetServerDrain_Handler,
+		},
+		{
+			MethodName: "ReplaceServer",
+			Handler:    _OxiaAdmin_ReplaceServer_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _OxiaAdmin_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "ListShards",
+			Handler:    _OxiaAdmin_ListShards_Handler,
+		},
+		{
+			MethodName: "ListServers",
+			Handler:    _OxiaAdmin_ListServers_Handler,
+		},
+		{
+			MethodName: "ListOperations",
+			Handler:    _OxiaAdmin_ListOperations_Handler,
+		},
+		{
+			MethodName: "TransferLeader",
+			Handler:    _OxiaAdmin_TransferLeader_Handler,
+		},
+		{
+			MethodName: "MergeShard",
+			Handler:    _OxiaAdmin_MergeShard_Handler,
+		},
+		{
+			MethodName: "SetClusterFreeze",
+			Handler:    _OxiaAdmin_SetClusterFreeze_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _OxiaAdmin_ListEvents_Handler,
+		},
+		{
+			MethodName: "ExportClusterStatus",
+			Handler:    _OxiaAdmin_ExportClusterStatus_Handler,
+		},
+		{
+			MethodName: "ListAuditRecords",
+			Handler:    _OxiaAdmin_ListAuditRecords_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _OxiaAdmin_ListSessions_Handler,
+		},
+		{
+			MethodName: "ForceCloseSession",
+			Handler:    _OxiaAdmin_ForceCloseSession_Handler,
+		},
+		{
+			MethodName: "SetKeyPrefixACL",
+			Handler:    _OxiaAdmin_SetKeyPrefixACL_Handler,
+		},
+		{
+			MethodName: "RemoveKeyPrefixACL",
+			Handler:    _OxiaAdmin_RemoveKeyPrefixACL_Handler,
+		},
+		{
+			MethodName: "ListKeyPrefixACLs",
+			Handler:    _OxiaAdmin_ListKeyPrefixACLs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _OxiaAdmin_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamCoordinatorState",
+			Handler:       _OxiaAdmin_StreamCoordinatorState_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "admin.proto",
+}
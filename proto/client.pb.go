@@ -42,6 +42,11 @@ type ShardKeyRouter int32
 const (
 	ShardKeyRouter_UNKNOWN ShardKeyRouter = 0
 	ShardKeyRouter_XXHASH3 ShardKeyRouter = 1
+	// The keys are hashed with the 32 bits FNV-1a function
+	ShardKeyRouter_FNV1A_32 ShardKeyRouter = 2
+	// Each shard owns a contiguous range of keys, in the same order in which
+	// the keys are listed
+	ShardKeyRouter_KEY_RANGE ShardKeyRouter = 3
 )
 
 // Enum value maps for ShardKeyRouter.
@@ -49,10 +54,14 @@ var (
 	ShardKeyRouter_name = map[int32]string{
 		0: "UNKNOWN",
 		1: "XXHASH3",
+		2: "FNV1A_32",
+		3: "KEY_RANGE",
 	}
 	ShardKeyRouter_value = map[string]int32{
-		"UNKNOWN": 0,
-		"XXHASH3": 1,
+		"UNKNOWN":   0,
+		"XXHASH3":   1,
+		"FNV1A_32":  2,
+		"KEY_RANGE": 3,
 	}
 )
 
@@ -158,6 +167,11 @@ const (
 	Status_UNEXPECTED_VERSION_ID Status = 2
 	// The session that the put request referred to is not alive
 	Status_SESSION_DOES_NOT_EXIST Status = 3
+	// The existing value of the record is not a valid counter, or the
+	// increment would overflow it
+	Status_INVALID_COUNTER_VALUE Status = 4
+	// A key of the merged shard already exists in the shard it's merged into
+	Status_KEY_ALREADY_EXISTS Status = 5
 )
 
 // Enum value maps for Status.
@@ -167,12 +181,16 @@ var (
 		1: "KEY_NOT_FOUND",
 		2: "UNEXPECTED_VERSION_ID",
 		3: "SESSION_DOES_NOT_EXIST",
+		4: "INVALID_COUNTER_VALUE",
+		5: "KEY_ALREADY_EXISTS",
 	}
 	Status_value = map[string]int32{
 		"OK":                     0,
 		"KEY_NOT_FOUND":          1,
 		"UNEXPECTED_VERSION_ID":  2,
 		"SESSION_DOES_NOT_EXIST": 3,
+		"INVALID_COUNTER_VALUE":  4,
+		"KEY_ALREADY_EXISTS":     5,
 	}
 )
 
@@ -209,6 +227,9 @@ const (
 	NotificationType_KEY_CREATED  NotificationType = 0
 	NotificationType_KEY_MODIFIED NotificationType = 1
 	NotificationType_KEY_DELETED  NotificationType = 2
+	// A record deleted by a range deletion. Only sent to the subscribers that
+	// select it in the notification types, the others receive a KEY_DELETED.
+	NotificationType_KEY_RANGE_DELETED NotificationType = 3
 )
 
 // Enum value maps for NotificationType.
@@ -217,11 +238,13 @@ var (
 		0: "KEY_CREATED",
 		1: "KEY_MODIFIED",
 		2: "KEY_DELETED",
+		3: "KEY_RANGE_DELETED",
 	}
 	NotificationType_value = map[string]int32{
-		"KEY_CREATED":  0,
-		"KEY_MODIFIED": 1,
-		"KEY_DELETED":  2,
+		"KEY_CREATED":       0,
+		"KEY_MODIFIED":      1,
+		"KEY_DELETED":       2,
+		"KEY_RANGE_DELETED": 3,
 	}
 )
 
@@ -371,6 +394,9 @@ type NamespaceShardsAssignment struct {
 	// Indicates the mechanism by which the keys are assigned to the individual
 	// shards.
 	ShardKeyRouter ShardKeyRouter `protobuf:"varint,2,opt,name=shard_key_router,json=shardKeyRouter,proto3,enum=io.streamnative.oxia.proto.ShardKeyRouter" json:"shard_key_router,omitempty"`
+	// The policies that the shard leaders apply to the requests of the
+	// namespace. Not set when the namespace doesn't have any policy.
+	Policies *NamespacePolicies `protobuf:"bytes,3,opt,name=policies,proto3" json:"policies,omitempty"`
 }
 
 func (x *NamespaceShardsAssignment) Reset() {
@@ -419,27 +445,53 @@ func (x *NamespaceShardsAssignment) GetShardKeyRouter() ShardKeyRouter {
 	return ShardKeyRouter_UNKNOWN
 }
 
+func (x *NamespaceShardsAssignment) GetPolicies() *NamespacePolicies {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
 // *
-// The assignment of a shard to a server.
-type ShardAssignment struct {
+// The guardrails configured for a namespace. A zero value means that the
+// policy is not set.
+type NamespacePolicies struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The shard id
-	ShardId int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
-	// The shard leader, e.g. `host:port`
-	Leader string `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
-	// There could be multiple ways to describe the boundaries of a shard
-	//
-	// Types that are assignable to ShardBoundaries:
-	//
-	//	*ShardAssignment_Int32HashRange
-	ShardBoundaries isShardAssignment_ShardBoundaries `protobuf_oneof:"shard_boundaries"`
-}
-
-func (x *ShardAssignment) Reset() {
-	*x = ShardAssignment{}
+	// The time after which the records that are not modified anymore are
+	// deleted. The ephemeral records are not affected.
+	DefaultTtlMs uint64 `protobuf:"varint,1,opt,name=default_ttl_ms,json=defaultTtlMs,proto3" json:"default_ttl_ms,omitempty"`
+	// The max size of the values that can be stored
+	MaxValueSize uint64 `protobuf:"varint,2,opt,name=max_value_size,json=maxValueSize,proto3" json:"max_value_size,omitempty"`
+	// The max number of write operations per second in each shard
+	MaxWritesPerSecond float64 `protobuf:"fixed64,3,opt,name=max_writes_per_second,json=maxWritesPerSecond,proto3" json:"max_writes_per_second,omitempty"`
+	// The max number of read operations per second in each shard
+	MaxReadsPerSecond float64 `protobuf:"fixed64,4,opt,name=max_reads_per_second,json=maxReadsPerSecond,proto3" json:"max_reads_per_second,omitempty"`
+	// How long the notifications are kept, overriding the server default
+	NotificationsRetentionMs uint64 `protobuf:"varint,5,opt,name=notifications_retention_ms,json=notificationsRetentionMs,proto3" json:"notifications_retention_ms,omitempty"`
+	// The max number of operations per second of each client connection in
+	// each shard, reads and writes combined
+	MaxClientOpsPerSecond float64 `protobuf:"fixed64,6,opt,name=max_client_ops_per_second,json=maxClientOpsPerSecond,proto3" json:"max_client_ops_per_second,omitempty"`
+	// The max number of bytes written per second by each client connection
+	// in each shard, counting the keys and the values
+	MaxClientWriteBytesPerSecond float64 `protobuf:"fixed64,7,opt,name=max_client_write_bytes_per_second,json=maxClientWriteBytesPerSecond,proto3" json:"max_client_write_bytes_per_second,omitempty"`
+	// Reset the expiry of the records when they're read, so that the records
+	// are only deleted after not being modified nor read for the default TTL
+	SlidingTtl bool `protobuf:"varint,8,opt,name=sliding_ttl,json=slidingTtl,proto3" json:"sliding_ttl,omitempty"`
+	// The permissions of the principals on the namespace. Not set when the
+	// access to the namespace is not restricted.
+	Authorization *NamespaceAuthorization `protobuf:"bytes,9,opt,name=authorization,proto3,oneof" json:"authorization,omitempty"`
+	// The quotas of the authenticated principals in each shard, by principal.
+	// The quota of the `*` principal applies to the ones without their own.
+	PrincipalQuotas map[string]*PrincipalQuota `protobuf:"bytes,10,rep,name=principal_quotas,json=principalQuotas,proto3" json:"principal_quotas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The tenant of the namespace, whose limits apply to all its namespaces
+	Tenant *TenantPolicies `protobuf:"bytes,11,opt,name=tenant,proto3,oneof" json:"tenant,omitempty"`
+}
+
+func (x *NamespacePolicies) Reset() {
+	*x = NamespacePolicies{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -447,13 +499,13 @@ func (x *ShardAssignment) Reset() {
 	}
 }
 
-func (x *ShardAssignment) String() string {
+func (x *NamespacePolicies) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShardAssignment) ProtoMessage() {}
+func (*NamespacePolicies) ProtoMessage() {}
 
-func (x *ShardAssignment) ProtoReflect() protoreflect.Message {
+func (x *NamespacePolicies) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -465,64 +517,106 @@ func (x *ShardAssignment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShardAssignment.ProtoReflect.Descriptor instead.
-func (*ShardAssignment) Descriptor() ([]byte, []int) {
+// Deprecated: Use NamespacePolicies.ProtoReflect.Descriptor instead.
+func (*NamespacePolicies) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ShardAssignment) GetShardId() int64 {
+func (x *NamespacePolicies) GetDefaultTtlMs() uint64 {
 	if x != nil {
-		return x.ShardId
+		return x.DefaultTtlMs
 	}
 	return 0
 }
 
-func (x *ShardAssignment) GetLeader() string {
+func (x *NamespacePolicies) GetMaxValueSize() uint64 {
 	if x != nil {
-		return x.Leader
+		return x.MaxValueSize
 	}
-	return ""
+	return 0
 }
 
-func (m *ShardAssignment) GetShardBoundaries() isShardAssignment_ShardBoundaries {
-	if m != nil {
-		return m.ShardBoundaries
+func (x *NamespacePolicies) GetMaxWritesPerSecond() float64 {
+	if x != nil {
+		return x.MaxWritesPerSecond
 	}
-	return nil
+	return 0
 }
 
-func (x *ShardAssignment) GetInt32HashRange() *Int32HashRange {
-	if x, ok := x.GetShardBoundaries().(*ShardAssignment_Int32HashRange); ok {
-		return x.Int32HashRange
+func (x *NamespacePolicies) GetMaxReadsPerSecond() float64 {
+	if x != nil {
+		return x.MaxReadsPerSecond
 	}
-	return nil
+	return 0
 }
 
-type isShardAssignment_ShardBoundaries interface {
-	isShardAssignment_ShardBoundaries()
+func (x *NamespacePolicies) GetNotificationsRetentionMs() uint64 {
+	if x != nil {
+		return x.NotificationsRetentionMs
+	}
+	return 0
 }
 
-type ShardAssignment_Int32HashRange struct {
-	Int32HashRange *Int32HashRange `protobuf:"bytes,3,opt,name=int32_hash_range,json=int32HashRange,proto3,oneof"`
+func (x *NamespacePolicies) GetMaxClientOpsPerSecond() float64 {
+	if x != nil {
+		return x.MaxClientOpsPerSecond
+	}
+	return 0
 }
 
-func (*ShardAssignment_Int32HashRange) isShardAssignment_ShardBoundaries() {}
+func (x *NamespacePolicies) GetMaxClientWriteBytesPerSecond() float64 {
+	if x != nil {
+		return x.MaxClientWriteBytesPerSecond
+	}
+	return 0
+}
+
+func (x *NamespacePolicies) GetSlidingTtl() bool {
+	if x != nil {
+		return x.SlidingTtl
+	}
+	return false
+}
+
+func (x *NamespacePolicies) GetAuthorization() *NamespaceAuthorization {
+	if x != nil {
+		return x.Authorization
+	}
+	return nil
+}
+
+func (x *NamespacePolicies) GetPrincipalQuotas() map[string]*PrincipalQuota {
+	if x != nil {
+		return x.PrincipalQuotas
+	}
+	return nil
+}
+
+func (x *NamespacePolicies) GetTenant() *TenantPolicies {
+	if x != nil {
+		return x.Tenant
+	}
+	return nil
+}
 
 // *
-// Represents a range of hash values [min, max)
-type Int32HashRange struct {
+// The limits of a tenant, shared by all the shards of its namespaces that
+// are led by the same server. A zero value means no limit.
+type TenantPolicies struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The minimum inclusive hash that the shard can contain
-	MinHashInclusive uint32 `protobuf:"fixed32,1,opt,name=min_hash_inclusive,json=minHashInclusive,proto3" json:"min_hash_inclusive,omitempty"`
-	// The maximum inclusive hash that the shard can contain
-	MaxHashInclusive uint32 `protobuf:"fixed32,2,opt,name=max_hash_inclusive,json=maxHashInclusive,proto3" json:"max_hash_inclusive,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The max number of operations per second, reads and writes combined
+	MaxOpsPerSecond float64 `protobuf:"fixed64,2,opt,name=max_ops_per_second,json=maxOpsPerSecond,proto3" json:"max_ops_per_second,omitempty"`
+	// The max number of bytes written per second, counting the keys and the
+	// values
+	MaxWriteBytesPerSecond float64 `protobuf:"fixed64,3,opt,name=max_write_bytes_per_second,json=maxWriteBytesPerSecond,proto3" json:"max_write_bytes_per_second,omitempty"`
 }
 
-func (x *Int32HashRange) Reset() {
-	*x = Int32HashRange{}
+func (x *TenantPolicies) Reset() {
+	*x = TenantPolicies{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -530,13 +624,13 @@ func (x *Int32HashRange) Reset() {
 	}
 }
 
-func (x *Int32HashRange) String() string {
+func (x *TenantPolicies) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Int32HashRange) ProtoMessage() {}
+func (*TenantPolicies) ProtoMessage() {}
 
-func (x *Int32HashRange) ProtoReflect() protoreflect.Message {
+func (x *TenantPolicies) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -548,47 +642,52 @@ func (x *Int32HashRange) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Int32HashRange.ProtoReflect.Descriptor instead.
-func (*Int32HashRange) Descriptor() ([]byte, []int) {
+// Deprecated: Use TenantPolicies.ProtoReflect.Descriptor instead.
+func (*TenantPolicies) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Int32HashRange) GetMinHashInclusive() uint32 {
+func (x *TenantPolicies) GetName() string {
 	if x != nil {
-		return x.MinHashInclusive
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TenantPolicies) GetMaxOpsPerSecond() float64 {
+	if x != nil {
+		return x.MaxOpsPerSecond
 	}
 	return 0
 }
 
-func (x *Int32HashRange) GetMaxHashInclusive() uint32 {
+func (x *TenantPolicies) GetMaxWriteBytesPerSecond() float64 {
 	if x != nil {
-		return x.MaxHashInclusive
+		return x.MaxWriteBytesPerSecond
 	}
 	return 0
 }
 
 // *
-// A batch write request. Applies the batches of requests. Requests are
-// processed in positional order within batches and the batch types are
-// processed in the following order: puts, deletes, delete_ranges.
-type WriteRequest struct {
+// The limits of a principal in each shard of a namespace. A zero value
+// means that the limit is not set.
+type PrincipalQuota struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The shard id. This is optional allow for support for server-side hashing
-	// and proxying in the future.
-	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
-	// The put requests
-	Puts []*PutRequest `protobuf:"bytes,2,rep,name=puts,proto3" json:"puts,omitempty"`
-	// The delete requests
-	Deletes []*DeleteRequest `protobuf:"bytes,3,rep,name=deletes,proto3" json:"deletes,omitempty"`
-	// The delete range requests
-	DeleteRanges []*DeleteRangeRequest `protobuf:"bytes,4,rep,name=delete_ranges,json=deleteRanges,proto3" json:"delete_ranges,omitempty"`
+	// The max number of operations per second, reads and writes combined
+	OpsPerSecond float64 `protobuf:"fixed64,1,opt,name=ops_per_second,json=opsPerSecond,proto3" json:"ops_per_second,omitempty"`
+	// The max number of bytes written per second, counting the keys and the
+	// values
+	WriteBytesPerSecond float64 `protobuf:"fixed64,2,opt,name=write_bytes_per_second,json=writeBytesPerSecond,proto3" json:"write_bytes_per_second,omitempty"`
+	// The max size of the records written by the principal, counting the keys
+	// and the values
+	MaxStorageBytes uint64 `protobuf:"varint,3,opt,name=max_storage_bytes,json=maxStorageBytes,proto3" json:"max_storage_bytes,omitempty"`
 }
 
-func (x *WriteRequest) Reset() {
-	*x = WriteRequest{}
+func (x *PrincipalQuota) Reset() {
+	*x = PrincipalQuota{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -596,13 +695,13 @@ func (x *WriteRequest) Reset() {
 	}
 }
 
-func (x *WriteRequest) String() string {
+func (x *PrincipalQuota) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteRequest) ProtoMessage() {}
+func (*PrincipalQuota) ProtoMessage() {}
 
-func (x *WriteRequest) ProtoReflect() protoreflect.Message {
+func (x *PrincipalQuota) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -614,57 +713,52 @@ func (x *WriteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WriteRequest.ProtoReflect.Descriptor instead.
-func (*WriteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrincipalQuota.ProtoReflect.Descriptor instead.
+func (*PrincipalQuota) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *WriteRequest) GetShardId() int64 {
-	if x != nil && x.ShardId != nil {
-		return *x.ShardId
-	}
-	return 0
-}
-
-func (x *WriteRequest) GetPuts() []*PutRequest {
+func (x *PrincipalQuota) GetOpsPerSecond() float64 {
 	if x != nil {
-		return x.Puts
+		return x.OpsPerSecond
 	}
-	return nil
+	return 0
 }
 
-func (x *WriteRequest) GetDeletes() []*DeleteRequest {
+func (x *PrincipalQuota) GetWriteBytesPerSecond() float64 {
 	if x != nil {
-		return x.Deletes
+		return x.WriteBytesPerSecond
 	}
-	return nil
+	return 0
 }
 
-func (x *WriteRequest) GetDeleteRanges() []*DeleteRangeRequest {
+func (x *PrincipalQuota) GetMaxStorageBytes() uint64 {
 	if x != nil {
-		return x.DeleteRanges
+		return x.MaxStorageBytes
 	}
-	return nil
+	return 0
 }
 
 // *
-// The response to a batch write request. Responses of each type respect the
-// order of the original requests.
-type WriteResponse struct {
+// The principals allowed to access a namespace. The requests of the other
+// principals, and the requests that are not authenticated, are rejected.
+type NamespaceAuthorization struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The put responses
-	Puts []*PutResponse `protobuf:"bytes,1,rep,name=puts,proto3" json:"puts,omitempty"`
-	// The delete responses
-	Deletes []*DeleteResponse `protobuf:"bytes,2,rep,name=deletes,proto3" json:"deletes,omitempty"`
-	// The delete range responses
-	DeleteRanges []*DeleteRangeResponse `protobuf:"bytes,3,rep,name=delete_ranges,json=deleteRanges,proto3" json:"delete_ranges,omitempty"`
+	// The permissions granted to each principal on all the keys
+	Principals map[string]*NamespacePermissions `protobuf:"bytes,1,rep,name=principals,proto3" json:"principals,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The permissions granted to the principals on the keys starting with a
+	// prefix, in addition to the ones on all the keys
+	KeyPrefixes []*KeyPrefixPermissions `protobuf:"bytes,2,rep,name=key_prefixes,json=keyPrefixes,proto3" json:"key_prefixes,omitempty"`
+	// The permissions granted on all the keys to the principals matching a
+	// pattern, eg: `spiffe://example.org/ns/team-a/*`
+	PrincipalPatterns []*PrincipalPatternPermissions `protobuf:"bytes,3,rep,name=principal_patterns,json=principalPatterns,proto3" json:"principal_patterns,omitempty"`
 }
 
-func (x *WriteResponse) Reset() {
-	*x = WriteResponse{}
+func (x *NamespaceAuthorization) Reset() {
+	*x = NamespaceAuthorization{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -672,13 +766,13 @@ func (x *WriteResponse) Reset() {
 	}
 }
 
-func (x *WriteResponse) String() string {
+func (x *NamespaceAuthorization) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteResponse) ProtoMessage() {}
+func (*NamespaceAuthorization) ProtoMessage() {}
 
-func (x *WriteResponse) ProtoReflect() protoreflect.Message {
+func (x *NamespaceAuthorization) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -690,48 +784,43 @@ func (x *WriteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WriteResponse.ProtoReflect.Descriptor instead.
-func (*WriteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use NamespaceAuthorization.ProtoReflect.Descriptor instead.
+func (*NamespaceAuthorization) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *WriteResponse) GetPuts() []*PutResponse {
+func (x *NamespaceAuthorization) GetPrincipals() map[string]*NamespacePermissions {
 	if x != nil {
-		return x.Puts
+		return x.Principals
 	}
 	return nil
 }
 
-func (x *WriteResponse) GetDeletes() []*DeleteResponse {
+func (x *NamespaceAuthorization) GetKeyPrefixes() []*KeyPrefixPermissions {
 	if x != nil {
-		return x.Deletes
+		return x.KeyPrefixes
 	}
 	return nil
 }
 
-func (x *WriteResponse) GetDeleteRanges() []*DeleteRangeResponse {
+func (x *NamespaceAuthorization) GetPrincipalPatterns() []*PrincipalPatternPermissions {
 	if x != nil {
-		return x.DeleteRanges
+		return x.PrincipalPatterns
 	}
 	return nil
 }
 
-// *
-// A batch read request. Applies the batches of requests.
-type ReadRequest struct {
+type PrincipalPatternPermissions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The shard id. This is optional allow for support for server-side hashing
-	// and proxying in the future.
-	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
-	// The get requests
-	Gets []*GetRequest `protobuf:"bytes,2,rep,name=gets,proto3" json:"gets,omitempty"`
+	Pattern     string                `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Permissions *NamespacePermissions `protobuf:"bytes,2,opt,name=permissions,proto3" json:"permissions,omitempty"`
 }
 
-func (x *ReadRequest) Reset() {
-	*x = ReadRequest{}
+func (x *PrincipalPatternPermissions) Reset() {
+	*x = PrincipalPatternPermissions{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -739,13 +828,13 @@ func (x *ReadRequest) Reset() {
 	}
 }
 
-func (x *ReadRequest) String() string {
+func (x *PrincipalPatternPermissions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadRequest) ProtoMessage() {}
+func (*PrincipalPatternPermissions) ProtoMessage() {}
 
-func (x *ReadRequest) ProtoReflect() protoreflect.Message {
+func (x *PrincipalPatternPermissions) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -757,39 +846,37 @@ func (x *ReadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
-func (*ReadRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrincipalPatternPermissions.ProtoReflect.Descriptor instead.
+func (*PrincipalPatternPermissions) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ReadRequest) GetShardId() int64 {
-	if x != nil && x.ShardId != nil {
-		return *x.ShardId
+func (x *PrincipalPatternPermissions) GetPattern() string {
+	if x != nil {
+		return x.Pattern
 	}
-	return 0
+	return ""
 }
 
-func (x *ReadRequest) GetGets() []*GetRequest {
+func (x *PrincipalPatternPermissions) GetPermissions() *NamespacePermissions {
 	if x != nil {
-		return x.Gets
+		return x.Permissions
 	}
 	return nil
 }
 
-// *
-// The response to a batch read request. Responses of each type respect the
-// order of the original requests.
-type ReadResponse struct {
+type KeyPrefixPermissions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The get responses
-	Gets []*GetResponse `protobuf:"bytes,1,rep,name=gets,proto3" json:"gets,omitempty"`
+	Principal   string                `protobuf:"bytes,1,opt,name=principal,proto3" json:"principal,omitempty"`
+	Prefix      string                `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Permissions *NamespacePermissions `protobuf:"bytes,3,opt,name=permissions,proto3" json:"permissions,omitempty"`
 }
 
-func (x *ReadResponse) Reset() {
-	*x = ReadResponse{}
+func (x *KeyPrefixPermissions) Reset() {
+	*x = KeyPrefixPermissions{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -797,13 +884,13 @@ func (x *ReadResponse) Reset() {
 	}
 }
 
-func (x *ReadResponse) String() string {
+func (x *KeyPrefixPermissions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadResponse) ProtoMessage() {}
+func (*KeyPrefixPermissions) ProtoMessage() {}
 
-func (x *ReadResponse) ProtoReflect() protoreflect.Message {
+func (x *KeyPrefixPermissions) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -815,50 +902,47 @@ func (x *ReadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
-func (*ReadResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyPrefixPermissions.ProtoReflect.Descriptor instead.
+func (*KeyPrefixPermissions) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ReadResponse) GetGets() []*GetResponse {
+func (x *KeyPrefixPermissions) GetPrincipal() string {
 	if x != nil {
-		return x.Gets
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *KeyPrefixPermissions) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *KeyPrefixPermissions) GetPermissions() *NamespacePermissions {
+	if x != nil {
+		return x.Permissions
 	}
 	return nil
 }
 
-// *
-// A put request. Persists the specified key and value
-type PutRequest struct {
+type NamespacePermissions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The key
-	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	// The value
-	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	// An optional expected version_id. The put will fail if the server's current version_id
-	// does not match
-	ExpectedVersionId *int64 `protobuf:"varint,3,opt,name=expected_version_id,json=expectedVersionId,proto3,oneof" json:"expected_version_id,omitempty"`
-	// Optional. Associate the new record with the session (i.e. ephemeral record).
-	// When the session expires or is explicitly closed, the record will be automatically
-	// removed
-	SessionId *int64 `protobuf:"varint,4,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
-	// Client identifier used to track the client that last modified an
-	// ephemeral record.
-	ClientIdentity *string `protobuf:"bytes,5,opt,name=client_identity,json=clientIdentity,proto3,oneof" json:"client_identity,omitempty"`
-	// If a partition key is present, it supersedes the regular record key in determining the routing of
-	// a record to a particular shard. It is passed to the server because it needs to be persisted as
-	// part of the record. We would need the partition_key if we're going to do a split of the shards.
-	PartitionKey *string `protobuf:"bytes,6,opt,name=partition_key,json=partitionKey,proto3,oneof" json:"partition_key,omitempty"`
-	// If one or more sequence key are specified. The key will get added suffixes
-	// based on adding the delta to the current highest key with the same prefix
-	SequenceKeyDelta []uint64 `protobuf:"varint,7,rep,packed,name=sequence_key_delta,json=sequenceKeyDelta,proto3" json:"sequence_key_delta,omitempty"`
+	// Read the records and get the notifications
+	Read bool `protobuf:"varint,1,opt,name=read,proto3" json:"read,omitempty"`
+	// Write the records and manage the sessions
+	Write bool `protobuf:"varint,2,opt,name=write,proto3" json:"write,omitempty"`
+	// All the permissions on the namespace
+	Admin bool `protobuf:"varint,3,opt,name=admin,proto3" json:"admin,omitempty"`
 }
 
-func (x *PutRequest) Reset() {
-	*x = PutRequest{}
+func (x *NamespacePermissions) Reset() {
+	*x = NamespacePermissions{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -866,13 +950,13 @@ func (x *PutRequest) Reset() {
 	}
 }
 
-func (x *PutRequest) String() string {
+func (x *NamespacePermissions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutRequest) ProtoMessage() {}
+func (*NamespacePermissions) ProtoMessage() {}
 
-func (x *PutRequest) ProtoReflect() protoreflect.Message {
+func (x *NamespacePermissions) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -884,78 +968,54 @@ func (x *PutRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutRequest.ProtoReflect.Descriptor instead.
-func (*PutRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NamespacePermissions.ProtoReflect.Descriptor instead.
+func (*NamespacePermissions) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PutRequest) GetKey() string {
+func (x *NamespacePermissions) GetRead() bool {
 	if x != nil {
-		return x.Key
+		return x.Read
 	}
-	return ""
+	return false
 }
 
-func (x *PutRequest) GetValue() []byte {
+func (x *NamespacePermissions) GetWrite() bool {
 	if x != nil {
-		return x.Value
+		return x.Write
 	}
-	return nil
+	return false
 }
 
-func (x *PutRequest) GetExpectedVersionId() int64 {
-	if x != nil && x.ExpectedVersionId != nil {
-		return *x.ExpectedVersionId
+func (x *NamespacePermissions) GetAdmin() bool {
+	if x != nil {
+		return x.Admin
 	}
-	return 0
-}
-
-func (x *PutRequest) GetSessionId() int64 {
-	if x != nil && x.SessionId != nil {
-		return *x.SessionId
-	}
-	return 0
-}
-
-func (x *PutRequest) GetClientIdentity() string {
-	if x != nil && x.ClientIdentity != nil {
-		return *x.ClientIdentity
-	}
-	return ""
-}
-
-func (x *PutRequest) GetPartitionKey() string {
-	if x != nil && x.PartitionKey != nil {
-		return *x.PartitionKey
-	}
-	return ""
-}
-
-func (x *PutRequest) GetSequenceKeyDelta() []uint64 {
-	if x != nil {
-		return x.SequenceKeyDelta
-	}
-	return nil
+	return false
 }
 
 // *
-// The response to a put request.
-type PutResponse struct {
+// The assignment of a shard to a server.
+type ShardAssignment struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Includes the error or OK
-	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
-	// The version if the put was successful
-	Version *Version `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	// If the key was generated by Oxia, it will be returned as part
-	// of the response
-	Key *string `protobuf:"bytes,3,opt,name=key,proto3,oneof" json:"key,omitempty"`
+	// The shard id
+	ShardId int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	// The shard leader, e.g. `host:port`
+	Leader string `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	// There could be multiple ways to describe the boundaries of a shard
+	//
+	// Types that are assignable to ShardBoundaries:
+	//
+	//	*ShardAssignment_Int32HashRange
+	//	*ShardAssignment_KeyRange
+	ShardBoundaries isShardAssignment_ShardBoundaries `protobuf_oneof:"shard_boundaries"`
 }
 
-func (x *PutResponse) Reset() {
-	*x = PutResponse{}
+func (x *ShardAssignment) Reset() {
+	*x = ShardAssignment{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -963,13 +1023,13 @@ func (x *PutResponse) Reset() {
 	}
 }
 
-func (x *PutResponse) String() string {
+func (x *ShardAssignment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutResponse) ProtoMessage() {}
+func (*ShardAssignment) ProtoMessage() {}
 
-func (x *PutResponse) ProtoReflect() protoreflect.Message {
+func (x *ShardAssignment) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -981,48 +1041,77 @@ func (x *PutResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
-func (*PutResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ShardAssignment.ProtoReflect.Descriptor instead.
+func (*ShardAssignment) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *PutResponse) GetStatus() Status {
+func (x *ShardAssignment) GetShardId() int64 {
 	if x != nil {
-		return x.Status
+		return x.ShardId
 	}
-	return Status_OK
+	return 0
 }
 
-func (x *PutResponse) GetVersion() *Version {
+func (x *ShardAssignment) GetLeader() string {
 	if x != nil {
-		return x.Version
+		return x.Leader
+	}
+	return ""
+}
+
+func (m *ShardAssignment) GetShardBoundaries() isShardAssignment_ShardBoundaries {
+	if m != nil {
+		return m.ShardBoundaries
 	}
 	return nil
 }
 
-func (x *PutResponse) GetKey() string {
-	if x != nil && x.Key != nil {
-		return *x.Key
+func (x *ShardAssignment) GetInt32HashRange() *Int32HashRange {
+	if x, ok := x.GetShardBoundaries().(*ShardAssignment_Int32HashRange); ok {
+		return x.Int32HashRange
 	}
-	return ""
+	return nil
+}
+
+func (x *ShardAssignment) GetKeyRange() *KeyRange {
+	if x, ok := x.GetShardBoundaries().(*ShardAssignment_KeyRange); ok {
+		return x.KeyRange
+	}
+	return nil
+}
+
+type isShardAssignment_ShardBoundaries interface {
+	isShardAssignment_ShardBoundaries()
+}
+
+type ShardAssignment_Int32HashRange struct {
+	Int32HashRange *Int32HashRange `protobuf:"bytes,3,opt,name=int32_hash_range,json=int32HashRange,proto3,oneof"`
+}
+
+type ShardAssignment_KeyRange struct {
+	KeyRange *KeyRange `protobuf:"bytes,4,opt,name=key_range,json=keyRange,proto3,oneof"`
 }
 
+func (*ShardAssignment_Int32HashRange) isShardAssignment_ShardBoundaries() {}
+
+func (*ShardAssignment_KeyRange) isShardAssignment_ShardBoundaries() {}
+
 // *
-// A delete request. Deletes the specified key.
-type DeleteRequest struct {
+// Represents a range of hash values [min, max)
+type Int32HashRange struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The key
-	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	// An optional expected version_id. The delete will fail if the server's current version_id
-	// does not match
-	ExpectedVersionId *int64 `protobuf:"varint,2,opt,name=expected_version_id,json=expectedVersionId,proto3,oneof" json:"expected_version_id,omitempty"`
+	// The minimum inclusive hash that the shard can contain
+	MinHashInclusive uint32 `protobuf:"fixed32,1,opt,name=min_hash_inclusive,json=minHashInclusive,proto3" json:"min_hash_inclusive,omitempty"`
+	// The maximum inclusive hash that the shard can contain
+	MaxHashInclusive uint32 `protobuf:"fixed32,2,opt,name=max_hash_inclusive,json=maxHashInclusive,proto3" json:"max_hash_inclusive,omitempty"`
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *Int32HashRange) Reset() {
+	*x = Int32HashRange{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1030,13 +1119,13 @@ func (x *DeleteRequest) Reset() {
 	}
 }
 
-func (x *DeleteRequest) String() string {
+func (x *Int32HashRange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*Int32HashRange) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+func (x *Int32HashRange) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1048,39 +1137,41 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Int32HashRange.ProtoReflect.Descriptor instead.
+func (*Int32HashRange) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *DeleteRequest) GetKey() string {
+func (x *Int32HashRange) GetMinHashInclusive() uint32 {
 	if x != nil {
-		return x.Key
+		return x.MinHashInclusive
 	}
-	return ""
+	return 0
 }
 
-func (x *DeleteRequest) GetExpectedVersionId() int64 {
-	if x != nil && x.ExpectedVersionId != nil {
-		return *x.ExpectedVersionId
+func (x *Int32HashRange) GetMaxHashInclusive() uint32 {
+	if x != nil {
+		return x.MaxHashInclusive
 	}
 	return 0
 }
 
 // *
-// The response to a delete request or an item in a response to the
-// delete range request.
-type DeleteResponse struct {
+// Represents a range of keys [min, max), in the order in which the keys
+// are listed
+type KeyRange struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Includes the error or OK
-	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+	// The minimum inclusive key, empty for the first shard
+	MinKeyInclusive string `protobuf:"bytes,1,opt,name=min_key_inclusive,json=minKeyInclusive,proto3" json:"min_key_inclusive,omitempty"`
+	// The maximum exclusive key, empty for the last shard
+	MaxKeyExclusive string `protobuf:"bytes,2,opt,name=max_key_exclusive,json=maxKeyExclusive,proto3" json:"max_key_exclusive,omitempty"`
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
+func (x *KeyRange) Reset() {
+	*x = KeyRange{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1088,13 +1179,13 @@ func (x *DeleteResponse) Reset() {
 	}
 }
 
-func (x *DeleteResponse) String() string {
+func (x *KeyRange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*KeyRange) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+func (x *KeyRange) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1106,35 +1197,55 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyRange.ProtoReflect.Descriptor instead.
+func (*KeyRange) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *DeleteResponse) GetStatus() Status {
+func (x *KeyRange) GetMinKeyInclusive() string {
 	if x != nil {
-		return x.Status
+		return x.MinKeyInclusive
 	}
-	return Status_OK
+	return ""
+}
+
+func (x *KeyRange) GetMaxKeyExclusive() string {
+	if x != nil {
+		return x.MaxKeyExclusive
+	}
+	return ""
 }
 
 // *
-// A get request. Gets the stat and optionally the value for the specified
-// key.
-type GetRequest struct {
+// A batch write request. Applies the batches of requests. Requests are
+// processed in positional order within batches and the batch types are
+// processed in the following order: puts, deletes, delete_ranges.
+type WriteRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The key
-	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	// Specifies whether the response should include the value
-	IncludeValue   bool              `protobuf:"varint,2,opt,name=include_value,json=includeValue,proto3" json:"include_value,omitempty"`
-	ComparisonType KeyComparisonType `protobuf:"varint,3,opt,name=comparison_type,json=comparisonType,proto3,enum=io.streamnative.oxia.proto.KeyComparisonType" json:"comparison_type,omitempty"`
+	// The shard id. This is optional allow for support for server-side hashing
+	// and proxying in the future.
+	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
+	// The put requests
+	Puts []*PutRequest `protobuf:"bytes,2,rep,name=puts,proto3" json:"puts,omitempty"`
+	// The delete requests
+	Deletes []*DeleteRequest `protobuf:"bytes,3,rep,name=deletes,proto3" json:"deletes,omitempty"`
+	// The delete range requests
+	DeleteRanges []*DeleteRangeRequest `protobuf:"bytes,4,rep,name=delete_ranges,json=deleteRanges,proto3" json:"delete_ranges,omitempty"`
+	// The authenticated principal of the client, set by the leader before the
+	// request is replicated, to account the storage used by each principal.
+	// The value set by the clients is ignored.
+	Principal *string `protobuf:"bytes,5,opt,name=principal,proto3,oneof" json:"principal,omitempty"`
+	// The shard whose staged records are moved into the key space, to commit
+	// its merge into this shard. Set by the leader, on the request of the
+	// coordinator. The value set by the clients is ignored.
+	MergedShardId *int64 `protobuf:"varint,6,opt,name=merged_shard_id,json=mergedShardId,proto3,oneof" json:"merged_shard_id,omitempty"`
 }
 
-func (x *GetRequest) Reset() {
-	*x = GetRequest{}
+func (x *WriteRequest) Reset() {
+	*x = WriteRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1142,13 +1253,13 @@ func (x *GetRequest) Reset() {
 	}
 }
 
-func (x *GetRequest) String() string {
+func (x *WriteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRequest) ProtoMessage() {}
+func (*WriteRequest) ProtoMessage() {}
 
-func (x *GetRequest) ProtoReflect() protoreflect.Message {
+func (x *WriteRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1160,52 +1271,74 @@ func (x *GetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
-func (*GetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WriteRequest.ProtoReflect.Descriptor instead.
+func (*WriteRequest) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetRequest) GetKey() string {
+func (x *WriteRequest) GetShardId() int64 {
+	if x != nil && x.ShardId != nil {
+		return *x.ShardId
+	}
+	return 0
+}
+
+func (x *WriteRequest) GetPuts() []*PutRequest {
 	if x != nil {
-		return x.Key
+		return x.Puts
 	}
-	return ""
+	return nil
 }
 
-func (x *GetRequest) GetIncludeValue() bool {
+func (x *WriteRequest) GetDeletes() []*DeleteRequest {
 	if x != nil {
-		return x.IncludeValue
+		return x.Deletes
 	}
-	return false
+	return nil
 }
 
-func (x *GetRequest) GetComparisonType() KeyComparisonType {
+func (x *WriteRequest) GetDeleteRanges() []*DeleteRangeRequest {
 	if x != nil {
-		return x.ComparisonType
+		return x.DeleteRanges
 	}
-	return KeyComparisonType_EQUAL
+	return nil
+}
+
+func (x *WriteRequest) GetPrincipal() string {
+	if x != nil && x.Principal != nil {
+		return *x.Principal
+	}
+	return ""
+}
+
+func (x *WriteRequest) GetMergedShardId() int64 {
+	if x != nil && x.MergedShardId != nil {
+		return *x.MergedShardId
+	}
+	return 0
 }
 
 // *
-// The response to a get request.
-type GetResponse struct {
+// The response to a batch write request. Responses of each type respect the
+// order of the original requests.
+type WriteResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Includes the error or OK
-	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
-	// The version of the record
-	Version *Version `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	// The value, if it was requested and there was no error
-	Value []byte `protobuf:"bytes,3,opt,name=value,proto3,oneof" json:"value,omitempty"`
-	// In case of non-exact queries (eg. floor, ceiling) the found key will be
-	// returned in the GetResponse.
-	Key *string `protobuf:"bytes,4,opt,name=key,proto3,oneof" json:"key,omitempty"`
+	// The put responses
+	Puts []*PutResponse `protobuf:"bytes,1,rep,name=puts,proto3" json:"puts,omitempty"`
+	// The delete responses
+	Deletes []*DeleteResponse `protobuf:"bytes,2,rep,name=deletes,proto3" json:"deletes,omitempty"`
+	// The delete range responses
+	DeleteRanges []*DeleteRangeResponse `protobuf:"bytes,3,rep,name=delete_ranges,json=deleteRanges,proto3" json:"delete_ranges,omitempty"`
+	// The result of the merge of a shard, when the request commits it. Nothing
+	// is merged if it's not OK.
+	MergeStatus *Status `protobuf:"varint,4,opt,name=merge_status,json=mergeStatus,proto3,enum=io.streamnative.oxia.proto.Status,oneof" json:"merge_status,omitempty"`
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
+func (x *WriteResponse) Reset() {
+	*x = WriteResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_client_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1213,13 +1346,13 @@ func (x *GetResponse) Reset() {
 	}
 }
 
-func (x *GetResponse) String() string {
+func (x *WriteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*WriteResponse) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
+func (x *WriteResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_client_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1231,69 +1364,1076 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WriteResponse.ProtoReflect.Descriptor instead.
+func (*WriteResponse) Descriptor() ([]byte, []int) {
 	return file_client_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetResponse) GetStatus() Status {
+func (x *WriteResponse) GetPuts() []*PutResponse {
 	if x != nil {
-		return x.Status
+		return x.Puts
+	}
+	return nil
+}
+
+func (x *WriteResponse) GetDeletes() []*DeleteResponse {
+	if x != nil {
+		return x.Deletes
+	}
+	return nil
+}
+
+func (x *WriteResponse) GetDeleteRanges() []*DeleteRangeResponse {
+	if x != nil {
+		return x.DeleteRanges
+	}
+	return nil
+}
+
+func (x *WriteResponse) GetMergeStatus() Status {
+	if x != nil && x.MergeStatus != nil {
+		return *x.MergeStatus
 	}
 	return Status_OK
 }
 
-func (x *GetResponse) GetVersion() *Version {
+// *
+// A batch read request. Applies the batches of requests.
+type ReadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The shard id. This is optional allow for support for server-side hashing
+	// and proxying in the future.
+	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
+	// The get requests
+	Gets []*GetRequest `protobuf:"bytes,2,rep,name=gets,proto3" json:"gets,omitempty"`
+}
+
+func (x *ReadRequest) Reset() {
+	*x = ReadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadRequest) ProtoMessage() {}
+
+func (x *ReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadRequest) GetShardId() int64 {
+	if x != nil && x.ShardId != nil {
+		return *x.ShardId
+	}
+	return 0
+}
+
+func (x *ReadRequest) GetGets() []*GetRequest {
 	if x != nil {
-		return x.Version
+		return x.Gets
 	}
 	return nil
 }
 
-func (x *GetResponse) GetValue() []byte {
+// *
+// The response to a batch read request. Responses of each type respect the
+// order of the original requests.
+type ReadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The get responses
+	Gets []*GetResponse `protobuf:"bytes,1,rep,name=gets,proto3" json:"gets,omitempty"`
+}
+
+func (x *ReadResponse) Reset() {
+	*x = ReadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResponse) ProtoMessage() {}
+
+func (x *ReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReadResponse) GetGets() []*GetResponse {
+	if x != nil {
+		return x.Gets
+	}
+	return nil
+}
+
+// *
+// A put request. Persists the specified key and value
+type PutRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// The value
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// An optional expected version_id. The put will fail if the server's current version_id
+	// does not match
+	ExpectedVersionId *int64 `protobuf:"varint,3,opt,name=expected_version_id,json=expectedVersionId,proto3,oneof" json:"expected_version_id,omitempty"`
+	// Optional. Associate the new record with the session (i.e. ephemeral record).
+	// When the session expires or is explicitly closed, the record will be automatically
+	// removed
+	SessionId *int64 `protobuf:"varint,4,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
+	// Client identifier used to track the client that last modified an
+	// ephemeral record.
+	ClientIdentity *string `protobuf:"bytes,5,opt,name=client_identity,json=clientIdentity,proto3,oneof" json:"client_identity,omitempty"`
+	// If a partition key is present, it supersedes the regular record key in determining the routing of
+	// a record to a particular shard. It is passed to the server because it needs to be persisted as
+	// part of the record. We would need the partition_key if we're going to do a split of the shards.
+	PartitionKey *string `protobuf:"bytes,6,opt,name=partition_key,json=partitionKey,proto3,oneof" json:"partition_key,omitempty"`
+	// If one or more sequence key are specified. The key will get added suffixes
+	// based on adding the delta to the current highest key with the same prefix
+	SequenceKeyDelta []uint64 `protobuf:"varint,7,rep,packed,name=sequence_key_delta,json=sequenceKeyDelta,proto3" json:"sequence_key_delta,omitempty"`
+	// If set, the value is ignored and the record is treated as a counter: the
+	// delta is added to its current value, as a decimal integer, or to 0 if the
+	// record doesn't exist
+	IncrementDelta *int64 `protobuf:"varint,8,opt,name=increment_delta,json=incrementDelta,proto3,oneof" json:"increment_delta,omitempty"`
+}
+
+func (x *PutRequest) Reset() {
+	*x = PutRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutRequest) ProtoMessage() {}
+
+func (x *PutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutRequest.ProtoReflect.Descriptor instead.
+func (*PutRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PutRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PutRequest) GetValue() []byte {
 	if x != nil {
 		return x.Value
 	}
 	return nil
 }
 
-func (x *GetResponse) GetKey() string {
-	if x != nil && x.Key != nil {
-		return *x.Key
+func (x *PutRequest) GetExpectedVersionId() int64 {
+	if x != nil && x.ExpectedVersionId != nil {
+		return *x.ExpectedVersionId
+	}
+	return 0
+}
+
+func (x *PutRequest) GetSessionId() int64 {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return 0
+}
+
+func (x *PutRequest) GetClientIdentity() string {
+	if x != nil && x.ClientIdentity != nil {
+		return *x.ClientIdentity
+	}
+	return ""
+}
+
+func (x *PutRequest) GetPartitionKey() string {
+	if x != nil && x.PartitionKey != nil {
+		return *x.PartitionKey
+	}
+	return ""
+}
+
+func (x *PutRequest) GetSequenceKeyDelta() []uint64 {
+	if x != nil {
+		return x.SequenceKeyDelta
+	}
+	return nil
+}
+
+func (x *PutRequest) GetIncrementDelta() int64 {
+	if x != nil && x.IncrementDelta != nil {
+		return *x.IncrementDelta
+	}
+	return 0
+}
+
+// *
+// The response to a put request.
+type PutResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Includes the error or OK
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+	// The version if the put was successful
+	Version *Version `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// If the key was generated by Oxia, it will be returned as part
+	// of the response
+	Key *string `protobuf:"bytes,3,opt,name=key,proto3,oneof" json:"key,omitempty"`
+	// The value of the counter after an increment
+	IncrementedValue *int64 `protobuf:"varint,4,opt,name=incremented_value,json=incrementedValue,proto3,oneof" json:"incremented_value,omitempty"`
+}
+
+func (x *PutResponse) Reset() {
+	*x = PutResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutResponse) ProtoMessage() {}
+
+func (x *PutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
+func (*PutResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PutResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_OK
+}
+
+func (x *PutResponse) GetVersion() *Version {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *PutResponse) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
+	}
+	return ""
+}
+
+func (x *PutResponse) GetIncrementedValue() int64 {
+	if x != nil && x.IncrementedValue != nil {
+		return *x.IncrementedValue
+	}
+	return 0
+}
+
+// *
+// A delete request. Deletes the specified key.
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// An optional expected version_id. The delete will fail if the server's current version_id
+	// does not match
+	ExpectedVersionId *int64 `protobuf:"varint,2,opt,name=expected_version_id,json=expectedVersionId,proto3,oneof" json:"expected_version_id,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetExpectedVersionId() int64 {
+	if x != nil && x.ExpectedVersionId != nil {
+		return *x.ExpectedVersionId
+	}
+	return 0
+}
+
+// *
+// The response to a delete request or an item in a response to the
+// delete range request.
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Includes the error or OK
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_OK
+}
+
+// *
+// A get request. Gets the stat and optionally the value for the specified
+// key.
+type GetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Specifies whether the response should include the value
+	IncludeValue   bool              `protobuf:"varint,2,opt,name=include_value,json=includeValue,proto3" json:"include_value,omitempty"`
+	ComparisonType KeyComparisonType `protobuf:"varint,3,opt,name=comparison_type,json=comparisonType,proto3,enum=io.streamnative.oxia.proto.KeyComparisonType" json:"comparison_type,omitempty"`
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetRequest) GetIncludeValue() bool {
+	if x != nil {
+		return x.IncludeValue
+	}
+	return false
+}
+
+func (x *GetRequest) GetComparisonType() KeyComparisonType {
+	if x != nil {
+		return x.ComparisonType
+	}
+	return KeyComparisonType_EQUAL
+}
+
+// *
+// The response to a get request.
+type GetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Includes the error or OK
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+	// The version of the record
+	Version *Version `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// The value, if it was requested and there was no error
+	Value []byte `protobuf:"bytes,3,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	// In case of non-exact queries (eg. floor, ceiling) the found key will be
+	// returned in the GetResponse.
+	Key *string `protobuf:"bytes,4,opt,name=key,proto3,oneof" json:"key,omitempty"`
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_OK
+}
+
+func (x *GetResponse) GetVersion() *Version {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *GetResponse) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *GetResponse) GetKey() string {
+	if x != nil && x.Key != nil {
+		return *x.Key
+	}
+	return ""
+}
+
+// *
+// Input to a delete range request. Key ranges assume a UTF-8 byte sort order.
+type DeleteRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The start of the range, inclusive
+	StartInclusive string `protobuf:"bytes,1,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
+	// The end of the range, exclusive
+	EndExclusive string `protobuf:"bytes,2,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+}
+
+func (x *DeleteRangeRequest) Reset() {
+	*x = DeleteRangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRangeRequest) ProtoMessage() {}
+
+func (x *DeleteRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRangeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRangeRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteRangeRequest) GetStartInclusive() string {
+	if x != nil {
+		return x.StartInclusive
+	}
+	return ""
+}
+
+func (x *DeleteRangeRequest) GetEndExclusive() string {
+	if x != nil {
+		return x.EndExclusive
+	}
+	return ""
+}
+
+// *
+// The response for a delete range request.
+type DeleteRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Includes the error or OK
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+}
+
+func (x *DeleteRangeResponse) Reset() {
+	*x = DeleteRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRangeResponse) ProtoMessage() {}
+
+func (x *DeleteRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRangeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRangeResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteRangeResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_OK
+}
+
+// *
+// Input to a list request. Key ranges assume a UTF-8 byte sort order.
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The shard id. This is optional allow for support for server-side hashing
+	// and proxying in the future.
+	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
+	// The start of the range, inclusive
+	StartInclusive string `protobuf:"bytes,2,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
+	// The end of the range, exclusive
+	EndExclusive string `protobuf:"bytes,3,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListRequest) GetShardId() int64 {
+	if x != nil && x.ShardId != nil {
+		return *x.ShardId
+	}
+	return 0
+}
+
+func (x *ListRequest) GetStartInclusive() string {
+	if x != nil {
+		return x.StartInclusive
+	}
+	return ""
+}
+
+func (x *ListRequest) GetEndExclusive() string {
+	if x != nil {
+		return x.EndExclusive
+	}
+	return ""
+}
+
+// *
+// The response to a list request.
+type ListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A portion of the keys found within the specified range
+	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListResponse) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// *
+// Input to a range-scan request
+type RangeScanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The shard id. This is optional allow for support for server-side hashing
+	// and proxying in the future.
+	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
+	// The start of the range, inclusive
+	StartInclusive string `protobuf:"bytes,2,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
+	// The end of the range, exclusive
+	EndExclusive string `protobuf:"bytes,3,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+}
+
+func (x *RangeScanRequest) Reset() {
+	*x = RangeScanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RangeScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeScanRequest) ProtoMessage() {}
+
+func (x *RangeScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeScanRequest.ProtoReflect.Descriptor instead.
+func (*RangeScanRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RangeScanRequest) GetShardId() int64 {
+	if x != nil && x.ShardId != nil {
+		return *x.ShardId
+	}
+	return 0
+}
+
+func (x *RangeScanRequest) GetStartInclusive() string {
+	if x != nil {
+		return x.StartInclusive
+	}
+	return ""
+}
+
+func (x *RangeScanRequest) GetEndExclusive() string {
+	if x != nil {
+		return x.EndExclusive
+	}
+	return ""
+}
+
+// *
+// The response to a range-scan request.
+type RangeScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A portion of the records found within the specified range
+	Records []*GetResponse `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *RangeScanResponse) Reset() {
+	*x = RangeScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RangeScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeScanResponse) ProtoMessage() {}
+
+func (x *RangeScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeScanResponse.ProtoReflect.Descriptor instead.
+func (*RangeScanResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RangeScanResponse) GetRecords() []*GetResponse {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// *
+// Version contains info about the state of a record.
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version identifier of the record
+	VersionId int64 `protobuf:"varint,1,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	// The number of modifications made to the record since
+	// it was created
+	ModificationsCount int64 `protobuf:"varint,2,opt,name=modifications_count,json=modificationsCount,proto3" json:"modifications_count,omitempty"`
+	// The creation timestamp of the first version of the record
+	CreatedTimestamp uint64 `protobuf:"fixed64,3,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
+	// The modified timestamp of the current version of the record
+	ModifiedTimestamp uint64 `protobuf:"fixed64,4,opt,name=modified_timestamp,json=modifiedTimestamp,proto3" json:"modified_timestamp,omitempty"`
+	// Identifier of the session if the record is ephemeral
+	SessionId      *int64  `protobuf:"varint,5,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
+	ClientIdentity *string `protobuf:"bytes,6,opt,name=client_identity,json=clientIdentity,proto3,oneof" json:"client_identity,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Version) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *Version) GetModificationsCount() int64 {
+	if x != nil {
+		return x.ModificationsCount
+	}
+	return 0
+}
+
+func (x *Version) GetCreatedTimestamp() uint64 {
+	if x != nil {
+		return x.CreatedTimestamp
+	}
+	return 0
+}
+
+func (x *Version) GetModifiedTimestamp() uint64 {
+	if x != nil {
+		return x.ModifiedTimestamp
+	}
+	return 0
+}
+
+func (x *Version) GetSessionId() int64 {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return 0
+}
+
+func (x *Version) GetClientIdentity() string {
+	if x != nil && x.ClientIdentity != nil {
+		return *x.ClientIdentity
 	}
 	return ""
 }
 
-// *
-// Input to a delete range request. Key ranges assume a UTF-8 byte sort order.
-type DeleteRangeRequest struct {
+type CreateSessionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The start of the range, inclusive
-	StartInclusive string `protobuf:"bytes,1,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
-	// The end of the range, exclusive
-	EndExclusive string `protobuf:"bytes,2,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+	ShardId          int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	SessionTimeoutMs uint32 `protobuf:"varint,2,opt,name=session_timeout_ms,json=sessionTimeoutMs,proto3" json:"session_timeout_ms,omitempty"`
+	ClientIdentity   string `protobuf:"bytes,3,opt,name=client_identity,json=clientIdentity,proto3" json:"client_identity,omitempty"`
 }
 
-func (x *DeleteRangeRequest) Reset() {
-	*x = DeleteRangeRequest{}
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[15]
+		mi := &file_client_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteRangeRequest) String() string {
+func (x *CreateSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRangeRequest) ProtoMessage() {}
+func (*CreateSessionRequest) ProtoMessage() {}
 
-func (x *DeleteRangeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[15]
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1304,53 +2444,57 @@ func (x *DeleteRangeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRangeRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRangeRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *DeleteRangeRequest) GetStartInclusive() string {
+func (x *CreateSessionRequest) GetShardId() int64 {
 	if x != nil {
-		return x.StartInclusive
+		return x.ShardId
 	}
-	return ""
+	return 0
 }
 
-func (x *DeleteRangeRequest) GetEndExclusive() string {
+func (x *CreateSessionRequest) GetSessionTimeoutMs() uint32 {
 	if x != nil {
-		return x.EndExclusive
+		return x.SessionTimeoutMs
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetClientIdentity() string {
+	if x != nil {
+		return x.ClientIdentity
 	}
 	return ""
 }
 
-// *
-// The response for a delete range request.
-type DeleteRangeResponse struct {
+type CreateSessionResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Includes the error or OK
-	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=io.streamnative.oxia.proto.Status" json:"status,omitempty"`
+	SessionId int64 `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *DeleteRangeResponse) Reset() {
-	*x = DeleteRangeResponse{}
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[16]
+		mi := &file_client_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteRangeResponse) String() string {
+func (x *CreateSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRangeResponse) ProtoMessage() {}
+func (*CreateSessionResponse) ProtoMessage() {}
 
-func (x *DeleteRangeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[16]
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1361,51 +2505,44 @@ func (x *DeleteRangeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRangeResponse.ProtoReflect.Descriptor instead.
-func (*DeleteRangeResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *DeleteRangeResponse) GetStatus() Status {
+func (x *CreateSessionResponse) GetSessionId() int64 {
 	if x != nil {
-		return x.Status
+		return x.SessionId
 	}
-	return Status_OK
+	return 0
 }
 
-// *
-// Input to a list request. Key ranges assume a UTF-8 byte sort order.
-type ListRequest struct {
+type SessionHeartbeat struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The shard id. This is optional allow for support for server-side hashing
-	// and proxying in the future.
-	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
-	// The start of the range, inclusive
-	StartInclusive string `protobuf:"bytes,2,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
-	// The end of the range, exclusive
-	EndExclusive string `protobuf:"bytes,3,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+	ShardId   int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	SessionId int64 `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *ListRequest) Reset() {
-	*x = ListRequest{}
+func (x *SessionHeartbeat) Reset() {
+	*x = SessionHeartbeat{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[17]
+		mi := &file_client_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListRequest) String() string {
+func (x *SessionHeartbeat) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequest) ProtoMessage() {}
+func (*SessionHeartbeat) ProtoMessage() {}
 
-func (x *ListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[17]
+func (x *SessionHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1416,60 +2553,48 @@ func (x *ListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
-func (*ListRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *ListRequest) GetShardId() int64 {
-	if x != nil && x.ShardId != nil {
-		return *x.ShardId
-	}
-	return 0
+// Deprecated: Use SessionHeartbeat.ProtoReflect.Descriptor instead.
+func (*SessionHeartbeat) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *ListRequest) GetStartInclusive() string {
+func (x *SessionHeartbeat) GetShardId() int64 {
 	if x != nil {
-		return x.StartInclusive
+		return x.ShardId
 	}
-	return ""
+	return 0
 }
 
-func (x *ListRequest) GetEndExclusive() string {
+func (x *SessionHeartbeat) GetSessionId() int64 {
 	if x != nil {
-		return x.EndExclusive
+		return x.SessionId
 	}
-	return ""
+	return 0
 }
 
-// *
-// The response to a list request.
-type ListResponse struct {
+type KeepAliveResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// A portion of the keys found within the specified range
-	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 }
 
-func (x *ListResponse) Reset() {
-	*x = ListResponse{}
+func (x *KeepAliveResponse) Reset() {
+	*x = KeepAliveResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[18]
+		mi := &file_client_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListResponse) String() string {
+func (x *KeepAliveResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListResponse) ProtoMessage() {}
+func (*KeepAliveResponse) ProtoMessage() {}
 
-func (x *ListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[18]
+func (x *KeepAliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1480,51 +2605,37 @@ func (x *ListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{18}
-}
-
-func (x *ListResponse) GetKeys() []string {
-	if x != nil {
-		return x.Keys
-	}
-	return nil
+// Deprecated: Use KeepAliveResponse.ProtoReflect.Descriptor instead.
+func (*KeepAliveResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{33}
 }
 
-// *
-// Input to a range-scan request
-type RangeScanRequest struct {
+type CloseSessionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The shard id. This is optional allow for support for server-side hashing
-	// and proxying in the future.
-	ShardId *int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3,oneof" json:"shard_id,omitempty"`
-	// The start of the range, inclusive
-	StartInclusive string `protobuf:"bytes,2,opt,name=start_inclusive,json=startInclusive,proto3" json:"start_inclusive,omitempty"`
-	// The end of the range, exclusive
-	EndExclusive string `protobuf:"bytes,3,opt,name=end_exclusive,json=endExclusive,proto3" json:"end_exclusive,omitempty"`
+	ShardId   int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	SessionId int64 `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *RangeScanRequest) Reset() {
-	*x = RangeScanRequest{}
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[19]
+		mi := &file_client_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RangeScanRequest) String() string {
+func (x *CloseSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RangeScanRequest) ProtoMessage() {}
+func (*CloseSessionRequest) ProtoMessage() {}
 
-func (x *RangeScanRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[19]
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1535,60 +2646,48 @@ func (x *RangeScanRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RangeScanRequest.ProtoReflect.Descriptor instead.
-func (*RangeScanRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *RangeScanRequest) GetShardId() int64 {
-	if x != nil && x.ShardId != nil {
-		return *x.ShardId
-	}
-	return 0
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *RangeScanRequest) GetStartInclusive() string {
+func (x *CloseSessionRequest) GetShardId() int64 {
 	if x != nil {
-		return x.StartInclusive
+		return x.ShardId
 	}
-	return ""
+	return 0
 }
 
-func (x *RangeScanRequest) GetEndExclusive() string {
+func (x *CloseSessionRequest) GetSessionId() int64 {
 	if x != nil {
-		return x.EndExclusive
+		return x.SessionId
 	}
-	return ""
+	return 0
 }
 
-// *
-// The response to a range-scan request.
-type RangeScanResponse struct {
+type CloseSessionResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// A portion of the records found within the specified range
-	Records []*GetResponse `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 }
 
-func (x *RangeScanResponse) Reset() {
-	*x = RangeScanResponse{}
+func (x *CloseSessionResponse) Reset() {
+	*x = CloseSessionResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[20]
+		mi := &file_client_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RangeScanResponse) String() string {
+func (x *CloseSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RangeScanResponse) ProtoMessage() {}
+func (*CloseSessionResponse) ProtoMessage() {}
 
-func (x *RangeScanResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[20]
+func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1599,56 +2698,39 @@ func (x *RangeScanResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RangeScanResponse.ProtoReflect.Descriptor instead.
-func (*RangeScanResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{20}
-}
-
-func (x *RangeScanResponse) GetRecords() []*GetResponse {
-	if x != nil {
-		return x.Records
-	}
-	return nil
+// Deprecated: Use CloseSessionResponse.ProtoReflect.Descriptor instead.
+func (*CloseSessionResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{35}
 }
 
-// *
-// Version contains info about the state of a record.
-type Version struct {
+type GrantLeaseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The version identifier of the record
-	VersionId int64 `protobuf:"varint,1,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
-	// The number of modifications made to the record since
-	// it was created
-	ModificationsCount int64 `protobuf:"varint,2,opt,name=modifications_count,json=modificationsCount,proto3" json:"modifications_count,omitempty"`
-	// The creation timestamp of the first version of the record
-	CreatedTimestamp uint64 `protobuf:"fixed64,3,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
-	// The modified timestamp of the current version of the record
-	ModifiedTimestamp uint64 `protobuf:"fixed64,4,opt,name=modified_timestamp,json=modifiedTimestamp,proto3" json:"modified_timestamp,omitempty"`
-	// Identifier of the session if the record is ephemeral
-	SessionId      *int64  `protobuf:"varint,5,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
-	ClientIdentity *string `protobuf:"bytes,6,opt,name=client_identity,json=clientIdentity,proto3,oneof" json:"client_identity,omitempty"`
+	ShardId        int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	Name           string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TtlMs          uint32 `protobuf:"varint,3,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	ClientIdentity string `protobuf:"bytes,4,opt,name=client_identity,json=clientIdentity,proto3" json:"client_identity,omitempty"`
 }
-
-func (x *Version) Reset() {
-	*x = Version{}
+
+func (x *GrantLeaseRequest) Reset() {
+	*x = GrantLeaseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[21]
+		mi := &file_client_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Version) String() string {
+func (x *GrantLeaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Version) ProtoMessage() {}
+func (*GrantLeaseRequest) ProtoMessage() {}
 
-func (x *Version) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[21]
+func (x *GrantLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1659,80 +2741,66 @@ func (x *Version) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Version.ProtoReflect.Descriptor instead.
-func (*Version) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use GrantLeaseRequest.ProtoReflect.Descriptor instead.
+func (*GrantLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *Version) GetVersionId() int64 {
+func (x *GrantLeaseRequest) GetShardId() int64 {
 	if x != nil {
-		return x.VersionId
+		return x.ShardId
 	}
 	return 0
 }
 
-func (x *Version) GetModificationsCount() int64 {
+func (x *GrantLeaseRequest) GetName() string {
 	if x != nil {
-		return x.ModificationsCount
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *Version) GetCreatedTimestamp() uint64 {
+func (x *GrantLeaseRequest) GetTtlMs() uint32 {
 	if x != nil {
-		return x.CreatedTimestamp
+		return x.TtlMs
 	}
 	return 0
 }
 
-func (x *Version) GetModifiedTimestamp() uint64 {
+func (x *GrantLeaseRequest) GetClientIdentity() string {
 	if x != nil {
-		return x.ModifiedTimestamp
-	}
-	return 0
-}
-
-func (x *Version) GetSessionId() int64 {
-	if x != nil && x.SessionId != nil {
-		return *x.SessionId
-	}
-	return 0
-}
-
-func (x *Version) GetClientIdentity() string {
-	if x != nil && x.ClientIdentity != nil {
-		return *x.ClientIdentity
+		return x.ClientIdentity
 	}
 	return ""
 }
 
-type CreateSessionRequest struct {
+type GrantLeaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ShardId          int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
-	SessionTimeoutMs uint32 `protobuf:"varint,2,opt,name=session_timeout_ms,json=sessionTimeoutMs,proto3" json:"session_timeout_ms,omitempty"`
-	ClientIdentity   string `protobuf:"bytes,3,opt,name=client_identity,json=clientIdentity,proto3" json:"client_identity,omitempty"`
+	// The id by which the records are attached to the lease, as their session
+	// id. It's the same for all the clients that share the lease.
+	SessionId int64 `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *CreateSessionRequest) Reset() {
-	*x = CreateSessionRequest{}
+func (x *GrantLeaseResponse) Reset() {
+	*x = GrantLeaseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[22]
+		mi := &file_client_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CreateSessionRequest) String() string {
+func (x *GrantLeaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSessionRequest) ProtoMessage() {}
+func (*GrantLeaseResponse) ProtoMessage() {}
 
-func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[22]
+func (x *GrantLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1743,57 +2811,47 @@ func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
-func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *CreateSessionRequest) GetShardId() int64 {
-	if x != nil {
-		return x.ShardId
-	}
-	return 0
+// Deprecated: Use GrantLeaseResponse.ProtoReflect.Descriptor instead.
+func (*GrantLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *CreateSessionRequest) GetSessionTimeoutMs() uint32 {
+func (x *GrantLeaseResponse) GetSessionId() int64 {
 	if x != nil {
-		return x.SessionTimeoutMs
+		return x.SessionId
 	}
 	return 0
 }
 
-func (x *CreateSessionRequest) GetClientIdentity() string {
-	if x != nil {
-		return x.ClientIdentity
-	}
-	return ""
-}
-
-type CreateSessionResponse struct {
+type LeaseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionId int64 `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ShardId int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// When set, the request fails if the lease has expired and was granted
+	// again since, with another session id
+	SessionId *int64 `protobuf:"varint,3,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
 }
 
-func (x *CreateSessionResponse) Reset() {
-	*x = CreateSessionResponse{}
+func (x *LeaseRequest) Reset() {
+	*x = LeaseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[23]
+		mi := &file_client_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CreateSessionResponse) String() string {
+func (x *LeaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSessionResponse) ProtoMessage() {}
+func (*LeaseRequest) ProtoMessage() {}
 
-func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[23]
+func (x *LeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1804,44 +2862,55 @@ func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
-func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use LeaseRequest.ProtoReflect.Descriptor instead.
+func (*LeaseRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *CreateSessionResponse) GetSessionId() int64 {
+func (x *LeaseRequest) GetShardId() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.ShardId
 	}
 	return 0
 }
 
-type SessionHeartbeat struct {
+func (x *LeaseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LeaseRequest) GetSessionId() int64 {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return 0
+}
+
+type RefreshLeaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	ShardId   int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
-	SessionId int64 `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *SessionHeartbeat) Reset() {
-	*x = SessionHeartbeat{}
+func (x *RefreshLeaseResponse) Reset() {
+	*x = RefreshLeaseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[24]
+		mi := &file_client_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SessionHeartbeat) String() string {
+func (x *RefreshLeaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionHeartbeat) ProtoMessage() {}
+func (*RefreshLeaseResponse) ProtoMessage() {}
 
-func (x *SessionHeartbeat) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[24]
+func (x *RefreshLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1852,48 +2921,34 @@ func (x *SessionHeartbeat) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionHeartbeat.ProtoReflect.Descriptor instead.
-func (*SessionHeartbeat) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{24}
-}
-
-func (x *SessionHeartbeat) GetShardId() int64 {
-	if x != nil {
-		return x.ShardId
-	}
-	return 0
-}
-
-func (x *SessionHeartbeat) GetSessionId() int64 {
-	if x != nil {
-		return x.SessionId
-	}
-	return 0
+// Deprecated: Use RefreshLeaseResponse.ProtoReflect.Descriptor instead.
+func (*RefreshLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{39}
 }
 
-type KeepAliveResponse struct {
+type RevokeLeaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *KeepAliveResponse) Reset() {
-	*x = KeepAliveResponse{}
+func (x *RevokeLeaseResponse) Reset() {
+	*x = RevokeLeaseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[25]
+		mi := &file_client_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KeepAliveResponse) String() string {
+func (x *RevokeLeaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeepAliveResponse) ProtoMessage() {}
+func (*RevokeLeaseResponse) ProtoMessage() {}
 
-func (x *KeepAliveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[25]
+func (x *RevokeLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1904,37 +2959,49 @@ func (x *KeepAliveResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeepAliveResponse.ProtoReflect.Descriptor instead.
-func (*KeepAliveResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use RevokeLeaseResponse.ProtoReflect.Descriptor instead.
+func (*RevokeLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{40}
 }
 
-type CloseSessionRequest struct {
+type NotificationsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ShardId   int64 `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
-	SessionId int64 `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ShardId              int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	StartOffsetExclusive *int64 `protobuf:"varint,2,opt,name=start_offset_exclusive,json=startOffsetExclusive,proto3,oneof" json:"start_offset_exclusive,omitempty"`
+	// Only send the notifications for the keys starting with any of the
+	// prefixes. All the notifications are sent when empty.
+	KeyPrefixes []string `protobuf:"bytes,3,rep,name=key_prefixes,json=keyPrefixes,proto3" json:"key_prefixes,omitempty"`
+	// Only send the notifications of these types. All the types are sent when
+	// empty, with the range deletions reported as KEY_DELETED.
+	Types []NotificationType `protobuf:"varint,4,rep,packed,name=types,proto3,enum=io.streamnative.oxia.proto.NotificationType" json:"types,omitempty"`
+	// Include the value of the record in the KEY_CREATED and KEY_MODIFIED
+	// notifications
+	IncludeValue bool `protobuf:"varint,5,opt,name=include_value,json=includeValue,proto3" json:"include_value,omitempty"`
+	// The name of a durable consumer. When no start offset is given, the
+	// notifications resume after the last offset acknowledged by the consumer.
+	ConsumerName *string `protobuf:"bytes,6,opt,name=consumer_name,json=consumerName,proto3,oneof" json:"consumer_name,omitempty"`
 }
 
-func (x *CloseSessionRequest) Reset() {
-	*x = CloseSessionRequest{}
+func (x *NotificationsRequest) Reset() {
+	*x = NotificationsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[26]
+		mi := &file_client_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CloseSessionRequest) String() string {
+func (x *NotificationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CloseSessionRequest) ProtoMessage() {}
+func (*NotificationsRequest) ProtoMessage() {}
 
-func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[26]
+func (x *NotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1945,48 +3012,81 @@ func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
-func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use NotificationsRequest.ProtoReflect.Descriptor instead.
+func (*NotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *CloseSessionRequest) GetShardId() int64 {
+func (x *NotificationsRequest) GetShardId() int64 {
 	if x != nil {
 		return x.ShardId
 	}
 	return 0
 }
 
-func (x *CloseSessionRequest) GetSessionId() int64 {
-	if x != nil {
-		return x.SessionId
+func (x *NotificationsRequest) GetStartOffsetExclusive() int64 {
+	if x != nil && x.StartOffsetExclusive != nil {
+		return *x.StartOffsetExclusive
 	}
 	return 0
 }
 
-type CloseSessionResponse struct {
+func (x *NotificationsRequest) GetKeyPrefixes() []string {
+	if x != nil {
+		return x.KeyPrefixes
+	}
+	return nil
+}
+
+func (x *NotificationsRequest) GetTypes() []NotificationType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *NotificationsRequest) GetIncludeValue() bool {
+	if x != nil {
+		return x.IncludeValue
+	}
+	return false
+}
+
+func (x *NotificationsRequest) GetConsumerName() string {
+	if x != nil && x.ConsumerName != nil {
+		return *x.ConsumerName
+	}
+	return ""
+}
+
+type AckNotificationsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	ShardId      int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
+	ConsumerName string `protobuf:"bytes,2,opt,name=consumer_name,json=consumerName,proto3" json:"consumer_name,omitempty"`
+	// The offset of the last notification processed by the consumer
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
-func (x *CloseSessionResponse) Reset() {
-	*x = CloseSessionResponse{}
+func (x *AckNotificationsRequest) Reset() {
+	*x = AckNotificationsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[27]
+		mi := &file_client_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CloseSessionResponse) String() string {
+func (x *AckNotificationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CloseSessionResponse) ProtoMessage() {}
+func (*AckNotificationsRequest) ProtoMessage() {}
 
-func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[27]
+func (x *AckNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1997,37 +3097,55 @@ func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CloseSessionResponse.ProtoReflect.Descriptor instead.
-func (*CloseSessionResponse) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use AckNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*AckNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{42}
 }
 
-type NotificationsRequest struct {
+func (x *AckNotificationsRequest) GetShardId() int64 {
+	if x != nil {
+		return x.ShardId
+	}
+	return 0
+}
+
+func (x *AckNotificationsRequest) GetConsumerName() string {
+	if x != nil {
+		return x.ConsumerName
+	}
+	return ""
+}
+
+func (x *AckNotificationsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type AckNotificationsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	ShardId              int64  `protobuf:"varint,1,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
-	StartOffsetExclusive *int64 `protobuf:"varint,2,opt,name=start_offset_exclusive,json=startOffsetExclusive,proto3,oneof" json:"start_offset_exclusive,omitempty"`
 }
 
-func (x *NotificationsRequest) Reset() {
-	*x = NotificationsRequest{}
+func (x *AckNotificationsResponse) Reset() {
+	*x = AckNotificationsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[28]
+		mi := &file_client_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NotificationsRequest) String() string {
+func (x *AckNotificationsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotificationsRequest) ProtoMessage() {}
+func (*AckNotificationsResponse) ProtoMessage() {}
 
-func (x *NotificationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[28]
+func (x *AckNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2038,23 +3156,9 @@ func (x *NotificationsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotificationsRequest.ProtoReflect.Descriptor instead.
-func (*NotificationsRequest) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *NotificationsRequest) GetShardId() int64 {
-	if x != nil {
-		return x.ShardId
-	}
-	return 0
-}
-
-func (x *NotificationsRequest) GetStartOffsetExclusive() int64 {
-	if x != nil && x.StartOffsetExclusive != nil {
-		return *x.StartOffsetExclusive
-	}
-	return 0
+// Deprecated: Use AckNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*AckNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{43}
 }
 
 type NotificationBatch struct {
@@ -2071,7 +3175,7 @@ type NotificationBatch struct {
 func (x *NotificationBatch) Reset() {
 	*x = NotificationBatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[29]
+		mi := &file_client_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2084,7 +3188,7 @@ func (x *NotificationBatch) String() string {
 func (*NotificationBatch) ProtoMessage() {}
 
 func (x *NotificationBatch) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[29]
+	mi := &file_client_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2097,7 +3201,7 @@ func (x *NotificationBatch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotificationBatch.ProtoReflect.Descriptor instead.
 func (*NotificationBatch) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{29}
+	return file_client_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *NotificationBatch) GetShardId() int64 {
@@ -2135,12 +3239,19 @@ type Notification struct {
 
 	Type      NotificationType `protobuf:"varint,1,opt,name=type,proto3,enum=io.streamnative.oxia.proto.NotificationType" json:"type,omitempty"`
 	VersionId *int64           `protobuf:"varint,2,opt,name=version_id,json=versionId,proto3,oneof" json:"version_id,omitempty"`
+	// The offset of the entry that generated the notification, when it's
+	// different from the offset of the batch it's part of, after multiple
+	// batches were merged together
+	Offset *int64 `protobuf:"varint,3,opt,name=offset,proto3,oneof" json:"offset,omitempty"`
+	// The value of the record, when requested by the subscriber. It's only
+	// set if the record was not modified again since the notification.
+	Value []byte `protobuf:"bytes,4,opt,name=value,proto3,oneof" json:"value,omitempty"`
 }
 
 func (x *Notification) Reset() {
 	*x = Notification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_client_proto_msgTypes[30]
+		mi := &file_client_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2153,7 +3264,7 @@ func (x *Notification) String() string {
 func (*Notification) ProtoMessage() {}
 
 func (x *Notification) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[30]
+	mi := &file_client_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2166,7 +3277,7 @@ func (x *Notification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Notification.ProtoReflect.Descriptor instead.
 func (*Notification) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{30}
+	return file_client_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *Notification) GetType() NotificationType {
@@ -2183,6 +3294,20 @@ func (x *Notification) GetVersionId() int64 {
 	return 0
 }
 
+func (x *Notification) GetOffset() int64 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *Notification) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
 var File_client_proto protoreflect.FileDescriptor
 
 var file_client_proto_rawDesc = []byte{
@@ -2206,7 +3331,7 @@ var file_client_proto_rawDesc = []byte{
 	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69,
 	0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
 	0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e,
-	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc0, 0x01, 0x0a,
+	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8b, 0x02, 0x0a,
 	0x19, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73,
 	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x4d, 0x0a, 0x0b, 0x61, 0x73,
 	0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
@@ -2218,26 +3343,164 @@ var file_client_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0e, 0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e,
 	0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x2e, 0x53, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x52,
-	0x0e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x22,
-	0xb0, 0x01, 0x0a, 0x0f, 0x53, 0x68, 0x61, 0x72, 0x64, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d,
-	0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x16,
-	0x0a, 0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x56, 0x0a, 0x10, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f,
-	0x68, 0x61, 0x73, 0x68, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x0e, 0x73, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x12,
+	0x49, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2d, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74,
+	0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
+	0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0xc9, 0x06, 0x0a, 0x11, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
+	0x12, 0x24, 0x0a, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74, 0x74, 0x6c, 0x5f,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x54, 0x74, 0x6c, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x6d, 0x61, 0x78, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x31, 0x0a, 0x15,
+	0x6d, 0x61, 0x78, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x6d, 0x61, 0x78,
+	0x57, 0x72, 0x69, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12,
+	0x2f, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x73, 0x5f, 0x70, 0x65, 0x72,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x6d,
+	0x61, 0x78, 0x52, 0x65, 0x61, 0x64, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x12, 0x3c, 0x0a, 0x1a, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x5f, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x18, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x38,
+	0x0a, 0x19, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x70, 0x73,
+	0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x70, 0x73, 0x50,
+	0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x47, 0x0a, 0x21, 0x6d, 0x61, 0x78, 0x5f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x1c, 0x6d, 0x61, 0x78, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x74, 0x6c,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x54,
+	0x74, 0x6c, 0x12, 0x5d, 0x0a, 0x0d, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x69, 0x6f, 0x2e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x0d, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x6d, 0x0a, 0x10, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x5f, 0x71,
+	0x75, 0x6f, 0x74, 0x61, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x69, 0x6f,
+	0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78,
+	0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x63,
+	0x69, 0x70, 0x61, 0x6c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0f, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x73,
+	0x12, 0x47, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
-	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e,
-	0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0e,
-	0x69, 0x6e, 0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x12,
-	0x0a, 0x10, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x69,
-	0x65, 0x73, 0x22, 0x6c, 0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x61, 0x73, 0x68,
-	0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x07,
-	0x52, 0x10, 0x6d, 0x69, 0x6e, 0x48, 0x61, 0x73, 0x68, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69,
-	0x76, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x69,
-	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x07, 0x52, 0x10,
-	0x6d, 0x61, 0x78, 0x48, 0x61, 0x73, 0x68, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65,
-	0x22, 0x91, 0x02, 0x0a, 0x0c, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65,
+	0x6e, 0x61, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x48, 0x01, 0x52, 0x06,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x1a, 0x6e, 0x0a, 0x14, 0x50, 0x72, 0x69,
+	0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x40, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x22, 0x8d, 0x01, 0x0a, 0x0e, 0x54, 0x65, 0x6e, 0x61, 0x6e,
+	0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a,
+	0x12, 0x6d, 0x61, 0x78, 0x5f, 0x6f, 0x70, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x4f, 0x70,
+	0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x3a, 0x0a, 0x1a, 0x6d, 0x61,
+	0x78, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65,
+	0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x16,
+	0x6d, 0x61, 0x78, 0x57, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72,
+	0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x22, 0x97, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x69, 0x6e, 0x63,
+	0x69, 0x70, 0x61, 0x6c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x70, 0x73,
+	0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0c, 0x6f, 0x70, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12,
+	0x33, 0x0a, 0x16, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70,
+	0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x13, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0f, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x22, 0xaa, 0x03, 0x0a, 0x16, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x41, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x62, 0x0a, 0x0a, 0x70,
+	0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x42, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x12,
+	0x53, 0x0a, 0x0c, 0x6b, 0x65, 0x79, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x79, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x50, 0x65, 0x72, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0b, 0x6b, 0x65, 0x79, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x12, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61,
+	0x6c, 0x5f, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x37, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72,
+	0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x50, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x11, 0x70, 0x72, 0x69, 0x6e, 0x63,
+	0x69, 0x70, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x1a, 0x6f, 0x0a, 0x0f,
+	0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x46, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8b, 0x01,
+	0x0a, 0x1b, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x6e, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x52, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69,
+	0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f,
+	0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0b,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x14,
+	0x4b, 0x65, 0x79, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70,
+	0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x52, 0x0a, 0x0b, 0x70, 0x65,
+	0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x56,
+	0x0a, 0x14, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x65, 0x61, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x72, 0x65, 0x61, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x22, 0xf5, 0x01, 0x0a, 0x0f, 0x53, 0x68, 0x61, 0x72, 0x64,
+	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x56, 0x0a,
+	0x10, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x72, 0x61, 0x6e, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0e, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x43, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x08, 0x6b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x5f, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x69, 0x65, 0x73, 0x22, 0x6c,
+	0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x48, 0x61, 0x73, 0x68, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x07, 0x52, 0x10, 0x6d, 0x69,
+	0x6e, 0x48, 0x61, 0x73, 0x68, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x12, 0x2c,
+	0x0a, 0x12, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x07, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x48,
+	0x61, 0x73, 0x68, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x22, 0x62, 0x0a, 0x08,
+	0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x69, 0x6e, 0x5f,
+	0x6b, 0x65, 0x79, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x69, 0x6e, 0x4b, 0x65, 0x79, 0x49, 0x6e, 0x63, 0x6c, 0x75,
+	0x73, 0x69, 0x76, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x6d, 0x61, 0x78, 0x4b, 0x65, 0x79, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65,
+	0x22, 0x83, 0x03, 0x0a, 0x0c, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x12, 0x1e, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x88, 0x01,
 	0x01, 0x12, 0x3a, 0x0a, 0x04, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
@@ -2253,238 +3516,314 @@ var file_client_proto_rawDesc = []byte{
 	0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e,
 	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x68, 0x61, 0x72,
-	0x64, 0x5f, 0x69, 0x64, 0x22, 0xe8, 0x01, 0x0a, 0x0d, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x04, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x50, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x70,
-	0x75, 0x74, 0x73, 0x12, 0x44, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x73, 0x12, 0x54, 0x0a, 0x0d, 0x64, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x2f, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
-	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22,
-	0x76, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e,
-	0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x48, 0x00, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x3a,
-	0x0a, 0x04, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69,
-	0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f,
-	0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x52, 0x04, 0x67, 0x65, 0x74, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73,
-	0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x04, 0x67, 0x65, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
-	0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04,
-	0x67, 0x65, 0x74, 0x73, 0x22, 0xe0, 0x02, 0x0a, 0x0a, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x33, 0x0a, 0x13, 0x65,
-	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x11, 0x65, 0x78, 0x70, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01,
-	0x12, 0x22, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
-	0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x88,
-	0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0c, 0x70, 0x61, 0x72,
-	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x12,
-	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x6c,
-	0x74, 0x61, 0x18, 0x07, 0x20, 0x03, 0x28, 0x04, 0x52, 0x10, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x65, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65,
-	0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x22, 0xa7, 0x01, 0x0a, 0x0b, 0x50, 0x75, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x63,
+	0x69, 0x70, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x09, 0x70, 0x72,
+	0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x6d, 0x65,
+	0x72, 0x67, 0x65, 0x64, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x0d, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x53, 0x68, 0x61,
+	0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x68, 0x61, 0x72,
+	0x64, 0x5f, 0x69, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70,
+	0x61, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x64, 0x5f, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0xc5, 0x02, 0x0a, 0x0d, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x04, 0x70, 0x75, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
+	0x04, 0x70, 0x75, 0x74, 0x73, 0x12, 0x44, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x73, 0x12, 0x54, 0x0a, 0x0d, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x12, 0x4a, 0x0a, 0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
 	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x3d, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0b, 0x6d,
+	0x65, 0x72, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x76,
+	0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a,
+	0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a,
+	0x04, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x69, 0x6f,
+	0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78,
+	0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x04, 0x67, 0x65, 0x74, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x04, 0x67, 0x65, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
 	0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6b, 0x65,
-	0x79, 0x22, 0x6e, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x04, 0x67,
+	0x65, 0x74, 0x73, 0x22, 0xa2, 0x03, 0x0a, 0x0a, 0x50, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x33, 0x0a, 0x13, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
-	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x48, 0x00, 0x52, 0x11, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x78,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x33, 0x0a, 0x13, 0x65, 0x78,
 	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x22, 0x4c, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x11, 0x65, 0x78, 0x70, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x22, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0e,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x88, 0x01,
+	0x01, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0c, 0x70, 0x61, 0x72, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x12, 0x73,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x6c, 0x74,
+	0x61, 0x18, 0x07, 0x20, 0x03, 0x28, 0x04, 0x52, 0x10, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x0f, 0x69, 0x6e, 0x63,
+	0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x04, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x44,
+	0x65, 0x6c, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x6b, 0x65, 0x79, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x22, 0xef, 0x01, 0x0a, 0x0b, 0x50, 0x75, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x3d, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x11, 0x69, 0x6e,
+	0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x10, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04,
+	0x5f, 0x6b, 0x65, 0x79, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x6e, 0x0a, 0x0d, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x33, 0x0a,
+	0x13, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x11, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88,
+	0x01, 0x01, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x22, 0x4c, 0x0a, 0x0e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69,
+	0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f,
+	0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x0a, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x56,
+	0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2d, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0xcc, 0x01, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x3d, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e,
 	0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
-	0x9b, 0x01, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x6b, 0x65, 0x79, 0x22, 0x62, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75,
+	0x73, 0x69, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64,
+	0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x22, 0x51, 0x0a, 0x13, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x88, 0x01, 0x0a,
+	0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x08,
+	0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0f,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x63, 0x6c,
+	0x75, 0x73, 0x69, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x65, 0x78, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e,
+	0x64, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x22, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x8d, 0x01, 0x0a, 0x10,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1e, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73,
+	0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64,
+	0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x6e, 0x64, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x56, 0x0a, 0x11, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x41, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74,
+	0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x73, 0x22, 0xaa, 0x02, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2f,
+	0x0a, 0x13, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x6d, 0x6f, 0x64,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x2b, 0x0a, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x10, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x12,
+	0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x06, 0x52, 0x11, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69,
+	0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x22, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x2c, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x12, 0x0a, 0x10,
+	0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x22, 0x88, 0x01, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61,
+	0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61,
+	0x72, 0x64, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x10, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x4d, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x36, 0x0a, 0x15, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x22, 0x4c, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x65,
+	0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x22, 0x13, 0x0a, 0x11, 0x4b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f, 0x0a, 0x13, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x43, 0x6c, 0x6f, 0x73, 0x65,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x82, 0x01, 0x0a, 0x11, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x74, 0x6c, 0x5f, 0x6d, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x74, 0x6c, 0x4d, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x22, 0x33, 0x0a, 0x12, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x70, 0x0a, 0x0c, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61,
+	0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61,
+	0x72, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x52,
+	0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xcf, 0x02, 0x0a, 0x14, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x39,
+	0x0a, 0x16, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x14, 0x73, 0x74, 0x61, 0x72, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x45, 0x78, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x6b, 0x65, 0x79,
+	0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x6b, 0x65, 0x79, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x42, 0x0a, 0x05,
+	0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x69, 0x6f,
+	0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78,
+	0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73,
 	0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x56, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69,
-	0x73, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2d,
-	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
-	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x79, 0x43,
-	0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0e, 0x63,
-	0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22, 0xcc, 0x01,
-	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e,
-	0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e,
-	0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3d, 0x0a, 0x07, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x69, 0x6f, 0x2e,
-	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69,
-	0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
-	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x01, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6b, 0x65, 0x79, 0x22, 0x62, 0x0a, 0x12,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c,
-	0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74, 0x61,
-	0x72, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65,
-	0x6e, 0x64, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65,
-	0x22, 0x51, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64,
-	0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x63,
-	0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x12, 0x23, 0x0a, 0x0d,
-	0x65, 0x6e, 0x64, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76,
-	0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x22, 0x22,
-	0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65,
-	0x79, 0x73, 0x22, 0x8d, 0x01, 0x0a, 0x10, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x61, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x73, 0x68, 0x61,
-	0x72, 0x64, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65,
-	0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x45, 0x78, 0x63, 0x6c,
-	0x75, 0x73, 0x69, 0x76, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f,
-	0x69, 0x64, 0x22, 0x56, 0x0a, 0x11, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x53, 0x63, 0x61, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x22, 0xaa, 0x02, 0x0a, 0x07, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x12, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x06, 0x52, 0x10, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x12, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x06, 0x52,
-	0x11, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x12, 0x22, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x01, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x5f, 0x69, 0x64, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x88, 0x01, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x22, 0x36, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x4c, 0x0a, 0x10, 0x53, 0x65,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x19,
-	0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x13, 0x0a, 0x11, 0x4b, 0x65, 0x65, 0x70,
-	0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f, 0x0a,
-	0x13, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12,
-	0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x16,
-	0x0a, 0x14, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x87, 0x01, 0x0a, 0x14, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x39, 0x0a, 0x16, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75,
-	0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x14, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69,
-	0x76, 0x65, 0x88, 0x01, 0x01, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
-	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65,
-	0x22, 0xb8, 0x02, 0x0a, 0x11, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49,
-	0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x09, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x66, 0x0a, 0x0d, 0x6e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x40,
-	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
-	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4e, 0x6f,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x28, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65,
+	0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c,
+	0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42,
+	0x19, 0x0a, 0x17, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x76, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x63,
+	0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x71, 0x0a, 0x17,
+	0x41, 0x63, 0x6b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64,
+	0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x73, 0x75,
+	0x6d, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22,
+	0x1a, 0x0a, 0x18, 0x41, 0x63, 0x6b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb8, 0x02, 0x0a, 0x11,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x68, 0x61, 0x72, 0x64, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x06, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x66, 0x0a, 0x0d, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x40, 0x2e, 0x69, 0x6f, 0x2e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x6e, 0x6f, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x6a, 0x0a, 0x12, 0x4e, 0x6f,
 	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x52, 0x0d, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a,
-	0x6a, 0x0a, 0x12, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x83, 0x01, 0x0a, 0x0c,
-	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x69, 0x6f, 0x2e,
-	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69,
-	0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x22,
-	0x0a, 0x0a, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88,
-	0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x2a, 0x2a, 0x0a, 0x0e, 0x53, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
-	0x12, 0x0b, 0x0a, 0x07, 0x58, 0x58, 0x48, 0x41, 0x53, 0x48, 0x33, 0x10, 0x01, 0x2a, 0x4d, 0x0a,
-	0x11, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69, 0x73, 0x6f, 0x6e, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x51, 0x55, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x09, 0x0a,
-	0x05, 0x46, 0x4c, 0x4f, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x45, 0x49, 0x4c,
-	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x4f, 0x57, 0x45, 0x52, 0x10, 0x03,
-	0x12, 0x0a, 0x0a, 0x06, 0x48, 0x49, 0x47, 0x48, 0x45, 0x52, 0x10, 0x04, 0x2a, 0x5a, 0x0a, 0x06,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x11,
-	0x0a, 0x0d, 0x4b, 0x45, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10,
-	0x01, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x4e, 0x45, 0x58, 0x50, 0x45, 0x43, 0x54, 0x45, 0x44, 0x5f,
-	0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x44, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16,
-	0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x4f, 0x45, 0x53, 0x5f, 0x4e, 0x4f, 0x54,
-	0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x10, 0x03, 0x2a, 0x46, 0x0a, 0x10, 0x4e, 0x6f, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b,
-	0x4b, 0x45, 0x59, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a,
-	0x0c, 0x4b, 0x45, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x01, 0x12,
-	0x0f, 0x0a, 0x0b, 0x4b, 0x45, 0x59, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02,
-	0x32, 0xbe, 0x08, 0x0a, 0x0a, 0x4f, 0x78, 0x69, 0x61, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x3e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74,
+	0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd0, 0x01, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0a, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52,
+	0x09, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x02, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a, 0x47, 0x0a, 0x0e, 0x53, 0x68, 0x61,
+	0x72, 0x64, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x58, 0x58, 0x48, 0x41,
+	0x53, 0x48, 0x33, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x4e, 0x56, 0x31, 0x41, 0x5f, 0x33,
+	0x32, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x45, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45,
+	0x10, 0x03, 0x2a, 0x4d, 0x0a, 0x11, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x69,
+	0x73, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x51, 0x55, 0x41, 0x4c,
+	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x4c, 0x4f, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0b, 0x0a,
+	0x07, 0x43, 0x45, 0x49, 0x4c, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x4f,
+	0x57, 0x45, 0x52, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x48, 0x49, 0x47, 0x48, 0x45, 0x52, 0x10,
+	0x04, 0x2a, 0x8d, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x06, 0x0a, 0x02,
+	0x4f, 0x4b, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x4b, 0x45, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
+	0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x4e, 0x45, 0x58, 0x50,
+	0x45, 0x43, 0x54, 0x45, 0x44, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x44,
+	0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x4f,
+	0x45, 0x53, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x10, 0x03, 0x12, 0x19,
+	0x0a, 0x15, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x45,
+	0x52, 0x5f, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12, 0x4b, 0x45, 0x59,
+	0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x53, 0x10,
+	0x05, 0x2a, 0x5d, 0x0a, 0x10, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x4b, 0x45, 0x59, 0x5f, 0x43, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x45, 0x59, 0x5f, 0x4d, 0x4f,
+	0x44, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4b, 0x45, 0x59, 0x5f,
+	0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4b, 0x45, 0x59,
+	0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03,
+	0x32, 0x80, 0x0c, 0x0a, 0x0a, 0x4f, 0x78, 0x69, 0x61, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12,
 	0x7a, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x41, 0x73, 0x73, 0x69, 0x67,
 	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x33, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65,
 	0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72,
@@ -2530,32 +3869,60 @@ var file_client_proto_rawDesc = []byte{
 	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e,
 	0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e,
 	0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x30, 0x01, 0x12, 0x74,
-	0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76,
-	0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x31, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74,
-	0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x09, 0x4b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76,
-	0x65, 0x12, 0x2c, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74,
-	0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x1a,
-	0x2d, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76,
-	0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x65,
-	0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x71,
-	0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x30, 0x01, 0x12, 0x7d,
+	0x0a, 0x10, 0x41, 0x63, 0x6b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x33, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x41, 0x63, 0x6b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x63, 0x6b, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x74, 0x0a,
+	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x30,
+	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x31, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x09, 0x4b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65,
+	0x12, 0x2c, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69,
+	0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x1a, 0x2d,
+	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4b, 0x65, 0x65, 0x70,
+	0x41, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x71, 0x0a,
+	0x0c, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x2e,
+	0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e,
+	0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30,
 	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
 	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x6f, 0x73,
-	0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76,
-	0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6c, 0x6f,
-	0x73, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x26, 0x50, 0x01, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2f, 0x6f,
-	0x78, 0x69, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x6b, 0x0a, 0x0a, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x2d,
+	0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x72, 0x61, 0x6e,
+	0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e,
+	0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e,
+	0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x74,
+	0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6a, 0x0a,
+	0x0c, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x28, 0x2e,
+	0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e,
+	0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x4c, 0x65, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x0b, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x28, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x69, 0x6f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61,
+	0x74, 0x69, 0x76, 0x65, 0x2e, 0x6f, 0x78, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x26, 0x50, 0x01, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x2f, 0x6f, 0x78, 0x69, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2571,96 +3938,135 @@ func file_client_proto_rawDescGZIP() []byte {
 }
 
 var file_client_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_client_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
+var file_client_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_client_proto_goTypes = []interface{}{
-	(ShardKeyRouter)(0),               // 0: io.streamnative.oxia.proto.ShardKeyRouter
-	(KeyComparisonType)(0),            // 1: io.streamnative.oxia.proto.KeyComparisonType
-	(Status)(0),                       // 2: io.streamnative.oxia.proto.Status
-	(NotificationType)(0),             // 3: io.streamnative.oxia.proto.NotificationType
-	(*ShardAssignmentsRequest)(nil),   // 4: io.streamnative.oxia.proto.ShardAssignmentsRequest
-	(*ShardAssignments)(nil),          // 5: io.streamnative.oxia.proto.ShardAssignments
-	(*NamespaceShardsAssignment)(nil), // 6: io.streamnative.oxia.proto.NamespaceShardsAssignment
-	(*ShardAssignment)(nil),           // 7: io.streamnative.oxia.proto.ShardAssignment
-	(*Int32HashRange)(nil),            // 8: io.streamnative.oxia.proto.Int32HashRange
-	(*WriteRequest)(nil),              // 9: io.streamnative.oxia.proto.WriteRequest
-	(*WriteResponse)(nil),             // 10: io.streamnative.oxia.proto.WriteResponse
-	(*ReadRequest)(nil),               // 11: io.streamnative.oxia.proto.ReadRequest
-	(*ReadResponse)(nil),              // 12: io.streamnative.oxia.proto.ReadResponse
-	(*PutRequest)(nil),                // 13: io.streamnative.oxia.proto.PutRequest
-	(*PutResponse)(nil),               // 14: io.streamnative.oxia.proto.PutResponse
-	(*DeleteRequest)(nil),             // 15: io.streamnative.oxia.proto.DeleteRequest
-	(*DeleteResponse)(nil),            // 16: io.streamnative.oxia.proto.DeleteResponse
-	(*GetRequest)(nil),                // 17: io.streamnative.oxia.proto.GetRequest
-	(*GetResponse)(nil),               // 18: io.streamnative.oxia.proto.GetResponse
-	(*DeleteRangeRequest)(nil),        // 19: io.streamnative.oxia.proto.DeleteRangeRequest
-	(*DeleteRangeResponse)(nil),       // 20: io.streamnative.oxia.proto.DeleteRangeResponse
-	(*ListRequest)(nil),               // 21: io.streamnative.oxia.proto.ListRequest
-	(*ListResponse)(nil),              // 22: io.streamnative.oxia.proto.ListResponse
-	(*RangeScanRequest)(nil),          // 23: io.streamnative.oxia.proto.RangeScanRequest
-	(*RangeScanResponse)(nil),         // 24: io.streamnative.oxia.proto.RangeScanResponse
-	(*Version)(nil),                   // 25: io.streamnative.oxia.proto.Version
-	(*CreateSessionRequest)(nil),      // 26: io.streamnative.oxia.proto.CreateSessionRequest
-	(*CreateSessionResponse)(nil),     // 27: io.streamnative.oxia.proto.CreateSessionResponse
-	(*SessionHeartbeat)(nil),          // 28: io.streamnative.oxia.proto.SessionHeartbeat
-	(*KeepAliveResponse)(nil),         // 29: io.streamnative.oxia.proto.KeepAliveResponse
-	(*CloseSessionRequest)(nil),       // 30: io.streamnative.oxia.proto.CloseSessionRequest
-	(*CloseSessionResponse)(nil),      // 31: io.streamnative.oxia.proto.CloseSessionResponse
-	(*NotificationsRequest)(nil),      // 32: io.streamnative.oxia.proto.NotificationsRequest
-	(*NotificationBatch)(nil),         // 33: io.streamnative.oxia.proto.NotificationBatch
-	(*Notification)(nil),              // 34: io.streamnative.oxia.proto.Notification
-	nil,                               // 35: io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry
-	nil,                               // 36: io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry
+	(ShardKeyRouter)(0),                 // 0: io.streamnative.oxia.proto.ShardKeyRouter
+	(KeyComparisonType)(0),              // 1: io.streamnative.oxia.proto.KeyComparisonType
+	(Status)(0),                         // 2: io.streamnative.oxia.proto.Status
+	(NotificationType)(0),               // 3: io.streamnative.oxia.proto.NotificationType
+	(*ShardAssignmentsRequest)(nil),     // 4: io.streamnative.oxia.proto.ShardAssignmentsRequest
+	(*ShardAssignments)(nil),            // 5: io.streamnative.oxia.proto.ShardAssignments
+	(*NamespaceShardsAssignment)(nil),   // 6: io.streamnative.oxia.proto.NamespaceShardsAssignment
+	(*NamespacePolicies)(nil),           // 7: io.streamnative.oxia.proto.NamespacePolicies
+	(*TenantPolicies)(nil),              // 8: io.streamnative.oxia.proto.TenantPolicies
+	(*PrincipalQuota)(nil),              // 9: io.streamnative.oxia.proto.PrincipalQuota
+	(*NamespaceAuthorization)(nil),      // 10: io.streamnative.oxia.proto.NamespaceAuthorization
+	(*PrincipalPatternPermissions)(nil), // 11: io.streamnative.oxia.proto.PrincipalPatternPermissions
+	(*KeyPrefixPermissions)(nil),        // 12: io.streamnative.oxia.proto.KeyPrefixPermissions
+	(*NamespacePermissions)(nil),        // 13: io.streamnative.oxia.proto.NamespacePermissions
+	(*ShardAssignment)(nil),             // 14: io.streamnative.oxia.proto.ShardAssignment
+	(*Int32HashRange)(nil),              // 15: io.streamnative.oxia.proto.Int32HashRange
+	(*KeyRange)(nil),                    // 16: io.streamnative.oxia.proto.KeyRange
+	(*WriteRequest)(nil),                // 17: io.streamnative.oxia.proto.WriteRequest
+	(*WriteResponse)(nil),               // 18: io.streamnative.oxia.proto.WriteResponse
+	(*ReadRequest)(nil),                 // 19: io.streamnative.oxia.proto.ReadRequest
+	(*ReadResponse)(nil),                // 20: io.streamnative.oxia.proto.ReadResponse
+	(*PutRequest)(nil),                  // 21: io.streamnative.oxia.proto.PutRequest
+	(*PutResponse)(nil),                 // 22: io.streamnative.oxia.proto.PutResponse
+	(*DeleteRequest)(nil),               // 23: io.streamnative.oxia.proto.DeleteRequest
+	(*DeleteResponse)(nil),              // 24: io.streamnative.oxia.proto.DeleteResponse
+	(*GetRequest)(nil),                  // 25: io.streamnative.oxia.proto.GetRequest
+	(*GetResponse)(nil),                 // 26: io.streamnative.oxia.proto.GetResponse
+	(*DeleteRangeRequest)(nil),          // 27: io.streamnative.oxia.proto.DeleteRangeRequest
+	(*DeleteRangeResponse)(nil),         // 28: io.streamnative.oxia.proto.DeleteRangeResponse
+	(*ListRequest)(nil),                 // 29: io.streamnative.oxia.proto.ListRequest
+	(*ListResponse)(nil),                // 30: io.streamnative.oxia.proto.ListResponse
+	(*RangeScanRequest)(nil),            // 31: io.streamnative.oxia.proto.RangeScanRequest
+	(*RangeScanResponse)(nil),           // 32: io.streamnative.oxia.proto.RangeScanResponse
+	(*Version)(nil),                     // 33: io.streamnative.oxia.proto.Version
+	(*CreateSessionRequest)(nil),        // 34: io.streamnative.oxia.proto.CreateSessionRequest
+	(*CreateSessionResponse)(nil),       // 35: io.streamnative.oxia.proto.CreateSessionResponse
+	(*SessionHeartbeat)(nil),            // 36: io.streamnative.oxia.proto.SessionHeartbeat
+	(*KeepAliveResponse)(nil),           // 37: io.streamnative.oxia.proto.KeepAliveResponse
+	(*CloseSessionRequest)(nil),         // 38: io.streamnative.oxia.proto.CloseSessionRequest
+	(*CloseSessionResponse)(nil),        // 39: io.streamnative.oxia.proto.CloseSessionResponse
+	(*GrantLeaseRequest)(nil),           // 40: io.streamnative.oxia.proto.GrantLeaseRequest
+	(*GrantLeaseResponse)(nil),          // 41: io.streamnative.oxia.proto.GrantLeaseResponse
+	(*LeaseRequest)(nil),                // 42: io.streamnative.oxia.proto.LeaseRequest
+	(*RefreshLeaseResponse)(nil),        // 43: io.streamnative.oxia.proto.RefreshLeaseResponse
+	(*RevokeLeaseResponse)(nil),         // 44: io.streamnative.oxia.proto.RevokeLeaseResponse
+	(*NotificationsRequest)(nil),        // 45: io.streamnative.oxia.proto.NotificationsRequest
+	(*AckNotificationsRequest)(nil),     // 46: io.streamnative.oxia.proto.AckNotificationsRequest
+	(*AckNotificationsResponse)(nil),    // 47: io.streamnative.oxia.proto.AckNotificationsResponse
+	(*NotificationBatch)(nil),           // 48: io.streamnative.oxia.proto.NotificationBatch
+	(*Notification)(nil),                // 49: io.streamnative.oxia.proto.Notification
+	nil,                                 // 50: io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry
+	nil,                                 // 51: io.streamnative.oxia.proto.NamespacePolicies.PrincipalQuotasEntry
+	nil,                                 // 52: io.streamnative.oxia.proto.NamespaceAuthorization.PrincipalsEntry
+	nil,                                 // 53: io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry
 }
 var file_client_proto_depIdxs = []int32{
-	35, // 0: io.streamnative.oxia.proto.ShardAssignments.namespaces:type_name -> io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry
-	7,  // 1: io.streamnative.oxia.proto.NamespaceShardsAssignment.assignments:type_name -> io.streamnative.oxia.proto.ShardAssignment
+	50, // 0: io.streamnative.oxia.proto.ShardAssignments.namespaces:type_name -> io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry
+	14, // 1: io.streamnative.oxia.proto.NamespaceShardsAssignment.assignments:type_name -> io.streamnative.oxia.proto.ShardAssignment
 	0,  // 2: io.streamnative.oxia.proto.NamespaceShardsAssignment.shard_key_router:type_name -> io.streamnative.oxia.proto.ShardKeyRouter
-	8,  // 3: io.streamnative.oxia.proto.ShardAssignment.int32_hash_range:type_name -> io.streamnative.oxia.proto.Int32HashRange
-	13, // 4: io.streamnative.oxia.proto.WriteRequest.puts:type_name -> io.streamnative.oxia.proto.PutRequest
-	15, // 5: io.streamnative.oxia.proto.WriteRequest.deletes:type_name -> io.streamnative.oxia.proto.DeleteRequest
-	19, // 6: io.streamnative.oxia.proto.WriteRequest.delete_ranges:type_name -> io.streamnative.oxia.proto.DeleteRangeRequest
-	14, // 7: io.streamnative.oxia.proto.WriteResponse.puts:type_name -> io.streamnative.oxia.proto.PutResponse
-	16, // 8: io.streamnative.oxia.proto.WriteResponse.deletes:type_name -> io.streamnative.oxia.proto.DeleteResponse
-	20, // 9: io.streamnative.oxia.proto.WriteResponse.delete_ranges:type_name -> io.streamnative.oxia.proto.DeleteRangeResponse
-	17, // 10: io.streamnative.oxia.proto.ReadRequest.gets:type_name -> io.streamnative.oxia.proto.GetRequest
-	18, // 11: io.streamnative.oxia.proto.ReadResponse.gets:type_name -> io.streamnative.oxia.proto.GetResponse
-	2,  // 12: io.streamnative.oxia.proto.PutResponse.status:type_name -> io.streamnative.oxia.proto.Status
-	25, // 13: io.streamnative.oxia.proto.PutResponse.version:type_name -> io.streamnative.oxia.proto.Version
-	2,  // 14: io.streamnative.oxia.proto.DeleteResponse.status:type_name -> io.streamnative.oxia.proto.Status
-	1,  // 15: io.streamnative.oxia.proto.GetRequest.comparison_type:type_name -> io.streamnative.oxia.proto.KeyComparisonType
-	2,  // 16: io.streamnative.oxia.proto.GetResponse.status:type_name -> io.streamnative.oxia.proto.Status
-	25, // 17: io.streamnative.oxia.proto.GetResponse.version:type_name -> io.streamnative.oxia.proto.Version
-	2,  // 18: io.streamnative.oxia.proto.DeleteRangeResponse.status:type_name -> io.streamnative.oxia.proto.Status
-	18, // 19: io.streamnative.oxia.proto.RangeScanResponse.records:type_name -> io.streamnative.oxia.proto.GetResponse
-	36, // 20: io.streamnative.oxia.proto.NotificationBatch.notifications:type_name -> io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry
-	3,  // 21: io.streamnative.oxia.proto.Notification.type:type_name -> io.streamnative.oxia.proto.NotificationType
-	6,  // 22: io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry.value:type_name -> io.streamnative.oxia.proto.NamespaceShardsAssignment
-	34, // 23: io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry.value:type_name -> io.streamnative.oxia.proto.Notification
-	4,  // 24: io.streamnative.oxia.proto.OxiaClient.GetShardAssignments:input_type -> io.streamnative.oxia.proto.ShardAssignmentsRequest
-	9,  // 25: io.streamnative.oxia.proto.OxiaClient.Write:input_type -> io.streamnative.oxia.proto.WriteRequest
-	9,  // 26: io.streamnative.oxia.proto.OxiaClient.WriteStream:input_type -> io.streamnative.oxia.proto.WriteRequest
-	11, // 27: io.streamnative.oxia.proto.OxiaClient.Read:input_type -> io.streamnative.oxia.proto.ReadRequest
-	21, // 28: io.streamnative.oxia.proto.OxiaClient.List:input_type -> io.streamnative.oxia.proto.ListRequest
-	23, // 29: io.streamnative.oxia.proto.OxiaClient.RangeScan:input_type -> io.streamnative.oxia.proto.RangeScanRequest
-	32, // 30: io.streamnative.oxia.proto.OxiaClient.GetNotifications:input_type -> io.streamnative.oxia.proto.NotificationsRequest
-	26, // 31: io.streamnative.oxia.proto.OxiaClient.CreateSession:input_type -> io.streamnative.oxia.proto.CreateSessionRequest
-	28, // 32: io.streamnative.oxia.proto.OxiaClient.KeepAlive:input_type -> io.streamnative.oxia.proto.SessionHeartbeat
-	30, // 33: io.streamnative.oxia.proto.OxiaClient.CloseSession:input_type -> io.streamnative.oxia.proto.CloseSessionRequest
-	5,  // 34: io.streamnative.oxia.proto.OxiaClient.GetShardAssignments:output_type -> io.streamnative.oxia.proto.ShardAssignments
-	10, // 35: io.streamnative.oxia.proto.OxiaClient.Write:output_type -> io.streamnative.oxia.proto.WriteResponse
-	10, // 36: io.streamnative.oxia.proto.OxiaClient.WriteStream:output_type -> io.streamnative.oxia.proto.WriteResponse
-	12, // 37: io.streamnative.oxia.proto.OxiaClient.Read:output_type -> io.streamnative.oxia.proto.ReadResponse
-	22, // 38: io.streamnative.oxia.proto.OxiaClient.List:output_type -> io.streamnative.oxia.proto.ListResponse
-	24, // 39: io.streamnative.oxia.proto.OxiaClient.RangeScan:output_type -> io.streamnative.oxia.proto.RangeScanResponse
-	33, // 40: io.streamnative.oxia.proto.OxiaClient.GetNotifications:output_type -> io.streamnative.oxia.proto.NotificationBatch
-	27, // 41: io.streamnative.oxia.proto.OxiaClient.CreateSession:output_type -> io.streamnative.oxia.proto.CreateSessionResponse
-	29, // 42: io.streamnative.oxia.proto.OxiaClient.KeepAlive:output_type -> io.streamnative.oxia.proto.KeepAliveResponse
-	31, // 43: io.streamnative.oxia.proto.OxiaClient.CloseSession:output_type -> io.streamnative.oxia.proto.CloseSessionResponse
-	34, // [34:44] is the sub-list for method output_type
-	24, // [24:34] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	7,  // 3: io.streamnative.oxia.proto.NamespaceShardsAssignment.policies:type_name -> io.streamnative.oxia.proto.NamespacePolicies
+	10, // 4: io.streamnative.oxia.proto.NamespacePolicies.authorization:type_name -> io.streamnative.oxia.proto.NamespaceAuthorization
+	51, // 5: io.streamnative.oxia.proto.NamespacePolicies.principal_quotas:type_name -> io.streamnative.oxia.proto.NamespacePolicies.PrincipalQuotasEntry
+	8,  // 6: io.streamnative.oxia.proto.NamespacePolicies.tenant:type_name -> io.streamnative.oxia.proto.TenantPolicies
+	52, // 7: io.streamnative.oxia.proto.NamespaceAuthorization.principals:type_name -> io.streamnative.oxia.proto.NamespaceAuthorization.PrincipalsEntry
+	12, // 8: io.streamnative.oxia.proto.NamespaceAuthorization.key_prefixes:type_name -> io.streamnative.oxia.proto.KeyPrefixPermissions
+	11, // 9: io.streamnative.oxia.proto.NamespaceAuthorization.principal_patterns:type_name -> io.streamnative.oxia.proto.PrincipalPatternPermissions
+	13, // 10: io.streamnative.oxia.proto.PrincipalPatternPermissions.permissions:type_name -> io.streamnative.oxia.proto.NamespacePermissions
+	13, // 11: io.streamnative.oxia.proto.KeyPrefixPermissions.permissions:type_name -> io.streamnative.oxia.proto.NamespacePermissions
+	15, // 12: io.streamnative.oxia.proto.ShardAssignment.int32_hash_range:type_name -> io.streamnative.oxia.proto.Int32HashRange
+	16, // 13: io.streamnative.oxia.proto.ShardAssignment.key_range:type_name -> io.streamnative.oxia.proto.KeyRange
+	21, // 14: io.streamnative.oxia.proto.WriteRequest.puts:type_name -> io.streamnative.oxia.proto.PutRequest
+	23, // 15: io.streamnative.oxia.proto.WriteRequest.deletes:type_name -> io.streamnative.oxia.proto.DeleteRequest
+	27, // 16: io.streamnative.oxia.proto.WriteRequest.delete_ranges:type_name -> io.streamnative.oxia.proto.DeleteRangeRequest
+	22, // 17: io.streamnative.oxia.proto.WriteResponse.puts:type_name -> io.streamnative.oxia.proto.PutResponse
+	24, // 18: io.streamnative.oxia.proto.WriteResponse.deletes:type_name -> io.streamnative.oxia.proto.DeleteResponse
+	28, // 19: io.streamnative.oxia.proto.WriteResponse.delete_ranges:type_name -> io.streamnative.oxia.proto.DeleteRangeResponse
+	2,  // 20: io.streamnative.oxia.proto.WriteResponse.merge_status:type_name -> io.streamnative.oxia.proto.Status
+	25, // 21: io.streamnative.oxia.proto.ReadRequest.gets:type_name -> io.streamnative.oxia.proto.GetRequest
+	26, // 22: io.streamnative.oxia.proto.ReadResponse.gets:type_name -> io.streamnative.oxia.proto.GetResponse
+	2,  // 23: io.streamnative.oxia.proto.PutResponse.status:type_name -> io.streamnative.oxia.proto.Status
+	33, // 24: io.streamnative.oxia.proto.PutResponse.version:type_name -> io.streamnative.oxia.proto.Version
+	2,  // 25: io.streamnative.oxia.proto.DeleteResponse.status:type_name -> io.streamnative.oxia.proto.Status
+	1,  // 26: io.streamnative.oxia.proto.GetRequest.comparison_type:type_name -> io.streamnative.oxia.proto.KeyComparisonType
+	2,  // 27: io.streamnative.oxia.proto.GetResponse.status:type_name -> io.streamnative.oxia.proto.Status
+	33, // 28: io.streamnative.oxia.proto.GetResponse.version:type_name -> io.streamnative.oxia.proto.Version
+	2,  // 29: io.streamnative.oxia.proto.DeleteRangeResponse.status:type_name -> io.streamnative.oxia.proto.Status
+	26, // 30: io.streamnative.oxia.proto.RangeScanResponse.records:type_name -> io.streamnative.oxia.proto.GetResponse
+	3,  // 31: io.streamnative.oxia.proto.NotificationsRequest.types:type_name -> io.streamnative.oxia.proto.NotificationType
+	53, // 32: io.streamnative.oxia.proto.NotificationBatch.notifications:type_name -> io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry
+	3,  // 33: io.streamnative.oxia.proto.Notification.type:type_name -> io.streamnative.oxia.proto.NotificationType
+	6,  // 34: io.streamnative.oxia.proto.ShardAssignments.NamespacesEntry.value:type_name -> io.streamnative.oxia.proto.NamespaceShardsAssignment
+	9,  // 35: io.streamnative.oxia.proto.NamespacePolicies.PrincipalQuotasEntry.value:type_name -> io.streamnative.oxia.proto.PrincipalQuota
+	13, // 36: io.streamnative.oxia.proto.NamespaceAuthorization.PrincipalsEntry.value:type_name -> io.streamnative.oxia.proto.NamespacePermissions
+	49, // 37: io.streamnative.oxia.proto.NotificationBatch.NotificationsEntry.value:type_name -> io.streamnative.oxia.proto.Notification
+	4,  // 38: io.streamnative.oxia.proto.OxiaClient.GetShardAssignments:input_type -> io.streamnative.oxia.proto.ShardAssignmentsRequest
+	17, // 39: io.streamnative.oxia.proto.OxiaClient.Write:input_type -> io.streamnative.oxia.proto.WriteRequest
+	17, // 40: io.streamnative.oxia.proto.OxiaClient.WriteStream:input_type -> io.streamnative.oxia.proto.WriteRequest
+	19, // 41: io.streamnative.oxia.proto.OxiaClient.Read:input_type -> io.streamnative.oxia.proto.ReadRequest
+	29, // 42: io.streamnative.oxia.proto.OxiaClient.List:input_type -> io.streamnative.oxia.proto.ListRequest
+	31, // 43: io.streamnative.oxia.proto.OxiaClient.RangeScan:input_type -> io.streamnative.oxia.proto.RangeScanRequest
+	45, // 44: io.streamnative.oxia.proto.OxiaClient.GetNotifications:input_type -> io.streamnative.oxia.proto.NotificationsRequest
+	46, // 45: io.streamnative.oxia.proto.OxiaClient.AckNotifications:input_type -> io.streamnative.oxia.proto.AckNotificationsRequest
+	34, // 46: io.streamnative.oxia.proto.OxiaClient.CreateSession:input_type -> io.streamnative.oxia.proto.CreateSessionRequest
+	36, // 47: io.streamnative.oxia.proto.OxiaClient.KeepAlive:input_type -> io.streamnative.oxia.proto.SessionHeartbeat
+	38, // 48: io.streamnative.oxia.proto.OxiaClient.CloseSession:input_type -> io.streamnative.oxia.proto.CloseSessionRequest
+	40, // 49: io.streamnative.oxia.proto.OxiaClient.GrantLease:input_type -> io.streamnative.oxia.proto.GrantLeaseRequest
+	42, // 50: io.streamnative.oxia.proto.OxiaClient.RefreshLease:input_type -> io.streamnative.oxia.proto.LeaseRequest
+	42, // 51: io.streamnative.oxia.proto.OxiaClient.RevokeLease:input_type -> io.streamnative.oxia.proto.LeaseRequest
+	5,  // 52: io.streamnative.oxia.proto.OxiaClient.GetShardAssignments:output_type -> io.streamnative.oxia.proto.ShardAssignments
+	18, // 53: io.streamnative.oxia.proto.OxiaClient.Write:output_type -> io.streamnative.oxia.proto.WriteResponse
+	18, // 54: io.streamnative.oxia.proto.OxiaClient.WriteStream:output_type -> io.streamnative.oxia.proto.WriteResponse
+	20, // 55: io.streamnative.oxia.proto.OxiaClient.Read:output_type -> io.streamnative.oxia.proto.ReadResponse
+	30, // 56: io.streamnative.oxia.proto.OxiaClient.List:output_type -> io.streamnative.oxia.proto.ListResponse
+	32, // 57: io.streamnative.oxia.proto.OxiaClient.RangeScan:output_type -> io.streamnative.oxia.proto.RangeScanResponse
+	48, // 58: io.streamnative.oxia.proto.OxiaClient.GetNotifications:output_type -> io.streamnative.oxia.proto.NotificationBatch
+	47, // 59: io.streamnative.oxia.proto.OxiaClient.AckNotifications:output_type -> io.streamnative.oxia.proto.AckNotificationsResponse
+	35, // 60: io.streamnative.oxia.proto.OxiaClient.CreateSession:output_type -> io.streamnative.oxia.proto.CreateSessionResponse
+	37, // 61: io.streamnative.oxia.proto.OxiaClient.KeepAlive:output_type -> io.streamnative.oxia.proto.KeepAliveResponse
+	39, // 62: io.streamnative.oxia.proto.OxiaClient.CloseSession:output_type -> io.streamnative.oxia.proto.CloseSessionResponse
+	41, // 63: io.streamnative.oxia.proto.OxiaClient.GrantLease:output_type -> io.streamnative.oxia.proto.GrantLeaseResponse
+	43, // 64: io.streamnative.oxia.proto.OxiaClient.RefreshLease:output_type -> io.streamnative.oxia.proto.RefreshLeaseResponse
+	44, // 65: io.streamnative.oxia.proto.OxiaClient.RevokeLease:output_type -> io.streamnative.oxia.proto.RevokeLeaseResponse
+	52, // [52:66] is the sub-list for method output_type
+	38, // [38:52] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_client_proto_init() }
@@ -2706,7 +4112,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShardAssignment); i {
+			switch v := v.(*NamespacePolicies); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2718,7 +4124,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Int32HashRange); i {
+			switch v := v.(*TenantPolicies); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2730,7 +4136,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteRequest); i {
+			switch v := v.(*PrincipalQuota); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2742,7 +4148,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WriteResponse); i {
+			switch v := v.(*NamespaceAuthorization); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2754,7 +4160,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadRequest); i {
+			switch v := v.(*PrincipalPatternPermissions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2766,7 +4172,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadResponse); i {
+			switch v := v.(*KeyPrefixPermissions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2778,7 +4184,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PutRequest); i {
+			switch v := v.(*NamespacePermissions); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2790,7 +4196,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PutResponse); i {
+			switch v := v.(*ShardAssignment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2802,7 +4208,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteRequest); i {
+			switch v := v.(*Int32HashRange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2814,7 +4220,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteResponse); i {
+			switch v := v.(*KeyRange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2826,7 +4232,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRequest); i {
+			switch v := v.(*WriteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2838,7 +4244,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetResponse); i {
+			switch v := v.(*WriteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2850,7 +4256,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteRangeRequest); i {
+			switch v := v.(*ReadRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2862,7 +4268,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteRangeResponse); i {
+			switch v := v.(*ReadResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2874,7 +4280,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRequest); i {
+			switch v := v.(*PutRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2886,7 +4292,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListResponse); i {
+			switch v := v.(*PutResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2898,7 +4304,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RangeScanRequest); i {
+			switch v := v.(*DeleteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2910,7 +4316,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RangeScanResponse); i {
+			switch v := v.(*DeleteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2922,7 +4328,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Version); i {
+			switch v := v.(*GetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2934,7 +4340,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateSessionRequest); i {
+			switch v := v.(*GetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2946,7 +4352,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateSessionResponse); i {
+			switch v := v.(*DeleteRangeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2958,7 +4364,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionHeartbeat); i {
+			switch v := v.(*DeleteRangeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2970,7 +4376,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeepAliveResponse); i {
+			switch v := v.(*ListRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2982,7 +4388,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CloseSessionRequest); i {
+			switch v := v.(*ListResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2994,7 +4400,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CloseSessionResponse); i {
+			switch v := v.(*RangeScanRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3006,7 +4412,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationsRequest); i {
+			switch v := v.(*RangeScanResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3018,7 +4424,7 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NotificationBatch); i {
+			switch v := v.(*Version); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3030,6 +4436,186 @@ func file_client_proto_init() {
 			}
 		}
 		file_client_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateSessionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionHeartbeat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeepAliveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloseSessionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrantLeaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GrantLeaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RefreshLeaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeLeaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AckNotificationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AckNotificationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Notification); i {
 			case 0:
 				return &v.state
@@ -3042,27 +4628,31 @@ func file_client_proto_init() {
 			}
 		}
 	}
-	file_client_proto_msgTypes[3].OneofWrappers = []interface{}{
+	file_client_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*ShardAssignment_Int32HashRange)(nil),
+		(*ShardAssignment_KeyRange)(nil),
 	}
-	file_client_proto_msgTypes[5].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[7].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[9].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[10].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[13].OneofWrappers = []interface{}{}
 	file_client_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[15].OneofWrappers = []interface{}{}
 	file_client_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[18].OneofWrappers = []interface{}{}
 	file_client_proto_msgTypes[19].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[21].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[28].OneofWrappers = []interface{}{}
-	file_client_proto_msgTypes[30].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[22].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[25].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[27].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[29].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[38].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[41].OneofWrappers = []interface{}{}
+	file_client_proto_msgTypes[45].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_client_proto_rawDesc,
 			NumEnums:      4,
-			NumMessages:   33,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
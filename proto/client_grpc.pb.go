@@ -65,6 +65,10 @@ type OxiaClientClient interface {
 	// unless a particular partition key was specified.
 	RangeScan(ctx context.Context, in *RangeScanRequest, opts ...grpc.CallOption) (OxiaClient_RangeScanClient, error)
 	GetNotifications(ctx context.Context, in *NotificationsRequest, opts ...grpc.CallOption) (OxiaClient_GetNotificationsClient, error)
+	// Stores the offset of the last notification processed by a named
+	// consumer, so that its notifications resume after it when the consumer
+	// subscribes again.
+	AckNotifications(ctx context.Context, in *AckNotificationsRequest, opts ...grpc.CallOption) (*AckNotificationsResponse, error)
 	// Creates a new client session. Sessions are kept alive by regularly sending
 	// heartbeats via the KeepAlive rpc.
 	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
@@ -72,6 +76,17 @@ type OxiaClientClient interface {
 	KeepAlive(ctx context.Context, in *SessionHeartbeat, opts ...grpc.CallOption) (*KeepAliveResponse, error)
 	// Closes a session and removes all ephemeral values associated with it.
 	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error)
+	//
+	// Grants a lease: a named expiration that the records of the shard can be
+	// attached to, as they are to a session. All the clients granting a lease
+	// with the same name share it, and any of them can refresh or revoke it.
+	GrantLease(ctx context.Context, in *GrantLeaseRequest, opts ...grpc.CallOption) (*GrantLeaseResponse, error)
+	//
+	// Extends a lease by its TTL, to prevent it from expiring.
+	RefreshLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*RefreshLeaseResponse, error)
+	//
+	// Revokes a lease and removes all the records attached to it.
+	RevokeLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*RevokeLeaseResponse, error)
 }
 
 type oxiaClientClient struct {
@@ -282,6 +297,15 @@ func (x *oxiaClientGetNotificationsClient) Recv() (*NotificationBatch, error) {
 	return m, nil
 }
 
+func (c *oxiaClientClient) AckNotifications(ctx context.Context, in *AckNotificationsRequest, opts ...grpc.CallOption) (*AckNotificationsResponse, error) {
+	out := new(AckNotificationsResponse)
+	err := c.cc.Invoke(ctx, "/io.streamnative.oxia.proto.OxiaClient/AckNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *oxiaClientClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
 	out := new(CreateSessionResponse)
 	err := c.cc.Invoke(ctx, "/io.streamnative.oxia.proto.OxiaClient/CreateSession", in, out, opts...)
@@ -309,6 +333,33 @@ func (c *oxiaClientClient) CloseSession(ctx context.Context, in *CloseSessionReq
 	return out, nil
 }
 
+func (c *oxiaClientClient) GrantLease(ctx context.Context, in *GrantLeaseRequest, opts ...grpc.CallOption) (*GrantLeaseResponse, error) {
+	out := new(GrantLeaseResponse)
+	err := c.cc.Invoke(ctx, "/io.streamnative.oxia.proto.OxiaClient/GrantLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaClientClient) RefreshLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*RefreshLeaseResponse, error) {
+	out := new(RefreshLeaseResponse)
+	err := c.cc.Invoke(ctx, "/io.streamnative.oxia.proto.OxiaClient/RefreshLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaClientClient) RevokeLease(ctx context.Context, in *LeaseRequest, opts ...grpc.CallOption) (*RevokeLeaseResponse, error) {
+	out := new(RevokeLeaseResponse)
+	err := c.cc.Invoke(ctx, "/io.streamnative.oxia.proto.OxiaClient/RevokeLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OxiaClientServer is the server API for OxiaClient service.
 // All implementations must embed UnimplementedOxiaClientServer
 // for forward compatibility
@@ -356,6 +407,10 @@ type OxiaClientServer interface {
 	// unless a particular partition key was specified.
 	RangeScan(*RangeScanRequest, OxiaClient_RangeScanServer) error
 	GetNotifications(*NotificationsRequest, OxiaClient_GetNotificationsServer) error
+	// Stores the offset of the last notification processed by a named
+	// consumer, so that its notifications resume after it when the consumer
+	// subscribes again.
+	AckNotifications(context.Context, *AckNotificationsRequest) (*AckNotificationsResponse, error)
 	// Creates a new client session. Sessions are kept alive by regularly sending
 	// heartbeats via the KeepAlive rpc.
 	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
@@ -363,6 +418,17 @@ type OxiaClientServer interface {
 	KeepAlive(context.Context, *SessionHeartbeat) (*KeepAliveResponse, error)
 	// Closes a session and removes all ephemeral values associated with it.
 	CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error)
+	//
+	// Grants a lease: a named expiration that the records of the shard can be
+	// attached to, as they are to a session. All the clients granting a lease
+	// with the same name share it, and any of them can refresh or revoke it.
+	GrantLease(context.Context, *GrantLeaseRequest) (*GrantLeaseResponse, error)
+	//
+	// Extends a lease by its TTL, to prevent it from expiring.
+	RefreshLease(context.Context, *LeaseRequest) (*RefreshLeaseResponse, error)
+	//
+	// Revokes a lease and removes all the records attached to it.
+	RevokeLease(context.Context, *LeaseRequest) (*RevokeLeaseResponse, error)
 	mustEmbedUnimplementedOxiaClientServer()
 }
 
@@ -391,6 +457,9 @@ func (UnimplementedOxiaClientServer) RangeScan(*RangeScanRequest, OxiaClient_Ran
 func (UnimplementedOxiaClientServer) GetNotifications(*NotificationsRequest, OxiaClient_GetNotificationsServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetNotifications not implemented")
 }
+func (UnimplementedOxiaClientServer) AckNotifications(context.Context, *AckNotificationsRequest) (*AckNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckNotifications not implemented")
+}
 func (UnimplementedOxiaClientServer) CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
 }
@@ -400,6 +469,15 @@ func (UnimplementedOxiaClientServer) KeepAlive(context.Context, *SessionHeartbea
 func (UnimplementedOxiaClientServer) CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
 }
+func (UnimplementedOxiaClientServer) GrantLease(context.Context, *GrantLeaseRequest) (*GrantLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantLease not implemented")
+}
+func (UnimplementedOxiaClientServer) RefreshLease(context.Context, *LeaseRequest) (*RefreshLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshLease not implemented")
+}
+func (UnimplementedOxiaClientServer) RevokeLease(context.Context, *LeaseRequest) (*RevokeLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeLease not implemented")
+}
 func (UnimplementedOxiaClientServer) mustEmbedUnimplementedOxiaClientServer() {}
 
 // UnsafeOxiaClientServer may be embedded to opt out of forward compatibility for this service.
@@ -562,6 +640,24 @@ func (x *oxiaClientGetNotificationsServer) Send(m *NotificationBatch) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _OxiaClient_AckNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaClientServer).AckNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/io.streamnative.oxia.proto.OxiaClient/AckNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaClientServer).AckNotifications(ctx, req.(*AckNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OxiaClient_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateSessionRequest)
 	if err := dec(in); err != nil {
@@ -616,6 +712,60 @@ func _OxiaClient_CloseSession_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OxiaClient_GrantLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaClientServer).GrantLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/io.streamnative.oxia.proto.OxiaClient/GrantLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaClientServer).GrantLease(ctx, req.(*GrantLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaClient_RefreshLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaClientServer).RefreshLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/io.streamnative.oxia.proto.OxiaClient/RefreshLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaClientServer).RefreshLease(ctx, req.(*LeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaClient_RevokeLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaClientServer).RevokeLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/io.streamnative.oxia.proto.OxiaClient/RevokeLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaClientServer).RevokeLease(ctx, req.(*LeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OxiaClient_ServiceDesc is the grpc.ServiceDesc for OxiaClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -627,6 +777,10 @@ var OxiaClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Write",
 			Handler:    _OxiaClient_Write_Handler,
 		},
+		{
+			MethodName: "AckNotifications",
+			Handler:    _OxiaClient_AckNotifications_Handler,
+		},
 		{
 			MethodName: "CreateSession",
 			Handler:    _OxiaClient_CreateSession_Handler,
@@ -639,6 +793,18 @@ var OxiaClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CloseSession",
 			Handler:    _OxiaClient_CloseSession_Handler,
 		},
+		{
+			MethodName: "GrantLease",
+			Handler:    _OxiaClient_GrantLease_Handler,
+		},
+		{
+			MethodName: "RefreshLease",
+			Handler:    _OxiaClient_RefreshLease_Handler,
+		},
+		{
+			MethodName: "RevokeLease",
+			Handler:    _OxiaClient_RevokeLease_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -31,12 +31,21 @@ type vtprotoMessage interface {
 	UnmarshalVT([]byte) error
 }
 
+// The messages generated with gogo protobuf, like the ones of the etcd client
+// used by the coordinator metadata
+type gogoMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
 func (vtprotoCodec) Marshal(v any) ([]byte, error) {
 	switch v := v.(type) {
 	case vtprotoMessage:
 		return v.MarshalVT()
 	case pb.Message:
 		return pb.Marshal(v)
+	case gogoMessage:
+		return v.Marshal()
 	default:
 		return nil, fmt.Errorf("failed to marshal, message is %T, must satisfy the vtprotoMessage interface or want proto.Message", v)
 	}
@@ -48,6 +57,8 @@ func (vtprotoCodec) Unmarshal(data []byte, v any) error {
 		return v.UnmarshalVT(data)
 	case pb.Message:
 		return pb.Unmarshal(data, v)
+	case gogoMessage:
+		return v.Unmarshal(data)
 	default:
 		return fmt.Errorf("failed to unmarshal, message is %T, must satisfy the vtprotoMessage interface or want proto.Message", v)
 	}
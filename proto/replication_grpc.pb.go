@@ -28,6 +28,16 @@ type OxiaCoordinationClient interface {
 	AddFollower(ctx context.Context, in *AddFollowerRequest, opts ...grpc.CallOption) (*AddFollowerResponse, error)
 	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
 	DeleteShard(ctx context.Context, in *DeleteShardRequest, opts ...grpc.CallOption) (*DeleteShardResponse, error)
+	// List the sessions that are active on the shard leader
+	ListShardSessions(ctx context.Context, in *ListShardSessionsRequest, opts ...grpc.CallOption) (*ListShardSessionsResponse, error)
+	// Close a session on the shard leader, deleting its ephemeral records
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error)
+	// Read the records of a replica of a shard that was fenced to be merged
+	// into an adjacent shard
+	ExportRecords(ctx context.Context, in *ExportRecordsRequest, opts ...grpc.CallOption) (OxiaCoordination_ExportRecordsClient, error)
+	// Write the records of a merged shard through the leader of the shard
+	// that takes over its range
+	ImportRecords(ctx context.Context, in *ImportRecordsRequest, opts ...grpc.CallOption) (*ImportRecordsResponse, error)
 }
 
 type oxiaCoordinationClient struct {
@@ -117,6 +127,65 @@ func (c *oxiaCoordinationClient) DeleteShard(ctx context.Context, in *DeleteShar
 	return out, nil
 }
 
+func (c *oxiaCoordinationClient) ListShardSessions(ctx context.Context, in *ListShardSessionsRequest, opts ...grpc.CallOption) (*ListShardSessionsResponse, error) {
+	out := new(ListShardSessionsResponse)
+	err := c.cc.Invoke(ctx, "/replication.OxiaCoordination/ListShardSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaCoordinationClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error) {
+	out := new(CloseSessionResponse)
+	err := c.cc.Invoke(ctx, "/replication.OxiaCoordination/CloseSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oxiaCoordinationClient) ExportRecords(ctx context.Context, in *ExportRecordsRequest, opts ...grpc.CallOption) (OxiaCoordination_ExportRecordsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OxiaCoordination_ServiceDesc.Streams[1], "/replication.OxiaCoordination/ExportRecords", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &oxiaCoordinationExportRecordsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OxiaCoordination_ExportRecordsClient interface {
+	Recv() (*ExportRecordsResponse, error)
+	grpc.ClientStream
+}
+
+type oxiaCoordinationExportRecordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *oxiaCoordinationExportRecordsClient) Recv() (*ExportRecordsResponse, error) {
+	m := new(ExportRecordsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *oxiaCoordinationClient) ImportRecords(ctx context.Context, in *ImportRecordsRequest, opts ...grpc.CallOption) (*ImportRecordsResponse, error) {
+	out := new(ImportRecordsResponse)
+	err := c.cc.Invoke(ctx, "/replication.OxiaCoordination/ImportRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OxiaCoordinationServer is the server API for OxiaCoordination service.
 // All implementations must embed UnimplementedOxiaCoordinationServer
 // for forward compatibility
@@ -127,6 +196,16 @@ type OxiaCoordinationServer interface {
 	AddFollower(context.Context, *AddFollowerRequest) (*AddFollowerResponse, error)
 	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
 	DeleteShard(context.Context, *DeleteShardRequest) (*DeleteShardResponse, error)
+	// List the sessions that are active on the shard leader
+	ListShardSessions(context.Context, *ListShardSessionsRequest) (*ListShardSessionsResponse, error)
+	// Close a session on the shard leader, deleting its ephemeral records
+	CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error)
+	// Read the records of a replica of a shard that was fenced to be merged
+	// into an adjacent shard
+	ExportRecords(*ExportRecordsRequest, OxiaCoordination_ExportRecordsServer) error
+	// Write the records of a merged shard through the leader of the shard
+	// that takes over its range
+	ImportRecords(context.Context, *ImportRecordsRequest) (*ImportRecordsResponse, error)
 	mustEmbedUnimplementedOxiaCoordinationServer()
 }
 
@@ -152,6 +231,18 @@ func (UnimplementedOxiaCoordinationServer) GetStatus(context.Context, *GetStatus
 func (UnimplementedOxiaCoordinationServer) DeleteShard(context.Context, *DeleteShardRequest) (*DeleteShardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteShard not implemented")
 }
+func (UnimplementedOxiaCoordinationServer) ListShardSessions(context.Context, *ListShardSessionsRequest) (*ListShardSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShardSessions not implemented")
+}
+func (UnimplementedOxiaCoordinationServer) CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedOxiaCoordinationServer) ExportRecords(*ExportRecordsRequest, OxiaCoordination_ExportRecordsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportRecords not implemented")
+}
+func (UnimplementedOxiaCoordinationServer) ImportRecords(context.Context, *ImportRecordsRequest) (*ImportRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportRecords not implemented")
+}
 func (UnimplementedOxiaCoordinationServer) mustEmbedUnimplementedOxiaCoordinationServer() {}
 
 // UnsafeOxiaCoordinationServer may be embedded to opt out of forward compatibility for this service.
@@ -281,6 +372,81 @@ func _OxiaCoordination_DeleteShard_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OxiaCoordination_ListShardSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListShardSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaCoordinationServer).ListShardSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/replication.OxiaCoordination/ListShardSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaCoordinationServer).ListShardSessions(ctx, req.(*ListShardSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaCoordination_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaCoordinationServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/replication.OxiaCoordination/CloseSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaCoordinationServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OxiaCoordination_ExportRecords_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRecordsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OxiaCoordinationServer).ExportRecords(m, &oxiaCoordinationExportRecordsServer{stream})
+}
+
+type OxiaCoordination_ExportRecordsServer interface {
+	Send(*ExportRecordsResponse) error
+	grpc.ServerStream
+}
+
+type oxiaCoordinationExportRecordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *oxiaCoordinationExportRecordsServer) Send(m *ExportRecordsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OxiaCoordination_ImportRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OxiaCoordinationServer).ImportRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/replication.OxiaCoordination/ImportRecords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OxiaCoordinationServer).ImportRecords(ctx, req.(*ImportRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OxiaCoordination_ServiceDesc is the grpc.ServiceDesc for OxiaCoordination service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -308,6 +474,18 @@ var OxiaCoordination_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteShard",
 			Handler:    _OxiaCoordination_DeleteShard_Handler,
 		},
+		{
+			MethodName: "ListShardSessions",
+			Handler:    _OxiaCoordination_ListShardSessions_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _OxiaCoordination_CloseSession_Handler,
+		},
+		{
+			MethodName: "ImportRecords",
+			Handler:    _OxiaCoordination_ImportRecords_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -315,6 +493,11 @@ var OxiaCoordination_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _OxiaCoordination_PushShardAssignments_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExportRecords",
+			Handler:       _OxiaCoordination_ExportRecords_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "replication.proto",
 }
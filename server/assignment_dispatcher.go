@@ -46,6 +46,10 @@ type ShardAssignmentsDispatcher interface {
 	Initialized() bool
 	PushShardAssignments(stream proto.OxiaCoordination_PushShardAssignmentsServer) error
 	RegisterForUpdates(req *proto.ShardAssignmentsRequest, client Client) error
+
+	// OnNamespacePoliciesUpdate registers the function called with the
+	// policies of all the namespaces, every time the assignments are received
+	OnNamespacePoliciesUpdate(listener func(map[string]*proto.NamespacePolicies))
 }
 
 type shardAssignmentDispatcher struct {
@@ -56,6 +60,8 @@ type shardAssignmentDispatcher struct {
 	standalone   bool
 	healthServer *health.Server
 
+	policiesListener func(map[string]*proto.NamespacePolicies)
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -223,7 +229,6 @@ func (s *shardAssignmentDispatcher) updateShardAssignment(assignments *proto.Sha
 	s.healthServer.SetServingStatus(container.ReadinessProbeService, grpc_health_v1.HealthCheckResponse_SERVING)
 
 	s.Lock()
-	defer s.Unlock()
 
 	s.assignments = assignments
 
@@ -240,9 +245,28 @@ func (s *shardAssignmentDispatcher) updateShardAssignment(assignments *proto.Sha
 		}
 	}
 
+	listener := s.policiesListener
+	s.Unlock()
+
+	if listener != nil {
+		policies := make(map[string]*proto.NamespacePolicies)
+		for ns, nsa := range assignments.Namespaces {
+			if nsa.Policies != nil {
+				policies[ns] = nsa.Policies
+			}
+		}
+		listener(policies)
+	}
 	return nil
 }
 
+func (s *shardAssignmentDispatcher) OnNamespacePoliciesUpdate(listener func(map[string]*proto.NamespacePolicies)) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.policiesListener = listener
+}
+
 func NewShardAssignmentDispatcher(healthServer *health.Server) ShardAssignmentsDispatcher {
 	s := &shardAssignmentDispatcher{
 		assignments:  nil,
@@ -0,0 +1,232 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common/metrics"
+	"github.com/streamnative/oxia/proto"
+)
+
+const (
+	AuditOperationPut         = "put"
+	AuditOperationDelete      = "delete"
+	AuditOperationDeleteRange = "delete-range"
+
+	// Max number of records waiting to be written. When the sink can't keep
+	// up, the new records are dropped instead of slowing down the writes.
+	auditLogQueueSize = 10_000
+)
+
+// AuditRecord is a mutation of the data, with the principal that requested it
+// and its outcome.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal,omitempty"`
+	Peer      string    `json:"peer,omitempty"`
+	Namespace string    `json:"namespace"`
+	Shard     int64     `json:"shard"`
+	Operation string    `json:"operation"`
+	Key       string    `json:"key"`
+
+	// EndKey is the exclusive end of the range, for the delete-range operations
+	EndKey string `json:"endKey,omitempty"`
+
+	ExpectedVersionId *int64 `json:"expectedVersionId,omitempty"`
+	VersionId         *int64 `json:"versionId,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	// Error is set when the whole write request failed
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog receives the records of the mutations applied by the leaders.
+// Append must not block the caller.
+type AuditLog interface {
+	io.Closer
+
+	Append(record AuditRecord)
+}
+
+type asyncAuditLog struct {
+	sync.WaitGroup
+	w       io.WriteCloser
+	records chan AuditRecord
+	closed  chan struct{}
+	once    sync.Once
+
+	droppedRecords metrics.Counter
+}
+
+// NewFileAuditLog appends the records to a file, as json objects one per
+// line. The records are written in the background.
+func NewFileAuditLog(path string) (AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open the audit log")
+	}
+
+	return NewWriterAuditLog(file), nil
+}
+
+// NewWriterAuditLog writes the records to a dedicated sink, as json objects
+// one per line. The records are written in the background, and dropped when
+// the sink is not keeping up with them. The writer is closed with the log.
+func NewWriterAuditLog(w io.WriteCloser) AuditLog {
+	l := &asyncAuditLog{
+		w:       w,
+		records: make(chan AuditRecord, auditLogQueueSize),
+		closed:  make(chan struct{}),
+		droppedRecords: metrics.NewCounter("oxia_server_audit_log_dropped_records",
+			"The number of audit records dropped because the audit log was not keeping up", "count", map[string]any{}),
+	}
+
+	l.Add(1)
+	go l.run()
+	return l
+}
+
+func (l *asyncAuditLog) Append(record AuditRecord) {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	select {
+	case <-l.closed:
+	case l.records <- record:
+	default:
+		l.droppedRecords.Inc()
+	}
+}
+
+func (l *asyncAuditLog) run() {
+	defer l.Done()
+
+	bw := bufio.NewWriter(l.w)
+	encoder := json.NewEncoder(bw)
+	for {
+		select {
+		case <-l.closed:
+			// Write what was queued before the close
+			for {
+				select {
+				case record := <-l.records:
+					l.write(encoder, record)
+				default:
+					l.flush(bw)
+					return
+				}
+			}
+
+		case record := <-l.records:
+			l.write(encoder, record)
+
+			// Flush only once there is nothing more queued, to batch the
+			// writes under load
+			if len(l.records) == 0 {
+				l.flush(bw)
+			}
+		}
+	}
+}
+
+func (*asyncAuditLog) write(encoder *json.Encoder, record AuditRecord) {
+	if err := encoder.Encode(record); err != nil {
+		slog.Warn(
+			"Failed to write the audit record",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (*asyncAuditLog) flush(bw *bufio.Writer) {
+	if err := bw.Flush(); err != nil {
+		slog.Warn(
+			"Failed to flush the audit log",
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (l *asyncAuditLog) Close() error {
+	l.once.Do(func() {
+		close(l.closed)
+	})
+	l.Wait()
+	return l.w.Close()
+}
+
+// auditWrite records each of the operations of a write request, along with
+// their result.
+func auditWrite(auditLog AuditLog, record AuditRecord, req *proto.WriteRequest, resp *proto.WriteResponse, err error) {
+	if auditLog == nil {
+		return
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for i, put := range req.Puts {
+		r := record
+		r.Operation = AuditOperationPut
+		r.Key = put.Key
+		r.ExpectedVersionId = put.ExpectedVersionId
+		if resp != nil && i < len(resp.Puts) {
+			pr := resp.Puts[i]
+			r.Status = pr.Status.String()
+			if pr.Key != nil {
+				r.Key = *pr.Key
+			}
+			if pr.Version != nil && pr.Status == proto.Status_OK {
+				versionId := pr.Version.VersionId
+				r.VersionId = &versionId
+			}
+		}
+		auditLog.Append(r)
+	}
+
+	for i, del := range req.Deletes {
+		r := record
+		r.Operation = AuditOperationDelete
+		r.Key = del.Key
+		r.ExpectedVersionId = del.ExpectedVersionId
+		if resp != nil && i < len(resp.Deletes) {
+			r.Status = resp.Deletes[i].Status.String()
+		}
+		auditLog.Append(r)
+	}
+
+	for i, dr := range req.DeleteRanges {
+		r := record
+		r.Operation = AuditOperationDeleteRange
+		r.Key = dr.StartInclusive
+		r.EndKey = dr.EndExclusive
+		if resp != nil && i < len(resp.DeleteRanges) {
+			r.Status = resp.DeleteRanges[i].Status.String()
+		}
+		auditLog.Append(r)
+	}
+}
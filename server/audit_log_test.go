@@ -0,0 +1,127 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/streamnative/oxia/proto"
+)
+
+func readAuditRecords(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	assert.NoError(t, scanner.Err())
+	return records
+}
+
+func TestFileAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	auditLog, err := NewFileAuditLog(path)
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		auditLog.Append(AuditRecord{Namespace: "ns", Operation: AuditOperationPut, Key: "/key"})
+	}
+	assert.NoError(t, auditLog.Close())
+
+	// Appending after the close is a no-op
+	auditLog.Append(AuditRecord{Namespace: "ns", Operation: AuditOperationPut, Key: "/key"})
+
+	records := readAuditRecords(t, path)
+	assert.Len(t, records, 100)
+	for _, r := range records {
+		assert.Equal(t, "ns", r.Namespace)
+		assert.Equal(t, AuditOperationPut, r.Operation)
+		assert.False(t, r.Time.IsZero())
+	}
+}
+
+type memoryAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *memoryAuditSink) Append(record AuditRecord) {
+	s.records = append(s.records, record)
+}
+
+func (*memoryAuditSink) Close() error {
+	return nil
+}
+
+func TestAuditWrite(t *testing.T) {
+	sink := &memoryAuditSink{}
+	base := AuditRecord{Principal: "alice", Namespace: "ns", Shard: 2}
+
+	auditWrite(sink, base, &proto.WriteRequest{
+		Puts: []*proto.PutRequest{
+			{Key: "/a", ExpectedVersionId: pb.Int64(3)},
+			{Key: "/b"},
+		},
+		Deletes:      []*proto.DeleteRequest{{Key: "/c"}},
+		DeleteRanges: []*proto.DeleteRangeRequest{{StartInclusive: "/d", EndExclusive: "/e"}},
+	}, &proto.WriteResponse{
+		Puts: []*proto.PutResponse{
+			{Status: proto.Status_UNEXPECTED_VERSION_ID},
+			{Status: proto.Status_OK, Version: &proto.Version{VersionId: 7}},
+		},
+		Deletes:      []*proto.DeleteResponse{{Status: proto.Status_KEY_NOT_FOUND}},
+		DeleteRanges: []*proto.DeleteRangeResponse{{Status: proto.Status_OK}},
+	}, nil)
+
+	assert.Equal(t, []AuditRecord{
+		{Principal: "alice", Namespace: "ns", Shard: 2, Operation: AuditOperationPut, Key: "/a",
+			ExpectedVersionId: pb.Int64(3), Status: "UNEXPECTED_VERSION_ID"},
+		{Principal: "alice", Namespace: "ns", Shard: 2, Operation: AuditOperationPut, Key: "/b",
+			VersionId: pb.Int64(7), Status: "OK"},
+		{Principal: "alice", Namespace: "ns", Shard: 2, Operation: AuditOperationDelete, Key: "/c",
+			Status: "KEY_NOT_FOUND"},
+		{Principal: "alice", Namespace: "ns", Shard: 2, Operation: AuditOperationDeleteRange, Key: "/d",
+			EndKey: "/e", Status: "OK"},
+	}, sink.records)
+
+	// A failed request is recorded with its error
+	sink.records = nil
+	auditWrite(sink, base, &proto.WriteRequest{
+		Deletes: []*proto.DeleteRequest{{Key: "/c"}},
+	}, nil, errors.New("failed"))
+	assert.Equal(t, []AuditRecord{
+		{Principal: "alice", Namespace: "ns", Shard: 2, Operation: AuditOperationDelete, Key: "/c",
+			Error: "failed"},
+	}, sink.records)
+
+	// Nothing is recorded without an audit log
+	auditWrite(nil, base, &proto.WriteRequest{Deletes: []*proto.DeleteRequest{{Key: "/c"}}}, nil, nil)
+}
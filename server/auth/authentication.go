@@ -16,21 +16,24 @@ package auth
 
 import (
 	"context"
+	"crypto/x509"
+	"sync"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
-	ProviderOIDC = "oidc"
-
-	ProviderParamTypeToken = "token"
+	ProviderOIDC      = "oidc"
+	ProviderTokenFile = "token-file"
+	ProviderMTLS      = "mtls"
 )
 
 var (
-	ErrUnsupportedProvider              = errors.New("unsupported authentication provider")
-	ErrUnMatchedAuthenticationParamType = errors.New("unmatched authentication parameter type")
-	ErrEmptyToken                       = errors.New("empty token")
-	ErrMalformedToken                   = errors.New("malformed token")
+	ErrUnsupportedProvider = errors.New("unsupported authentication provider")
+	ErrProviderRegistered  = errors.New("authentication provider already registered")
+	ErrEmptyToken          = errors.New("empty token")
+	ErrMalformedToken      = errors.New("malformed token")
 )
 
 var Disabled = Options{}
@@ -44,17 +47,60 @@ func (op *Options) IsEnabled() bool {
 	return op != nil && op.ProviderName != ""
 }
 
+// Credentials are what the client presented on the connection and in the
+// request metadata.
+type Credentials struct {
+	// Token is the bearer token of the "authorization" metadata, if any
+	Token string
+	// PeerCertificates is the verified chain of the client certificate, with
+	// the leaf first. It's only set when the client authenticated with TLS.
+	PeerCertificates []*x509.Certificate
+	// Metadata is the whole metadata of the request, for the providers that
+	// rely on custom headers
+	Metadata metadata.MD
+	// Peer is the address of the client
+	Peer string
+}
+
+// Provider validates the credentials of the clients and returns the principal
+// they are identified as.
 // todo: add metrics
-type AuthenticationProvider interface {
-	AcceptParamType() string
-	Authenticate(ctx context.Context, param any) (string, error)
+type Provider interface {
+	ValidateCredentials(ctx context.Context, credentials *Credentials) (principal string, err error)
+}
+
+// ProviderFactory creates a provider from the parameters given with
+// `--auth-provider-params`.
+type ProviderFactory func(ctx context.Context, params string) (Provider, error)
+
+var (
+	providersLock sync.RWMutex
+	providers     = map[string]ProviderFactory{
+		ProviderOIDC:      NewOIDCProvider,
+		ProviderTokenFile: NewTokenFileProvider,
+		ProviderMTLS:      NewMTLSProvider,
+	}
+)
+
+// RegisterProvider makes a custom provider available under the given name.
+// It is meant to be called from the init function of a package linked into a
+// custom build of the server.
+func RegisterProvider(name string, factory ProviderFactory) error {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	if _, exist := providers[name]; exist {
+		return errors.Wrap(ErrProviderRegistered, name)
+	}
+	providers[name] = factory
+	return nil
 }
 
-func NewAuthenticationProvider(ctx context.Context, options Options) (AuthenticationProvider, error) {
-	switch options.ProviderName {
-	case ProviderOIDC:
-		return NewOIDCProvider(ctx, options.ProviderParams)
-	default:
+func NewAuthenticationProvider(ctx context.Context, options Options) (Provider, error) {
+	providersLock.RLock()
+	factory, exist := providers[options.ProviderName]
+	providersLock.RUnlock()
+	if !exist {
 		return nil, ErrUnsupportedProvider
 	}
+	return factory(ctx, options.ProviderParams)
 }
@@ -22,6 +22,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	grpccredentials "google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
@@ -37,69 +38,84 @@ var (
 )
 
 type GrpcAuthenticationDelegator struct {
-	provider AuthenticationProvider
-
-	validate func(ctx context.Context, provider AuthenticationProvider) (string, error)
+	provider Provider
 }
 
 func (delegator *GrpcAuthenticationDelegator) GetUnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		_, err := delegator.validate(ctx, delegator.provider)
+		principal, err := delegator.validate(ctx)
 		if err != nil {
 			return nil, status.Errorf(codes.Unauthenticated, err.Error())
 		}
-		// todo: set username to metadata to support authorization
-		return handler(ctx, req)
+		return handler(WithPrincipal(ctx, principal), req)
 	}
 }
 
 func (delegator *GrpcAuthenticationDelegator) GetStreamInterceptor() grpc.StreamServerInterceptor {
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		_, err := delegator.validate(ss.Context(), delegator.provider)
+		principal, err := delegator.validate(ss.Context())
 		if err != nil {
 			return status.Errorf(codes.Unauthenticated, err.Error())
 		}
-		// todo: set username to metadata to support authorization
-		return handler(srv, ss)
+		return handler(srv, &authenticatedStream{
+			ServerStream: ss,
+			ctx:          WithPrincipal(ss.Context(), principal),
+		})
 	}
 }
 
-func NewGrpcAuthenticationDelegator(provider AuthenticationProvider) (*GrpcAuthenticationDelegator, error) {
-	delegator := &GrpcAuthenticationDelegator{
+// authenticatedStream exposes the context carrying the principal to the
+// stream handlers.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func NewGrpcAuthenticationDelegator(provider Provider) (*GrpcAuthenticationDelegator, error) {
+	return &GrpcAuthenticationDelegator{
 		provider: provider,
+	}, nil
+}
+
+func (delegator *GrpcAuthenticationDelegator) validate(ctx context.Context) (string, error) {
+	credentials, err := credentialsFromContext(ctx)
+	if err != nil {
+		return "", err
 	}
-	switch provider.AcceptParamType() {
-	case ProviderParamTypeToken:
-		delegator.validate = validateTokenWithContext
-	default:
-		return nil, ErrUnMatchedAuthenticationParamType
+	principal, err := delegator.provider.ValidateCredentials(ctx, credentials)
+	if err != nil {
+		slog.Debug("Failed to authenticate the client",
+			slog.String("peer", credentials.Peer),
+			slog.Any("error", err))
+		return "", err
 	}
-	return delegator, nil
+	return principal, nil
 }
 
-func validateTokenWithContext(ctx context.Context, provider AuthenticationProvider) (string, error) {
-	meta, ok := metadata.FromIncomingContext(ctx)
+// credentialsFromContext collects the token, the verified client certificate
+// and the metadata of the request.
+func credentialsFromContext(ctx context.Context) (*Credentials, error) {
+	peerMeta, ok := peer.FromContext(ctx)
 	if !ok {
-		return "", ErrMetadataFetchFailed
+		return nil, ErrMetadataFetchFailed
 	}
-	peerMeta, ok := peer.FromContext(ctx)
+	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", ErrMetadataFetchFailed
+		meta = metadata.MD{}
 	}
-	val := meta.Get(MetadataAuthorizationKey)
-	if len(val) < 1 {
-		slog.Debug("Receive empty token from the client",
-			slog.String("peer", peerMeta.Addr.String()))
-		return "", ErrEmptyToken
+	credentials := &Credentials{
+		Metadata: meta,
+		Peer:     peerMeta.Addr.String(),
 	}
-	token := strings.TrimPrefix(val[0], TokenPrefix)
-	var userName string
-	var err error
-	if userName, err = provider.Authenticate(ctx, token); err != nil {
-		slog.Debug("Failed to authenticate token",
-			slog.String("peer", peerMeta.Addr.String()),
-			slog.String("token", token))
-		return "", err
+	if val := meta.Get(MetadataAuthorizationKey); len(val) > 0 {
+		credentials.Token = strings.TrimPrefix(val[0], TokenPrefix)
+	}
+	if tlsInfo, ok := peerMeta.AuthInfo.(grpccredentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+		credentials.PeerCertificates = tlsInfo.State.VerifiedChains[0]
 	}
-	return userName, nil
+	return credentials, nil
 }
@@ -0,0 +1,149 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type staticTokenProvider struct {
+	tokens map[string]string
+}
+
+func (p *staticTokenProvider) ValidateCredentials(_ context.Context, credentials *Credentials) (string, error) {
+	userName, ok := p.tokens[credentials.Token]
+	if !ok {
+		return "", ErrMalformedToken
+	}
+	return userName, nil
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func newIncomingContext(token string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataAuthorizationKey, TokenPrefix+token))
+}
+
+func TestGrpcAuthenticationDelegator_Principal(t *testing.T) {
+	delegator, err := NewGrpcAuthenticationDelegator(&staticTokenProvider{
+		tokens: map[string]string{"token-1": "user-1"},
+	})
+	assert.NoError(t, err)
+
+	unary := delegator.GetUnaryInterceptor()
+	var principal string
+	var found bool
+	_, err = unary(newIncomingContext("token-1"), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) {
+			principal, found = GetPrincipal(ctx)
+			return nil, nil
+		})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "user-1", principal)
+
+	_, err = unary(newIncomingContext("token-2"), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) {
+			assert.Fail(t, "the handler should not be called")
+			return nil, nil
+		})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream := delegator.GetStreamInterceptor()
+	principal, found = "", false
+	err = stream(nil, &testServerStream{ctx: newIncomingContext("token-1")}, &grpc.StreamServerInfo{},
+		func(srv any, ss grpc.ServerStream) error {
+			principal, found = GetPrincipal(ss.Context())
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "user-1", principal)
+
+	_, found = GetPrincipal(context.Background())
+	assert.False(t, found)
+}
+
+func TestGrpcAuthenticationDelegator_Credentials(t *testing.T) {
+	var received *Credentials
+	delegator, err := NewGrpcAuthenticationDelegator(providerFunc(func(_ context.Context, c *Credentials) (string, error) {
+		received = c
+		return "user-1", nil
+	}))
+	assert.NoError(t, err)
+
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "user-1"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{leaf}},
+		}},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataAuthorizationKey, TokenPrefix+"token-1", "x-tenant", "tenant-1"))
+
+	_, err = delegator.GetUnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", received.Token)
+	assert.Equal(t, []*x509.Certificate{leaf}, received.PeerCertificates)
+	assert.Equal(t, []string{"tenant-1"}, received.Metadata.Get("x-tenant"))
+	assert.Equal(t, "127.0.0.1:1234", received.Peer)
+}
+
+type providerFunc func(ctx context.Context, credentials *Credentials) (string, error)
+
+func (f providerFunc) ValidateCredentials(ctx context.Context, credentials *Credentials) (string, error) {
+	return f(ctx, credentials)
+}
+
+func TestRegisterProvider(t *testing.T) {
+	assert.NoError(t, RegisterProvider("test-static", func(_ context.Context, params string) (Provider, error) {
+		return &staticTokenProvider{tokens: map[string]string{params: "user-1"}}, nil
+	}))
+	assert.ErrorIs(t, RegisterProvider("test-static", nil), ErrProviderRegistered)
+	assert.ErrorIs(t, RegisterProvider(ProviderOIDC, nil), ErrProviderRegistered)
+
+	provider, err := NewAuthenticationProvider(context.Background(), Options{ProviderName: "test-static", ProviderParams: "token-1"})
+	assert.NoError(t, err)
+	principal, err := provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal)
+
+	_, err = NewAuthenticationProvider(context.Background(), Options{ProviderName: "unknown"})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
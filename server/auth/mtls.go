@@ -0,0 +1,109 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	PrincipalFieldCommonName = "commonName"
+	PrincipalFieldDNSName    = "dnsName"
+	PrincipalFieldURI        = "uri"
+	PrincipalFieldEmail      = "email"
+	PrincipalFieldSPIFFEID   = "spiffeId"
+)
+
+var (
+	ErrMissingClientCertificate = errors.New("missing verified client certificate")
+	ErrPrincipalFieldNotFound   = errors.New("principal field not found in the client certificate")
+)
+
+type MTLSOptions struct {
+	// PrincipalField is the field of the client certificate the principal
+	// is taken from: commonName (default), dnsName, uri, email or spiffeId.
+	// For the subject alternative names, the first one is used.
+	PrincipalField string `json:"principalField,omitempty"`
+
+	// TrustDomains are the SPIFFE trust domains the clients are accepted
+	// from, separated by commas. They are required with spiffeId.
+	TrustDomains string `json:"trustDomains,omitempty"`
+}
+
+type mtlsProvider struct {
+	principalField string
+	trustDomains   map[string]bool
+}
+
+// NewMTLSProvider identifies the clients by the certificate they presented
+// in the TLS handshake, eg: by the SPIFFE ID of their X509-SVID. The public
+// listener must be configured to require and verify the client certificates,
+// with `--tls-client-auth` and `--tls-trusted-ca-file`.
+func NewMTLSProvider(_ context.Context, params string) (Provider, error) {
+	options := MTLSOptions{}
+	if params != "" {
+		if err := json.Unmarshal([]byte(params), &options); err != nil {
+			return nil, errors.Wrap(err, "invalid mtls provider params")
+		}
+	}
+	switch options.PrincipalField {
+	case "":
+		options.PrincipalField = PrincipalFieldCommonName
+	case PrincipalFieldCommonName, PrincipalFieldDNSName, PrincipalFieldURI, PrincipalFieldEmail:
+	case PrincipalFieldSPIFFEID:
+		trustDomains, err := parseTrustDomains(options.TrustDomains)
+		if err != nil {
+			return nil, err
+		}
+		return &mtlsProvider{principalField: options.PrincipalField, trustDomains: trustDomains}, nil
+	default:
+		return nil, errors.Errorf("unsupported principal field %q", options.PrincipalField)
+	}
+	return &mtlsProvider{principalField: options.PrincipalField}, nil
+}
+
+func (p *mtlsProvider) ValidateCredentials(_ context.Context, credentials *Credentials) (string, error) {
+	if len(credentials.PeerCertificates) == 0 {
+		return "", ErrMissingClientCertificate
+	}
+	leaf := credentials.PeerCertificates[0]
+
+	principal := ""
+	switch p.principalField {
+	case PrincipalFieldCommonName:
+		principal = leaf.Subject.CommonName
+	case PrincipalFieldDNSName:
+		if len(leaf.DNSNames) > 0 {
+			principal = leaf.DNSNames[0]
+		}
+	case PrincipalFieldURI:
+		if len(leaf.URIs) > 0 {
+			principal = leaf.URIs[0].String()
+		}
+	case PrincipalFieldEmail:
+		if len(leaf.EmailAddresses) > 0 {
+			principal = leaf.EmailAddresses[0]
+		}
+	case PrincipalFieldSPIFFEID:
+		return spiffeIDFromSVID(leaf, p.trustDomains)
+	}
+	if principal == "" {
+		return "", errors.Wrap(ErrPrincipalFieldNotFound, p.principalField)
+	}
+	return principal, nil
+}
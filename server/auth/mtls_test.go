@@ -0,0 +1,77 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMTLSProvider(t *testing.T) {
+	spiffeId, err := url.Parse("spiffe://example.com/ns/team-a/sa/writer")
+	assert.NoError(t, err)
+	credentials := &Credentials{
+		PeerCertificates: []*x509.Certificate{{
+			Subject:        pkix.Name{CommonName: "writer"},
+			DNSNames:       []string{"writer.example.com"},
+			URIs:           []*url.URL{spiffeId},
+			EmailAddresses: []string{"writer@example.com"},
+		}, {
+			Subject: pkix.Name{CommonName: "ca"},
+		}},
+	}
+
+	for _, test := range []struct {
+		params    string
+		principal string
+	}{
+		{"", "writer"},
+		{`{"principalField":"commonName"}`, "writer"},
+		{`{"principalField":"dnsName"}`, "writer.example.com"},
+		{`{"principalField":"uri"}`, "spiffe://example.com/ns/team-a/sa/writer"},
+		{`{"principalField":"email"}`, "writer@example.com"},
+	} {
+		provider, err := NewAuthenticationProvider(context.Background(), Options{
+			ProviderName:   ProviderMTLS,
+			ProviderParams: test.params,
+		})
+		assert.NoError(t, err)
+
+		principal, err := provider.ValidateCredentials(context.Background(), credentials)
+		assert.NoError(t, err)
+		assert.Equal(t, test.principal, principal, test.params)
+	}
+
+	_, err = NewMTLSProvider(context.Background(), `{"principalField":"serialNumber"}`)
+	assert.Error(t, err)
+}
+
+func TestMTLSProvider_MissingIdentity(t *testing.T) {
+	provider, err := NewMTLSProvider(context.Background(), `{"principalField":"uri"}`)
+	assert.NoError(t, err)
+
+	_, err = provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-1"})
+	assert.ErrorIs(t, err, ErrMissingClientCertificate)
+
+	_, err = provider.ValidateCredentials(context.Background(), &Credentials{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "writer"}}},
+	})
+	assert.ErrorIs(t, err, ErrPrincipalFieldNotFound)
+}
@@ -43,6 +43,10 @@ type OIDCOptions struct {
 	AllowedIssueURLs string `json:"allowedIssueURLs,omitempty"`
 	AllowedAudiences string `json:"allowedAudiences,omitempty"`
 	UserNameClaim    string `json:"userNameClaim,omitempty"`
+	// JWKSURL is the endpoint publishing the keys that sign the tokens. When
+	// set, the keys of all the allowed issuers are fetched from it, instead
+	// of using the OIDC discovery on the issuers.
+	JWKSURL string `json:"jwksURL,omitempty"`
 }
 
 func (op *OIDCOptions) Validate() error {
@@ -73,14 +77,10 @@ type OIDCProvider struct {
 	providers map[string]*ProviderWithVerifier
 }
 
-func (*OIDCProvider) AcceptParamType() string {
-	return ProviderParamTypeToken
-}
-
-func (p *OIDCProvider) Authenticate(ctx context.Context, param any) (string, error) {
-	token, ok := param.(string)
-	if !ok {
-		return "", ErrUnMatchedAuthenticationParamType
+func (p *OIDCProvider) ValidateCredentials(ctx context.Context, credentials *Credentials) (string, error) {
+	token := credentials.Token
+	if token == "" {
+		return "", ErrEmptyToken
 	}
 	tokenParts := strings.Split(token, ".")
 	if len(tokenParts) != 3 {
@@ -132,7 +132,7 @@ func (p *OIDCProvider) Authenticate(ctx context.Context, param any) (string, err
 	return userName, nil
 }
 
-func NewOIDCProvider(ctx context.Context, jsonParam string) (AuthenticationProvider, error) {
+func NewOIDCProvider(ctx context.Context, jsonParam string) (Provider, error) {
 	oidcParams := &OIDCOptions{}
 	if err := json.Unmarshal([]byte(jsonParam), oidcParams); err != nil {
 		return nil, err
@@ -157,14 +157,20 @@ func NewOIDCProvider(ctx context.Context, jsonParam string) (AuthenticationProvi
 	urlArr := strings.Split(oidcParams.AllowedIssueURLs, ",")
 	for i := 0; i < len(urlArr); i++ {
 		issueURL := urlArr[i]
-		provider, err := oidc.NewProvider(ctx, issueURL)
-		if err != nil {
-			return nil, err
-		}
 		config := &oidc.Config{
 			SkipClientIDCheck: true,
 			Now:               time.Now,
 		}
+		if oidcParams.JWKSURL != "" {
+			oidcProvider.providers[issueURL] = &ProviderWithVerifier{
+				verifier: oidc.NewVerifier(issueURL, oidc.NewRemoteKeySet(ctx, oidcParams.JWKSURL), config),
+			}
+			continue
+		}
+		provider, err := oidc.NewProvider(ctx, issueURL)
+		if err != nil {
+			return nil, err
+		}
 		verifier := provider.Verifier(config)
 		oidcProvider.providers[issueURL] = &ProviderWithVerifier{
 			provider: provider,
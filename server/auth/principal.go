@@ -0,0 +1,58 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context that carries the name of the
+// authenticated client.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// GetPrincipal returns the name of the authenticated client attached to the
+// context by the authentication interceptors. It returns false when the
+// authentication is disabled.
+func GetPrincipal(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
+
+// IsPrincipalPattern tells whether the principal of an authorization rule is
+// a pattern, matching several principals, eg: the SPIFFE IDs of the workloads
+// of a namespace with `spiffe://example.org/ns/team-a/*`.
+func IsPrincipalPattern(principal string) bool {
+	return strings.ContainsAny(principal, "*?[")
+}
+
+// ValidatePrincipalPattern checks the syntax of a pattern.
+func ValidatePrincipalPattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+// MatchPrincipal tells whether the principal matches the pattern. The syntax
+// is the one of path.Match, where `*` matches any sequence of characters
+// except `/`, so that a pattern only matches a single level of the paths.
+func MatchPrincipal(pattern string, principal string) bool {
+	matched, err := path.Match(pattern, principal)
+	return err == nil && matched
+}
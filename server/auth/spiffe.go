@@ -0,0 +1,115 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const spiffeScheme = "spiffe"
+
+var (
+	ErrInvalidSPIFFEID          = errors.New("invalid SPIFFE ID")
+	ErrUntrustedSPIFFEDomain    = errors.New("untrusted SPIFFE trust domain")
+	ErrMissingTrustDomains      = errors.New("the trust domains are required to accept the SPIFFE IDs")
+	ErrInvalidSVIDURICount      = errors.New("a X509-SVID must have exactly one URI SAN")
+	ErrInvalidSPIFFETrustDomain = errors.New("invalid SPIFFE trust domain")
+)
+
+// spiffeIDFromSVID returns the SPIFFE ID of a X509-SVID, which is its single
+// URI SAN, after checking that it belongs to one of the trusted domains.
+func spiffeIDFromSVID(cert *x509.Certificate, trustDomains map[string]bool) (string, error) {
+	if len(cert.URIs) != 1 {
+		return "", ErrInvalidSVIDURICount
+	}
+
+	id := cert.URIs[0]
+	trustDomain, err := parseSPIFFEID(id)
+	if err != nil {
+		return "", err
+	}
+	if !trustDomains[trustDomain] {
+		return "", errors.Wrap(ErrUntrustedSPIFFEDomain, trustDomain)
+	}
+	return id.String(), nil
+}
+
+// parseSPIFFEID checks that the URI is a SPIFFE ID, as defined by the SPIFFE
+// specification, and returns its trust domain.
+func parseSPIFFEID(id *url.URL) (trustDomain string, err error) {
+	switch {
+	case id.Scheme != spiffeScheme:
+		return "", errors.Wrap(ErrInvalidSPIFFEID, "the scheme must be spiffe")
+	case id.User != nil || id.Port() != "":
+		return "", errors.Wrap(ErrInvalidSPIFFEID, "the trust domain cannot have a user info or a port")
+	case id.RawQuery != "" || id.Fragment != "" || id.ForceQuery:
+		return "", errors.Wrap(ErrInvalidSPIFFEID, "the query and the fragment are not allowed")
+	}
+	if err := validateTrustDomain(id.Host); err != nil {
+		return "", errors.Wrap(ErrInvalidSPIFFEID, err.Error())
+	}
+
+	if id.Path != "" {
+		for _, segment := range strings.Split(strings.TrimPrefix(id.Path, "/"), "/") {
+			if segment == "" || segment == "." || segment == ".." || !isValidSPIFFEChars(segment, true) {
+				return "", errors.Wrapf(ErrInvalidSPIFFEID, "invalid path segment %q", segment)
+			}
+		}
+	}
+	return id.Host, nil
+}
+
+func validateTrustDomain(trustDomain string) error {
+	if trustDomain == "" || !isValidSPIFFEChars(trustDomain, false) {
+		return errors.Wrapf(ErrInvalidSPIFFETrustDomain, "%q", trustDomain)
+	}
+	return nil
+}
+
+// The trust domains only have lowercase letters, digits, dots, dashes and
+// underscores, and the path segments can have uppercase letters as well.
+func isValidSPIFFEChars(s string, allowUppercase bool) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+		case allowUppercase && c >= 'A' && c <= 'Z':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func parseTrustDomains(list string) (map[string]bool, error) {
+	trustDomains := map[string]bool{}
+	for _, trustDomain := range strings.Split(list, ",") {
+		trustDomain = strings.TrimSpace(trustDomain)
+		if trustDomain == "" {
+			continue
+		}
+		if err := validateTrustDomain(trustDomain); err != nil {
+			return nil, err
+		}
+		trustDomains[trustDomain] = true
+	}
+	if len(trustDomains) == 0 {
+		return nil, ErrMissingTrustDomains
+	}
+	return trustDomains, nil
+}
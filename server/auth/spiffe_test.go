@@ -0,0 +1,94 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func svid(t *testing.T, uris ...string) *Credentials {
+	t.Helper()
+	cert := &x509.Certificate{}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		assert.NoError(t, err)
+		cert.URIs = append(cert.URIs, u)
+	}
+	return &Credentials{PeerCertificates: []*x509.Certificate{cert}}
+}
+
+func TestMTLSProvider_SPIFFEID(t *testing.T) {
+	_, err := NewMTLSProvider(context.Background(), `{"principalField":"spiffeId"}`)
+	assert.ErrorIs(t, err, ErrMissingTrustDomains)
+	_, err = NewMTLSProvider(context.Background(), `{"principalField":"spiffeId","trustDomains":"Example.org"}`)
+	assert.ErrorIs(t, err, ErrInvalidSPIFFETrustDomain)
+
+	provider, err := NewMTLSProvider(context.Background(),
+		`{"principalField":"spiffeId","trustDomains":"example.org, prod.example.com"}`)
+	assert.NoError(t, err)
+
+	for _, id := range []string{
+		"spiffe://example.org/ns/team-a/sa/writer",
+		"spiffe://prod.example.com/Workload_1",
+		"spiffe://example.org",
+	} {
+		principal, err := provider.ValidateCredentials(context.Background(), svid(t, id))
+		assert.NoError(t, err)
+		assert.Equal(t, id, principal)
+	}
+
+	for id, expected := range map[string]error{
+		"spiffe://other.org/ns/team-a":        ErrUntrustedSPIFFEDomain,
+		"https://example.org/ns/team-a":       ErrInvalidSPIFFEID,
+		"spiffe://user@example.org/ns/team-a": ErrInvalidSPIFFEID,
+		"spiffe://example.org:8080/ns/team-a": ErrInvalidSPIFFEID,
+		"spiffe://example.org/ns/team-a?x=1":  ErrInvalidSPIFFEID,
+		"spiffe://example.org/ns/team-a#x":    ErrInvalidSPIFFEID,
+		"spiffe://example.org/ns//team-a":     ErrInvalidSPIFFEID,
+		"spiffe://example.org/ns/../team-a":   ErrInvalidSPIFFEID,
+		"spiffe://example.org/ns/team-a/":     ErrInvalidSPIFFEID,
+		"spiffe://EXAMPLE.org/ns/team-a":      ErrInvalidSPIFFEID,
+		"spiffe:///ns/team-a":                 ErrInvalidSPIFFEID,
+	} {
+		_, err := provider.ValidateCredentials(context.Background(), svid(t, id))
+		assert.ErrorIs(t, err, expected, id)
+	}
+
+	// The X509-SVIDs have a single URI SAN
+	_, err = provider.ValidateCredentials(context.Background(), svid(t))
+	assert.ErrorIs(t, err, ErrInvalidSVIDURICount)
+	_, err = provider.ValidateCredentials(context.Background(),
+		svid(t, "spiffe://example.org/a", "spiffe://example.org/b"))
+	assert.ErrorIs(t, err, ErrInvalidSVIDURICount)
+}
+
+func TestMatchPrincipal(t *testing.T) {
+	assert.False(t, IsPrincipalPattern("spiffe://example.org/ns/team-a/sa/writer"))
+	assert.True(t, IsPrincipalPattern("spiffe://example.org/ns/team-a/sa/*"))
+
+	assert.NoError(t, ValidatePrincipalPattern("spiffe://example.org/ns/team-?/sa/*"))
+	assert.Error(t, ValidatePrincipalPattern("spiffe://example.org/ns/[team"))
+
+	pattern := "spiffe://example.org/ns/team-a/sa/*"
+	assert.True(t, MatchPrincipal(pattern, "spiffe://example.org/ns/team-a/sa/writer"))
+	assert.False(t, MatchPrincipal(pattern, "spiffe://example.org/ns/team-b/sa/writer"))
+	assert.False(t, MatchPrincipal(pattern, "spiffe://example.org/ns/team-a/sa/writer/extra"))
+	assert.False(t, MatchPrincipal(pattern, "spiffe://other.org/ns/team-a/sa/writer"))
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The max time before a modification of the token file is detected
+const tokenFileCheckInterval = 5 * time.Second
+
+var ErrUnknownToken = errors.New("unknown token")
+
+type TokenFileOptions struct {
+	// Path of the file with the tokens, one per line as
+	// `<principal>:<token>`. Several tokens can map to the same principal.
+	Path string `json:"path"`
+}
+
+type tokenFileProvider struct {
+	sync.Mutex
+	path      string
+	modTime   time.Time
+	lastCheck time.Time
+
+	// The principals by the sha256 of their tokens, so that the tokens are
+	// not kept in memory
+	principals map[[sha256.Size]byte]string
+}
+
+// NewTokenFileProvider authenticates the clients with static bearer tokens
+// read from a local file. The file is reloaded when modified, so that the
+// tokens can be added and revoked without restarting the server.
+func NewTokenFileProvider(_ context.Context, params string) (Provider, error) {
+	options := TokenFileOptions{}
+	if err := json.Unmarshal([]byte(params), &options); err != nil {
+		return nil, errors.Wrap(err, "invalid token file provider params")
+	}
+	if options.Path == "" {
+		return nil, errors.New("the path of the token file is required")
+	}
+
+	p := &tokenFileProvider{path: options.Path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *tokenFileProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the token file")
+	}
+	p.lastCheck = time.Now()
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the token file")
+	}
+
+	principals := map[[sha256.Size]byte]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		principal, token, found := strings.Cut(text, ":")
+		if !found || principal == "" || token == "" {
+			return errors.Errorf("invalid line %d in the token file: expected <principal>:<token>", line)
+		}
+		principals[sha256.Sum256([]byte(token))] = principal
+	}
+
+	p.principals = principals
+	p.modTime = info.ModTime()
+	return nil
+}
+
+func (p *tokenFileProvider) ValidateCredentials(_ context.Context, credentials *Credentials) (string, error) {
+	if credentials.Token == "" {
+		return "", ErrEmptyToken
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	// A file that cannot be loaded, eg: because it's partially written,
+	// doesn't replace the tokens
+	if time.Since(p.lastCheck) >= tokenFileCheckInterval {
+		_ = p.reload()
+	}
+	principal, ok := p.principals[sha256.Sum256([]byte(credentials.Token))]
+	if !ok {
+		return "", ErrUnknownToken
+	}
+	return principal, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	assert.NoError(t, os.WriteFile(path, []byte("# tokens\nuser-1:token-1\nuser-2:token-2\n"), 0600))
+
+	provider, err := NewAuthenticationProvider(context.Background(), Options{
+		ProviderName:   ProviderTokenFile,
+		ProviderParams: `{"path":"` + path + `"}`,
+	})
+	assert.NoError(t, err)
+
+	principal, err := provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal)
+
+	_, err = provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-3"})
+	assert.ErrorIs(t, err, ErrUnknownToken)
+	_, err = provider.ValidateCredentials(context.Background(), &Credentials{})
+	assert.ErrorIs(t, err, ErrEmptyToken)
+
+	// Revoke a token and add a new one
+	assert.NoError(t, os.WriteFile(path, []byte("user-2:token-2\nuser-3:token-3\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, later, later))
+	provider.(*tokenFileProvider).lastCheck = time.Time{}
+
+	_, err = provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-1"})
+	assert.ErrorIs(t, err, ErrUnknownToken)
+	principal, err = provider.ValidateCredentials(context.Background(), &Credentials{Token: "token-3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user-3", principal)
+}
+
+func TestTokenFileProvider_InvalidFile(t *testing.T) {
+	_, err := NewTokenFileProvider(context.Background(), `{}`)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "tokens")
+	_, err = NewTokenFileProvider(context.Background(), `{"path":"`+path+`"}`)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("user-1\n"), 0600))
+	_, err = NewTokenFileProvider(context.Background(), `{"path":"`+path+`"}`)
+	assert.ErrorContains(t, err, "line 1")
+}
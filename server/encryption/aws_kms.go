@@ -0,0 +1,113 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/pkg/errors"
+)
+
+type AwsKmsOptions struct {
+	// Region of the key. Defaults to the region of the AWS configuration
+	Region string `json:"region,omitempty"`
+	// KeyId is the id, the ARN or the alias of the KMS key wrapping the data keys
+	KeyId string `json:"keyId"`
+	// Endpoint overrides the regional endpoint of KMS, eg: for a VPC endpoint
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+type awsKmsProvider struct {
+	options AwsKmsOptions
+	client  *kms.Client
+}
+
+// NewAwsKmsProvider wraps the data keys with a KMS key. The credentials are
+// resolved by the default chain of the AWS SDK: environment, shared config,
+// web identity (eg: IRSA), and the container or instance roles. The
+// automatic rotation of the key material is handled by KMS, transparently
+// for the wrapped keys.
+func NewAwsKmsProvider(params string) (KeyProvider, error) {
+	options := AwsKmsOptions{}
+	if err := json.Unmarshal([]byte(params), &options); err != nil {
+		return nil, errors.Wrap(err, "invalid aws kms provider params")
+	}
+	if options.KeyId == "" {
+		return nil, errors.New("the aws kms key id is required")
+	}
+
+	ctx := context.Background()
+	var loadOptions []func(*config.LoadOptions) error
+	if options.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(options.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the aws configuration")
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("the aws region is required")
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to get the aws credentials")
+	}
+
+	return &awsKmsProvider{
+		options: options,
+		client: kms.NewFromConfig(cfg, func(o *kms.Options) {
+			if options.Endpoint != "" {
+				o.BaseEndpoint = aws.String(options.Endpoint)
+			}
+		}),
+	}, nil
+}
+
+func (p *awsKmsProvider) MasterKeyId() string {
+	return "aws-kms:" + p.options.KeyId
+}
+
+func (p *awsKmsProvider) WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error) {
+	res, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(p.options.KeyId),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt with aws kms")
+	}
+
+	return &WrappedKey{
+		MasterKeyId: p.MasterKeyId(),
+		Ciphertext:  res.CiphertextBlob,
+	}, nil
+}
+
+func (p *awsKmsProvider) UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error) {
+	res, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(p.options.KeyId),
+		CiphertextBlob: key.Ciphertext,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt with aws kms")
+	}
+	return res.Plaintext, nil
+}
+
+func (*awsKmsProvider) Close() error {
+	return nil
+}
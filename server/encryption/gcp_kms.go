@@ -0,0 +1,108 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+type GcpKmsOptions struct {
+	// KeyName is the resource name of the crypto key wrapping the data keys:
+	// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>
+	KeyName string `json:"keyName"`
+	// Endpoint overrides the endpoint of Cloud KMS
+	Endpoint string `json:"endpoint,omitempty"`
+	// AccessToken is a static OAuth2 token. Defaults to the Application
+	// Default Credentials.
+	AccessToken string `json:"accessToken,omitempty"`
+}
+
+type gcpKmsProvider struct {
+	options GcpKmsOptions
+	client  *kms.KeyManagementClient
+}
+
+// NewGcpKmsProvider wraps the data keys with a Cloud KMS crypto key. The
+// credentials are the Application Default Credentials: the key file of
+// $GOOGLE_APPLICATION_CREDENTIALS, the gcloud credentials, or the service
+// account of the workload. The rotation of the key versions is handled by
+// Cloud KMS, and the primary version is used when the data keys are rotated.
+func NewGcpKmsProvider(ctx context.Context, params string, clientOptions ...option.ClientOption) (KeyProvider, error) {
+	options := GcpKmsOptions{}
+	if err := json.Unmarshal([]byte(params), &options); err != nil {
+		return nil, errors.Wrap(err, "invalid gcp kms provider params")
+	}
+	if options.KeyName == "" {
+		return nil, errors.New("the gcp kms key name is required")
+	}
+	if options.Endpoint != "" {
+		clientOptions = append(clientOptions, option.WithEndpoint(options.Endpoint))
+	}
+	if options.AccessToken != "" {
+		clientOptions = append(clientOptions, option.WithTokenSource(
+			oauth2.StaticTokenSource(&oauth2.Token{AccessToken: options.AccessToken})))
+	}
+
+	client, err := kms.NewKeyManagementClient(ctx, clientOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the gcp kms client")
+	}
+
+	return &gcpKmsProvider{
+		options: options,
+		client:  client,
+	}, nil
+}
+
+func (p *gcpKmsProvider) MasterKeyId() string {
+	return "gcp-kms:" + p.options.KeyName
+}
+
+func (p *gcpKmsProvider) WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error) {
+	res, err := p.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      p.options.KeyName,
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt with gcp kms")
+	}
+
+	return &WrappedKey{
+		MasterKeyId: p.MasterKeyId(),
+		Ciphertext:  res.Ciphertext,
+	}, nil
+}
+
+func (p *gcpKmsProvider) UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error) {
+	res, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       p.options.KeyName,
+		Ciphertext: key.Ciphertext,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt with gcp kms")
+	}
+	return res.Plaintext, nil
+}
+
+func (p *gcpKmsProvider) Close() error {
+	return p.client.Close()
+}
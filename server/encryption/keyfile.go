@@ -0,0 +1,200 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The max time before a modification of the key file is detected
+const keyFileCheckInterval = 5 * time.Second
+
+type KeyFileOptions struct {
+	// Path of the file with the master keys, one per line as
+	// `<id>:<base64 encoded 32 bytes key>`. The last key is the current one,
+	// the previous ones are only used to unwrap the existing data keys.
+	Path string `json:"path"`
+}
+
+type keyFileProvider struct {
+	sync.Mutex
+	path      string
+	modTime   time.Time
+	lastCheck time.Time
+
+	keys      map[string][]byte
+	currentId string
+}
+
+// NewKeyFileProvider reads the master keys from a local file. The file is
+// reloaded when modified, so that a key can be rotated by appending a new one.
+func NewKeyFileProvider(params string) (KeyProvider, error) {
+	options := KeyFileOptions{}
+	if err := json.Unmarshal([]byte(params), &options); err != nil {
+		return nil, errors.Wrap(err, "invalid key file provider params")
+	}
+	if options.Path == "" {
+		return nil, errors.New("the path of the key file is required")
+	}
+
+	p := &keyFileProvider{path: options.Path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *keyFileProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the key file")
+	}
+	p.lastCheck = time.Now()
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the key file")
+	}
+
+	keys := map[string][]byte{}
+	currentId := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(line, ":")
+		if !found || id == "" {
+			return errors.Errorf("invalid line in the key file: expected <id>:<key>")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != dataKeySize {
+			return errors.Errorf("invalid master key %q in the key file: expected %d base64 encoded bytes", id, dataKeySize)
+		}
+		keys[id] = key
+		currentId = id
+	}
+	if currentId == "" {
+		return errors.New("no master key in the key file")
+	}
+
+	p.keys = keys
+	p.currentId = currentId
+	p.modTime = info.ModTime()
+	return nil
+}
+
+// maybeReload checks the key file for modifications. A file that cannot be
+// loaded, eg: because it's partially written, doesn't replace the keys.
+func (p *keyFileProvider) maybeReload(force bool) {
+	if !force && time.Since(p.lastCheck) < keyFileCheckInterval {
+		return
+	}
+	_ = p.reload()
+}
+
+func (p *keyFileProvider) MasterKeyId() string {
+	p.Lock()
+	defer p.Unlock()
+
+	p.maybeReload(false)
+	return p.currentId
+}
+
+func (p *keyFileProvider) WrapKey(_ context.Context, dataKey []byte) (*WrappedKey, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.maybeReload(false)
+	ciphertext, err := seal(p.keys[p.currentId], dataKey, []byte(p.currentId))
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedKey{MasterKeyId: p.currentId, Ciphertext: ciphertext}, nil
+}
+
+func (p *keyFileProvider) UnwrapKey(_ context.Context, key *WrappedKey) ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	masterKey, ok := p.keys[key.MasterKeyId]
+	if !ok {
+		p.maybeReload(true)
+		if masterKey, ok = p.keys[key.MasterKeyId]; !ok {
+			return nil, errors.Wrapf(ErrUnknownMasterKey, "master key %q", key.MasterKeyId)
+		}
+	}
+	return open(masterKey, key.Ciphertext, []byte(key.MasterKeyId))
+}
+
+func (*keyFileProvider) Close() error {
+	return nil
+}
+
+// seal encrypts with AES-GCM, prepending the random nonce to the ciphertext.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCiphertext, err.Error())
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
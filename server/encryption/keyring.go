@@ -0,0 +1,354 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+const (
+	dataKeySize = 32
+
+	// A data key is rotated after encrypting this number of records, well
+	// below the limit of the random nonces of AES-GCM
+	maxDataKeyUses = 1 << 30
+
+	DefaultRotationInterval = 7 * 24 * time.Hour
+
+	// The min time between the attempts to rotate the data key, when the
+	// provider is not available
+	rotationRetryInterval = 30 * time.Second
+
+	// The encrypted records start with a zero byte, which never starts a
+	// serialized protobuf message, so that the records written before the
+	// encryption was enabled are still readable.
+	formatMagic   byte = 0
+	formatVersion byte = 1
+	headerSize         = 6
+)
+
+var ErrUnknownDataKey = errors.New("unknown data key")
+
+type KeyringOptions struct {
+	// RotationInterval is the max age of the data key encrypting the new
+	// records. The previous data keys are kept to decrypt the existing ones.
+	RotationInterval time.Duration
+
+	// Persist stores the wrapped data keys. It's called with all the keys
+	// each time a new one is created, before it's used.
+	Persist func(data []byte) error
+}
+
+type storedDataKey struct {
+	Id      uint32     `json:"id"`
+	Created time.Time  `json:"created"`
+	Key     WrappedKey `json:"key"`
+}
+
+type storedKeyring struct {
+	Keys []storedDataKey `json:"keys"`
+}
+
+type dataKey struct {
+	stored storedDataKey
+	key    []byte
+	aead   cipher.AEAD
+	uses   atomic.Int64
+}
+
+// Keyring implements the envelope encryption of the records: they're
+// encrypted with data keys, which are stored along with the data, wrapped
+// by a master key of the provider.
+type Keyring struct {
+	sync.RWMutex
+	provider KeyProvider
+	options  KeyringOptions
+
+	keys              map[uint32]*dataKey
+	current           *dataKey
+	lastRotationRetry time.Time
+}
+
+// OpenKeyring unwraps the data keys previously persisted, or creates the
+// first data key when there are none.
+func OpenKeyring(ctx context.Context, provider KeyProvider, data []byte, options KeyringOptions) (*Keyring, error) {
+	if options.RotationInterval == 0 {
+		options.RotationInterval = DefaultRotationInterval
+	}
+
+	k := &Keyring{
+		provider: provider,
+		options:  options,
+		keys:     map[uint32]*dataKey{},
+	}
+
+	if len(data) > 0 {
+		stored := storedKeyring{}
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the data keys")
+		}
+
+		for _, sk := range stored.Keys {
+			key, err := provider.UnwrapKey(ctx, &sk.Key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to unwrap the data key %d", sk.Id)
+			}
+			dk, err := newDataKey(sk, key)
+			if err != nil {
+				return nil, err
+			}
+			k.keys[sk.Id] = dk
+			if k.current == nil || sk.Id > k.current.stored.Id {
+				k.current = dk
+			}
+		}
+	}
+
+	if k.current == nil || k.needsRotation(k.current) {
+		if err := k.Rotate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+func newDataKey(stored storedDataKey, key []byte) (*dataKey, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &dataKey{stored: stored, key: key, aead: aead}, nil
+}
+
+func (k *Keyring) needsRotation(dk *dataKey) bool {
+	return dk.stored.Key.MasterKeyId != k.provider.MasterKeyId() ||
+		time.Since(dk.stored.Created) > k.options.RotationInterval ||
+		dk.uses.Load() >= maxDataKeyUses
+}
+
+// Rotate creates a new data key for the next records, and wraps the previous
+// ones again with the current master key of the provider.
+func (k *Keyring) Rotate(ctx context.Context) error {
+	return k.rotate(ctx, true)
+}
+
+func (k *Keyring) rotate(ctx context.Context, force bool) error {
+	k.Lock()
+	defer k.Unlock()
+
+	if !force && !k.needsRotation(k.current) {
+		// Already rotated by a concurrent writer
+		return nil
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	wrapped, err := k.provider.WrapKey(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to wrap the data key")
+	}
+
+	id := uint32(0)
+	if k.current != nil {
+		id = k.current.stored.Id + 1
+	}
+	dk, err := newDataKey(storedDataKey{Id: id, Created: time.Now(), Key: *wrapped}, key)
+	if err != nil {
+		return err
+	}
+
+	stored := storedKeyring{Keys: []storedDataKey{}}
+	rewrapped := map[uint32]WrappedKey{}
+	for _, existing := range k.keys {
+		sk := existing.stored
+		if sk.Key.MasterKeyId != wrapped.MasterKeyId {
+			w, err := k.provider.WrapKey(ctx, existing.key)
+			if err != nil {
+				return errors.Wrapf(err, "failed to wrap the data key %d", sk.Id)
+			}
+			sk.Key = *w
+			rewrapped[sk.Id] = *w
+		}
+		stored.Keys = append(stored.Keys, sk)
+	}
+	stored.Keys = append(stored.Keys, dk.stored)
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if k.options.Persist != nil {
+		if err := k.options.Persist(data); err != nil {
+			return errors.Wrap(err, "failed to persist the data keys")
+		}
+	}
+
+	for id, w := range rewrapped {
+		k.keys[id].stored.Key = w
+	}
+	k.keys[id] = dk
+	k.current = dk
+
+	slog.Info(
+		"Rotated the encryption data key",
+		slog.Int("data-key-id", int(id)),
+		slog.String("master-key-id", wrapped.MasterKeyId),
+	)
+	return nil
+}
+
+func (k *Keyring) currentKey() *dataKey {
+	k.RLock()
+	current := k.current
+	rotate := k.needsRotation(current) && time.Since(k.lastRotationRetry) > rotationRetryInterval
+	k.RUnlock()
+
+	if !rotate {
+		return current
+	}
+
+	if err := k.rotate(context.Background(), false); err != nil {
+		// Keep encrypting with the current data key until the provider is
+		// available again
+		slog.Warn(
+			"Failed to rotate the encryption data key",
+			slog.Any("error", err),
+		)
+		k.Lock()
+		k.lastRotationRetry = time.Now()
+		k.Unlock()
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+	return k.current
+}
+
+// Encrypt the record with the current data key, rotating it when needed.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	dk := k.currentKey()
+	dk.uses.Add(1)
+
+	nonceSize := dk.aead.NonceSize()
+	buf := make([]byte, headerSize+nonceSize, headerSize+nonceSize+len(plaintext)+dk.aead.Overhead())
+	buf[0] = formatMagic
+	buf[1] = formatVersion
+	binary.BigEndian.PutUint32(buf[2:headerSize], dk.stored.Id)
+	nonce := buf[headerSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return dk.aead.Seal(buf, nonce, plaintext, buf[:headerSize]), nil
+}
+
+// IsEncrypted returns whether the record was encrypted by a keyring, as
+// opposed to a record written before the encryption was enabled.
+func IsEncrypted(record []byte) bool {
+	return len(record) >= headerSize && record[0] == formatMagic && record[1] == formatVersion
+}
+
+// Decrypt a record encrypted by the keyring. The records that are not
+// encrypted are returned as they are.
+func (k *Keyring) Decrypt(record []byte) ([]byte, error) {
+	if !IsEncrypted(record) {
+		return record, nil
+	}
+
+	id := binary.BigEndian.Uint32(record[2:headerSize])
+	k.RLock()
+	dk, ok := k.keys[id]
+	k.RUnlock()
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownDataKey, "data key %d", id)
+	}
+
+	nonceSize := dk.aead.NonceSize()
+	if len(record) < headerSize+nonceSize {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := dk.aead.Open(nil, record[headerSize:headerSize+nonceSize], record[headerSize+nonceSize:], record[:headerSize])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCiphertext, err.Error())
+	}
+	return plaintext, nil
+}
+
+// OpenFileKeyring persists the wrapped data keys in a local file.
+func OpenFileKeyring(ctx context.Context, provider KeyProvider, path string, rotationInterval time.Duration) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read the data keys")
+	}
+
+	return OpenKeyring(ctx, provider, data, KeyringOptions{
+		RotationInterval: rotationInterval,
+		Persist: func(data []byte) error {
+			return writeFileAtomically(path, data)
+		},
+	})
+}
+
+// OpenFileKeyringReadOnly loads the data keys stored in a local file, to
+// decrypt the existing records offline. The file is never modified: if the
+// keys need to be wrapped again with a new master key, it's only done in
+// memory.
+func OpenFileKeyringReadOnly(ctx context.Context, provider KeyProvider, path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the data keys")
+	}
+
+	return OpenKeyring(ctx, provider, data, KeyringOptions{
+		RotationInterval: math.MaxInt64,
+		Persist: func([]byte) error {
+			return nil
+		},
+	})
+}
+
+func writeFileAtomically(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if err = multierr.Append(err, file.Close()); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,231 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeyFile(t *testing.T, path string, ids ...string) {
+	t.Helper()
+
+	lines := []string{"# master keys"}
+	for _, id := range ids {
+		key := make([]byte, dataKeySize)
+		_, err := rand.Read(key)
+		assert.NoError(t, err)
+		lines = append(lines, id+":"+base64.StdEncoding.EncodeToString(key))
+	}
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+}
+
+// appendKeyFile adds a new current master key, forcing the reload of the file
+func appendKeyFile(t *testing.T, p KeyProvider, path string, id string) {
+	t.Helper()
+
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	content = append(content, []byte("\n"+id+":"+base64.StdEncoding.EncodeToString(key))...)
+	assert.NoError(t, os.WriteFile(path, content, 0600))
+
+	modTime := time.Now().Add(time.Second)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	p.(*keyFileProvider).lastCheck = time.Time{}
+}
+
+func newTestKeyFileProvider(t *testing.T, ids ...string) (KeyProvider, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "master-keys")
+	writeKeyFile(t, path, ids...)
+	p, err := NewKeyProvider(context.Background(), Options{
+		ProviderName:   ProviderKeyFile,
+		ProviderParams: fmt.Sprintf(`{"path":%q}`, path),
+	})
+	assert.NoError(t, err)
+	return p, path
+}
+
+func TestKeyFileProvider(t *testing.T) {
+	p, path := newTestKeyFileProvider(t, "k1", "k2")
+	assert.Equal(t, "k2", p.MasterKeyId())
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := p.WrapKey(context.Background(), dataKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", wrapped.MasterKeyId)
+	assert.NotContains(t, string(wrapped.Ciphertext), string(dataKey))
+
+	unwrapped, err := p.UnwrapKey(context.Background(), wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = p.UnwrapKey(context.Background(), &WrappedKey{MasterKeyId: "k3", Ciphertext: wrapped.Ciphertext})
+	assert.ErrorIs(t, err, ErrUnknownMasterKey)
+
+	// The wrapped key is bound to its master key id
+	_, err = p.UnwrapKey(context.Background(), &WrappedKey{MasterKeyId: "k1", Ciphertext: wrapped.Ciphertext})
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	appendKeyFile(t, p, path, "k3")
+	assert.Equal(t, "k3", p.MasterKeyId())
+	unwrapped, err = p.UnwrapKey(context.Background(), wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	assert.NoError(t, p.Close())
+
+	_, err = NewKeyFileProvider(`{}`)
+	assert.Error(t, err)
+	assert.NoError(t, os.WriteFile(path, []byte("k1:not-a-key"), 0600))
+	_, err = NewKeyFileProvider(fmt.Sprintf(`{"path":%q}`, path))
+	assert.Error(t, err)
+
+	_, err = NewKeyProvider(context.Background(), Options{ProviderName: "unknown"})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestKeyring(t *testing.T) {
+	p, path := newTestKeyFileProvider(t, "k1")
+
+	var persisted []byte
+	options := KeyringOptions{
+		Persist: func(data []byte) error {
+			persisted = data
+			return nil
+		},
+	}
+	k, err := OpenKeyring(context.Background(), p, nil, options)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, persisted)
+
+	plaintext := []byte("my-secret-value")
+	ciphertext, err := k.Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(ciphertext))
+	assert.NotContains(t, string(ciphertext), string(plaintext))
+
+	decrypted, err := k.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// The records written before the encryption was enabled are readable
+	decrypted, err = k.Decrypt([]byte("\x0a\x03abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\x0a\x03abc"), decrypted)
+
+	// Rotating the master key rotates the data key on the next encryption
+	appendKeyFile(t, p, path, "k2")
+	ciphertext2, err := k.Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.NotEqual(t, ciphertext[:headerSize], ciphertext2[:headerSize])
+	assert.NotContains(t, string(persisted), `"masterKeyId":"k1"`)
+
+	// The persisted keys decrypt both the old and the new records
+	k2, err := OpenKeyring(context.Background(), p, persisted, options)
+	assert.NoError(t, err)
+	for _, c := range [][]byte{ciphertext, ciphertext2} {
+		decrypted, err = k2.Decrypt(c)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	}
+
+	// Tampering is detected
+	ciphertext2[len(ciphertext2)-1] ^= 1
+	_, err = k2.Decrypt(ciphertext2)
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	// The data keys of another keyring are unknown
+	k3, err := OpenKeyring(context.Background(), p, nil, KeyringOptions{})
+	assert.NoError(t, err)
+	ciphertext3, err := k3.Encrypt(plaintext)
+	assert.NoError(t, err)
+	ciphertext3[5] = 0xff
+	_, err = k.Decrypt(ciphertext3)
+	assert.ErrorIs(t, err, ErrUnknownDataKey)
+}
+
+func TestKeyring_RotationInterval(t *testing.T) {
+	p, _ := newTestKeyFileProvider(t, "k1")
+
+	k, err := OpenKeyring(context.Background(), p, nil, KeyringOptions{RotationInterval: time.Millisecond})
+	assert.NoError(t, err)
+
+	c1, err := k.Encrypt([]byte("a"))
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	c2, err := k.Encrypt([]byte("b"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1[:headerSize], c2[:headerSize])
+}
+
+func TestFileKeyring(t *testing.T) {
+	p, _ := newTestKeyFileProvider(t, "k1")
+	path := filepath.Join(t.TempDir(), "wal", "keys.json")
+
+	k, err := OpenFileKeyring(context.Background(), p, path, 0)
+	assert.NoError(t, err)
+	ciphertext, err := k.Encrypt([]byte("value"))
+	assert.NoError(t, err)
+
+	k, err = OpenFileKeyring(context.Background(), p, path, 0)
+	assert.NoError(t, err)
+	decrypted, err := k.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+}
+
+func TestFileKeyringReadOnly(t *testing.T) {
+	p, masterKeysPath := newTestKeyFileProvider(t, "k1")
+	path := filepath.Join(t.TempDir(), "wal", "keys.json")
+
+	_, err := OpenFileKeyringReadOnly(context.Background(), p, path)
+	assert.Error(t, err)
+
+	k, err := OpenFileKeyring(context.Background(), p, path, 0)
+	assert.NoError(t, err)
+	ciphertext, err := k.Encrypt([]byte("value"))
+	assert.NoError(t, err)
+
+	stored, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	// A new master key would make the keys rotate
+	appendKeyFile(t, p, masterKeysPath, "k2")
+
+	k, err = OpenFileKeyringReadOnly(context.Background(), p, path)
+	assert.NoError(t, err)
+	decrypted, err := k.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), decrypted)
+
+	afterOpen, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, afterOpen)
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func assertWrapUnwrap(t *testing.T, p KeyProvider) {
+	t.Helper()
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := p.WrapKey(context.Background(), dataKey)
+	assert.NoError(t, err)
+	assert.Equal(t, p.MasterKeyId(), wrapped.MasterKeyId)
+
+	unwrapped, err := p.UnwrapKey(context.Background(), wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+	assert.NoError(t, p.Close())
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body := map[string]string{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/oxia":
+			_, _ = fmt.Fprintf(w, `{"data":{"ciphertext":"vault:v1:%s"}}`, body["plaintext"])
+		case "/v1/transit/decrypt/oxia":
+			_, _ = fmt.Fprintf(w, `{"data":{"plaintext":%q}}`, strings.TrimPrefix(body["ciphertext"], "vault:v1:"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, err := NewVaultProvider(fmt.Sprintf(`{"address":%q,"token":"my-token","keyName":"oxia"}`, server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:transit/oxia", p.MasterKeyId())
+	assertWrapUnwrap(t, p)
+
+	// The address and the token default to the environment of the client
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "my-token")
+	p, err = NewVaultProvider(`{"keyName":"oxia"}`)
+	assert.NoError(t, err)
+	assertWrapUnwrap(t, p)
+
+	p, err = NewVaultProvider(`{"token":"wrong","keyName":"oxia"}`)
+	assert.NoError(t, err)
+	_, err = p.WrapKey(context.Background(), []byte("key"))
+	assert.ErrorContains(t, err, "403")
+
+	_, err = NewVaultProvider(`{"address":"http://vault:8200"}`)
+	assert.Error(t, err)
+}
+
+func TestAwsKmsProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=my-access-key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body := struct {
+			KeyId          string
+			Plaintext      []byte
+			CiphertextBlob []byte
+		}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "alias/oxia", body.KeyId)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"KeyId":          body.KeyId,
+				"CiphertextBlob": append([]byte("wrapped:"), body.Plaintext...),
+			})
+		case "TrentService.Decrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"KeyId":     body.KeyId,
+				"Plaintext": []byte(strings.TrimPrefix(string(body.CiphertextBlob), "wrapped:")),
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	// Only the credentials of the environment are available to the default chain
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "my-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "my-secret-key")
+	p, err := NewAwsKmsProvider(fmt.Sprintf(`{"keyId":"alias/oxia","endpoint":%q}`, server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, "aws-kms:alias/oxia", p.MasterKeyId())
+	assertWrapUnwrap(t, p)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	_, err = NewAwsKmsProvider(`{"keyId":"alias/oxia"}`)
+	assert.Error(t, err)
+
+	_, err = NewAwsKmsProvider(`{"region":"us-east-1"}`)
+	assert.Error(t, err)
+}
+
+type fakeGcpKms struct {
+	kmspb.UnimplementedKeyManagementServiceServer
+	keyName string
+}
+
+func (f *fakeGcpKms) Encrypt(_ context.Context, req *kmspb.EncryptRequest) (*kmspb.EncryptResponse, error) {
+	if req.Name != f.keyName {
+		return nil, status.Error(codes.NotFound, "unknown key")
+	}
+	return &kmspb.EncryptResponse{
+		Name:       f.keyName + "/cryptoKeyVersions/1",
+		Ciphertext: append([]byte("wrapped:"), req.Plaintext...),
+	}, nil
+}
+
+func (f *fakeGcpKms) Decrypt(_ context.Context, req *kmspb.DecryptRequest) (*kmspb.DecryptResponse, error) {
+	if req.Name != f.keyName {
+		return nil, status.Error(codes.NotFound, "unknown key")
+	}
+	return &kmspb.DecryptResponse{
+		Plaintext: []byte(strings.TrimPrefix(string(req.Ciphertext), "wrapped:")),
+	}, nil
+}
+
+func TestGcpKmsProvider(t *testing.T) {
+	keyName := "projects/p/locations/global/keyRings/r/cryptoKeys/oxia"
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	kmspb.RegisterKeyManagementServiceServer(server, &fakeGcpKms{keyName: keyName})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	insecureOptions := []option.ClientOption{
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+	p, err := NewGcpKmsProvider(context.Background(),
+		fmt.Sprintf(`{"keyName":%q,"endpoint":%q}`, keyName, listener.Addr().String()), insecureOptions...)
+	assert.NoError(t, err)
+	assert.Equal(t, "gcp-kms:"+keyName, p.MasterKeyId())
+	assertWrapUnwrap(t, p)
+
+	p, err = NewGcpKmsProvider(context.Background(),
+		fmt.Sprintf(`{"keyName":"other","endpoint":%q}`, listener.Addr().String()), insecureOptions...)
+	assert.NoError(t, err)
+	_, err = p.WrapKey(context.Background(), []byte("key"))
+	assert.Equal(t, codes.NotFound, status.Code(errors.Cause(err)))
+	assert.NoError(t, p.Close())
+
+	_, err = NewGcpKmsProvider(context.Background(), `{"endpoint":"localhost:1"}`, insecureOptions...)
+	assert.Error(t, err)
+}
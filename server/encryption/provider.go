@@ -0,0 +1,80 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ProviderKeyFile = "keyfile"
+	ProviderVault   = "vault"
+	ProviderAwsKms  = "aws-kms"
+	ProviderGcpKms  = "gcp-kms"
+)
+
+var (
+	ErrUnsupportedProvider = errors.New("unsupported encryption key provider")
+	ErrUnknownMasterKey    = errors.New("unknown master key")
+	ErrInvalidCiphertext   = errors.New("invalid ciphertext")
+)
+
+var Disabled = Options{}
+
+type Options struct {
+	ProviderName   string
+	ProviderParams string
+}
+
+func (op *Options) IsEnabled() bool {
+	return op != nil && op.ProviderName != ""
+}
+
+// WrappedKey is a data key encrypted with a master key of the provider.
+type WrappedKey struct {
+	MasterKeyId string `json:"masterKeyId"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// KeyProvider holds the master keys, which never leave the provider, and
+// uses them to encrypt and decrypt the data keys.
+type KeyProvider interface {
+	io.Closer
+
+	// MasterKeyId is the id of the master key wrapping the new data keys. When
+	// it changes, the data keys are rotated and wrapped again with it.
+	MasterKeyId() string
+
+	WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error)
+	UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error)
+}
+
+func NewKeyProvider(ctx context.Context, options Options) (KeyProvider, error) {
+	switch options.ProviderName {
+	case ProviderKeyFile:
+		return NewKeyFileProvider(options.ProviderParams)
+	case ProviderVault:
+		return NewVaultProvider(options.ProviderParams)
+	case ProviderAwsKms:
+		return NewAwsKmsProvider(options.ProviderParams)
+	case ProviderGcpKms:
+		return NewGcpKmsProvider(ctx, options.ProviderParams)
+	default:
+		return nil, ErrUnsupportedProvider
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+)
+
+type VaultOptions struct {
+	// Address of the Vault server. Defaults to $VAULT_ADDR
+	Address string `json:"address,omitempty"`
+	// Token to authenticate to Vault. Defaults to $VAULT_TOKEN
+	Token string `json:"token,omitempty"`
+	// Namespace of the key. Defaults to $VAULT_NAMESPACE
+	Namespace string `json:"namespace,omitempty"`
+	// MountPath of the transit secrets engine. Defaults to "transit"
+	MountPath string `json:"mountPath,omitempty"`
+	// KeyName is the transit key wrapping the data keys
+	KeyName string `json:"keyName"`
+}
+
+type vaultProvider struct {
+	options VaultOptions
+	client  *api.Client
+}
+
+// NewVaultProvider wraps the data keys with a key of the transit secrets
+// engine of Vault. The client is configured from the standard VAULT_*
+// environment variables, eg: for the TLS settings, and the options take
+// precedence over them. The transit key is rotated in Vault, and its latest
+// version is used when the data keys are rotated.
+func NewVaultProvider(params string) (KeyProvider, error) {
+	options := VaultOptions{}
+	if err := json.Unmarshal([]byte(params), &options); err != nil {
+		return nil, errors.Wrap(err, "invalid vault provider params")
+	}
+	if options.MountPath == "" {
+		options.MountPath = "transit"
+	}
+	if options.KeyName == "" {
+		return nil, errors.New("the vault key name is required")
+	}
+
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return nil, errors.Wrap(config.Error, "invalid vault configuration")
+	}
+	if options.Address != "" {
+		config.Address = options.Address
+	}
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the vault client")
+	}
+	if options.Token != "" {
+		client.SetToken(options.Token)
+	}
+	if options.Namespace != "" {
+		client.SetNamespace(options.Namespace)
+	}
+
+	return &vaultProvider{
+		options: options,
+		client:  client,
+	}, nil
+}
+
+func (p *vaultProvider) MasterKeyId() string {
+	return fmt.Sprintf("vault:%s/%s", p.options.MountPath, p.options.KeyName)
+}
+
+func (p *vaultProvider) do(ctx context.Context, operation string, data map[string]any, field string) (string, error) {
+	path := fmt.Sprintf("%s/%s/%s", p.options.MountPath, operation, p.options.KeyName)
+	secret, err := p.client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", errors.Errorf("empty response from vault for %s", path)
+	}
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", errors.Errorf("missing %s in the response from vault for %s", field, path)
+	}
+	return value, nil
+}
+
+func (p *vaultProvider) WrapKey(ctx context.Context, dataKey []byte) (*WrappedKey, error) {
+	ciphertext, err := p.do(ctx, "encrypt", map[string]any{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, "ciphertext")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt with vault")
+	}
+
+	return &WrappedKey{
+		MasterKeyId: p.MasterKeyId(),
+		Ciphertext:  []byte(ciphertext),
+	}, nil
+}
+
+func (p *vaultProvider) UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error) {
+	plaintext, err := p.do(ctx, "decrypt", map[string]any{
+		"ciphertext": string(key.Ciphertext),
+	}, "plaintext")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt with vault")
+	}
+	return base64.StdEncoding.DecodeString(plaintext)
+}
+
+func (*vaultProvider) Close() error {
+	return nil
+}
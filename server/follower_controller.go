@@ -21,6 +21,7 @@ import (
 	"log/slog"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -69,9 +70,17 @@ type FollowerController interface {
 	GetStatus(request *proto.GetStatusRequest) (*proto.GetStatusResponse, error)
 	DeleteShard(request *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error)
 
+	// ExportRecords sends the records of the replica, once it was fenced to
+	// merge the shard into another shard
+	ExportRecords(req *proto.ExportRecordsRequest, stream proto.OxiaCoordination_ExportRecordsServer) error
+
 	Term() int64
 	CommitOffset() int64
 	Status() proto.ServingStatus
+
+	// SetNotificationsRetention changes how long the notifications are kept,
+	// including in the databases that are created later from a snapshot
+	SetNotificationsRetention(retention time.Duration)
 }
 
 type followerController struct {
@@ -237,6 +246,16 @@ func (fc *followerController) Status() proto.ServingStatus {
 	return fc.status
 }
 
+func (fc *followerController) SetNotificationsRetention(retention time.Duration) {
+	fc.Lock()
+	defer fc.Unlock()
+
+	fc.config.NotificationsRetentionTime = retention
+	if fc.db != nil {
+		fc.db.SetNotificationsRetention(retention)
+	}
+}
+
 func (fc *followerController) Term() int64 {
 	fc.Lock()
 	defer fc.Unlock()
@@ -301,11 +320,18 @@ func (fc *followerController) NewTerm(req *proto.NewTermRequest) (*proto.NewTerm
 		return nil, err
 	}
 
+	res := &proto.NewTermResponse{HeadEntryId: lastEntryId}
+	if req.CheckMergeable {
+		if res.Mergeable, err = isMergeable(fc.db, lastEntryId); err != nil {
+			return nil, err
+		}
+	}
+
 	fc.log.Info(
 		"Follower successfully initialized in new term",
 		slog.Any("last-entry", lastEntryId),
 	)
-	return &proto.NewTermResponse{HeadEntryId: lastEntryId}, nil
+	return res, nil
 }
 
 func (fc *followerController) Truncate(req *proto.TruncateRequest) (*proto.TruncateResponse, error) {
@@ -443,28 +469,75 @@ func (fc *followerController) append(req *proto.Append, stream proto.OxiaLogRepl
 }
 
 func (fc *followerController) handleReplicateSync(stream proto.OxiaLogReplication_ReplicateServer) {
+	// Acks are cumulative: acking an offset means that all the entries
+	// up to that offset were synced on the follower's wal. Only the entries
+	// that were appended before a sync started are acked after it, since the
+	// ones appended in the meantime might not be durable yet.
+	ackedOffset := fc.wal.LastOffset()
+	syncedOffset := ackedOffset
+	var pendingSince time.Time
+
 	for {
-		fc.Lock()
-		if err := fc.syncCond.Wait(stream.Context()); err != nil {
-			fc.Unlock()
-			fc.closeStream(err)
-			return
+		var waitCtx context.Context
+		var waitCancel context.CancelFunc
+		if syncedOffset > ackedOffset && fc.config.ReplicationAckInterval > 0 {
+			// Don't hold the pending acks for longer than the ack interval
+			waitCtx, waitCancel = context.WithDeadline(stream.Context(), pendingSince.Add(fc.config.ReplicationAckInterval))
+		} else {
+			waitCtx, waitCancel = context.WithCancel(stream.Context())
 		}
+
+		fc.Lock()
+		err := fc.syncCond.Wait(waitCtx)
+		appendedOffset := fc.lastAppendedOffset
 		fc.Unlock()
+		waitCancel()
 
-		oldHeadOffset := fc.wal.LastOffset()
+		if err != nil {
+			if stream.Context().Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+				fc.closeStream(err)
+				return
+			}
 
-		if err := fc.wal.Sync(stream.Context()); err != nil {
+			// The ack interval has expired, ack all the synced entries
+			if err = stream.Send(&proto.Ack{Offset: syncedOffset}); err != nil {
+				fc.closeStream(err)
+				return
+			}
+			ackedOffset = syncedOffset
+			pendingSince = time.Time{}
+			continue
+		}
+
+		if err = fc.wal.Sync(stream.Context()); err != nil {
 			fc.closeStream(err)
 			return
 		}
+		syncedOffset = max(syncedOffset, appendedOffset)
+
+		if fc.config.ReplicationAckInterval == 0 {
+			// Ack all the entries that were synced in the last round, one by one,
+			// to stay compatible with leaders that don't handle cumulative acks
+			for offset := ackedOffset + 1; offset <= syncedOffset; offset++ {
+				if err = stream.Send(&proto.Ack{Offset: offset}); err != nil {
+					fc.closeStream(err)
+					return
+				}
+			}
+			ackedOffset = max(ackedOffset, syncedOffset)
+		} else if syncedOffset > ackedOffset {
+			if pendingSince.IsZero() {
+				pendingSince = time.Now()
+			}
 
-		// Ack all the entries that were synced in the last round
-		newHeadOffset := fc.wal.LastOffset()
-		for offset := oldHeadOffset + 1; offset <= newHeadOffset; offset++ {
-			if err := stream.Send(&proto.Ack{Offset: offset}); err != nil {
-				fc.closeStream(err)
-				return
+			if fc.shouldSendAck(syncedOffset-ackedOffset, pendingSince) {
+				// Ack all the entries that were synced so far, with a single message
+				if err = stream.Send(&proto.Ack{Offset: syncedOffset}); err != nil {
+					fc.closeStream(err)
+					return
+				}
+				ackedOffset = syncedOffset
+				pendingSince = time.Time{}
 			}
 		}
 
@@ -472,6 +545,16 @@ func (fc *followerController) handleReplicateSync(stream proto.OxiaLogReplicatio
 	}
 }
 
+// The acks are coalesced until either the max number of entries is
+// reached or the oldest pending entry has waited for the whole interval.
+func (fc *followerController) shouldSendAck(pendingEntries int64, pendingSince time.Time) bool {
+	if fc.config.ReplicationAckMaxEntries > 0 && pendingEntries >= fc.config.ReplicationAckMaxEntries {
+		return true
+	}
+
+	return time.Since(pendingSince) >= fc.config.ReplicationAckInterval
+}
+
 func (fc *followerController) applyAllCommittedEntries() {
 	for {
 		fc.Lock()
@@ -735,6 +818,26 @@ func (fc *followerController) GetStatus(_ *proto.GetStatusRequest) (*proto.GetSt
 	}, nil
 }
 
+func (fc *followerController) ExportRecords(req *proto.ExportRecordsRequest, stream proto.OxiaCoordination_ExportRecordsServer) error {
+	fc.Lock()
+	defer fc.Unlock()
+
+	if fc.isClosed() {
+		return common.ErrorAlreadyClosed
+	}
+
+	if fc.status != proto.ServingStatus_FENCED || fc.db == nil {
+		return common.ErrorInvalidStatus
+	}
+
+	if req.Term != fc.term {
+		return common.ErrorInvalidTerm
+	}
+
+	fc.log.Info("Exporting the records of the shard to merge it")
+	return exportRecords(fc.db, fc.wal, stream)
+}
+
 func (fc *followerController) DeleteShard(request *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error) {
 	fc.cancel()
 	<-fc.applyEntriesDone
@@ -609,7 +609,11 @@ func TestFollower_HandleSnapshot(t *testing.T) {
 	assert.EqualValues(t, 1, fc.Term())
 
 	stream := newMockServerReplicateStream()
-	go func() { assert.NoError(t, fc.Replicate(stream)) }()
+	replicateDone := make(chan struct{})
+	go func() {
+		assert.NoError(t, fc.Replicate(stream))
+		close(replicateDone)
+	}()
 
 	stream.AddRequest(createAddRequest(t, 1, 0, map[string]string{"a": "0", "b": "1"}, 0))
 
@@ -619,6 +623,9 @@ func TestFollower_HandleSnapshot(t *testing.T) {
 	assert.EqualValues(t, 0, r1.Offset)
 	close(stream.requests)
 
+	// The snapshot is only accepted once the replication stream is closed
+	<-replicateDone
+
 	// Load snapshot into follower
 	snapshot := prepareTestDb(t)
 
@@ -921,6 +928,44 @@ func TestFollower_GetStatus(t *testing.T) {
 	assert.NoError(t, walFactory.Close())
 }
 
+func TestFollower_CoalescedAcks(t *testing.T) {
+	var shardId int64
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	fc, _ := NewFollowerController(Config{
+		ReplicationAckInterval:   1 * time.Second,
+		ReplicationAckMaxEntries: 3,
+	}, common.DefaultNamespace, shardId, walFactory, kvFactory)
+	_, _ = fc.NewTerm(&proto.NewTermRequest{Term: 1})
+
+	stream := newMockServerReplicateStream()
+	go func() {
+		// cancelled due to fc.Close() below
+		assert.ErrorIs(t, fc.Replicate(stream), context.Canceled)
+	}()
+
+	stream.AddRequest(createAddRequest(t, 1, 0, map[string]string{"a": "0"}, wal.InvalidOffset))
+	stream.AddRequest(createAddRequest(t, 1, 1, map[string]string{"a": "1"}, wal.InvalidOffset))
+	stream.AddRequest(createAddRequest(t, 1, 2, map[string]string{"a": "2"}, wal.InvalidOffset))
+
+	// The max number of pending entries is reached, all of them are acked at once
+	r1 := stream.GetResponse()
+	assert.EqualValues(t, 2, r1.Offset)
+
+	// A single pending entry is acked after the ack interval
+	start := time.Now()
+	stream.AddRequest(createAddRequest(t, 1, 3, map[string]string{"a": "3"}, 2))
+
+	r2 := stream.GetResponse()
+	assert.EqualValues(t, 3, r2.Offset)
+	assert.GreaterOrEqual(t, time.Since(start), 1*time.Second)
+
+	assert.NoError(t, fc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
 func TestFollower_HandleSnapshotWithWrongTerm(t *testing.T) {
 	var shardId int64
 	kvFactory, err := kv.NewPebbleKVFactory(&kv.FactoryOptions{
@@ -938,7 +983,11 @@ func TestFollower_HandleSnapshotWithWrongTerm(t *testing.T) {
 	assert.EqualValues(t, 1, fc.Term())
 
 	stream := newMockServerReplicateStream()
-	go func() { assert.NoError(t, fc.Replicate(stream)) }()
+	replicateDone := make(chan struct{})
+	go func() {
+		assert.NoError(t, fc.Replicate(stream))
+		close(replicateDone)
+	}()
 
 	stream.AddRequest(createAddRequest(t, 1, 0, map[string]string{"a": "0", "b": "1"}, 0))
 
@@ -948,6 +997,9 @@ func TestFollower_HandleSnapshotWithWrongTerm(t *testing.T) {
 	assert.EqualValues(t, 0, r1.Offset)
 	close(stream.requests)
 
+	// The snapshot is only accepted once the replication stream is closed
+	<-replicateDone
+
 	// Load snapshot into follower
 	snapshot := prepareTestDb(t)
 
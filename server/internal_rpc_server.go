@@ -33,6 +33,7 @@ import (
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/container"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/proto"
 )
 
@@ -48,7 +49,7 @@ type internalRpcServer struct {
 }
 
 func newInternalRpcServer(grpcProvider container.GrpcProvider, bindAddress string, shardsDirector ShardsDirector,
-	assignmentDispatcher ShardAssignmentsDispatcher, healthServer *health.Server, tlsConf *tls.Config) (*internalRpcServer, error) {
+	assignmentDispatcher ShardAssignmentsDispatcher, healthServer *health.Server, tlsConf *tls.Config, ipFilter *security.IPFilter) (*internalRpcServer, error) {
 	server := &internalRpcServer{
 		shardsDirector:       shardsDirector,
 		assignmentDispatcher: assignmentDispatcher,
@@ -59,11 +60,11 @@ func newInternalRpcServer(grpcProvider container.GrpcProvider, bindAddress strin
 	}
 
 	var err error
-	server.grpcServer, err = grpcProvider.StartGrpcServer("internal", bindAddress, func(registrar grpc.ServiceRegistrar) {
+	server.grpcServer, err = grpcProvider.StartGrpcServer(container.ServerInternal, bindAddress, func(registrar grpc.ServiceRegistrar) {
 		proto.RegisterOxiaCoordinationServer(registrar, server)
 		proto.RegisterOxiaLogReplicationServer(registrar, server)
 		grpc_health_v1.RegisterHealthServer(registrar, server.healthServer)
-	}, tlsConf, &auth.Disabled)
+	}, tlsConf, ipFilter, &auth.Disabled)
 	if err != nil {
 		return nil, err
 	}
@@ -352,6 +353,80 @@ func (s *internalRpcServer) DeleteShard(_ context.Context, req *proto.DeleteShar
 	return s.shardsDirector.DeleteShard(req)
 }
 
+func (s *internalRpcServer) ListShardSessions(ctx context.Context, req *proto.ListShardSessionsRequest) (*proto.ListShardSessionsResponse, error) {
+	leader, err := s.shardsDirector.GetLeader(req.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := leader.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ListShardSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s *internalRpcServer) CloseSession(ctx context.Context, req *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+	s.log.Info(
+		"Received request to close session",
+		slog.Int64("shard", req.ShardId),
+		slog.Int64("session-id", req.SessionId),
+	)
+
+	leader, err := s.shardsDirector.GetLeader(req.ShardId)
+	if err != nil {
+		return nil, err
+	}
+	return leader.CloseSession(ctx, req)
+}
+
+func (s *internalRpcServer) ExportRecords(req *proto.ExportRecordsRequest, srv proto.OxiaCoordination_ExportRecordsServer) error {
+	log := s.log.With(
+		slog.Any("request", req),
+		slog.String("peer", common.GetPeer(srv.Context())),
+	)
+
+	log.Info("Received ExportRecords request")
+
+	follower, err := s.shardsDirector.GetFollower(req.ShardId)
+	if err == nil {
+		err = follower.ExportRecords(req, srv)
+	} else if status.Code(err) == common.CodeNodeIsNotFollower {
+		// If we don't have a follower, fallback to checking the leader controller
+		var leader LeaderController
+		if leader, err = s.shardsDirector.GetLeader(req.ShardId); err == nil {
+			err = leader.ExportRecords(req, srv)
+		}
+	}
+
+	if err != nil {
+		log.Warn(
+			"ExportRecords failed",
+			slog.Any("error", err),
+		)
+	}
+	return err
+}
+
+func (s *internalRpcServer) ImportRecords(c context.Context, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	leader, err := s.shardsDirector.GetLeader(req.ShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := leader.ImportRecords(c, req)
+	if err != nil {
+		s.log.Warn(
+			"ImportRecords failed",
+			slog.Int64("shard", req.ShardId),
+			slog.String("namespace", req.Namespace),
+			slog.String("peer", common.GetPeer(c)),
+			slog.Any("error", err),
+		)
+	}
+	return res, err
+}
+
 func readHeader(md metadata.MD, key string) (value string, err error) {
 	arr := md.Get(key)
 	if len(arr) == 0 {
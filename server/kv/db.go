@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
+	"strconv"
 	"time"
 
 	pb "google.golang.org/protobuf/proto"
@@ -37,6 +39,9 @@ var (
 	ErrMissingPartitionKey   = errors.New("oxia: sequential key operation requires partition key")
 	ErrMissingSequenceDeltas = errors.New("oxia: sequential key operation missing some sequence deltas")
 	ErrSequenceDeltaIsZero   = errors.New("oxia: sequential key operation requires first delta do be > 0")
+	ErrIncrementSequenceKey  = errors.New("oxia: increment operation cannot use sequential keys")
+	ErrInvalidCounterValue   = errors.New("oxia: invalid counter value")
+	ErrMergedKeyExists       = errors.New("oxia: key of the merged shard already exists")
 )
 
 const (
@@ -53,7 +58,12 @@ type RangeScanIterator interface {
 	io.Closer
 
 	Valid() bool
+	Key() string
 	Value() (*proto.GetResponse, error)
+
+	// Entry returns the record as it's stored, including the fields that are
+	// not part of its version, eg: its partition key
+	Entry() (*proto.StorageEntry, error)
 	Next() bool
 }
 
@@ -66,6 +76,10 @@ type DB interface {
 	RangeScan(request *proto.RangeScanRequest) (RangeScanIterator, error)
 	ReadCommitOffset() (int64, error)
 
+	// ModifiedBefore returns the keys of the records that were last modified
+	// before the timestamp, from the least recently modified
+	ModifiedBefore(timestamp uint64) (KeyIterator, error)
+
 	ReadNextNotifications(ctx context.Context, startOffset int64) ([]*proto.NotificationBatch, error)
 
 	UpdateTerm(newTerm int64) error
@@ -73,6 +87,16 @@ type DB interface {
 
 	Snapshot() (Snapshot, error)
 
+	// DiskSpaceUsage returns the space used by the database files on disk
+	DiskSpaceUsage() int64
+
+	// StorageUsage returns the size of the records written by a principal,
+	// counting the keys and the values
+	StorageUsage(principal string) (int64, error)
+
+	// SetNotificationsRetention changes how long the notifications are kept
+	SetNotificationsRetention(retention time.Duration)
+
 	// Delete and close the database and all its files
 	Delete() error
 }
@@ -118,6 +142,10 @@ func NewDB(namespace string, shardId int64, factory Factory, notificationRetenti
 		return nil, err
 	}
 
+	if err := db.buildModificationIndex(); err != nil {
+		return nil, multierr.Combine(err, kv.Close())
+	}
+
 	db.notificationsTracker = newNotificationsTracker(namespace, shardId, commitOffset, kv, notificationRetentionTime, clock)
 	return db, nil
 }
@@ -144,6 +172,22 @@ func (d *db) Snapshot() (Snapshot, error) {
 	return d.kv.Snapshot()
 }
 
+func (d *db) DiskSpaceUsage() int64 {
+	return d.kv.DiskSpaceUsage()
+}
+
+func (d *db) StorageUsage(principal string) (int64, error) {
+	res, err := applyGet(d.kv, &proto.GetRequest{Key: storageUsageKey(principal), IncludeValue: true})
+	if err != nil || res.Status != proto.Status_OK {
+		return 0, err
+	}
+	return strconv.ParseInt(string(res.Value), 10, 64)
+}
+
+func (d *db) SetNotificationsRetention(retention time.Duration) {
+	d.notificationsTracker.trimmer.setRetention(retention)
+}
+
 func (d *db) Close() error {
 	return multierr.Combine(
 		d.notificationsTracker.Close(),
@@ -165,10 +209,11 @@ func now() uint64 {
 func (d *db) applyWriteRequest(b *proto.WriteRequest, batch WriteBatch, commitOffset int64, timestamp uint64, updateOperationCallback UpdateOperationCallback) (*notifications, *proto.WriteResponse, error) {
 	res := &proto.WriteResponse{}
 	notifications := newNotifications(d.shardId, commitOffset, timestamp)
+	usage := newStorageUsage(b.Principal)
 
 	d.putCounter.Add(len(b.Puts))
 	for _, putReq := range b.Puts {
-		pr, err := d.applyPut(commitOffset, batch, notifications, putReq, timestamp, updateOperationCallback)
+		pr, err := d.applyPut(commitOffset, batch, notifications, usage, putReq, timestamp, updateOperationCallback)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -177,7 +222,7 @@ func (d *db) applyWriteRequest(b *proto.WriteRequest, batch WriteBatch, commitOf
 
 	d.deleteCounter.Add(len(b.Deletes))
 	for _, delReq := range b.Deletes {
-		dr, err := d.applyDelete(batch, notifications, delReq, updateOperationCallback)
+		dr, err := d.applyDelete(batch, notifications, usage, delReq, updateOperationCallback)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -187,7 +232,7 @@ func (d *db) applyWriteRequest(b *proto.WriteRequest, batch WriteBatch, commitOf
 
 	d.deleteRangesCounter.Add(len(b.DeleteRanges))
 	for _, delRangeReq := range b.DeleteRanges {
-		dr, err := d.applyDeleteRange(batch, notifications, delRangeReq, updateOperationCallback)
+		dr, err := d.applyDeleteRange(batch, notifications, usage, delRangeReq, updateOperationCallback)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -195,6 +240,22 @@ func (d *db) applyWriteRequest(b *proto.WriteRequest, batch WriteBatch, commitOf
 		res.DeleteRanges = append(res.DeleteRanges, dr)
 	}
 
+	if b.MergedShardId != nil {
+		err := d.applyMergedRecords(batch, usage, b.GetMergedShardId(), commitOffset)
+		switch {
+		case errors.Is(err, ErrMergedKeyExists):
+			res.MergeStatus = proto.Status_KEY_ALREADY_EXISTS.Enum()
+		case err != nil:
+			return nil, nil, err
+		default:
+			res.MergeStatus = proto.Status_OK.Enum()
+		}
+	}
+
+	if err := usage.apply(batch); err != nil {
+		return nil, nil, err
+	}
+
 	return notifications, res, nil
 }
 
@@ -241,7 +302,7 @@ func (*db) addNotifications(batch WriteBatch, notifications *notifications) erro
 
 func (d *db) addCommitOffset(commitOffset int64, batch WriteBatch, timestamp uint64) error {
 	commitOffsetValue := []byte(fmt.Sprintf("%d", commitOffset))
-	_, err := d.applyPut(commitOffset, batch, nil, &proto.PutRequest{
+	_, err := d.applyPut(commitOffset, batch, nil, nil, &proto.PutRequest{
 		Key:               commitOffsetKey,
 		Value:             commitOffsetValue,
 		ExpectedVersionId: nil,
@@ -286,7 +347,7 @@ type rangeScanIterator struct {
 	timer metrics.Timer
 }
 
-func (it *rangeScanIterator) Value() (*proto.GetResponse, error) {
+func (it *rangeScanIterator) Entry() (*proto.StorageEntry, error) {
 	value, err := it.KeyValueIterator.Value()
 	if err != nil {
 		return nil, err
@@ -296,6 +357,14 @@ func (it *rangeScanIterator) Value() (*proto.GetResponse, error) {
 	if err = deserialize(value, se); err != nil {
 		return nil, err
 	}
+	return se, nil
+}
+
+func (it *rangeScanIterator) Value() (*proto.GetResponse, error) {
+	se, err := it.Entry()
+	if err != nil {
+		return nil, err
+	}
 
 	res := &proto.GetResponse{
 		Key:    pb.String(it.Key()),
@@ -358,7 +427,7 @@ func (d *db) ReadCommitOffset() (int64, error) {
 func (d *db) UpdateTerm(newTerm int64) error {
 	batch := d.kv.NewWriteBatch()
 
-	if _, err := d.applyPut(wal.InvalidOffset, batch, nil, &proto.PutRequest{
+	if _, err := d.applyPut(wal.InvalidOffset, batch, nil, nil, &proto.PutRequest{
 		Key:   termKey,
 		Value: []byte(fmt.Sprintf("%d", newTerm)),
 	}, now(), NoOpCallback); err != nil {
@@ -397,15 +466,23 @@ func (d *db) ReadTerm() (term int64, err error) {
 	return term, nil
 }
 
-func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notifications, putReq *proto.PutRequest, timestamp uint64, updateOperationCallback UpdateOperationCallback) (*proto.PutResponse, error) {
+func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notifications, usage *storageUsage, putReq *proto.PutRequest, timestamp uint64, updateOperationCallback UpdateOperationCallback) (*proto.PutResponse, error) {
 	var se *proto.StorageEntry
 	var err error
 	var newKey string
-	if len(putReq.GetSequenceKeyDelta()) > 0 {
+	var incrementedValue *int64
+	prefixKey := putReq.Key
+	switch {
+	case putReq.IncrementDelta != nil && len(putReq.GetSequenceKeyDelta()) > 0:
+		err = ErrIncrementSequenceKey
+	case len(putReq.GetSequenceKeyDelta()) > 0:
 		newKey, err = generateUniqueKeyFromSequences(batch, putReq)
 		putReq.Key = newKey
-	} else {
+	default:
 		se, err = checkExpectedVersionId(batch, putReq.Key, putReq.ExpectedVersionId)
+		if err == nil && putReq.IncrementDelta != nil {
+			incrementedValue, err = applyIncrement(putReq, se)
+		}
 	}
 
 	switch {
@@ -413,6 +490,11 @@ func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notif
 		return &proto.PutResponse{
 			Status: proto.Status_UNEXPECTED_VERSION_ID,
 		}, nil
+	case errors.Is(err, ErrInvalidCounterValue):
+		se.ReturnToVTPool()
+		return &proto.PutResponse{
+			Status: proto.Status_INVALID_COUNTER_VALUE,
+		}, nil
 	case err != nil:
 		return nil, errors.Wrap(err, "oxia db: failed to apply batch")
 	}
@@ -428,6 +510,11 @@ func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notif
 		}, nil
 	}
 
+	usage.removed(putReq.Key, se)
+	var previousModification *uint64
+	if se != nil {
+		previousModification = pb.Uint64(se.ModificationTimestamp)
+	}
 	if se == nil {
 		se = proto.StorageEntryFromVTPool()
 		se.VersionId = commitOffset
@@ -447,6 +534,10 @@ func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notif
 		se.ClientIdentity = putReq.ClientIdentity
 		se.PartitionKey = putReq.PartitionKey
 	}
+	if usage != nil {
+		se.Principal = usage.principal
+	}
+	usage.added(putReq.Key, se)
 
 	defer se.ReturnToVTPool()
 
@@ -459,6 +550,16 @@ func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notif
 		return nil, err
 	}
 
+	if err = updateModificationIndex(batch, putReq.Key, previousModification, &se.ModificationTimestamp); err != nil {
+		return nil, err
+	}
+
+	if newKey != "" {
+		if err = updateLastGeneratedKey(batch, prefixKey, newKey); err != nil {
+			return nil, err
+		}
+	}
+
 	if notifications != nil {
 		notifications.Modified(putReq.Key, se.VersionId, se.ModificationsCount)
 	}
@@ -478,14 +579,36 @@ func (d *db) applyPut(commitOffset int64, batch WriteBatch, notifications *notif
 		slog.Any("version", version),
 	)
 
-	pr := &proto.PutResponse{Version: version}
+	pr := &proto.PutResponse{Version: version, IncrementedValue: incrementedValue}
 	if newKey != "" {
 		pr.Key = &newKey
 	}
 	return pr, nil
 }
 
-func (d *db) applyDelete(batch WriteBatch, notifications *notifications, delReq *proto.DeleteRequest, updateOperationCallback UpdateOperationCallback) (*proto.DeleteResponse, error) {
+// Replace the value of the put request with the current value of the counter
+// plus the delta. Since the increment is computed when applying the entry,
+// it's the same on all the replicas.
+func applyIncrement(putReq *proto.PutRequest, se *proto.StorageEntry) (*int64, error) {
+	var current int64
+	if se != nil {
+		var err error
+		if current, err = strconv.ParseInt(string(se.Value), 10, 64); err != nil {
+			return nil, ErrInvalidCounterValue
+		}
+	}
+
+	delta := *putReq.IncrementDelta
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return nil, ErrInvalidCounterValue
+	}
+
+	value := current + delta
+	putReq.Value = []byte(strconv.FormatInt(value, 10))
+	return &value, nil
+}
+
+func (d *db) applyDelete(batch WriteBatch, notifications *notifications, usage *storageUsage, delReq *proto.DeleteRequest, updateOperationCallback UpdateOperationCallback) (*proto.DeleteResponse, error) {
 	se, err := checkExpectedVersionId(batch, delReq.Key, delReq.ExpectedVersionId)
 	if se != nil {
 		defer se.ReturnToVTPool()
@@ -507,6 +630,10 @@ func (d *db) applyDelete(batch WriteBatch, notifications *notifications, delReq
 		if err = batch.Delete(delReq.Key); err != nil {
 			return &proto.DeleteResponse{}, err
 		}
+		if err = updateModificationIndex(batch, delReq.Key, &se.ModificationTimestamp, nil); err != nil {
+			return &proto.DeleteResponse{}, err
+		}
+		usage.removed(delReq.Key, se)
 
 		if notifications != nil {
 			notifications.Deleted(delReq.Key)
@@ -520,24 +647,35 @@ func (d *db) applyDelete(batch WriteBatch, notifications *notifications, delReq
 	}
 }
 
-func (*db) applyDeleteRangeNotifications(batch WriteBatch, notifications *notifications, delReq *proto.DeleteRangeRequest, updateOperationCallback UpdateOperationCallback) error {
-	if notifications == nil && updateOperationCallback == NoOpCallback {
-		return nil
+func (*db) applyDeleteRangeNotifications(batch WriteBatch, notifications *notifications, usage *storageUsage, delReq *proto.DeleteRangeRequest, updateOperationCallback UpdateOperationCallback) error {
+	trackUsage, err := usage.tracked(batch)
+	if err != nil {
+		return errors.Wrap(err, "oxia db: failed to delete range")
 	}
 
+	// The deleted records are always read, to remove them from the
+	// modification index
 	it, err := batch.KeyRangeScan(delReq.StartInclusive, delReq.EndExclusive)
 	if err != nil {
 		return err
 	}
 
-	for it.Next() {
+	for ; it.Valid(); it.Next() {
 		if notifications != nil {
-			notifications.Deleted(it.Key())
+			notifications.RangeDeleted(it.Key())
 		}
 		err := updateOperationCallback.OnDelete(batch, it.Key())
 		if err != nil {
 			return errors.Wrap(multierr.Combine(err, it.Close()), "oxia db: failed to delete range")
 		}
+		if trackUsage {
+			if err := removeStorageUsage(batch, usage, it.Key()); err != nil {
+				return errors.Wrap(multierr.Combine(err, it.Close()), "oxia db: failed to delete range")
+			}
+		}
+		if err := removeFromModificationIndex(batch, it.Key()); err != nil {
+			return errors.Wrap(multierr.Combine(err, it.Close()), "oxia db: failed to delete range")
+		}
 	}
 
 	if err := it.Close(); err != nil {
@@ -547,8 +685,8 @@ func (*db) applyDeleteRangeNotifications(batch WriteBatch, notifications *notifi
 	return nil
 }
 
-func (d *db) applyDeleteRange(batch WriteBatch, notifications *notifications, delReq *proto.DeleteRangeRequest, updateOperationCallback UpdateOperationCallback) (*proto.DeleteRangeResponse, error) {
-	if err := d.applyDeleteRangeNotifications(batch, notifications, delReq, updateOperationCallback); err != nil {
+func (d *db) applyDeleteRange(batch WriteBatch, notifications *notifications, usage *storageUsage, delReq *proto.DeleteRangeRequest, updateOperationCallback UpdateOperationCallback) (*proto.DeleteRangeResponse, error) {
+	if err := d.applyDeleteRangeNotifications(batch, notifications, usage, delReq, updateOperationCallback); err != nil {
 		return nil, err
 	}
 
@@ -161,6 +161,29 @@ func TestDB_Notifications(t *testing.T) {
 	assert.Equal(t, proto.NotificationType_KEY_MODIFIED, n.Type)
 	assert.EqualValues(t, 4, *n.VersionId)
 
+	// All the keys in a deleted range are notified
+	t5 := now()
+	_, _ = db.ProcessWrite(&proto.WriteRequest{
+		DeleteRanges: []*proto.DeleteRangeRequest{{
+			StartInclusive: "c",
+			EndExclusive:   "e",
+		}},
+	}, 5, t5, NoOpCallback)
+
+	notifications, err = db.ReadNextNotifications(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(notifications))
+
+	nb = notifications[0]
+	assert.EqualValues(t, 5, nb.Offset)
+	assert.Equal(t, 2, len(nb.Notifications))
+	for _, key := range []string{"c", "d"} {
+		n, found = nb.Notifications[key]
+		assert.True(t, found)
+		assert.Equal(t, proto.NotificationType_KEY_RANGE_DELETED, n.Type)
+		assert.Nil(t, n.VersionId)
+	}
+
 	assert.NoError(t, db.Close())
 	assert.NoError(t, factory.Close())
 }
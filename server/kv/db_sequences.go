@@ -21,10 +21,22 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/proto"
 )
 
-const maxSequence = uint64(math.MaxUint64)
+const (
+	maxSequence = uint64(math.MaxUint64)
+
+	sequencesPrefix = common.InternalKeyPrefix + "sequence/"
+)
+
+// The last key generated for a sequence is kept under an internal key, so that
+// the sequence keeps increasing even after its last records are deleted, as it
+// happens when the ephemeral records of an expired session are removed.
+func sequenceLastKey(prefixKey string) string {
+	return sequencesPrefix + prefixKey
+}
 
 func generateUniqueKeyFromSequences(batch WriteBatch, req *proto.PutRequest) (string, error) {
 	if req.PartitionKey == nil {
@@ -77,10 +89,17 @@ func findCurrentLastKeyInSequence(wb WriteBatch, req *proto.PutRequest) ([]strin
 
 	if errors.Is(err, ErrKeyNotFound) || !strings.HasPrefix(lastKeyInSequence, prefixKey) {
 		lastKeyInSequence = ""
-	} else {
-		lastKeyInSequence = strings.TrimPrefix(lastKeyInSequence, prefixKey)
 	}
 
+	lastGeneratedKey, err := getLastGeneratedKey(wb, prefixKey)
+	if err != nil {
+		return nil, err
+	}
+	if lastGeneratedKey > lastKeyInSequence {
+		lastKeyInSequence = lastGeneratedKey
+	}
+	lastKeyInSequence = strings.TrimPrefix(lastKeyInSequence, prefixKey)
+
 	parts := strings.Split(lastKeyInSequence, "-")[1:]
 	if len(parts) > len(req.SequenceKeyDelta) {
 		// The request has less sequence key deltas than there are already
@@ -89,3 +108,22 @@ func findCurrentLastKeyInSequence(wb WriteBatch, req *proto.PutRequest) ([]strin
 	}
 	return parts, nil
 }
+
+func getLastGeneratedKey(wb WriteBatch, prefixKey string) (string, error) {
+	value, closer, err := wb.Get(sequenceLastKey(prefixKey))
+	if errors.Is(err, ErrKeyNotFound) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	lastKey := string(value)
+	if err = closer.Close(); err != nil {
+		return "", err
+	}
+	return lastKey, nil
+}
+
+func updateLastGeneratedKey(wb WriteBatch, prefixKey string, newKey string) error {
+	return wb.Put(sequenceLastKey(prefixKey), []byte(newKey))
+}
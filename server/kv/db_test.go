@@ -15,7 +15,9 @@
 package kv
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -790,6 +792,50 @@ func TestDB_SequentialKeys(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("a-%020d-%020d-%020d", 20, 18, 15), resp.GetPuts()[0].GetKey())
 }
 
+func TestDB_SequentialKeysAfterDelete(t *testing.T) {
+	factory, err := NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	db, err := NewDB(common.DefaultNamespace, 1, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+
+	for i := 1; i <= 2; i++ {
+		resp, err := db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+			Key:              "a",
+			Value:            []byte("0"),
+			PartitionKey:     pb.String("x"),
+			SequenceKeyDelta: []uint64{1},
+		}}}, int64(i), 0, NoOpCallback)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("a-%020d", i), resp.GetPuts()[0].GetKey())
+	}
+
+	// Removing the last records must not let the sequence go back
+	_, err = db.ProcessWrite(&proto.WriteRequest{DeleteRanges: []*proto.DeleteRangeRequest{{
+		StartInclusive: "a-",
+		EndExclusive:   "a-~",
+	}}}, 3, 0, NoOpCallback)
+	assert.NoError(t, err)
+
+	resp, err := db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+		Key:              "a",
+		Value:            []byte("0"),
+		PartitionKey:     pb.String("x"),
+		SequenceKeyDelta: []uint64{1},
+	}}}, 4, 0, NoOpCallback)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("a-%020d", 3), resp.GetPuts()[0].GetKey())
+
+	// The internal keys are not notified
+	notifications, err := db.ReadNextNotifications(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	assert.Len(t, notifications[0].Notifications, 1)
+	assert.Contains(t, notifications[0].Notifications, fmt.Sprintf("a-%020d", 3))
+
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+}
+
 func rangeScanIteratorToSlice(it RangeScanIterator, err error) []string {
 	assert.NoError(nil, err)
 	var keys []string
@@ -802,6 +848,70 @@ func rangeScanIteratorToSlice(it RangeScanIterator, err error) []string {
 	return keys
 }
 
+func TestDB_Increment(t *testing.T) {
+	factory, err := NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	db, err := NewDB(common.DefaultNamespace, 1, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+
+	increment := func(offset int64, key string, delta int64) *proto.PutResponse {
+		resp, err := db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+			Key:            key,
+			IncrementDelta: &delta,
+		}}}, offset, 0, NoOpCallback)
+		assert.NoError(t, err)
+		return resp.Puts[0]
+	}
+
+	// The counter starts from 0
+	pr := increment(0, "a", 5)
+	assert.Equal(t, proto.Status_OK, pr.Status)
+	assert.EqualValues(t, 5, pr.GetIncrementedValue())
+	assert.EqualValues(t, 0, pr.Version.ModificationsCount)
+
+	pr = increment(1, "a", -7)
+	assert.Equal(t, proto.Status_OK, pr.Status)
+	assert.EqualValues(t, -2, pr.GetIncrementedValue())
+	assert.EqualValues(t, 1, pr.Version.ModificationsCount)
+
+	gr, err := db.Get(&proto.GetRequest{Key: "a", IncludeValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("-2"), gr.Value)
+
+	// Overflow
+	pr = increment(2, "a", math.MinInt64)
+	assert.Equal(t, proto.Status_INVALID_COUNTER_VALUE, pr.Status)
+
+	// Not a counter
+	_, err = db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+		Key:   "b",
+		Value: []byte("not-a-number"),
+	}}}, 3, 0, NoOpCallback)
+	assert.NoError(t, err)
+	pr = increment(4, "b", 1)
+	assert.Equal(t, proto.Status_INVALID_COUNTER_VALUE, pr.Status)
+
+	// Conditional increment
+	resp, err := db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+		Key:               "a",
+		IncrementDelta:    pb.Int64(1),
+		ExpectedVersionId: pb.Int64(0),
+	}}}, 5, 0, NoOpCallback)
+	assert.NoError(t, err)
+	assert.Equal(t, proto.Status_UNEXPECTED_VERSION_ID, resp.Puts[0].Status)
+
+	_, err = db.ProcessWrite(&proto.WriteRequest{Puts: []*proto.PutRequest{{
+		Key:              "c",
+		PartitionKey:     pb.String("x"),
+		SequenceKeyDelta: []uint64{1},
+		IncrementDelta:   pb.Int64(1),
+	}}}, 6, 0, NoOpCallback)
+	assert.ErrorIs(t, err, ErrIncrementSequenceKey)
+
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+}
+
 func TestDBRangeScan(t *testing.T) {
 	factory, err := NewPebbleKVFactory(testKVOptions)
 	assert.NoError(t, err)
@@ -865,3 +975,113 @@ func TestDBRangeScan(t *testing.T) {
 	assert.NoError(t, db.Close())
 	assert.NoError(t, factory.Close())
 }
+
+func TestDB_StorageUsage(t *testing.T) {
+	factory, err := NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	db, err := NewDB(common.DefaultNamespace, 1, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+
+	alice := "alice"
+	bob := "spiffe://example.com/bob"
+	offset := int64(0)
+	write := func(principal *string, request *proto.WriteRequest) {
+		request.Principal = principal
+		_, err := db.ProcessWrite(request, offset, 0, NoOpCallback)
+		assert.NoError(t, err)
+		offset++
+	}
+	usage := func(principal string) int64 {
+		u, err := db.StorageUsage(principal)
+		assert.NoError(t, err)
+		return u
+	}
+
+	write(&alice, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 10)}}})
+	write(&bob, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "b", Value: make([]byte, 5)}}})
+	assert.EqualValues(t, 11, usage(alice))
+	assert.EqualValues(t, 6, usage(bob))
+
+	// The previous value is replaced
+	write(&alice, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 20)}}})
+	assert.EqualValues(t, 21, usage(alice))
+
+	// The record is owned by its last writer
+	write(&bob, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 1)}}})
+	assert.EqualValues(t, 0, usage(alice))
+	assert.EqualValues(t, 8, usage(bob))
+	write(nil, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 1)}}})
+	assert.EqualValues(t, 6, usage(bob))
+
+	// The records are released by any delete
+	write(&alice, &proto.WriteRequest{Puts: []*proto.PutRequest{
+		{Key: "c1", Value: make([]byte, 3)},
+		{Key: "c2", Value: make([]byte, 3)},
+	}})
+	assert.EqualValues(t, 10, usage(alice))
+	write(nil, &proto.WriteRequest{
+		Deletes:      []*proto.DeleteRequest{{Key: "b"}},
+		DeleteRanges: []*proto.DeleteRangeRequest{{StartInclusive: "c", EndExclusive: "d"}},
+	})
+	assert.EqualValues(t, 0, usage(alice))
+	assert.EqualValues(t, 0, usage(bob))
+
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+}
+
+func TestDB_ModifiedBefore(t *testing.T) {
+	factory, err := NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	database, err := NewDB(common.DefaultNamespace, 1, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+	db := database.(*db)
+
+	offset := int64(0)
+	write := func(timestamp uint64, request *proto.WriteRequest) {
+		_, err := db.ProcessWrite(request, offset, timestamp, NoOpCallback)
+		assert.NoError(t, err)
+		offset++
+	}
+	modifiedBefore := func(timestamp uint64) []string {
+		it, err := db.ModifiedBefore(timestamp)
+		assert.NoError(t, err)
+		var keys []string
+		for ; it.Valid(); it.Next() {
+			keys = append(keys, it.Key())
+		}
+		assert.NoError(t, it.Close())
+		return keys
+	}
+
+	write(10, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "/a/b", Value: []byte("0")}}})
+	write(20, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "c%d", Value: []byte("0")}}})
+	write(30, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "e1", Value: []byte("0")}, {Key: "e2", Value: []byte("0")}}})
+	assert.Empty(t, modifiedBefore(10))
+	assert.Equal(t, []string{"/a/b", "c%d"}, modifiedBefore(30))
+	assert.Equal(t, []string{"/a/b", "c%d", "e1", "e2"}, modifiedBefore(31))
+
+	// The records are moved in the index when they are modified, and
+	// removed when they are deleted
+	write(40, &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "/a/b", Value: []byte("1")}}})
+	write(50, &proto.WriteRequest{
+		Deletes:      []*proto.DeleteRequest{{Key: "c%d"}},
+		DeleteRanges: []*proto.DeleteRangeRequest{{StartInclusive: "e1", EndExclusive: "e2"}},
+	})
+	assert.Equal(t, []string{"e2", "/a/b"}, modifiedBefore(100))
+
+	// The index of a shard written before the index existed is built when
+	// the shard is opened
+	batch := db.kv.NewWriteBatch()
+	assert.NoError(t, batch.DeleteRange(modificationIndexPrefix, modificationIndexTimeBound(math.MaxUint64)))
+	assert.NoError(t, batch.Delete(modificationIndexReadyKey))
+	assert.NoError(t, batch.Commit())
+	assert.NoError(t, batch.Close())
+	assert.Empty(t, modifiedBefore(100))
+
+	assert.NoError(t, db.buildModificationIndex())
+	assert.Equal(t, []string{"e2", "/a/b"}, modifiedBefore(100))
+
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
+	"github.com/streamnative/oxia/server/wal"
+)
+
+// DBMetadata is the internal state of a shard database.
+type DBMetadata struct {
+	CommitOffset int64 `json:"commitOffset"`
+	Term         int64 `json:"term"`
+	Encrypted    bool  `json:"encrypted"`
+}
+
+// Record is a record of the database, as stored on disk.
+type Record struct {
+	Key string
+
+	// Value is the decrypted value. It's a StorageEntry for the records of
+	// the users, while some internal records have their own format
+	Value []byte
+
+	// Size is the size of the key and of the stored value
+	Size int
+}
+
+func (r Record) StorageEntry() (*proto.StorageEntry, error) {
+	se := &proto.StorageEntry{}
+	if err := se.UnmarshalVT(r.Value); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the value of %s", r.Key)
+	}
+	return se, nil
+}
+
+// Inspector reads the database of a shard offline. The database is opened
+// in read-only mode, so it can't be opened while the server is running.
+type Inspector struct {
+	db      *pebble.DB
+	keyring *encryption.Keyring
+}
+
+// NewInspector opens the database of a shard. The key provider is only
+// needed when the database is encrypted.
+func NewInspector(dataDir string, namespace string, shard int64, provider encryption.KeyProvider) (*Inspector, error) {
+	path := filepath.Join(dataDir, namespace, fmt.Sprint("shard-", shard))
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrapf(err, "failed to open the database of shard %s / %d", namespace, shard)
+	}
+
+	db, err := pebble.Open(path, &pebble.Options{
+		Comparer: OxiaSlashSpanComparer,
+		ReadOnly: true,
+		Logger: &pebbleLogger{
+			slog.With(
+				slog.String("component", "pebble"),
+				slog.Int64("shard", shard),
+			),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open database at %s", path)
+	}
+
+	i := &Inspector{db: db}
+	if provider != nil {
+		if i.keyring, err = i.openKeyring(provider); err != nil {
+			return nil, multierr.Append(err, db.Close())
+		}
+	}
+	return i, nil
+}
+
+func (i *Inspector) openKeyring(provider encryption.KeyProvider) (*encryption.Keyring, error) {
+	data, err := i.readEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.New("the database is not encrypted")
+	}
+
+	return encryption.OpenKeyring(context.Background(), provider, data, encryption.KeyringOptions{
+		RotationInterval: math.MaxInt64,
+		Persist: func([]byte) error {
+			return nil
+		},
+	})
+}
+
+func (i *Inspector) readEncryptionKeys() ([]byte, error) {
+	value, closer, err := i.db.Get([]byte(encryptionKeysKey))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	se := &proto.StorageEntry{}
+	if err = se.UnmarshalVT(value); err != nil {
+		return nil, errors.Wrap(err, "failed to read the data keys")
+	}
+	return se.Value, nil
+}
+
+func (i *Inspector) Close() error {
+	return i.db.Close()
+}
+
+func (i *Inspector) decrypt(key string, value []byte) ([]byte, error) {
+	// The data keys are the only values that are never encrypted
+	if key == encryptionKeysKey {
+		return value, nil
+	}
+
+	if i.keyring != nil {
+		plaintext, err := i.keyring.Decrypt(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt the value of %s", key)
+		}
+		return plaintext, nil
+	} else if encryption.IsEncrypted(value) {
+		return nil, errors.New("the database is encrypted, but the encryption is not configured")
+	}
+	return value, nil
+}
+
+// Scan calls the function with each record whose key is in the range
+// [lowerBound, upperBound), including the internal ones. An empty upper
+// bound means that the range has no upper bound.
+func (i *Inspector) Scan(lowerBound string, upperBound string, f func(record Record) error) error {
+	opts := &pebble.IterOptions{LowerBound: []byte(lowerBound)}
+	if upperBound != "" {
+		opts.UpperBound = []byte(upperBound)
+	}
+
+	it, err := i.db.NewIter(opts)
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		key := string(it.Key())
+		value, err := it.ValueAndErr()
+		if err != nil {
+			return multierr.Append(err, it.Close())
+		}
+
+		plaintext, err := i.decrypt(key, value)
+		if err != nil {
+			return multierr.Append(err, it.Close())
+		}
+
+		if err = f(Record{Key: key, Value: plaintext, Size: len(key) + len(value)}); err != nil {
+			return multierr.Append(err, it.Close())
+		}
+	}
+	return it.Close()
+}
+
+func (i *Inspector) readInt(key string, defaultValue int64) (int64, error) {
+	value, closer, err := i.db.Get([]byte(key))
+	if errors.Is(err, pebble.ErrNotFound) {
+		return defaultValue, nil
+	} else if err != nil {
+		return defaultValue, err
+	}
+	defer closer.Close()
+
+	plaintext, err := i.decrypt(key, value)
+	if err != nil {
+		return defaultValue, err
+	}
+	entry, err := Record{Key: key, Value: plaintext}.StorageEntry()
+	if err != nil {
+		return defaultValue, err
+	}
+
+	var res int64
+	if _, err = fmt.Sscanf(string(entry.Value), "%d", &res); err != nil {
+		return defaultValue, err
+	}
+	return res, nil
+}
+
+// Metadata returns the internal state of the database.
+func (i *Inspector) Metadata() (DBMetadata, error) {
+	var err error
+	metadata := DBMetadata{}
+	if metadata.CommitOffset, err = i.readInt(commitOffsetKey, wal.InvalidOffset); err != nil {
+		return metadata, err
+	}
+	if metadata.Term, err = i.readInt(termKey, wal.InvalidTerm); err != nil {
+		return metadata, err
+	}
+
+	keys, err := i.readEncryptionKeys()
+	metadata.Encrypted = keys != nil
+	return metadata, err
+}
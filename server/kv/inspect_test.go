@@ -0,0 +1,139 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
+	"github.com/streamnative/oxia/server/wal"
+)
+
+func writeInspectorTestDB(t *testing.T, options *FactoryOptions) {
+	t.Helper()
+
+	factory, err := NewPebbleKVFactory(options)
+	assert.NoError(t, err)
+	db, err := NewDB(common.DefaultNamespace, 1, factory, 0, common.SystemClock)
+	assert.NoError(t, err)
+
+	_, err = db.ProcessWrite(&proto.WriteRequest{
+		Puts: []*proto.PutRequest{
+			{Key: "/a", Value: []byte("value-a")},
+			{Key: "/b", Value: []byte("value-b")},
+		},
+	}, 5, 0, NoOpCallback)
+	assert.NoError(t, err)
+	assert.NoError(t, db.UpdateTerm(3))
+
+	assert.NoError(t, db.Close())
+	assert.NoError(t, factory.Close())
+}
+
+func TestInspector(t *testing.T) {
+	dir := t.TempDir()
+	writeInspectorTestDB(t, &FactoryOptions{DataDir: dir, CacheSizeMB: 1})
+
+	inspector, err := NewInspector(dir, common.DefaultNamespace, 1, nil)
+	assert.NoError(t, err)
+
+	metadata, err := inspector.Metadata()
+	assert.NoError(t, err)
+	assert.Equal(t, DBMetadata{CommitOffset: 5, Term: 3}, metadata)
+
+	var keys []string
+	var values []string
+	assert.NoError(t, inspector.Scan("/", common.InternalKeyPrefix, func(record Record) error {
+		entry, err := record.StorageEntry()
+		assert.NoError(t, err)
+		keys = append(keys, record.Key)
+		values = append(values, string(entry.Value))
+		assert.Equal(t, record.Size, len(record.Key)+len(record.Value))
+		return nil
+	}))
+	assert.Equal(t, []string{"/a", "/b"}, keys)
+	assert.Equal(t, []string{"value-a", "value-b"}, values)
+
+	// Without upper bound, the internal records are included
+	keys = nil
+	assert.NoError(t, inspector.Scan("", "", func(record Record) error {
+		keys = append(keys, record.Key)
+		return nil
+	}))
+	assert.Contains(t, keys, commitOffsetKey)
+	assert.Contains(t, keys, termKey)
+
+	assert.NoError(t, inspector.Close())
+
+	_, err = NewInspector(dir, common.DefaultNamespace, 2, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInspector_Empty(t *testing.T) {
+	dir := t.TempDir()
+	factory, err := NewPebbleKVFactory(&FactoryOptions{DataDir: dir, CacheSizeMB: 1})
+	assert.NoError(t, err)
+	kv, err := factory.NewKV(common.DefaultNamespace, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, kv.Close())
+	assert.NoError(t, factory.Close())
+
+	inspector, err := NewInspector(dir, common.DefaultNamespace, 1, nil)
+	assert.NoError(t, err)
+	metadata, err := inspector.Metadata()
+	assert.NoError(t, err)
+	assert.Equal(t, DBMetadata{CommitOffset: wal.InvalidOffset, Term: wal.InvalidTerm}, metadata)
+	assert.NoError(t, inspector.Close())
+}
+
+func TestInspector_Encryption(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master-keys")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("k1:"+base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600))
+	provider, err := encryption.NewKeyFileProvider(fmt.Sprintf(`{"path":%q}`, keyFile))
+	assert.NoError(t, err)
+
+	writeInspectorTestDB(t, &FactoryOptions{DataDir: filepath.Join(dir, "db"), CacheSizeMB: 1, KeyProvider: provider})
+
+	inspector, err := NewInspector(filepath.Join(dir, "db"), common.DefaultNamespace, 1, nil)
+	assert.NoError(t, err)
+	_, err = inspector.Metadata()
+	assert.ErrorContains(t, err, "encryption is not configured")
+	assert.NoError(t, inspector.Close())
+
+	inspector, err = NewInspector(filepath.Join(dir, "db"), common.DefaultNamespace, 1, provider)
+	assert.NoError(t, err)
+	metadata, err := inspector.Metadata()
+	assert.NoError(t, err)
+	assert.Equal(t, DBMetadata{CommitOffset: 5, Term: 3, Encrypted: true}, metadata)
+
+	var values []string
+	assert.NoError(t, inspector.Scan("/", common.InternalKeyPrefix, func(record Record) error {
+		entry, err := record.StorageEntry()
+		assert.NoError(t, err)
+		values = append(values, string(entry.Value))
+		return nil
+	}))
+	assert.Equal(t, []string{"value-a", "value-b"}, values)
+	assert.NoError(t, inspector.Close())
+}
@@ -16,8 +16,10 @@ package kv
 
 import (
 	"io"
+	"time"
 
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
 
 	"github.com/pkg/errors"
 )
@@ -122,6 +124,9 @@ type KV interface {
 
 	Flush() error
 
+	// DiskSpaceUsage returns the space used by the database files on disk
+	DiskSpaceUsage() int64
+
 	Delete() error
 }
 type FactoryOptions struct {
@@ -130,6 +135,13 @@ type FactoryOptions struct {
 
 	// Create a pure in-memory database. Used for unit-tests
 	InMemory bool
+
+	// KeyProvider enables the encryption of the values, with data keys
+	// wrapped by its master key
+	KeyProvider encryption.KeyProvider
+
+	// KeyRotationInterval is the max age of the data keys of each shard
+	KeyRotationInterval time.Duration
 }
 
 var DefaultFactoryOptions = &FactoryOptions{
@@ -0,0 +1,156 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
+)
+
+// The data keys are stored in the database itself, so that they're
+// transferred along with the snapshots
+const encryptionKeysKey = common.InternalKeyPrefix + "encryption-keys"
+
+// encryptedKV encrypts the values of a KV, while the keys are stored in
+// clear to preserve their ordering.
+type encryptedKV struct {
+	KV
+	keyring *encryption.Keyring
+}
+
+func newEncryptedKV(kv KV, provider encryption.KeyProvider, rotationInterval time.Duration) (KV, error) {
+	data, err := readEncryptionKeys(kv)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := encryption.OpenKeyring(context.Background(), provider, data, encryption.KeyringOptions{
+		RotationInterval: rotationInterval,
+		Persist: func(data []byte) error {
+			return writeEncryptionKeys(kv, data)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedKV{KV: kv, keyring: keyring}, nil
+}
+
+func readEncryptionKeys(kv KV) ([]byte, error) {
+	_, value, closer, err := kv.Get(encryptionKeysKey, ComparisonEqual)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	se := &proto.StorageEntry{}
+	if err = se.UnmarshalVT(value); err != nil {
+		return nil, errors.Wrap(err, "failed to read the data keys")
+	}
+	return se.Value, nil
+}
+
+func writeEncryptionKeys(kv KV, data []byte) error {
+	value, err := (&proto.StorageEntry{Value: data}).MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	wb := kv.NewWriteBatch()
+	if err = wb.Put(encryptionKeysKey, value); err == nil {
+		err = wb.Commit()
+	}
+	if err = multierr.Append(err, wb.Close()); err != nil {
+		return err
+	}
+
+	// The writes are not durable until flushed, and the new data key must
+	// not be lost while there are values encrypted with it
+	return kv.Flush()
+}
+
+func (e *encryptedKV) decrypt(value []byte, closer io.Closer) ([]byte, io.Closer, error) {
+	plaintext, err := e.keyring.Decrypt(value)
+	if err != nil {
+		return nil, nil, multierr.Append(err, closer.Close())
+	}
+	return plaintext, closer, nil
+}
+
+func (e *encryptedKV) NewWriteBatch() WriteBatch {
+	return &encryptedWriteBatch{WriteBatch: e.KV.NewWriteBatch(), kv: e}
+}
+
+func (e *encryptedKV) Get(key string, comparisonType ComparisonType) (storedKey string, value []byte, closer io.Closer, err error) {
+	storedKey, value, closer, err = e.KV.Get(key, comparisonType)
+	if err != nil {
+		return storedKey, value, closer, err
+	}
+	value, closer, err = e.decrypt(value, closer)
+	return storedKey, value, closer, err
+}
+
+func (e *encryptedKV) RangeScan(lowerBound, upperBound string) (KeyValueIterator, error) {
+	it, err := e.KV.RangeScan(lowerBound, upperBound)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedIterator{KeyValueIterator: it, keyring: e.keyring}, nil
+}
+
+type encryptedWriteBatch struct {
+	WriteBatch
+	kv *encryptedKV
+}
+
+func (b *encryptedWriteBatch) Put(key string, value []byte) error {
+	ciphertext, err := b.kv.keyring.Encrypt(value)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt the value")
+	}
+	return b.WriteBatch.Put(key, ciphertext)
+}
+
+func (b *encryptedWriteBatch) Get(key string) ([]byte, io.Closer, error) {
+	value, closer, err := b.WriteBatch.Get(key)
+	if err != nil {
+		return value, closer, err
+	}
+	return b.kv.decrypt(value, closer)
+}
+
+type encryptedIterator struct {
+	KeyValueIterator
+	keyring *encryption.Keyring
+}
+
+func (it *encryptedIterator) Value() ([]byte, error) {
+	value, err := it.KeyValueIterator.Value()
+	if err != nil {
+		return nil, err
+	}
+	return it.keyring.Decrypt(value)
+}
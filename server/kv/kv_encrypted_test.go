@@ -0,0 +1,103 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/server/encryption"
+)
+
+func TestEncryptedKV(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master-keys")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("k1:"+base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600))
+	provider, err := encryption.NewKeyFileProvider(fmt.Sprintf(`{"path":%q}`, keyFile))
+	assert.NoError(t, err)
+
+	options := &FactoryOptions{
+		DataDir:     filepath.Join(dir, "db"),
+		CacheSizeMB: 1,
+		KeyProvider: provider,
+	}
+	factory, err := NewPebbleKVFactory(options)
+	assert.NoError(t, err)
+	kv, err := factory.NewKV(common.DefaultNamespace, 1)
+	assert.NoError(t, err)
+
+	wb := kv.NewWriteBatch()
+	assert.NoError(t, wb.Put("/a", []byte("secret-a")))
+	assert.NoError(t, wb.Put("/b", []byte("secret-b")))
+	value, closer, err := wb.Get("/a")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-a", string(value))
+	assert.NoError(t, closer.Close())
+	assert.NoError(t, wb.Commit())
+	assert.NoError(t, wb.Close())
+
+	key, value, closer, err := kv.Get("/c", ComparisonFloor)
+	assert.NoError(t, err)
+	assert.Equal(t, "/b", key)
+	assert.Equal(t, "secret-b", string(value))
+	assert.NoError(t, closer.Close())
+
+	it, err := kv.RangeScan("/a", "/c")
+	assert.NoError(t, err)
+	var values []string
+	for ; it.Valid(); it.Next() {
+		value, err := it.Value()
+		assert.NoError(t, err)
+		values = append(values, string(value))
+	}
+	assert.Equal(t, []string{"secret-a", "secret-b"}, values)
+	assert.NoError(t, it.Close())
+
+	assert.NoError(t, kv.Close())
+	assert.NoError(t, factory.Close())
+
+	// The values are stored encrypted
+	options.KeyProvider = nil
+	factory, err = NewPebbleKVFactory(options)
+	assert.NoError(t, err)
+	kv, err = factory.NewKV(common.DefaultNamespace, 1)
+	assert.NoError(t, err)
+	_, value, closer, err = kv.Get("/a", ComparisonEqual)
+	assert.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(value))
+	assert.NotContains(t, string(value), "secret")
+	assert.NoError(t, closer.Close())
+	assert.NoError(t, kv.Close())
+	assert.NoError(t, factory.Close())
+
+	// The data keys are read back from the database
+	options.KeyProvider = provider
+	factory, err = NewPebbleKVFactory(options)
+	assert.NoError(t, err)
+	kv, err = factory.NewKV(common.DefaultNamespace, 1)
+	assert.NoError(t, err)
+	_, value, closer, err = kv.Get("/a", ComparisonEqual)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-a", string(value))
+	assert.NoError(t, closer.Close())
+	assert.NoError(t, kv.Close())
+	assert.NoError(t, factory.Close())
+}
@@ -118,7 +118,16 @@ func (p *PebbleFactory) Close() error {
 }
 
 func (p *PebbleFactory) NewKV(namespace string, shardId int64) (KV, error) {
-	return newKVPebble(p, namespace, shardId)
+	kv, err := newKVPebble(p, namespace, shardId)
+	if err != nil || p.options.KeyProvider == nil {
+		return kv, err
+	}
+
+	ekv, err := newEncryptedKV(kv, p.options.KeyProvider, p.options.KeyRotationInterval)
+	if err != nil {
+		return nil, multierr.Append(err, kv.Close())
+	}
+	return ekv, nil
 }
 
 func (p *PebbleFactory) NewSnapshotLoader(namespace string, shardId int64) (SnapshotLoader, error) {
@@ -353,16 +362,25 @@ func (p *Pebble) Close() error {
 }
 
 func (p *Pebble) Delete() error {
-	return multierr.Combine(
+	dbPath := p.factory.getKVPath(p.namespace, p.shardId)
+	err := multierr.Combine(
 		p.Close(),
-		os.RemoveAll(p.factory.getKVPath(p.namespace, p.shardId)),
+		os.RemoveAll(dbPath),
 	)
+
+	// Remove the namespace directory too, if this was its last shard
+	_ = os.Remove(filepath.Dir(dbPath))
+	return err
 }
 
 func (p *Pebble) Flush() error {
 	return p.db.Flush()
 }
 
+func (p *Pebble) DiskSpaceUsage() int64 {
+	return int64(p.dbMetrics().DiskSpaceUsage())
+}
+
 func (p *Pebble) NewWriteBatch() WriteBatch {
 	return &PebbleBatch{p: p, b: p.db.NewIndexedBatch()}
 }
@@ -1051,3 +1051,28 @@ func TestPebbleFindLowerInBatch(t *testing.T) {
 	assert.NoError(t, kv.Close())
 	assert.NoError(t, factory.Close())
 }
+
+func TestPebbleDeleteRemovesNamespaceDir(t *testing.T) {
+	dataDir := t.TempDir()
+	factory, err := NewPebbleKVFactory(&FactoryOptions{
+		DataDir:     dataDir,
+		CacheSizeMB: 1,
+		InMemory:    false,
+	})
+	assert.NoError(t, err)
+
+	kv1, err := factory.NewKV("my-ns", 1)
+	assert.NoError(t, err)
+	kv2, err := factory.NewKV("my-ns", 2)
+	assert.NoError(t, err)
+
+	// The namespace dir is kept while there are other shards
+	assert.NoError(t, kv1.Delete())
+	assert.NoDirExists(t, filepath.Join(dataDir, "my-ns", "shard-1"))
+	assert.DirExists(t, filepath.Join(dataDir, "my-ns"))
+
+	assert.NoError(t, kv2.Delete())
+	assert.NoDirExists(t, filepath.Join(dataDir, "my-ns"))
+
+	assert.NoError(t, factory.Close())
+}
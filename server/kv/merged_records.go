@@ -0,0 +1,147 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+// The records of a shard that is merged into this shard are first staged
+// under the internal keys, where they are not visible to the clients, and
+// then moved into the key space in a single write, when the merge commits.
+// Like the other internal keys, they are replicated and carried by the
+// snapshots.
+const mergedRecordsPrefix = common.InternalKeyPrefix + "merged/"
+
+// MergedRecordsRange is the range of the keys under which the records of a
+// merged shard are staged.
+func MergedRecordsRange(mergedShardId int64) (startInclusive string, endExclusive string) {
+	prefix := fmt.Sprintf("%s%d/", mergedRecordsPrefix, mergedShardId)
+	return prefix, prefix + "/"
+}
+
+// MergedRecordKey is the key under which a record of a merged shard is
+// staged. The record keys are escaped, so that all the keys of a merged
+// shard are in the same span of the slash-aware ordering of the keys.
+func MergedRecordKey(mergedShardId int64, key string) string {
+	prefix, _ := MergedRecordsRange(mergedShardId)
+	return prefix + url.PathEscape(key)
+}
+
+func stagedRecordKey(mergedShardId int64, stagedKey string) (string, error) {
+	prefix, _ := MergedRecordsRange(mergedShardId)
+	return url.PathUnescape(strings.TrimPrefix(stagedKey, prefix))
+}
+
+// applyMergedRecords moves the staged records of a merged shard into the key
+// space, with their timestamps and their metadata, and without any
+// notification, since their values are not modified. The version ids are the
+// offsets of the shard the records come from, so the records get the offset
+// of the merge as their new version id, for the versions of each key to keep
+// increasing. Nothing is moved, and ErrMergedKeyExists is returned, if any of
+// the keys was written in the shard in the meantime, in which case the staged
+// records are left for the merge to be aborted.
+func (d *db) applyMergedRecords(batch WriteBatch, usage *storageUsage, mergedShardId int64, commitOffset int64) error {
+	startInclusive, endExclusive := MergedRecordsRange(mergedShardId)
+
+	keys, err := stagedRecordKeys(batch, mergedShardId, startInclusive, endExclusive)
+	if err != nil {
+		return errors.Wrap(err, "oxia db: failed to merge the records")
+	}
+
+	for _, key := range keys {
+		if _, closer, err := batch.Get(key); err == nil {
+			d.log.Warn(
+				"Key of the merged shard already exists, not merging the records",
+				slog.Int64("merged-shard", mergedShardId),
+				slog.String("key", key),
+			)
+			return multierr.Combine(ErrMergedKeyExists, closer.Close())
+		} else if !errors.Is(err, ErrKeyNotFound) {
+			return errors.Wrap(err, "oxia db: failed to merge the records")
+		}
+	}
+
+	for _, key := range keys {
+		if err := moveMergedRecord(batch, usage, mergedShardId, key, commitOffset); err != nil {
+			return errors.Wrap(err, "oxia db: failed to merge the records")
+		}
+	}
+
+	if err := batch.DeleteRange(startInclusive, endExclusive); err != nil {
+		return errors.Wrap(err, "oxia db: failed to merge the records")
+	}
+
+	d.log.Info(
+		"Merged the records of the shard",
+		slog.Int64("merged-shard", mergedShardId),
+		slog.Int("records", len(keys)),
+	)
+	return nil
+}
+
+func stagedRecordKeys(batch WriteBatch, mergedShardId int64, startInclusive string, endExclusive string) ([]string, error) {
+	it, err := batch.KeyRangeScan(startInclusive, endExclusive)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for ; it.Valid(); it.Next() {
+		key, err := stagedRecordKey(mergedShardId, it.Key())
+		if err != nil {
+			return nil, multierr.Combine(err, it.Close())
+		}
+		keys = append(keys, key)
+	}
+	return keys, it.Close()
+}
+
+// moveMergedRecord writes a staged record under its own key, with the offset
+// of the merge as its version id. The value of the staged entry is the entry
+// of the record in the merged shard.
+func moveMergedRecord(batch WriteBatch, usage *storageUsage, mergedShardId int64, key string, commitOffset int64) error {
+	staged, err := GetStorageEntry(batch, MergedRecordKey(mergedShardId, key))
+	if err != nil {
+		return err
+	}
+	defer staged.ReturnToVTPool()
+
+	se := proto.StorageEntryFromVTPool()
+	defer se.ReturnToVTPool()
+	if err := deserialize(staged.Value, se); err != nil {
+		return err
+	}
+
+	se.VersionId = commitOffset
+	value, err := se.MarshalVT()
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(key, value); err != nil {
+		return err
+	}
+	usage.added(key, se)
+	return updateModificationIndex(batch, key, nil, &se.ModificationTimestamp)
+}
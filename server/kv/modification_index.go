@@ -0,0 +1,182 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+// The records are indexed by their modification time, so that the ones that
+// were not modified for a while are found without scanning the whole shard.
+// Like the storage usage, the index is kept in the shard, so that it's the
+// same on all the replicas and it's carried by the snapshots.
+const (
+	modificationIndexPrefix = common.InternalKeyPrefix + "modified/"
+
+	// Set once the records written before the index existed are indexed
+	modificationIndexReadyKey = common.InternalKeyPrefix + "modified-index-ready"
+
+	// Max number of records indexed in a single batch, when building the
+	// index of an existing shard
+	maxIndexedRecordsPerBatch = 1000
+)
+
+// The timestamps are padded to sort the keys by time, and the record keys
+// are escaped, so that all the keys of the index are in the same span of the
+// slash-aware ordering of the keys
+func modificationIndexKey(timestamp uint64, key string) string {
+	return modificationIndexTimeBound(timestamp) + url.PathEscape(key)
+}
+
+func modificationIndexTimeBound(timestamp uint64) string {
+	return fmt.Sprintf("%s%020d/", modificationIndexPrefix, timestamp)
+}
+
+func indexedKey(indexKey string) (string, error) {
+	escaped := strings.TrimPrefix(indexKey, modificationIndexPrefix)
+	if i := strings.IndexByte(escaped, '/'); i >= 0 {
+		escaped = escaped[i+1:]
+	}
+	return url.PathUnescape(escaped)
+}
+
+// updateModificationIndex moves a record in the index, from the time of its
+// previous version, if any, to the time of its current version, if any.
+func updateModificationIndex(batch WriteBatch, key string, previous *uint64, current *uint64) error {
+	if strings.HasPrefix(key, common.InternalKeyPrefix) {
+		return nil
+	}
+
+	if previous != nil && (current == nil || *previous != *current) {
+		if err := batch.Delete(modificationIndexKey(*previous, key)); err != nil {
+			return err
+		}
+	}
+	if current != nil {
+		return batch.Put(modificationIndexKey(*current, key), nil)
+	}
+	return nil
+}
+
+func removeFromModificationIndex(batch WriteBatch, key string) error {
+	if strings.HasPrefix(key, common.InternalKeyPrefix) {
+		return nil
+	}
+
+	se, err := GetStorageEntry(batch, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer se.ReturnToVTPool()
+
+	return updateModificationIndex(batch, key, &se.ModificationTimestamp, nil)
+}
+
+// modifiedBeforeIterator returns the keys of the records in the index,
+// rather than the keys of the index.
+type modifiedBeforeIterator struct {
+	KeyIterator
+}
+
+func (it *modifiedBeforeIterator) Key() string {
+	key, err := indexedKey(it.KeyIterator.Key())
+	if err != nil {
+		// The keys are escaped when they are indexed
+		return it.KeyIterator.Key()
+	}
+	return key
+}
+
+func (d *db) ModifiedBefore(timestamp uint64) (KeyIterator, error) {
+	it, err := d.kv.KeyRangeScan(modificationIndexPrefix, modificationIndexTimeBound(timestamp))
+	if err != nil {
+		return nil, err
+	}
+	return &modifiedBeforeIterator{it}, nil
+}
+
+// buildModificationIndex indexes the records of a shard that was written
+// before the index existed.
+func (d *db) buildModificationIndex() error {
+	if _, _, closer, err := d.kv.Get(modificationIndexReadyKey, ComparisonEqual); err == nil {
+		return closer.Close()
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+
+	it, err := d.kv.RangeScan("", "")
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	batch := d.kv.NewWriteBatch()
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if strings.HasPrefix(key, common.InternalKeyPrefix) {
+			continue
+		}
+
+		value, err := it.Value()
+		if err != nil {
+			return multierr.Combine(err, it.Close(), batch.Close())
+		}
+		se := proto.StorageEntryFromVTPool()
+		err = deserialize(value, se)
+		if err == nil {
+			err = updateModificationIndex(batch, key, nil, &se.ModificationTimestamp)
+		}
+		se.ReturnToVTPool()
+		if err != nil {
+			return multierr.Combine(err, it.Close(), batch.Close())
+		}
+
+		count++
+		if batch.Count() >= maxIndexedRecordsPerBatch {
+			if err := multierr.Combine(batch.Commit(), batch.Close()); err != nil {
+				return multierr.Combine(err, it.Close())
+			}
+			batch = d.kv.NewWriteBatch()
+		}
+	}
+
+	if err := multierr.Combine(
+		it.Close(),
+		batch.Put(modificationIndexReadyKey, nil),
+		batch.Commit(),
+		batch.Close(),
+	); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		d.log.Info(
+			"Indexed the records by modification time",
+			slog.Int("count", count),
+		)
+	}
+	return nil
+}
@@ -81,6 +81,15 @@ func (n *notifications) Deleted(key string) {
 	}
 }
 
+func (n *notifications) RangeDeleted(key string) {
+	if strings.HasPrefix(key, common.InternalKeyPrefix) {
+		return
+	}
+	n.batch.Notifications[key] = &proto.Notification{
+		Type: proto.NotificationType_KEY_RANGE_DELETED,
+	}
+}
+
 func notificationKey(offset int64) string {
 	return fmt.Sprintf("%s/%016x", notificationsPrefix, offset)
 }
@@ -99,6 +108,7 @@ type notificationsTracker struct {
 	lastOffset atomic.Int64
 	closed     atomic.Bool
 	kv         KV
+	trimmer    *notificationsTrimmer
 	log        *slog.Logger
 
 	ctx       context.Context
@@ -131,7 +141,7 @@ func newNotificationsTracker(namespace string, shard int64, lastOffset int64, kv
 	nt.lastOffset.Store(lastOffset)
 	nt.cond = common.NewConditionContext(nt)
 	nt.ctx, nt.cancel = context.WithCancel(context.Background())
-	newNotificationsTrimmer(nt.ctx, namespace, shard, kv, notificationRetentionTime, nt.waitClose, clock)
+	nt.trimmer = newNotificationsTrimmer(nt.ctx, namespace, shard, kv, notificationRetentionTime, nt.waitClose, clock)
 	return nt
 }
 
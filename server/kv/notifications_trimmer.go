@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -36,34 +37,24 @@ type notificationsTrimmer struct {
 	ctx                        context.Context
 	waitClose                  common.WaitGroup
 	kv                         KV
-	interval                   time.Duration
-	notificationsRetentionTime time.Duration
+	notificationsRetentionTime atomic.Int64
 	clock                      common.Clock
 	log                        *slog.Logger
 }
 
 func newNotificationsTrimmer(ctx context.Context, namespace string, shardId int64, kv KV, notificationRetentionTime time.Duration, waitClose common.WaitGroup, clock common.Clock) *notificationsTrimmer {
-	interval := notificationRetentionTime / 10
-	if interval < minNotificationTrimmingInterval {
-		interval = minNotificationTrimmingInterval
-	}
-	if interval > maxNotificationTrimmingInterval {
-		interval = maxNotificationTrimmingInterval
-	}
-
 	t := &notificationsTrimmer{
-		ctx:                        ctx,
-		waitClose:                  waitClose,
-		kv:                         kv,
-		interval:                   interval,
-		notificationsRetentionTime: notificationRetentionTime,
-		clock:                      clock,
+		ctx:       ctx,
+		waitClose: waitClose,
+		kv:        kv,
+		clock:     clock,
 		log: slog.With(
 			slog.String("component", "db-notifications-trimmer"),
 			slog.String("namespace", namespace),
 			slog.Int64("shard", shardId),
 		),
 	}
+	t.notificationsRetentionTime.Store(int64(notificationRetentionTime))
 
 	go common.DoWithLabels(
 		t.ctx,
@@ -78,8 +69,30 @@ func newNotificationsTrimmer(ctx context.Context, namespace string, shardId int6
 	return t
 }
 
+func (t *notificationsTrimmer) retention() time.Duration {
+	return time.Duration(t.notificationsRetentionTime.Load())
+}
+
+// The retention time can be changed at runtime, eg: by the policies of the
+// namespace. The trimming interval is adjusted at the next run.
+func (t *notificationsTrimmer) setRetention(retention time.Duration) {
+	t.notificationsRetentionTime.Store(int64(retention))
+}
+
+func trimmingInterval(retention time.Duration) time.Duration {
+	interval := retention / 10
+	if interval < minNotificationTrimmingInterval {
+		interval = minNotificationTrimmingInterval
+	}
+	if interval > maxNotificationTrimmingInterval {
+		interval = maxNotificationTrimmingInterval
+	}
+	return interval
+}
+
 func (t *notificationsTrimmer) run() {
-	ticker := time.NewTicker(t.interval)
+	interval := trimmingInterval(t.retention())
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -89,6 +102,11 @@ func (t *notificationsTrimmer) run() {
 				t.log.Warn("Failed to trim notifications", slog.Any("error", err))
 			}
 
+			if newInterval := trimmingInterval(t.retention()); newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+			}
+
 		case <-t.ctx.Done():
 			t.waitClose.Done()
 			return
@@ -107,14 +125,14 @@ func (t *notificationsTrimmer) trimNotifications() error {
 		slog.Int64("first-offset", first),
 		slog.Int64("last-offset", last),
 		slog.Time("current-time", t.clock.Now()),
-		slog.Duration("retention-time", t.notificationsRetentionTime),
+		slog.Duration("retention-time", t.retention()),
 	)
 
 	if last == -1 {
 		return nil
 	}
 
-	cutoffTime := t.clock.Now().Add(-t.notificationsRetentionTime)
+	cutoffTime := t.clock.Now().Add(-t.retention())
 
 	// Check if first entry has expired
 	tsFirst, err := t.readAt(first)
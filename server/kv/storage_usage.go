@@ -0,0 +1,155 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+// The storage used by each principal is kept in the shard, so that it's the
+// same on all the replicas and it's carried by the snapshots
+const storageUsageKeyPrefix = common.InternalKeyPrefix + "storage-usage/"
+
+// The principals are escaped, so that all the keys are in the same span of
+// the slash-aware ordering of the keys
+func storageUsageKey(principal string) string {
+	return storageUsageKeyPrefix + url.PathEscape(principal)
+}
+
+// storageUsage accumulates the changes of the storage used by the principals
+// while applying a write request, to update their usage once per request.
+type storageUsage struct {
+	// The principal of the write request, that owns the records it puts
+	principal *string
+	deltas    map[string]int64
+}
+
+func newStorageUsage(principal *string) *storageUsage {
+	return &storageUsage{principal: principal}
+}
+
+func recordSize(key string, se *proto.StorageEntry) int64 {
+	return int64(len(key) + len(se.Value))
+}
+
+func (u *storageUsage) add(key string, se *proto.StorageEntry, sign int64) {
+	if u == nil || se == nil || se.GetPrincipal() == "" || strings.HasPrefix(key, common.InternalKeyPrefix) {
+		return
+	}
+	if u.deltas == nil {
+		u.deltas = map[string]int64{}
+	}
+	u.deltas[se.GetPrincipal()] += sign * recordSize(key, se)
+}
+
+// added records a new record, or the new value of a record
+func (u *storageUsage) added(key string, se *proto.StorageEntry) {
+	u.add(key, se, 1)
+}
+
+// removed records a deleted record, or the previous value of a record
+func (u *storageUsage) removed(key string, se *proto.StorageEntry) {
+	u.add(key, se, -1)
+}
+
+// tracked tells whether any principal uses storage in the shard, in which
+// case the deleted ranges must be read to update their usage.
+func (u *storageUsage) tracked(batch WriteBatch) (bool, error) {
+	if u == nil {
+		return false, nil
+	}
+	if len(u.deltas) > 0 {
+		return true, nil
+	}
+
+	it, err := batch.KeyRangeScan(storageUsageKeyPrefix, storageUsageKeyPrefix+"\xff")
+	if err != nil {
+		return false, err
+	}
+	tracked := it.Valid()
+	return tracked, it.Close()
+}
+
+func (u *storageUsage) apply(batch WriteBatch) error {
+	for principal, delta := range u.deltas {
+		if delta == 0 {
+			continue
+		}
+
+		usage, err := readStorageUsage(batch, principal)
+		if err != nil {
+			return err
+		}
+
+		usage += delta
+		key := storageUsageKey(principal)
+		if usage <= 0 {
+			if err := batch.Delete(key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		se := &proto.StorageEntry{Value: []byte(strconv.FormatInt(usage, 10))}
+		value, err := se.MarshalVT()
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readStorageUsage(batch WriteBatch, principal string) (int64, error) {
+	se, err := GetStorageEntry(batch, storageUsageKey(principal))
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer se.ReturnToVTPool()
+
+	usage, err := strconv.ParseInt(string(se.Value), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid storage usage of principal %s", principal)
+	}
+	return usage, nil
+}
+
+func removeStorageUsage(batch WriteBatch, usage *storageUsage, key string) error {
+	if strings.HasPrefix(key, common.InternalKeyPrefix) {
+		return nil
+	}
+
+	se, err := GetStorageEntry(batch, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer se.ReturnToVTPool()
+
+	usage.removed(key, se)
+	return nil
+}
@@ -19,7 +19,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc/status"
@@ -31,10 +35,13 @@ import (
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/metrics"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
 	"github.com/streamnative/oxia/server/kv"
 	"github.com/streamnative/oxia/server/wal"
 )
 
+const followersHealthCheckInterval = 1 * time.Second
+
 type GetResult struct {
 	Response *proto.GetResponse
 	Err      error
@@ -60,18 +67,51 @@ type LeaderController interface {
 
 	GetNotifications(req *proto.NotificationsRequest, stream proto.OxiaClient_GetNotificationsServer) error
 
+	// AckNotifications stores the offset of the last notification processed
+	// by a durable consumer
+	AckNotifications(ctx context.Context, req *proto.AckNotificationsRequest) (*proto.AckNotificationsResponse, error)
+
 	GetStatus(request *proto.GetStatusRequest) (*proto.GetStatusResponse, error)
 	DeleteShard(request *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error)
 
+	// ExportRecords sends the records of the replica, once it was fenced to
+	// merge the shard into another shard
+	ExportRecords(req *proto.ExportRecordsRequest, stream proto.OxiaCoordination_ExportRecordsServer) error
+
+	// ImportRecords writes the records of a shard that is merged into this
+	// one. Unlike the writes of the clients, the policies of the namespace
+	// are not enforced.
+	ImportRecords(ctx context.Context, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error)
+
 	// Term The current term of the leader
 	Term() int64
 
 	// Status The Status of the leader
 	Status() proto.ServingStatus
 
-	CreateSession(*proto.CreateSessionRequest) (*proto.CreateSessionResponse, error)
-	KeepAlive(sessionId int64) error
-	CloseSession(*proto.CloseSessionRequest) (*proto.CloseSessionResponse, error)
+	CreateSession(context.Context, *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error)
+	KeepAlive(ctx context.Context, sessionId int64) error
+	CloseSession(context.Context, *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error)
+
+	// GrantLease returns the session of the named lease, creating the lease
+	// if needed, while RefreshLease and RevokeLease act on it by its name
+	GrantLease(context.Context, *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error)
+	RefreshLease(context.Context, *proto.LeaseRequest) error
+	RevokeLease(context.Context, *proto.LeaseRequest) error
+
+	// ListSessions returns the sessions that are active on the shard
+	ListSessions(ctx context.Context) ([]*proto.SessionStatus, error)
+
+	// UpdatePolicies sets the policies of the namespace that are enforced
+	// by the leader
+	UpdatePolicies(policies *proto.NamespacePolicies)
+
+	// Authorize checks that the principal of the request has the permission
+	// on the namespace
+	Authorize(ctx context.Context, required permission) error
+
+	// SetNotificationsRetention changes how long the notifications are kept
+	SetNotificationsRetention(retention time.Duration)
 }
 
 type leaderController struct {
@@ -90,6 +130,23 @@ type leaderController struct {
 	// truncate the followers.
 	leaderElectionHeadEntryId *proto.EntryId
 
+	// Keep track of the ack progress of each follower, to detect
+	// the ones that stopped acknowledging entries
+	followerAckTimeout      time.Duration
+	followersHealth         map[string]*followerHealth
+	unhealthyFollowersCount atomic.Int64
+
+	// Cumulative load served by the leader, reported to the coordinator
+	writeOps   atomic.Uint64
+	readOps    atomic.Uint64
+	writeBytes atomic.Uint64
+
+	// The limiters of the tenants, shared with the other leaders
+	tenants *tenantLimiters
+
+	// The policies of the namespace, as distributed by the coordinator
+	policies namespacePolicies
+
 	ctx            context.Context
 	cancel         context.CancelFunc
 	wal            wal.Wal
@@ -98,10 +155,30 @@ type leaderController struct {
 	sessionManager SessionManager
 	log            *slog.Logger
 
-	writeLatencyHisto       metrics.LatencyHistogram
-	headOffsetGauge         metrics.Gauge
-	commitOffsetGauge       metrics.Gauge
-	followerAckOffsetGauges map[string]metrics.Gauge
+	writeLatencyHisto        metrics.LatencyHistogram
+	headOffsetGauge          metrics.Gauge
+	commitOffsetGauge        metrics.Gauge
+	followerAckOffsetGauges  map[string]metrics.Gauge
+	unhealthyFollowersGauge  metrics.Gauge
+	followerAckTimeoutsCount metrics.Counter
+	expiredRecordsCounter    metrics.Counter
+	rejectedRequestsCounter  metrics.Counter
+
+	// The requests rejected by the quotas of the principals, by quota
+	quotaRejectionsCounters map[string]metrics.Counter
+
+	// Records the writes applied on the shard, when enabled
+	auditLog AuditLog
+
+	// Max time a notifications subscriber can stall before being evicted
+	notificationsSubscriberTimeout time.Duration
+	evictedSubscribersCounter      metrics.Counter
+}
+
+type followerHealth struct {
+	ackOffset    int64
+	lastProgress time.Time
+	unhealthy    bool
 }
 
 func NewLeaderController(config Config, namespace string, shardId int64, rpcClient ReplicationRpcProvider, walFactory wal.Factory, kvFactory kv.Factory) (LeaderController, error) {
@@ -114,9 +191,29 @@ func NewLeaderController(config Config, namespace string, shardId int64, rpcClie
 		rpcClient:        rpcClient,
 		followers:        make(map[string]FollowerCursor),
 
+		followerAckTimeout: config.FollowerAckTimeout,
+		followersHealth:    make(map[string]*followerHealth),
+
 		writeLatencyHisto: metrics.NewLatencyHistogram("oxia_server_leader_write_latency",
 			"Latency for write operations in the leader", labels),
 		followerAckOffsetGauges: map[string]metrics.Gauge{},
+		followerAckTimeoutsCount: metrics.NewCounter("oxia_server_leader_follower_ack_timeouts",
+			"The number of times a follower was marked as unhealthy for not acknowledging entries", "count", labels),
+		expiredRecordsCounter: metrics.NewCounter("oxia_server_leader_expired_records",
+			"The number of records deleted after outliving the default TTL of the namespace", "count", labels),
+		rejectedRequestsCounter: metrics.NewCounter("oxia_server_leader_rejected_requests",
+			"The number of requests rejected by the policies of the namespace", "count", labels),
+		quotaRejectionsCounters: newQuotaRejectionsCounters(labels),
+
+		auditLog: config.AuditLog,
+		tenants:  config.tenants,
+
+		notificationsSubscriberTimeout: config.NotificationsSubscriberTimeout,
+		evictedSubscribersCounter: metrics.NewCounter("oxia_server_leader_notifications_evicted_subscribers",
+			"The number of notifications subscribers disconnected for being too slow", "count", labels),
+	}
+	if lc.tenants == nil {
+		lc.tenants = newTenantLimiters()
 	}
 
 	lc.headOffsetGauge = metrics.NewGauge("oxia_server_leader_head_offset",
@@ -137,6 +234,10 @@ func NewLeaderController(config Config, namespace string, shardId int64, rpcClie
 
 			return -1
 		})
+	lc.unhealthyFollowersGauge = metrics.NewGauge("oxia_server_leader_unhealthy_followers",
+		"The number of followers that are not acknowledging entries within the ack timeout", "count", labels, func() int64 {
+			return lc.unhealthyFollowersCount.Load()
+		})
 
 	lc.ctx, lc.cancel = context.WithCancel(context.Background())
 
@@ -160,6 +261,27 @@ func NewLeaderController(config Config, namespace string, shardId int64, rpcClie
 	}
 
 	lc.setLogger()
+
+	if lc.followerAckTimeout > 0 {
+		go common.DoWithLabels(
+			lc.ctx,
+			map[string]string{
+				"oxia":  "leader-followers-health",
+				"shard": fmt.Sprintf("%d", lc.shardId),
+			},
+			lc.monitorFollowersHealth,
+		)
+	}
+
+	go common.DoWithLabels(
+		lc.ctx,
+		map[string]string{
+			"oxia":  "leader-records-expiry",
+			"shard": fmt.Sprintf("%d", lc.shardId),
+		},
+		lc.expireRecords,
+	)
+
 	lc.log.Info("Created leader controller")
 	return lc, nil
 }
@@ -251,6 +373,8 @@ func (lc *leaderController) NewTerm(req *proto.NewTermRequest) (*proto.NewTermRe
 	}
 
 	lc.followers = nil
+	lc.followersHealth = make(map[string]*followerHealth)
+	lc.unhealthyFollowersCount.Store(0)
 	headEntryId, err := getLastEntryIdInWal(lc.wal)
 	if err != nil {
 		return nil, err
@@ -261,14 +385,20 @@ func (lc *leaderController) NewTerm(req *proto.NewTermRequest) (*proto.NewTermRe
 		return nil, err
 	}
 
+	res := &proto.NewTermResponse{
+		HeadEntryId: headEntryId,
+	}
+	if req.CheckMergeable {
+		if res.Mergeable, err = isMergeable(lc.db, headEntryId); err != nil {
+			return nil, err
+		}
+	}
+
 	lc.log.Info(
 		"Leader successfully initialized in new term",
 		slog.Any("last-entry", headEntryId),
 	)
-
-	return &proto.NewTermResponse{
-		HeadEntryId: headEntryId,
-	}, nil
+	return res, nil
 }
 
 // BecomeLeader : Node handles a Become Leader request
@@ -315,6 +445,7 @@ func (lc *leaderController) BecomeLeader(ctx context.Context, req *proto.BecomeL
 	lc.status = proto.ServingStatus_LEADER
 	lc.replicationFactor = req.GetReplicationFactor()
 	lc.followers = make(map[string]FollowerCursor)
+	lc.policies.resetAccesses()
 
 	var err error
 	lc.leaderElectionHeadEntryId, err = getLastEntryIdInWal(lc.wal)
@@ -327,7 +458,12 @@ func (lc *leaderController) BecomeLeader(ctx context.Context, req *proto.BecomeL
 		return nil, err
 	}
 
-	lc.quorumAckTracker = NewQuorumAckTracker(req.GetReplicationFactor(), lc.leaderElectionHeadEntryId.Offset, leaderCommitOffset)
+	if req.GetReplicationMode() == proto.ReplicationMode_RELAXED {
+		// The entries are committed as soon as they're synced in the leader wal
+		lc.quorumAckTracker = NewRelaxedQuorumAckTracker(req.GetReplicationFactor(), lc.leaderElectionHeadEntryId.Offset)
+	} else {
+		lc.quorumAckTracker = NewQuorumAckTracker(req.GetReplicationFactor(), lc.leaderElectionHeadEntryId.Offset, leaderCommitOffset)
+	}
 	lc.sessionManager = NewSessionManager(lc.ctx, lc.namespace, lc.shardId, lc)
 
 	for follower, followerHeadEntryId := range req.FollowerMaps {
@@ -574,6 +710,12 @@ func (lc *leaderController) Read(ctx context.Context, request *proto.ReadRequest
 	lc.RLock()
 	err := checkStatusIsLeader(lc.status)
 	lc.RUnlock()
+	if err == nil {
+		err = lc.authorizeGets(ctx, request.Gets)
+	}
+	if err == nil {
+		err = lc.checkRead(ctx, len(request.Gets))
+	}
 	if err != nil {
 		go func() {
 			ch <- GetResult{Err: err}
@@ -581,6 +723,7 @@ func (lc *leaderController) Read(ctx context.Context, request *proto.ReadRequest
 		return ch
 	}
 
+	lc.readOps.Add(uint64(len(request.Gets)))
 	go lc.read(ctx, request, ch)
 
 	return ch
@@ -597,11 +740,24 @@ func (lc *leaderController) read(ctx context.Context, request *proto.ReadRequest
 		func() {
 			lc.log.Debug("Received read request")
 
+			principal, authenticated := auth.GetPrincipal(ctx)
 			for _, get := range request.Gets {
 				response, err := lc.db.Get(get)
 				if err != nil {
 					return
 				}
+				if response.Key != nil && lc.policies.authorizeKey(principal, authenticated, permissionRead, *response.Key) != nil {
+					// The floor and ceiling lookups don't return the keys
+					// that the principal is not allowed to read
+					response = &proto.GetResponse{Status: proto.Status_KEY_NOT_FOUND}
+				}
+				if response.Status == proto.Status_OK {
+					key := get.Key
+					if response.Key != nil {
+						key = *response.Key
+					}
+					lc.policies.recordAccess(key)
+				}
 				ch <- GetResult{Response: response}
 				if ctx.Err() != nil {
 					ch <- GetResult{Err: ctx.Err()}
@@ -619,16 +775,26 @@ func (lc *leaderController) List(ctx context.Context, request *proto.ListRequest
 	lc.RLock()
 	err := checkStatusIsLeader(lc.status)
 	lc.RUnlock()
+	if err == nil {
+		err = lc.authorizeRange(ctx, request.StartInclusive, request.EndExclusive)
+	}
+	if err == nil {
+		err = lc.checkRead(ctx, 1)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	go lc.list(ctx, request, ch)
+	lc.readOps.Add(1)
+	go lc.list(ctx, request, ch, false)
 
 	return ch, nil
 }
 
-func (lc *leaderController) list(ctx context.Context, request *proto.ListRequest, ch chan<- string) {
+// list sends the keys of the range to the channel. The internal keys, such as
+// the ones of the sessions and of the modification index, are only included
+// for the internal callers, since they're spread over the whole key space.
+func (lc *leaderController) list(ctx context.Context, request *proto.ListRequest, ch chan<- string, includeInternalKeys bool) {
 	common.DoWithLabels(
 		ctx,
 		map[string]string{
@@ -659,7 +825,12 @@ func (lc *leaderController) list(ctx context.Context, request *proto.ListRequest
 			}()
 
 			for ; it.Valid(); it.Next() {
-				ch <- it.Key()
+				key := it.Key()
+				if !includeInternalKeys && strings.HasPrefix(key, common.InternalKeyPrefix) {
+					continue
+				}
+
+				ch <- key
 				if ctx.Err() != nil {
 					break
 				}
@@ -670,7 +841,7 @@ func (lc *leaderController) list(ctx context.Context, request *proto.ListRequest
 
 func (lc *leaderController) ListSliceNoMutex(ctx context.Context, request *proto.ListRequest) ([]string, error) {
 	ch := make(chan string)
-	go lc.list(ctx, request, ch)
+	go lc.list(ctx, request, ch, true)
 	keys := make([]string, 0)
 	for {
 		select {
@@ -692,10 +863,17 @@ func (lc *leaderController) RangeScan(ctx context.Context, request *proto.RangeS
 	lc.RLock()
 	err := checkStatusIsLeader(lc.status)
 	lc.RUnlock()
+	if err == nil {
+		err = lc.authorizeRange(ctx, request.StartInclusive, request.EndExclusive)
+	}
+	if err == nil {
+		err = lc.checkRead(ctx, 1)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
+	lc.readOps.Add(1)
 	go lc.rangeScan(ctx, request, ch, errCh)
 
 	return ch, errCh, nil
@@ -735,6 +913,10 @@ func (lc *leaderController) rangeScan(ctx context.Context, request *proto.RangeS
 			}()
 
 			for ; it.Valid(); it.Next() {
+				if strings.HasPrefix(it.Key(), common.InternalKeyPrefix) {
+					continue
+				}
+
 				gr, err := it.Value()
 				if err != nil {
 					errCh <- err
@@ -756,13 +938,39 @@ func (lc *leaderController) rangeScan(ctx context.Context, request *proto.RangeS
 // A client writes a value from Values to a leader node
 // if that value has not previously been written. The leader adds
 // the entry to its log, updates its head offset.
-func (lc *leaderController) Write(ctx context.Context, request *proto.WriteRequest) (*proto.WriteResponse, error) {
-	_, resp, err := lc.write(ctx, func(_ int64) *proto.WriteRequest {
+func (lc *leaderController) Write(ctx context.Context, request *proto.WriteRequest) (resp *proto.WriteResponse, err error) {
+	defer func() {
+		lc.auditWrite(ctx, request, resp, err)
+	}()
+
+	principal, authenticated := auth.GetPrincipal(ctx)
+	if err = lc.checkPolicy(lc.policies.authorizeWrite(principal, authenticated, request)); err != nil {
+		return nil, err
+	}
+	if err = lc.checkWrite(ctx, principal, authenticated, request); err != nil {
+		return nil, err
+	}
+
+	_, resp, err = lc.write(ctx, func(_ int64) *proto.WriteRequest {
 		return request
 	})
 	return resp, err
 }
 
+func (lc *leaderController) auditWrite(ctx context.Context, request *proto.WriteRequest, resp *proto.WriteResponse, err error) {
+	if lc.auditLog == nil {
+		return
+	}
+
+	principal, _ := auth.GetPrincipal(ctx)
+	auditWrite(lc.auditLog, AuditRecord{
+		Principal: principal,
+		Peer:      common.GetPeer(ctx),
+		Namespace: lc.namespace,
+		Shard:     lc.shardId,
+	}, request, resp, err)
+}
+
 func (lc *leaderController) write(ctx context.Context, request func(int64) *proto.WriteRequest) (int64, *proto.WriteResponse, error) {
 	timer := lc.writeLatencyHisto.Timer()
 	defer timer.Done() //nolint:contextcheck
@@ -773,6 +981,7 @@ func (lc *leaderController) write(ctx context.Context, request func(int64) *prot
 	if err != nil {
 		return wal.InvalidOffset, nil, err
 	}
+	lc.trackWrite(actualRequest)
 
 	resp, err := lc.quorumAckTracker.WaitForCommitOffset(ctx, newOffset, func() (*proto.WriteResponse, error) {
 		return lc.db.ProcessWrite(actualRequest, newOffset, timestamp, SessionUpdateOperationCallback)
@@ -862,6 +1071,7 @@ func (lc *leaderController) WriteStream(stream proto.OxiaClient_WriteStreamServe
 
 func (lc *leaderController) handleWriteStream(stream proto.OxiaClient_WriteStreamServer,
 	closeCh chan error) {
+	principal, authenticated := auth.GetPrincipal(stream.Context())
 	for {
 		req, err := stream.Recv()
 
@@ -876,15 +1086,29 @@ func (lc *leaderController) handleWriteStream(stream proto.OxiaClient_WriteStrea
 		slog.Debug("Got request in stream",
 			slog.Any("req", req))
 
+		if err = lc.checkPolicy(lc.policies.authorizeWrite(principal, authenticated, req)); err != nil {
+			lc.auditWrite(stream.Context(), req, nil, err)
+			closeCh <- err
+			return
+		}
+		if err = lc.checkWrite(stream.Context(), principal, authenticated, req); err != nil {
+			lc.auditWrite(stream.Context(), req, nil, err)
+			closeCh <- err
+			return
+		}
+
 		offset, timestamp, err1 := lc.appendToWalStreamRequest(stream.Context(), req)
 		if err1 != nil {
+			lc.auditWrite(stream.Context(), req, nil, err1)
 			closeCh <- err1
 			return
 		}
+		lc.trackWrite(req)
 
 		resp, err2 := lc.quorumAckTracker.WaitForCommitOffset(stream.Context(), offset, func() (*proto.WriteResponse, error) {
 			return lc.db.ProcessWrite(req, offset, timestamp, SessionUpdateOperationCallback)
 		})
+		lc.auditWrite(stream.Context(), req, resp, err2)
 		if err2 != nil {
 			closeCh <- err2
 			return
@@ -952,10 +1176,26 @@ func (lc *leaderController) appendToWalStreamRequest(ctx context.Context, reques
 
 // ////
 
+const (
+	// Max number of messages queued for each notifications subscriber
+	notificationsQueueSize = 16
+
+	// Max number of notifications merged into a single stream message
+	maxNotificationsPerMessage = 1000
+)
+
 func (lc *leaderController) GetNotifications(req *proto.NotificationsRequest, stream proto.OxiaClient_GetNotificationsServer) error {
+	// The notifications cover all the keys of the shard
+	principal, authenticated := auth.GetPrincipal(stream.Context())
+	if err := lc.checkPolicy(lc.policies.authorizeAllKeys(principal, authenticated, permissionRead)); err != nil {
+		return err
+	}
+
 	// Create a context for handling this stream
 	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
 
+	dispatchErr := make(chan error, 1)
 	go common.DoWithLabels(
 		ctx,
 		map[string]string{
@@ -970,7 +1210,7 @@ func (lc *leaderController) GetNotifications(req *proto.NotificationsRequest, st
 					slog.Any("error", err),
 					slog.String("peer", common.GetPeer(stream.Context())),
 				)
-				cancel()
+				dispatchErr <- err
 			}
 		},
 	)
@@ -978,15 +1218,13 @@ func (lc *leaderController) GetNotifications(req *proto.NotificationsRequest, st
 	select {
 	case <-lc.ctx.Done():
 		// Leader is getting closed
-		cancel()
 		return lc.ctx.Err()
 
-	case <-ctx.Done():
-		return ctx.Err()
+	case err := <-dispatchErr:
+		return err
 
 	case <-stream.Context().Done():
 		// The stream is getting closed
-		cancel()
 		return stream.Context().Err()
 	}
 }
@@ -1008,7 +1246,18 @@ func (lc *leaderController) dispatchNotifications(ctx context.Context, req *prot
 		if qat == nil {
 			return errors.New("leader is not yet ready")
 		}
-		commitOffset := qat.CommitOffset()
+		startOffset := qat.CommitOffset()
+
+		// A durable consumer resumes after the last offset it acknowledged
+		if req.ConsumerName != nil {
+			acked, found, err := lc.notificationsConsumerOffset(*req.ConsumerName)
+			if err != nil {
+				return err
+			}
+			if found {
+				startOffset = acked
+			}
+		}
 
 		// The client is creating a new notification stream and wants to receive the notification from the next
 		// entry that will be written.
@@ -1017,28 +1266,62 @@ func (lc *leaderController) dispatchNotifications(ctx context.Context, req *prot
 		// channel available to the application
 		lc.log.Debug(
 			"Sending first dummy notification",
-			slog.Int64("commit-offset", commitOffset),
+			slog.Int64("start-offset", startOffset),
 		)
 		if err := stream.Send(&proto.NotificationBatch{
 			ShardId:       lc.shardId,
-			Offset:        commitOffset,
+			Offset:        startOffset,
 			Timestamp:     0,
 			Notifications: nil,
 		}); err != nil {
 			return err
 		}
 
-		offsetInclusive = commitOffset + 1
+		offsetInclusive = startOffset + 1
 	}
 
-	return lc.iterateOverNotifications(ctx, stream, offsetInclusive)
+	return lc.iterateOverNotifications(ctx, stream, offsetInclusive, newNotificationsFilter(req))
 }
 
-func (lc *leaderController) iterateOverNotifications(ctx context.Context, stream proto.OxiaClient_GetNotificationsServer, startOffsetInclusive int64) error {
+// The notifications are read from the db and sent to the subscriber through
+// a bounded queue, so that a stalled subscriber only holds a limited amount
+// of memory in the leader. When the queue stays full for longer than the
+// subscriber timeout, the subscriber is evicted.
+func (lc *leaderController) iterateOverNotifications(ctx context.Context, stream proto.OxiaClient_GetNotificationsServer,
+	startOffsetInclusive int64, filter *notificationsFilter) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	queue := make(chan *proto.NotificationBatch, notificationsQueueSize)
+
+	go common.DoWithLabels(
+		ctx,
+		map[string]string{
+			"oxia":  "send-notifications",
+			"shard": fmt.Sprintf("%d", lc.shardId),
+		},
+		func() {
+			for {
+				select {
+				case nb := <-queue:
+					if err := stream.Send(nb); err != nil {
+						cancel(err)
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		},
+	)
+
 	offsetInclusive := startOffsetInclusive
 	for ctx.Err() == nil {
 		notifications, err := lc.db.ReadNextNotifications(ctx, offsetInclusive)
 		if err != nil {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
 			return err
 		}
 
@@ -1047,8 +1330,20 @@ func (lc *leaderController) iterateOverNotifications(ctx context.Context, stream
 			slog.Int("list-size", len(notifications)),
 		)
 
+		filtered := make([]*proto.NotificationBatch, 0, len(notifications))
 		for _, n := range notifications {
-			if err := stream.Send(n); err != nil {
+			if n = filterNotifications(n, filter); n != nil {
+				if filter.includeValue {
+					if n, err = lc.inlineValues(n); err != nil {
+						return err
+					}
+				}
+				filtered = append(filtered, n)
+			}
+		}
+
+		for _, nb := range mergeNotifications(filtered, maxNotificationsPerMessage) {
+			if err := lc.enqueueNotifications(ctx, queue, nb); err != nil {
 				return err
 			}
 		}
@@ -1056,7 +1351,204 @@ func (lc *leaderController) iterateOverNotifications(ctx context.Context, stream
 		offsetInclusive += int64(len(notifications))
 	}
 
-	return ctx.Err()
+	return context.Cause(ctx)
+}
+
+func (lc *leaderController) enqueueNotifications(ctx context.Context, queue chan<- *proto.NotificationBatch, nb *proto.NotificationBatch) error {
+	var timeout <-chan time.Time
+	if lc.notificationsSubscriberTimeout > 0 {
+		timer := time.NewTimer(lc.notificationsSubscriberTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case queue <- nb:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timeout:
+		lc.evictedSubscribersCounter.Inc()
+		lc.log.Warn(
+			"Evicting notifications subscriber that is too slow",
+			slog.Int64("offset", nb.Offset),
+			slog.Duration("timeout", lc.notificationsSubscriberTimeout),
+		)
+		return common.ErrorSubscriberTooSlow
+	}
+}
+
+// Merge consecutive batches into fewer stream messages. A message takes the
+// offset and timestamp of the last batch merged into it, so that the client
+// resumes after it on reconnection, while the notifications coming from the
+// previous batches keep their own offset. A message is closed when it reaches
+// the max number of notifications, or when a key is already in it, to not
+// lose any of the notifications of that key.
+func mergeNotifications(batches []*proto.NotificationBatch, maxNotifications int) []*proto.NotificationBatch {
+	var res []*proto.NotificationBatch
+	var current *proto.NotificationBatch
+
+	for _, nb := range batches {
+		if current != nil && !canMergeNotifications(current, nb, maxNotifications) {
+			res = append(res, current)
+			current = nil
+		}
+
+		if current == nil {
+			current = &proto.NotificationBatch{
+				ShardId:       nb.ShardId,
+				Notifications: make(map[string]*proto.Notification, len(nb.Notifications)),
+			}
+		}
+
+		if len(current.Notifications) > 0 {
+			setNotificationsOffset(current)
+		}
+
+		current.Offset = nb.Offset
+		current.Timestamp = nb.Timestamp
+		for key, n := range nb.Notifications {
+			current.Notifications[key] = n
+		}
+	}
+
+	if current != nil {
+		res = append(res, current)
+	}
+	return res
+}
+
+// Stamp the offset of the batch on the notifications that don't have one yet.
+// The notifications are copied, since they can be shared with other
+// subscribers.
+func setNotificationsOffset(nb *proto.NotificationBatch) {
+	offset := nb.Offset
+	for key, n := range nb.Notifications {
+		if n.Offset == nil {
+			n = n.CloneVT()
+			n.Offset = &offset
+			nb.Notifications[key] = n
+		}
+	}
+}
+
+func canMergeNotifications(current *proto.NotificationBatch, nb *proto.NotificationBatch, maxNotifications int) bool {
+	if len(current.Notifications)+len(nb.Notifications) > maxNotifications {
+		return false
+	}
+	for key := range nb.Notifications {
+		if _, ok := current.Notifications[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// The notifications selected by a subscriber.
+type notificationsFilter struct {
+	keyPrefixes []string
+
+	// The types of notification to send, all of them when empty
+	types map[proto.NotificationType]bool
+
+	includeValue bool
+}
+
+func newNotificationsFilter(req *proto.NotificationsRequest) *notificationsFilter {
+	f := &notificationsFilter{
+		keyPrefixes:  req.KeyPrefixes,
+		includeValue: req.IncludeValue,
+	}
+	if len(req.Types) > 0 {
+		f.types = map[proto.NotificationType]bool{}
+		for _, t := range req.Types {
+			f.types[t] = true
+		}
+	}
+	return f
+}
+
+func (f *notificationsFilter) matchesKey(key string) bool {
+	if len(f.keyPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range f.keyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// The range deletions are only reported with their own type to the
+// subscribers that selected it, so that the older clients keep receiving
+// them as plain deletions.
+func (f *notificationsFilter) notificationType(t proto.NotificationType) proto.NotificationType {
+	if t == proto.NotificationType_KEY_RANGE_DELETED && !f.types[t] {
+		return proto.NotificationType_KEY_DELETED
+	}
+	return t
+}
+
+func (f *notificationsFilter) matchesType(t proto.NotificationType) bool {
+	return len(f.types) == 0 || f.types[t]
+}
+
+// Only keep the notifications selected by the filter. The batches left
+// without notifications are not sent at all, the client will resume from the
+// offset of the last batch it received.
+func filterNotifications(nb *proto.NotificationBatch, filter *notificationsFilter) *proto.NotificationBatch {
+	filtered := &proto.NotificationBatch{
+		ShardId:       nb.ShardId,
+		Offset:        nb.Offset,
+		Timestamp:     nb.Timestamp,
+		Notifications: make(map[string]*proto.Notification, len(nb.Notifications)),
+	}
+	for key, n := range nb.Notifications {
+		t := filter.notificationType(n.Type)
+		if !filter.matchesKey(key) || !filter.matchesType(t) {
+			continue
+		}
+
+		if t != n.Type {
+			// The notifications are shared with the other subscribers
+			n = n.CloneVT()
+			n.Type = t
+		}
+		filtered.Notifications[key] = n
+	}
+
+	if len(filtered.Notifications) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// Add the current value of the records to the notifications of the batch,
+// when the record still has the version of the notification. Otherwise, the
+// record was changed again and a later notification follows.
+func (lc *leaderController) inlineValues(nb *proto.NotificationBatch) (*proto.NotificationBatch, error) {
+	for key, n := range nb.Notifications {
+		if n.Type != proto.NotificationType_KEY_CREATED && n.Type != proto.NotificationType_KEY_MODIFIED {
+			continue
+		}
+
+		gr, err := lc.db.Get(&proto.GetRequest{Key: key, IncludeValue: true})
+		if err != nil {
+			return nil, err
+		}
+		if gr.Status != proto.Status_OK || gr.Version.VersionId != n.GetVersionId() {
+			continue
+		}
+
+		n = n.CloneVT()
+		n.Value = gr.Value
+		if n.Value == nil {
+			n.Value = []byte{}
+		}
+		nb.Notifications[key] = n
+	}
+	return nb, nil
 }
 
 func (lc *leaderController) isClosed() bool {
@@ -1085,6 +1577,9 @@ func (lc *leaderController) close() error {
 		g.Unregister()
 	}
 	lc.followerAckOffsetGauges = map[string]metrics.Gauge{}
+	lc.followersHealth = make(map[string]*followerHealth)
+	lc.unhealthyFollowersCount.Store(0)
+	lc.unhealthyFollowersGauge.Unregister()
 
 	err = lc.sessionManager.Close()
 
@@ -1144,14 +1639,149 @@ func (lc *leaderController) GetStatus(_ *proto.GetStatusRequest) (*proto.GetStat
 		commitOffset = lc.quorumAckTracker.CommitOffset()
 	}
 
+	var load *proto.ShardLoad
+	if lc.status == proto.ServingStatus_LEADER {
+		load = &proto.ShardLoad{
+			WriteOps:   lc.writeOps.Load(),
+			ReadOps:    lc.readOps.Load(),
+			WriteBytes: lc.writeBytes.Load(),
+			DiskBytes:  lc.db.DiskSpaceUsage(),
+		}
+	}
+
 	return &proto.GetStatusResponse{
-		Term:         lc.term,
-		Status:       lc.status,
-		HeadOffset:   headOffset,
-		CommitOffset: commitOffset,
+		Term:               lc.term,
+		Status:             lc.status,
+		HeadOffset:         headOffset,
+		CommitOffset:       commitOffset,
+		UnhealthyFollowers: lc.unhealthyFollowers(),
+		Load:               load,
 	}, nil
 }
 
+func (lc *leaderController) trackWrite(request *proto.WriteRequest) {
+	lc.writeOps.Add(uint64(len(request.Puts) + len(request.Deletes) + len(request.DeleteRanges)))
+	lc.writeBytes.Add(uint64(request.SizeVT()))
+}
+
+func (lc *leaderController) monitorFollowersHealth() {
+	ticker := time.NewTicker(followersHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lc.ctx.Done():
+			return
+		case <-ticker.C:
+			lc.checkFollowersHealth(time.Now())
+		}
+	}
+}
+
+// A follower is considered unhealthy when it's lagging behind the head offset
+// and its ack offset hasn't moved for longer than the ack timeout. The leader
+// keeps going with the remaining quorum and the unhealthy followers are
+// reported to the coordinator through the GetStatus response, so that they
+// can be replaced.
+func (lc *leaderController) checkFollowersHealth(now time.Time) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.status != proto.ServingStatus_LEADER || lc.quorumAckTracker == nil {
+		return
+	}
+
+	headOffset := lc.quorumAckTracker.HeadOffset()
+	for follower, cursor := range lc.followers {
+		ackOffset := cursor.AckOffset()
+		fh, ok := lc.followersHealth[follower]
+		if !ok {
+			lc.followersHealth[follower] = &followerHealth{ackOffset: ackOffset, lastProgress: now}
+			continue
+		}
+
+		if ackOffset != fh.ackOffset || ackOffset >= headOffset {
+			fh.ackOffset = ackOffset
+			fh.lastProgress = now
+			if fh.unhealthy {
+				fh.unhealthy = false
+				lc.unhealthyFollowersCount.Add(-1)
+				lc.log.Info(
+					"Follower is acknowledging entries again",
+					slog.String("follower", follower),
+					slog.Int64("ack-offset", ackOffset),
+				)
+			}
+			continue
+		}
+
+		if !fh.unhealthy && now.Sub(fh.lastProgress) > lc.followerAckTimeout {
+			fh.unhealthy = true
+			lc.unhealthyFollowersCount.Add(1)
+			lc.followerAckTimeoutsCount.Inc()
+			lc.log.Error(
+				"Follower has not acknowledged entries within the ack timeout. Reporting it as unhealthy",
+				slog.String("follower", follower),
+				slog.Int64("ack-offset", ackOffset),
+				slog.Int64("head-offset", headOffset),
+				slog.Duration("ack-timeout", lc.followerAckTimeout),
+			)
+		}
+	}
+}
+
+// This is called while already holding the lock on the leader controller.
+func (lc *leaderController) unhealthyFollowers() []string {
+	var res []string
+	for follower, fh := range lc.followersHealth {
+		if fh.unhealthy {
+			res = append(res, follower)
+		}
+	}
+
+	sort.Strings(res)
+	return res
+}
+
+func (lc *leaderController) ExportRecords(req *proto.ExportRecordsRequest, stream proto.OxiaCoordination_ExportRecordsServer) error {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if lc.isClosed() {
+		return common.ErrorAlreadyClosed
+	}
+
+	if lc.status != proto.ServingStatus_FENCED {
+		return common.ErrorInvalidStatus
+	}
+
+	if req.Term != lc.term {
+		return common.ErrorInvalidTerm
+	}
+
+	lc.log.Info("Exporting the records of the shard to merge it")
+	return exportRecords(lc.db, lc.wal, stream)
+}
+
+func (lc *leaderController) ImportRecords(ctx context.Context, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	lc.RLock()
+	term := lc.term
+	lc.RUnlock()
+
+	if req.Term != term {
+		return nil, common.ErrorInvalidTerm
+	}
+
+	switch {
+	case req.Abort:
+		return lc.abortMergedRecords(ctx, req.MergedShardId)
+	case req.Commit:
+		return lc.commitMergedRecords(ctx, req.MergedShardId)
+	default:
+		return lc.stageMergedRecords(ctx, req)
+	}
+}
+
 func (lc *leaderController) DeleteShard(request *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error) {
 	lc.Lock()
 	defer lc.Unlock()
@@ -1183,16 +1813,165 @@ func (lc *leaderController) DeleteShard(request *proto.DeleteShardRequest) (*pro
 	return &proto.DeleteShardResponse{}, nil
 }
 
-func (lc *leaderController) CreateSession(request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error) {
-	return lc.sessionManager.CreateSession(request)
+func (lc *leaderController) CreateSession(ctx context.Context, request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error) {
+	return lc.sessionManager.CreateSession(ctx, request)
+}
+
+func (lc *leaderController) KeepAlive(ctx context.Context, sessionId int64) error {
+	return lc.sessionManager.KeepAlive(ctx, sessionId)
+}
+
+func (lc *leaderController) CloseSession(ctx context.Context, request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+	return lc.sessionManager.CloseSession(ctx, request)
+}
+
+func (lc *leaderController) GrantLease(ctx context.Context, request *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error) {
+	return lc.sessionManager.GrantLease(ctx, request)
+}
+
+func (lc *leaderController) RefreshLease(ctx context.Context, request *proto.LeaseRequest) error {
+	return lc.sessionManager.RefreshLease(ctx, request)
+}
+
+func (lc *leaderController) RevokeLease(ctx context.Context, request *proto.LeaseRequest) error {
+	return lc.sessionManager.RevokeLease(ctx, request)
+}
+
+func (lc *leaderController) ListSessions(ctx context.Context) ([]*proto.SessionStatus, error) {
+	return lc.sessionManager.ListSessions(ctx)
+}
+
+func (lc *leaderController) UpdatePolicies(policies *proto.NamespacePolicies) {
+	lc.policies.update(policies)
+}
+
+func (lc *leaderController) Authorize(ctx context.Context, required permission) error {
+	principal, authenticated := auth.GetPrincipal(ctx)
+	return lc.checkPolicy(lc.policies.authorize(principal, authenticated, required))
+}
+
+func (lc *leaderController) authorizeGets(ctx context.Context, gets []*proto.GetRequest) error {
+	principal, authenticated := auth.GetPrincipal(ctx)
+	for _, get := range gets {
+		if err := lc.policies.authorizeKey(principal, authenticated, permissionRead, get.Key); err != nil {
+			return lc.checkPolicy(err)
+		}
+	}
+	return nil
+}
+
+func (lc *leaderController) authorizeRange(ctx context.Context, start string, end string) error {
+	principal, authenticated := auth.GetPrincipal(ctx)
+	return lc.checkPolicy(lc.policies.authorizeRange(principal, authenticated, permissionRead, start, end))
+}
+
+func (lc *leaderController) SetNotificationsRetention(retention time.Duration) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if lc.db != nil {
+		lc.db.SetNotificationsRetention(retention)
+	}
+}
+
+func (lc *leaderController) checkPolicy(err error) error {
+	if err != nil {
+		lc.rejectedRequestsCounter.Inc()
+	}
+	return err
 }
 
-func (lc *leaderController) KeepAlive(sessionId int64) error {
-	return lc.sessionManager.KeepAlive(sessionId)
+// checkRead applies the rate limits of the namespace, of the client, of the
+// principal and of the tenant to the reads.
+func (lc *leaderController) checkRead(ctx context.Context, ops int) error {
+	if err := lc.checkPolicy(lc.policies.checkRead(clientId(ctx, nil), ops)); err != nil {
+		return err
+	}
+
+	if principal, authenticated := auth.GetPrincipal(ctx); authenticated {
+		quota, err := lc.policies.checkPrincipalRead(principal, ops)
+		if err := lc.checkQuota(principal, quota, err); err != nil {
+			return err
+		}
+	}
+	return lc.checkPolicy(lc.tenants.check(lc.policies.tenant(), ops, 0, 0))
+}
+
+// checkWrite applies the limits of the namespace, of the client, of the
+// principal and of the tenant to the writes. The request is then marked with
+// the principal, which owns the records it puts, for its storage to be
+// accounted on all the replicas.
+func (lc *leaderController) checkWrite(ctx context.Context, principal string, authenticated bool, request *proto.WriteRequest) error {
+	if err := lc.checkPolicy(lc.policies.checkWrite(clientId(ctx, request), request)); err != nil {
+		return err
+	}
+
+	// Only the coordinator commits the merges of the shards
+	request.MergedShardId = nil
+	request.Principal = nil
+	if authenticated {
+		request.Principal = &principal
+		quota, err := lc.policies.checkPrincipalWrite(principal, request, lc.storageUsage)
+		if err := lc.checkQuota(principal, quota, err); err != nil {
+			return err
+		}
+	}
+
+	ops, bytes := writeRequestSize(request)
+	return lc.checkPolicy(lc.tenants.check(lc.policies.tenant(), 0, ops, bytes))
+}
+
+// clientId identifies the client of a request, for its own rate limits: by
+// the session the request is bound to, if any, or else by the principal it
+// is authenticated as, or by the address of its connection. The identity
+// the clients send is not trusted, since any client could claim the one of
+// another.
+func clientId(ctx context.Context, request *proto.WriteRequest) string {
+	for _, put := range request.GetPuts() {
+		if put.SessionId != nil {
+			return fmt.Sprintf("session/%d", put.GetSessionId())
+		}
+	}
+	if principal, authenticated := auth.GetPrincipal(ctx); authenticated {
+		return "principal/" + principal
+	}
+	return "peer/" + common.GetPeer(ctx)
+}
+
+func (lc *leaderController) storageUsage(principal string) (int64, error) {
+	lc.RLock()
+	db := lc.db
+	lc.RUnlock()
+
+	if db == nil {
+		return 0, common.ErrorNotInitialized
+	}
+	return db.StorageUsage(principal)
+}
+
+func (lc *leaderController) checkQuota(principal string, quota string, err error) error {
+	if err != nil && quota != "" {
+		lc.log.Debug(
+			"Request rejected by the quota of the principal",
+			slog.String("principal", principal),
+			slog.String("quota", quota),
+		)
+		lc.quotaRejectionsCounters[quota].Inc()
+	}
+	return lc.checkPolicy(err)
 }
 
-func (lc *leaderController) CloseSession(request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
-	return lc.sessionManager.CloseSession(request)
+// The principals are not used as labels, since they're not bounded and the
+// counters are never removed. The rejected principals are logged instead.
+func newQuotaRejectionsCounters(shardLabels map[string]any) map[string]metrics.Counter {
+	counters := map[string]metrics.Counter{}
+	for _, quota := range []string{quotaOps, quotaWriteBytes, quotaStorage} {
+		labels := maps.Clone(shardLabels)
+		labels["quota"] = quota
+		counters[quota] = metrics.NewCounter("oxia_server_leader_quota_rejected_requests",
+			"The number of requests rejected by the quotas of the principals", "count", labels)
+	}
+	return counters
 }
 
 func checkStatusIsLeader(actual proto.ServingStatus) error {
@@ -16,16 +16,21 @@ package server
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	pb "google.golang.org/protobuf/proto"
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
 	"github.com/streamnative/oxia/server/kv"
 	"github.com/streamnative/oxia/server/wal"
 )
@@ -341,6 +346,58 @@ func TestLeaderController_BecomeLeader_RF2(t *testing.T) {
 	assert.NoError(t, walFactory.Close())
 }
 
+func TestLeaderController_BecomeLeader_RelaxedReplication(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	rpc := newMockRpcClient()
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, rpc, walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{
+		ShardId: shard,
+		Term:    1,
+	})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 3,
+		FollowerMaps: map[string]*proto.EntryId{
+			"f1": InvalidEntryId,
+			"f2": InvalidEntryId,
+		},
+		ReplicationMode: proto.ReplicationMode_RELAXED,
+	})
+	assert.NoError(t, err)
+
+	// The write is completed without any ack from the followers
+	res, err := lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts: []*proto.PutRequest{{
+			Key:   "a",
+			Value: []byte("value-a")}},
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, len(res.Puts))
+	assert.Equal(t, proto.Status_OK, res.Puts[0].Status)
+
+	res2, err := lc.GetStatus(&proto.GetStatusRequest{ShardId: shard})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, res2.HeadOffset)
+	assert.EqualValues(t, 0, res2.CommitOffset)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
 func TestLeaderController_TermPersistent(t *testing.T) {
 	var shard int64 = 1
 
@@ -432,6 +489,246 @@ func TestLeaderController_FenceTerm(t *testing.T) {
 	assert.NoError(t, walFactory.Close())
 }
 
+func TestLeaderController_NewTermCheckMergeable(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	becomeLeader := func(term int64, expectedMergeable bool) {
+		fr, err := lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: term, CheckMergeable: true})
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMergeable, fr.Mergeable)
+
+		_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+			ShardId:           shard,
+			Term:              term,
+			ReplicationFactor: 1,
+		})
+		assert.NoError(t, err)
+	}
+
+	becomeLeader(1, true)
+
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "a", Value: []byte("value-a")}},
+	})
+	assert.NoError(t, err)
+
+	// The records can be copied into another shard
+	becomeLeader(2, true)
+
+	// The records are only checked when requested
+	fr, err := lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 3})
+	assert.NoError(t, err)
+	assert.False(t, fr.Mergeable)
+
+	// A live session cannot be moved into another shard
+	becomeLeader(4, true)
+	_, err = lc.CreateSession(context.Background(), &proto.CreateSessionRequest{
+		ShardId:          shard,
+		SessionTimeoutMs: 10_000,
+		ClientIdentity:   "client-1",
+	})
+	assert.NoError(t, err)
+
+	fr, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 5, CheckMergeable: true})
+	assert.NoError(t, err)
+	assert.False(t, fr.Mergeable)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_ExportImportRecords(t *testing.T) {
+	var shard int64 = 1
+	var intoShard int64 = 2
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+	into, err := NewLeaderController(Config{}, common.DefaultNamespace, intoShard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		lc    LeaderController
+		shard int64
+	}{{lc, shard}, {into, intoShard}} {
+		_, err = c.lc.NewTerm(&proto.NewTermRequest{ShardId: c.shard, Term: 1})
+		assert.NoError(t, err)
+		_, err = c.lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+			ShardId:           c.shard,
+			Term:              1,
+			ReplicationFactor: 1,
+		})
+		assert.NoError(t, err)
+	}
+
+	partitionKey := "x"
+	for i := 0; i < 2; i++ {
+		_, err = lc.Write(context.Background(), &proto.WriteRequest{
+			ShardId: &shard,
+			Puts: []*proto.PutRequest{
+				{Key: "a", Value: []byte("value-a")},
+				{Key: "b/c", Value: []byte("value-b"), PartitionKey: &partitionKey},
+			},
+		})
+		assert.NoError(t, err)
+	}
+
+	// The records are only exported once the shard is fenced
+	stream := newMockExportRecordsServer(context.Background())
+	assert.ErrorIs(t, lc.ExportRecords(&proto.ExportRecordsRequest{ShardId: shard, Term: 1}, stream), common.ErrorInvalidStatus)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 2})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, lc.ExportRecords(&proto.ExportRecordsRequest{ShardId: shard, Term: 1}, stream), common.ErrorInvalidTerm)
+
+	assert.NoError(t, lc.ExportRecords(&proto.ExportRecordsRequest{ShardId: shard, Term: 2}, stream))
+	assert.Len(t, stream.responses, 1)
+	records := stream.responses[0].Records
+	assert.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].Key)
+	assert.Equal(t, "b/c", records[1].Key)
+
+	se := &proto.StorageEntry{}
+	assert.NoError(t, se.UnmarshalVT(records[1].Entry))
+	assert.Equal(t, []byte("value-b"), se.Value)
+	assert.Equal(t, &partitionKey, se.PartitionKey)
+	assert.EqualValues(t, 1, se.ModificationsCount)
+
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          2,
+		MergedShardId: shard,
+		Records:       records,
+	})
+	assert.ErrorIs(t, err, common.ErrorInvalidTerm)
+
+	wr, err := into.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &intoShard,
+		Puts:    []*proto.PutRequest{{Key: "z", Value: []byte("value-z")}},
+	})
+	assert.NoError(t, err)
+	intoVersionId := wr.Puts[0].Version.VersionId
+
+	// The staged records are not visible until the merge is committed
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          1,
+		MergedShardId: shard,
+		Records:       records,
+	})
+	assert.NoError(t, err)
+
+	getB := func() *proto.GetResponse {
+		r := <-into.Read(context.Background(), &proto.ReadRequest{
+			ShardId: &intoShard,
+			Gets:    []*proto.GetRequest{{Key: "b/c", IncludeValue: true}},
+		})
+		assert.NoError(t, r.Err)
+		return r.Response
+	}
+	assert.Equal(t, proto.Status_KEY_NOT_FOUND, getB().Status)
+
+	for i := 0; i < 2; i++ {
+		_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+			ShardId:       intoShard,
+			Term:          1,
+			MergedShardId: shard,
+			Commit:        true,
+		})
+		assert.NoError(t, err)
+	}
+
+	res := getB()
+	assert.Equal(t, proto.Status_OK, res.Status)
+	assert.Equal(t, []byte("value-b"), res.Value)
+	// The merged records get the offset of the merge as their version id
+	assert.Greater(t, res.Version.VersionId, intoVersionId)
+	assert.EqualValues(t, 1, res.Version.ModificationsCount)
+	assert.Equal(t, se.CreationTimestamp, res.Version.CreatedTimestamp)
+
+	wr, err = into.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &intoShard,
+		Puts:    []*proto.PutRequest{{Key: "b/c", Value: []byte("value-b")}},
+	})
+	assert.NoError(t, err)
+	assert.Greater(t, wr.Puts[0].Version.VersionId, res.Version.VersionId)
+	assert.EqualValues(t, 2, wr.Puts[0].Version.ModificationsCount)
+
+	// The records of another merge are rejected if their keys are already in
+	// the shard
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          1,
+		MergedShardId: 3,
+		Records:       records,
+	})
+	assert.ErrorIs(t, err, common.ErrorKeyAlreadyExists)
+
+	// A key written after the records were staged fails the commit, and the
+	// record written in the shard is left untouched by the abort
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          1,
+		MergedShardId: 3,
+		Records:       []*proto.MergedRecord{{Key: "d", Entry: records[0].Entry}},
+	})
+	assert.NoError(t, err)
+	_, err = into.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &intoShard,
+		Puts:    []*proto.PutRequest{{Key: "d", Value: []byte("value-d")}},
+	})
+	assert.NoError(t, err)
+
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          1,
+		MergedShardId: 3,
+		Commit:        true,
+	})
+	assert.ErrorIs(t, err, common.ErrorKeyAlreadyExists)
+
+	_, err = into.ImportRecords(context.Background(), &proto.ImportRecordsRequest{
+		ShardId:       intoShard,
+		Term:          1,
+		MergedShardId: 3,
+		Abort:         true,
+	})
+	assert.NoError(t, err)
+
+	r := <-into.Read(context.Background(), &proto.ReadRequest{
+		ShardId: &intoShard,
+		Gets:    []*proto.GetRequest{{Key: "d", IncludeValue: true}},
+	})
+	assert.NoError(t, r.Err)
+	assert.Equal(t, []byte("value-d"), r.Response.Value)
+
+	startInclusive, endExclusive := kv.MergedRecordsRange(3)
+	it, err := into.(*leaderController).db.List(&proto.ListRequest{
+		StartInclusive: startInclusive,
+		EndExclusive:   endExclusive,
+	})
+	assert.NoError(t, err)
+	assert.False(t, it.Valid())
+	assert.NoError(t, it.Close())
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, into.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
 func TestLeaderController_BecomeLeaderTerm(t *testing.T) {
 	var shard int64 = 1
 
@@ -860,6 +1157,229 @@ func TestLeaderController_Notifications(t *testing.T) {
 	assert.NoError(t, walFactory.Close())
 }
 
+func TestLeaderController_NotificationsKeyPrefixes(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	lc, _ := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	_, _ = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	_, _ = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+		FollowerMaps:      nil,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newMockGetNotificationsServer(ctx)
+
+	go func() {
+		_ = lc.GetNotifications(&proto.NotificationsRequest{
+			ShardId:              shard,
+			StartOffsetExclusive: &wal.InvalidOffset,
+			KeyPrefixes:          []string{"/a/", "/c/"},
+		}, stream)
+	}()
+
+	for _, keys := range [][]string{{"/b/1"}, {"/a/1", "/b/2"}, {"/c/1"}} {
+		req := &proto.WriteRequest{ShardId: &shard}
+		for _, key := range keys {
+			req.Puts = append(req.Puts, &proto.PutRequest{Key: key, Value: []byte("value")})
+		}
+		_, err := lc.Write(context.Background(), req)
+		assert.NoError(t, err)
+	}
+
+	// The batch without any matching key is skipped, the other ones can be
+	// merged into a single message
+	received := map[string]*proto.Notification{}
+	for offset := int64(-1); offset < 2; {
+		nb := <-stream.ch
+		assert.Greater(t, nb.Offset, int64(0))
+		offset = nb.Offset
+		for key, n := range nb.Notifications {
+			received[key] = n
+		}
+	}
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, proto.NotificationType_KEY_CREATED, received["/a/1"].Type)
+	assert.NotNil(t, received["/c/1"])
+
+	cancel()
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_NotificationsSlowSubscriber(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	lc, _ := NewLeaderController(Config{NotificationsSubscriberTimeout: 100 * time.Millisecond},
+		common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	_, _ = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	_, _ = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+		FollowerMaps:      nil,
+	})
+
+	// Writing the same key prevents the batches from being merged
+	for i := 0; i < 2*notificationsQueueSize; i++ {
+		_, err := lc.Write(context.Background(), &proto.WriteRequest{
+			ShardId: &shard,
+			Puts:    []*proto.PutRequest{{Key: "a", Value: []byte("value")}},
+		})
+		assert.NoError(t, err)
+	}
+
+	// The subscriber never consumes the notifications
+	stream := newMockGetNotificationsServer(context.Background())
+	stream.ch = make(chan *proto.NotificationBatch)
+
+	err := lc.GetNotifications(&proto.NotificationsRequest{ShardId: shard, StartOffsetExclusive: &wal.InvalidOffset}, stream)
+	assert.ErrorIs(t, err, common.ErrorSubscriberTooSlow)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestMergeNotifications(t *testing.T) {
+	batch := func(offset int64, keys ...string) *proto.NotificationBatch {
+		nb := &proto.NotificationBatch{
+			ShardId:       1,
+			Offset:        offset,
+			Timestamp:     uint64(offset * 10),
+			Notifications: map[string]*proto.Notification{},
+		}
+		for _, key := range keys {
+			nb.Notifications[key] = &proto.Notification{Type: proto.NotificationType_KEY_CREATED}
+		}
+		return nb
+	}
+
+	assert.Nil(t, mergeNotifications(nil, 10))
+
+	res := mergeNotifications([]*proto.NotificationBatch{
+		batch(0, "a"),
+		batch(1, "b", "c"),
+		// Key already in the message
+		batch(2, "a"),
+		batch(3, "d", "e"),
+		// Max number of notifications reached
+		batch(4, "f"),
+	}, 3)
+
+	assert.Equal(t, 3, len(res))
+	assert.EqualValues(t, 1, res[0].Offset)
+	assert.EqualValues(t, 10, res[0].Timestamp)
+	assert.Equal(t, 3, len(res[0].Notifications))
+	// The notifications of the merged batches keep their offset
+	assert.EqualValues(t, 0, *res[0].Notifications["a"].Offset)
+	assert.Nil(t, res[0].Notifications["b"].Offset)
+	assert.EqualValues(t, 3, res[1].Offset)
+	assert.Equal(t, 3, len(res[1].Notifications))
+	assert.EqualValues(t, 4, res[2].Offset)
+	assert.Equal(t, 1, len(res[2].Notifications))
+	assert.NotNil(t, res[2].Notifications["f"])
+}
+
+func TestLeaderController_AckNotifications(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	lc, _ := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	_, _ = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	_, _ = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+		FollowerMaps:      nil,
+	})
+
+	ctx := context.Background()
+	_, err := lc.AckNotifications(ctx, &proto.AckNotificationsRequest{ShardId: shard, Offset: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = lc.AckNotifications(ctx, &proto.AckNotificationsRequest{ShardId: shard, ConsumerName: "c1", Offset: 5})
+	assert.NoError(t, err)
+
+	// The acknowledged offset doesn't move backward
+	_, err = lc.AckNotifications(ctx, &proto.AckNotificationsRequest{ShardId: shard, ConsumerName: "c1", Offset: 3})
+	assert.NoError(t, err)
+
+	offset, found, err := lc.(*leaderController).notificationsConsumerOffset("c1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.EqualValues(t, 5, offset)
+
+	_, found, err = lc.(*leaderController).notificationsConsumerOffset("c2")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestFilterNotifications(t *testing.T) {
+	nb := &proto.NotificationBatch{
+		ShardId:   1,
+		Offset:    5,
+		Timestamp: 50,
+		Notifications: map[string]*proto.Notification{
+			"/a/1": {Type: proto.NotificationType_KEY_CREATED},
+			"/a/2": {Type: proto.NotificationType_KEY_MODIFIED},
+			"/a/3": {Type: proto.NotificationType_KEY_DELETED},
+			"/a/4": {Type: proto.NotificationType_KEY_RANGE_DELETED},
+			"/b/1": {Type: proto.NotificationType_KEY_MODIFIED},
+		},
+	}
+
+	// No filter, the range deletions are sent as deletions
+	res := filterNotifications(nb, newNotificationsFilter(&proto.NotificationsRequest{}))
+	assert.EqualValues(t, 5, res.Offset)
+	assert.EqualValues(t, 50, res.Timestamp)
+	assert.Equal(t, 5, len(res.Notifications))
+	assert.Equal(t, proto.NotificationType_KEY_DELETED, res.Notifications["/a/4"].Type)
+	// The original batch is not modified
+	assert.Equal(t, proto.NotificationType_KEY_RANGE_DELETED, nb.Notifications["/a/4"].Type)
+
+	res = filterNotifications(nb, newNotificationsFilter(&proto.NotificationsRequest{
+		Types: []proto.NotificationType{proto.NotificationType_KEY_DELETED},
+	}))
+	assert.Equal(t, 2, len(res.Notifications))
+	assert.Equal(t, proto.NotificationType_KEY_DELETED, res.Notifications["/a/3"].Type)
+	assert.Equal(t, proto.NotificationType_KEY_DELETED, res.Notifications["/a/4"].Type)
+
+	res = filterNotifications(nb, newNotificationsFilter(&proto.NotificationsRequest{
+		Types: []proto.NotificationType{proto.NotificationType_KEY_MODIFIED, proto.NotificationType_KEY_RANGE_DELETED},
+	}))
+	assert.Equal(t, 3, len(res.Notifications))
+	assert.Equal(t, proto.NotificationType_KEY_RANGE_DELETED, res.Notifications["/a/4"].Type)
+	assert.NotNil(t, res.Notifications["/b/1"])
+
+	res = filterNotifications(nb, newNotificationsFilter(&proto.NotificationsRequest{
+		KeyPrefixes: []string{"/b/"},
+		Types:       []proto.NotificationType{proto.NotificationType_KEY_MODIFIED},
+	}))
+	assert.Equal(t, 1, len(res.Notifications))
+	assert.NotNil(t, res.Notifications["/b/1"])
+
+	// Batches left without notifications are dropped
+	assert.Nil(t, filterNotifications(nb, newNotificationsFilter(&proto.NotificationsRequest{
+		KeyPrefixes: []string{"/c/"},
+	})))
+}
+
 func TestLeaderController_NotificationsCloseLeader(t *testing.T) {
 	var shard int64 = 1
 
@@ -924,7 +1444,7 @@ func TestLeaderController_NotificationsWhenNotReady(t *testing.T) {
 
 	// Get notification should fail if the leader controller is not fully initialized
 	err := lc.GetNotifications(&proto.NotificationsRequest{ShardId: shard}, stream)
-	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "leader is not yet ready")
 
 	assert.NoError(t, lc.Close())
 	assert.NoError(t, kvFactory.Close())
@@ -1029,7 +1549,7 @@ func TestLeaderController_RangeScan(t *testing.T) {
 	assert.False(t, more)
 }
 
-func TestLeaderController_DeleteShard(t *testing.T) {
+func TestLeaderController_ListRangeScanSkipInternalKeys(t *testing.T) {
 	var shard int64 = 1
 
 	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
@@ -1046,12 +1566,62 @@ func TestLeaderController_DeleteShard(t *testing.T) {
 
 	_, err := lc.Write(context.Background(), &proto.WriteRequest{
 		ShardId: &shard,
-		Puts:    []*proto.PutRequest{{Key: "k1", Value: []byte("hello")}},
+		Puts: []*proto.PutRequest{
+			{Key: "/a", Value: []byte{0}},
+			{Key: "/b/c", Value: []byte{0}},
+		},
 	})
 	assert.NoError(t, err)
 
-	_, err = lc.DeleteShard(&proto.DeleteShardRequest{
-		Namespace: common.DefaultNamespace,
+	// The shard holds internal keys, eg: the modification index of the records
+	internal, err := lc.ListSliceNoMutex(context.Background(), &proto.ListRequest{ShardId: &shard})
+	assert.NoError(t, err)
+	assert.Greater(t, len(internal), 2)
+
+	ch, err := lc.List(context.Background(), &proto.ListRequest{ShardId: &shard})
+	assert.NoError(t, err)
+	var keys []string
+	for key := range ch {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"/a", "/b/c"}, keys)
+
+	rangeCh, _, err := lc.RangeScan(context.Background(), &proto.RangeScanRequest{ShardId: &shard})
+	assert.NoError(t, err)
+	keys = nil
+	for gr := range rangeCh {
+		keys = append(keys, gr.GetKey())
+	}
+	assert.Equal(t, []string{"/a", "/b/c"}, keys)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_DeleteShard(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	lc, _ := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	_, _ = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	_, _ = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+		FollowerMaps:      nil,
+	})
+
+	_, err := lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "k1", Value: []byte("hello")}},
+	})
+	assert.NoError(t, err)
+
+	_, err = lc.DeleteShard(&proto.DeleteShardRequest{
+		Namespace: common.DefaultNamespace,
 		ShardId:   shard,
 		Term:      1,
 	})
@@ -1143,8 +1713,18 @@ func TestLeaderController_GetStatus(t *testing.T) {
 			Value: []byte("value-b")}},
 	})
 
+	// Read entry
+	r := <-lc.Read(context.Background(), &proto.ReadRequest{
+		ShardId: &shard,
+		Gets:    []*proto.GetRequest{{Key: "a", IncludeValue: true}},
+	})
+	assert.NoError(t, r.Err)
+
 	res, err := lc.GetStatus(&proto.GetStatusRequest{ShardId: shard})
 	assert.NoError(t, err)
+
+	load := res.Load
+	res.Load = nil
 	assert.Equal(t, &proto.GetStatusResponse{
 		Term:         2,
 		Status:       proto.ServingStatus_LEADER,
@@ -1152,6 +1732,11 @@ func TestLeaderController_GetStatus(t *testing.T) {
 		CommitOffset: 1,
 	}, res)
 
+	assert.EqualValues(t, 2, load.WriteOps)
+	assert.EqualValues(t, 1, load.ReadOps)
+	assert.Less(t, uint64(0), load.WriteBytes)
+	assert.Less(t, int64(0), load.DiskBytes)
+
 	assert.NoError(t, lc.Close())
 	assert.NoError(t, kvFactory.Close())
 	assert.NoError(t, walFactory.Close())
@@ -1263,3 +1848,436 @@ func TestLeaderController_WriteStream(t *testing.T) {
 	assert.NoError(t, kvFactory.Close())
 	assert.NoError(t, walFactory.Close())
 }
+
+func TestLeaderController_UnhealthyFollower(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	rpc := newMockRpcClient()
+
+	lc, err := NewLeaderController(Config{FollowerAckTimeout: 1 * time.Minute}, common.DefaultNamespace, shard, rpc, walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 2,
+		FollowerMaps: map[string]*proto.EntryId{
+			"f1": InvalidEntryId,
+		},
+	})
+	assert.NoError(t, err)
+
+	writeDone := make(chan error)
+	go func() {
+		_, err := lc.Write(context.Background(), &proto.WriteRequest{
+			ShardId: &shard,
+			Puts: []*proto.PutRequest{{
+				Key:   "a",
+				Value: []byte("value-a")}},
+		})
+		writeDone <- err
+	}()
+
+	// The entry is sent to the follower, which is not acknowledging it
+	req := <-rpc.appendReqs
+
+	t0 := time.Now()
+	lc.(*leaderController).checkFollowersHealth(t0)
+	res, err := lc.GetStatus(&proto.GetStatusRequest{ShardId: shard})
+	assert.NoError(t, err)
+	assert.Empty(t, res.UnhealthyFollowers)
+
+	lc.(*leaderController).checkFollowersHealth(t0.Add(2 * time.Minute))
+	res, err = lc.GetStatus(&proto.GetStatusRequest{ShardId: shard})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"f1"}, res.UnhealthyFollowers)
+
+	// Once the follower acks again, it's considered healthy
+	rpc.ackResps <- &proto.Ack{Offset: req.Entry.Offset}
+	assert.NoError(t, <-writeDone)
+
+	assert.Eventually(t, func() bool {
+		lc.(*leaderController).checkFollowersHealth(t0.Add(3 * time.Minute))
+		res, err = lc.GetStatus(&proto.GetStatusRequest{ShardId: shard})
+		assert.NoError(t, err)
+		return len(res.UnhealthyFollowers) == 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	close(rpc.ackResps)
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_NamespacePolicies(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+	})
+	assert.NoError(t, err)
+
+	lc.UpdatePolicies(&proto.NamespacePolicies{
+		DefaultTtlMs:       1000,
+		MaxValueSize:       8,
+		MaxWritesPerSecond: 0.1,
+	})
+
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "a", Value: []byte("value-too-large")}},
+	})
+	assert.Equal(t, common.CodeValueTooLarge, status.Code(err))
+
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "a", Value: []byte("value-a")}},
+	})
+	assert.NoError(t, err)
+
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "b", Value: []byte("value-b")}},
+	})
+	assert.Equal(t, common.CodeRateLimited, status.Code(err))
+
+	// The record is deleted once it outlives the default TTL
+	assert.Eventually(t, func() bool {
+		r := <-lc.Read(context.Background(), &proto.ReadRequest{
+			ShardId: &shard,
+			Gets:    []*proto.GetRequest{{Key: "a"}},
+		})
+		assert.NoError(t, r.Err)
+		return r.Response.Status == proto.Status_KEY_NOT_FOUND
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// Removing the policies lifts the limits
+	lc.UpdatePolicies(nil)
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "b", Value: []byte("value-not-limited")}},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_SlidingTTL(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+	})
+	assert.NoError(t, err)
+
+	lc.UpdatePolicies(&proto.NamespacePolicies{
+		DefaultTtlMs: 2000,
+		SlidingTtl:   true,
+	})
+
+	_, err = lc.Write(context.Background(), &proto.WriteRequest{
+		ShardId: &shard,
+		Puts: []*proto.PutRequest{
+			{Key: "a", Value: []byte("value-a")},
+			{Key: "b", Value: []byte("value-b")},
+		},
+	})
+	assert.NoError(t, err)
+
+	read := func(key string) proto.Status {
+		r := <-lc.Read(context.Background(), &proto.ReadRequest{
+			ShardId: &shard,
+			Gets:    []*proto.GetRequest{{Key: key}},
+		})
+		assert.NoError(t, r.Err)
+		return r.Response.Status
+	}
+
+	// Check the records without resetting their expiry
+	exists := func(key string) bool {
+		gr, err := lc.(*leaderController).db.Get(&proto.GetRequest{Key: key})
+		assert.NoError(t, err)
+		return gr.Status == proto.Status_OK
+	}
+
+	// The record that keeps being read outlives the TTL
+	assert.Eventually(t, func() bool {
+		assert.Equal(t, proto.Status_OK, read("a"))
+		return !exists("b")
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// It expires once it's not read anymore
+	assert.Eventually(t, func() bool {
+		return !exists("a")
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_KeyPrefixAuthorization(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+	})
+	assert.NoError(t, err)
+
+	put := func(ctx context.Context, key string) error {
+		_, err := lc.Write(ctx, &proto.WriteRequest{
+			ShardId: &shard,
+			Puts:    []*proto.PutRequest{{Key: key, Value: []byte("value")}},
+		})
+		return err
+	}
+	assert.NoError(t, put(context.Background(), "/a/1"))
+	assert.NoError(t, put(context.Background(), "/b/1"))
+	assert.NoError(t, put(context.Background(), "/shared/1"))
+
+	lc.UpdatePolicies(&proto.NamespacePolicies{
+		Authorization: &proto.NamespaceAuthorization{
+			KeyPrefixes: []*proto.KeyPrefixPermissions{
+				{Principal: "alice", Prefix: "/a/", Permissions: &proto.NamespacePermissions{Read: true, Write: true}},
+				{Principal: "alice", Prefix: "/shared/", Permissions: &proto.NamespacePermissions{Read: true}},
+			},
+		},
+	})
+
+	ctx := auth.WithPrincipal(context.Background(), "alice")
+	assert.NoError(t, lc.Authorize(ctx, permissionWrite))
+	assert.ErrorIs(t, lc.Authorize(context.Background(), permissionRead), common.ErrorPermissionDenied)
+
+	assert.NoError(t, put(ctx, "/a/2"))
+	assert.ErrorIs(t, put(ctx, "/shared/2"), common.ErrorPermissionDenied)
+	assert.ErrorIs(t, put(ctx, "/b/2"), common.ErrorPermissionDenied)
+
+	read := func(key string, comparison proto.KeyComparisonType) GetResult {
+		return <-lc.Read(ctx, &proto.ReadRequest{
+			ShardId: &shard,
+			Gets:    []*proto.GetRequest{{Key: key, ComparisonType: comparison}},
+		})
+	}
+	r := read("/shared/1", proto.KeyComparisonType_EQUAL)
+	assert.NoError(t, r.Err)
+	assert.Equal(t, proto.Status_OK, r.Response.Status)
+	assert.ErrorIs(t, read("/b/1", proto.KeyComparisonType_EQUAL).Err, common.ErrorPermissionDenied)
+
+	r = read("/a/5", proto.KeyComparisonType_FLOOR)
+	assert.NoError(t, r.Err)
+	assert.Equal(t, "/a/2", r.Response.GetKey())
+
+	// The lookups don't return the keys outside of the prefixes
+	r = read("/a/2", proto.KeyComparisonType_HIGHER)
+	assert.NoError(t, r.Err)
+	assert.Equal(t, proto.Status_KEY_NOT_FOUND, r.Response.Status)
+
+	ch, err := lc.List(ctx, &proto.ListRequest{ShardId: &shard, StartInclusive: "/a/", EndExclusive: "/a/~"})
+	assert.NoError(t, err)
+	var keys []string
+	for key := range ch {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"/a/1", "/a/2"}, keys)
+	_, err = lc.List(ctx, &proto.ListRequest{ShardId: &shard, StartInclusive: "/a/", EndExclusive: "/b/"})
+	assert.ErrorIs(t, err, common.ErrorPermissionDenied)
+
+	_, err = lc.Write(ctx, &proto.WriteRequest{
+		ShardId:      &shard,
+		DeleteRanges: []*proto.DeleteRangeRequest{{StartInclusive: "/shared/", EndExclusive: "/shared/~"}},
+	})
+	assert.ErrorIs(t, err, common.ErrorPermissionDenied)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_AuditLog(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	sink := &memoryAuditSink{}
+	lc, err := NewLeaderController(Config{AuditLog: sink}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+	})
+	assert.NoError(t, err)
+
+	ctx := auth.WithPrincipal(context.Background(), "alice")
+	_, err = lc.Write(ctx, &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "/a", Value: []byte("value")}},
+		Deletes: []*proto.DeleteRequest{{Key: "/b"}},
+	})
+	assert.NoError(t, err)
+
+	lc.UpdatePolicies(&proto.NamespacePolicies{
+		Authorization: &proto.NamespaceAuthorization{},
+	})
+	_, err = lc.Write(ctx, &proto.WriteRequest{
+		ShardId: &shard,
+		Puts:    []*proto.PutRequest{{Key: "/c", Value: []byte("value")}},
+	})
+	assert.ErrorIs(t, err, common.ErrorPermissionDenied)
+
+	assert.Len(t, sink.records, 3)
+	for _, r := range sink.records {
+		assert.Equal(t, "alice", r.Principal)
+		assert.Equal(t, common.DefaultNamespace, r.Namespace)
+		assert.Equal(t, shard, r.Shard)
+	}
+
+	assert.Equal(t, AuditOperationPut, sink.records[0].Operation)
+	assert.Equal(t, "/a", sink.records[0].Key)
+	assert.Equal(t, "OK", sink.records[0].Status)
+	assert.Equal(t, int64(0), *sink.records[0].VersionId)
+
+	assert.Equal(t, AuditOperationDelete, sink.records[1].Operation)
+	assert.Equal(t, "/b", sink.records[1].Key)
+	assert.Equal(t, "KEY_NOT_FOUND", sink.records[1].Status)
+
+	assert.Equal(t, "/c", sink.records[2].Key)
+	assert.Empty(t, sink.records[2].Status)
+	assert.NotEmpty(t, sink.records[2].Error)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_PrincipalQuotas(t *testing.T) {
+	var shard int64 = 1
+
+	kvFactory, err := kv.NewPebbleKVFactory(testKVOptions)
+	assert.NoError(t, err)
+	walFactory := newTestWalFactory(t)
+
+	lc, err := NewLeaderController(Config{}, common.DefaultNamespace, shard, newMockRpcClient(), walFactory, kvFactory)
+	assert.NoError(t, err)
+
+	_, err = lc.NewTerm(&proto.NewTermRequest{ShardId: shard, Term: 1})
+	assert.NoError(t, err)
+
+	_, err = lc.BecomeLeader(context.Background(), &proto.BecomeLeaderRequest{
+		ShardId:           shard,
+		Term:              1,
+		ReplicationFactor: 1,
+	})
+	assert.NoError(t, err)
+
+	lc.UpdatePolicies(&proto.NamespacePolicies{PrincipalQuotas: map[string]*proto.PrincipalQuota{
+		"alice": {MaxStorageBytes: 20},
+	}})
+
+	alice := auth.WithPrincipal(context.Background(), "alice")
+	put := func(ctx context.Context, key string, size int) error {
+		_, err := lc.Write(ctx, &proto.WriteRequest{
+			ShardId: &shard,
+			// The principal set by the client is ignored
+			Principal: pb.String("bob"),
+			Puts:      []*proto.PutRequest{{Key: key, Value: make([]byte, size)}},
+		})
+		return err
+	}
+
+	assert.NoError(t, put(alice, "/a", 8))
+	assert.NoError(t, put(alice, "/b", 8))
+	err = put(alice, "/c", 8)
+	assert.ErrorIs(t, err, common.ErrorQuotaExceeded)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The other principals, and the clients that are not authenticated,
+	// are not limited
+	assert.NoError(t, put(auth.WithPrincipal(context.Background(), "bob"), "/c", 8))
+	assert.NoError(t, put(context.Background(), "/d", 8))
+
+	// Deleting records frees the quota
+	_, err = lc.Write(alice, &proto.WriteRequest{
+		ShardId: &shard,
+		Deletes: []*proto.DeleteRequest{{Key: "/a"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, put(alice, "/c", 8))
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvFactory.Close())
+	assert.NoError(t, walFactory.Close())
+}
+
+func TestLeaderController_ClientId(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	assert.Equal(t, "peer/10.0.0.1:1234", clientId(ctx, nil))
+
+	// The identity sent by the client is not trusted
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("client-identity", "client-1"))
+	assert.Equal(t, "peer/10.0.0.1:1234", clientId(ctx, nil))
+
+	// The authenticated clients are told apart by their principal
+	ctx = auth.WithPrincipal(ctx, "team-a")
+	assert.Equal(t, "principal/team-a", clientId(ctx, nil))
+	assert.Equal(t, "principal/team-a", clientId(ctx, &proto.WriteRequest{
+		Puts: []*proto.PutRequest{{Key: "a", Value: []byte("0")}},
+	}))
+
+	// The requests bound to a session are limited by session
+	assert.Equal(t, "session/5", clientId(ctx, &proto.WriteRequest{
+		Puts: []*proto.PutRequest{{Key: "a", Value: []byte("0"), SessionId: pb.Int64(5)}},
+	}))
+}
@@ -219,6 +219,22 @@ func (m *mockGetNotificationsServer) Send(batch *proto.NotificationBatch) error
 	return nil
 }
 
+type mockExportRecordsServer struct {
+	mockBase
+	responses []*proto.ExportRecordsResponse
+}
+
+func newMockExportRecordsServer(ctx context.Context) *mockExportRecordsServer {
+	r := &mockExportRecordsServer{}
+	r.ctx = ctx
+	return r
+}
+
+func (m *mockExportRecordsServer) Send(res *proto.ExportRecordsResponse) error {
+	m.responses = append(m.responses, res)
+	return nil
+}
+
 func newMockSendSnapshotClientStream(ctx context.Context) *mockSendSnapshotClientStream {
 	r := &mockSendSnapshotClientStream{
 		requests: make(chan *proto.SnapshotChunk, 100),
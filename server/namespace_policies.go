@@ -0,0 +1,689 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"container/list"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+const (
+	// Bounds of the interval between the scans for the records that
+	// outlived the default TTL of the namespace
+	minTTLCheckInterval = 1 * time.Second
+	maxTTLCheckInterval = 1 * time.Minute
+
+	// Max number of expired records deleted by a single write
+	maxExpiredRecordsPerWrite = 100
+
+	// The limiters of the clients that didn't send any request for this
+	// long are discarded
+	clientLimiterIdleTime = 1 * time.Minute
+
+	// The principal of the quota that applies to the principals without
+	// their own
+	allPrincipals = "*"
+
+	// Max number of records whose last read is tracked, with a sliding TTL
+	maxTrackedAccesses = 100_000
+)
+
+// namespacePolicies holds the policies of the namespace of a shard, as
+// distributed by the coordinator, and enforces them on the requests.
+type namespacePolicies struct {
+	sync.RWMutex
+	policies     *proto.NamespacePolicies
+	writeLimiter *rate.Limiter
+	readLimiter  *rate.Limiter
+
+	// The limiters of each client, by its identity or else by its address,
+	// and of each principal with a quota
+	clientsLock       sync.Mutex
+	clientLimiters    map[string]*clientLimiter
+	principalLimiters map[string]*clientLimiter
+	lastClientsSweep  time.Time
+
+	// The last time the records were read, when the TTL is sliding, from
+	// the least recently read. The records read before this leader took
+	// over, or whose read was forgotten to stay within the max number of
+	// tracked reads, are considered as read at the time it did, or at the
+	// time of the last forgotten read.
+	accessLock    sync.Mutex
+	accessTimes   map[string]*list.Element
+	accessOrder   *list.List
+	accessesSince time.Time
+}
+
+type keyAccess struct {
+	key  string
+	time time.Time
+}
+
+type clientLimiter struct {
+	ops        *rate.Limiter
+	writeBytes *rate.Limiter
+	lastUsed   time.Time
+}
+
+func (p *namespacePolicies) update(policies *proto.NamespacePolicies) {
+	p.Lock()
+	defer p.Unlock()
+
+	if policies == nil {
+		policies = &proto.NamespacePolicies{}
+	}
+	p.policies = policies
+	p.writeLimiter = updateLimiter(p.writeLimiter, policies.MaxWritesPerSecond)
+	p.readLimiter = updateLimiter(p.readLimiter, policies.MaxReadsPerSecond)
+
+	// The clients start over with the new limits
+	p.clientsLock.Lock()
+	p.clientLimiters = nil
+	p.principalLimiters = nil
+	p.clientsLock.Unlock()
+}
+
+func (p *namespacePolicies) defaultTTL() time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.policies == nil {
+		return 0
+	}
+	return time.Duration(p.policies.DefaultTtlMs) * time.Millisecond
+}
+
+func (p *namespacePolicies) slidingTTL() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.policies != nil && p.policies.DefaultTtlMs > 0 && p.policies.SlidingTtl
+}
+
+// recordAccess resets the expiry of a record that was read, when the TTL of
+// the namespace is sliding.
+func (p *namespacePolicies) recordAccess(key string) {
+	if !p.slidingTTL() {
+		return
+	}
+
+	p.accessLock.Lock()
+	defer p.accessLock.Unlock()
+
+	if p.accessTimes == nil {
+		p.accessTimes = map[string]*list.Element{}
+		p.accessOrder = list.New()
+	}
+
+	now := time.Now()
+	if e, ok := p.accessTimes[key]; ok {
+		e.Value.(*keyAccess).time = now
+		p.accessOrder.MoveToBack(e)
+		return
+	}
+	p.accessTimes[key] = p.accessOrder.PushBack(&keyAccess{key: key, time: now})
+
+	// Forgetting the least recent read makes all the records look as read
+	// at that time, which can only delay their expiry
+	if len(p.accessTimes) > maxTrackedAccesses {
+		oldest := p.accessOrder.Remove(p.accessOrder.Front()).(*keyAccess)
+		delete(p.accessTimes, oldest.key)
+		if oldest.time.After(p.accessesSince) {
+			p.accessesSince = oldest.time
+		}
+	}
+}
+
+// resetAccesses forgets the access times, when the leadership of the shard
+// starts. The access times are only known by the leader that served the
+// reads, so each record gets a full TTL after a leader change.
+func (p *namespacePolicies) resetAccesses() {
+	p.accessLock.Lock()
+	defer p.accessLock.Unlock()
+
+	p.accessTimes = nil
+	p.accessOrder = nil
+	p.accessesSince = time.Now()
+}
+
+func (p *namespacePolicies) lastAccess(key string) time.Time {
+	p.accessLock.Lock()
+	defer p.accessLock.Unlock()
+
+	if e, ok := p.accessTimes[key]; ok && e.Value.(*keyAccess).time.After(p.accessesSince) {
+		return e.Value.(*keyAccess).time
+	}
+	return p.accessesSince
+}
+
+// pruneAccesses forgets the accesses that are too old to prevent the expiry
+// of any record.
+func (p *namespacePolicies) pruneAccesses(cutoff time.Time) {
+	p.accessLock.Lock()
+	defer p.accessLock.Unlock()
+
+	for p.accessOrder != nil && p.accessOrder.Len() > 0 {
+		oldest := p.accessOrder.Front().Value.(*keyAccess)
+		if !oldest.time.Before(cutoff) {
+			return
+		}
+		p.accessOrder.Remove(p.accessOrder.Front())
+		delete(p.accessTimes, oldest.key)
+	}
+}
+
+type permission int
+
+const (
+	permissionRead permission = iota
+	permissionWrite
+)
+
+// authorize rejects the requests of the principals that don't have the
+// permission on the namespace, either on all the keys or on a key prefix,
+// when its access is restricted. The keys of the principals that only have
+// permissions on key prefixes are checked with authorizeKey and
+// authorizeRange.
+func (p *namespacePolicies) authorize(principal string, authenticated bool, required permission) error {
+	return p.checkPermission(principal, authenticated, required, func(string) bool { return true })
+}
+
+// authorizeAllKeys rejects the requests of the principals that don't have
+// the permission on all the keys of the namespace, eg: for the notifications
+// that cover all the keys of the shard.
+func (p *namespacePolicies) authorizeAllKeys(principal string, authenticated bool, required permission) error {
+	return p.checkPermission(principal, authenticated, required, func(string) bool { return false })
+}
+
+func (p *namespacePolicies) authorizeKey(principal string, authenticated bool, required permission, key string) error {
+	return p.checkPermission(principal, authenticated, required, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// authorizeRange checks the permission on a range of keys. Since the key
+// prefixes end with `/`, all the keys between two keys with the same prefix
+// have the prefix as well.
+func (p *namespacePolicies) authorizeRange(principal string, authenticated bool, required permission, start string, end string) error {
+	return p.checkPermission(principal, authenticated, required, func(prefix string) bool {
+		return strings.HasPrefix(start, prefix) && strings.HasPrefix(end, prefix)
+	})
+}
+
+func (p *namespacePolicies) authorizeWrite(principal string, authenticated bool, request *proto.WriteRequest) error {
+	for _, put := range request.Puts {
+		if err := p.authorizeKey(principal, authenticated, permissionWrite, put.Key); err != nil {
+			return err
+		}
+	}
+	for _, del := range request.Deletes {
+		if err := p.authorizeKey(principal, authenticated, permissionWrite, del.Key); err != nil {
+			return err
+		}
+	}
+	for _, dr := range request.DeleteRanges {
+		if err := p.authorizeRange(principal, authenticated, permissionWrite, dr.StartInclusive, dr.EndExclusive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *namespacePolicies) checkPermission(principal string, authenticated bool, required permission,
+	matchesPrefix func(prefix string) bool) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.policies == nil || p.policies.Authorization == nil {
+		return nil
+	}
+	if !authenticated {
+		return common.ErrorPermissionDenied
+	}
+
+	authz := p.policies.Authorization
+	if hasPermission(authz.Principals[principal], required) {
+		return nil
+	}
+	for _, pp := range authz.PrincipalPatterns {
+		if hasPermission(pp.Permissions, required) && auth.MatchPrincipal(pp.Pattern, principal) {
+			return nil
+		}
+	}
+	for _, kp := range authz.KeyPrefixes {
+		if matchesPrincipal(kp.Principal, principal) && hasPermission(kp.Permissions, required) && matchesPrefix(kp.Prefix) {
+			return nil
+		}
+	}
+	return common.ErrorPermissionDenied
+}
+
+func matchesPrincipal(aclPrincipal string, principal string) bool {
+	if auth.IsPrincipalPattern(aclPrincipal) {
+		return auth.MatchPrincipal(aclPrincipal, principal)
+	}
+	return aclPrincipal == principal
+}
+
+func hasPermission(granted *proto.NamespacePermissions, required permission) bool {
+	switch {
+	case granted == nil:
+		return false
+	case granted.Admin:
+		return true
+	case required == permissionRead:
+		return granted.Read
+	default:
+		return granted.Write
+	}
+}
+
+// checkWrite rejects the requests with values larger than the max size, and
+// the ones in excess of the write rate of the namespace or of the client.
+func (p *namespacePolicies) checkWrite(client string, request *proto.WriteRequest) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.policies == nil {
+		return nil
+	}
+
+	if maxSize := p.policies.MaxValueSize; maxSize > 0 {
+		for _, put := range request.Puts {
+			if uint64(len(put.Value)) > maxSize {
+				return common.ErrorValueTooLarge
+			}
+		}
+	}
+
+	// The namespace tokens are only taken for the requests of the clients
+	// within their own share, so that a throttled client cannot use up the
+	// rate of the namespace
+	ops, bytes := writeRequestSize(request)
+	if err := p.checkClient(client, ops, bytes); err != nil {
+		return err
+	}
+	if !allow(p.writeLimiter, ops) {
+		return common.ErrorRateLimited
+	}
+	return nil
+}
+
+// writeRequestSize returns the number of operations of a write request, and
+// the number of bytes it writes, counting the keys and the values.
+func writeRequestSize(request *proto.WriteRequest) (ops int, bytes int) {
+	ops = len(request.Puts) + len(request.Deletes) + len(request.DeleteRanges)
+	for _, put := range request.Puts {
+		bytes += len(put.Key) + len(put.Value)
+	}
+	return ops, bytes
+}
+
+// tenant returns the policies of the tenant of the namespace, if any.
+func (p *namespacePolicies) tenant() *proto.TenantPolicies {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.policies == nil {
+		return nil
+	}
+	return p.policies.Tenant
+}
+
+// checkRead rejects the requests in excess of the read rate of the namespace
+// or of the client.
+func (p *namespacePolicies) checkRead(client string, ops int) error {
+	p.RLock()
+	defer p.RUnlock()
+
+	if err := p.checkClient(client, ops, 0); err != nil {
+		return err
+	}
+	if !allow(p.readLimiter, ops) {
+		return common.ErrorRateLimited
+	}
+	return nil
+}
+
+// checkClient throttles the clients that exceed their own share of the
+// shard, so that a single client in a tight loop cannot starve the others.
+// It must be called with the policies read lock held.
+func (p *namespacePolicies) checkClient(client string, ops int, writeBytes int) error {
+	if p.policies == nil || (p.policies.MaxClientOpsPerSecond <= 0 && p.policies.MaxClientWriteBytesPerSecond <= 0) {
+		return nil
+	}
+
+	p.clientsLock.Lock()
+	defer p.clientsLock.Unlock()
+
+	now := time.Now()
+	p.sweepClientLimiters(now)
+
+	l, ok := p.clientLimiters[client]
+	if !ok {
+		l = &clientLimiter{
+			ops:        updateLimiter(nil, p.policies.MaxClientOpsPerSecond),
+			writeBytes: updateLimiter(nil, p.policies.MaxClientWriteBytesPerSecond),
+		}
+		if p.clientLimiters == nil {
+			p.clientLimiters = map[string]*clientLimiter{}
+		}
+		p.clientLimiters[client] = l
+	}
+	l.lastUsed = now
+
+	if opsAllowed, bytesAllowed := allowBoth(l.ops, ops, l.writeBytes, writeBytes); !opsAllowed || !bytesAllowed {
+		return common.ErrorThrottled
+	}
+	return nil
+}
+
+func (p *namespacePolicies) sweepClientLimiters(now time.Time) {
+	if now.Sub(p.lastClientsSweep) < clientLimiterIdleTime {
+		return
+	}
+
+	p.lastClientsSweep = now
+	for _, limiters := range []map[string]*clientLimiter{p.clientLimiters, p.principalLimiters} {
+		for key, l := range limiters {
+			if now.Sub(l.lastUsed) > clientLimiterIdleTime {
+				delete(limiters, key)
+			}
+		}
+	}
+}
+
+// The names of the quotas of the principals, as reported in the metrics
+const (
+	quotaOps        = "ops"
+	quotaWriteBytes = "write-bytes"
+	quotaStorage    = "storage"
+)
+
+// principalQuota returns the quota of the principal, or the one of all the
+// principals, if any.
+// It must be called with the policies read lock held.
+func (p *namespacePolicies) principalQuota(principal string) *proto.PrincipalQuota {
+	if p.policies == nil || len(p.policies.PrincipalQuotas) == 0 {
+		return nil
+	}
+	if q, ok := p.policies.PrincipalQuotas[principal]; ok {
+		return q
+	}
+	return p.policies.PrincipalQuotas[allPrincipals]
+}
+
+// checkPrincipalWrite rejects the writes in excess of the quota of the
+// principal. The storage quota only rejects the requests with puts, so that
+// the principals over quota can still free some space. The storage used by
+// the writes in progress is not accounted yet, so a principal can exceed its
+// quota by the size of a few requests.
+func (p *namespacePolicies) checkPrincipalWrite(principal string, request *proto.WriteRequest,
+	storageUsage func(principal string) (int64, error)) (quota string, err error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	q := p.principalQuota(principal)
+	if q == nil {
+		return "", nil
+	}
+
+	ops, bytes := writeRequestSize(request)
+	if quota, err = p.checkPrincipal(principal, q, ops, bytes); err != nil {
+		return quota, err
+	}
+
+	if q.MaxStorageBytes > 0 && len(request.Puts) > 0 {
+		usage, err := storageUsage(principal)
+		if err != nil {
+			return "", err
+		}
+		if usage+int64(bytes) > int64(q.MaxStorageBytes) {
+			return quotaStorage, common.ErrorQuotaExceeded
+		}
+	}
+	return "", nil
+}
+
+// checkPrincipalRead rejects the reads in excess of the quota of the
+// principal.
+func (p *namespacePolicies) checkPrincipalRead(principal string, ops int) (quota string, err error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	q := p.principalQuota(principal)
+	if q == nil {
+		return "", nil
+	}
+	return p.checkPrincipal(principal, q, ops, 0)
+}
+
+// It must be called with the policies read lock held.
+func (p *namespacePolicies) checkPrincipal(principal string, q *proto.PrincipalQuota, ops int, writeBytes int) (quota string, err error) {
+	if q.OpsPerSecond <= 0 && q.WriteBytesPerSecond <= 0 {
+		return "", nil
+	}
+
+	p.clientsLock.Lock()
+	defer p.clientsLock.Unlock()
+
+	now := time.Now()
+	p.sweepClientLimiters(now)
+
+	l, ok := p.principalLimiters[principal]
+	if !ok {
+		l = &clientLimiter{
+			ops:        updateLimiter(nil, q.OpsPerSecond),
+			writeBytes: updateLimiter(nil, q.WriteBytesPerSecond),
+		}
+		if p.principalLimiters == nil {
+			p.principalLimiters = map[string]*clientLimiter{}
+		}
+		p.principalLimiters[principal] = l
+	}
+	l.lastUsed = now
+
+	opsAllowed, bytesAllowed := allowBoth(l.ops, ops, l.writeBytes, writeBytes)
+	switch {
+	case !opsAllowed:
+		return quotaOps, common.ErrorQuotaExceeded
+	case !bytesAllowed:
+		return quotaWriteBytes, common.ErrorQuotaExceeded
+	}
+	return "", nil
+}
+
+func updateLimiter(l *rate.Limiter, perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	limit := rate.Limit(perSecond)
+	burst := int(math.Ceil(perSecond))
+	if l == nil {
+		return rate.NewLimiter(limit, burst)
+	}
+
+	if l.Limit() != limit || l.Burst() != burst {
+		l.SetLimit(limit)
+		l.SetBurst(burst)
+	}
+	return l
+}
+
+func allow(l *rate.Limiter, ops int) bool {
+	_, ok := reserve(l, time.Now(), ops)
+	return ok
+}
+
+// reserve takes the tokens from the limiter, if they are available now. The
+// returned reservation, if any, can be canceled to give the tokens back.
+func reserve(l *rate.Limiter, now time.Time, n int) (*rate.Reservation, bool) {
+	if l == nil || n == 0 {
+		return nil, true
+	}
+
+	// The requests with more operations than the burst are admitted once
+	// the limiter is full, otherwise they would never be
+	r := l.ReserveN(now, min(n, l.Burst()))
+	if !r.OK() {
+		return nil, false
+	}
+	if r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		return nil, false
+	}
+	return r, true
+}
+
+// allowBoth takes the tokens from both the limiters of the operations and
+// of the bytes, or from none of them, so that a request rejected by one of
+// the limiters doesn't use up the other.
+func allowBoth(ops *rate.Limiter, n int, bytes *rate.Limiter, size int) (opsAllowed bool, bytesAllowed bool) {
+	now := time.Now()
+	r, ok := reserve(ops, now, n)
+	if !ok {
+		return false, true
+	}
+	if _, ok := reserve(bytes, now, size); !ok {
+		if r != nil {
+			r.CancelAt(now)
+		}
+		return true, false
+	}
+	return true, true
+}
+
+func ttlCheckInterval(ttl time.Duration) time.Duration {
+	interval := ttl / 10
+	if interval < minTTLCheckInterval {
+		interval = minTTLCheckInterval
+	}
+	if interval > maxTTLCheckInterval || ttl == 0 {
+		interval = maxTTLCheckInterval
+	}
+	return interval
+}
+
+// Periodically delete the records that were not modified for longer than
+// the default TTL of the namespace. The deletions are replicated like any
+// other write, and they are notified to the clients.
+func (lc *leaderController) expireRecords() {
+	timer := time.NewTimer(minTTLCheckInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-lc.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		ttl := lc.policies.defaultTTL()
+		if ttl > 0 && lc.Status() == proto.ServingStatus_LEADER {
+			if err := lc.deleteExpiredRecords(ttl); err != nil {
+				lc.log.Warn(
+					"Failed to delete the expired records",
+					slog.Any("error", err),
+				)
+			}
+		}
+
+		timer.Reset(ttlCheckInterval(ttl))
+	}
+}
+
+func (lc *leaderController) deleteExpiredRecords(ttl time.Duration) error {
+	cutoffTime := time.Now().Add(-ttl)
+	cutoff := uint64(cutoffTime.UnixMilli())
+
+	sliding := lc.policies.slidingTTL()
+	if sliding {
+		defer lc.policies.pruneAccesses(cutoffTime)
+	}
+
+	// Only the records that were not modified since the cutoff are read,
+	// from the modification index of the shard
+	it, err := lc.db.ModifiedBefore(cutoff + 1)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var expired []*proto.DeleteRequest
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		gr, err := lc.db.Get(&proto.GetRequest{Key: key})
+		if err != nil {
+			return err
+		}
+
+		// The ephemeral records are bound to their session
+		if gr.Status != proto.Status_OK || gr.Version.SessionId != nil || gr.Version.ModifiedTimestamp > cutoff {
+			continue
+		}
+
+		// With a sliding TTL, the records that are read are kept as well
+		if sliding && lc.policies.lastAccess(key).After(cutoffTime) {
+			continue
+		}
+
+		// The record is only deleted if it wasn't modified in the meantime
+		expired = append(expired, &proto.DeleteRequest{
+			Key:               key,
+			ExpectedVersionId: &gr.Version.VersionId,
+		})
+		if len(expired) == maxExpiredRecordsPerWrite {
+			if err = lc.deleteRecords(expired); err != nil {
+				return err
+			}
+			expired = nil
+		}
+	}
+
+	return lc.deleteRecords(expired)
+}
+
+func (lc *leaderController) deleteRecords(deletes []*proto.DeleteRequest) error {
+	if len(deletes) == 0 {
+		return nil
+	}
+
+	if _, _, err := lc.write(lc.ctx, func(_ int64) *proto.WriteRequest {
+		return &proto.WriteRequest{
+			ShardId: &lc.shardId,
+			Deletes: deletes,
+		}
+	}); err != nil {
+		return err
+	}
+
+	lc.expiredRecordsCounter.Add(len(deletes))
+	lc.log.Debug(
+		"Deleted expired records",
+		slog.Int("count", len(deletes)),
+	)
+	return nil
+}
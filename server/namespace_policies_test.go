@@ -0,0 +1,330 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+func TestNamespacePolicies(t *testing.T) {
+	p := &namespacePolicies{}
+
+	// No policy is set
+	assert.NoError(t, p.checkWrite("client-1", &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 1024)}}}))
+	assert.NoError(t, p.checkRead("client-1", 100))
+	assert.Zero(t, p.defaultTTL())
+
+	p.update(&proto.NamespacePolicies{
+		DefaultTtlMs:      60_000,
+		MaxValueSize:      10,
+		MaxReadsPerSecond: 2,
+	})
+	assert.Equal(t, 1*time.Minute, p.defaultTTL())
+
+	assert.ErrorIs(t, p.checkWrite("client-1", &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 11)}}}),
+		common.ErrorValueTooLarge)
+	assert.NoError(t, p.checkWrite("client-1", &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, 10)}}}))
+
+	// A request with more operations than the burst is still admitted
+	assert.NoError(t, p.checkRead("client-1", 5))
+	assert.ErrorIs(t, p.checkRead("client-1", 1), common.ErrorRateLimited)
+}
+
+func TestNamespacePolicies_ClientLimits(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{
+		MaxClientOpsPerSecond:        2,
+		MaxClientWriteBytesPerSecond: 100,
+	})
+
+	put := func(size int) *proto.WriteRequest {
+		return &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, size-1)}}}
+	}
+
+	assert.NoError(t, p.checkRead("client-1", 1))
+	assert.NoError(t, p.checkWrite("client-1", put(10)))
+	assert.ErrorIs(t, p.checkRead("client-1", 1), common.ErrorThrottled)
+
+	// Each client has its own limits
+	assert.NoError(t, p.checkWrite("client-2", put(100)))
+	assert.ErrorIs(t, p.checkWrite("client-2", put(10)), common.ErrorThrottled)
+
+	// The operations of the requests rejected for their size are not counted
+	assert.NoError(t, p.checkRead("client-2", 1))
+
+	// The clients start over when the policies are changed
+	p.update(&proto.NamespacePolicies{MaxClientOpsPerSecond: 1})
+	assert.NoError(t, p.checkWrite("client-2", put(1000)))
+	assert.NotContains(t, p.clientLimiters, "client-1")
+
+	// The idle clients are discarded
+	p.lastClientsSweep = time.Time{}
+	p.clientLimiters["client-2"].lastUsed = time.Now().Add(-2 * clientLimiterIdleTime)
+	assert.NoError(t, p.checkRead("client-1", 1))
+	assert.NotContains(t, p.clientLimiters, "client-2")
+}
+
+func TestNamespacePolicies_ThrottledClientsDontUseNamespaceRate(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{
+		MaxReadsPerSecond:     3,
+		MaxClientOpsPerSecond: 1,
+	})
+
+	put := &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a"}}}
+
+	// The requests rejected for the client don't take the namespace tokens
+	assert.NoError(t, p.checkRead("client-1", 1))
+	for i := 0; i < 10; i++ {
+		assert.ErrorIs(t, p.checkRead("client-1", 1), common.ErrorThrottled)
+	}
+	assert.NoError(t, p.checkRead("client-2", 1))
+	assert.NoError(t, p.checkRead("client-3", 1))
+
+	assert.ErrorIs(t, p.checkRead("client-4", 1), common.ErrorRateLimited)
+
+	p = &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{
+		MaxWritesPerSecond:    3,
+		MaxClientOpsPerSecond: 1,
+	})
+	assert.NoError(t, p.checkWrite("client-1", put))
+	for i := 0; i < 10; i++ {
+		assert.ErrorIs(t, p.checkWrite("client-1", put), common.ErrorThrottled)
+	}
+	assert.NoError(t, p.checkWrite("client-2", put))
+	assert.NoError(t, p.checkWrite("client-3", put))
+	assert.ErrorIs(t, p.checkWrite("client-4", put), common.ErrorRateLimited)
+}
+
+func TestNamespacePolicies_SlidingTTL(t *testing.T) {
+	p := &namespacePolicies{}
+	p.resetAccesses()
+	since := p.lastAccess("a")
+
+	// The accesses are only tracked with a sliding TTL
+	p.update(&proto.NamespacePolicies{DefaultTtlMs: 60_000})
+	p.recordAccess("a")
+	assert.Equal(t, since, p.lastAccess("a"))
+
+	p.update(&proto.NamespacePolicies{DefaultTtlMs: 60_000, SlidingTtl: true})
+	p.recordAccess("a")
+	accessed := p.lastAccess("a")
+	assert.True(t, accessed.After(since))
+	assert.Equal(t, since, p.lastAccess("b"))
+
+	p.pruneAccesses(since)
+	assert.Equal(t, accessed, p.lastAccess("a"))
+	p.pruneAccesses(time.Now().Add(time.Second))
+	assert.Empty(t, p.accessTimes)
+
+	// A new leader starts over
+	p.recordAccess("a")
+	p.resetAccesses()
+	assert.Empty(t, p.accessTimes)
+	assert.False(t, p.lastAccess("a").Before(accessed))
+}
+
+func TestNamespacePolicies_MaxTrackedAccesses(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{DefaultTtlMs: 60_000, SlidingTtl: true})
+	p.resetAccesses()
+
+	p.recordAccess("first")
+	first := p.lastAccess("first")
+	for i := 0; i < maxTrackedAccesses; i++ {
+		p.recordAccess(fmt.Sprintf("key-%d", i))
+	}
+
+	// The least recent read is forgotten, and all the records are then
+	// considered as read at that time
+	assert.Len(t, p.accessTimes, maxTrackedAccesses)
+	assert.NotContains(t, p.accessTimes, "first")
+	assert.Equal(t, first, p.lastAccess("first"))
+	assert.Equal(t, first, p.lastAccess("other"))
+
+	// A record read again becomes the most recent one
+	p.recordAccess("key-0")
+	p.recordAccess("next")
+	assert.Contains(t, p.accessTimes, "key-0")
+	assert.NotContains(t, p.accessTimes, "key-1")
+
+	p.pruneAccesses(time.Now().Add(time.Second))
+	assert.Empty(t, p.accessTimes)
+	assert.Zero(t, p.accessOrder.Len())
+}
+
+func TestNamespacePolicies_Authorization(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(nil)
+
+	// The access is not restricted by default
+	assert.NoError(t, p.authorize("", false, permissionWrite))
+
+	p.update(&proto.NamespacePolicies{
+		Authorization: &proto.NamespaceAuthorization{
+			Principals: map[string]*proto.NamespacePermissions{
+				"reader": {Read: true},
+				"writer": {Write: true},
+				"admin":  {Admin: true},
+			},
+		},
+	})
+
+	assert.NoError(t, p.authorize("reader", true, permissionRead))
+	assert.ErrorIs(t, p.authorize("reader", true, permissionWrite), common.ErrorPermissionDenied)
+	assert.NoError(t, p.authorize("writer", true, permissionWrite))
+	assert.ErrorIs(t, p.authorize("writer", true, permissionRead), common.ErrorPermissionDenied)
+	assert.NoError(t, p.authorize("admin", true, permissionRead))
+	assert.NoError(t, p.authorize("admin", true, permissionWrite))
+	assert.ErrorIs(t, p.authorize("other", true, permissionRead), common.ErrorPermissionDenied)
+
+	// The requests that are not authenticated are rejected
+	assert.ErrorIs(t, p.authorize("", false, permissionRead), common.ErrorPermissionDenied)
+}
+
+func TestNamespacePolicies_PrincipalPatterns(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{
+		Authorization: &proto.NamespaceAuthorization{
+			PrincipalPatterns: []*proto.PrincipalPatternPermissions{
+				{Pattern: "spiffe://example.org/ns/team-a/*", Permissions: &proto.NamespacePermissions{Write: true}},
+			},
+			KeyPrefixes: []*proto.KeyPrefixPermissions{
+				{Principal: "spiffe://example.org/ns/*/reader", Prefix: "/configs/", Permissions: &proto.NamespacePermissions{Read: true}},
+			},
+		},
+	})
+
+	assert.NoError(t, p.authorize("spiffe://example.org/ns/team-a/writer", true, permissionWrite))
+	assert.ErrorIs(t, p.authorize("spiffe://example.org/ns/team-a/writer", true, permissionRead), common.ErrorPermissionDenied)
+	assert.ErrorIs(t, p.authorize("spiffe://example.org/ns/team-b/writer", true, permissionWrite), common.ErrorPermissionDenied)
+	assert.ErrorIs(t, p.authorize("spiffe://example.org/ns/team-a/sa/writer", true, permissionWrite), common.ErrorPermissionDenied)
+
+	assert.NoError(t, p.authorizeKey("spiffe://example.org/ns/team-b/reader", true, permissionRead, "/configs/x"))
+	assert.ErrorIs(t, p.authorizeKey("spiffe://example.org/ns/team-b/reader", true, permissionRead, "/other/x"), common.ErrorPermissionDenied)
+}
+
+func TestNamespacePolicies_KeyPrefixAuthorization(t *testing.T) {
+	p := &namespacePolicies{}
+	p.update(&proto.NamespacePolicies{
+		Authorization: &proto.NamespaceAuthorization{
+			Principals: map[string]*proto.NamespacePermissions{
+				"reader": {Read: true},
+			},
+			KeyPrefixes: []*proto.KeyPrefixPermissions{
+				{Principal: "team-a", Prefix: "/configs/a/", Permissions: &proto.NamespacePermissions{Write: true}},
+				{Principal: "team-a", Prefix: "/configs/shared/", Permissions: &proto.NamespacePermissions{Read: true}},
+				{Principal: "reader", Prefix: "/configs/b/", Permissions: &proto.NamespacePermissions{Admin: true}},
+			},
+		},
+	})
+
+	// The principals with permissions on a prefix can reach the shard leader
+	assert.NoError(t, p.authorize("team-a", true, permissionWrite))
+	assert.ErrorIs(t, p.authorize("team-b", true, permissionRead), common.ErrorPermissionDenied)
+
+	assert.NoError(t, p.authorizeKey("team-a", true, permissionWrite, "/configs/a/x"))
+	assert.ErrorIs(t, p.authorizeKey("team-a", true, permissionRead, "/configs/a/x"), common.ErrorPermissionDenied)
+	assert.NoError(t, p.authorizeKey("team-a", true, permissionRead, "/configs/shared/x"))
+	assert.ErrorIs(t, p.authorizeKey("team-a", true, permissionWrite, "/configs/shared/x"), common.ErrorPermissionDenied)
+	assert.ErrorIs(t, p.authorizeKey("team-a", true, permissionWrite, "/configs/b/x"), common.ErrorPermissionDenied)
+
+	// The permissions on all the keys and on the prefixes are combined
+	assert.NoError(t, p.authorizeKey("reader", true, permissionRead, "/configs/a/x"))
+	assert.NoError(t, p.authorizeKey("reader", true, permissionWrite, "/configs/b/x"))
+	assert.ErrorIs(t, p.authorizeKey("reader", true, permissionWrite, "/configs/a/x"), common.ErrorPermissionDenied)
+
+	// The ranges must be within a prefix
+	assert.NoError(t, p.authorizeRange("team-a", true, permissionWrite, "/configs/a/", "/configs/a/~"))
+	assert.ErrorIs(t, p.authorizeRange("team-a", true, permissionWrite, "/configs/a/", "/configs/b/"), common.ErrorPermissionDenied)
+
+	assert.NoError(t, p.authorizeWrite("team-a", true, &proto.WriteRequest{
+		Puts:    []*proto.PutRequest{{Key: "/configs/a/x"}},
+		Deletes: []*proto.DeleteRequest{{Key: "/configs/a/y"}},
+	}))
+	assert.ErrorIs(t, p.authorizeWrite("team-a", true, &proto.WriteRequest{
+		Puts:    []*proto.PutRequest{{Key: "/configs/a/x"}},
+		Deletes: []*proto.DeleteRequest{{Key: "/configs/shared/y"}},
+	}), common.ErrorPermissionDenied)
+
+	// The notifications require the permission on all the keys
+	assert.NoError(t, p.authorizeAllKeys("reader", true, permissionRead))
+	assert.ErrorIs(t, p.authorizeAllKeys("team-a", true, permissionRead), common.ErrorPermissionDenied)
+}
+
+func TestTTLCheckInterval(t *testing.T) {
+	assert.Equal(t, maxTTLCheckInterval, ttlCheckInterval(0))
+	assert.Equal(t, minTTLCheckInterval, ttlCheckInterval(1*time.Second))
+	assert.Equal(t, 30*time.Second, ttlCheckInterval(5*time.Minute))
+	assert.Equal(t, maxTTLCheckInterval, ttlCheckInterval(24*time.Hour))
+}
+
+func TestNamespacePolicies_PrincipalQuotas(t *testing.T) {
+	p := &namespacePolicies{}
+	usage := map[string]int64{}
+	storageUsage := func(principal string) (int64, error) {
+		return usage[principal], nil
+	}
+	put := func(size int) *proto.WriteRequest {
+		return &proto.WriteRequest{Puts: []*proto.PutRequest{{Key: "a", Value: make([]byte, size-1)}}}
+	}
+
+	// No quota is set
+	_, err := p.checkPrincipalWrite("user-1", put(1000), storageUsage)
+	assert.NoError(t, err)
+
+	p.update(&proto.NamespacePolicies{PrincipalQuotas: map[string]*proto.PrincipalQuota{
+		"*":      {OpsPerSecond: 2},
+		"user-1": {WriteBytesPerSecond: 100, MaxStorageBytes: 1000},
+	}})
+
+	// The principals without their own quota share the default one
+	_, err = p.checkPrincipalRead("user-2", 1)
+	assert.NoError(t, err)
+	_, err = p.checkPrincipalWrite("user-2", put(10), storageUsage)
+	assert.NoError(t, err)
+	quota, err := p.checkPrincipalRead("user-2", 1)
+	assert.ErrorIs(t, err, common.ErrorQuotaExceeded)
+	assert.Equal(t, quotaOps, quota)
+	_, err = p.checkPrincipalRead("user-3", 1)
+	assert.NoError(t, err)
+
+	_, err = p.checkPrincipalWrite("user-1", put(100), storageUsage)
+	assert.NoError(t, err)
+	quota, err = p.checkPrincipalWrite("user-1", put(10), storageUsage)
+	assert.ErrorIs(t, err, common.ErrorQuotaExceeded)
+	assert.Equal(t, quotaWriteBytes, quota)
+
+	// The storage quota only rejects the puts
+	p.update(&proto.NamespacePolicies{PrincipalQuotas: map[string]*proto.PrincipalQuota{
+		"user-1": {MaxStorageBytes: 1000},
+	}})
+	usage["user-1"] = 995
+	_, err = p.checkPrincipalWrite("user-1", put(5), storageUsage)
+	assert.NoError(t, err)
+	quota, err = p.checkPrincipalWrite("user-1", put(6), storageUsage)
+	assert.ErrorIs(t, err, common.ErrorQuotaExceeded)
+	assert.Equal(t, quotaStorage, quota)
+	_, err = p.checkPrincipalWrite("user-1", &proto.WriteRequest{Deletes: []*proto.DeleteRequest{{Key: "a"}}}, storageUsage)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
+)
+
+const notificationsConsumerKeyPrefix = common.InternalKeyPrefix + "notifications-consumer/"
+
+func notificationsConsumerKey(name string) string {
+	return notificationsConsumerKeyPrefix + name
+}
+
+// AckNotifications stores the offset of the last notification processed by a
+// durable consumer. The offset is written as an internal record of the shard,
+// so that it's replicated and survives the leader changes. The acknowledged
+// offset never moves backward.
+func (lc *leaderController) AckNotifications(ctx context.Context, req *proto.AckNotificationsRequest) (*proto.AckNotificationsResponse, error) {
+	if req.ConsumerName == "" {
+		return nil, status.Error(codes.InvalidArgument, "oxia: missing consumer name")
+	}
+
+	if err := checkStatusIsLeader(lc.Status()); err != nil {
+		return nil, err
+	}
+
+	principal, authenticated := auth.GetPrincipal(ctx)
+	if err := lc.checkPolicy(lc.policies.authorizeAllKeys(principal, authenticated, permissionRead)); err != nil {
+		return nil, err
+	}
+
+	acked, found, err := lc.notificationsConsumerOffset(req.ConsumerName)
+	if err != nil {
+		return nil, err
+	}
+	if found && acked >= req.Offset {
+		return &proto.AckNotificationsResponse{}, nil
+	}
+
+	if _, _, err = lc.write(ctx, func(_ int64) *proto.WriteRequest {
+		return &proto.WriteRequest{
+			ShardId: &lc.shardId,
+			Puts: []*proto.PutRequest{{
+				Key:   notificationsConsumerKey(req.ConsumerName),
+				Value: []byte(strconv.FormatInt(req.Offset, 10)),
+			}},
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	lc.log.Debug(
+		"Acknowledged notifications",
+		slog.String("consumer", req.ConsumerName),
+		slog.Int64("offset", req.Offset),
+	)
+	return &proto.AckNotificationsResponse{}, nil
+}
+
+func (lc *leaderController) notificationsConsumerOffset(name string) (offset int64, found bool, err error) {
+	gr, err := lc.db.Get(&proto.GetRequest{
+		Key:          notificationsConsumerKey(name),
+		IncludeValue: true,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if gr.Status != proto.Status_OK {
+		return 0, false, nil
+	}
+
+	if offset, err = strconv.ParseInt(string(gr.Value), 10, 64); err != nil {
+		return 0, false, errors.Wrapf(err, "invalid offset for notifications consumer %q", name)
+	}
+	return offset, true, nil
+}
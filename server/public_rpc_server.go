@@ -31,6 +31,7 @@ import (
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/container"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/proto"
 )
 
@@ -50,7 +51,7 @@ type publicRpcServer struct {
 }
 
 func newPublicRpcServer(provider container.GrpcProvider, bindAddress string, shardsDirector ShardsDirector, assignmentDispatcher ShardAssignmentsDispatcher,
-	tlsConf *tls.Config, options *auth.Options) (*publicRpcServer, error) {
+	tlsConf *tls.Config, ipFilter *security.IPFilter, options *auth.Options) (*publicRpcServer, error) {
 	server := &publicRpcServer{
 		shardsDirector:       shardsDirector,
 		assignmentDispatcher: assignmentDispatcher,
@@ -60,9 +61,9 @@ func newPublicRpcServer(provider container.GrpcProvider, bindAddress string, sha
 	}
 
 	var err error
-	server.grpcServer, err = provider.StartGrpcServer("public", bindAddress, func(registrar grpc.ServiceRegistrar) {
+	server.grpcServer, err = provider.StartGrpcServer(container.ServerPublic, bindAddress, func(registrar grpc.ServiceRegistrar) {
 		proto.RegisterOxiaClientServer(registrar, server)
-	}, tlsConf, options)
+	}, tlsConf, ipFilter, options)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +95,7 @@ func (s *publicRpcServer) Write(ctx context.Context, write *proto.WriteRequest)
 		slog.Any("req", write),
 	)
 
-	lc, err := s.getLeader(*write.ShardId)
+	lc, err := s.getLeader(ctx, *write.ShardId, permissionWrite)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +137,7 @@ func (s *publicRpcServer) WriteStream(stream proto.OxiaClient_WriteStreamServer)
 
 	log.Debug("Write Stream request")
 
-	lc, err := s.getLeader(shardId)
+	lc, err := s.getLeader(stream.Context(), shardId, permissionWrite)
 	if err != nil {
 		return err
 	}
@@ -161,7 +162,7 @@ func (s *publicRpcServer) Read(request *proto.ReadRequest, stream proto.OxiaClie
 		slog.Any("req", request),
 	)
 
-	lc, err := s.getLeader(*request.ShardId)
+	lc, err := s.getLeader(stream.Context(), *request.ShardId, permissionRead)
 	if err != nil {
 		return err
 	}
@@ -209,7 +210,7 @@ func (s *publicRpcServer) List(request *proto.ListRequest, stream proto.OxiaClie
 		slog.Any("req", request),
 	)
 
-	lc, err := s.getLeader(*request.ShardId)
+	lc, err := s.getLeader(stream.Context(), *request.ShardId, permissionRead)
 	if err != nil {
 		return err
 	}
@@ -260,7 +261,7 @@ func (s *publicRpcServer) RangeScan(request *proto.RangeScanRequest, stream prot
 		slog.Any("req", request),
 	)
 
-	lc, err := s.getLeader(*request.ShardId)
+	lc, err := s.getLeader(stream.Context(), *request.ShardId, permissionRead)
 	if err != nil {
 		return err
 	}
@@ -317,7 +318,7 @@ func (s *publicRpcServer) GetNotifications(req *proto.NotificationsRequest, stre
 		slog.Any("req", req),
 	)
 
-	lc, err := s.getLeader(req.ShardId)
+	lc, err := s.getLeader(stream.Context(), req.ShardId, permissionRead)
 	if err != nil {
 		return err
 	}
@@ -332,6 +333,29 @@ func (s *publicRpcServer) GetNotifications(req *proto.NotificationsRequest, stre
 	return err
 }
 
+func (s *publicRpcServer) AckNotifications(ctx context.Context, req *proto.AckNotificationsRequest) (*proto.AckNotificationsResponse, error) {
+	s.log.Debug(
+		"Ack notifications",
+		slog.String("peer", common.GetPeer(ctx)),
+		slog.Any("req", req),
+	)
+
+	lc, err := s.getLeader(ctx, req.ShardId, permissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := lc.AckNotifications(ctx, req)
+	if err != nil {
+		s.log.Warn(
+			"Failed to ack notifications",
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *publicRpcServer) Port() int {
 	return s.grpcServer.Port()
 }
@@ -342,11 +366,11 @@ func (s *publicRpcServer) CreateSession(ctx context.Context, req *proto.CreateSe
 		slog.String("peer", common.GetPeer(ctx)),
 		slog.Any("req", req),
 	)
-	lc, err := s.getLeader(req.ShardId)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
 	if err != nil {
 		return nil, err
 	}
-	res, err := lc.CreateSession(req)
+	res, err := lc.CreateSession(ctx, req)
 	if err != nil {
 		s.log.Warn(
 			"Failed to create session",
@@ -364,11 +388,11 @@ func (s *publicRpcServer) KeepAlive(ctx context.Context, req *proto.SessionHeart
 		slog.Int64("session", req.SessionId),
 		slog.String("peer", common.GetPeer(ctx)),
 	)
-	lc, err := s.getLeader(req.ShardId)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
 	if err != nil {
 		return nil, err
 	}
-	err = lc.KeepAlive(req.SessionId)
+	err = lc.KeepAlive(ctx, req.SessionId)
 	if err != nil {
 		s.log.Warn(
 			"Failed to listen to heartbeats",
@@ -385,11 +409,11 @@ func (s *publicRpcServer) CloseSession(ctx context.Context, req *proto.CloseSess
 		slog.String("peer", common.GetPeer(ctx)),
 		slog.Any("req", req),
 	)
-	lc, err := s.getLeader(req.ShardId)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
 	if err != nil {
 		return nil, err
 	}
-	res, err := lc.CloseSession(req)
+	res, err := lc.CloseSession(ctx, req)
 	if err != nil {
 		s.log.Warn(
 			"Failed to close session",
@@ -400,7 +424,64 @@ func (s *publicRpcServer) CloseSession(ctx context.Context, req *proto.CloseSess
 	return res, nil
 }
 
-func (s *publicRpcServer) getLeader(shardId int64) (LeaderController, error) {
+func (s *publicRpcServer) GrantLease(ctx context.Context, req *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error) {
+	s.log.Debug(
+		"Grant lease request",
+		slog.String("peer", common.GetPeer(ctx)),
+		slog.Any("req", req),
+	)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	res, err := lc.GrantLease(ctx, req)
+	if err != nil {
+		s.log.Warn(
+			"Failed to grant lease",
+			slog.String("lease", req.Name),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *publicRpcServer) RefreshLease(ctx context.Context, req *proto.LeaseRequest) (*proto.RefreshLeaseResponse, error) {
+	s.log.Debug(
+		"Refresh lease request",
+		slog.Int64("shard", req.ShardId),
+		slog.String("lease", req.Name),
+		slog.String("peer", common.GetPeer(ctx)),
+	)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err = lc.RefreshLease(ctx, req); err != nil {
+		return nil, err
+	}
+	return &proto.RefreshLeaseResponse{}, nil
+}
+
+func (s *publicRpcServer) RevokeLease(ctx context.Context, req *proto.LeaseRequest) (*proto.RevokeLeaseResponse, error) {
+	s.log.Debug(
+		"Revoke lease request",
+		slog.String("peer", common.GetPeer(ctx)),
+		slog.Any("req", req),
+	)
+	lc, err := s.getLeader(ctx, req.ShardId, permissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err = lc.RevokeLease(ctx, req); err != nil {
+		return nil, err
+	}
+	return &proto.RevokeLeaseResponse{}, nil
+}
+
+// getLeader returns the leader controller of the shard, if the principal of
+// the request has the permission on its namespace.
+func (s *publicRpcServer) getLeader(ctx context.Context, shardId int64, required permission) (LeaderController, error) {
 	lc, err := s.shardsDirector.GetLeader(shardId)
 	if err != nil {
 		if status.Code(err) != common.CodeNodeIsNotLeader {
@@ -411,6 +492,9 @@ func (s *publicRpcServer) getLeader(shardId int64) (LeaderController, error) {
 		}
 		return nil, err
 	}
+	if err = lc.Authorize(ctx, required); err != nil {
+		return nil, err
+	}
 	return lc, nil
 }
 
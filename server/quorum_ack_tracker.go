@@ -85,19 +85,33 @@ type quorumAckTracker struct {
 }
 
 type CursorAcker interface {
+	// Ack marks all the entries up to the offset (inclusive) as acknowledged
+	// by the cursor
 	Ack(offset int64)
 }
 
 type cursorAcker struct {
 	quorumTracker *quorumAckTracker
 	cursorIdx     int
+	ackOffset     int64
 }
 
 func NewQuorumAckTracker(replicationFactor uint32, headOffset int64, commitOffset int64) QuorumAckTracker {
+	// Ack quorum is number of follower acks that are required to consider the entry fully committed
+	// We are using RF/2 (and not RF/2 + 1) because the leader is already storing 1 copy locally
+	return newQuorumAckTracker(replicationFactor, replicationFactor/2, headOffset, commitOffset)
+}
+
+// NewRelaxedQuorumAckTracker creates a tracker that doesn't wait for any follower ack: the entries
+// are considered committed as soon as they are written in the local WAL of the leader, including
+// all the entries that are already there.
+func NewRelaxedQuorumAckTracker(replicationFactor uint32, headOffset int64) QuorumAckTracker {
+	return newQuorumAckTracker(replicationFactor, 0, headOffset, headOffset)
+}
+
+func newQuorumAckTracker(replicationFactor uint32, requiredAcks uint32, headOffset int64, commitOffset int64) QuorumAckTracker {
 	q := &quorumAckTracker{
-		// Ack quorum is number of follower acks that are required to consider the entry fully committed
-		// We are using RF/2 (and not RF/2 + 1) because the leader is already storing 1 copy locally
-		requiredAcks:      replicationFactor / 2,
+		requiredAcks:      requiredAcks,
 		replicationFactor: replicationFactor,
 		tracker:           make(map[int64]*util.BitSet),
 	}
@@ -206,13 +220,12 @@ func (q *quorumAckTracker) NewCursorAcker(ackOffset int64) (CursorAcker, error)
 	qa := &cursorAcker{
 		quorumTracker: q,
 		cursorIdx:     q.cursorIdxGenerator,
+		ackOffset:     q.commitOffset.Load(),
 	}
 
 	// If the new cursor is already past the current quorum commit offset, we have
 	// to mark these entries as acked (by that cursor).
-	for offset := q.commitOffset.Load() + 1; offset <= ackOffset; offset++ {
-		qa.ack(offset)
-	}
+	qa.ack(ackOffset)
 
 	q.cursorIdxGenerator++
 	return qa, nil
@@ -226,6 +239,14 @@ func (c *cursorAcker) Ack(offset int64) {
 }
 
 func (c *cursorAcker) ack(offset int64) {
+	// Acks are cumulative, the follower might be acking
+	// multiple entries at once
+	for ; c.ackOffset < offset; c.ackOffset++ {
+		c.ackEntry(c.ackOffset + 1)
+	}
+}
+
+func (c *cursorAcker) ackEntry(offset int64) {
 	q := c.quorumTracker
 
 	e, found := q.tracker[offset]
@@ -88,6 +88,62 @@ func TestQuorumAckTrackerRF3(t *testing.T) {
 	assert.EqualValues(t, 2, at.CommitOffset())
 }
 
+func TestQuorumAckTracker_CumulativeAcks(t *testing.T) {
+	at := NewQuorumAckTracker(3, wal.InvalidOffset, wal.InvalidOffset)
+
+	c1, err := at.NewCursorAcker(wal.InvalidOffset)
+	assert.NoError(t, err)
+
+	c2, err := at.NewCursorAcker(wal.InvalidOffset)
+	assert.NoError(t, err)
+
+	for i := int64(0); i < 10; i++ {
+		at.AdvanceHeadOffset(i)
+	}
+	assert.EqualValues(t, 9, at.HeadOffset())
+	assert.Equal(t, wal.InvalidOffset, at.CommitOffset())
+
+	// A single ack covers all the previous entries
+	c1.Ack(4)
+	assert.EqualValues(t, 4, at.CommitOffset())
+
+	c2.Ack(7)
+	assert.EqualValues(t, 7, at.CommitOffset())
+
+	// Acks for older entries are ignored
+	c1.Ack(2)
+	assert.EqualValues(t, 7, at.CommitOffset())
+
+	c1.Ack(9)
+	assert.EqualValues(t, 9, at.CommitOffset())
+}
+
+func TestQuorumAckTracker_Relaxed(t *testing.T) {
+	at := NewRelaxedQuorumAckTracker(3, 5)
+
+	// All the entries in the leader wal are considered committed
+	assert.EqualValues(t, 5, at.HeadOffset())
+	assert.EqualValues(t, 5, at.CommitOffset())
+
+	c1, err := at.NewCursorAcker(wal.InvalidOffset)
+	assert.NoError(t, err)
+	_, err = at.NewCursorAcker(wal.InvalidOffset)
+	assert.NoError(t, err)
+	_, err = at.NewCursorAcker(wal.InvalidOffset)
+	assert.ErrorIs(t, err, ErrTooManyCursors)
+
+	// The commit offset doesn't wait for the followers acks
+	at.AdvanceHeadOffset(6)
+	assert.EqualValues(t, 6, at.HeadOffset())
+	assert.EqualValues(t, 6, at.CommitOffset())
+
+	_, err = at.WaitForCommitOffset(context.Background(), 6, nil)
+	assert.NoError(t, err)
+
+	c1.Ack(6)
+	assert.EqualValues(t, 6, at.CommitOffset())
+}
+
 func TestQuorumAckTrackerRF5(t *testing.T) {
 	at := NewQuorumAckTracker(5, 1, wal.InvalidOffset)
 
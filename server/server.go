@@ -15,21 +15,28 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"log/slog"
+	"path/filepath"
 	"time"
 
 	"github.com/streamnative/oxia/server/auth"
+	"github.com/streamnative/oxia/server/encryption"
 
 	"go.uber.org/multierr"
 	"google.golang.org/grpc/health"
 
 	"github.com/streamnative/oxia/common/container"
 	"github.com/streamnative/oxia/common/metrics"
+	"github.com/streamnative/oxia/common/security"
 	"github.com/streamnative/oxia/server/kv"
 	"github.com/streamnative/oxia/server/wal"
 )
 
+// The file where the wrapped data keys of the wal are stored, in the wal dir
+const WalEncryptionKeysFile = "encryption-keys.json"
+
 type Config struct {
 	PublicServiceAddr   string
 	InternalServiceAddr string
@@ -38,6 +45,12 @@ type Config struct {
 	InternalServerTLS   *tls.Config
 	MetricsServiceAddr  string
 
+	// The filters of the connections accepted by each listener, based on the
+	// address of the peer. A nil filter accepts everything.
+	PublicIPFilter   *security.IPFilter
+	InternalIPFilter *security.IPFilter
+	MetricsIPFilter  *security.IPFilter
+
 	AuthOptions auth.Options
 
 	DataDir string
@@ -47,7 +60,79 @@ type Config struct {
 	WalSyncData                bool
 	NotificationsRetentionTime time.Duration
 
+	// FollowerAckTimeout is the time after which a follower that is not
+	// acknowledging the pending entries is marked as unhealthy by the leader.
+	// A value of 0 disables the check.
+	FollowerAckTimeout time.Duration
+
+	// ReplicationAckInterval is the max time a follower waits before acking
+	// the entries synced in its wal. Acks are cumulative, so coalescing them
+	// reduces the number of messages sent back to the leader. With 0, the
+	// follower acks right after each wal sync.
+	ReplicationAckInterval time.Duration
+
+	// ReplicationAckMaxEntries makes the follower send the ack as soon as
+	// there are at least this number of pending entries, without waiting for
+	// the whole ReplicationAckInterval.
+	ReplicationAckMaxEntries int64
+
+	// NotificationsSubscriberTimeout is the max time a notifications
+	// subscriber can leave its send queue full before being evicted. The
+	// client is then expected to reconnect from its last received offset.
+	// A value of 0 disables the eviction.
+	NotificationsSubscriberTimeout time.Duration
+
 	DbBlockCacheMB int64
+
+	// AuditLogPath is the file where the writes applied by the leaders are
+	// recorded, one json object per line. Empty disables the audit log.
+	AuditLogPath string
+
+	// EncryptionOptions enable the encryption at rest of the wal and the
+	// database, with the master keys of the given provider
+	EncryptionOptions encryption.Options
+
+	// EncryptionKeyRotationInterval is the max age of the data keys
+	EncryptionKeyRotationInterval time.Duration
+
+	// AuditLog is a dedicated sink for the audit records. It takes precedence
+	// over AuditLogPath, and is closed with the server.
+	AuditLog AuditLog
+
+	// The limiters of the tenants, shared by the leaders of the server
+	tenants *tenantLimiters
+}
+
+// openEncryption creates the key provider, and the keyring of the wal. The
+// data keys of the wal are shared by all the shards of the server, while each
+// database stores its own data keys.
+func openEncryption(config Config) (encryption.KeyProvider, *encryption.Keyring, error) {
+	if !config.EncryptionOptions.IsEnabled() {
+		return nil, nil, nil
+	}
+
+	ctx := context.Background()
+	provider, err := encryption.NewKeyProvider(ctx, config.EncryptionOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyring, err := encryption.OpenFileKeyring(ctx, provider, filepath.Join(config.WalDir, WalEncryptionKeysFile),
+		config.EncryptionKeyRotationInterval)
+	if err != nil {
+		return nil, nil, multierr.Append(err, provider.Close())
+	}
+	return provider, keyring, nil
+}
+
+func openAuditLog(config *Config) error {
+	if config.AuditLog != nil || config.AuditLogPath == "" {
+		return nil
+	}
+
+	var err error
+	config.AuditLog, err = NewFileAuditLog(config.AuditLogPath)
+	return err
 }
 
 type Server struct {
@@ -62,6 +147,8 @@ type Server struct {
 	kvFactory                 kv.Factory
 
 	healthServer *health.Server
+	auditLog     AuditLog
+	keyProvider  encryption.KeyProvider
 }
 
 func New(config Config) (*Server, error) {
@@ -74,9 +161,20 @@ func NewWithGrpcProvider(config Config, provider container.GrpcProvider, replica
 		slog.Any("config", config),
 	)
 
+	if err := openAuditLog(&config); err != nil {
+		return nil, err
+	}
+
+	keyProvider, walKeyring, err := openEncryption(config)
+	if err != nil {
+		return nil, err
+	}
+
 	kvFactory, err := kv.NewPebbleKVFactory(&kv.FactoryOptions{
-		DataDir:     config.DataDir,
-		CacheSizeMB: config.DbBlockCacheMB,
+		DataDir:             config.DataDir,
+		CacheSizeMB:         config.DbBlockCacheMB,
+		KeyProvider:         keyProvider,
+		KeyRotationInterval: config.EncryptionKeyRotationInterval,
 	})
 	if err != nil {
 		return nil, err
@@ -89,28 +187,32 @@ func NewWithGrpcProvider(config Config, provider container.GrpcProvider, replica
 			Retention:   config.WalRetentionTime,
 			SegmentSize: wal.DefaultFactoryOptions.SegmentSize,
 			SyncData:    true,
+			Keyring:     walKeyring,
 		}),
 		kvFactory:    kvFactory,
 		healthServer: health.NewServer(),
+		auditLog:     config.AuditLog,
+		keyProvider:  keyProvider,
 	}
 
 	s.shardsDirector = NewShardsDirector(config, s.walFactory, s.kvFactory, replicationRpcProvider)
 	s.shardAssignmentDispatcher = NewShardAssignmentDispatcher(s.healthServer)
+	s.shardAssignmentDispatcher.OnNamespacePoliciesUpdate(s.shardsDirector.UpdateNamespacePolicies)
 
 	s.internalRpcServer, err = newInternalRpcServer(provider, config.InternalServiceAddr,
-		s.shardsDirector, s.shardAssignmentDispatcher, s.healthServer, config.InternalServerTLS)
+		s.shardsDirector, s.shardAssignmentDispatcher, s.healthServer, config.InternalServerTLS, config.InternalIPFilter)
 	if err != nil {
 		return nil, err
 	}
 
 	s.publicRpcServer, err = newPublicRpcServer(provider, config.PublicServiceAddr, s.shardsDirector,
-		s.shardAssignmentDispatcher, config.ServerTLS, &config.AuthOptions)
+		s.shardAssignmentDispatcher, config.ServerTLS, config.PublicIPFilter, &config.AuthOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	if config.MetricsServiceAddr != "" {
-		s.metrics, err = metrics.Start(config.MetricsServiceAddr)
+		s.metrics, err = metrics.Start(config.MetricsServiceAddr, config.MetricsIPFilter)
 		if err != nil {
 			return nil, err
 		}
@@ -143,5 +245,13 @@ func (s *Server) Close() error {
 		err = multierr.Append(err, s.metrics.Close())
 	}
 
+	if s.auditLog != nil {
+		err = multierr.Append(err, s.auditLog.Close())
+	}
+
+	if s.keyProvider != nil {
+		err = multierr.Append(err, s.keyProvider.Close())
+	}
+
 	return err
 }
@@ -22,6 +22,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
 	"github.com/streamnative/oxia/common"
 
 	"github.com/streamnative/oxia/proto"
@@ -33,10 +35,13 @@ type session struct {
 	sync.Mutex
 	id             SessionId
 	clientIdentity string
+	principal      string
+	leaseName      string
 	shardId        int64
 	timeout        time.Duration
 	sm             *sessionManager
 	heartbeatCh    chan bool
+	lastHeartbeat  time.Time
 	cancel         context.CancelFunc
 	ctx            context.Context
 	log            *slog.Logger
@@ -46,9 +51,12 @@ func startSession(sessionId SessionId, sessionMetadata *proto.SessionMetadata, s
 	s := &session{
 		id:             sessionId,
 		clientIdentity: sessionMetadata.Identity,
+		principal:      sessionMetadata.GetPrincipal(),
+		leaseName:      sessionMetadata.GetLeaseName(),
 		timeout:        time.Duration(sessionMetadata.TimeoutMs) * time.Millisecond,
 		sm:             sm,
 		heartbeatCh:    make(chan bool, 1),
+		lastHeartbeat:  time.Now(),
 
 		log: slog.With(
 			slog.String("client-identity", sessionMetadata.Identity),
@@ -59,6 +67,9 @@ func startSession(sessionId SessionId, sessionMetadata *proto.SessionMetadata, s
 		),
 	}
 	sm.sessions[sessionId] = s
+	if s.leaseName != "" {
+		sm.leases[s.leaseName] = s
+	}
 
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 
@@ -148,8 +159,14 @@ func (s *session) delete() error {
 func (s *session) heartbeat() {
 	s.Lock()
 	defer s.Unlock()
+	s.lastHeartbeat = time.Now()
 	if s.heartbeatCh != nil {
-		s.heartbeatCh <- true
+		select {
+		case s.heartbeatCh <- true:
+		default:
+			// There is already a pending heartbeat. We must not block here
+			// while holding the lock, since the session might be expiring.
+		}
 	}
 }
 
@@ -157,36 +174,51 @@ func (s *session) waitForHeartbeats() {
 	s.Lock()
 	heartbeatChannel := s.heartbeatCh
 	s.Unlock()
+	if heartbeatChannel == nil {
+		// The session was closed before it started
+		return
+	}
 	s.log.Debug("Waiting for heartbeats")
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
 	for {
-		var timer = time.NewTimer(s.timeout)
-		var timeoutCh = timer.C
 		select {
 		case heartbeat := <-heartbeatChannel:
 			if !heartbeat {
 				// The channel is closed, so the session must be closing
 				return
 			}
+			if !timer.Stop() {
+				<-timer.C
+			}
 			timer.Reset(s.timeout)
-		case <-timeoutCh:
+		case <-timer.C:
 			s.log.Warn("Session expired")
 
 			s.Lock()
 			s.closeChannels()
-			err := s.delete()
-
-			if err != nil {
-				s.log.Error(
-					"Failed to delete session",
-					slog.Any("error", err),
-				)
-			}
 			s.Unlock()
 
 			s.sm.Lock()
-			delete(s.sm.sessions, s.id)
+			s.sm.forget(s)
 			s.sm.expiredSessions.Inc()
 			s.sm.Unlock()
+
+			s.deleteWithRetries()
+			return
 		}
 	}
 }
+
+// The ephemeral records of an expired session must not be left behind, so
+// we keep retrying while this server is the shard leader. Otherwise, the new
+// leader expires the session again, since it's still in the database.
+func (s *session) deleteWithRetries() {
+	_ = backoff.RetryNotify(s.delete, common.NewBackOff(s.sm.ctx), func(err error, duration time.Duration) {
+		s.log.Error(
+			"Failed to delete session, retrying later",
+			slog.Any("error", err),
+			slog.Duration("retry-after", duration),
+		)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"log/slog"
 	"net/url"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,22 +29,23 @@ import (
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/metrics"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
 	"github.com/streamnative/oxia/server/kv"
 )
 
 const (
-	sessionKeyPrefix = common.InternalKeyPrefix + "session"
-	sessionKeyFormat = sessionKeyPrefix + "/%016x"
+	SessionKeyPrefix = common.InternalKeyPrefix + "session"
+	sessionKeyFormat = SessionKeyPrefix + "/%016x"
 )
 
 type SessionId int64
 
 func SessionKey(sessionId SessionId) string {
-	return fmt.Sprintf("%s/%016x", sessionKeyPrefix, sessionId)
+	return fmt.Sprintf("%s/%016x", SessionKeyPrefix, sessionId)
 }
 
 func ShadowKey(sessionId SessionId, key string) string {
-	return fmt.Sprintf("%s/%016x/%s", sessionKeyPrefix, sessionId, url.PathEscape(key))
+	return fmt.Sprintf("%s/%016x/%s", SessionKeyPrefix, sessionId, url.PathEscape(key))
 }
 
 func KeyToId(key string) (SessionId, error) {
@@ -64,9 +66,13 @@ func KeyToId(key string) (SessionId, error) {
 
 type SessionManager interface {
 	io.Closer
-	CreateSession(request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error)
-	KeepAlive(sessionId int64) error
-	CloseSession(request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error)
+	CreateSession(ctx context.Context, request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error)
+	KeepAlive(ctx context.Context, sessionId int64) error
+	CloseSession(ctx context.Context, request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error)
+	GrantLease(ctx context.Context, request *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error)
+	RefreshLease(ctx context.Context, request *proto.LeaseRequest) error
+	RevokeLease(ctx context.Context, request *proto.LeaseRequest) error
+	ListSessions(ctx context.Context) ([]*proto.SessionStatus, error)
 	Initialize() error
 }
 
@@ -80,6 +86,9 @@ type sessionManager struct {
 	sessions         map[SessionId]*session
 	log              *slog.Logger
 
+	// The sessions that are the records of the leases, by lease name
+	leases map[string]*session
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -93,6 +102,7 @@ func NewSessionManager(ctx context.Context, namespace string, shardId int64, con
 	labels := metrics.LabelsForShard(namespace, shardId)
 	sm := &sessionManager{
 		sessions:         make(map[SessionId]*session),
+		leases:           make(map[string]*session),
 		namespace:        namespace,
 		shardId:          shardId,
 		leaderController: controller,
@@ -123,11 +133,13 @@ func NewSessionManager(ctx context.Context, namespace string, shardId int64, con
 	return sm
 }
 
-func (sm *sessionManager) CreateSession(request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error) {
-	return sm.createSession(request, common.MinSessionTimeout)
+// CreateSession registers a new session, owned by the principal of the
+// request, when authenticated.
+func (sm *sessionManager) CreateSession(ctx context.Context, request *proto.CreateSessionRequest) (*proto.CreateSessionResponse, error) {
+	return sm.createSession(ctx, request, common.MinSessionTimeout)
 }
 
-func (sm *sessionManager) createSession(request *proto.CreateSessionRequest, minTimeout time.Duration) (*proto.CreateSessionResponse, error) {
+func (sm *sessionManager) createSession(ctx context.Context, request *proto.CreateSessionRequest, minTimeout time.Duration) (*proto.CreateSessionResponse, error) {
 	timeout := time.Duration(request.SessionTimeoutMs) * time.Millisecond
 	if timeout > common.MaxSessionTimeout || timeout < minTimeout {
 		return nil, errors.Wrap(common.ErrorInvalidSessionTimeout, fmt.Sprintf("timeoutMs=%d", request.SessionTimeoutMs))
@@ -138,15 +150,30 @@ func (sm *sessionManager) createSession(request *proto.CreateSessionRequest, min
 	metadata := proto.SessionMetadataFromVTPool()
 	metadata.TimeoutMs = uint32(timeout.Milliseconds())
 	metadata.Identity = request.ClientIdentity
+	if principal, authenticated := auth.GetPrincipal(ctx); authenticated {
+		metadata.Principal = &principal
+	}
 	defer metadata.ReturnToVTPool()
 
+	s, err := sm.registerSession(request.ShardId, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.createdSessions.Inc()
+	return &proto.CreateSessionResponse{SessionId: int64(s.id)}, nil
+}
+
+// registerSession writes the metadata of a new session and starts tracking
+// its heartbeats. It must be called with the lock held.
+func (sm *sessionManager) registerSession(shardId int64, metadata *proto.SessionMetadata) (*session, error) {
 	marshalledMetadata, err := metadata.MarshalVT()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not marshal session metadata")
 	}
 	id, resp, err := sm.leaderController.write(sm.ctx, func(id int64) *proto.WriteRequest {
 		return &proto.WriteRequest{
-			ShardId: &request.ShardId,
+			ShardId: &shardId,
 			Puts: []*proto.PutRequest{{
 				Key:   SessionKey(SessionId(id)),
 				Value: marshalledMetadata,
@@ -163,10 +190,7 @@ func (sm *sessionManager) createSession(request *proto.CreateSessionRequest, min
 		return nil, errors.Errorf("failed to register session. invalid status %#v", resp.Puts[0].Status)
 	}
 
-	s := startSession(sessionId, metadata, sm)
-
-	sm.createdSessions.Inc()
-	return &proto.CreateSessionResponse{SessionId: int64(s.id)}, nil
+	return startSession(sessionId, metadata, sm), nil
 }
 
 func (sm *sessionManager) getSession(sessionId int64) (*session, error) {
@@ -181,36 +205,197 @@ func (sm *sessionManager) getSession(sessionId int64) (*session, error) {
 	return s, nil
 }
 
-func (sm *sessionManager) KeepAlive(sessionId int64) error {
+// checkOwner rejects the requests on a session from the principals other
+// than the one that created it. The requests without a principal, when the
+// authentication is disabled, like the ones of the coordinator, are allowed.
+func checkOwner(ctx context.Context, s *session) error {
+	if principal, authenticated := auth.GetPrincipal(ctx); authenticated && s.principal != principal {
+		return common.ErrorPermissionDenied
+	}
+	return nil
+}
+
+func (sm *sessionManager) KeepAlive(ctx context.Context, sessionId int64) error {
 	sm.RLock()
 	s, err := sm.getSession(sessionId)
 	sm.RUnlock()
 	if err != nil {
 		return err
 	}
+	if err = checkOwner(ctx, s); err != nil {
+		return err
+	}
 	s.heartbeat()
 	return nil
 }
 
-func (sm *sessionManager) CloseSession(request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
+func (sm *sessionManager) CloseSession(ctx context.Context, request *proto.CloseSessionRequest) (*proto.CloseSessionResponse, error) {
 	sm.Lock()
 	s, err := sm.getSession(request.SessionId)
+	if err == nil {
+		err = checkOwner(ctx, s)
+	}
 	if err != nil {
 		sm.Unlock()
 		return nil, err
 	}
-	delete(sm.sessions, s.id)
+	sm.forget(s)
 	sm.Unlock()
+	if err = sm.closeSession(s); err != nil {
+		return nil, err
+	}
+	return &proto.CloseSessionResponse{}, nil
+}
+
+func (sm *sessionManager) closeSession(s *session) error {
 	s.Lock()
 	defer s.Unlock()
 	s.closeChannels()
-	err = s.close()
+	if err := s.close(); err != nil {
+		return err
+	}
+
+	sm.closedSessions.Inc()
+	return nil
+}
+
+// forget stops tracking a session that is closed or expired. It must be
+// called with the lock held.
+func (sm *sessionManager) forget(s *session) {
+	delete(sm.sessions, s.id)
+	if s.leaseName != "" && sm.leases[s.leaseName] == s {
+		delete(sm.leases, s.leaseName)
+	}
+}
+
+// GrantLease returns the session of the lease with the given name, creating
+// it if the lease doesn't exist yet. A lease is a session that is not tied to
+// a client: it's refreshed and revoked by its name, by any of the clients of
+// the principal that granted it.
+func (sm *sessionManager) GrantLease(ctx context.Context, request *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error) {
+	if request.Name == "" {
+		return nil, errors.Wrap(common.ErrorInvalidSession, "the lease name cannot be empty")
+	}
+	ttl := time.Duration(request.TtlMs) * time.Millisecond
+	if ttl > common.MaxSessionTimeout || ttl < common.MinSessionTimeout {
+		return nil, errors.Wrap(common.ErrorInvalidSessionTimeout, fmt.Sprintf("ttlMs=%d", request.TtlMs))
+	}
+	sm.Lock()
+	defer sm.Unlock()
+
+	if s, found := sm.leases[request.Name]; found {
+		if err := checkOwner(ctx, s); err != nil {
+			return nil, err
+		}
+		if s.timeout != ttl {
+			return nil, errors.Wrap(common.ErrorInvalidSessionTimeout,
+				fmt.Sprintf("the lease %s exists with a TTL of %v", request.Name, s.timeout))
+		}
+		return &proto.GrantLeaseResponse{SessionId: int64(s.id)}, nil
+	}
+
+	metadata := proto.SessionMetadataFromVTPool()
+	metadata.TimeoutMs = uint32(ttl.Milliseconds())
+	metadata.Identity = request.ClientIdentity
+	metadata.LeaseName = &request.Name
+	if principal, authenticated := auth.GetPrincipal(ctx); authenticated {
+		metadata.Principal = &principal
+	}
+	defer metadata.ReturnToVTPool()
+
+	s, err := sm.registerSession(request.ShardId, metadata)
 	if err != nil {
 		return nil, err
 	}
 
-	sm.closedSessions.Inc()
-	return &proto.CloseSessionResponse{}, nil
+	sm.createdSessions.Inc()
+	return &proto.GrantLeaseResponse{SessionId: int64(s.id)}, nil
+}
+
+// getLease returns the session of a lease. It must be called with the lock
+// held.
+func (sm *sessionManager) getLease(ctx context.Context, request *proto.LeaseRequest) (*session, error) {
+	s, found := sm.leases[request.Name]
+	if !found || (request.SessionId != nil && int64(s.id) != request.GetSessionId()) {
+		return nil, errors.Wrap(common.ErrorInvalidSession, "lease not found: "+request.Name)
+	}
+	if err := checkOwner(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (sm *sessionManager) RefreshLease(ctx context.Context, request *proto.LeaseRequest) error {
+	sm.RLock()
+	s, err := sm.getLease(ctx, request)
+	sm.RUnlock()
+	if err != nil {
+		return err
+	}
+	s.heartbeat()
+	return nil
+}
+
+func (sm *sessionManager) RevokeLease(ctx context.Context, request *proto.LeaseRequest) error {
+	sm.Lock()
+	s, err := sm.getLease(ctx, request)
+	if err != nil {
+		sm.Unlock()
+		return err
+	}
+	sm.forget(s)
+	sm.Unlock()
+	return sm.closeSession(s)
+}
+
+// ListSessions returns the status of the active sessions, along with the
+// number of their ephemeral records, for the admin API.
+func (sm *sessionManager) ListSessions(ctx context.Context) ([]*proto.SessionStatus, error) {
+	sm.RLock()
+	sessions := make([]*session, 0, len(sm.sessions))
+	for _, s := range sm.sessions {
+		sessions = append(sessions, s)
+	}
+	sm.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].id < sessions[j].id })
+
+	res := make([]*proto.SessionStatus, 0, len(sessions))
+	for _, s := range sessions {
+		s.Lock()
+		lastHeartbeat := s.lastHeartbeat
+		s.Unlock()
+
+		ss := &proto.SessionStatus{
+			SessionId:              int64(s.id),
+			ClientIdentity:         s.clientIdentity,
+			TimeoutMs:              uint32(s.timeout.Milliseconds()),
+			LastHeartbeatTimestamp: uint64(lastHeartbeat.UnixMilli()),
+		}
+
+		metaEntry, err := sm.leaderController.db.Get(&proto.GetRequest{Key: SessionKey(s.id)})
+		if err != nil {
+			return nil, err
+		}
+		if metaEntry.Status != proto.Status_OK {
+			// The session was closed in the meantime
+			continue
+		}
+		ss.CreatedTimestamp = metaEntry.Version.CreatedTimestamp
+
+		shadowKeys, err := sm.leaderController.ListSliceNoMutex(ctx, &proto.ListRequest{
+			ShardId:        &sm.shardId,
+			StartInclusive: SessionKey(s.id) + "/",
+			EndExclusive:   SessionKey(s.id) + "//",
+		})
+		if err != nil {
+			return nil, err
+		}
+		ss.EphemeralKeys = int64(len(shadowKeys))
+
+		res = append(res, ss)
+	}
+	return res, nil
 }
 
 func (sm *sessionManager) Initialize() error {
@@ -229,8 +414,8 @@ func (sm *sessionManager) Initialize() error {
 func (sm *sessionManager) readSessions() (map[SessionId]*proto.SessionMetadata, error) {
 	list, err := sm.leaderController.ListSliceNoMutex(context.Background(), &proto.ListRequest{
 		ShardId:        &sm.shardId,
-		StartInclusive: sessionKeyPrefix + "/",
-		EndExclusive:   sessionKeyPrefix + "//",
+		StartInclusive: SessionKeyPrefix + "/",
+		EndExclusive:   SessionKeyPrefix + "//",
 	})
 	sm.log.Debug("All sessions",
 		slog.Int("count", len(list)))
@@ -293,7 +478,7 @@ func (sm *sessionManager) Close() error {
 	defer sm.Unlock()
 	sm.cancel()
 	for _, s := range sm.sessions {
-		delete(sm.sessions, s.id)
+		sm.forget(s)
 		s.Lock()
 		s.closeChannels()
 		s.Unlock()
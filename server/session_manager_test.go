@@ -28,6 +28,7 @@ import (
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/auth"
 	"github.com/streamnative/oxia/server/kv"
 )
 
@@ -233,19 +234,19 @@ func TestSessionManager(t *testing.T) {
 	shardId := int64(1)
 	// Invalid session timeout
 	kvf, walf, sManager, lc := createSessionManager(t)
-	_, err := sManager.CreateSession(&proto.CreateSessionRequest{
+	_, err := sManager.CreateSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32((1 * time.Hour).Milliseconds()),
 	})
 	assert.ErrorIs(t, err, common.ErrorInvalidSessionTimeout)
-	_, err = sManager.CreateSession(&proto.CreateSessionRequest{
+	_, err = sManager.CreateSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32((1 * time.Second).Milliseconds()),
 	})
 	assert.ErrorIs(t, err, common.ErrorInvalidSessionTimeout)
 
 	// Create and close a session, check if its persisted
-	createResp, err := sManager.CreateSession(&proto.CreateSessionRequest{
+	createResp, err := sManager.CreateSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: 5 * 1000,
 	})
@@ -255,7 +256,7 @@ func TestSessionManager(t *testing.T) {
 	assert.NotNil(t, meta)
 	assert.Equal(t, uint32(5000), meta.TimeoutMs)
 
-	_, err = sManager.CloseSession(&proto.CloseSessionRequest{
+	_, err = sManager.CloseSession(context.Background(), &proto.CloseSessionRequest{
 		ShardId:   shardId,
 		SessionId: sessionId,
 	})
@@ -263,7 +264,7 @@ func TestSessionManager(t *testing.T) {
 	assert.Nil(t, getSessionMetadata(t, lc, sessionId))
 
 	// Create a session, watch it time out
-	createResp, err = sManager.createSession(&proto.CreateSessionRequest{
+	createResp, err = sManager.createSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32(50),
 	}, 0)
@@ -279,7 +280,7 @@ func TestSessionManager(t *testing.T) {
 	}, time.Second, 30*time.Millisecond)
 
 	// Create a session, keep it alive
-	createResp, err = sManager.createSession(&proto.CreateSessionRequest{
+	createResp, err = sManager.createSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32(50),
 	}, 0)
@@ -296,7 +297,7 @@ func TestSessionManager(t *testing.T) {
 	}, 10*time.Second, 30*time.Millisecond)
 
 	// Create a session, put an ephemeral value
-	createResp, err = sManager.createSession(&proto.CreateSessionRequest{
+	createResp, err = sManager.createSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32(50),
 	}, 0)
@@ -332,7 +333,7 @@ func TestMultipleSessionsExpiry(t *testing.T) {
 	kvf, walf, sManager, lc := createSessionManager(t)
 
 	// Create 2 sessions
-	createResp1, err := sManager.createSession(&proto.CreateSessionRequest{
+	createResp1, err := sManager.createSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32(3000),
 		ClientIdentity:   "session-1",
@@ -340,7 +341,7 @@ func TestMultipleSessionsExpiry(t *testing.T) {
 	assert.NoError(t, err)
 	sessionId1 := createResp1.SessionId
 
-	createResp2, err := sManager.createSession(&proto.CreateSessionRequest{
+	createResp2, err := sManager.createSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: uint32(50),
 		ClientIdentity:   "session-2",
@@ -450,7 +451,7 @@ func TestSessionManagerReopening(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create session and reopen the session manager
-	createResp, err := sManager.CreateSession(&proto.CreateSessionRequest{
+	createResp, err := sManager.CreateSession(context.Background(), &proto.CreateSessionRequest{
 		ShardId:          shardId,
 		SessionTimeoutMs: 5 * 1000,
 	})
@@ -482,6 +483,133 @@ func TestSessionManagerReopening(t *testing.T) {
 	assert.NoError(t, walf.Close())
 }
 
+func TestSessionManager_Owner(t *testing.T) {
+	shardId := int64(1)
+	walf, kvf, sManager, lc := createSessionManager(t)
+
+	alice := auth.WithPrincipal(context.Background(), "alice")
+	bob := auth.WithPrincipal(context.Background(), "bob")
+
+	createResp, err := sManager.CreateSession(alice, &proto.CreateSessionRequest{
+		ShardId:          shardId,
+		SessionTimeoutMs: 5 * 1000,
+	})
+	assert.NoError(t, err)
+	sessionId := createResp.SessionId
+	assert.Equal(t, "alice", getSessionMetadata(t, lc, sessionId).GetPrincipal())
+
+	// Only the principal that created the session can use it
+	assert.ErrorIs(t, sManager.KeepAlive(bob, sessionId), common.ErrorPermissionDenied)
+	assert.NoError(t, sManager.KeepAlive(alice, sessionId))
+
+	// The owner is restored with the session
+	lc = reopenLeaderController(t, walf, kvf, lc)
+	sManager = lc.sessionManager.(*sessionManager)
+
+	_, err = sManager.CloseSession(bob, &proto.CloseSessionRequest{ShardId: shardId, SessionId: sessionId})
+	assert.ErrorIs(t, err, common.ErrorPermissionDenied)
+	assert.NotNil(t, getSessionMetadata(t, lc, sessionId))
+
+	// The requests without a principal, like the ones of the coordinator,
+	// are allowed
+	assert.NoError(t, sManager.KeepAlive(context.Background(), sessionId))
+	_, err = sManager.CloseSession(context.Background(), &proto.CloseSessionRequest{ShardId: shardId, SessionId: sessionId})
+	assert.NoError(t, err)
+	assert.Nil(t, getSessionMetadata(t, lc, sessionId))
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvf.Close())
+	assert.NoError(t, walf.Close())
+}
+
+func TestSessionManager_Leases(t *testing.T) {
+	shardId := int64(1)
+	walf, kvf, sManager, lc := createSessionManager(t)
+	ctx := context.Background()
+
+	_, err := sManager.GrantLease(ctx, &proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 1})
+	assert.ErrorIs(t, err, common.ErrorInvalidSessionTimeout)
+	_, err = sManager.GrantLease(ctx, &proto.GrantLeaseRequest{ShardId: shardId, TtlMs: 5 * 1000})
+	assert.ErrorIs(t, err, common.ErrorInvalidSession)
+
+	grantResp, err := sManager.GrantLease(ctx, &proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 5 * 1000})
+	assert.NoError(t, err)
+	sessionId := grantResp.SessionId
+	meta := getSessionMetadata(t, lc, sessionId)
+	assert.Equal(t, "workers", meta.GetLeaseName())
+	assert.Equal(t, uint32(5000), meta.TimeoutMs)
+
+	// The clients granting the same lease share it
+	grantResp, err = sManager.GrantLease(ctx, &proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 5 * 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, sessionId, grantResp.SessionId)
+	_, err = sManager.GrantLease(ctx, &proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 10 * 1000})
+	assert.ErrorIs(t, err, common.ErrorInvalidSessionTimeout)
+
+	_, err = lc.Write(ctx, &proto.WriteRequest{
+		ShardId: &shardId,
+		Puts: []*proto.PutRequest{{
+			Key:       "/a/b",
+			Value:     []byte("/a/b"),
+			SessionId: &sessionId,
+		}},
+	})
+	assert.NoError(t, err)
+
+	// The lease is restored by its name
+	lc = reopenLeaderController(t, walf, kvf, lc)
+	sManager = lc.sessionManager.(*sessionManager)
+
+	assert.NoError(t, sManager.RefreshLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "workers"}))
+	assert.NoError(t, sManager.RefreshLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "workers", SessionId: &sessionId}))
+	otherSessionId := sessionId + 1
+	assert.ErrorIs(t, sManager.RefreshLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "workers", SessionId: &otherSessionId}),
+		common.ErrorInvalidSession)
+	assert.ErrorIs(t, sManager.RefreshLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "others"}),
+		common.ErrorInvalidSession)
+
+	// Only the principal that granted the lease can revoke it
+	bob := auth.WithPrincipal(ctx, "bob")
+	assert.NoError(t, sManager.RevokeLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "workers"}))
+	assert.Nil(t, getSessionMetadata(t, lc, sessionId))
+	assert.Equal(t, "", getData(t, lc, "/a/b"))
+	assert.ErrorIs(t, sManager.RevokeLease(ctx, &proto.LeaseRequest{ShardId: shardId, Name: "workers"}),
+		common.ErrorInvalidSession)
+
+	grantResp, err = sManager.GrantLease(auth.WithPrincipal(ctx, "alice"),
+		&proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 5 * 1000})
+	assert.NoError(t, err)
+	assert.NotEqual(t, sessionId, grantResp.SessionId)
+	assert.ErrorIs(t, sManager.RevokeLease(bob, &proto.LeaseRequest{ShardId: shardId, Name: "workers"}),
+		common.ErrorPermissionDenied)
+	_, err = sManager.GrantLease(bob, &proto.GrantLeaseRequest{ShardId: shardId, Name: "workers", TtlMs: 5 * 1000})
+	assert.ErrorIs(t, err, common.ErrorPermissionDenied)
+
+	assert.NoError(t, lc.Close())
+	assert.NoError(t, kvf.Close())
+	assert.NoError(t, walf.Close())
+}
+
+func TestSessionHeartbeatNotBlocking(t *testing.T) {
+	s := &session{heartbeatCh: make(chan bool, 1)}
+
+	// The heartbeats must not block while the session is not consuming them,
+	// eg: because it's expiring
+	done := make(chan any)
+	go func() {
+		s.heartbeat()
+		s.heartbeat()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the heartbeat is blocked")
+	}
+	assert.Len(t, s.heartbeatCh, 1)
+}
+
 func getData(t *testing.T, lc *leaderController, key string) string {
 	t.Helper()
 
@@ -503,7 +631,7 @@ func keepAlive(t *testing.T, sManager *sessionManager, sessionId int64, err erro
 		assert.NoError(t, err)
 		for i := 0; i < heartbeatCount; i++ {
 			time.Sleep(sleepTime)
-			assert.NoError(t, sManager.KeepAlive(sessionId))
+			assert.NoError(t, sManager.KeepAlive(context.Background(), sessionId))
 		}
 	}()
 }
@@ -0,0 +1,200 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	pb "google.golang.org/protobuf/proto"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/kv"
+	"github.com/streamnative/oxia/server/wal"
+)
+
+// The size of the keys and entries sent in each response when exporting the
+// records of a shard
+const exportBatchSize = 1024 * 1024
+
+// isMergeable tells whether the database has applied all the entries of the
+// wal, up to its head, and doesn't hold any session. The records can then be
+// exported into another shard, while the sessions and their ephemeral records
+// cannot be moved across shards.
+func isMergeable(db kv.DB, headEntryId *proto.EntryId) (bool, error) {
+	commitOffset, err := db.ReadCommitOffset()
+	if err != nil {
+		return false, err
+	}
+	if commitOffset < headEntryId.Offset {
+		return false, nil
+	}
+
+	it, err := db.List(&proto.ListRequest{
+		StartInclusive: SessionKeyPrefix + "/",
+		EndExclusive:   SessionKeyPrefix + "//",
+	})
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+
+	return !it.Valid(), nil
+}
+
+// exportRecords sends all the records of a fenced replica, with their
+// storage entries, so that they keep their versions, their timestamps and
+// their metadata in the shard they are merged into. The internal keys are
+// left out.
+func exportRecords(db kv.DB, w wal.Wal, stream proto.OxiaCoordination_ExportRecordsServer) error {
+	headEntryId, err := getLastEntryIdInWal(w)
+	if err != nil {
+		return err
+	}
+	mergeable, err := isMergeable(db, headEntryId)
+	if err != nil {
+		return err
+	}
+	if !mergeable {
+		return common.ErrorInvalidStatus
+	}
+
+	it, err := db.RangeScan(&proto.RangeScanRequest{})
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	res := &proto.ExportRecordsResponse{}
+	size := 0
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if strings.HasPrefix(key, common.InternalKeyPrefix) {
+			continue
+		}
+
+		se, err := it.Entry()
+		if err != nil {
+			return err
+		}
+		entry, err := se.MarshalVT()
+		if err != nil {
+			return err
+		}
+		res.Records = append(res.Records, &proto.MergedRecord{
+			Key:   key,
+			Entry: entry,
+		})
+
+		size += len(key) + len(entry)
+		if size >= exportBatchSize {
+			if err = stream.Send(res); err != nil {
+				return err
+			}
+			res = &proto.ExportRecordsResponse{}
+			size = 0
+		}
+	}
+
+	if len(res.Records) > 0 {
+		return stream.Send(res)
+	}
+	return nil
+}
+
+// stageMergedRecords writes the records of a merged shard under the internal
+// keys, where they stay until the merge is committed or aborted. The records
+// are rejected if any of their keys is already in the shard.
+func (lc *leaderController) stageMergedRecords(ctx context.Context, req *proto.ImportRecordsRequest) (*proto.ImportRecordsResponse, error) {
+	puts := make([]*proto.PutRequest, 0, len(req.Records))
+	for _, record := range req.Records {
+		res, err := lc.db.Get(&proto.GetRequest{Key: record.Key})
+		if err != nil {
+			return nil, err
+		}
+		if res.Status != proto.Status_KEY_NOT_FOUND {
+			lc.log.Warn(
+				"Key of the merged shard already exists",
+				slog.Int64("merged-shard", req.MergedShardId),
+				slog.String("key", record.Key),
+			)
+			return nil, common.ErrorKeyAlreadyExists
+		}
+
+		puts = append(puts, &proto.PutRequest{
+			Key:   kv.MergedRecordKey(req.MergedShardId, record.Key),
+			Value: record.Entry,
+		})
+	}
+
+	lc.log.Info(
+		"Staging the records of a merged shard",
+		slog.Int64("merged-shard", req.MergedShardId),
+		slog.Int("records", len(puts)),
+	)
+
+	if _, _, err := lc.write(ctx, func(_ int64) *proto.WriteRequest {
+		return &proto.WriteRequest{Puts: puts}
+	}); err != nil {
+		return nil, err
+	}
+	return &proto.ImportRecordsResponse{}, nil
+}
+
+// commitMergedRecords moves the staged records of a merged shard into the key
+// space. The records are left staged, and ErrorKeyAlreadyExists is returned,
+// if any of their keys was written in the meantime, for the merge to be
+// aborted. Committing again a merge that was
+// already committed has no effect.
+func (lc *leaderController) commitMergedRecords(ctx context.Context, mergedShardId int64) (*proto.ImportRecordsResponse, error) {
+	lc.log.Info(
+		"Committing the merge of a shard",
+		slog.Int64("merged-shard", mergedShardId),
+	)
+
+	_, res, err := lc.write(ctx, func(_ int64) *proto.WriteRequest {
+		return &proto.WriteRequest{MergedShardId: pb.Int64(mergedShardId)}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.GetMergeStatus() == proto.Status_KEY_ALREADY_EXISTS {
+		return nil, common.ErrorKeyAlreadyExists
+	}
+	return &proto.ImportRecordsResponse{}, nil
+}
+
+// abortMergedRecords drops the staged records of a merged shard.
+func (lc *leaderController) abortMergedRecords(ctx context.Context, mergedShardId int64) (*proto.ImportRecordsResponse, error) {
+	lc.log.Info(
+		"Aborting the merge of a shard",
+		slog.Int64("merged-shard", mergedShardId),
+	)
+
+	startInclusive, endExclusive := kv.MergedRecordsRange(mergedShardId)
+	if _, _, err := lc.write(ctx, func(_ int64) *proto.WriteRequest {
+		return &proto.WriteRequest{
+			DeleteRanges: []*proto.DeleteRangeRequest{{
+				StartInclusive: startInclusive,
+				EndExclusive:   endExclusive,
+			}},
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return &proto.ImportRecordsResponse{}, nil
+}
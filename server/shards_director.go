@@ -18,6 +18,7 @@ import (
 	"io"
 	"log/slog"
 	"sync"
+	"time"
 
 	"go.uber.org/multierr"
 	"google.golang.org/grpc/status"
@@ -39,6 +40,10 @@ type ShardsDirector interface {
 	GetOrCreateFollower(namespace string, shardId int64) (FollowerController, error)
 
 	DeleteShard(req *proto.DeleteShardRequest) (*proto.DeleteShardResponse, error)
+
+	// UpdateNamespacePolicies applies the policies distributed by the
+	// coordinator to the leaders and followers of each namespace
+	UpdateNamespacePolicies(policies map[string]*proto.NamespacePolicies)
 }
 
 type shardsDirector struct {
@@ -48,6 +53,10 @@ type shardsDirector struct {
 	leaders   map[int64]LeaderController
 	followers map[int64]FollowerController
 
+	// The namespace of each shard and the policies of each namespace
+	namespaces map[int64]string
+	policies   map[string]*proto.NamespacePolicies
+
 	kvFactory              kv.Factory
 	walFactory             wal.Factory
 	replicationRpcProvider ReplicationRpcProvider
@@ -59,12 +68,18 @@ type shardsDirector struct {
 }
 
 func NewShardsDirector(config Config, walFactory wal.Factory, kvFactory kv.Factory, provider ReplicationRpcProvider) ShardsDirector {
+	if config.tenants == nil {
+		config.tenants = newTenantLimiters()
+	}
+
 	sd := &shardsDirector{
 		config:                 config,
 		walFactory:             walFactory,
 		kvFactory:              kvFactory,
 		leaders:                make(map[int64]LeaderController),
 		followers:              make(map[int64]FollowerController),
+		namespaces:             make(map[int64]string),
+		policies:               make(map[string]*proto.NamespacePolicies),
 		replicationRpcProvider: provider,
 		log: slog.With(
 			slog.String("component", "shards-director"),
@@ -152,6 +167,9 @@ func (s *shardsDirector) GetOrCreateLeader(namespace string, shardId int64) (Lea
 
 	s.leaders[shardId] = lc
 	s.leadersCounter.Inc()
+	s.namespaces[shardId] = namespace
+	lc.UpdatePolicies(s.policies[namespace])
+	lc.SetNotificationsRetention(s.notificationsRetention(namespace))
 	return lc, nil
 }
 
@@ -188,6 +206,8 @@ func (s *shardsDirector) GetOrCreateFollower(namespace string, shardId int64) (F
 
 	s.followers[shardId] = fc
 	s.followersCounter.Inc()
+	s.namespaces[shardId] = namespace
+	fc.SetNotificationsRetention(s.notificationsRetention(namespace))
 	return fc, nil
 }
 
@@ -202,6 +222,7 @@ func (s *shardsDirector) DeleteShard(req *proto.DeleteShardRequest) (*proto.Dele
 		}
 
 		delete(s.leaders, req.ShardId)
+		delete(s.namespaces, req.ShardId)
 		s.leadersCounter.Dec()
 		return resp, nil
 	}
@@ -213,6 +234,7 @@ func (s *shardsDirector) DeleteShard(req *proto.DeleteShardRequest) (*proto.Dele
 		}
 
 		delete(s.followers, req.ShardId)
+		delete(s.namespaces, req.ShardId)
 		s.followersCounter.Dec()
 		return resp, nil
 	}
@@ -224,6 +246,31 @@ func (s *shardsDirector) DeleteShard(req *proto.DeleteShardRequest) (*proto.Dele
 	return fc.DeleteShard(req)
 }
 
+func (s *shardsDirector) UpdateNamespacePolicies(policies map[string]*proto.NamespacePolicies) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.policies = policies
+	for shardId, leader := range s.leaders {
+		namespace := s.namespaces[shardId]
+		leader.UpdatePolicies(policies[namespace])
+		leader.SetNotificationsRetention(s.notificationsRetention(namespace))
+	}
+
+	for shardId, follower := range s.followers {
+		follower.SetNotificationsRetention(s.notificationsRetention(s.namespaces[shardId]))
+	}
+}
+
+// The retention of the namespace policies takes precedence over the one
+// configured on the server.
+func (s *shardsDirector) notificationsRetention(namespace string) time.Duration {
+	if p := s.policies[namespace]; p != nil && p.NotificationsRetentionMs > 0 {
+		return time.Duration(p.NotificationsRetentionMs) * time.Millisecond
+	}
+	return s.config.NotificationsRetentionTime
+}
+
 func (s *shardsDirector) Close() error {
 	s.Lock()
 	defer s.Unlock()
@@ -17,6 +17,7 @@ package server
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -62,3 +63,33 @@ func TestShardsDirector_DeleteShardLeader(t *testing.T) {
 	assert.NoError(t, lc.Close())
 	assert.NoError(t, walFactory.Close())
 }
+
+func TestShardsDirector_UpdateNamespacePolicies(t *testing.T) {
+	kvFactory, _ := kv.NewPebbleKVFactory(testKVOptions)
+	walFactory := newTestWalFactory(t)
+
+	sd := NewShardsDirector(Config{NotificationsRetentionTime: 1 * time.Hour}, walFactory, kvFactory, newMockRpcClient())
+
+	lc, err := sd.GetOrCreateLeader("ns-1", 1)
+	assert.NoError(t, err)
+	fc, err := sd.GetOrCreateFollower("ns-2", 2)
+	assert.NoError(t, err)
+
+	sd.UpdateNamespacePolicies(map[string]*proto.NamespacePolicies{
+		"ns-1": {MaxValueSize: 5},
+		"ns-2": {NotificationsRetentionMs: 1000},
+	})
+
+	assert.EqualValues(t, 5, lc.(*leaderController).policies.policies.MaxValueSize)
+	assert.Equal(t, 1*time.Second, fc.(*followerController).config.NotificationsRetentionTime)
+	assert.Equal(t, 1*time.Hour, sd.(*shardsDirector).notificationsRetention("ns-1"))
+
+	// The policies are applied to the controllers created later
+	lc3, err := sd.GetOrCreateLeader("ns-2", 3)
+	assert.NoError(t, err)
+	assert.Zero(t, lc3.(*leaderController).policies.policies.MaxValueSize)
+	assert.Equal(t, 1*time.Second, sd.(*shardsDirector).notificationsRetention("ns-2"))
+
+	assert.NoError(t, sd.Close())
+	assert.NoError(t, walFactory.Close())
+}
@@ -43,6 +43,7 @@ type Standalone struct {
 	walFactory                wal.Factory
 	shardsDirector            ShardsDirector
 	shardAssignmentDispatcher ShardAssignmentsDispatcher
+	auditLog                  AuditLog
 
 	metrics *metrics.PrometheusMetrics
 }
@@ -66,7 +67,11 @@ func NewStandalone(config StandaloneConfig) (*Standalone, error) {
 		slog.Any("config", config),
 	)
 
-	s := &Standalone{}
+	if err := openAuditLog(&config.Config); err != nil {
+		return nil, err
+	}
+
+	s := &Standalone{auditLog: config.AuditLog}
 
 	kvOptions := kv.FactoryOptions{DataDir: config.DataDir}
 	s.walFactory = wal.NewWalFactory(&wal.FactoryOptions{
@@ -87,7 +92,7 @@ func NewStandalone(config StandaloneConfig) (*Standalone, error) {
 	}
 
 	s.rpc, err = newPublicRpcServer(container.Default, config.PublicServiceAddr, s.shardsDirector,
-		nil, config.ServerTLS, &auth.Disabled)
+		nil, config.ServerTLS, config.PublicIPFilter, &auth.Disabled)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +102,7 @@ func NewStandalone(config StandaloneConfig) (*Standalone, error) {
 	s.rpc.assignmentDispatcher = s.shardAssignmentDispatcher
 
 	if config.MetricsServiceAddr != "" {
-		s.metrics, err = metrics.Start(config.MetricsServiceAddr)
+		s.metrics, err = metrics.Start(config.MetricsServiceAddr, config.MetricsIPFilter)
 	}
 	if err != nil {
 		return nil, err
@@ -146,13 +151,18 @@ func (s *Standalone) Close() error {
 		err = s.metrics.Close()
 	}
 
-	return multierr.Combine(
+	err = multierr.Combine(
 		err,
 		s.shardsDirector.Close(),
 		s.shardAssignmentDispatcher.Close(),
 		s.rpc.Close(),
 		s.kvFactory.Close(),
 	)
+
+	if s.auditLog != nil {
+		err = multierr.Append(err, s.auditLog.Close())
+	}
+	return err
 }
 
 type noOpReplicationRpcProvider struct {
@@ -0,0 +1,88 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/common/metrics"
+	"github.com/streamnative/oxia/proto"
+)
+
+// tenantLimiters enforces the limits of the tenants across all the shards
+// led by the server, and aggregates the metrics of the namespaces of each
+// tenant.
+type tenantLimiters struct {
+	sync.Mutex
+	tenants map[string]*tenantLimiter
+}
+
+type tenantLimiter struct {
+	ops        *rate.Limiter
+	writeBytes *rate.Limiter
+
+	readOps          metrics.Counter
+	writeOps         metrics.Counter
+	writtenBytes     metrics.Counter
+	rejectedRequests metrics.Counter
+}
+
+func newTenantLimiters() *tenantLimiters {
+	return &tenantLimiters{
+		tenants: map[string]*tenantLimiter{},
+	}
+}
+
+// check rejects the requests in excess of the rate of the tenant, and counts
+// the operations of the ones that are admitted.
+func (t *tenantLimiters) check(tenant *proto.TenantPolicies, reads int, writes int, writeBytes int) error {
+	if tenant == nil {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	l, ok := t.tenants[tenant.Name]
+	if !ok {
+		labels := map[string]any{"tenant": tenant.Name}
+		l = &tenantLimiter{
+			readOps: metrics.NewCounter("oxia_server_tenant_read_ops",
+				"The number of read operations of the namespaces of the tenant", "count", labels),
+			writeOps: metrics.NewCounter("oxia_server_tenant_write_ops",
+				"The number of write operations of the namespaces of the tenant", "count", labels),
+			writtenBytes: metrics.NewCounter("oxia_server_tenant_written_bytes",
+				"The number of bytes written in the namespaces of the tenant", metrics.Bytes, labels),
+			rejectedRequests: metrics.NewCounter("oxia_server_tenant_rejected_requests",
+				"The number of requests rejected by the rate limits of the tenant", "count", labels),
+		}
+		t.tenants[tenant.Name] = l
+	}
+	l.ops = updateLimiter(l.ops, tenant.MaxOpsPerSecond)
+	l.writeBytes = updateLimiter(l.writeBytes, tenant.MaxWriteBytesPerSecond)
+
+	if !allow(l.ops, reads+writes) || !allow(l.writeBytes, writeBytes) {
+		l.rejectedRequests.Inc()
+		return common.ErrorTenantRateLimited
+	}
+
+	l.readOps.Add(reads)
+	l.writeOps.Add(writes)
+	l.writtenBytes.Add(writeBytes)
+	return nil
+}
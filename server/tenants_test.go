@@ -0,0 +1,46 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+)
+
+func TestTenantLimiters(t *testing.T) {
+	tenants := newTenantLimiters()
+
+	// The namespaces without a tenant are not limited
+	assert.NoError(t, tenants.check(nil, 1000, 1000, 1000))
+
+	teamA := &proto.TenantPolicies{Name: "team-a", MaxOpsPerSecond: 10, MaxWriteBytesPerSecond: 100}
+	teamB := &proto.TenantPolicies{Name: "team-b"}
+
+	// The limits are shared by all the shards of the tenant
+	assert.NoError(t, tenants.check(teamA, 5, 0, 0))
+	assert.NoError(t, tenants.check(teamA, 0, 5, 50))
+	assert.ErrorIs(t, tenants.check(teamA, 1, 0, 0), common.ErrorTenantRateLimited)
+	assert.NoError(t, tenants.check(teamB, 100, 100, 1000))
+
+	// The limits are updated with the policies
+	tenants = newTenantLimiters()
+	assert.NoError(t, tenants.check(teamA, 0, 1, 100))
+	assert.ErrorIs(t, tenants.check(teamA, 0, 1, 10), common.ErrorTenantRateLimited)
+	assert.NoError(t, tenants.check(&proto.TenantPolicies{Name: "team-a"}, 0, 1, 10))
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
+)
+
+// SegmentStats describes a segment of the wal, as stored on disk.
+type SegmentStats struct {
+	BaseOffset int64 `json:"baseOffset"`
+
+	// LastOffset is InvalidOffset for an empty segment
+	LastOffset int64 `json:"lastOffset"`
+	Entries    int64 `json:"entries"`
+
+	// Bytes is the size of the entries, while FileBytes includes the space
+	// that is pre-allocated for the next entries
+	Bytes     int64 `json:"bytes"`
+	FileBytes int64 `json:"fileBytes"`
+
+	// HasIndex is false for the segment that was being written, whose index
+	// is only written when it is closed
+	HasIndex bool `json:"hasIndex"`
+}
+
+// EntryHeader describes an entry of the wal, without its value.
+type EntryHeader struct {
+	Offset    int64  `json:"offset"`
+	Term      int64  `json:"term"`
+	Timestamp uint64 `json:"timestamp"`
+	Size      uint32 `json:"size"`
+}
+
+// Inspector reads the wal of a shard offline, without modifying any of its
+// files. Unlike the wal itself, it does not rely on the segment indexes,
+// so that it can read the segment that was being written when the server
+// stopped.
+type Inspector struct {
+	walPath  string
+	keyring  *encryption.Keyring
+	segments []int64
+}
+
+func NewInspector(baseWalDir string, namespace string, shard int64, keyring *encryption.Keyring) (*Inspector, error) {
+	path := walPath(baseWalDir, namespace, shard)
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrapf(err, "failed to open the wal of shard %s / %d", namespace, shard)
+	}
+
+	segments, err := listAllSegments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Inspector{
+		walPath:  path,
+		keyring:  keyring,
+		segments: segments,
+	}, nil
+}
+
+// scanSegment calls the function with the position in the file and the raw
+// value of each entry of the segment, in order.
+func scanSegment(txn []byte, f func(fileOffset uint32, value []byte) error) error {
+	var fileOffset uint32
+	for int(fileOffset)+4 <= len(txn) {
+		size := readInt(txn, fileOffset)
+		if size == 0 || int(size) > len(txn)-int(fileOffset)-4 {
+			break
+		}
+
+		if err := f(fileOffset, txn[fileOffset+4:fileOffset+4+size]); err != nil {
+			return err
+		}
+		fileOffset += 4 + size
+	}
+	return nil
+}
+
+func (i *Inspector) readSegment(baseOffset int64) ([]byte, error) {
+	txnPath := segmentPath(i.walPath, baseOffset) + txnExtension
+	txn, err := os.ReadFile(txnPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read segment txn file %s", txnPath)
+	}
+	return txn, nil
+}
+
+// Segments returns the stats of all the segments of the wal, from the oldest
+// to the newest.
+func (i *Inspector) Segments() ([]SegmentStats, error) {
+	stats := make([]SegmentStats, 0, len(i.segments))
+	for _, baseOffset := range i.segments {
+		txn, err := i.readSegment(baseOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		s := SegmentStats{
+			BaseOffset: baseOffset,
+			FileBytes:  int64(len(txn)),
+		}
+		_ = scanSegment(txn, func(_ uint32, value []byte) error {
+			s.Entries++
+			s.Bytes += 4 + int64(len(value))
+			return nil
+		})
+		s.LastOffset = baseOffset + s.Entries - 1
+		if s.Entries == 0 {
+			s.LastOffset = InvalidOffset
+		}
+
+		_, err = os.Stat(segmentPath(i.walPath, baseOffset) + idxExtension)
+		s.HasIndex = err == nil
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// ReadEntries calls the function with the header and the entry for each
+// entry of the wal whose offset is in [firstOffset, lastOffset]. It fails
+// if an entry is not stored at the position matching its offset.
+func (i *Inspector) ReadEntries(firstOffset int64, lastOffset int64, f func(header EntryHeader, entry *proto.LogEntry) error) error {
+	for idx, baseOffset := range i.segments {
+		if baseOffset > lastOffset {
+			break
+		}
+		if idx+1 < len(i.segments) && i.segments[idx+1] <= firstOffset {
+			continue
+		}
+
+		txn, err := i.readSegment(baseOffset)
+		if err != nil {
+			return err
+		}
+
+		offset := baseOffset
+		err = scanSegment(txn, func(fileOffset uint32, value []byte) error {
+			defer func() { offset++ }()
+			if offset < firstOffset || offset > lastOffset {
+				return nil
+			}
+
+			entry, err := i.decode(value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to decode the entry at position %d of segment %d", fileOffset, baseOffset)
+			}
+			if entry.Offset != offset {
+				return errors.Errorf("found the entry with offset %d where offset %d was expected, in segment %d",
+					entry.Offset, offset, baseOffset)
+			}
+
+			return f(EntryHeader{
+				Offset:    entry.Offset,
+				Term:      entry.Term,
+				Timestamp: entry.Timestamp,
+				Size:      uint32(len(value)),
+			}, entry)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Inspector) decode(value []byte) (*proto.LogEntry, error) {
+	if i.keyring != nil {
+		var err error
+		if value, err = i.keyring.Decrypt(value); err != nil {
+			return nil, err
+		}
+	} else if encryption.IsEncrypted(value) {
+		return nil, errors.New("the wal entry is encrypted, but the encryption is not configured")
+	}
+
+	entry := &proto.LogEntry{}
+	if err := entry.UnmarshalVT(value); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
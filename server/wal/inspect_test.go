@@ -0,0 +1,131 @@
+// Copyright 2023 StreamNative, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/streamnative/oxia/common"
+	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
+)
+
+func TestInspector(t *testing.T) {
+	dir := t.TempDir()
+	f := NewWalFactory(&FactoryOptions{
+		BaseWalDir:  dir,
+		Retention:   1 * time.Hour,
+		SegmentSize: 1024,
+	})
+	w, err := f.NewWal(common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+
+	for i := int64(0); i < 30; i++ {
+		assert.NoError(t, w.Append(&proto.LogEntry{
+			Term:      1 + i/10,
+			Offset:    i,
+			Value:     bytes.Repeat([]byte{'v'}, 100),
+			Timestamp: uint64(1000 + i),
+		}))
+	}
+
+	// The current segment is read, even if it has no index yet
+	inspector, err := NewInspector(dir, common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+
+	segments, err := inspector.Segments()
+	assert.NoError(t, err)
+	assert.Greater(t, len(segments), 1)
+
+	var entries int64
+	for i, s := range segments {
+		assert.EqualValues(t, entries, s.BaseOffset)
+		assert.EqualValues(t, s.BaseOffset+s.Entries-1, s.LastOffset)
+		assert.LessOrEqual(t, s.Bytes, s.FileBytes)
+		assert.Equal(t, i < len(segments)-1, s.HasIndex)
+		entries += s.Entries
+	}
+	assert.EqualValues(t, 30, entries)
+
+	var headers []EntryHeader
+	assert.NoError(t, inspector.ReadEntries(8, 12, func(header EntryHeader, entry *proto.LogEntry) error {
+		assert.Len(t, entry.Value, 100)
+		headers = append(headers, header)
+		return nil
+	}))
+	assert.Len(t, headers, 5)
+	for i, h := range headers {
+		offset := int64(8 + i)
+		assert.Equal(t, offset, h.Offset)
+		assert.Equal(t, 1+offset/10, h.Term)
+		assert.EqualValues(t, 1000+offset, h.Timestamp)
+		assert.Positive(t, h.Size)
+	}
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	_, err = NewInspector(dir, common.DefaultNamespace, shard+1, nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestInspector_Encryption(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master-keys")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("k1:"+base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600))
+	provider, err := encryption.NewKeyFileProvider(fmt.Sprintf(`{"path":%q}`, keyFile))
+	assert.NoError(t, err)
+	keyring, err := encryption.OpenFileKeyring(context.Background(), provider, filepath.Join(dir, "keys.json"), 0)
+	assert.NoError(t, err)
+
+	f := NewWalFactory(&FactoryOptions{
+		BaseWalDir:  filepath.Join(dir, "wal"),
+		Retention:   1 * time.Hour,
+		SegmentSize: 128 * 1024,
+		Keyring:     keyring,
+	})
+	w, err := f.NewWal(common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Append(&proto.LogEntry{Term: 1, Offset: 0, Value: []byte("secret")}))
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	inspector, err := NewInspector(filepath.Join(dir, "wal"), common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+	err = inspector.ReadEntries(0, 10, func(EntryHeader, *proto.LogEntry) error {
+		return nil
+	})
+	assert.ErrorContains(t, err, "encrypted")
+
+	readOnlyKeyring, err := encryption.OpenFileKeyringReadOnly(context.Background(), provider, filepath.Join(dir, "keys.json"))
+	assert.NoError(t, err)
+	inspector, err = NewInspector(filepath.Join(dir, "wal"), common.DefaultNamespace, shard, readOnlyKeyring)
+	assert.NoError(t, err)
+	var values []string
+	assert.NoError(t, inspector.ReadEntries(0, 10, func(_ EntryHeader, entry *proto.LogEntry) error {
+		values = append(values, string(entry.Value))
+		return nil
+	}))
+	assert.Equal(t, []string{"secret"}, values)
+}
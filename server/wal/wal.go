@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
 )
 
 var (
@@ -39,6 +40,9 @@ type FactoryOptions struct {
 	Retention   time.Duration
 	SegmentSize int32
 	SyncData    bool
+
+	// Keyring encrypts the entries of the wal, when set
+	Keyring *encryption.Keyring
 }
 
 var DefaultFactoryOptions = &FactoryOptions{
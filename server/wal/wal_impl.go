@@ -31,6 +31,7 @@ import (
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/common/metrics"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
 )
 
 type walFactory struct {
@@ -60,6 +61,7 @@ type wal struct {
 	firstOffset atomic.Int64
 	segmentSize uint32
 	syncData    bool
+	keyring     *encryption.Keyring
 
 	currentSegment   ReadWriteSegment
 	readOnlySegments ReadOnlySegmentsGroup
@@ -106,6 +108,7 @@ func newWal(namespace string, shard int64, options *FactoryOptions, commitOffset
 		shard:       shard,
 		segmentSize: uint32(options.SegmentSize),
 		syncData:    options.SyncData,
+		keyring:     options.Keyring,
 
 		appendLatency: metrics.NewLatencyHistogram("oxia_server_wal_append_latency",
 			"The time it takes to append entries to the WAL", labels),
@@ -190,6 +193,11 @@ func (t *wal) readAtIndex(index int64) (*proto.LogEntry, error) {
 		return nil, err
 	}
 
+	if val, err = t.decrypt(val); err != nil {
+		t.readErrors.Inc()
+		return nil, err
+	}
+
 	entry := &proto.LogEntry{}
 	if err = entry.UnmarshalVT(val); err != nil {
 		t.readErrors.Inc()
@@ -199,6 +207,16 @@ func (t *wal) readAtIndex(index int64) (*proto.LogEntry, error) {
 	return entry, err
 }
 
+func (t *wal) decrypt(val []byte) ([]byte, error) {
+	if t.keyring != nil {
+		return t.keyring.Decrypt(val)
+	}
+	if encryption.IsEncrypted(val) {
+		return nil, errors.New("the wal entry is encrypted, but the encryption is not configured")
+	}
+	return val, nil
+}
+
 func (t *wal) LastOffset() int64 {
 	return t.lastSyncedOffset.Load()
 }
@@ -268,6 +286,12 @@ func (t *wal) AppendAsync(entry *proto.LogEntry) error {
 		t.writeErrors.Inc()
 		return err
 	}
+	if t.keyring != nil {
+		if val, err = t.keyring.Encrypt(val); err != nil {
+			t.writeErrors.Inc()
+			return errors.Wrap(err, "failed to encrypt the wal entry")
+		}
+	}
 
 	if t.lastAppendedOffset.Load() == InvalidOffset && entry.Offset != 0 && t.currentSegment.BaseOffset() == 0 {
 		// The wal was cleared and we're starting from a non-initial position
@@ -424,10 +448,14 @@ func (t *wal) Delete() error {
 	t.Lock()
 	defer t.Unlock()
 
-	return multierr.Combine(
+	err := multierr.Combine(
 		t.close(),
 		os.RemoveAll(t.walPath),
 	)
+
+	// Remove the namespace directory too, if this was its last shard
+	_ = os.Remove(filepath.Dir(t.walPath))
+	return err
 }
 
 func (t *wal) TruncateLog(lastSafeOffset int64) (int64, error) { //nolint:revive
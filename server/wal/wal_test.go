@@ -16,7 +16,10 @@ package wal
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -25,6 +28,7 @@ import (
 
 	"github.com/streamnative/oxia/common"
 	"github.com/streamnative/oxia/proto"
+	"github.com/streamnative/oxia/server/encryption"
 )
 
 const shard = int64(100)
@@ -513,3 +517,63 @@ func TestDelete(t *testing.T) {
 	assert.NoError(t, w.Close())
 	assert.NoError(t, f.Close())
 }
+
+func TestEncryption(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master-keys")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("k1:"+base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600))
+	provider, err := encryption.NewKeyFileProvider(fmt.Sprintf(`{"path":%q}`, keyFile))
+	assert.NoError(t, err)
+	keyring, err := encryption.OpenFileKeyring(context.Background(), provider, filepath.Join(dir, "keys.json"), 0)
+	assert.NoError(t, err)
+
+	options := &FactoryOptions{
+		BaseWalDir:  filepath.Join(dir, "wal"),
+		Retention:   1 * time.Hour,
+		SegmentSize: 128 * 1024,
+		Keyring:     keyring,
+	}
+	f := NewWalFactory(options)
+	w, err := f.NewWal(common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+
+	input := []string{"secret-A", "secret-B", "secret-C"}
+	for i, s := range input {
+		assert.NoError(t, w.Append(&proto.LogEntry{
+			Term:   1,
+			Offset: int64(i),
+			Value:  []byte(s),
+		}))
+	}
+	assert.NoError(t, w.Close())
+
+	// The entries are not stored in clear
+	segments, err := filepath.Glob(filepath.Join(options.BaseWalDir, "*", "*", "*"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, segments)
+	for _, segment := range segments {
+		content, err := os.ReadFile(segment)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "secret")
+	}
+
+	w, err = f.NewWal(common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+	r, err := w.NewReader(InvalidOffset)
+	assert.NoError(t, err)
+	assertReaderReads(t, r, input)
+	assert.NoError(t, r.Close())
+	assert.NoError(t, w.Close())
+
+	// Without the keyring, the encrypted entries are rejected
+	options.Keyring = nil
+	w, err = f.NewWal(common.DefaultNamespace, shard, nil)
+	assert.NoError(t, err)
+	r, err = w.NewReader(InvalidOffset)
+	assert.NoError(t, err)
+	_, err = r.ReadNext()
+	assert.ErrorContains(t, err, "encrypted")
+	assert.NoError(t, r.Close())
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+}
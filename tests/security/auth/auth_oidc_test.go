@@ -40,10 +40,15 @@ import (
 
 func newOxiaClusterWithAuth(t *testing.T, issueURL string, audiences string) (address string, closeFunc func()) {
 	t.Helper()
-	options := auth.OIDCOptions{
+	return newOxiaClusterWithOIDCOptions(t, auth.OIDCOptions{
 		AllowedIssueURLs: issueURL,
 		AllowedAudiences: audiences,
-	}
+	}, nil)
+}
+
+func newOxiaClusterWithOIDCOptions(t *testing.T, options auth.OIDCOptions,
+	authorization *model.AuthorizationConfig) (address string, closeFunc func()) {
+	t.Helper()
 	jsonParams, err := json.Marshal(options)
 	assert.NoError(t, err)
 	authParams := auth.Options{
@@ -100,7 +105,8 @@ func newOxiaClusterWithAuth(t *testing.T, issueURL string, audiences string) (ad
 			ReplicationFactor: 3,
 			InitialShardCount: 1,
 		}},
-		Servers: []model.ServerAddress{s1Addr, s2Addr, s3Addr},
+		Servers:       []model.ServerAddress{s1Addr, s2Addr, s3Addr},
+		Authorization: authorization,
 	}
 
 	clientPool := common.NewClientPool(nil, nil)
@@ -197,6 +203,123 @@ func TestOIDCWithStaticToken(t *testing.T) {
 	client.Close()
 }
 
+func TestOIDCWithJWKSURL(t *testing.T) {
+	mockOIDC, err := mockoidc.Run()
+	assert.NoError(t, err)
+	defer func(mockOIDC *mockoidc.MockOIDC) {
+		_ = mockOIDC.Shutdown()
+	}(mockOIDC)
+
+	// The issuer doesn't need to serve the OIDC discovery when the keys are
+	// fetched from the JWKS endpoint
+	issuer := "https://issuer.example.com"
+	audience := generateRandomStr(t)
+	subject := generateRandomStr(t)
+	newToken := func(issuer string) string {
+		token, err := mockOIDC.Keypair.SignJWT(&jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(1) * time.Hour)),
+			ID:        generateRandomStr(t),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    issuer,
+			NotBefore: jwt.NewNumericDate(time.Time{}),
+			Subject:   subject,
+		})
+		assert.NoError(t, err)
+		return token
+	}
+
+	addr, clusterCloseFunc := newOxiaClusterWithOIDCOptions(t, auth.OIDCOptions{
+		AllowedIssueURLs: issuer,
+		AllowedAudiences: audience,
+		JWKSURL:          mockOIDC.JWKSEndpoint(),
+	}, nil)
+	defer clusterCloseFunc()
+
+	// assert connection failed with a token from another issuer
+	_, err = oxia.NewSyncClient(addr,
+		oxia.WithAuthentication(clientauth.NewTokenAuthenticationWithToken(newToken(mockOIDC.Issuer()), false)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(errors.Unwrap(err)))
+
+	// assert connection success with a token signed by a key of the JWKS endpoint
+	client, err := oxia.NewSyncClient(addr,
+		oxia.WithAuthentication(clientauth.NewTokenAuthenticationWithToken(newToken(issuer), false)))
+	assert.NoError(t, err)
+	_, _, err = client.Put(context.Background(), "hi", []byte("matt"))
+	assert.NoError(t, err)
+	assert.NoError(t, client.Close())
+}
+
+func TestOIDCWithAuthorization(t *testing.T) {
+	mockOIDC, err := mockoidc.Run()
+	assert.NoError(t, err)
+	defer func(mockOIDC *mockoidc.MockOIDC) {
+		_ = mockOIDC.Shutdown()
+	}(mockOIDC)
+
+	audience := generateRandomStr(t)
+	addr, clusterCloseFunc := newOxiaClusterWithOIDCOptions(t, auth.OIDCOptions{
+		AllowedIssueURLs: mockOIDC.Issuer(),
+		AllowedAudiences: audience,
+	}, &model.AuthorizationConfig{
+		Roles: []model.Role{{
+			Name:       "writers",
+			Principals: []string{"writer"},
+			Permissions: map[string][]model.Permission{
+				common.DefaultNamespace: {model.PermissionRead, model.PermissionWrite},
+			},
+		}, {
+			Name:       "readers",
+			Principals: []string{"reader"},
+			Permissions: map[string][]model.Permission{
+				common.DefaultNamespace: {model.PermissionRead},
+			},
+		}},
+	})
+	defer clusterCloseFunc()
+
+	newClient := func(subject string) oxia.SyncClient {
+		token, err := mockOIDC.Keypair.SignJWT(&jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(1) * time.Hour)),
+			ID:        generateRandomStr(t),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    mockOIDC.Issuer(),
+			NotBefore: jwt.NewNumericDate(time.Time{}),
+			Subject:   subject,
+		})
+		assert.NoError(t, err)
+		client, err := oxia.NewSyncClient(addr,
+			oxia.WithAuthentication(clientauth.NewTokenAuthenticationWithToken(token, false)))
+		assert.NoError(t, err)
+		return client
+	}
+
+	ctx := context.Background()
+	writer := newClient("writer")
+	defer writer.Close()
+	reader := newClient("reader")
+	defer reader.Close()
+	other := newClient("other")
+	defer other.Close()
+
+	// The permissions are distributed to the leaders with the shard assignments
+	assert.Eventually(t, func() bool {
+		_, _, err := reader.Put(ctx, "key", []byte("value"))
+		return errors.Is(err, oxia.ErrPermissionDenied)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	_, _, err = writer.Put(ctx, "key", []byte("value"))
+	assert.NoError(t, err)
+
+	_, value, _, err := reader.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	_, _, _, err = other.Get(ctx, "key")
+	assert.ErrorIs(t, err, oxia.ErrPermissionDenied)
+}
+
 func generateRandomStr(t *testing.T) string {
 	t.Helper()
 	random, err := uuid.NewRandom()
@@ -15,6 +15,7 @@
 package tls
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -336,3 +337,81 @@ func TestOnlyEnablePublicTls(t *testing.T) {
 	assert.NoError(t, err)
 	client.Close()
 }
+
+func TestOnlyEnableInternalTls(t *testing.T) {
+	option, err := getPeerTLSOption()
+	assert.NoError(t, err)
+	// Servers require mutual authentication on the internal port
+	option.ClientAuth = true
+	internalTLSConf, err := option.MakeServerTLSConf()
+	assert.NoError(t, err)
+
+	disablePublicTLS := func(config *server.Config) {
+		config.ServerTLS = nil
+		config.InternalServerTLS = internalTLSConf
+	}
+	s1, sa1 := newTLSServerWithInterceptor(t, disablePublicTLS)
+	defer s1.Close()
+	s2, sa2 := newTLSServerWithInterceptor(t, disablePublicTLS)
+	defer s2.Close()
+	s3, sa3 := newTLSServerWithInterceptor(t, disablePublicTLS)
+	defer s3.Close()
+
+	metadataProvider := impl.NewMetadataProviderMemory()
+	clusterConfig := model.ClusterConfig{
+		Namespaces: []model.NamespaceConfig{{
+			Name:              common.DefaultNamespace,
+			ReplicationFactor: 3,
+			InitialShardCount: 1,
+		}},
+		Servers: []model.ServerAddress{sa1, sa2, sa3},
+	}
+	tlsConf, err := option.MakeClientTLSConf()
+	assert.NoError(t, err)
+
+	clientPool := common.NewClientPool(tlsConf, nil)
+	defer clientPool.Close()
+
+	coordinator, err := impl.NewCoordinator(metadataProvider, func() (model.ClusterConfig, error) { return clusterConfig, nil }, nil, impl.NewRpcProvider(clientPool))
+	assert.NoError(t, err)
+	defer coordinator.Close()
+
+	// The public port doesn't require any cert, while the writes are
+	// still replicated over the mutually authenticated internal port
+	client, err := oxia.NewSyncClient(sa1.Public)
+	assert.NoError(t, err)
+
+	_, _, err = client.Put(context.Background(), "/a", []byte("0"))
+	assert.NoError(t, err)
+	assert.NoError(t, client.Close())
+}
+
+func TestMetadataProviderOxiaTLS(t *testing.T) {
+	option, err := getPeerTLSOption()
+	assert.NoError(t, err)
+	serverTLSConf, err := option.MakeServerTLSConf()
+	assert.NoError(t, err)
+
+	config := server.NewTestConfig(t.TempDir())
+	config.ServerTLS = serverTLSConf
+	standalone, err := server.NewStandalone(config)
+	assert.NoError(t, err)
+	defer standalone.Close()
+
+	tlsOption, err := getClientTLSOption()
+	assert.NoError(t, err)
+	tlsConf, err := tlsOption.MakeClientTLSConf()
+	assert.NoError(t, err)
+
+	m, err := impl.NewMetadataProviderOxia(fmt.Sprintf("localhost:%d", standalone.RpcPort()),
+		common.DefaultNamespace, "/oxia/cluster-status", tlsConf, "")
+	assert.NoError(t, err)
+
+	version, err := m.Store(&model.ClusterStatus{}, impl.MetadataNotExists)
+	assert.NoError(t, err)
+
+	_, v, err := m.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, version, v)
+	assert.NoError(t, m.Close())
+}